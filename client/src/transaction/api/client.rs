@@ -115,6 +115,12 @@ pub struct WaitBlockIndexedRequest {
     pub round: u64,
 }
 
+#[derive(Clone, Debug, Serialize, Deserialize)]
+pub struct WaitRoundRequest {
+    pub runtime_id: RuntimeId,
+    pub round: u64,
+}
+
 grpc_method!(
     METHOD_SUBMIT_TX,
     "/oasis-core.RuntimeClient/SubmitTx",
@@ -169,6 +175,12 @@ grpc_method!(
     WaitBlockIndexedRequest,
     ()
 );
+grpc_method!(
+    METHOD_WAIT_ROUND,
+    "/oasis-core.RuntimeClient/WaitRound",
+    WaitRoundRequest,
+    Block
+);
 
 grpc_stream!(
     METHOD_WATCH_BLOCKS,
@@ -269,6 +281,15 @@ impl RuntimeClient {
             .unary_call_async(&METHOD_WAIT_BLOCK_INDEXED, &request, opt)
     }
 
+    pub fn wait_round(
+        &self,
+        request: &WaitRoundRequest,
+        opt: CallOption,
+    ) -> Result<ClientUnaryReceiver<Block>> {
+        self.client
+            .unary_call_async(&METHOD_WAIT_ROUND, &request, opt)
+    }
+
     pub fn watch_blocks(
         &self,
         runtime_id: RuntimeId,
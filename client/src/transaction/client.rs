@@ -424,6 +424,30 @@ impl TxnClient {
         result
     }
 
+    /// Wait for a block round to be finalized.
+    pub fn wait_round(&self, round: u64) -> BoxFuture<BlockSnapshot> {
+        let (span, options) = self.prepare_options("TxnClient::wait_round");
+        let request = api::client::WaitRoundRequest {
+            runtime_id: self.runtime_id,
+            round: round,
+        };
+
+        let result: BoxFuture<BlockSnapshot> = match self.client.wait_round(&request, options) {
+            Ok(resp) => {
+                let storage_client = self.storage_client.clone();
+                Box::new(
+                    resp.map(move |rsp| BlockSnapshot::new(storage_client, rsp))
+                        .map_err(|error| TxnClientError::CallFailed(format!("{}", error)).into()),
+                )
+            }
+            Err(error) => Box::new(future::err(
+                TxnClientError::CallFailed(format!("{}", error)).into(),
+            )),
+        };
+        drop(span);
+        result
+    }
+
     fn prepare_options(&self, span_name: &'static str) -> (Span, grpcio::CallOption) {
         // TODO: Use oasis_core_tracing to get the tracer.
         let (tracer, _) = Tracer::new(AllSampler);
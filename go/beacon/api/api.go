@@ -3,9 +3,13 @@ package api
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 )
 
@@ -15,11 +19,52 @@ const (
 
 	// BeaconSize is the size of the beacon in bytes.
 	BeaconSize = 32
+
+	// BackendInsecure is the name of the insecure beacon backend which derives
+	// entropy from block commit hashes.
+	BackendInsecure = "insecure"
+
+	// BackendVRF is the name of the beacon backend which derives entropy from
+	// VRF proofs submitted by validator nodes.
+	BackendVRF = "vrf"
+
+	// LogEventVRFProofFailed is a log event value that signals the local node
+	// failed to generate or submit a VRF proof.
+	LogEventVRFProofFailed = "beacon/vrf_proof_failed"
+	// LogEventInsufficientVRFProofs is a log event value that signals that
+	// not enough VRF proofs were submitted and insecure entropy was used.
+	LogEventInsufficientVRFProofs = "beacon/insufficient_vrf_proofs"
 )
 
-// ErrBeaconNotAvailable is the error returned when a beacon is not
-// available for the requested height for any reason.
-var ErrBeaconNotAvailable = errors.New(ModuleName, 1, "beacon: random beacon not available")
+var (
+	// ErrBeaconNotAvailable is the error returned when a beacon is not
+	// available for the requested height for any reason.
+	ErrBeaconNotAvailable = errors.New(ModuleName, 1, "beacon: random beacon not available")
+
+	// ErrInvalidArgument is the error returned on malformed argument(s).
+	ErrInvalidArgument = errors.New(ModuleName, 2, "beacon: invalid argument")
+
+	// ErrMethodNotSupported is the error returned when a beacon transaction
+	// is not supported by the configured beacon backend.
+	ErrMethodNotSupported = errors.New(ModuleName, 3, "beacon: method not supported by backend")
+
+	// ErrInvalidProof is the error returned when a VRF proof is invalid.
+	ErrInvalidProof = errors.New(ModuleName, 4, "beacon: invalid VRF proof")
+
+	// ErrDuplicateProof is the error returned when a VRF proof for the same
+	// key has already been submitted for the given epoch.
+	ErrDuplicateProof = errors.New(ModuleName, 5, "beacon: duplicate VRF proof")
+
+	// MethodVRFProve is the method name for submitting a VRF proof.
+	MethodVRFProve = transaction.NewMethodName(ModuleName, "VRFProve", VRFProve{})
+
+	// Methods is a list of all methods supported by the beacon backend.
+	Methods = []transaction.MethodName{
+		MethodVRFProve,
+	}
+
+	vrfAlphaCtx = []byte("oasis-core/beacon: vrf alpha")
+)
 
 // Backend is a random beacon implementation.
 type Backend interface {
@@ -30,6 +75,21 @@ type Backend interface {
 
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(context.Context, int64) (*Genesis, error)
+
+	// WatchPendingEpochs returns a channel that produces a stream of
+	// pending epoch transition events, emitted the configured number of
+	// blocks before each epoch transition.
+	WatchPendingEpochs(context.Context) (<-chan *PendingEpochEvent, pubsub.ClosableSubscription, error)
+}
+
+// PendingEpochEvent is the event emitted a configured number of blocks
+// before an epoch transition.
+type PendingEpochEvent struct {
+	// Epoch is the epoch that is about to start.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// Height is the height of the first block of the epoch.
+	Height int64 `json:"height"`
 }
 
 // Genesis is the beacon genesis state.
@@ -40,16 +100,107 @@ type Genesis struct {
 
 // ConsensusParameters are the beacon consensus parameters.
 type ConsensusParameters struct {
+	// Backend is the beacon backend used to generate entropy. If empty,
+	// the insecure backend is used.
+	Backend string `json:"backend,omitempty"`
+
 	// DebugDeterministic is true iff the output should be deterministic.
 	DebugDeterministic bool `json:"debug_deterministic,omitempty"`
+
+	// VRFParameters are the VRF beacon backend parameters.
+	VRFParameters *VRFParameters `json:"vrf_parameters,omitempty"`
+
+	// PendingEpochNotice is the number of blocks before an epoch
+	// transition at which a pending epoch transition event is emitted.
+	// If zero, no such events are emitted.
+	PendingEpochNotice uint64 `json:"pending_epoch_notice,omitempty"`
+}
+
+// BackendName returns the name of the configured beacon backend.
+func (p *ConsensusParameters) BackendName() string {
+	if p.Backend == "" {
+		return BackendInsecure
+	}
+	return p.Backend
+}
+
+// VRFParameters are the VRF beacon backend parameters.
+type VRFParameters struct {
+	// MinProofs is the minimum number of VRF proofs that need to be
+	// submitted during an epoch for the next beacon to be derived solely
+	// from VRF outputs. In case fewer proofs are available, the insecure
+	// entropy source is mixed in.
+	MinProofs uint64 `json:"min_proofs"`
+
+	// GasCosts are the VRF beacon transaction gas costs.
+	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
+}
+
+const (
+	// GasOpVRFProve is the gas operation identifier for VRF proof submission.
+	GasOpVRFProve transaction.Op = "vrf_prove"
+)
+
+// DefaultVRFGasCosts are the "default" gas costs for VRF beacon operations.
+var DefaultVRFGasCosts = transaction.Costs{
+	GasOpVRFProve: 1000,
+}
+
+// VRFProve is a VRF proof submission.
+type VRFProve struct {
+	// Epoch is the epoch for which the beacon is being generated.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// Pi is the VRF proof over the input returned by VRFAlpha.
+	Pi []byte `json:"pi"`
+}
+
+// VRFAlpha returns the VRF input used to generate the beacon for the given
+// epoch, chained to the beacon of the previous epoch.
+func VRFAlpha(epoch epochtime.EpochTime, prevBeacon []byte) []byte {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], uint64(epoch))
+
+	alpha := append([]byte{}, vrfAlphaCtx...)
+	alpha = append(alpha, tmp[:]...)
+	return append(alpha, prevBeacon...)
+}
+
+// NewVRFProveTx creates a new VRF proof submission transaction.
+func NewVRFProveTx(nonce uint64, fee *transaction.Fee, prove *VRFProve) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodVRFProve, prove)
 }
 
 // SanityCheck does basic sanity checking on the genesis state.
-func (g *Genesis) SanityCheck() error {
+func (g *Genesis) SanityCheck(epochParams *epochtime.ConsensusParameters) error {
 	unsafeFlags := g.Parameters.DebugDeterministic
 	if unsafeFlags && !flags.DebugDontBlameOasis() {
 		return fmt.Errorf("beacon: sanity check failed: one or more unsafe debug flags set")
 	}
 
+	switch g.Parameters.BackendName() {
+	case BackendInsecure:
+	case BackendVRF:
+		if g.Parameters.DebugDeterministic {
+			return fmt.Errorf("beacon: sanity check failed: deterministic beacon not supported by the VRF backend")
+		}
+		if g.Parameters.VRFParameters == nil {
+			return fmt.Errorf("beacon: sanity check failed: VRF backend parameters missing")
+		}
+	default:
+		return fmt.Errorf("beacon: sanity check failed: unknown backend: '%s'", g.Parameters.Backend)
+	}
+
+	if notice := g.Parameters.PendingEpochNotice; notice > 0 {
+		// Epoch transitions of the mock backend are triggered externally
+		// and cannot be known in advance.
+		if epochParams.DebugMockBackend {
+			return fmt.Errorf("beacon: sanity check failed: pending epoch notice not supported by the mock epochtime backend")
+		}
+		if notice >= uint64(epochParams.Interval) {
+			return fmt.Errorf("beacon: sanity check failed: pending epoch notice must be less than the epoch interval")
+		}
+	}
+
 	return nil
 }
@@ -12,6 +12,9 @@ import (
 // Subject is an access control subject.
 type Subject string
 
+// AnySubject is a special access control subject that matches all subjects.
+const AnySubject Subject = "*"
+
 // SubjectFromX509Certificate returns a Subject from the given X.509
 // certificate.
 func SubjectFromX509Certificate(cert *x509.Certificate) Subject {
@@ -72,7 +75,13 @@ func (p Policy) IsAllowed(sub Subject, act Action) bool {
 	if p[act] == nil {
 		return false
 	}
-	return p[act][sub]
+	return p[act][sub] || p[act][AnySubject]
+}
+
+// IsAllowedForAll returns a boolean indicating whether all subjects are
+// allowed to perform the given Action under the current Policy.
+func (p Policy) IsAllowedForAll(act Action) bool {
+	return p.IsAllowed(AnySubject, act)
 }
 
 // String returns the string representation of the policy.
@@ -40,6 +40,14 @@ func TestPolicy(t *testing.T) {
 
 	// Remove nonexisting rule from a non-empty policy.
 	policy.Deny("anne", "write")
+
+	// Rules for all subjects.
+	policy.Allow(AnySubject, "read")
+	require.True(policy.IsAllowed("anne", "read"), "Anne should have read access")
+	require.True(policy.IsAllowed("bob", "read"), "Bob should have read access")
+	require.True(policy.IsAllowedForAll("read"), "everyone should have read access")
+	require.False(policy.IsAllowed("anne", "write"), "Anne should not have write access")
+	require.False(policy.IsAllowedForAll("write"), "not everyone should have write access")
 }
 
 func TestSubjectFromCertificate(t *testing.T) {
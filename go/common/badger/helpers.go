@@ -2,6 +2,8 @@
 package badger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -13,10 +15,44 @@ import (
 )
 
 const (
-	gcInterval     = 5 * time.Minute
-	gcDiscardRatio = 0.5
+	// DefaultGCInterval is the default interval between periodic value log GC runs.
+	DefaultGCInterval = 5 * time.Minute
+	// DefaultGCDiscardRatio is the default value log GC discard ratio.
+	DefaultGCDiscardRatio = 0.5
 )
 
+// ErrGCWorkerClosed is the error returned when triggering GC on a closed GC worker.
+var ErrGCWorkerClosed = errors.New("badger: GC worker closed")
+
+// GCConfig is the BadgerDB value log GC worker configuration.
+type GCConfig struct {
+	// Interval is the interval between periodic GC runs. Zero selects the default interval while
+	// a negative interval disables periodic GC (GC can still be triggered manually).
+	Interval time.Duration
+
+	// DiscardRatio is the fraction of a value log file that must be discardable in order for the
+	// file to be rewritten. Values outside of (0, 1) select the default discard ratio.
+	DiscardRatio float64
+
+	// MaxRewrites is the maximum number of value log files rewritten in a single GC run, bounding
+	// the amount of IO a run can perform. Zero means that the number of rewrites is not limited.
+	MaxRewrites uint64
+}
+
+func (cfg *GCConfig) interval() time.Duration {
+	if cfg.Interval == 0 {
+		return DefaultGCInterval
+	}
+	return cfg.Interval
+}
+
+func (cfg *GCConfig) discardRatio() float64 {
+	if cfg.DiscardRatio <= 0 || cfg.DiscardRatio >= 1 {
+		return DefaultGCDiscardRatio
+	}
+	return cfg.DiscardRatio
+}
+
 // NewLogAdapter returns a badger.Logger backed by an oasis-node logger.
 func NewLogAdapter(logger *logging.Logger) badger.Logger {
 	return &badgerLogger{
@@ -48,13 +84,21 @@ func (l *badgerLogger) Debugf(format string, a ...interface{}) {
 type GCWorker struct {
 	logger *logging.Logger
 
-	db *badger.DB
+	db  *badger.DB
+	cfg GCConfig
+
+	triggerCh chan chan *gcResult
 
 	closeOnce sync.Once
 	closeCh   chan struct{}
 	closedCh  chan struct{}
 }
 
+type gcResult struct {
+	rewrites uint64
+	err      error
+}
+
 // Close halts the GC worker.
 func (gc *GCWorker) Close() {
 	gc.closeOnce.Do(func() {
@@ -63,35 +107,74 @@ func (gc *GCWorker) Close() {
 	})
 }
 
-func (gc *GCWorker) worker() {
-	defer close(gc.closedCh)
+// Trigger runs the value log GC immediately, waits for it to complete and returns the number of
+// value log files that have been rewritten.
+func (gc *GCWorker) Trigger(ctx context.Context) (uint64, error) {
+	ch := make(chan *gcResult, 1)
+	select {
+	case gc.triggerCh <- ch:
+	case <-gc.closeCh:
+		return 0, ErrGCWorkerClosed
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
 
-	ticker := time.NewTicker(gcInterval)
-	defer ticker.Stop()
+	select {
+	case res := <-ch:
+		return res.rewrites, res.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
 
-	doGC := func() error {
-		for {
-			if err := gc.db.RunValueLogGC(gcDiscardRatio); err != nil {
-				return err
-			}
+func (gc *GCWorker) doGC() (uint64, error) {
+	var rewrites uint64
+	for gc.cfg.MaxRewrites == 0 || rewrites < gc.cfg.MaxRewrites {
+		switch err := gc.db.RunValueLogGC(gc.cfg.discardRatio()); err {
+		case nil:
+			rewrites++
+		case badger.ErrNoRewrite:
+			return rewrites, nil
+		default:
+			return rewrites, err
 		}
 	}
+	return rewrites, nil
+}
+
+func (gc *GCWorker) worker() {
+	defer close(gc.closedCh)
+
+	var tickCh <-chan time.Time
+	if interval := gc.cfg.interval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
 
 	for {
+		var resultCh chan *gcResult
 		select {
 		case <-gc.closeCh:
 			return
-		case <-ticker.C:
+		case <-tickCh:
+		case resultCh = <-gc.triggerCh:
 		}
 
 		// Run the value log GC.
-		err := doGC()
-		switch err {
-		case nil, badger.ErrNoRewrite:
-		default:
+		rewrites, err := gc.doGC()
+		if err != nil {
 			gc.logger.Error("failed to GC value log",
 				"err", err,
 			)
+		} else if rewrites > 0 {
+			gc.logger.Debug("value log GC completed",
+				"rewrites", rewrites,
+			)
+		}
+
+		if resultCh != nil {
+			resultCh <- &gcResult{rewrites: rewrites, err: err}
 		}
 	}
 }
@@ -99,11 +182,19 @@ func (gc *GCWorker) worker() {
 // NewGCWorker creates a new BadgerDB value log GC worker for the provided
 // db, logging to the specified logger.
 func NewGCWorker(logger *logging.Logger, db *badger.DB) *GCWorker {
+	return NewGCWorkerWithConfig(logger, db, GCConfig{})
+}
+
+// NewGCWorkerWithConfig creates a new BadgerDB value log GC worker for the
+// provided db with the given configuration, logging to the specified logger.
+func NewGCWorkerWithConfig(logger *logging.Logger, db *badger.DB, cfg GCConfig) *GCWorker {
 	gc := &GCWorker{
-		logger:   logger,
-		db:       db,
-		closeCh:  make(chan struct{}),
-		closedCh: make(chan struct{}),
+		logger:    logger,
+		db:        db,
+		cfg:       cfg,
+		triggerCh: make(chan chan *gcResult),
+		closeCh:   make(chan struct{}),
+		closedCh:  make(chan struct{}),
 	}
 
 	go gc.worker()
@@ -0,0 +1,206 @@
+package ledger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+const (
+	claOasis = 0x05
+
+	insGetVersion     = 0x00
+	insGetAddrEd25519 = 0x01
+	insSignEd25519    = 0x02
+
+	payloadChunkInit = 0x00
+	payloadChunkAdd  = 0x01
+	payloadChunkLast = 0x02
+
+	addrNoConfirm      = 0x00
+	addrRequireConfirm = 0x01
+
+	// userMessageChunkSize is the maximum size of a single signing payload chunk.
+	userMessageChunkSize = 250
+
+	swSuccess = 0x9000
+
+	// maxContextSize is the maximum size of a signature context supported by the app.
+	maxContextSize = 255
+
+	// Coin type registered for Oasis in SLIP-0044.
+	oasisCoinType = 474
+
+	hardenedOffset = 0x80000000
+)
+
+var (
+	// ErrRejected is the error returned when the user rejects an operation on the device.
+	ErrRejected = errors.New("signature/signer/ledger: rejected by user")
+	// ErrAppNotOpen is the error returned when the Oasis app is not open on the device.
+	ErrAppNotOpen = errors.New("signature/signer/ledger: Oasis app is not open on the device")
+
+	statusErrors = map[uint16]error{
+		0x6986: ErrRejected,
+		0x6e00: ErrAppNotOpen,
+		0x6e01: ErrAppNotOpen,
+	}
+)
+
+// Device is a transport capable of exchanging APDUs with a Ledger device.
+type Device interface {
+	// Exchange sends a command APDU to the device and returns the response APDU,
+	// including the trailing status word.
+	Exchange(command []byte) ([]byte, error)
+
+	// Close closes the device.
+	Close() error
+}
+
+// DeviceOpener is a function that opens a Ledger device.
+type DeviceOpener func() (Device, error)
+
+// Version is the version of the Oasis app running on the device.
+type Version struct {
+	TestMode bool
+	Major    uint8
+	Minor    uint8
+	Patch    uint8
+}
+
+// String returns a string representation of the app version.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.TestMode {
+		s += " (test mode)"
+	}
+	return s
+}
+
+// app is a client for the Oasis app running on a Ledger device.
+type app struct {
+	device Device
+}
+
+// derivationPath returns the serialized BIP-44 derivation path for the given account index.
+func derivationPath(index uint32) []byte {
+	path := []uint32{44, oasisCoinType, 0, 0, index}
+
+	raw := make([]byte, 4*len(path))
+	for i, v := range path {
+		binary.LittleEndian.PutUint32(raw[i*4:], hardenedOffset|v)
+	}
+	return raw
+}
+
+func (a *app) exchange(ins, p1 byte, data []byte) ([]byte, error) {
+	if len(data) > 255 {
+		return nil, fmt.Errorf("signature/signer/ledger: APDU payload too large: %d", len(data))
+	}
+
+	command := append([]byte{claOasis, ins, p1, 0x00, byte(len(data))}, data...)
+	rsp, err := a.device.Exchange(command)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/ledger: failed to exchange APDU: %w", err)
+	}
+	if len(rsp) < 2 {
+		return nil, fmt.Errorf("signature/signer/ledger: malformed response")
+	}
+
+	sw := binary.BigEndian.Uint16(rsp[len(rsp)-2:])
+	if sw != swSuccess {
+		if swErr, ok := statusErrors[sw]; ok {
+			return nil, swErr
+		}
+		return nil, fmt.Errorf("signature/signer/ledger: device returned error: 0x%04x", sw)
+	}
+	return rsp[:len(rsp)-2], nil
+}
+
+// getVersion returns the version of the Oasis app.
+func (a *app) getVersion() (*Version, error) {
+	rsp, err := a.exchange(insGetVersion, 0x00, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp) < 4 {
+		return nil, fmt.Errorf("signature/signer/ledger: malformed version response")
+	}
+
+	return &Version{
+		TestMode: rsp[0] != 0,
+		Major:    rsp[1],
+		Minor:    rsp[2],
+		Patch:    rsp[3],
+	}, nil
+}
+
+// getAddress returns the public key and the address for the given account index.
+//
+// If confirm is true, the address is displayed on the device and the user must
+// confirm it before the method returns.
+func (a *app) getAddress(index uint32, confirm bool) (signature.PublicKey, string, error) {
+	p1 := byte(addrNoConfirm)
+	if confirm {
+		p1 = addrRequireConfirm
+	}
+
+	var pk signature.PublicKey
+	rsp, err := a.exchange(insGetAddrEd25519, p1, derivationPath(index))
+	if err != nil {
+		return pk, "", err
+	}
+	if len(rsp) < signature.PublicKeySize {
+		return pk, "", fmt.Errorf("signature/signer/ledger: malformed address response")
+	}
+	if err = pk.UnmarshalBinary(rsp[:signature.PublicKeySize]); err != nil {
+		return pk, "", fmt.Errorf("signature/signer/ledger: malformed public key: %w", err)
+	}
+
+	return pk, string(rsp[signature.PublicKeySize:]), nil
+}
+
+// sign signs the given message under the given (already prepared) raw context.
+//
+// The device displays the context and the message for the user to confirm.
+func (a *app) sign(index uint32, rawContext, message []byte) ([]byte, error) {
+	if len(rawContext) > maxContextSize {
+		return nil, fmt.Errorf("signature/signer/ledger: context too long")
+	}
+
+	payload := make([]byte, 0, 1+len(rawContext)+len(message))
+	payload = append(payload, byte(len(rawContext)))
+	payload = append(payload, rawContext...)
+	payload = append(payload, message...)
+
+	if _, err := a.exchange(insSignEd25519, payloadChunkInit, derivationPath(index)); err != nil {
+		return nil, err
+	}
+
+	var rsp []byte
+	for len(payload) > 0 {
+		n := len(payload)
+		if n > userMessageChunkSize {
+			n = userMessageChunkSize
+		}
+		chunk := payload[:n]
+		payload = payload[n:]
+
+		p1 := byte(payloadChunkAdd)
+		if len(payload) == 0 {
+			p1 = payloadChunkLast
+		}
+
+		var err error
+		if rsp, err = a.exchange(insSignEd25519, p1, chunk); err != nil {
+			return nil, err
+		}
+	}
+	if len(rsp) != signature.SignatureSize {
+		return nil, fmt.Errorf("signature/signer/ledger: malformed signature response")
+	}
+
+	return rsp, nil
+}
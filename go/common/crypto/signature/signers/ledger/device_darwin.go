@@ -0,0 +1,10 @@
+package ledger
+
+import "fmt"
+
+// OpenDevice opens the first Ledger device found.
+//
+// Note: Accessing Ledger devices directly is currently only supported on Linux.
+func OpenDevice() (Device, error) {
+	return nil, fmt.Errorf("signature/signer/ledger: direct device access not supported on this platform")
+}
@@ -0,0 +1,47 @@
+package ledger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const sysClassHidraw = "/sys/class/hidraw"
+
+// OpenDevice opens the first Ledger device found via the Linux hidraw interface.
+func OpenDevice() (Device, error) {
+	entries, err := ioutil.ReadDir(sysClassHidraw)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/ledger: failed to enumerate HID devices: %w", err)
+	}
+
+	hidID := fmt.Sprintf("HID_ID=0003:%08X:", ledgerVendorID)
+	for _, entry := range entries {
+		devDir := filepath.Join(sysClassHidraw, entry.Name(), "device")
+
+		uevent, err := ioutil.ReadFile(filepath.Join(devDir, "uevent"))
+		if err != nil || !strings.Contains(string(uevent), hidID) {
+			continue
+		}
+
+		// Ledger devices expose multiple interfaces, APDUs are exchanged via the first one.
+		resolved, err := filepath.EvalSymlinks(devDir)
+		if err != nil || !strings.HasSuffix(filepath.Base(filepath.Dir(resolved)), ":1.0") {
+			continue
+		}
+
+		f, err := os.OpenFile(filepath.Join("/dev", entry.Name()), os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("signature/signer/ledger: failed to open device: %w", err)
+		}
+		return &hidDevice{
+			rw: f,
+			// Writes to hidraw must be prefixed by the report ID.
+			reportPrefix: []byte{0x00},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("signature/signer/ledger: no Ledger device found")
+}
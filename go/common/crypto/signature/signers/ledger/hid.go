@@ -0,0 +1,109 @@
+package ledger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// ledgerVendorID is the USB vendor ID used by Ledger devices.
+	ledgerVendorID = 0x2c97
+
+	hidChannel    = 0x0101
+	hidTagAPDU    = 0x05
+	hidPacketSize = 64
+)
+
+// hidDevice is a Ledger device accessed via a raw HID report stream.
+type hidDevice struct {
+	rw io.ReadWriteCloser
+
+	// reportPrefix is prepended to every written report (e.g., a report ID).
+	reportPrefix []byte
+}
+
+func (d *hidDevice) Exchange(command []byte) ([]byte, error) {
+	for _, packet := range wrapCommandAPDU(hidChannel, command) {
+		if _, err := d.rw.Write(append(append([]byte{}, d.reportPrefix...), packet...)); err != nil {
+			return nil, fmt.Errorf("failed to write to device: %w", err)
+		}
+	}
+
+	return unwrapResponseAPDU(hidChannel, func() ([]byte, error) {
+		buf := make([]byte, hidPacketSize)
+		n, err := d.rw.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from device: %w", err)
+		}
+		return buf[:n], nil
+	})
+}
+
+func (d *hidDevice) Close() error {
+	return d.rw.Close()
+}
+
+// wrapCommandAPDU splits a command APDU into HID packets.
+func wrapCommandAPDU(channel uint16, command []byte) [][]byte {
+	// Prefix the command with its length.
+	data := make([]byte, 2+len(command))
+	binary.BigEndian.PutUint16(data, uint16(len(command)))
+	copy(data[2:], command)
+
+	var packets [][]byte
+	for seq := uint16(0); len(data) > 0; seq++ {
+		packet := make([]byte, hidPacketSize)
+		binary.BigEndian.PutUint16(packet[0:], channel)
+		packet[2] = hidTagAPDU
+		binary.BigEndian.PutUint16(packet[3:], seq)
+		n := copy(packet[5:], data)
+		data = data[n:]
+
+		packets = append(packets, packet)
+	}
+	return packets
+}
+
+// unwrapResponseAPDU reassembles a response APDU from HID packets returned by readPacket.
+func unwrapResponseAPDU(channel uint16, readPacket func() ([]byte, error)) ([]byte, error) {
+	var (
+		rsp      []byte
+		totalLen int
+	)
+	for seq := uint16(0); ; seq++ {
+		packet, err := readPacket()
+		if err != nil {
+			return nil, err
+		}
+		if len(packet) < 5 {
+			return nil, fmt.Errorf("malformed packet: too short")
+		}
+		if binary.BigEndian.Uint16(packet[0:]) != channel {
+			return nil, fmt.Errorf("malformed packet: invalid channel")
+		}
+		if packet[2] != hidTagAPDU {
+			return nil, fmt.Errorf("malformed packet: invalid tag")
+		}
+		if binary.BigEndian.Uint16(packet[3:]) != seq {
+			return nil, fmt.Errorf("malformed packet: invalid sequence number")
+		}
+
+		data := packet[5:]
+		if seq == 0 {
+			if len(data) < 2 {
+				return nil, fmt.Errorf("malformed packet: missing length")
+			}
+			totalLen = int(binary.BigEndian.Uint16(data))
+			data = data[2:]
+		}
+
+		if remaining := totalLen - len(rsp); len(data) > remaining {
+			data = data[:remaining]
+		}
+		rsp = append(rsp, data...)
+		if len(rsp) == totalLen {
+			return rsp, nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package ledger provides a Ledger hardware wallet backed Signer.
+package ledger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// SignerName is the name used to identify the Ledger backed signer.
+const SignerName = "ledger"
+
+var (
+	_ signature.SignerFactory = (*Factory)(nil)
+	_ signature.Signer        = (*Signer)(nil)
+)
+
+// FactoryConfig is the Ledger factory configuration.
+type FactoryConfig struct {
+	// Index is the account index of the key to use.
+	Index uint32
+
+	// ConfirmAddress specifies whether the address should be displayed on the device and
+	// confirmed by the user when the key is first loaded.
+	ConfirmAddress bool
+
+	// Device is the function used to open the device. If nil, OpenDevice is used.
+	Device DeviceOpener
+}
+
+// Factory is a Ledger backed SignerFactory.
+type Factory struct {
+	sync.Mutex
+
+	cfg    FactoryConfig
+	signer *Signer
+}
+
+// NewFactory creates a new factory backed by a Ledger device.
+//
+// Keys held by the Ledger device can only be used with explicit user confirmation
+// so only the entity role is supported.
+func NewFactory(config interface{}, roles ...signature.SignerRole) (signature.SignerFactory, error) {
+	cfg, ok := config.(*FactoryConfig)
+	if !ok {
+		return nil, fmt.Errorf("signature/signer/ledger: invalid Ledger signer configuration provided")
+	}
+	for _, role := range roles {
+		if role != signature.SignerEntity {
+			return nil, fmt.Errorf("signature/signer/ledger: unsupported role: %v", role)
+		}
+	}
+
+	fac := &Factory{
+		cfg: *cfg,
+	}
+	if fac.cfg.Device == nil {
+		fac.cfg.Device = OpenDevice
+	}
+	return fac, nil
+}
+
+// EnsureRole ensures that the SignerFactory is configured for the given role.
+func (fac *Factory) EnsureRole(role signature.SignerRole) error {
+	if role != signature.SignerEntity {
+		return signature.ErrRoleMismatch
+	}
+	return nil
+}
+
+// Generate returns the Signer for the configured account index.
+//
+// As keys are derived on the device, this is equivalent to Load.
+func (fac *Factory) Generate(role signature.SignerRole, _rng io.Reader) (signature.Signer, error) {
+	return fac.Load(role)
+}
+
+// Load returns the Signer for the configured account index.
+func (fac *Factory) Load(role signature.SignerRole) (signature.Signer, error) {
+	if err := fac.EnsureRole(role); err != nil {
+		return nil, err
+	}
+
+	fac.Lock()
+	defer fac.Unlock()
+
+	if fac.signer != nil {
+		return fac.signer, nil
+	}
+
+	device, err := fac.cfg.Device()
+	if err != nil {
+		return nil, err
+	}
+	s := &Signer{
+		app:   &app{device: device},
+		index: fac.cfg.Index,
+	}
+	if s.publicKey, s.address, err = s.app.getAddress(s.index, fac.cfg.ConfirmAddress); err != nil {
+		_ = device.Close()
+		return nil, err
+	}
+	fac.signer = s
+
+	return s, nil
+}
+
+// Signer is a Ledger backed Signer.
+type Signer struct {
+	sync.Mutex
+
+	app   *app
+	index uint32
+
+	publicKey signature.PublicKey
+	address   string
+}
+
+// Public returns the PublicKey corresponding to the signer.
+func (s *Signer) Public() signature.PublicKey {
+	return s.publicKey
+}
+
+// Address returns the address corresponding to the signer, as reported by the device.
+func (s *Signer) Address() string {
+	return s.address
+}
+
+// Version returns the version of the Oasis app running on the device.
+func (s *Signer) Version() (*Version, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.app.getVersion()
+}
+
+// ConfirmAddress displays the signer's address on the device and waits for the user to
+// confirm it.
+func (s *Signer) ConfirmAddress() error {
+	s.Lock()
+	defer s.Unlock()
+
+	pk, _, err := s.app.getAddress(s.index, true)
+	if err != nil {
+		return err
+	}
+	if !pk.Equal(s.publicKey) {
+		return fmt.Errorf("signature/signer/ledger: public key changed")
+	}
+	return nil
+}
+
+// ContextSign generates a signature with the device's private key over the context and
+// message. The device displays the request and requires the user to confirm it.
+func (s *Signer) ContextSign(context signature.Context, message []byte) ([]byte, error) {
+	rawCtx, err := signature.PrepareSignerContext(context)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/ledger: failed to prepare context: %w", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	sig, err := s.app.sign(s.index, rawCtx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure that the device actually signed what we asked for.
+	if !s.publicKey.Verify(context, message, sig) {
+		return nil, fmt.Errorf("signature/signer/ledger: device returned an invalid signature")
+	}
+
+	return sig, nil
+}
+
+// String returns a string representation of the signer.
+func (s *Signer) String() string {
+	return fmt.Sprintf("[ledger signer: %s (index %d)]", s.publicKey, s.index)
+}
+
+// Reset closes the connection to the device.
+func (s *Signer) Reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	_ = s.app.device.Close()
+}
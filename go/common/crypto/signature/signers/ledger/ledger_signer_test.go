@@ -0,0 +1,158 @@
+package ledger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+var testSignatureCtx = signature.NewContext("oasis-core/ledger: test signature context")
+
+type mockDevice struct {
+	signer signature.Signer
+
+	confirmations int
+	reject        bool
+
+	signPath    []byte
+	signPayload []byte
+	closed      bool
+}
+
+func (d *mockDevice) Exchange(command []byte) ([]byte, error) {
+	ok := func(data []byte) ([]byte, error) {
+		return append(append([]byte{}, data...), 0x90, 0x00), nil
+	}
+	if command[0] != claOasis {
+		return []byte{0x6e, 0x00}, nil
+	}
+
+	ins, p1, data := command[1], command[2], command[5:5+int(command[4])]
+	switch ins {
+	case insGetVersion:
+		return ok([]byte{0, 1, 2, 3})
+	case insGetAddrEd25519:
+		if p1 == addrRequireConfirm {
+			d.confirmations++
+			if d.reject {
+				return []byte{0x69, 0x86}, nil
+			}
+		}
+		pk, _ := d.signer.Public().MarshalBinary()
+		return ok(append(pk, []byte("oasis1test")...))
+	case insSignEd25519:
+		switch p1 {
+		case payloadChunkInit:
+			d.signPath = data
+			d.signPayload = nil
+			return ok(nil)
+		case payloadChunkAdd:
+			d.signPayload = append(d.signPayload, data...)
+			return ok(nil)
+		case payloadChunkLast:
+			d.signPayload = append(d.signPayload, data...)
+			if d.reject {
+				return []byte{0x69, 0x86}, nil
+			}
+
+			ctxLen := int(d.signPayload[0])
+			rawCtx := d.signPayload[1 : 1+ctxLen]
+			message := d.signPayload[1+ctxLen:]
+			sig, err := d.signer.ContextSign(signature.Context(rawCtx), message)
+			if err != nil {
+				return nil, err
+			}
+			return ok(sig)
+		}
+	}
+	return []byte{0x6d, 0x00}, nil
+}
+
+func (d *mockDevice) Close() error {
+	d.closed = true
+	return nil
+}
+
+func TestLedgerSigner(t *testing.T) {
+	require := require.New(t)
+
+	memSigner, err := memorySigner.NewFactory().Generate(signature.SignerEntity, rand.Reader)
+	require.NoError(err, "Generate")
+	dev := &mockDevice{signer: memSigner}
+
+	_, err = NewFactory(&FactoryConfig{}, signature.SignerNode)
+	require.Error(err, "NewFactory: node role")
+
+	sf, err := NewFactory(&FactoryConfig{
+		Index:          3,
+		ConfirmAddress: true,
+		Device: func() (Device, error) {
+			return dev, nil
+		},
+	}, signature.SignerEntity)
+	require.NoError(err, "NewFactory")
+
+	require.Equal(signature.ErrRoleMismatch, sf.EnsureRole(signature.SignerConsensus), "EnsureRole: consensus")
+	require.NoError(sf.EnsureRole(signature.SignerEntity), "EnsureRole: entity")
+
+	signer, err := sf.Load(signature.SignerEntity)
+	require.NoError(err, "Load")
+	require.Equal(memSigner.Public(), signer.Public(), "Public")
+	require.Equal("oasis1test", signer.(*Signer).Address(), "Address")
+	require.Equal(1, dev.confirmations, "address should be confirmed on load")
+
+	signer2, err := sf.Generate(signature.SignerEntity, rand.Reader)
+	require.NoError(err, "Generate")
+	require.Equal(signer, signer2, "Generate should return the loaded signer")
+	require.Equal(1, dev.confirmations, "address should only be confirmed once")
+
+	version, err := signer.(*Signer).Version()
+	require.NoError(err, "Version")
+	require.Equal("1.2.3", version.String(), "Version")
+
+	// Use a message that spans multiple chunks.
+	msg := bytes.Repeat([]byte("message"), 100)
+	sig, err := signer.ContextSign(testSignatureCtx, msg)
+	require.NoError(err, "ContextSign")
+	require.True(signer.Public().Verify(testSignatureCtx, msg, sig), "signature should verify")
+	require.Equal(derivationPath(3), dev.signPath, "derivation path")
+	require.Equal(uint32(hardenedOffset|3), binary.LittleEndian.Uint32(dev.signPath[16:]), "account index")
+
+	dev.reject = true
+	_, err = signer.ContextSign(testSignatureCtx, msg)
+	require.Equal(ErrRejected, err, "ContextSign: rejected")
+	err = signer.(*Signer).ConfirmAddress()
+	require.Equal(ErrRejected, err, "ConfirmAddress: rejected")
+
+	signer.Reset()
+	require.True(dev.closed, "Reset should close the device")
+}
+
+func TestHIDFraming(t *testing.T) {
+	require := require.New(t)
+
+	for _, size := range []int{1, 57, 58, 59, 200, 300} {
+		command := bytes.Repeat([]byte{0xaa}, size)
+
+		packets := wrapCommandAPDU(hidChannel, command)
+		for _, packet := range packets {
+			require.Len(packet, hidPacketSize, "packet size")
+		}
+
+		var seq int
+		rsp, err := unwrapResponseAPDU(hidChannel, func() ([]byte, error) {
+			packet := packets[seq]
+			seq++
+			return packet, nil
+		})
+		require.NoError(err, "unwrapResponseAPDU")
+		require.Equal(command, rsp, "framing should round-trip (size: %d)", size)
+		require.Equal(len(packets), seq, "all packets should be consumed")
+	}
+}
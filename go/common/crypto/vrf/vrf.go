@@ -0,0 +1,415 @@
+// Package vrf implements the ECVRF-P256-SHA256-TAI verifiable random function
+// as specified in RFC 9381.
+package vrf
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/oasisprotocol/oasis-core/go/common/pem"
+)
+
+const (
+	// PublicKeySize is the size of a (compressed) public key in bytes.
+	PublicKeySize = 33
+
+	// PrivateKeySize is the size of a private key in bytes.
+	PrivateKeySize = 32
+
+	// ProofSize is the size of a VRF proof in bytes.
+	ProofSize = PublicKeySize + challengeSize + scalarSize
+
+	// OutputSize is the size of a VRF output in bytes.
+	OutputSize = sha256.Size
+
+	suiteString   = 0x01
+	challengeSize = 16
+	scalarSize    = 32
+
+	privPEMType = "VRF PRIVATE KEY"
+	filePerm    = 0o600
+)
+
+var (
+	// ErrMalformedPublicKey is the error returned when a public key is
+	// malformed.
+	ErrMalformedPublicKey = errors.New("vrf: malformed public key")
+
+	// ErrMalformedPrivateKey is the error returned when a private key is
+	// malformed.
+	ErrMalformedPrivateKey = errors.New("vrf: malformed private key")
+
+	// ErrMalformedProof is the error returned when a proof is malformed.
+	ErrMalformedProof = errors.New("vrf: malformed proof")
+
+	// ErrVerifyFailed is the error returned when proof verification fails.
+	ErrVerifyFailed = errors.New("vrf: proof verification failed")
+
+	errEncodeToCurve = errors.New("vrf: failed to encode input to curve")
+
+	curve = elliptic.P256()
+
+	_ encoding.BinaryMarshaler   = PublicKey{}
+	_ encoding.BinaryUnmarshaler = (*PublicKey)(nil)
+	_ encoding.TextMarshaler     = PublicKey{}
+	_ encoding.TextUnmarshaler   = (*PublicKey)(nil)
+)
+
+// PublicKey is a VRF public key.
+type PublicKey [PublicKeySize]byte
+
+// MarshalBinary encodes a public key into binary form.
+func (k PublicKey) MarshalBinary() (data []byte, err error) {
+	data = append([]byte{}, k[:]...)
+	return
+}
+
+// UnmarshalBinary decodes a binary marshaled public key.
+func (k *PublicKey) UnmarshalBinary(data []byte) error {
+	if len(data) != PublicKeySize {
+		return ErrMalformedPublicKey
+	}
+
+	copy(k[:], data)
+
+	return nil
+}
+
+// MarshalText encodes a public key into text form.
+func (k PublicKey) MarshalText() (data []byte, err error) {
+	return []byte(base64.StdEncoding.EncodeToString(k[:])), nil
+}
+
+// UnmarshalText decodes a text marshaled public key.
+func (k *PublicKey) UnmarshalText(text []byte) error {
+	b, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+
+	return k.UnmarshalBinary(b)
+}
+
+// UnmarshalHex deserializes a hexadecimal text string into the given type.
+func (k *PublicKey) UnmarshalHex(text string) error {
+	b, err := hex.DecodeString(text)
+	if err != nil {
+		return err
+	}
+
+	return k.UnmarshalBinary(b)
+}
+
+// Equal compares vs another public key for equality.
+func (k PublicKey) Equal(cmp PublicKey) bool {
+	return bytes.Equal(k[:], cmp[:])
+}
+
+// String returns a string representation of the public key.
+func (k PublicKey) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// IsValid checks whether the public key is a valid curve point.
+func (k PublicKey) IsValid() bool {
+	x, _ := elliptic.UnmarshalCompressed(curve, k[:])
+	return x != nil
+}
+
+// Verify verifies a VRF proof for the given input and returns the VRF output.
+func (k PublicKey) Verify(alpha, pi []byte) ([]byte, error) {
+	yX, yY := elliptic.UnmarshalCompressed(curve, k[:])
+	if yX == nil {
+		return nil, ErrMalformedPublicKey
+	}
+	gammaX, gammaY, c, s, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+
+	hX, hY, err := encodeToCurve(k[:], alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s*B - c*Y
+	sbX, sbY := curve.ScalarBaseMult(scalarBytes(s))
+	cyX, cyY := curve.ScalarMult(yX, yY, scalarBytes(c))
+	uX, uY := curve.Add(sbX, sbY, cyX, negate(cyY))
+	// V = s*H - c*Gamma
+	shX, shY := curve.ScalarMult(hX, hY, scalarBytes(s))
+	cgX, cgY := curve.ScalarMult(gammaX, gammaY, scalarBytes(c))
+	vX, vY := curve.Add(shX, shY, cgX, negate(cgY))
+	if isIdentity(uX, uY) || isIdentity(vX, vY) {
+		return nil, ErrVerifyFailed
+	}
+
+	expected := challenge(
+		k[:],
+		elliptic.MarshalCompressed(curve, hX, hY),
+		pi[:PublicKeySize],
+		elliptic.MarshalCompressed(curve, uX, uY),
+		elliptic.MarshalCompressed(curve, vX, vY),
+	)
+	if !hmac.Equal(expected, pi[PublicKeySize:PublicKeySize+challengeSize]) {
+		return nil, ErrVerifyFailed
+	}
+
+	return proofToHash(gammaX, gammaY), nil
+}
+
+// PrivateKey is a VRF private key.
+type PrivateKey struct {
+	x      *big.Int
+	public PublicKey
+}
+
+// Public returns the public key corresponding to the private key.
+func (k *PrivateKey) Public() PublicKey {
+	return k.public
+}
+
+// MarshalBinary encodes a private key into binary form.
+func (k *PrivateKey) MarshalBinary() (data []byte, err error) {
+	return scalarBytes(k.x), nil
+}
+
+// UnmarshalBinary decodes a binary marshaled private key.
+func (k *PrivateKey) UnmarshalBinary(data []byte) error {
+	if len(data) != PrivateKeySize {
+		return ErrMalformedPrivateKey
+	}
+
+	x := new(big.Int).SetBytes(data)
+	if x.Sign() == 0 || x.Cmp(curve.Params().N) >= 0 {
+		return ErrMalformedPrivateKey
+	}
+
+	k.x = x
+	yX, yY := curve.ScalarBaseMult(data)
+	copy(k.public[:], elliptic.MarshalCompressed(curve, yX, yY))
+
+	return nil
+}
+
+// UnmarshalPEM decodes a PEM marshaled private key.
+func (k *PrivateKey) UnmarshalPEM(data []byte) error {
+	b, err := pem.Unmarshal(privPEMType, data)
+	if err != nil {
+		return err
+	}
+
+	return k.UnmarshalBinary(b)
+}
+
+// MarshalPEM encodes a private key into PEM form.
+func (k *PrivateKey) MarshalPEM() (data []byte, err error) {
+	return pem.Marshal(privPEMType, scalarBytes(k.x))
+}
+
+// Prove computes a VRF proof for the given input.
+//
+// The corresponding VRF output can be obtained via ProofToHash.
+func (k *PrivateKey) Prove(alpha []byte) ([]byte, error) {
+	hX, hY, err := encodeToCurve(k.public[:], alpha)
+	if err != nil {
+		return nil, err
+	}
+	hString := elliptic.MarshalCompressed(curve, hX, hY)
+
+	xBytes := scalarBytes(k.x)
+	gammaX, gammaY := curve.ScalarMult(hX, hY, xBytes)
+	nonce := generateNonce(xBytes, hString)
+	uX, uY := curve.ScalarBaseMult(scalarBytes(nonce))
+	vX, vY := curve.ScalarMult(hX, hY, scalarBytes(nonce))
+
+	gammaString := elliptic.MarshalCompressed(curve, gammaX, gammaY)
+	c := challenge(
+		k.public[:],
+		hString,
+		gammaString,
+		elliptic.MarshalCompressed(curve, uX, uY),
+		elliptic.MarshalCompressed(curve, vX, vY),
+	)
+
+	// s = (k + c*x) mod q
+	s := new(big.Int).SetBytes(c)
+	s.Mul(s, k.x)
+	s.Add(s, nonce)
+	s.Mod(s, curve.Params().N)
+
+	pi := make([]byte, 0, ProofSize)
+	pi = append(pi, gammaString...)
+	pi = append(pi, c...)
+	pi = append(pi, scalarBytes(s)...)
+	return pi, nil
+}
+
+// GenerateKey generates a new VRF private key using the given entropy source.
+func GenerateKey(rng io.Reader) (*PrivateKey, error) {
+	var raw [PrivateKeySize]byte
+	for {
+		if _, err := io.ReadFull(rng, raw[:]); err != nil {
+			return nil, err
+		}
+
+		var k PrivateKey
+		if err := k.UnmarshalBinary(raw[:]); err == nil {
+			return &k, nil
+		}
+	}
+}
+
+// LoadOrGeneratePrivateKey loads a PEM encoded private key from disk or
+// generates (and persists) a new one iff it does not exist.
+func LoadOrGeneratePrivateKey(fn string, rng io.Reader) (*PrivateKey, error) {
+	data, err := ioutil.ReadFile(fn)
+	switch {
+	case err == nil:
+		var k PrivateKey
+		if err = k.UnmarshalPEM(data); err != nil {
+			return nil, err
+		}
+		return &k, nil
+	case os.IsNotExist(err):
+	default:
+		return nil, err
+	}
+
+	k, err := GenerateKey(rng)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = k.MarshalPEM(); err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(fn, data, filePerm); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// ProofToHash returns the VRF output corresponding to the given proof.
+//
+// Note that this does not verify the proof, use PublicKey.Verify instead
+// for proofs obtained from untrusted sources.
+func ProofToHash(pi []byte) ([]byte, error) {
+	gammaX, gammaY, _, _, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return proofToHash(gammaX, gammaY), nil
+}
+
+func proofToHash(gammaX, gammaY *big.Int) []byte {
+	// The P-256 cofactor is 1.
+	h := sha256.New()
+	_, _ = h.Write([]byte{suiteString, 0x03})
+	_, _ = h.Write(elliptic.MarshalCompressed(curve, gammaX, gammaY))
+	_, _ = h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
+
+func decodeProof(pi []byte) (gammaX, gammaY, c, s *big.Int, err error) {
+	if len(pi) != ProofSize {
+		return nil, nil, nil, nil, ErrMalformedProof
+	}
+
+	gammaX, gammaY = elliptic.UnmarshalCompressed(curve, pi[:PublicKeySize])
+	if gammaX == nil {
+		return nil, nil, nil, nil, ErrMalformedProof
+	}
+	c = new(big.Int).SetBytes(pi[PublicKeySize : PublicKeySize+challengeSize])
+	s = new(big.Int).SetBytes(pi[PublicKeySize+challengeSize:])
+	if s.Cmp(curve.Params().N) >= 0 {
+		return nil, nil, nil, nil, ErrMalformedProof
+	}
+	return
+}
+
+// encodeToCurve implements ECVRF_encode_to_curve_try_and_increment.
+func encodeToCurve(pk, alpha []byte) (*big.Int, *big.Int, error) {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha256.New()
+		_, _ = h.Write([]byte{suiteString, 0x01})
+		_, _ = h.Write(pk)
+		_, _ = h.Write(alpha)
+		_, _ = h.Write([]byte{byte(ctr), 0x00})
+
+		x, y := elliptic.UnmarshalCompressed(curve, append([]byte{0x02}, h.Sum(nil)...))
+		if x != nil {
+			return x, y, nil
+		}
+	}
+	return nil, nil, errEncodeToCurve
+}
+
+// challenge implements ECVRF_challenge_generation.
+func challenge(points ...[]byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte{suiteString, 0x02})
+	for _, p := range points {
+		_, _ = h.Write(p)
+	}
+	_, _ = h.Write([]byte{0x00})
+	return h.Sum(nil)[:challengeSize]
+}
+
+// generateNonce implements ECVRF_nonce_generation_RFC6979.
+func generateNonce(x, hString []byte) *big.Int {
+	n := curve.Params().N
+	h1 := sha256.Sum256(hString)
+	h1Int := new(big.Int).SetBytes(h1[:])
+	h1Int.Mod(h1Int, n)
+	h1Octets := scalarBytes(h1Int)
+
+	mac := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, d := range data {
+			_, _ = m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+
+	v := bytes.Repeat([]byte{0x01}, sha256.Size)
+	k := make([]byte, sha256.Size)
+	k = mac(k, v, []byte{0x00}, x, h1Octets)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h1Octets)
+	v = mac(k, v)
+	for {
+		v = mac(k, v)
+		nonce := new(big.Int).SetBytes(v)
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			return nonce
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+func scalarBytes(s *big.Int) []byte {
+	var b [scalarSize]byte
+	return s.FillBytes(b[:])
+}
+
+func negate(y *big.Int) *big.Int {
+	if y.Sign() == 0 {
+		return y
+	}
+	return new(big.Int).Sub(curve.Params().P, y)
+}
+
+func isIdentity(x, y *big.Int) bool {
+	return x.Sign() == 0 && y.Sign() == 0
+}
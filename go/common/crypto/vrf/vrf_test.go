@@ -0,0 +1,131 @@
+package vrf
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/drbg"
+)
+
+func TestVectors(t *testing.T) {
+	require := require.New(t)
+
+	// Test vectors from RFC 9381 Appendix B.1.
+	for _, v := range []struct {
+		sk    string
+		pk    string
+		alpha string
+		pi    string
+		beta  string
+	}{
+		{
+			sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+			alpha: "73616d706c65",
+			pi:    "035b5c726e8c0e2c488a107c600578ee75cb702343c153cb1eb8dec77f4b5071b4a53f0a46f018bc2c56e58d383f2305e0975972c26feea0eb122fe7893c15af376b33edf7de17c6ea056d4d82de6bc02f",
+			beta:  "a3ad7b0ef73d8fc6655053ea22f9bede8c743f08bbed3d38821f0e16474b505e",
+		},
+	} {
+		rawSk, _ := hex.DecodeString(v.sk)
+		alpha, _ := hex.DecodeString(v.alpha)
+
+		var sk PrivateKey
+		err := sk.UnmarshalBinary(rawSk)
+		require.NoError(err, "UnmarshalBinary")
+
+		var pk PublicKey
+		err = pk.UnmarshalHex(v.pk)
+		require.NoError(err, "UnmarshalHex")
+		require.True(pk.Equal(sk.Public()), "public key should match")
+		require.True(pk.IsValid(), "public key should be valid")
+
+		pi, err := sk.Prove(alpha)
+		require.NoError(err, "Prove")
+		require.Equal(v.pi, hex.EncodeToString(pi), "proof should match")
+
+		beta, err := pk.Verify(alpha, pi)
+		require.NoError(err, "Verify")
+		require.Equal(v.beta, hex.EncodeToString(beta), "output should match")
+
+		beta, err = ProofToHash(pi)
+		require.NoError(err, "ProofToHash")
+		require.Equal(v.beta, hex.EncodeToString(beta), "output should match")
+	}
+}
+
+func TestProveVerify(t *testing.T) {
+	require := require.New(t)
+
+	rng, err := drbg.New(crypto.SHA512, []byte("vrf test entropyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyy"), nil, []byte("vrf test"))
+	require.NoError(err, "drbg.New")
+
+	sk, err := GenerateKey(rng)
+	require.NoError(err, "GenerateKey")
+	otherSk, err := GenerateKey(rng)
+	require.NoError(err, "GenerateKey")
+
+	alpha := []byte("vrf test input")
+	pi, err := sk.Prove(alpha)
+	require.NoError(err, "Prove")
+	require.Len(pi, ProofSize, "proof should have the correct size")
+
+	beta, err := sk.Public().Verify(alpha, pi)
+	require.NoError(err, "Verify")
+	require.Len(beta, OutputSize, "output should have the correct size")
+
+	// Proofs should be deterministic.
+	pi2, err := sk.Prove(alpha)
+	require.NoError(err, "Prove")
+	require.EqualValues(pi, pi2, "proofs should be deterministic")
+
+	// Different inputs should result in different outputs.
+	pi2, err = sk.Prove([]byte("other vrf test input"))
+	require.NoError(err, "Prove")
+	beta2, err := sk.Public().Verify([]byte("other vrf test input"), pi2)
+	require.NoError(err, "Verify")
+	require.NotEqualValues(beta, beta2, "outputs for different inputs should differ")
+
+	// Invalid proofs.
+	_, err = sk.Public().Verify([]byte("other vrf test input"), pi)
+	require.Error(err, "Verify should fail for a different input")
+	_, err = otherSk.Public().Verify(alpha, pi)
+	require.Error(err, "Verify should fail for a different public key")
+	_, err = sk.Public().Verify(alpha, pi[:ProofSize-1])
+	require.Error(err, "Verify should fail for a truncated proof")
+	for _, i := range []int{1, PublicKeySize, ProofSize - 1} {
+		corrupted := append([]byte{}, pi...)
+		corrupted[i] ^= 0x01
+		_, err = sk.Public().Verify(alpha, corrupted)
+		require.Error(err, "Verify should fail for a corrupted proof (byte %d)", i)
+	}
+	var invalidPk PublicKey
+	require.False(invalidPk.IsValid(), "zero public key should be invalid")
+	_, err = invalidPk.Verify(alpha, pi)
+	require.Error(err, "Verify should fail for an invalid public key")
+}
+
+func TestPrivateKeyPEM(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-vrf-test_")
+	require.NoError(err, "create data dir")
+	defer os.RemoveAll(dataDir)
+
+	fn := filepath.Join(dataDir, "vrf.pem")
+	sk, err := LoadOrGeneratePrivateKey(fn, rand.Reader)
+	require.NoError(err, "LoadOrGeneratePrivateKey (generate)")
+	sk2, err := LoadOrGeneratePrivateKey(fn, rand.Reader)
+	require.NoError(err, "LoadOrGeneratePrivateKey (load)")
+	require.True(sk.Public().Equal(sk2.Public()), "loaded key should match generated key")
+
+	var invalidSk PrivateKey
+	err = invalidSk.UnmarshalBinary(make([]byte, PrivateKeySize))
+	require.Error(err, "UnmarshalBinary should fail for a zero key")
+}
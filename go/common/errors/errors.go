@@ -76,6 +76,45 @@ func FromCode(module string, code uint32) error {
 	return err.(*codedError)
 }
 
+// FromCodeWithMessage reconstructs an error from module, code and message as
+// produced by Code and the error's Error method.
+//
+// In case the module and code correspond to a previously registered error, the
+// returned error matches it via Is while preserving the given message (which
+// may include additional context). Otherwise the returned error still carries
+// the module and code so that they can be retrieved via Code.
+func FromCodeWithMessage(module string, code uint32, msg string) error {
+	if err := FromCode(module, code); err != nil {
+		if msg == "" || msg == err.Error() {
+			return err
+		}
+		return &messageError{err: err, msg: msg}
+	}
+
+	if module == "" || module == UnknownModule || code == CodeNoError {
+		return errors.New(msg)
+	}
+	return &codedError{
+		module: module,
+		code:   code,
+		msg:    msg,
+	}
+}
+
+// messageError is a registered error with a different message.
+type messageError struct {
+	err error
+	msg string
+}
+
+func (e *messageError) Error() string {
+	return e.msg
+}
+
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
 // Code returns the module and code for the given error.
 //
 // In case the error is not of the correct type, default values
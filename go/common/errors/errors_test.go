@@ -56,3 +56,38 @@ func TestErrors(t *testing.T) {
 	err = FromCode("test/errors", 3)
 	require.Nil(err)
 }
+
+func TestFromCodeWithMessage(t *testing.T) {
+	require := require.New(t)
+
+	errTest := New("test/errors/message", 1, "test: this is an error")
+
+	// Registered error with the same message.
+	err := FromCodeWithMessage("test/errors/message", 1, "test: this is an error")
+	require.Equal(errTest, err)
+	err = FromCodeWithMessage("test/errors/message", 1, "")
+	require.Equal(errTest, err)
+
+	// Registered error with additional context.
+	wrapped := fmt.Errorf("context: %w", errTest)
+	module, code := Code(wrapped)
+	err = FromCodeWithMessage(module, code, wrapped.Error())
+	require.True(Is(err, errTest), "error should match the registered error")
+	require.Equal(wrapped.Error(), err.Error(), "error message should be preserved")
+	module, code = Code(err)
+	require.Equal("test/errors/message", module)
+	require.EqualValues(1, code)
+
+	// Unregistered module and code.
+	err = FromCodeWithMessage("test/does-not-exist", 5, "test: remote error")
+	require.Equal("test: remote error", err.Error())
+	module, code = Code(err)
+	require.Equal("test/does-not-exist", module)
+	require.EqualValues(5, code)
+
+	// Unknown module.
+	err = FromCodeWithMessage(UnknownModule, 1, "test: unknown error")
+	require.Equal("test: unknown error", err.Error())
+	module, _ = Code(err)
+	require.Equal(UnknownModule, module)
+}
@@ -87,8 +87,11 @@ func errorFromGrpc(err error) error {
 			return err
 		}
 
-		if mappedErr := errors.FromCode(ge.Module, ge.Code); mappedErr != nil {
-			return mappedErr
+		if ge.Module != "" && ge.Module != errors.UnknownModule {
+			// Preserve the module and code (so that the error can be matched against
+			// registered errors) as well as the message (which may include additional
+			// context provided by the server).
+			return errors.FromCodeWithMessage(ge.Module, ge.Code, s.Message())
 		}
 	}
 
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -162,3 +163,25 @@ func TestErrorMapping(t *testing.T) {
 	s, _ := status.FromError(io.ErrUnexpectedEOF)
 	require.EqualValues(s, st, "GetErrorStatus.Status should be io.ErrUnexpectedEOF")
 }
+
+func TestErrorMappingContext(t *testing.T) {
+	require := require.New(t)
+
+	// Registered errors should be mapped back to the same error.
+	err := errorFromGrpc(errorToGrpc(errTest))
+	require.Equal(errTest, err, "errors should be properly mapped")
+
+	// Registered errors with additional context should preserve the context.
+	wrapped := fmt.Errorf("some context: %w", errTest)
+	err = errorFromGrpc(errorToGrpc(wrapped))
+	require.True(errors.Is(err, errTest), "wrapped errors should be properly mapped")
+	require.Equal(wrapped.Error(), err.Error(), "error context should be preserved")
+
+	// Unregistered errors should preserve the module and code.
+	unregistered := errors.FromCodeWithMessage("test/grpc/unregistered", 2, "unregistered error")
+	err = errorFromGrpc(errorToGrpc(unregistered))
+	require.Equal(unregistered.Error(), err.Error(), "error message should be preserved")
+	module, code := errors.Code(err)
+	require.Equal("test/grpc/unregistered", module, "error module should be preserved")
+	require.EqualValues(2, code, "error code should be preserved")
+}
@@ -125,6 +125,11 @@ func (c *DynamicRuntimePolicyChecker) CheckAccessAllowed(
 	c.RLock()
 	defer c.RUnlock()
 
+	// Actions allowed for all subjects do not require client authentication.
+	if policy := c.accessPolicies[runtimeID]; policy != nil && policy.IsAllowedForAll(method) {
+		return nil
+	}
+
 	peer, ok := peer.FromContext(ctx)
 	if !ok {
 		return status.Errorf(codes.PermissionDenied, "grpc: failed to obtain connection peer from context")
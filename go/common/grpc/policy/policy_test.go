@@ -124,4 +124,18 @@ func TestAccessPolicy(t *testing.T) {
 	res, err := client.Ping(ctx, pingQuery)
 	require.NoError(err, "Calling Ping with proper access policy set should succeed")
 	require.IsType(&cmnTesting.PingResponse{}, res, "Calling Ping should return a response of the correct type")
+
+	// Add a policy rule to allow any client to call Ping.
+	policy = accessctl.NewPolicy()
+	policy.Allow(accessctl.AnySubject, accessctl.Action(cmnTesting.MethodPing.FullName()))
+	policyChecker.SetAccessPolicy(policy, testNs)
+
+	// Connect to the gRPC server without a client certificate.
+	conn = connectToGrpcServer(ctx, t, address, clientTLSCredsWithoutCert)
+	defer conn.Close()
+	client = cmnTesting.NewPingClient(conn)
+
+	res, err = client.Ping(ctx, pingQuery)
+	require.NoError(err, "Calling Ping without a client certificate should succeed when allowed for any subject")
+	require.IsType(&cmnTesting.PingResponse{}, res, "Calling Ping should return a response of the correct type")
 }
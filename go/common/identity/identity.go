@@ -11,6 +11,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	tlsCert "github.com/oasisprotocol/oasis-core/go/common/crypto/tls"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/vrf"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 )
@@ -35,6 +36,8 @@ const (
 	// These are used for the sentry client connection to the sentry node and are never rotated.
 	tlsSentryClientKeyFilename  = "sentry_client_tls_identity.pem"
 	tlsSentryClientCertFilename = "sentry_client_tls_identity_cert.pem"
+
+	vrfKeyFilename = "vrf_identity.pem"
 )
 
 // ErrCertificateRotationForbidden is returned by RotateCertificates if
@@ -53,6 +56,8 @@ type Identity struct {
 	P2PSigner signature.Signer
 	// ConsensusSigner is a node consensus key signer.
 	ConsensusSigner signature.Signer
+	// VRFKey is a node VRF private key used for generating beacon entropy.
+	VRFKey *vrf.PrivateKey
 
 	// TLSSentryClientCertificate is the client certificate used for
 	// connecting to the sentry node's control connection.  It is never rotated.
@@ -272,10 +277,17 @@ func doLoadOrGenerate(dataDir string, signerFactory signature.SignerFactory, sho
 		}
 	}
 
+	// Load or generate the VRF key for this node.
+	vrfKey, err := vrf.LoadOrGeneratePrivateKey(filepath.Join(dataDir, vrfKeyFilename), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Identity{
 		NodeSigner:                 signers[0],
 		P2PSigner:                  signers[1],
 		ConsensusSigner:            signers[2],
+		VRFKey:                     vrfKey,
 		tlsSigner:                  memory.NewFromRuntime(cert.PrivateKey.(ed25519.PrivateKey)),
 		tlsCertificate:             cert,
 		nextTLSSigner:              nextSigner,
@@ -30,6 +30,7 @@ func TestLoadOrGenerate(t *testing.T) {
 	require.EqualValues(t, identity.NodeSigner, identity2.NodeSigner)
 	require.EqualValues(t, identity.P2PSigner, identity2.P2PSigner)
 	require.EqualValues(t, identity.ConsensusSigner, identity2.ConsensusSigner)
+	require.EqualValues(t, identity.VRFKey.Public(), identity2.VRFKey.Public())
 	require.EqualValues(t, identity.GetTLSSigner(), identity2.GetTLSSigner())
 	require.EqualValues(t, identity.GetTLSCertificate(), identity2.GetTLSCertificate())
 	require.EqualValues(t, identity.GetTLSPubKeys(), identity2.GetTLSPubKeys())
@@ -52,6 +53,7 @@ func TestLoadOrGenerate(t *testing.T) {
 	require.EqualValues(t, identity3.NodeSigner, identity4.NodeSigner)
 	require.EqualValues(t, identity3.P2PSigner, identity4.P2PSigner)
 	require.EqualValues(t, identity3.ConsensusSigner, identity4.ConsensusSigner)
+	require.EqualValues(t, identity3.VRFKey.Public(), identity4.VRFKey.Public())
 	require.NotEqual(t, identity.GetTLSSigner(), identity3.GetTLSSigner())
 	require.NotEqual(t, identity2.GetTLSSigner(), identity3.GetTLSSigner())
 	require.NotEqual(t, identity3.GetTLSSigner(), identity4.GetTLSSigner())
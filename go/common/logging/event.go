@@ -1,8 +1,132 @@
 package logging
 
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 // LogEvent is the structured log key used to signal log events to
 // be easily parsed by the testing harness.
 //
 // Values should be defined as constants in the respective modules
 // that emit these events.
 const LogEvent = "log_event"
+
+// Event is a log event observed by an event handler.
+type Event struct {
+	// Event is the log event value.
+	Event string `json:"event"`
+	// Module is the module of the logger that emitted the event.
+	Module string `json:"module,omitempty"`
+	// Level is the level at which the event was logged.
+	Level string `json:"level"`
+	// Message is the log message.
+	Message string `json:"msg"`
+	// Timestamp is the time at which the event was logged.
+	Timestamp time.Time `json:"ts"`
+	// Fields are the remaining key/value pairs of the log entry.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// EventHandler is a handler invoked for observed log events.
+//
+// Handlers are invoked synchronously by the logging goroutine and must not
+// block.
+type EventHandler func(ev *Event)
+
+type eventHandlerEntry struct {
+	events  map[string]bool
+	handler EventHandler
+}
+
+var eventHandlers struct {
+	sync.RWMutex
+
+	active  int32
+	nextID  uint64
+	entries map[uint64]*eventHandlerEntry
+}
+
+// RegisterEventHandler registers a handler that is invoked for each log event
+// with one of the given values, irrespective of the configured log levels.
+//
+// The returned function unregisters the handler.
+func RegisterEventHandler(events []string, handler EventHandler) func() {
+	entry := &eventHandlerEntry{
+		events:  make(map[string]bool),
+		handler: handler,
+	}
+	for _, ev := range events {
+		entry.events[ev] = true
+	}
+
+	eventHandlers.Lock()
+	defer eventHandlers.Unlock()
+
+	if eventHandlers.entries == nil {
+		eventHandlers.entries = make(map[uint64]*eventHandlerEntry)
+	}
+	id := eventHandlers.nextID
+	eventHandlers.nextID++
+	eventHandlers.entries[id] = entry
+	atomic.StoreInt32(&eventHandlers.active, int32(len(eventHandlers.entries)))
+
+	return func() {
+		eventHandlers.Lock()
+		defer eventHandlers.Unlock()
+
+		delete(eventHandlers.entries, id)
+		atomic.StoreInt32(&eventHandlers.active, int32(len(eventHandlers.entries)))
+	}
+}
+
+func observeEvent(module string, lvl Level, msg string, keyvals []interface{}) {
+	if atomic.LoadInt32(&eventHandlers.active) == 0 {
+		return
+	}
+
+	var (
+		event  string
+		fields = make(map[string]string)
+	)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if key == LogEvent {
+			event = fmt.Sprint(keyvals[i+1])
+			continue
+		}
+		fields[key] = fmt.Sprint(keyvals[i+1])
+	}
+	if event == "" {
+		return
+	}
+
+	// Collect matching handlers first so that handlers may log themselves.
+	var handlers []EventHandler
+	eventHandlers.RLock()
+	for _, entry := range eventHandlers.entries {
+		if entry.events[event] {
+			handlers = append(handlers, entry.handler)
+		}
+	}
+	eventHandlers.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	ev := &Event{
+		Event:     event,
+		Module:    module,
+		Level:     lvl.String(),
+		Message:   msg,
+		Timestamp: time.Now().UTC(),
+	}
+	if len(fields) > 0 {
+		ev.Fields = fields
+	}
+	for _, h := range handlers {
+		h(ev)
+	}
+}
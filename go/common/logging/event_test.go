@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHandler(t *testing.T) {
+	require := require.New(t)
+
+	var events []*Event
+	unregister := RegisterEventHandler([]string{"test/event"}, func(ev *Event) {
+		events = append(events, ev)
+	})
+
+	logger := GetLogger("test/module").With("extra", 42)
+	logger.Debug("filtered out by log level",
+		"err", fmt.Errorf("test error"),
+		LogEvent, "test/event",
+	)
+	logger.Error("other event",
+		LogEvent, "test/other",
+	)
+	logger.Error("no event")
+
+	require.Len(events, 1, "only designated events should be observed")
+	ev := events[0]
+	require.Equal("test/event", ev.Event)
+	require.Equal("test/module", ev.Module)
+	require.Equal("DEBUG", ev.Level)
+	require.Equal("filtered out by log level", ev.Message)
+	require.Equal(map[string]string{"err": "test error"}, ev.Fields)
+
+	unregister()
+	logger.Error("after unregister",
+		LogEvent, "test/event",
+	)
+	require.Len(events, 1, "unregistered handlers should not be invoked")
+}
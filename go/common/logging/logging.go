@@ -149,6 +149,7 @@ type Logger struct {
 
 // Debug logs the message and key value pairs at the Debug log level.
 func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	observeEvent(l.module, LevelDebug, msg, keyvals)
 	if l.level > LevelDebug {
 		return
 	}
@@ -158,6 +159,7 @@ func (l *Logger) Debug(msg string, keyvals ...interface{}) {
 
 // Info logs the message and key value pairs at the Info log level.
 func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	observeEvent(l.module, LevelInfo, msg, keyvals)
 	if l.level > LevelInfo {
 		return
 	}
@@ -167,6 +169,7 @@ func (l *Logger) Info(msg string, keyvals ...interface{}) {
 
 // Warn logs the message and key value pairs at the Warn log level.
 func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	observeEvent(l.module, LevelWarn, msg, keyvals)
 	if l.level > LevelWarn {
 		return
 	}
@@ -176,6 +179,7 @@ func (l *Logger) Warn(msg string, keyvals ...interface{}) {
 
 // Error logs the message and key value pairs at the Error log level.
 func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	observeEvent(l.module, LevelError, msg, keyvals)
 	if l.level > LevelError {
 		return
 	}
@@ -189,6 +193,7 @@ func (l *Logger) With(keyvals ...interface{}) *Logger {
 	return &Logger{
 		logger: log.With(l.logger, keyvals...),
 		level:  l.level,
+		module: l.module,
 	}
 }
 
@@ -15,6 +15,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/vrf"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
@@ -74,6 +75,10 @@ type Node struct { // nolint: maligned
 	// NOTE: This is reserved for future use.
 	Beacon cbor.RawMessage `json:"beacon,omitempty"`
 
+	// VRF contains information for this node's participation in the VRF
+	// based random beacon protocol.
+	VRF *VRFInfo `json:"vrf,omitempty"`
+
 	// Runtimes are the node's runtimes.
 	Runtimes []*Runtime `json:"runtimes"`
 
@@ -132,6 +137,22 @@ func (m RolesMask) String() string {
 	return strings.Join(ret, ",")
 }
 
+// RoleFromString parses a single role from its string representation.
+func RoleFromString(s string) (RolesMask, error) {
+	for _, role := range []RolesMask{
+		RoleComputeWorker,
+		RoleStorageWorker,
+		RoleKeyManager,
+		RoleValidator,
+		RoleConsensusRPC,
+	} {
+		if role.String() == s {
+			return role, nil
+		}
+	}
+	return 0, fmt.Errorf("node: invalid role: '%s'", s)
+}
+
 // ValidateBasic performs basic descriptor validity checks.
 func (n *Node) ValidateBasic(strictVersion bool) error {
 	v := n.Versioned.V
@@ -272,10 +293,56 @@ type ConsensusInfo struct {
 	Addresses []ConsensusAddress `json:"addresses"`
 }
 
+// VRFInfo contains information for this node's participation in the VRF
+// based random beacon protocol.
+type VRFInfo struct {
+	// ID is the unique identifier of the node used to generate VRF proofs.
+	ID vrf.PublicKey `json:"id"`
+}
+
 // Capabilities represents a node's capabilities.
 type Capabilities struct {
 	// TEE is the capability of a node executing batches in a TEE.
 	TEE *CapabilityTEE `json:"tee,omitempty"`
+
+	// Storage is the capability of a node serving runtime storage.
+	Storage *CapabilityStorage `json:"storage,omitempty"`
+}
+
+// MaxStorageCheckpoints is the maximum number of storage checkpoints that a node can advertise
+// for a single runtime.
+const MaxStorageCheckpoints = 16
+
+// MaxStorageCheckpointStoreURLLength is the maximum length of an advertised external checkpoint
+// store URL.
+const MaxStorageCheckpointStoreURLLength = 512
+
+// CapabilityStorage represents the node's capability of serving runtime storage.
+type CapabilityStorage struct {
+	// Checkpoints are the storage checkpoints that the node is able to serve.
+	Checkpoints []StorageCheckpoint `json:"checkpoints,omitempty"`
+
+	// CheckpointStoreURL is the optional base URL of an external object store from which chunks
+	// of the advertised checkpoints can be fetched directly.
+	CheckpointStoreURL string `json:"checkpoint_store_url,omitempty"`
+}
+
+// StorageCheckpoint is an advertisement of a storage checkpoint available on a node.
+type StorageCheckpoint struct {
+	// Version is the checkpoint format version.
+	Version uint16 `json:"version"`
+
+	// Round is the runtime round of the checkpointed root.
+	Round uint64 `json:"round"`
+
+	// Root is the checkpointed root hash.
+	Root hash.Hash `json:"root"`
+
+	// Chunks is the number of chunks in the checkpoint.
+	Chunks uint64 `json:"chunks"`
+
+	// Hash is the hash of the encoded checkpoint metadata.
+	Hash hash.Hash `json:"hash"`
 }
 
 // TEEHardware is a TEE hardware implementation.
@@ -33,3 +33,24 @@ func TestNodeDescriptor(t *testing.T) {
 	require.Equal(&rt1, &rt2, "AddOrUpdateRuntime should return the same reference for same id")
 	require.Len(n.Runtimes, 1)
 }
+
+func TestRoleFromString(t *testing.T) {
+	require := require.New(t)
+
+	for _, role := range []RolesMask{
+		RoleComputeWorker,
+		RoleStorageWorker,
+		RoleKeyManager,
+		RoleValidator,
+		RoleConsensusRPC,
+	} {
+		parsed, err := RoleFromString(role.String())
+		require.NoError(err, "RoleFromString(%s)", role)
+		require.Equal(role, parsed)
+	}
+
+	_, err := RoleFromString("compute,storage")
+	require.Error(err, "RoleFromString should reject multiple roles")
+	_, err = RoleFromString("")
+	require.Error(err, "RoleFromString should reject empty roles")
+}
@@ -0,0 +1,271 @@
+package persistent
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+var (
+	// ErrUnsupportedVersion is returned when a service store has a schema version that is newer
+	// than what the registered migrations support (e.g., after a downgrade).
+	ErrUnsupportedVersion = errors.New("persistent: unsupported service store schema version")
+
+	// Metadata keys are prefixed by a zero byte so they can never clash with service keys.
+	versionKeyPrefix   = []byte("\x00version.")
+	migratingKeyPrefix = []byte("\x00migrating.")
+	backupKeyPrefix    = []byte("\x00backup.")
+
+	migrationsLock       sync.Mutex
+	registeredMigrations = make(map[string]map[uint64]MigrationFunc)
+)
+
+// MigrationFunc is a service store schema migration function. It is called with the service
+// store at the schema version the migration was registered for and must leave the store at the
+// next schema version.
+type MigrationFunc func(ss *ServiceStore) error
+
+// RegisterMigration registers a schema migration for the named service store, upgrading it from
+// the given schema version to the next one.
+//
+// The latest schema version of a service store is the number of migrations registered for it
+// and migrations must be registered for all versions starting at zero. Pending migrations are
+// run when the service store is opened via CommonStore.GetServiceStore.
+func RegisterMigration(name string, version uint64, fn MigrationFunc) {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+
+	migrations := registeredMigrations[name]
+	if migrations == nil {
+		migrations = make(map[uint64]MigrationFunc)
+		registeredMigrations[name] = migrations
+	}
+	if _, exists := migrations[version]; exists {
+		panic(fmt.Sprintf("persistent: migration for service store '%s' version %d already registered", name, version))
+	}
+	migrations[version] = fn
+}
+
+func getMigrations(name string) ([]MigrationFunc, error) {
+	migrationsLock.Lock()
+	defer migrationsLock.Unlock()
+
+	migrations := registeredMigrations[name]
+	fns := make([]MigrationFunc, 0, len(migrations))
+	for v := uint64(0); v < uint64(len(migrations)); v++ {
+		fn, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("persistent: missing migration for service store '%s' version %d", name, v)
+		}
+		fns = append(fns, fn)
+	}
+	return fns, nil
+}
+
+// Version returns the schema version of the service store.
+func (ss *ServiceStore) Version() (uint64, error) {
+	var version uint64
+	err := ss.store.db.View(func(tx *badger.Txn) error {
+		var err error
+		version, err = getUint64(tx, ss.metaKey(versionKeyPrefix))
+		return err
+	})
+	return version, err
+}
+
+// migrate runs all pending schema migrations for the service store.
+//
+// Before any migration is run, the contents of the service store are backed up. In case a
+// migration fails (or the node crashes while migrating), the backup is restored so that the
+// migrations can be retried. The backup of the last migration is retained.
+func (ss *ServiceStore) migrate() error {
+	migrations, err := getMigrations(string(ss.name))
+	if err != nil {
+		return err
+	}
+	latest := uint64(len(migrations))
+
+	var (
+		version     uint64
+		interrupted bool
+	)
+	err = ss.store.db.View(func(tx *badger.Txn) error {
+		var txErr error
+		if version, txErr = getUint64(tx, ss.metaKey(versionKeyPrefix)); txErr != nil {
+			return txErr
+		}
+		switch _, txErr = tx.Get(ss.metaKey(migratingKeyPrefix)); txErr {
+		case nil:
+			interrupted = true
+		case badger.ErrKeyNotFound:
+		default:
+			return txErr
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("persistent: failed to query service store '%s' version: %w", ss.name, err)
+	}
+
+	if interrupted {
+		ss.store.logger.Warn("previous service store migration interrupted, restoring backup",
+			"service", string(ss.name),
+			"version", version,
+		)
+		if err = ss.restoreBackup(); err != nil {
+			return fmt.Errorf("persistent: failed to restore service store '%s' backup: %w", ss.name, err)
+		}
+	}
+
+	switch {
+	case version == latest:
+		return nil
+	case version > latest:
+		return fmt.Errorf("%w: service store '%s' is at version %d (supported: %d)", ErrUnsupportedVersion, ss.name, version, latest)
+	}
+
+	ss.store.logger.Info("migrating service store",
+		"service", string(ss.name),
+		"from_version", version,
+		"to_version", latest,
+	)
+
+	if err = ss.createBackup(); err != nil {
+		return fmt.Errorf("persistent: failed to back up service store '%s': %w", ss.name, err)
+	}
+
+	for v := version; v < latest; v++ {
+		if err = migrations[v](ss); err != nil {
+			ss.store.logger.Error("service store migration failed, restoring backup",
+				"err", err,
+				"service", string(ss.name),
+				"version", v,
+			)
+			if rerr := ss.restoreBackup(); rerr != nil {
+				return fmt.Errorf("persistent: failed to restore service store '%s' backup: %w", ss.name, rerr)
+			}
+			return fmt.Errorf("persistent: failed to migrate service store '%s' from version %d: %w", ss.name, v, err)
+		}
+	}
+
+	return ss.store.db.Update(func(tx *badger.Txn) error {
+		if txErr := tx.Set(ss.metaKey(versionKeyPrefix), encodeUint64(latest)); txErr != nil {
+			return txErr
+		}
+		return tx.Delete(ss.metaKey(migratingKeyPrefix))
+	})
+}
+
+// createBackup replaces the service store backup with its current contents and marks the
+// service store as being migrated.
+func (ss *ServiceStore) createBackup() error {
+	return ss.store.db.Update(func(tx *badger.Txn) error {
+		backupPrefix := ss.backupPrefix()
+		if err := deletePrefix(tx, backupPrefix); err != nil {
+			return err
+		}
+		if err := copyPrefix(tx, ss.dbKey(nil), backupPrefix); err != nil {
+			return err
+		}
+		return tx.Set(ss.metaKey(migratingKeyPrefix), []byte{})
+	})
+}
+
+// restoreBackup replaces the service store contents with its backup and clears the migration
+// marker.
+func (ss *ServiceStore) restoreBackup() error {
+	return ss.store.db.Update(func(tx *badger.Txn) error {
+		prefix := ss.dbKey(nil)
+		if err := deletePrefix(tx, prefix); err != nil {
+			return err
+		}
+		if err := copyPrefix(tx, ss.backupPrefix(), prefix); err != nil {
+			return err
+		}
+		return tx.Delete(ss.metaKey(migratingKeyPrefix))
+	})
+}
+
+func (ss *ServiceStore) metaKey(prefix []byte) []byte {
+	return append(append([]byte{}, prefix...), ss.name...)
+}
+
+func (ss *ServiceStore) backupPrefix() []byte {
+	return append(ss.metaKey(backupKeyPrefix), '.')
+}
+
+func getUint64(tx *badger.Txn, key []byte) (uint64, error) {
+	item, err := tx.Get(key)
+	switch err {
+	case nil:
+	case badger.ErrKeyNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+
+	var v uint64
+	err = item.Value(func(val []byte) error {
+		if len(val) != 8 {
+			return fmt.Errorf("persistent: malformed version")
+		}
+		v = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	return v, err
+}
+
+func encodeUint64(v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return b[:]
+}
+
+func deletePrefix(tx *badger.Txn, prefix []byte) error {
+	// NOTE: Do not prefetch values as we are only looking at keys.
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: prefix})
+	var keys [][]byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		keys = append(keys, it.Item().KeyCopy(nil))
+	}
+	it.Close()
+
+	for _, key := range keys {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyPrefix(tx *badger.Txn, srcPrefix, dstPrefix []byte) error {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = srcPrefix
+	it := tx.NewIterator(opts)
+
+	type entry struct {
+		key, value []byte
+	}
+	var entries []entry
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			it.Close()
+			return err
+		}
+		key := append(append([]byte{}, dstPrefix...), item.Key()[len(srcPrefix):]...)
+		entries = append(entries, entry{key, value})
+	}
+	it.Close()
+
+	for _, e := range entries {
+		if err := tx.Set(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
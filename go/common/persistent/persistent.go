@@ -25,6 +25,8 @@ var ErrNotFound = errors.New("persistent: key not found in database")
 type CommonStore struct {
 	db *badger.DB
 	gc *cmnBadger.GCWorker
+
+	logger *logging.Logger
 }
 
 // Close closes the database handle.
@@ -34,11 +36,17 @@ func (cs *CommonStore) Close() {
 }
 
 // GetServiceStore returns a handle to a per-service bucket for the given service.
+//
+// Any pending schema migrations registered for the service via RegisterMigration are run
+// before the handle is returned.
 func (cs *CommonStore) GetServiceStore(name string) (*ServiceStore, error) {
 	ss := &ServiceStore{
 		store: cs,
 		name:  []byte(name),
 	}
+	if err := ss.migrate(); err != nil {
+		return nil, err
+	}
 	return ss, nil
 }
 
@@ -62,6 +70,8 @@ func NewCommonStore(dataDir string) (*CommonStore, error) {
 	cs := &CommonStore{
 		db: db,
 		gc: cmnBadger.NewGCWorker(logger, db),
+
+		logger: logger,
 	}
 
 	return cs, nil
@@ -118,6 +128,23 @@ func (ss *ServiceStore) Delete(key []byte) error {
 	})
 }
 
+// Keys returns all keys in the service store.
+func (ss *ServiceStore) Keys() ([][]byte, error) {
+	var keys [][]byte
+	err := ss.store.db.View(func(tx *badger.Txn) error {
+		prefix := ss.dbKey(nil)
+		// NOTE: Do not prefetch values as we are only looking at keys.
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			keys = append(keys, append([]byte{}, it.Item().Key()[len(prefix):]...))
+		}
+		return nil
+	})
+	return keys, err
+}
+
 func (ss *ServiceStore) dbKey(key []byte) []byte {
 	return bytes.Join([][]byte{ss.name, key}, []byte{'.'})
 }
@@ -1,11 +1,13 @@
 package persistent
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPersistent(t *testing.T) {
@@ -33,3 +35,94 @@ func TestPersistent(t *testing.T) {
 	err = svc.GetCBOR(nonexistentKey, &valOut)
 	assert.Equal(t, ErrNotFound, err, "GetCBOR(nonexistent)")
 }
+
+func TestMigrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oasis-core-unittests")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	common, err := NewCommonStore(dir)
+	require.NoError(t, err, "NewCommonStore")
+	defer common.Close()
+
+	const name = "persistent_test_migrations"
+	svc, err := common.GetServiceStore(name)
+	require.NoError(t, err, "GetServiceStore")
+	version, err := svc.Version()
+	require.NoError(t, err, "Version")
+	require.EqualValues(t, 0, version, "unversioned store should be at version 0")
+
+	type stateV1 struct {
+		Rounds []uint64 `json:"rounds"`
+	}
+	err = svc.PutCBOR([]byte("a"), uint64(1))
+	require.NoError(t, err, "PutCBOR")
+	err = svc.PutCBOR([]byte("b"), uint64(2))
+	require.NoError(t, err, "PutCBOR")
+
+	// Migrate from a plain round number to a structure.
+	RegisterMigration(name, 0, func(ss *ServiceStore) error {
+		keys, kerr := ss.Keys()
+		if kerr != nil {
+			return kerr
+		}
+		for _, key := range keys {
+			var round uint64
+			if kerr = ss.GetCBOR(key, &round); kerr != nil {
+				return kerr
+			}
+			if kerr = ss.PutCBOR(key, &stateV1{Rounds: []uint64{round}}); kerr != nil {
+				return kerr
+			}
+		}
+		return nil
+	})
+	// A failing migration should restore the backup.
+	errMigration := errors.New("migration failed")
+	failingMigration := func(ss *ServiceStore) error {
+		if ferr := ss.Delete([]byte("a")); ferr != nil {
+			return ferr
+		}
+		return errMigration
+	}
+	RegisterMigration(name, 1, failingMigration)
+
+	_, err = common.GetServiceStore(name)
+	require.True(t, errors.Is(err, errMigration), "GetServiceStore should fail on failed migration")
+	version, err = svc.Version()
+	require.NoError(t, err, "Version")
+	require.EqualValues(t, 0, version, "version should not change on failed migration")
+	var round uint64
+	err = svc.GetCBOR([]byte("a"), &round)
+	require.NoError(t, err, "GetCBOR")
+	require.EqualValues(t, 1, round, "store contents should be restored on failed migration")
+
+	// Replace the failing migration.
+	migrationsLock.Lock()
+	registeredMigrations[name][1] = func(ss *ServiceStore) error {
+		return ss.Delete([]byte("b"))
+	}
+	migrationsLock.Unlock()
+
+	svc, err = common.GetServiceStore(name)
+	require.NoError(t, err, "GetServiceStore")
+	version, err = svc.Version()
+	require.NoError(t, err, "Version")
+	require.EqualValues(t, 2, version, "store should be at the latest version")
+	keys, err := svc.Keys()
+	require.NoError(t, err, "Keys")
+	require.Len(t, keys, 1, "migrations should be applied")
+	var state stateV1
+	err = svc.GetCBOR([]byte("a"), &state)
+	require.NoError(t, err, "GetCBOR")
+	require.EqualValues(t, []uint64{1}, state.Rounds, "migrations should be applied")
+
+	// Opening the store again should not re-run migrations.
+	_, err = common.GetServiceStore(name)
+	require.NoError(t, err, "GetServiceStore")
+
+	// Stores with a newer version should be rejected.
+	delete(registeredMigrations[name], 1)
+	_, err = common.GetServiceStore(name)
+	require.True(t, errors.Is(err, ErrUnsupportedVersion), "GetServiceStore should fail for newer versions")
+}
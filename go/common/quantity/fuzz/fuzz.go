@@ -0,0 +1,51 @@
+// +build gofuzz
+
+// Package fuzz provides routines for fuzzing quantity serialization.
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+// Fuzz checks that the binary and CBOR encodings of quantities are
+// stable and match the reference big.Int encoding.
+func Fuzz(data []byte) int {
+	var q quantity.Quantity
+	if err := q.UnmarshalBinary(data); err != nil {
+		return -1
+	}
+
+	ref := new(big.Int).SetBytes(data)
+	if q.ToBigInt().Cmp(ref) != 0 {
+		panic(fmt.Sprintf("decoded value mismatch: %s != %s", q.String(), ref))
+	}
+
+	enc, err := q.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(enc, ref.Bytes()) {
+		panic(fmt.Sprintf("encoding mismatch: %X != %X", enc, ref.Bytes()))
+	}
+
+	var dec quantity.Quantity
+	if err = cbor.Unmarshal(cbor.Marshal(&q), &dec); err != nil {
+		panic(err)
+	}
+	if dec.Cmp(&q) != 0 {
+		panic(fmt.Sprintf("CBOR round trip mismatch: %s != %s", dec.String(), q.String()))
+	}
+
+	if sum, err := quantity.SafeAdd(&q, &dec); err == nil {
+		if diff, err := quantity.SafeSub(sum, &dec); err != nil || diff.Cmp(&q) != 0 {
+			panic("SafeAdd/SafeSub mismatch")
+		}
+	}
+
+	return 1
+}
@@ -4,8 +4,13 @@ import (
 	"encoding"
 	"errors"
 	"math/big"
+	"math/bits"
 )
 
+// SafeMaxBitLen is the maximum bit length of a quantity that can be produced
+// by the safe arithmetic operations.
+const SafeMaxBitLen = 256
+
 var (
 	// ErrInvalidQuantity is the error returned on malformed arguments.
 	ErrInvalidQuantity = errors.New("invalid quantity")
@@ -18,6 +23,14 @@ var (
 	// due to a missing account.
 	ErrInvalidAccount = errors.New("invalid account")
 
+	// ErrOverflow is the error returned when a safe arithmetic operation
+	// would produce a quantity exceeding SafeMaxBitLen bits.
+	ErrOverflow = errors.New("quantity overflow")
+
+	// ErrUnderflow is the error returned when a safe arithmetic operation
+	// would produce a negative quantity.
+	ErrUnderflow = errors.New("quantity underflow")
+
 	_ encoding.BinaryMarshaler   = (*Quantity)(nil)
 	_ encoding.BinaryUnmarshaler = (*Quantity)(nil)
 
@@ -37,15 +50,41 @@ func (q *Quantity) Clone() *Quantity {
 }
 
 // MarshalBinary encodes a Quantity into binary form.
+//
+// The encoding is the minimal big-endian representation of the value, with
+// zero being encoded as an empty byte slice.
 func (q *Quantity) MarshalBinary() ([]byte, error) {
-	return append([]byte{}, q.inner.Bytes()...), nil
+	// Fast path for zero and small values which avoids the intermediate
+	// allocations done by big.Int. The result is identical.
+	if q.inner.Sign() == 0 {
+		return []byte{}, nil
+	}
+	if q.inner.IsUint64() {
+		v := q.inner.Uint64()
+		data := make([]byte, (bits.Len64(v)+7)/8)
+		for i := len(data) - 1; i >= 0; i-- {
+			data[i] = byte(v)
+			v >>= 8
+		}
+		return data, nil
+	}
+
+	return q.inner.Bytes(), nil
 }
 
 // UnmarshalBinary decodes a byte slice into a Quantity.
 func (q *Quantity) UnmarshalBinary(data []byte) error {
-	var tmp big.Int
-	tmp.SetBytes(data)
-	q.inner.Set(&tmp)
+	// Fast path for small values.
+	if len(data) <= 8 {
+		var v uint64
+		for _, b := range data {
+			v = v<<8 | uint64(b)
+		}
+		q.inner.SetUint64(v)
+		return nil
+	}
+
+	q.inner.SetBytes(data)
 
 	if !q.IsValid() {
 		return ErrInvalidQuantity
@@ -174,6 +213,38 @@ func (q *Quantity) Quo(n *Quantity) error {
 	return nil
 }
 
+// SafeAdd returns a new quantity holding a + b, returning ErrOverflow if
+// the result would exceed SafeMaxBitLen bits.
+func SafeAdd(a, b *Quantity) (*Quantity, error) {
+	if a == nil || b == nil || !a.IsValid() || !b.IsValid() {
+		return nil, ErrInvalidQuantity
+	}
+
+	var result Quantity
+	result.inner.Add(&a.inner, &b.inner)
+	if result.inner.BitLen() > SafeMaxBitLen {
+		return nil, ErrOverflow
+	}
+
+	return &result, nil
+}
+
+// SafeSub returns a new quantity holding a - b, returning ErrUnderflow if
+// b > a.
+func SafeSub(a, b *Quantity) (*Quantity, error) {
+	if a == nil || b == nil || !a.IsValid() || !b.IsValid() {
+		return nil, ErrInvalidQuantity
+	}
+	if a.inner.Cmp(&b.inner) < 0 {
+		return nil, ErrUnderflow
+	}
+
+	var result Quantity
+	result.inner.Sub(&a.inner, &b.inner)
+
+	return &result, nil
+}
+
 // Cmp returns -1 if q < n, 0 if q == n, and 1 if q > n.
 func (q *Quantity) Cmp(n *Quantity) int {
 	return q.inner.Cmp(&n.inner)
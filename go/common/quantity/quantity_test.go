@@ -2,6 +2,7 @@ package quantity
 
 import (
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -90,6 +91,48 @@ func TestQuantityBinaryRoundTrip(t *testing.T) {
 	require.Zero(q.Cmp(&nq), "Round trip matches")
 }
 
+func TestQuantityBinaryEncoding(t *testing.T) {
+	require := require.New(t)
+
+	requireStable := func(n *big.Int) {
+		var q Quantity
+		err := q.FromBigInt(n)
+		require.NoError(err, "FromBigInt")
+
+		b, err := q.MarshalBinary()
+		require.NoError(err, "MarshalBinary")
+		require.NotNil(b, "MarshalBinary should never return nil")
+		require.EqualValues(n.Bytes(), b, "MarshalBinary should match the big.Int encoding (%s)", n)
+
+		var nq Quantity
+		err = nq.UnmarshalBinary(b)
+		require.NoError(err, "UnmarshalBinary")
+		require.Zero(q.Cmp(&nq), "Round trip matches (%s)", n)
+	}
+
+	requireStable(big.NewInt(0))
+	requireStable(new(big.Int).SetUint64(0xFFFFFFFFFFFFFFFF))
+	requireStable(new(big.Int).Lsh(big.NewInt(1), 64))
+	for i := uint(0); i < 128; i++ {
+		requireStable(new(big.Int).Lsh(big.NewInt(1), i))
+	}
+
+	rng := rand.New(rand.NewSource(42)) // nolint: gosec
+	for i := 0; i < 1000; i++ {
+		n := new(big.Int).Rand(rng, new(big.Int).Lsh(big.NewInt(1), uint(rng.Intn(200))+1))
+		requireStable(n)
+	}
+
+	// Leading zeros should be accepted on decode.
+	var q Quantity
+	err := q.UnmarshalBinary([]byte{0x00, 0x00, 0x01})
+	require.NoError(err, "UnmarshalBinary with leading zeros")
+	require.True(q.eqInt(1), "UnmarshalBinary with leading zeros value")
+	err = q.UnmarshalBinary([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02})
+	require.NoError(err, "UnmarshalBinary with leading zeros (large)")
+	require.True(q.eqInt(2), "UnmarshalBinary with leading zeros (large) value")
+}
+
 func TestQuantityAdd(t *testing.T) {
 	require := require.New(t)
 
@@ -182,6 +225,51 @@ func TestQuantityQuo(t *testing.T) {
 	require.True(q.eqInt(2), "Quo(50) value")
 }
 
+func TestSafeAdd(t *testing.T) {
+	require := require.New(t)
+
+	_, err := SafeAdd(nil, fromInt(1))
+	require.Equal(ErrInvalidQuantity, err, "SafeAdd(nil, 1)")
+	_, err = SafeAdd(fromInt(1), fromInt(-1))
+	require.Equal(ErrInvalidQuantity, err, "SafeAdd(1, -1)")
+
+	a, b := fromInt(100), fromInt(200)
+	r, err := SafeAdd(a, b)
+	require.NoError(err, "SafeAdd")
+	require.True(r.eqInt(300), "SafeAdd(100, 200) value")
+	require.True(a.eqInt(100) && b.eqInt(200), "SafeAdd - arguments unchanged")
+
+	var max Quantity
+	max.inner.Lsh(big.NewInt(1), SafeMaxBitLen)
+	max.inner.Sub(&max.inner, big.NewInt(1))
+	_, err = SafeAdd(&max, fromInt(0))
+	require.NoError(err, "SafeAdd(max, 0)")
+	_, err = SafeAdd(&max, fromInt(1))
+	require.Equal(ErrOverflow, err, "SafeAdd(max, 1)")
+}
+
+func TestSafeSub(t *testing.T) {
+	require := require.New(t)
+
+	_, err := SafeSub(fromInt(1), nil)
+	require.Equal(ErrInvalidQuantity, err, "SafeSub(1, nil)")
+	_, err = SafeSub(fromInt(-1), fromInt(1))
+	require.Equal(ErrInvalidQuantity, err, "SafeSub(-1, 1)")
+
+	a, b := fromInt(100), fromInt(23)
+	r, err := SafeSub(a, b)
+	require.NoError(err, "SafeSub")
+	require.True(r.eqInt(77), "SafeSub(100, 23) value")
+	require.True(a.eqInt(100) && b.eqInt(23), "SafeSub - arguments unchanged")
+
+	r, err = SafeSub(a, a)
+	require.NoError(err, "SafeSub(100, 100)")
+	require.True(r.IsZero(), "SafeSub(100, 100) value")
+
+	_, err = SafeSub(b, a)
+	require.Equal(ErrUnderflow, err, "SafeSub(23, 100)")
+}
+
 func TestQuantityCmp(t *testing.T) {
 	require := require.New(t)
 
@@ -246,3 +334,103 @@ func TestMoveUpTo(t *testing.T) {
 	require.True(src.eqInt(0), "MoveUpTo, oversized - src value")
 	require.True(moved.eqInt(225), "MoveUpTo, oversized - moved")
 }
+
+func benchmarkValues() []*Quantity {
+	large := NewQuantity()
+	large.inner.Lsh(big.NewInt(1), 100)
+
+	return []*Quantity{
+		NewQuantity(),
+		NewFromUint64(1_000),
+		NewFromUint64(0xFFFFFFFFFFFFFFFF),
+		large,
+	}
+}
+
+func BenchmarkQuantityMarshalBinary(b *testing.B) {
+	values := benchmarkValues()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = values[i%len(values)].MarshalBinary()
+	}
+}
+
+func BenchmarkQuantityUnmarshalBinary(b *testing.B) {
+	values := benchmarkValues()
+	encoded := make([][]byte, 0, len(values))
+	for _, v := range values {
+		data, _ := v.MarshalBinary()
+		encoded = append(encoded, data)
+	}
+
+	var q Quantity
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = q.UnmarshalBinary(encoded[i%len(encoded)])
+	}
+}
+
+func BenchmarkQuantityAdd(b *testing.B) {
+	q, n := NewQuantity(), NewFromUint64(1_000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = q.Add(n)
+	}
+}
+
+func BenchmarkQuantitySub(b *testing.B) {
+	q, n := NewFromUint64(0xFFFFFFFFFFFFFFFF), NewFromUint64(1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = q.Sub(n)
+	}
+}
+
+func BenchmarkQuantityMul(b *testing.B) {
+	n := NewFromUint64(1_000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := NewFromUint64(1_000_000)
+		_ = q.Mul(n)
+	}
+}
+
+func BenchmarkQuantityQuo(b *testing.B) {
+	n := NewFromUint64(1_000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := NewFromUint64(1_000_000_000)
+		_ = q.Quo(n)
+	}
+}
+
+func BenchmarkSafeAdd(b *testing.B) {
+	q, n := NewFromUint64(1_000_000), NewFromUint64(1_000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = SafeAdd(q, n)
+	}
+}
+
+func BenchmarkSafeSub(b *testing.B) {
+	q, n := NewFromUint64(1_000_000), NewFromUint64(1_000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = SafeSub(q, n)
+	}
+}
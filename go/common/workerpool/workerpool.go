@@ -2,6 +2,8 @@
 package workerpool
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -10,9 +12,18 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
+var (
+	// ErrPoolStopped is the error returned when submitting a job to a stopped pool.
+	ErrPoolStopped = errors.New("workerpool: pool is stopped")
+	// ErrPoolBusy is the error returned by TrySubmit when there is no idle worker available.
+	ErrPoolBusy = errors.New("workerpool: no idle worker available")
+)
+
 type jobDescriptor struct {
 	terminate  bool
+	ctx        context.Context
 	job        func()
+	ctxJob     func(context.Context)
 	completeCh chan struct{}
 }
 
@@ -21,6 +32,9 @@ type jobDescriptor struct {
 // Notes:
 //  * The pool is always constructed with one active worker goroutine.
 //  * Once closed, it can not be used anymore.
+//  * Jobs that are still queued when the pool is stopped are drained: jobs submitted via
+//    SubmitContext or TrySubmit are invoked with an already canceled context, jobs submitted via
+//    Submit are discarded. In both cases their completion channels are closed.
 type Pool struct { // nolint: maligned
 	lock        sync.Mutex
 	workerGroup sync.WaitGroup
@@ -28,6 +42,8 @@ type Pool struct { // nolint: maligned
 	name string
 
 	currentCount uint
+	pendingCount uint
+	stopped      bool
 
 	jobCh    *channels.InfiniteChannel
 	stopCh   chan struct{}
@@ -70,12 +86,16 @@ func (p *Pool) Resize(newCount uint) {
 
 // Stop causes all worker goroutines to shut down.
 //
+// Jobs that are currently running have their contexts canceled and queued jobs are drained. Use
+// Quit to wait for the pool to finish shutting down.
+//
 // The pool must not be used for any further tasks after calling this method.
 func (p *Pool) Stop() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
 	p.currentCount = 0
+	p.stopped = true
 	p.stopOnce.Do(func() {
 		close(p.stopCh)
 	})
@@ -88,19 +108,140 @@ func (p *Pool) Quit() <-chan struct{} {
 
 // Submit adds a task to the pool's queue and returns a channel that will be closed
 // once the task is complete.
+//
+// In case the pool has been stopped, the task is discarded and the returned channel
+// is already closed.
 func (p *Pool) Submit(job func()) <-chan struct{} {
 	desc := &jobDescriptor{
 		job:        job,
 		completeCh: make(chan struct{}),
 	}
 
-	p.jobCh.In() <- desc
+	if err := p.enqueue(desc, false); err != nil {
+		close(desc.completeCh)
+	}
 	return desc.completeCh
 }
 
+// SubmitContext adds a context-aware task to the pool's queue and returns a channel that will be
+// closed once the task is complete.
+//
+// The context passed to the task is canceled when either the given context is canceled or the
+// pool is stopped. Once accepted, the task is guaranteed to be invoked exactly once, even if the
+// pool is stopped before a worker picks it up.
+//
+// Returns ErrPoolStopped in case the pool has been stopped or the context error in case the
+// given context is already done.
+func (p *Pool) SubmitContext(ctx context.Context, job func(context.Context)) (<-chan struct{}, error) {
+	return p.submitContext(ctx, job, false)
+}
+
+// TrySubmit is like SubmitContext, but only accepts the task in case there is an idle worker
+// that can start executing it immediately. Otherwise ErrPoolBusy is returned.
+func (p *Pool) TrySubmit(ctx context.Context, job func(context.Context)) (<-chan struct{}, error) {
+	return p.submitContext(ctx, job, true)
+}
+
+func (p *Pool) submitContext(ctx context.Context, job func(context.Context), try bool) (<-chan struct{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	desc := &jobDescriptor{
+		ctx:        ctx,
+		ctxJob:     job,
+		completeCh: make(chan struct{}),
+	}
+	if err := p.enqueue(desc, try); err != nil {
+		return nil, err
+	}
+	return desc.completeCh, nil
+}
+
+func (p *Pool) enqueue(desc *jobDescriptor, try bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stopped {
+		return ErrPoolStopped
+	}
+	if try && p.pendingCount >= p.currentCount {
+		return ErrPoolBusy
+	}
+
+	p.pendingCount++
+	p.jobCh.In() <- desc
+	return nil
+}
+
+func (p *Pool) runJob(desc *jobDescriptor) {
+	defer func() {
+		p.lock.Lock()
+		p.pendingCount--
+		p.lock.Unlock()
+
+		close(desc.completeCh)
+	}()
+
+	if desc.ctxJob == nil {
+		desc.job()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(desc.ctx)
+	defer cancel()
+	select {
+	case <-p.stopCh:
+		// Pool is already stopped, make sure the job observes this.
+		cancel()
+	default:
+	}
+	go func() {
+		select {
+		case <-p.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	desc.ctxJob(ctx)
+}
+
+func (p *Pool) drainJob(desc *jobDescriptor) {
+	if desc.ctxJob == nil {
+		// Legacy jobs are not context-aware, discard them.
+		p.lock.Lock()
+		p.pendingCount--
+		p.lock.Unlock()
+
+		close(desc.completeCh)
+		return
+	}
+
+	// Context-aware jobs are always invoked so they can release any resources, the pool is
+	// stopped so their context will be canceled.
+	p.runJob(desc)
+}
+
 func (p *Pool) lifetimeManager() {
 	p.workerGroup.Wait()
 	p.jobCh.Close()
+
+	var drained int
+	for item := range p.jobCh.Out() {
+		job := item.(*jobDescriptor)
+		if job.terminate {
+			continue
+		}
+		p.drainJob(job)
+		drained++
+	}
+	if drained > 0 {
+		p.logger.Debug("drained queued jobs",
+			"num_jobs", drained,
+		)
+	}
+
 	close(p.quitCh)
 }
 
@@ -119,8 +260,7 @@ func (p *Pool) worker() {
 			if job.terminate {
 				return
 			}
-			job.job()
-			close(job.completeCh)
+			p.runJob(job)
 		}
 	}
 }
@@ -0,0 +1,131 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const recvTimeout = 5 * time.Second
+
+func TestSubmitContext(t *testing.T) {
+	require := require.New(t)
+
+	pool := New("test")
+	defer pool.Stop()
+
+	var ran bool
+	doneCh, err := pool.SubmitContext(context.Background(), func(ctx context.Context) {
+		ran = true
+	})
+	require.NoError(err, "SubmitContext")
+	select {
+	case <-doneCh:
+	case <-time.After(recvTimeout):
+		require.Fail("job should complete")
+	}
+	require.True(ran, "job should run")
+
+	// Jobs should observe cancellation of the submit context.
+	ctx, cancel := context.WithCancel(context.Background())
+	startedCh := make(chan struct{})
+	doneCh, err = pool.SubmitContext(ctx, func(ctx context.Context) {
+		close(startedCh)
+		<-ctx.Done()
+	})
+	require.NoError(err, "SubmitContext")
+	<-startedCh
+	cancel()
+	select {
+	case <-doneCh:
+	case <-time.After(recvTimeout):
+		require.Fail("job should complete after context is canceled")
+	}
+
+	// Submitting with an already canceled context should fail.
+	_, err = pool.SubmitContext(ctx, func(ctx context.Context) {})
+	require.True(errors.Is(err, context.Canceled), "SubmitContext should fail with canceled context")
+}
+
+func TestTrySubmit(t *testing.T) {
+	require := require.New(t)
+
+	pool := New("test")
+	defer pool.Stop()
+
+	releaseCh := make(chan struct{})
+	doneCh, err := pool.TrySubmit(context.Background(), func(ctx context.Context) {
+		<-releaseCh
+	})
+	require.NoError(err, "TrySubmit")
+
+	_, err = pool.TrySubmit(context.Background(), func(ctx context.Context) {})
+	require.True(errors.Is(err, ErrPoolBusy), "TrySubmit should fail without an idle worker")
+
+	close(releaseCh)
+	select {
+	case <-doneCh:
+	case <-time.After(recvTimeout):
+		require.Fail("job should complete")
+	}
+
+	_, err = pool.TrySubmit(context.Background(), func(ctx context.Context) {})
+	require.NoError(err, "TrySubmit should succeed with an idle worker")
+}
+
+func TestStopDrain(t *testing.T) {
+	require := require.New(t)
+
+	pool := New("test")
+
+	// Block the only worker until the pool is stopped.
+	startedCh := make(chan struct{})
+	runningCh, err := pool.SubmitContext(context.Background(), func(ctx context.Context) {
+		close(startedCh)
+		<-ctx.Done()
+	})
+	require.NoError(err, "SubmitContext")
+	<-startedCh
+
+	// Queue some jobs that can only complete once the pool is stopped.
+	var canceled bool
+	queuedCh, err := pool.SubmitContext(context.Background(), func(ctx context.Context) {
+		canceled = ctx.Err() != nil
+	})
+	require.NoError(err, "SubmitContext")
+	var legacyRan bool
+	legacyCh := pool.Submit(func() {
+		legacyRan = true
+	})
+
+	pool.Stop()
+	select {
+	case <-pool.Quit():
+	case <-time.After(recvTimeout):
+		require.Fail("pool should stop promptly")
+	}
+
+	for _, ch := range []<-chan struct{}{runningCh, queuedCh, legacyCh} {
+		select {
+		case <-ch:
+		default:
+			require.Fail("all jobs should be completed after the pool stops")
+		}
+	}
+	require.True(canceled, "queued context-aware jobs should be invoked with a canceled context")
+	require.False(legacyRan, "queued legacy jobs should be discarded")
+
+	// Submitting to a stopped pool should not block or panic.
+	_, err = pool.SubmitContext(context.Background(), func(ctx context.Context) {})
+	require.True(errors.Is(err, ErrPoolStopped), "SubmitContext should fail on a stopped pool")
+	_, err = pool.TrySubmit(context.Background(), func(ctx context.Context) {})
+	require.True(errors.Is(err, ErrPoolStopped), "TrySubmit should fail on a stopped pool")
+	select {
+	case <-pool.Submit(func() {}):
+	default:
+		require.Fail("Submit on a stopped pool should return a closed channel")
+	}
+}
@@ -52,6 +52,10 @@ var (
 
 	// ErrDuplicateTx is the error returned when the transaction already exists in the mempool.
 	ErrDuplicateTx = errors.New(moduleName, 5, "consensus: duplicate transaction")
+
+	// ErrMempoolClassFull is the error returned when the mempool capacity available to the
+	// transaction's priority class has been exhausted.
+	ErrMempoolClassFull = errors.New(moduleName, 9, "consensus: mempool full for transaction priority class")
 )
 
 // FeatureMask is the consensus backend feature bitmask.
@@ -100,6 +104,10 @@ type ClientBackend interface {
 	// EstimateGas calculates the amount of gas required to execute the given transaction.
 	EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error)
 
+	// SimulateTx simulates execution of the given transaction against the latest state without
+	// submitting it. Any state changes made by the transaction are discarded.
+	SimulateTx(ctx context.Context, req *SimulateTxRequest) (*SimulateTxResponse, error)
+
 	// WaitEpoch waits for consensus to reach an epoch.
 	//
 	// Note that an epoch is considered reached even if any epoch greater than
@@ -132,6 +140,15 @@ type ClientBackend interface {
 	// blocks as they are being finalized.
 	WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error)
 
+	// WatchBlocksFrom returns a channel that produces a stream of consensus blocks starting at the
+	// given height. Already finalized blocks are replayed first, after which the stream continues
+	// with blocks as they are being finalized.
+	//
+	// Each block is produced exactly once and in order of increasing height. Blocks are only
+	// retrieved as the consumer reads them so a slow consumer falls behind without missing any
+	// blocks. In case the given height is no longer available, ErrVersionNotFound is returned.
+	WatchBlocksFrom(ctx context.Context, height int64) (<-chan *Block, pubsub.ClosableSubscription, error)
+
 	// GetGenesisDocument returns the original genesis document.
 	GetGenesisDocument(ctx context.Context) (*genesis.Document, error)
 
@@ -189,6 +206,22 @@ type Status struct { // nolint: maligned
 
 	// IsValidator returns whether the current node is part of the validator set.
 	IsValidator bool `json:"is_validator"`
+
+	// StateSync is the state sync status. It is only set in case the node has started restoring
+	// its state from a checkpoint obtained via state sync.
+	StateSync *StateSyncStatus `json:"state_sync,omitempty"`
+}
+
+// StateSyncStatus is the state sync progress overview.
+type StateSyncStatus struct {
+	// Root is the consensus state root being restored.
+	Root mkvsNode.Root `json:"root"`
+	// TotalChunks is the total number of chunks in the checkpoint being restored.
+	TotalChunks uint64 `json:"total_chunks"`
+	// RestoredChunks is the number of chunks that have been restored so far.
+	RestoredChunks uint64 `json:"restored_chunks"`
+	// Done is true iff the state has been fully restored.
+	Done bool `json:"done"`
 }
 
 // Backend is an interface that a consensus backend must provide.
@@ -208,6 +241,9 @@ type Backend interface {
 
 	// GetAddresses returns the consensus backend addresses.
 	GetAddresses() ([]node.ConsensusAddress, error)
+
+	// PeerManager returns the consensus layer peer manager.
+	PeerManager() PeerManager
 }
 
 // ServicesBackend is an interface for consensus backends which indicate support for
@@ -260,6 +296,20 @@ type EstimateGasRequest struct {
 	Transaction *transaction.Transaction `json:"transaction"`
 }
 
+// SimulateTxRequest is a SimulateTx request.
+type SimulateTxRequest struct {
+	Signer      signature.PublicKey      `json:"signer"`
+	Transaction *transaction.Transaction `json:"transaction"`
+}
+
+// SimulateTxResponse is a SimulateTx response.
+type SimulateTxResponse struct {
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed transaction.Gas `json:"gas_used"`
+	// Result is the result of executing the transaction.
+	Result *results.Result `json:"result"`
+}
+
 // GetSignerNonceRequest is a GetSignerNonce request.
 type GetSignerNonceRequest struct {
 	AccountAddress staking.Address `json:"account_address"`
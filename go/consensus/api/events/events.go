@@ -0,0 +1,111 @@
+// Package events defines the ordering metadata attached to decoded consensus
+// service events.
+//
+// Events emitted while processing a block are totally ordered as follows:
+//
+//  1. Events emitted during BeginBlock (for the first block this includes any
+//     events emitted during InitChain, which precede all other BeginBlock
+//     events).
+//  2. Events emitted during DeliverTx, in the order in which transactions are
+//     included in the block.
+//  3. Events emitted during EndBlock.
+//
+// Within each of the above phases, applications are invoked in lexicographic
+// order of their names and events are ordered exactly as they were emitted.
+// During DeliverTx the application handling the transaction is invoked first,
+// followed by post-tx hooks of all other applications in lexicographic order.
+package events
+
+import "fmt"
+
+// Phase is the block processing phase during which an event was emitted.
+type Phase uint8
+
+const (
+	// PhaseInvalid is an invalid phase.
+	PhaseInvalid Phase = 0
+	// PhaseBeginBlock is the BeginBlock phase (including InitChain).
+	PhaseBeginBlock Phase = 1
+	// PhaseDeliverTx is the DeliverTx phase.
+	PhaseDeliverTx Phase = 2
+	// PhaseEndBlock is the EndBlock phase.
+	PhaseEndBlock Phase = 3
+)
+
+// String returns a string representation of the phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseBeginBlock:
+		return "begin_block"
+	case PhaseDeliverTx:
+		return "deliver_tx"
+	case PhaseEndBlock:
+		return "end_block"
+	default:
+		return fmt.Sprintf("[unknown phase: %d]", uint8(p))
+	}
+}
+
+// Index is the position of an event within the block that emitted it.
+//
+// Multiple decoded events may share the same index when they were decoded from
+// the same underlying consensus event. In this case their relative order is the
+// order in which they are returned.
+type Index struct {
+	// Phase is the block processing phase during which the event was emitted.
+	Phase Phase `json:"phase"`
+	// TxIndex is the index of the transaction within the block. It is only
+	// meaningful for events emitted in the DeliverTx phase.
+	TxIndex uint32 `json:"tx_index,omitempty"`
+	// Index is the index of the event within its phase (or within its
+	// transaction for events emitted in the DeliverTx phase).
+	Index uint32 `json:"index"`
+}
+
+// NewBlockIndex creates a new index for an event emitted during BeginBlock or
+// EndBlock.
+func NewBlockIndex(phase Phase, index uint32) Index {
+	return Index{Phase: phase, Index: index}
+}
+
+// NewTxIndex creates a new index for an event emitted during DeliverTx.
+func NewTxIndex(txIndex, index uint32) Index {
+	return Index{Phase: PhaseDeliverTx, TxIndex: txIndex, Index: index}
+}
+
+// IsValid checks whether the index refers to a valid phase.
+func (idx Index) IsValid() bool {
+	switch idx.Phase {
+	case PhaseBeginBlock, PhaseDeliverTx, PhaseEndBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// Offset returns the index of the event that is n positions after this one
+// within the same phase (and transaction).
+func (idx Index) Offset(n int) Index {
+	idx.Index += uint32(n)
+	return idx
+}
+
+// Less returns true iff the event at this index was emitted before the event at
+// the other index within the same block.
+func (idx Index) Less(other Index) bool {
+	if idx.Phase != other.Phase {
+		return idx.Phase < other.Phase
+	}
+	if idx.Phase == PhaseDeliverTx && idx.TxIndex != other.TxIndex {
+		return idx.TxIndex < other.TxIndex
+	}
+	return idx.Index < other.Index
+}
+
+// String returns a string representation of the index.
+func (idx Index) String() string {
+	if idx.Phase == PhaseDeliverTx {
+		return fmt.Sprintf("%s/%d/%d", idx.Phase, idx.TxIndex, idx.Index)
+	}
+	return fmt.Sprintf("%s/%d", idx.Phase, idx.Index)
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexOrdering(t *testing.T) {
+	require := require.New(t)
+
+	ordered := []Index{
+		NewBlockIndex(PhaseBeginBlock, 0),
+		NewBlockIndex(PhaseBeginBlock, 1),
+		NewTxIndex(0, 0),
+		NewTxIndex(0, 5),
+		NewTxIndex(1, 0),
+		NewTxIndex(2, 3),
+		NewBlockIndex(PhaseEndBlock, 0),
+		NewBlockIndex(PhaseEndBlock, 2),
+	}
+	for i := range ordered {
+		require.True(ordered[i].IsValid(), "index should be valid")
+		require.False(ordered[i].Less(ordered[i]), "index should not be less than itself")
+		for j := i + 1; j < len(ordered); j++ {
+			require.True(ordered[i].Less(ordered[j]), "%s should be less than %s", ordered[i], ordered[j])
+			require.False(ordered[j].Less(ordered[i]), "%s should not be less than %s", ordered[j], ordered[i])
+		}
+	}
+
+	shuffled := []Index{ordered[5], ordered[7], ordered[0], ordered[3], ordered[6], ordered[1], ordered[4], ordered[2]}
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i].Less(shuffled[j]) })
+	require.EqualValues(ordered, shuffled, "sorting should restore the emission order")
+
+	require.EqualValues(NewTxIndex(3, 7), NewTxIndex(3, 4).Offset(3), "offset should only move the event index")
+	require.False(Index{}.IsValid(), "zero index should be invalid")
+}
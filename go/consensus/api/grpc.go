@@ -25,6 +25,8 @@ var (
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodEstimateGas is the EstimateGas method.
 	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
+	// methodSimulateTx is the SimulateTx method.
+	methodSimulateTx = serviceName.NewMethod("SimulateTx", &SimulateTxRequest{})
 	// methodGetSignerNonce is a GetSignerNonce method.
 	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
 	// methodGetEpoch is the GetEpoch method.
@@ -46,6 +48,8 @@ var (
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", nil)
+	// methodWatchBlocksFrom is the WatchBlocksFrom method.
+	methodWatchBlocksFrom = serviceName.NewMethod("WatchBlocksFrom", int64(0))
 
 	// methodGetLightBlock is the GetLightBlock method.
 	methodGetLightBlock = lightServiceName.NewMethod("GetLightBlock", int64(0))
@@ -79,6 +83,10 @@ var (
 				MethodName: methodEstimateGas.ShortName(),
 				Handler:    handlerEstimateGas,
 			},
+			{
+				MethodName: methodSimulateTx.ShortName(),
+				Handler:    handlerSimulateTx,
+			},
 			{
 				MethodName: methodGetSignerNonce.ShortName(),
 				Handler:    handlerGetSignerNonce,
@@ -122,6 +130,11 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchBlocksFrom.ShortName(),
+				Handler:       handlerWatchBlocksFrom,
+				ServerStreams: true,
+			},
 		},
 	}
 
@@ -231,6 +244,29 @@ func handlerEstimateGas( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSimulateTx( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(SimulateTxRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientBackend).SimulateTx(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSimulateTx.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientBackend).SimulateTx(ctx, req.(*SimulateTxRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerGetSignerNonce( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -454,6 +490,35 @@ func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchBlocksFrom(srv interface{}, stream grpc.ServerStream) error {
+	var height int64
+	if err := stream.RecvMsg(&height); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(ClientBackend).WatchBlocksFrom(ctx, height)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case blk, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(blk); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func handlerGetLightBlock( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -720,6 +785,14 @@ func (c *consensusClient) EstimateGas(ctx context.Context, req *EstimateGasReque
 	return gas, nil
 }
 
+func (c *consensusClient) SimulateTx(ctx context.Context, req *SimulateTxRequest) (*SimulateTxResponse, error) {
+	var rsp SimulateTxResponse
+	if err := c.conn.Invoke(ctx, methodSimulateTx.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
 	var nonce uint64
 	if err := c.conn.Invoke(ctx, methodGetSignerNonce.FullName(), req, &nonce); err != nil {
@@ -823,6 +896,41 @@ func (c *consensusClient) WatchBlocks(ctx context.Context) (<-chan *Block, pubsu
 	return ch, sub, nil
 }
 
+func (c *consensusClient) WatchBlocksFrom(ctx context.Context, height int64) (<-chan *Block, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchBlocksFrom.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(height); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *Block)
+	go func() {
+		defer close(ch)
+
+		for {
+			var blk Block
+			if serr := stream.RecvMsg(&blk); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // NewConsensusClient creates a new gRPC consensus client service.
 func NewConsensusClient(c *grpc.ClientConn) ClientBackend {
 	return &consensusClient{
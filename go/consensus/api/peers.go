@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+)
+
+var (
+	// ErrPeerNotFound is the error returned when a peer is not known to the peer manager.
+	ErrPeerNotFound = errors.New(moduleName, 6, "consensus: peer not found")
+
+	// ErrPeerBanned is the error used when disconnecting from a banned peer.
+	ErrPeerBanned = errors.New(moduleName, 7, "consensus: peer banned")
+
+	// ErrInvalidPeer is the error returned when a peer identifier or address is malformed.
+	ErrInvalidPeer = errors.New(moduleName, 8, "consensus: invalid peer")
+)
+
+// PeerManager is the consensus layer peer manager interface.
+type PeerManager interface {
+	// GetPeers returns the status of all peers known to the peer manager, including currently
+	// connected, persistent and banned peers.
+	GetPeers(ctx context.Context) ([]*PeerStatus, error)
+
+	// BanPeer disconnects from the given peer (in case it is connected) and refuses any further
+	// connections with it until the ban expires or the peer is unbanned. Banned peers are also
+	// removed from the set of persistent peers.
+	BanPeer(ctx context.Context, req *BanPeerRequest) error
+
+	// UnbanPeer removes the ban for the given peer.
+	UnbanPeer(ctx context.Context, id string) error
+
+	// UpdatePersistentPeers updates the set of persistent peers the node maintains connections
+	// with and returns the updated set.
+	//
+	// Existing connections with peers removed from the set are not closed, but they will not be
+	// redialed after being disconnected.
+	UpdatePersistentPeers(ctx context.Context, req *PersistentPeersUpdate) ([]string, error)
+}
+
+// PeerStatus is the status of a consensus layer peer.
+type PeerStatus struct {
+	// ID is the peer identifier.
+	ID string `json:"id"`
+	// Address is the address of the peer, if known.
+	Address string `json:"address,omitempty"`
+	// Moniker is the peer's self-reported name, if known.
+	Moniker string `json:"moniker,omitempty"`
+
+	// Connected specifies whether the peer is currently connected.
+	Connected bool `json:"connected"`
+	// Outbound specifies whether the connection has been initiated by the local node.
+	Outbound bool `json:"outbound,omitempty"`
+	// Persistent specifies whether the peer is a persistent peer.
+	Persistent bool `json:"persistent,omitempty"`
+	// ConnectedSince is the time the current connection has been established.
+	ConnectedSince *time.Time `json:"connected_since,omitempty"`
+
+	// Score is the peer reputation score between 0 (worst) and 100 (best). The score decreases each
+	// time the peer is disconnected due to an error and slowly recovers over time.
+	Score uint64 `json:"score"`
+	// ErrorDisconnects is the number of times the peer has been disconnected due to an error.
+	ErrorDisconnects uint64 `json:"error_disconnects,omitempty"`
+	// LastError is the error that caused the last error disconnect.
+	LastError string `json:"last_error,omitempty"`
+
+	// Ban is the ban placed on the peer, if any.
+	Ban *PeerBan `json:"ban,omitempty"`
+}
+
+// PeerBan is a ban placed on a peer.
+type PeerBan struct {
+	// Reason is the reason for the ban.
+	Reason string `json:"reason,omitempty"`
+	// Since is the time the ban was placed.
+	Since time.Time `json:"since"`
+	// Until is the time the ban expires. In case it is the zero timestamp, the ban does not
+	// expire.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// BanPeerRequest is a BanPeer request.
+type BanPeerRequest struct {
+	// ID is the identifier of the peer to ban.
+	ID string `json:"id"`
+	// Reason is an optional reason for the ban.
+	Reason string `json:"reason,omitempty"`
+	// Duration is the duration of the ban. Zero means that the ban does not expire.
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// PersistentPeersUpdate is an update of the set of persistent peers.
+type PersistentPeersUpdate struct {
+	// Add are the addresses of the peers to add in the form ID@host:port.
+	Add []string `json:"add,omitempty"`
+	// Remove are the identifiers of the peers to remove.
+	Remove []string `json:"remove,omitempty"`
+}
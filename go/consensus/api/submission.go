@@ -20,6 +20,8 @@ const (
 	maxSubmissionRetryInterval    = 10 * time.Second
 )
 
+var _ staking.TransactionSubmitter = (SubmissionManager)(nil)
+
 // PriceDiscovery is the consensus fee price discovery interface.
 type PriceDiscovery interface {
 	// GasPrice returns the current consensus gas price.
@@ -50,6 +52,10 @@ type SubmissionManager interface {
 	// with the passed signer and submits it to consensus backend.
 	//
 	// It also automatically handles retries in case the nonce was incorrectly estimated.
+	//
+	// In case transaction execution fails, the returned error can be matched against the
+	// corresponding error variables of the consensus application (e.g., using errors.Is with
+	// registry.ErrInvalidArgument), even when the backend is accessed over gRPC.
 	SignAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error
 }
 
@@ -82,7 +88,12 @@ func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signatur
 		var gas transaction.Gas
 		gas, err = m.backend.EstimateGas(ctx, &EstimateGasRequest{Signer: signer.Public(), Transaction: tx})
 		if err != nil {
-			return fmt.Errorf("failed to estimate gas: %w", err)
+			err = fmt.Errorf("failed to estimate gas: %w", err)
+			if module, _ := errors.Code(err); module != errors.UnknownModule && !errors.Is(err, ErrNoCommittedBlocks) {
+				// Transaction execution failed during estimation, retrying will not help.
+				return backoff.Permanent(err)
+			}
+			return err
 		}
 
 		// Fetch current consensus gas price and compute the fee.
@@ -123,13 +134,21 @@ func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signatur
 	}
 
 	if err = m.backend.SubmitTx(ctx, sigTx); err != nil {
-		if errors.Is(err, transaction.ErrInvalidNonce) {
+		switch {
+		case errors.Is(err, transaction.ErrInvalidNonce):
 			// Invalid nonce, retry submission.
 			m.logger.Debug("retrying transaction submission due to invalid nonce",
 				"account_address", signerAddr,
 				"nonce", tx.Nonce,
 			)
 			return err
+		case errors.Is(err, ErrMempoolClassFull):
+			// Mempool capacity for the transaction's priority class is exhausted, retry later.
+			m.logger.Debug("retrying transaction submission due to full mempool",
+				"account_address", signerAddr,
+				"method", tx.Method,
+			)
+			return err
 		}
 		return backoff.Permanent(err)
 	}
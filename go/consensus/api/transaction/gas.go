@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -19,6 +20,10 @@ var (
 	// ErrGasPriceTooLow is the error returned when the gas price is too low.
 	ErrGasPriceTooLow = errors.New(moduleName, 3, "transaction: gas price too low")
 
+	// ErrInvalidFeeSponsor is the error returned when the fee sponsor is not allowed to pay
+	// fees on behalf of the transaction signer.
+	ErrInvalidFeeSponsor = errors.New(moduleName, 4, "transaction: invalid fee sponsor")
+
 	_ prettyprint.PrettyPrinter = (*Fee)(nil)
 )
 
@@ -32,6 +37,10 @@ type Fee struct {
 	Amount quantity.Quantity `json:"amount"`
 	// Gas is the maximum gas that a transaction can use.
 	Gas Gas `json:"gas"`
+	// Sponsor is an optional public key of the account that pays the fee on behalf of the
+	// transaction signer. The sponsor must have granted the signer an allowance that covers
+	// the fee amount.
+	Sponsor *signature.PublicKey `json:"sponsor,omitempty"`
 }
 
 // PrettyPrint writes a pretty-printed representation of the fee to the given
@@ -45,6 +54,9 @@ func (f Fee) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 	fmt.Fprintf(w, "%s(gas price: ", prefix)
 	token.PrettyPrintAmount(ctx, *f.GasPrice(), w)
 	fmt.Fprintln(w, " per gas unit)")
+	if f.Sponsor != nil {
+		fmt.Fprintf(w, "%sSponsor: %s\n", prefix, f.Sponsor)
+	}
 }
 
 // PrettyType returns a representation of Fee that can be used for pretty
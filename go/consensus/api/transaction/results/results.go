@@ -2,6 +2,7 @@ package results
 
 import (
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -15,6 +16,20 @@ type Event struct {
 	RootHash *roothash.Event `json:"roothash,omitempty"`
 }
 
+// Index returns the position of the event within the block that emitted it.
+func (e *Event) Index() events.Index {
+	switch {
+	case e.Staking != nil:
+		return e.Staking.Index
+	case e.Registry != nil:
+		return e.Registry.Index
+	case e.RootHash != nil:
+		return e.RootHash.Index
+	default:
+		return events.Index{}
+	}
+}
+
 // Error is a transaction execution error.
 type Error struct {
 	Module  string `json:"module,omitempty"`
@@ -22,6 +37,32 @@ type Error struct {
 	Message string `json:"message,omitempty"`
 }
 
+// NewError creates a new transaction execution error from the given error.
+func NewError(err error) Error {
+	if err == nil {
+		return Error{}
+	}
+
+	module, code := errors.Code(err)
+	return Error{
+		Module:  module,
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+// Err returns the transaction execution error as a Go error or nil in case
+// execution was successful.
+//
+// In case the error corresponds to a registered error, the returned error can
+// be matched against it via errors.Is.
+func (e *Error) Err() error {
+	if e.Code == errors.CodeNoError {
+		return nil
+	}
+	return errors.FromCodeWithMessage(e.Module, e.Code, e.Message)
+}
+
 // Result is a transaction execution result.
 type Result struct {
 	Error  Error    `json:"error"`
@@ -0,0 +1,101 @@
+package testvectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+// CorpusVersion is the version of the combined test vector corpus format.
+const CorpusVersion = 1
+
+var (
+	// Fees are the transaction fees used when generating test vectors.
+	Fees = []*transaction.Fee{
+		{},
+		{Amount: *quantity.NewFromUint64(100000000), Gas: 1000},
+		{Amount: *quantity.NewFromUint64(0), Gas: 1000},
+		{Amount: *quantity.NewFromUint64(4242), Gas: 1000},
+	}
+
+	// Nonces are the transaction nonces used when generating test vectors.
+	Nonces = []uint64{0, 1, 10, 42, 1000, 1_000_000, 10_000_000, math.MaxUint64}
+)
+
+// Generator is a test vector generator for the transactions of a consensus service.
+type Generator struct {
+	// Name is the name of the consensus service.
+	Name string
+	// ChainContext is the raw chain context used to derive the chain domain
+	// separation context for all signatures.
+	ChainContext string
+	// Generate generates the test vectors.
+	Generate func() []TestVector
+}
+
+// SetChainContext configures the chain domain separation context for all
+// signatures, derived from the given raw chain context.
+func SetChainContext(rawContext string) string {
+	var chainContext hash.Hash
+	chainContext.FromBytes([]byte(rawContext))
+
+	signature.UnsafeResetChainContext()
+	signature.SetChainContext(chainContext.String())
+
+	return chainContext.String()
+}
+
+// ServiceTestVectors are the test vectors for a single consensus service.
+type ServiceTestVectors struct {
+	ChainContext string       `json:"chain_context"`
+	Vectors      []TestVector `json:"vectors"`
+}
+
+// Corpus is a versioned collection of test vectors for multiple consensus
+// services.
+type Corpus struct {
+	Version  uint16                         `json:"version"`
+	Services map[string]*ServiceTestVectors `json:"services"`
+}
+
+// NewCorpus runs all of the given generators and combines their output into
+// a single corpus.
+func NewCorpus(generators ...*Generator) *Corpus {
+	corpus := &Corpus{
+		Version:  CorpusVersion,
+		Services: make(map[string]*ServiceTestVectors),
+	}
+	for _, g := range generators {
+		if _, exists := corpus.Services[g.Name]; exists {
+			panic(fmt.Errorf("testvectors: duplicate generator: %s", g.Name))
+		}
+
+		corpus.Services[g.Name] = &ServiceTestVectors{
+			ChainContext: SetChainContext(g.ChainContext),
+			Vectors:      g.Generate(),
+		}
+	}
+	return corpus
+}
+
+// Run runs the given generator and prints its test vectors to stdout.
+func Run(g *Generator) {
+	SetChainContext(g.ChainContext)
+	printJSON(g.Generate())
+}
+
+// RunCorpus runs all of the given generators and prints the combined corpus
+// to stdout.
+func RunCorpus(generators ...*Generator) {
+	printJSON(NewCorpus(generators...))
+}
+
+func printJSON(v interface{}) {
+	jsonOut, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Printf("%s", jsonOut)
+}
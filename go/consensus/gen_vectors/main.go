@@ -0,0 +1,18 @@
+// gen_vectors generates a combined corpus of test vectors for all supported
+// consensus transactions.
+package main
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/tests/vectors"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/tests/vectors"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/tests/vectors"
+)
+
+func main() {
+	testvectors.RunCorpus(
+		staking.Generator,
+		registry.Generator,
+		roothash.Generator,
+	)
+}
@@ -0,0 +1,133 @@
+// Package light provides light client verification of consensus headers and state proofs.
+//
+// It allows external services to verify query results obtained from untrusted public consensus
+// endpoints, starting from a trusted light block (e.g., one obtained from the genesis document or
+// a trusted node). Headers can be verified either sequentially (every intermediate header is
+// verified) or by skipping (intermediate headers are only fetched when the validator set changed
+// too much), after which state proofs can be verified against the committed state root.
+package light
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	tmlight "github.com/tendermint/tendermint/light"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+// moduleName is the module name used for error definitions.
+const moduleName = "consensus/light"
+
+var (
+	// ErrInvalidLightBlock is the error returned when a light block is malformed.
+	ErrInvalidLightBlock = errors.New(moduleName, 1, "light: invalid light block")
+
+	// ErrVerificationFailed is the error returned when a light block could not be verified.
+	ErrVerificationFailed = errors.New(moduleName, 2, "light: verification failed")
+
+	// ErrInvalidHeight is the error returned when verification is requested for a height that
+	// is lower than the height of the latest trusted light block.
+	ErrInvalidHeight = errors.New(moduleName, 3, "light: invalid height")
+
+	// DefaultTrustLevel is the default trust level used for skipping verification. A new header
+	// can be trusted if at least one correct validator signed it.
+	DefaultTrustLevel = tmlight.DefaultTrustLevel
+)
+
+// Config is the light client verification configuration.
+type Config struct {
+	// ChainID is the Tendermint chain ID.
+	ChainID string
+
+	// TrustingPeriod is the period during which a trusted header can be used to verify new
+	// headers. It should be significantly shorter than the debonding interval.
+	TrustingPeriod time.Duration
+
+	// MaxClockDrift is the maximum amount that header timestamps may drift into the future.
+	MaxClockDrift time.Duration
+
+	// TrustLevel is the fraction of the trusted validator set voting power that must have signed
+	// a non-adjacent header for it to be trusted during skipping verification. If not set,
+	// DefaultTrustLevel is used.
+	TrustLevel tmmath.Fraction
+}
+
+// ValidateBasic performs basic configuration validity checks.
+func (c *Config) ValidateBasic() error {
+	if c.ChainID == "" {
+		return fmt.Errorf("light: missing chain ID")
+	}
+	if c.TrustingPeriod <= 0 {
+		return fmt.Errorf("light: trusting period must be positive")
+	}
+	if c.MaxClockDrift < 0 {
+		return fmt.Errorf("light: max clock drift must not be negative")
+	}
+	if err := tmlight.ValidateTrustLevel(c.trustLevel()); err != nil {
+		return fmt.Errorf("light: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) trustLevel() tmmath.Fraction {
+	if c.TrustLevel.Denominator == 0 {
+		return DefaultTrustLevel
+	}
+	return c.TrustLevel
+}
+
+// Provider is a source of untrusted light blocks.
+type Provider interface {
+	// LightBlock returns the light block at the given height.
+	LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error)
+}
+
+type backendProvider struct {
+	chainID string
+	backend consensus.LightClientBackend
+}
+
+func (p *backendProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	lb, err := p.backend.GetLightBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeLightBlock(p.chainID, lb)
+}
+
+// NewProvider creates a new light block provider backed by the given (untrusted) consensus light
+// client backend, e.g., a gRPC connection to a public consensus endpoint.
+func NewProvider(chainID string, backend consensus.LightClientBackend) Provider {
+	return &backendProvider{
+		chainID: chainID,
+		backend: backend,
+	}
+}
+
+// DecodeLightBlock decodes the Tendermint light block from the given consensus light block and
+// performs basic validity checks.
+//
+// NOTE: The returned light block is not verified.
+func DecodeLightBlock(chainID string, lb *consensus.LightBlock) (*tmtypes.LightBlock, error) {
+	var protoLb tmproto.LightBlock
+	if err := protoLb.Unmarshal(lb.Meta); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLightBlock, err)
+	}
+	tlb, err := tmtypes.LightBlockFromProto(&protoLb)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLightBlock, err)
+	}
+	if err = tlb.ValidateBasic(chainID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLightBlock, err)
+	}
+	if tlb.Height != lb.Height {
+		return nil, fmt.Errorf("%w: height mismatch (expected: %d got: %d)", ErrInvalidLightBlock, lb.Height, tlb.Height)
+	}
+	return tlb, nil
+}
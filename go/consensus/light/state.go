@@ -0,0 +1,117 @@
+package light
+
+import (
+	"context"
+	"fmt"
+
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// StateRoot returns the root of the consensus state committed to by the given light block.
+//
+// Note that a light block at height H commits to the state after executing the block at height
+// H-1, so the returned root has version H-1.
+func StateRoot(lb *tmtypes.LightBlock) (mkvsNode.Root, error) {
+	if lb == nil || lb.SignedHeader == nil || lb.Header == nil {
+		return mkvsNode.Root{}, fmt.Errorf("%w: missing header", ErrInvalidLightBlock)
+	}
+	if lb.Height <= 0 {
+		return mkvsNode.Root{}, fmt.Errorf("%w: malformed height: %d", ErrInvalidLightBlock, lb.Height)
+	}
+
+	root := mkvsNode.Root{
+		Version: uint64(lb.Height) - 1,
+	}
+	switch lb.AppHash {
+	case nil:
+		root.Hash.Empty()
+	default:
+		if err := root.Hash.UnmarshalBinary(lb.AppHash); err != nil {
+			return mkvsNode.Root{}, fmt.Errorf("%w: malformed application hash: %s", ErrInvalidLightBlock, err)
+		}
+	}
+	return root, nil
+}
+
+// NewVerifiedState creates a read-only view of the consensus state under the given root, which
+// fetches state from the given (untrusted) read syncer and verifies all proofs against the root.
+//
+// The caller must make sure that the root has been verified, e.g., by using StateRoot on a
+// verified light block.
+func NewVerifiedState(rs syncer.ReadSyncer, root mkvsNode.Root) mkvs.Tree {
+	return mkvs.NewWithRoot(rs, nil, root)
+}
+
+// VerifiedState verifies the light block committing to the consensus state at the given height
+// and returns a read-only view of the state, which fetches state from the given (untrusted) read
+// syncer and verifies all proofs against the verified state root.
+//
+// The returned tree should be closed after use.
+func (v *Verifier) VerifiedState(ctx context.Context, rs syncer.ReadSyncer, height int64) (mkvs.Tree, error) {
+	lb, err := v.VerifySkipping(ctx, height+1)
+	if err != nil {
+		return nil, err
+	}
+	root, err := StateRoot(lb)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifiedState(rs, root), nil
+}
+
+// staticReadSyncer is a read syncer that returns a fixed proof for key lookups.
+type staticReadSyncer struct {
+	proof *syncer.ProofResponse
+}
+
+func (rs *staticReadSyncer) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer.ProofResponse, error) {
+	return rs.proof, nil
+}
+
+func (rs *staticReadSyncer) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
+	return nil, fmt.Errorf("light: prefix lookups not supported by static proof")
+}
+
+func (rs *staticReadSyncer) SyncIterate(ctx context.Context, request *syncer.IterateRequest) (*syncer.ProofResponse, error) {
+	return nil, fmt.Errorf("light: iteration not supported by static proof")
+}
+
+// VerifyStateProof verifies that the given proof is valid for the given state root.
+func VerifyStateProof(ctx context.Context, root mkvsNode.Root, proof *syncer.ProofResponse) error {
+	if proof == nil {
+		return fmt.Errorf("%w: missing proof", ErrVerificationFailed)
+	}
+	if root.Hash.IsEmpty() {
+		return nil
+	}
+
+	var pv syncer.ProofVerifier
+	if _, err := pv.VerifyProof(ctx, root.Hash, &proof.Proof); err != nil {
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, err)
+	}
+	return nil
+}
+
+// VerifyGet verifies the given proof obtained for a key lookup (e.g., via a SyncGet request
+// for the given key) against the given state root and returns the proven value of the key.
+//
+// In case the proof shows that the key does not exist, a nil value is returned.
+func VerifyGet(ctx context.Context, root mkvsNode.Root, key []byte, proof *syncer.ProofResponse) ([]byte, error) {
+	if proof == nil {
+		return nil, fmt.Errorf("%w: missing proof", ErrVerificationFailed)
+	}
+
+	// The tree verifies the proof against the given root before using it.
+	tree := mkvs.NewWithRoot(&staticReadSyncer{proof: proof}, nil, root)
+	defer tree.Close()
+
+	value, err := tree.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerificationFailed, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,95 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+func TestStateRoot(t *testing.T) {
+	require := require.New(t)
+
+	_, err := StateRoot(nil)
+	require.Error(err, "StateRoot should fail without a light block")
+
+	blocks := generateChain(t, 2, 2, []testValidatorSet{newTestValidatorSet(1)}, time.Now())
+	lb := blocks[2]
+	lb.AppHash = nil
+
+	root, err := StateRoot(lb)
+	require.NoError(err, "StateRoot")
+	require.EqualValues(1, root.Version, "state root version should be the previous height")
+	require.True(root.Hash.IsEmpty(), "state root hash should be empty without an application hash")
+
+	lb = &tmtypes.LightBlock{SignedHeader: &tmtypes.SignedHeader{Header: &tmtypes.Header{Height: 2, AppHash: []byte("malformed")}}}
+	_, err = StateRoot(lb)
+	require.Error(err, "StateRoot should fail with a malformed application hash")
+}
+
+func TestVerifyGet(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	var ns common.Namespace
+
+	tree := mkvs.New(nil, nil)
+	defer tree.Close()
+	for i := 0; i < 10; i++ {
+		err := tree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d", i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 10)
+	require.NoError(err, "Commit")
+	root := mkvsNode.Root{Namespace: ns, Version: 10, Hash: rootHash}
+
+	getProof := func(key []byte) *syncer.ProofResponse {
+		proof, perr := tree.SyncGet(ctx, &syncer.GetRequest{
+			Tree: syncer.TreeID{Root: root, Position: root.Hash},
+			Key:  key,
+		})
+		require.NoError(perr, "SyncGet")
+		return proof
+	}
+
+	// Existing key.
+	proof := getProof([]byte("key 5"))
+	value, err := VerifyGet(ctx, root, []byte("key 5"), proof)
+	require.NoError(err, "VerifyGet")
+	require.EqualValues([]byte("value 5"), value, "VerifyGet should return the proven value")
+
+	// Non-existing key.
+	proof = getProof([]byte("missing"))
+	value, err = VerifyGet(ctx, root, []byte("missing"), proof)
+	require.NoError(err, "VerifyGet")
+	require.Nil(value, "VerifyGet should return nil for non-existing keys")
+
+	// Proof for a different root.
+	badRoot := root
+	badRoot.Hash.FromBytes([]byte("bad root"))
+	_, err = VerifyGet(ctx, badRoot, []byte("key 5"), getProof([]byte("key 5")))
+	require.Error(err, "VerifyGet should fail for a proof for a different root")
+	require.True(errors.Is(err, ErrVerificationFailed), "VerifyGet should return ErrVerificationFailed")
+	require.Error(VerifyStateProof(ctx, badRoot, getProof([]byte("key 5"))), "VerifyStateProof should fail")
+	require.NoError(VerifyStateProof(ctx, root, getProof([]byte("key 5"))), "VerifyStateProof")
+
+	// Missing proof.
+	_, err = VerifyGet(ctx, root, []byte("key 5"), nil)
+	require.Error(err, "VerifyGet should fail without a proof")
+
+	// Verified state backed by the (untrusted) tree.
+	state := NewVerifiedState(tree, root)
+	defer state.Close()
+	value, err = state.Get(ctx, []byte("key 7"))
+	require.NoError(err, "Get")
+	require.EqualValues([]byte("value 7"), value, "verified state should return the correct value")
+}
@@ -0,0 +1,179 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tmlight "github.com/tendermint/tendermint/light"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Verifier verifies light blocks obtained from an untrusted provider, starting from a trusted
+// light block.
+type Verifier struct {
+	sync.Mutex
+
+	cfg      Config
+	provider Provider
+	trusted  *tmtypes.LightBlock
+
+	now func() time.Time
+}
+
+// Trusted returns the latest trusted light block.
+func (v *Verifier) Trusted() *tmtypes.LightBlock {
+	v.Lock()
+	defer v.Unlock()
+
+	return v.trusted
+}
+
+// VerifySequential verifies the light block at the given height by verifying all intermediate
+// light blocks since the latest trusted light block, one by one.
+//
+// On success the verified light block becomes the latest trusted light block.
+func (v *Verifier) VerifySequential(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	v.Lock()
+	defer v.Unlock()
+
+	if done, err := v.checkHeightLocked(height); done || err != nil {
+		return v.trusted, err
+	}
+
+	now := v.now()
+	for h := v.trusted.Height + 1; h <= height; h++ {
+		untrusted, err := v.fetch(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		if err = v.verifyStep(v.trusted, untrusted, now); err != nil {
+			return nil, err
+		}
+		v.trusted = untrusted
+	}
+	return v.trusted, nil
+}
+
+// VerifySkipping verifies the light block at the given height by skipping intermediate light
+// blocks as long as enough of the trusted validator set (as specified by the configured trust
+// level) signed the untrusted light block. Otherwise intermediate light blocks are verified by
+// bisection.
+//
+// On success the verified light block becomes the latest trusted light block.
+func (v *Verifier) VerifySkipping(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	v.Lock()
+	defer v.Unlock()
+
+	if done, err := v.checkHeightLocked(height); done || err != nil {
+		return v.trusted, err
+	}
+
+	target, err := v.fetch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	now := v.now()
+	pending := []*tmtypes.LightBlock{target}
+	for len(pending) > 0 {
+		untrusted := pending[len(pending)-1]
+
+		err = v.verifyStep(v.trusted, untrusted, now)
+		var errCantTrust tmlight.ErrNewValSetCantBeTrusted
+		switch {
+		case err == nil:
+			// Verified, continue with the next pending light block.
+			v.trusted = untrusted
+			pending = pending[:len(pending)-1]
+		case errors.As(err, &errCantTrust):
+			// Not enough of the trusted validator set signed the untrusted light block, bisect.
+			pivotHeight := (v.trusted.Height + untrusted.Height) / 2
+			if pivotHeight <= v.trusted.Height {
+				return nil, err
+			}
+
+			var pivot *tmtypes.LightBlock
+			if pivot, err = v.fetch(ctx, pivotHeight); err != nil {
+				return nil, err
+			}
+			pending = append(pending, pivot)
+		default:
+			return nil, err
+		}
+	}
+	return v.trusted, nil
+}
+
+func (v *Verifier) checkHeightLocked(height int64) (bool, error) {
+	switch {
+	case height < v.trusted.Height:
+		return false, fmt.Errorf("%w: height %d is lower than trusted height %d", ErrInvalidHeight, height, v.trusted.Height)
+	case height == v.trusted.Height:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (v *Verifier) fetch(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	lb, err := v.provider.LightBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("light: failed to fetch light block at height %d: %w", height, err)
+	}
+	if lb.Height != height {
+		return nil, fmt.Errorf("%w: height mismatch (expected: %d got: %d)", ErrInvalidLightBlock, height, lb.Height)
+	}
+	if err = lb.ValidateBasic(v.cfg.ChainID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLightBlock, err)
+	}
+	return lb, nil
+}
+
+func (v *Verifier) verifyStep(trusted, untrusted *tmtypes.LightBlock, now time.Time) error {
+	err := tmlight.Verify(
+		trusted.SignedHeader,
+		trusted.ValidatorSet,
+		untrusted.SignedHeader,
+		untrusted.ValidatorSet,
+		v.cfg.TrustingPeriod,
+		now,
+		v.cfg.MaxClockDrift,
+		v.cfg.trustLevel(),
+	)
+	if err == nil {
+		return nil
+	}
+
+	var errCantTrust tmlight.ErrNewValSetCantBeTrusted
+	if errors.As(err, &errCantTrust) {
+		// Keep the error type so that the caller can bisect.
+		return err
+	}
+	return fmt.Errorf("%w: height %d: %s", ErrVerificationFailed, untrusted.Height, err)
+}
+
+// NewVerifier creates a new light block verifier, starting from the given trusted light block.
+//
+// The trusted light block must be obtained from a trusted source (e.g., the operator of the
+// service) as it is not verified.
+func NewVerifier(cfg Config, provider Provider, trusted *tmtypes.LightBlock) (*Verifier, error) {
+	if err := cfg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if trusted == nil {
+		return nil, fmt.Errorf("%w: missing trusted light block", ErrInvalidLightBlock)
+	}
+	if err := trusted.ValidateBasic(cfg.ChainID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLightBlock, err)
+	}
+
+	return &Verifier{
+		cfg:      cfg,
+		provider: provider,
+		trusted:  trusted,
+		now:      time.Now,
+	}, nil
+}
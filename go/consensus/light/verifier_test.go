@@ -0,0 +1,207 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+const testChainID = "test-chain"
+
+type testProvider struct {
+	blocks  map[int64]*tmtypes.LightBlock
+	fetched []int64
+}
+
+func (p *testProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	p.fetched = append(p.fetched, height)
+	lb, ok := p.blocks[height]
+	if !ok {
+		return nil, fmt.Errorf("no light block at height %d", height)
+	}
+	return lb, nil
+}
+
+type testValidatorSet struct {
+	valSet   *tmtypes.ValidatorSet
+	privVals []tmtypes.PrivValidator
+}
+
+func newTestValidatorSet(n int) testValidatorSet {
+	valSet, privVals := tmtypes.RandValidatorSet(n, 10)
+	return testValidatorSet{valSet, privVals}
+}
+
+// generateChain generates a chain of light blocks where the validator set changes to the next
+// given validator set every changeInterval heights.
+func generateChain(t *testing.T, numBlocks int64, changeInterval int64, sets []testValidatorSet, start time.Time) map[int64]*tmtypes.LightBlock {
+	require := require.New(t)
+
+	setAt := func(height int64) testValidatorSet {
+		idx := int((height - 1) / changeInterval)
+		if idx >= len(sets) {
+			idx = len(sets) - 1
+		}
+		return sets[idx]
+	}
+
+	blocks := make(map[int64]*tmtypes.LightBlock)
+	var lastBlockID tmtypes.BlockID
+	for height := int64(1); height <= numBlocks; height++ {
+		vs := setAt(height)
+		nextVs := setAt(height + 1)
+
+		header := &tmtypes.Header{
+			Version:            tmversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            testChainID,
+			Height:             height,
+			Time:               start.Add(time.Duration(height) * time.Minute),
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vs.valSet.Hash(),
+			NextValidatorsHash: nextVs.valSet.Hash(),
+			AppHash:            []byte(fmt.Sprintf("app hash %d", height)),
+			ProposerAddress:    vs.valSet.Proposer.Address,
+		}
+		blockID := tmtypes.BlockID{
+			Hash: header.Hash(),
+			PartSetHeader: tmtypes.PartSetHeader{
+				Total: 1,
+				Hash:  header.Hash(),
+			},
+		}
+		voteSet := tmtypes.NewVoteSet(testChainID, height, 0, tmproto.PrecommitType, vs.valSet)
+		commit, err := tmtypes.MakeCommit(blockID, height, 0, voteSet, vs.privVals, header.Time)
+		require.NoError(err, "MakeCommit")
+
+		lb := &tmtypes.LightBlock{
+			SignedHeader: &tmtypes.SignedHeader{
+				Header: header,
+				Commit: commit,
+			},
+			ValidatorSet: vs.valSet,
+		}
+		require.NoError(lb.ValidateBasic(testChainID), "ValidateBasic")
+
+		blocks[height] = lb
+		lastBlockID = blockID
+	}
+	return blocks
+}
+
+func testConfig() Config {
+	return Config{
+		ChainID:        testChainID,
+		TrustingPeriod: 24 * time.Hour,
+		MaxClockDrift:  10 * time.Second,
+	}
+}
+
+func TestConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := testConfig()
+	require.NoError(cfg.ValidateBasic(), "ValidateBasic")
+	require.Equal(DefaultTrustLevel, cfg.trustLevel(), "default trust level should be used")
+
+	cfg = testConfig()
+	cfg.ChainID = ""
+	require.Error(cfg.ValidateBasic(), "ValidateBasic should fail without a chain ID")
+
+	cfg = testConfig()
+	cfg.TrustingPeriod = 0
+	require.Error(cfg.ValidateBasic(), "ValidateBasic should fail without a trusting period")
+
+	cfg = testConfig()
+	cfg.TrustLevel.Numerator = 1
+	cfg.TrustLevel.Denominator = 5
+	require.Error(cfg.ValidateBasic(), "ValidateBasic should fail with a too low trust level")
+}
+
+func TestVerifier(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Now().Add(-time.Hour)
+	sets := []testValidatorSet{
+		newTestValidatorSet(4),
+		newTestValidatorSet(4),
+		newTestValidatorSet(4),
+	}
+	blocks := generateChain(t, 20, 5, sets, start)
+
+	newVerifier := func() (*Verifier, *testProvider) {
+		provider := &testProvider{blocks: blocks}
+		v, err := NewVerifier(testConfig(), provider, blocks[1])
+		require.NoError(err, "NewVerifier")
+		return v, provider
+	}
+
+	t.Run("Sequential", func(t *testing.T) {
+		v, provider := newVerifier()
+
+		lb, err := v.VerifySequential(context.Background(), 12)
+		require.NoError(err, "VerifySequential")
+		require.EqualValues(12, lb.Height, "verified light block should have the correct height")
+		require.Equal(blocks[12], v.Trusted(), "trusted light block should be updated")
+		require.Len(provider.fetched, 11, "all intermediate light blocks should be fetched")
+
+		_, err = v.VerifySequential(context.Background(), 11)
+		require.Error(err, "VerifySequential should fail for heights below the trusted height")
+		require.True(errors.Is(err, ErrInvalidHeight), "VerifySequential should return ErrInvalidHeight")
+
+		lb, err = v.VerifySequential(context.Background(), 12)
+		require.NoError(err, "VerifySequential at the trusted height")
+		require.Equal(blocks[12], lb, "VerifySequential at the trusted height should return the trusted light block")
+	})
+
+	t.Run("Skipping", func(t *testing.T) {
+		v, provider := newVerifier()
+
+		// The validator set does not change so intermediate light blocks should be skipped.
+		lb, err := v.VerifySkipping(context.Background(), 5)
+		require.NoError(err, "VerifySkipping")
+		require.EqualValues(5, lb.Height, "verified light block should have the correct height")
+		require.Equal([]int64{5}, provider.fetched, "intermediate light blocks should be skipped")
+
+		// The validator set changes completely so bisection is required.
+		lb, err = v.VerifySkipping(context.Background(), 20)
+		require.NoError(err, "VerifySkipping")
+		require.EqualValues(20, lb.Height, "verified light block should have the correct height")
+		require.Equal(blocks[20], v.Trusted(), "trusted light block should be updated")
+		require.Less(len(provider.fetched), 16, "some intermediate light blocks should be skipped")
+	})
+
+	t.Run("InvalidCommit", func(t *testing.T) {
+		// Generate a chain signed by an unrelated validator set.
+		forged := generateChain(t, 5, 5, []testValidatorSet{newTestValidatorSet(4)}, start)
+		provider := &testProvider{blocks: forged}
+		v, err := NewVerifier(testConfig(), provider, blocks[1])
+		require.NoError(err, "NewVerifier")
+
+		_, err = v.VerifySequential(context.Background(), 3)
+		require.Error(err, "VerifySequential should fail for forged light blocks")
+		require.True(errors.Is(err, ErrVerificationFailed), "VerifySequential should return ErrVerificationFailed")
+
+		_, err = v.VerifySkipping(context.Background(), 5)
+		require.Error(err, "VerifySkipping should fail for forged light blocks")
+		require.Equal(blocks[1], v.Trusted(), "trusted light block should not be updated")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.TrustingPeriod = time.Minute
+		v, err := NewVerifier(cfg, &testProvider{blocks: blocks}, blocks[1])
+		require.NoError(err, "NewVerifier")
+
+		_, err = v.VerifySkipping(context.Background(), 5)
+		require.Error(err, "VerifySkipping should fail when the trusted light block expired")
+		require.True(errors.Is(err, ErrVerificationFailed), "VerifySkipping should return ErrVerificationFailed")
+	})
+}
@@ -0,0 +1,102 @@
+package abci
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+)
+
+var abciMempoolRejectedTxs = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "oasis_abci_mempool_rejected_txs",
+		Help: "Number of transactions rejected due to exhausted mempool capacity of their priority class.",
+	},
+	[]string{"class"},
+)
+
+// Mempool is the interface used by the multiplexer to query mempool utilization.
+type Mempool interface {
+	// Size returns the number of transactions in the mempool.
+	Size() int
+
+	// TxsBytes returns the total size of all transactions in the mempool.
+	TxsBytes() int64
+}
+
+// MempoolReservationConfig is the mempool capacity reservation configuration.
+//
+// Transactions of a given priority class are only admitted into the mempool in case the mempool
+// utilization is below the capacity that is not reserved for higher priority classes.
+type MempoolReservationConfig struct {
+	// High is the fraction of mempool capacity reserved for high priority transactions.
+	High float64
+
+	// Normal is the fraction of mempool capacity reserved for normal priority transactions (and
+	// available to high priority transactions), in addition to High.
+	Normal float64
+}
+
+// Validate validates the mempool capacity reservation configuration.
+func (cfg *MempoolReservationConfig) Validate() error {
+	if cfg.High < 0 || cfg.Normal < 0 {
+		return fmt.Errorf("abci/mempool: reserved capacity must be non-negative")
+	}
+	if cfg.High+cfg.Normal > 1 {
+		return fmt.Errorf("abci/mempool: total reserved capacity must not exceed 1 (got: %f)", cfg.High+cfg.Normal)
+	}
+	return nil
+}
+
+// reserved returns the fraction of mempool capacity that transactions of the given priority class
+// cannot use.
+func (cfg *MempoolReservationConfig) reserved(class api.PriorityClass) float64 {
+	switch class {
+	case api.PriorityClassLow:
+		return cfg.High + cfg.Normal
+	case api.PriorityClassNormal:
+		return cfg.High
+	default:
+		return 0
+	}
+}
+
+type mempoolReservations struct {
+	cfg MempoolReservationConfig
+
+	mempool     Mempool
+	maxSize     int
+	maxTxsBytes int64
+}
+
+// checkCapacity checks whether the mempool has enough unreserved capacity to admit a new
+// transaction of the given size in the given priority class.
+func (r *mempoolReservations) checkCapacity(class api.PriorityClass, txSize int) error {
+	if r.mempool == nil {
+		return nil
+	}
+	reserved := r.cfg.reserved(class)
+	if reserved == 0 {
+		return nil
+	}
+
+	maxSize := int(math.Round(float64(r.maxSize) * (1 - reserved)))
+	maxTxsBytes := int64(math.Round(float64(r.maxTxsBytes) * (1 - reserved)))
+	if r.mempool.Size() >= maxSize || r.mempool.TxsBytes()+int64(txSize) > maxTxsBytes {
+		abciMempoolRejectedTxs.With(prometheus.Labels{"class": class.String()}).Inc()
+		return fmt.Errorf("%w: class: %s", consensus.ErrMempoolClassFull, class)
+	}
+	return nil
+}
+
+// priorityClass returns the priority class of the given transaction method.
+func (mux *abciMux) priorityClass(method transaction.MethodName) api.PriorityClass {
+	if p, ok := mux.appsByMethod[method].(api.TransactionPrioritizer); ok {
+		return p.PriorityClass(method)
+	}
+	return api.PriorityClassNormal
+}
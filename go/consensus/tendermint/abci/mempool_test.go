@@ -0,0 +1,82 @@
+package abci
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+)
+
+type testMempool struct {
+	size     int
+	txsBytes int64
+}
+
+func (mp *testMempool) Size() int {
+	return mp.size
+}
+
+func (mp *testMempool) TxsBytes() int64 {
+	return mp.txsBytes
+}
+
+func TestMempoolReservationConfig(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError((&MempoolReservationConfig{}).Validate(), "no reservations should be valid")
+	require.NoError((&MempoolReservationConfig{High: 0.1, Normal: 0.2}).Validate(), "valid reservations")
+	require.NoError((&MempoolReservationConfig{High: 0.5, Normal: 0.5}).Validate(), "full reservation should be valid")
+	require.Error((&MempoolReservationConfig{High: -0.1}).Validate(), "negative reservations should be invalid")
+	require.Error((&MempoolReservationConfig{High: 0.6, Normal: 0.5}).Validate(), "over-reservation should be invalid")
+}
+
+func TestMempoolReservations(t *testing.T) {
+	require := require.New(t)
+
+	var r mempoolReservations
+	r.cfg = MempoolReservationConfig{High: 0.1, Normal: 0.2}
+	require.NoError(r.checkCapacity(api.PriorityClassLow, 100), "reservations should not be enforced without a mempool")
+
+	mp := &testMempool{}
+	r.mempool = mp
+	r.maxSize = 100
+	r.maxTxsBytes = 10_000
+
+	for _, tc := range []struct {
+		size     int
+		txsBytes int64
+		txSize   int
+		low      bool
+		normal   bool
+		high     bool
+	}{
+		{0, 0, 100, true, true, true},
+		{69, 0, 100, true, true, true},
+		{70, 0, 100, false, true, true},
+		{89, 0, 100, false, true, true},
+		{90, 0, 100, false, false, true},
+		{99, 0, 100, false, false, true},
+		{10, 6_900, 100, true, true, true},
+		{10, 6_901, 100, false, true, true},
+		{10, 8_901, 100, false, false, true},
+	} {
+		mp.size, mp.txsBytes = tc.size, tc.txsBytes
+
+		for class, admitted := range map[api.PriorityClass]bool{
+			api.PriorityClassLow:    tc.low,
+			api.PriorityClassNormal: tc.normal,
+			api.PriorityClassHigh:   tc.high,
+		} {
+			err := r.checkCapacity(class, tc.txSize)
+			switch admitted {
+			case true:
+				require.NoError(err, "transaction should be admitted (class: %s size: %d bytes: %d)", class, tc.size, tc.txsBytes)
+			case false:
+				require.True(errors.Is(err, consensus.ErrMempoolClassFull), "transaction should be rejected (class: %s size: %d bytes: %d)", class, tc.size, tc.txsBytes)
+			}
+		}
+	}
+}
@@ -58,6 +58,12 @@ var (
 	)
 	abciCollectors = []prometheus.Collector{
 		abciSize,
+		abciTxs,
+		abciGasUsed,
+		abciEvents,
+		abciBlockTxs,
+		abciBlockGasUsed,
+		abciMempoolRejectedTxs,
 	}
 
 	metricsOnce sync.Once
@@ -86,6 +92,12 @@ type ApplicationConfig struct { // nolint: maligned
 
 	// InitialHeight is the height of the initial block.
 	InitialHeight uint64
+
+	// LogBlockStats enables logging of per-block resource usage statistics.
+	LogBlockStats bool
+
+	// MempoolReservation is the per-priority class mempool capacity reservation configuration.
+	MempoolReservation MempoolReservationConfig
 }
 
 // ApplicationServer implements a tendermint ABCI application + socket server,
@@ -172,6 +184,22 @@ func (a *ApplicationServer) TransactionAuthHandler() api.TransactionAuthHandler
 	return a.mux.state.txAuthHandler
 }
 
+// SetMempool configures the mempool used to enforce per-priority class mempool capacity
+// reservations, together with its maximum size (number of transactions) and maximum total size
+// of all transactions (in bytes).
+//
+// Until the mempool is configured, no capacity reservations are enforced.
+func (a *ApplicationServer) SetMempool(mempool Mempool, maxSize int, maxTxsBytes int64) error {
+	if a.mux.mempool.mempool != nil {
+		return fmt.Errorf("mux: mempool already configured")
+	}
+
+	a.mux.mempool.mempool = mempool
+	a.mux.mempool.maxSize = maxSize
+	a.mux.mempool.maxTxsBytes = maxTxsBytes
+	return nil
+}
+
 // WatchInvalidatedTx adds a watcher for when/if the transaction with given
 // hash becomes invalid due to a failed re-check.
 func (a *ApplicationServer) WatchInvalidatedTx(txHash hash.Hash) (<-chan error, pubsub.ClosableSubscription, error) {
@@ -183,6 +211,19 @@ func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transact
 	return a.mux.EstimateGas(caller, tx)
 }
 
+// SimulateTx simulates execution of the given transaction against the latest state.
+//
+// Returns the amount of gas used, the emitted events and the transaction execution error.
+func (a *ApplicationServer) SimulateTx(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, []types.Event, error) {
+	return a.mux.SimulateTx(caller, tx)
+}
+
+// StateSyncStatus returns the state sync status or nil in case no state sync checkpoint has been
+// accepted for restoration.
+func (a *ApplicationServer) StateSyncStatus() *consensus.StateSyncStatus {
+	return a.mux.getStateSyncStatus()
+}
+
 // State returns the application state.
 func (a *ApplicationServer) State() api.ApplicationQueryState {
 	return a.mux.state
@@ -206,6 +247,18 @@ func NewApplicationServer(ctx context.Context, upgrader upgrade.Backend, cfg *Ap
 	}, nil
 }
 
+// abciMux multiplexes ABCI requests to the registered applications.
+//
+// Consensus service clients rely on events being emitted in a deterministic order, so the mux
+// guarantees the ordering contract defined in the consensus/api/events package:
+//
+//   - InitChain, BeginBlock and EndBlock are dispatched to applications in lexicographic order
+//     of their names, as are post-tx hooks (ForeignExecuteTx) after the application handling a
+//     transaction has executed it.
+//   - Events emitted during InitChain are returned before all other events of the first block's
+//     BeginBlock.
+//   - Events are returned in the order in which they were emitted, including for transactions
+//     that failed.
 type abciMux struct {
 	sync.RWMutex
 	types.BaseApplication
@@ -214,14 +267,21 @@ type abciMux struct {
 	upgrader upgrade.Backend
 	state    *applicationState
 
-	appsByName     map[string]api.Application
-	appsByMethod   map[transaction.MethodName]api.Application
+	appsByName   map[string]api.Application
+	appsByMethod map[transaction.MethodName]api.Application
+	// appsByLexOrder is the list of applications in lexicographic order of their names. It
+	// defines the dispatch (and thus event emission) order and must not be changed.
 	appsByLexOrder []api.Application
 	appBlessed     api.Application
 
 	lastBeginBlock int64
 	currentTime    time.Time
 
+	blockStats    blockStats
+	logBlockStats bool
+
+	mempool mempoolReservations
+
 	haltHooks []func(context.Context, int64, epochtime.EpochTime)
 
 	// invalidatedTxs maps transaction hashes (hash.Hash) to a subscriber
@@ -230,6 +290,10 @@ type abciMux struct {
 	// debugExpiringTxs maps transaction hashes to the time at which they were created. This is only
 	// used in case CheckTx is disabled (for debug purposes only).
 	debugExpiringTxs map[hash.Hash]time.Time
+
+	// stateSync is the state sync progress. It is only set after a checkpoint has been accepted
+	// for restoration.
+	stateSync *consensus.StateSyncStatus
 }
 
 type invalidatedTxSubscription struct {
@@ -386,6 +450,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 	}
 	mux.lastBeginBlock = blockHeight
 	mux.currentTime = req.Header.Time
+	mux.blockStats.reset()
 
 	params := mux.state.ConsensusParameters()
 
@@ -405,12 +470,16 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 		panic("mux: can't get current epoch in BeginBlock")
 	}
 
-	// Check if there are any upgrades pending or if we need to halt for an upgrade.
+	// Check if there are any upgrades pending or if we need to halt for an upgrade. Any state
+	// migrations are performed atomically, either all of them are applied or none are.
+	cp := ctx.StartCheckpoint()
+	defer cp.Close()
 	switch err = mux.upgrader.ConsensusUpgrade(ctx, currentEpoch, blockHeight); err {
 	case nil:
 		// Everything ok.
+		cp.Commit()
 	case upgrade.ErrStopForUpgrade:
-		panic("mux: reached upgrade epoch")
+		panic("mux: reached upgrade point")
 	default:
 		panic(fmt.Sprintf("mux: error while trying to perform consensus upgrade: %v", err))
 	}
@@ -479,6 +548,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 			}
 		}
 	}
+	mux.blockStats.addEvents(response.Events)
 
 	return response
 }
@@ -578,16 +648,16 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 	return nil
 }
 
-func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) error {
+func (mux *abciMux) executeTx(ctx *api.Context, rawTx []byte) (*transaction.Transaction, error) {
 	tx, sigTx, err := mux.decodeTx(ctx, rawTx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Set authenticated transaction signer.
 	ctx.SetTxSigner(sigTx.Signature.PublicKey)
 
-	return mux.processTx(ctx, tx, len(rawTx))
+	return tx, mux.processTx(ctx, tx, len(rawTx))
 }
 
 func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, error) {
@@ -622,6 +692,37 @@ func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Tran
 	return ctx.Gas().GasUsed(), nil
 }
 
+func (mux *abciMux) SimulateTx(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, []types.Event, error) {
+	// Same as EstimateGas, this method can be called in parallel to the consensus layer and to
+	// other invocations as all state changes are discarded.
+	ctx := mux.state.NewContext(api.ContextSimulateTx, time.Time{})
+	defer ctx.Close()
+
+	ctx.SetTxSigner(caller)
+	mockSignedTx := transaction.SignedTransaction{
+		Signed: signature.Signed{
+			Blob: cbor.Marshal(tx),
+			// Signature is fixed-size, so we can leave it as default.
+		},
+	}
+	txSize := len(cbor.Marshal(mockSignedTx))
+
+	err := mux.processTx(ctx, tx, txSize)
+	gasUsed := ctx.Gas().GasUsed()
+
+	// Gas is not limited during simulation, so make sure to report transactions that would run
+	// out of gas when actually executed.
+	var maxGas transaction.Gas
+	if tx.Fee != nil {
+		maxGas = tx.Fee.Gas
+	}
+	if err == nil && gasUsed > maxGas {
+		err = fmt.Errorf("%w (limit: %d wanted: %d)", api.ErrOutOfGas, maxGas, gasUsed)
+	}
+
+	return gasUsed, ctx.GetEvents(), err
+}
+
 func (mux *abciMux) notifyInvalidatedCheckTx(txHash hash.Hash, err error) {
 	if item, exists := mux.invalidatedTxs.Load(txHash); exists {
 		// Notify subscriber.
@@ -666,7 +767,17 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 	ctx := mux.state.NewContext(api.ContextCheckTx, mux.currentTime)
 	defer ctx.Close()
 
-	if err := mux.executeTx(ctx, req.Tx); err != nil {
+	tx, sigTx, err := mux.decodeTx(ctx, req.Tx)
+	if err == nil && req.Type == types.CheckTxType_New {
+		// Make sure that there is enough mempool capacity available for the transaction's
+		// priority class before executing it as execution updates the check state.
+		err = mux.mempool.checkCapacity(mux.priorityClass(tx.Method), len(req.Tx))
+	}
+	if err == nil {
+		ctx.SetTxSigner(sigTx.Signature.PublicKey)
+		err = mux.processTx(ctx, tx, len(req.Tx))
+	}
+	if err != nil {
 		module, code := errors.Code(err)
 
 		if req.Type == types.CheckTxType_Recheck {
@@ -701,7 +812,12 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 	ctx := mux.state.NewContext(api.ContextDeliverTx, mux.currentTime)
 	defer ctx.Close()
 
-	if err := mux.executeTx(ctx, req.Tx); err != nil {
+	tx, err := mux.executeTx(ctx, req.Tx)
+	events := ctx.GetEvents()
+	mux.blockStats.addTx(tx, err, ctx.Gas().GasUsed())
+	mux.blockStats.addEvents(events)
+
+	if err != nil {
 		if api.IsUnavailableStateError(err) {
 			// Make sure to not commit any transactions which include results based on unavailable
 			// and/or corrupted state -- doing so can further corrupt state.
@@ -716,7 +832,7 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 			Codespace: module,
 			Code:      code,
 			Log:       err.Error(),
-			Events:    ctx.GetEvents(),
+			Events:    events,
 			GasWanted: int64(ctx.Gas().GasWanted()),
 			GasUsed:   int64(ctx.Gas().GasUsed()),
 		}
@@ -725,7 +841,7 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 	return types.ResponseDeliverTx{
 		Code:      types.CodeTypeOK,
 		Data:      cbor.Marshal(ctx.Data()),
-		Events:    ctx.GetEvents(),
+		Events:    events,
 		GasWanted: int64(ctx.Gas().GasWanted()),
 		GasUsed:   int64(ctx.Gas().GasUsed()),
 	}
@@ -764,6 +880,10 @@ func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 	// Update tags.
 	resp.Events = ctx.GetEvents()
 
+	// Publish block resource usage statistics.
+	mux.blockStats.addEvents(resp.Events)
+	mux.blockStats.publish(mux.logger, mux.state.BlockHeight(), mux.logBlockStats)
+
 	// Update version to what we are actually running.
 	resp.ConsensusParamUpdates = &types.ConsensusParams{
 		Version: &tmproto.VersionParams{
@@ -904,6 +1024,13 @@ func (mux *abciMux) OfferSnapshot(req types.RequestOfferSnapshot) types.Response
 		"root", cp.Root,
 	)
 
+	mux.Lock()
+	mux.stateSync = &consensus.StateSyncStatus{
+		Root:        cp.Root,
+		TotalChunks: uint64(len(cp.Chunks)),
+	}
+	mux.Unlock()
+
 	return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_ACCEPT}
 }
 
@@ -993,6 +1120,12 @@ func (mux *abciMux) ApplySnapshotChunk(req types.RequestApplySnapshotChunk) type
 		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
 	}
 
+	mux.Lock()
+	if mux.stateSync != nil {
+		mux.stateSync.RestoredChunks++
+	}
+	mux.Unlock()
+
 	// Check if we are done with the restoration. In this case, finalize the root.
 	if done {
 		err = mux.state.storage.NodeDB().Finalize(mux.state.ctx, cp.Root.Version, []hash.Hash{cp.Root.Hash})
@@ -1010,6 +1143,12 @@ func (mux *abciMux) ApplySnapshotChunk(req types.RequestApplySnapshotChunk) type
 			return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
 		}
 
+		mux.Lock()
+		if mux.stateSync != nil {
+			mux.stateSync.Done = true
+		}
+		mux.Unlock()
+
 		mux.logger.Info("successfully synced state",
 			"root", cp.Root,
 			logging.LogEvent, LogEventABCIStateSyncComplete,
@@ -1019,6 +1158,17 @@ func (mux *abciMux) ApplySnapshotChunk(req types.RequestApplySnapshotChunk) type
 	return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ACCEPT}
 }
 
+func (mux *abciMux) getStateSyncStatus() *consensus.StateSyncStatus {
+	mux.RLock()
+	defer mux.RUnlock()
+
+	if mux.stateSync == nil {
+		return nil
+	}
+	status := *mux.stateSync
+	return &status
+}
+
 func (mux *abciMux) doCleanup() {
 	mux.state.doCleanup()
 
@@ -1087,6 +1237,10 @@ func (mux *abciMux) checkDependencies() error {
 }
 
 func newABCIMux(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationConfig) (*abciMux, error) {
+	if err := cfg.MempoolReservation.Validate(); err != nil {
+		return nil, err
+	}
+
 	state, err := newApplicationState(ctx, cfg)
 	if err != nil {
 		return nil, err
@@ -1099,6 +1253,10 @@ func newABCIMux(ctx context.Context, upgrader upgrade.Backend, cfg *ApplicationC
 		appsByName:     make(map[string]api.Application),
 		appsByMethod:   make(map[transaction.MethodName]api.Application),
 		lastBeginBlock: -1,
+		logBlockStats:  cfg.LogBlockStats,
+		mempool: mempoolReservations{
+			cfg: cfg.MempoolReservation,
+		},
 	}
 
 	// Create a map of expiring transactions if CheckTx is disabled (debug only).
@@ -0,0 +1,214 @@
+package abci
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade"
+)
+
+var (
+	keyInitChain  = []byte("init_chain")
+	keyBeginBlock = []byte("begin_block")
+	keyExecuteTx  = []byte("execute_tx")
+	keyForeignTx  = []byte("foreign_tx")
+	keyEndBlock   = []byte("end_block")
+
+	methodOrderingC = transaction.NewMethodName("ordering_c", "Test", nil)
+)
+
+// orderingTimeSource is a time source that always reports the base epoch.
+type orderingTimeSource struct {
+	epochtime.Backend
+}
+
+func (ts *orderingTimeSource) GetBaseEpoch(ctx context.Context) (epochtime.EpochTime, error) {
+	return 0, nil
+}
+
+func (ts *orderingTimeSource) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return 0, nil
+}
+
+// orderingApp is an application that emits an event in every ABCI method.
+type orderingApp struct {
+	name    string
+	id      uint8
+	methods []transaction.MethodName
+}
+
+func (app *orderingApp) Name() string {
+	return app.name
+}
+
+func (app *orderingApp) ID() uint8 {
+	return app.id
+}
+
+func (app *orderingApp) Methods() []transaction.MethodName {
+	return app.methods
+}
+
+func (app *orderingApp) Blessed() bool {
+	return false
+}
+
+func (app *orderingApp) Dependencies() []string {
+	return nil
+}
+
+func (app *orderingApp) QueryFactory() interface{} {
+	return nil
+}
+
+func (app *orderingApp) OnRegister(state api.ApplicationState) {
+}
+
+func (app *orderingApp) OnCleanup() {
+}
+
+func (app *orderingApp) emit(ctx *api.Context, key []byte) {
+	ctx.EmitEvent(api.NewEventBuilder(app.name).Attribute(key, []byte(app.name)))
+}
+
+func (app *orderingApp) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
+	app.emit(ctx, keyExecuteTx)
+	return nil
+}
+
+func (app *orderingApp) ForeignExecuteTx(ctx *api.Context, other api.Application, tx *transaction.Transaction) error {
+	app.emit(ctx, keyForeignTx)
+	return nil
+}
+
+func (app *orderingApp) InitChain(ctx *api.Context, req types.RequestInitChain, doc *genesis.Document) error {
+	app.emit(ctx, keyInitChain)
+	return nil
+}
+
+func (app *orderingApp) BeginBlock(ctx *api.Context, req types.RequestBeginBlock) error {
+	app.emit(ctx, keyBeginBlock)
+	return nil
+}
+
+func (app *orderingApp) EndBlock(ctx *api.Context, req types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	app.emit(ctx, keyEndBlock)
+	return types.ResponseEndBlock{}, nil
+}
+
+type orderedEvent struct {
+	app string
+	key string
+}
+
+func orderedEvents(events []types.Event) []orderedEvent {
+	var evs []orderedEvent
+	for _, ev := range events {
+		for _, attr := range ev.Attributes {
+			evs = append(evs, orderedEvent{app: string(attr.Value), key: string(attr.Key)})
+		}
+	}
+	return evs
+}
+
+func TestEventOrdering(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-core tests")
+
+	dir, err := ioutil.TempDir("", "abci-mux.test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux, err := newABCIMux(ctx, upgrade.NewDummyUpgradeManager(), &ApplicationConfig{
+		DataDir:             dir,
+		StorageBackend:      "badger",
+		HaltEpochHeight:     math.MaxUint64,
+		MemoryOnlyStorage:   true,
+		DisableCheckpointer: true,
+		InitialHeight:       1,
+	})
+	require.NoError(err, "newABCIMux")
+	defer mux.doCleanup()
+	mux.state.timeSource = &orderingTimeSource{}
+
+	// Register applications in non-lexicographic order.
+	for _, app := range []*orderingApp{
+		{name: "ordering_c", id: 0xf0, methods: []transaction.MethodName{methodOrderingC}},
+		{name: "ordering_a", id: 0xf1},
+		{name: "ordering_b", id: 0xf2},
+	} {
+		require.NoError(mux.doRegister(app), "doRegister")
+	}
+
+	now := time.Now()
+	doc := &genesis.Document{Height: 1, Time: now, ChainID: "test"}
+	rawDoc, err := json.Marshal(doc)
+	require.NoError(err, "json.Marshal")
+	mux.InitChain(types.RequestInitChain{
+		Time:          now,
+		ChainId:       doc.ChainID,
+		AppStateBytes: rawDoc,
+		InitialHeight: doc.Height,
+	})
+
+	signer := memorySigner.NewTestSigner("abci mux event ordering test")
+	sigTx, err := transaction.Sign(signer, transaction.NewTransaction(0, nil, methodOrderingC, nil))
+	require.NoError(err, "transaction.Sign")
+	rawTx := cbor.Marshal(sigTx)
+
+	for height := int64(1); height <= 2; height++ {
+		respBegin := mux.BeginBlock(types.RequestBeginBlock{})
+		respTx := mux.DeliverTx(types.RequestDeliverTx{Tx: rawTx})
+		require.True(respTx.IsOK(), "DeliverTx should succeed: %s", respTx.GetLog())
+		respEnd := mux.EndBlock(types.RequestEndBlock{Height: height})
+		mux.Commit()
+
+		var expectedBegin []orderedEvent
+		if height == 1 {
+			// InitChain events must precede all BeginBlock events of the first block.
+			expectedBegin = append(expectedBegin,
+				orderedEvent{"ordering_a", "init_chain"},
+				orderedEvent{"ordering_b", "init_chain"},
+				orderedEvent{"ordering_c", "init_chain"},
+			)
+		}
+		expectedBegin = append(expectedBegin,
+			orderedEvent{"ordering_a", "begin_block"},
+			orderedEvent{"ordering_b", "begin_block"},
+			orderedEvent{"ordering_c", "begin_block"},
+		)
+		require.Equal(expectedBegin, orderedEvents(respBegin.Events), "BeginBlock events should be ordered (height: %d)", height)
+
+		// The handling application must be invoked before post-tx hooks of the other applications.
+		require.Equal([]orderedEvent{
+			{"ordering_c", "execute_tx"},
+			{"ordering_a", "foreign_tx"},
+			{"ordering_b", "foreign_tx"},
+		}, orderedEvents(respTx.Events), "DeliverTx events should be ordered (height: %d)", height)
+
+		require.Equal([]orderedEvent{
+			{"ordering_a", "end_block"},
+			{"ordering_b", "end_block"},
+			{"ordering_c", "end_block"},
+		}, orderedEvents(respEnd.Events), "EndBlock events should be ordered (height: %d)", height)
+	}
+}
@@ -0,0 +1,149 @@
+package abci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+// upgradeTestTimeSource is a time source that always reports the base epoch.
+type upgradeTestTimeSource struct {
+	epochtime.Backend
+}
+
+func (ts *upgradeTestTimeSource) GetBaseEpoch(ctx context.Context) (epochtime.EpochTime, error) {
+	return 0, nil
+}
+
+func (ts *upgradeTestTimeSource) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return 0, nil
+}
+
+// testUpgrader is an upgrader that performs the consensus upgrade using the given function.
+type testUpgrader struct {
+	upgrade.Backend
+
+	consensusUpgrade func(ctx *api.Context) error
+}
+
+func (u *testUpgrader) ConsensusUpgrade(privateCtx interface{}, currentEpoch epochtime.EpochTime, currentHeight int64) error {
+	return u.consensusUpgrade(privateCtx.(*api.Context))
+}
+
+func TestConsensusUpgradeCheckpoint(t *testing.T) {
+	keyFirst := []byte("upgrade_first")
+	keySecond := []byte("upgrade_second")
+
+	for _, tc := range []struct {
+		name        string
+		afterFirst  func(ctx *api.Context) error
+		expectPanic interface{}
+	}{
+		{
+			name:       "Success",
+			afterFirst: func(ctx *api.Context) error { return nil },
+		},
+		{
+			name:        "Failure",
+			afterFirst:  func(ctx *api.Context) error { return fmt.Errorf("upgrade failed") },
+			expectPanic: "mux: error while trying to perform consensus upgrade: upgrade failed",
+		},
+		{
+			name:        "StopForUpgrade",
+			afterFirst:  func(ctx *api.Context) error { return upgrade.ErrStopForUpgrade },
+			expectPanic: "mux: reached upgrade point",
+		},
+		{
+			name: "NestedCheckpoint",
+			afterFirst: func(ctx *api.Context) error {
+				sc := ctx.StartCheckpoint()
+				defer sc.Close()
+				sc.Commit()
+				return nil
+			},
+			expectPanic: "context: nested checkpoints are not allowed",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			dir, err := ioutil.TempDir("", "abci-mux.test")
+			require.NoError(err, "TempDir")
+			defer os.RemoveAll(dir)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The upgrade handler writes some state, then either completes or fails halfway.
+			upgrader := &testUpgrader{
+				consensusUpgrade: func(ctx *api.Context) error {
+					if err := ctx.State().Insert(ctx, keyFirst, []byte("value")); err != nil {
+						return err
+					}
+					if err := tc.afterFirst(ctx); err != nil {
+						return err
+					}
+					return ctx.State().Insert(ctx, keySecond, []byte("value"))
+				},
+			}
+
+			mux, err := newABCIMux(ctx, upgrader, &ApplicationConfig{
+				DataDir:             dir,
+				StorageBackend:      "badger",
+				HaltEpochHeight:     math.MaxUint64,
+				MemoryOnlyStorage:   true,
+				DisableCheckpointer: true,
+				InitialHeight:       1,
+			})
+			require.NoError(err, "newABCIMux")
+			defer mux.doCleanup()
+			mux.state.timeSource = &upgradeTestTimeSource{}
+
+			now := time.Now()
+			doc := &genesis.Document{Height: 1, Time: now, ChainID: "test"}
+			rawDoc, err := json.Marshal(doc)
+			require.NoError(err, "json.Marshal")
+			mux.InitChain(types.RequestInitChain{
+				Time:          now,
+				ChainId:       doc.ChainID,
+				AppStateBytes: rawDoc,
+				InitialHeight: doc.Height,
+			})
+
+			beginBlock := func() {
+				mux.BeginBlock(types.RequestBeginBlock{})
+			}
+			if tc.expectPanic != nil {
+				require.PanicsWithValue(tc.expectPanic, beginBlock, "BeginBlock should panic")
+			} else {
+				require.NotPanics(beginBlock, "BeginBlock should not panic")
+			}
+
+			// Either all of the upgrade state changes are applied or none are.
+			stateCtx := mux.state.NewContext(api.ContextEndBlock, now)
+			defer stateCtx.Close()
+			for _, key := range [][]byte{keyFirst, keySecond} {
+				value, err := stateCtx.State().Get(stateCtx, key)
+				require.NoError(err, "Get")
+				if tc.expectPanic != nil {
+					require.Nil(value, "state changes of a failed upgrade should be discarded")
+				} else {
+					require.EqualValues([]byte("value"), value, "state changes of a successful upgrade should be applied")
+				}
+			}
+		})
+	}
+}
@@ -158,6 +158,14 @@ func (p *genericPruner) doPrune(ctx context.Context, latestVersion uint64) error
 		)
 
 		err := p.ndb.Prune(ctx, i)
+		if err == nodedb.ErrVersionPinned {
+			// The version is pinned by an open snapshot, retry on the next prune.
+			p.logger.Debug("Prune: stopping at pinned version",
+				"version", i,
+			)
+			p.earliestVersion = i
+			break
+		}
 		switch err {
 		case nil:
 		case nodedb.ErrNotEarliest:
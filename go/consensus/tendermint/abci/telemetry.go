@@ -0,0 +1,146 @@
+package abci
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+const (
+	// unknownModule is the module label used for transactions that could not be decoded.
+	unknownModule = "unknown"
+
+	txResultOk     = "ok"
+	txResultFailed = "failed"
+)
+
+var (
+	abciTxs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_txs",
+			Help: "Number of delivered transactions by module and result.",
+		},
+		[]string{"module", "result"},
+	)
+	abciGasUsed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_gas_used",
+			Help: "Gas used by delivered transactions by module.",
+		},
+		[]string{"module"},
+	)
+	abciEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_events",
+			Help: "Number of emitted events by type.",
+		},
+		[]string{"type"},
+	)
+	abciBlockTxs = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "oasis_abci_block_txs",
+			Help:    "Number of transactions per block.",
+			Buckets: []float64{0, 1, 5, 10, 50, 100, 500, 1000},
+		},
+	)
+	abciBlockGasUsed = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "oasis_abci_block_gas_used",
+			Help:    "Gas used per block.",
+			Buckets: prometheus.ExponentialBuckets(1000, 10, 6),
+		},
+	)
+)
+
+// moduleStats are the per-module transaction statistics of a block.
+type moduleStats struct {
+	txs       uint64
+	failedTxs uint64
+	gasUsed   uint64
+}
+
+// blockStats are the resource usage statistics of a block.
+type blockStats struct {
+	modules map[string]*moduleStats
+	events  map[string]uint64
+}
+
+func (s *blockStats) reset() {
+	s.modules = make(map[string]*moduleStats)
+	s.events = make(map[string]uint64)
+}
+
+func (s *blockStats) addTx(tx *transaction.Transaction, err error, gasUsed transaction.Gas) {
+	module := unknownModule
+	if tx != nil {
+		module = strings.SplitN(string(tx.Method), transaction.MethodSeparator, 2)[0]
+	}
+
+	ms := s.modules[module]
+	if ms == nil {
+		ms = &moduleStats{}
+		s.modules[module] = ms
+	}
+	ms.txs++
+	if err != nil {
+		ms.failedTxs++
+	}
+	ms.gasUsed += uint64(gasUsed)
+}
+
+func (s *blockStats) addEvents(events []types.Event) {
+	for _, ev := range events {
+		s.events[ev.Type]++
+	}
+}
+
+// publish updates the metrics with the block statistics and optionally logs them.
+func (s *blockStats) publish(logger *logging.Logger, height int64, log bool) {
+	var totalTxs, totalGasUsed uint64
+	modules := make([]string, 0, len(s.modules))
+	for module, ms := range s.modules {
+		abciTxs.With(prometheus.Labels{"module": module, "result": txResultOk}).Add(float64(ms.txs - ms.failedTxs))
+		abciTxs.With(prometheus.Labels{"module": module, "result": txResultFailed}).Add(float64(ms.failedTxs))
+		abciGasUsed.With(prometheus.Labels{"module": module}).Add(float64(ms.gasUsed))
+
+		totalTxs += ms.txs
+		totalGasUsed += ms.gasUsed
+		modules = append(modules, module)
+	}
+	eventTypes := make([]string, 0, len(s.events))
+	for evType, count := range s.events {
+		abciEvents.With(prometheus.Labels{"type": evType}).Add(float64(count))
+		eventTypes = append(eventTypes, evType)
+	}
+	abciBlockTxs.Observe(float64(totalTxs))
+	abciBlockGasUsed.Observe(float64(totalGasUsed))
+
+	if !log {
+		return
+	}
+
+	keyvals := []interface{}{
+		"height", height,
+		"txs", totalTxs,
+		"gas_used", totalGasUsed,
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		ms := s.modules[module]
+		keyvals = append(keyvals,
+			"txs_"+module, ms.txs,
+			"failed_txs_"+module, ms.failedTxs,
+			"gas_used_"+module, ms.gasUsed,
+		)
+	}
+	sort.Strings(eventTypes)
+	for _, evType := range eventTypes {
+		keyvals = append(keyvals, "events_"+evType, s.events[evType])
+	}
+	logger.Info("block resource usage", keyvals...)
+}
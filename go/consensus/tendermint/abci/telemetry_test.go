@@ -0,0 +1,34 @@
+package abci
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+func TestBlockStats(t *testing.T) {
+	require := require.New(t)
+
+	var stats blockStats
+	stats.reset()
+
+	stats.addTx(&transaction.Transaction{Method: "staking.Transfer"}, nil, 100)
+	stats.addTx(&transaction.Transaction{Method: "staking.Burn"}, errors.New("failed"), 50)
+	stats.addTx(&transaction.Transaction{Method: "registry.RegisterNode"}, nil, 1000)
+	stats.addTx(nil, errors.New("malformed"), 0)
+	stats.addEvents([]types.Event{{Type: "staking"}, {Type: "staking"}, {Type: "registry"}})
+
+	require.Len(stats.modules, 3, "transactions should be aggregated by module")
+	require.EqualValues(&moduleStats{txs: 2, failedTxs: 1, gasUsed: 150}, stats.modules["staking"])
+	require.EqualValues(&moduleStats{txs: 1, gasUsed: 1000}, stats.modules["registry"])
+	require.EqualValues(&moduleStats{txs: 1, failedTxs: 1}, stats.modules[unknownModule])
+	require.EqualValues(map[string]uint64{"staking": 2, "registry": 1}, stats.events)
+
+	stats.reset()
+	require.Empty(stats.modules, "reset should clear module statistics")
+	require.Empty(stats.events, "reset should clear event statistics")
+}
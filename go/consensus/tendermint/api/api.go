@@ -20,6 +20,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
 	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
@@ -300,7 +301,11 @@ type ServiceClient interface {
 	DeliverBlock(ctx context.Context, height int64) error
 
 	// DeliverEvent delivers an event emitted by the consensus service.
-	DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *types.Event) error
+	//
+	// The idx argument is the position of the event within the block that emitted it. As block
+	// and transaction events may be delivered separately, it should be used by clients that need
+	// to establish the order in which events were emitted.
+	DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *types.Event) error
 
 	// DeliverCommand delivers a command emitted via the command channel.
 	DeliverCommand(ctx context.Context, height int64, cmd interface{}) error
@@ -317,7 +322,7 @@ func (bsc *BaseServiceClient) DeliverBlock(ctx context.Context, height int64) er
 }
 
 // Implements ServiceClient.
-func (bsc *BaseServiceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *types.Event) error {
+func (bsc *BaseServiceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *types.Event) error {
 	return nil
 }
 
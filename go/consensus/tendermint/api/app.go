@@ -1,6 +1,8 @@
 package api
 
 import (
+	"fmt"
+
 	tmabcitypes "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -75,3 +77,42 @@ type Application interface {
 	// Commit is omitted because Applications will work on a cache of
 	// the state bound to the multiplexer.
 }
+
+// PriorityClass is a transaction priority class.
+//
+// Priority classes are used to reserve mempool capacity for transactions that are critical for
+// liveness (e.g., executor commitments and node registrations) so that they can still be
+// submitted when the mempool is saturated with lower priority transactions.
+type PriorityClass uint8
+
+const (
+	// PriorityClassLow is the priority class of generic transactions (e.g., transfers).
+	PriorityClassLow PriorityClass = 0
+	// PriorityClassNormal is the default priority class.
+	PriorityClassNormal PriorityClass = 1
+	// PriorityClassHigh is the priority class of transactions critical for liveness.
+	PriorityClassHigh PriorityClass = 2
+)
+
+// String returns a string representation of the priority class.
+func (c PriorityClass) String() string {
+	switch c {
+	case PriorityClassLow:
+		return "low"
+	case PriorityClassNormal:
+		return "normal"
+	case PriorityClassHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("[unknown priority class: %d]", uint8(c))
+	}
+}
+
+// TransactionPrioritizer is an optional interface that can be implemented by applications to
+// assign priority classes to the methods they handle.
+//
+// Methods of applications not implementing this interface are in PriorityClassNormal.
+type TransactionPrioritizer interface {
+	// PriorityClass returns the priority class of the given method.
+	PriorityClass(method transaction.MethodName) PriorityClass
+}
@@ -92,6 +92,9 @@ type MockApplicationStateConfig struct {
 	BaseEpoch    epochtime.EpochTime
 	CurrentEpoch epochtime.EpochTime
 	EpochChanged bool
+	// EpochInterval is the epoch interval (in blocks). If non-zero, GetEpoch
+	// derives epochs from block heights instead of returning CurrentEpoch.
+	EpochInterval int64
 
 	MaxBlockGas transaction.Gas
 	MinGasPrice *quantity.Quantity
@@ -134,6 +137,9 @@ func (ms *mockApplicationState) GetBaseEpoch() (epochtime.EpochTime, error) {
 }
 
 func (ms *mockApplicationState) GetEpoch(ctx context.Context, blockHeight int64) (epochtime.EpochTime, error) {
+	if ms.cfg.EpochInterval > 0 {
+		return ms.cfg.BaseEpoch + epochtime.EpochTime(blockHeight/ms.cfg.EpochInterval), nil
+	}
 	return ms.cfg.CurrentEpoch, nil
 }
 
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"sync"
+
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
+)
+
+// DefaultEventTrackerWindow is the default number of most recent heights for which an event
+// tracker remembers the delivered events.
+const DefaultEventTrackerWindow = 16
+
+// EventTracker provides replay protection for events delivered to service clients.
+//
+// The same event may be delivered multiple times, e.g., in case a query is resubscribed or the
+// event matches multiple queries. As block and transaction events are delivered via separate
+// subscriptions, events are not guaranteed to be delivered in order, so the tracker remembers the
+// positions of all events delivered at the most recent heights. Events at heights older than the
+// retained window cannot be checked and are always considered new.
+type EventTracker struct {
+	sync.Mutex
+
+	window     int64
+	lastHeight int64
+	seen       map[int64]map[consensusEvents.Index]bool
+}
+
+// Observe records the delivery of the event at the given height and position within the block
+// and returns true iff the event has not been delivered before.
+func (et *EventTracker) Observe(height int64, idx consensusEvents.Index) bool {
+	et.Lock()
+	defer et.Unlock()
+
+	if height <= et.lastHeight-et.window {
+		return true
+	}
+
+	seen := et.seen[height]
+	if seen == nil {
+		seen = make(map[consensusEvents.Index]bool)
+		et.seen[height] = seen
+	}
+	if seen[idx] {
+		return false
+	}
+	seen[idx] = true
+
+	if height > et.lastHeight {
+		et.lastHeight = height
+		for h := range et.seen {
+			if h <= et.lastHeight-et.window {
+				delete(et.seen, h)
+			}
+		}
+	}
+	return true
+}
+
+// LastHeight returns the most recent height at which an event has been delivered.
+func (et *EventTracker) LastHeight() int64 {
+	et.Lock()
+	defer et.Unlock()
+
+	return et.lastHeight
+}
+
+// NewEventTracker creates a new event tracker remembering delivered events for the given number
+// of most recent heights.
+func NewEventTracker(window int64) *EventTracker {
+	if window <= 0 {
+		window = DefaultEventTrackerWindow
+	}
+	return &EventTracker{
+		window: window,
+		seen:   make(map[int64]map[consensusEvents.Index]bool),
+	}
+}
+
+// HeightWatermark tracks the last consensus height at which a service client has processed
+// finalized events (e.g., for a given runtime).
+//
+// Finalized events are processed at most once per height. Processing may be interrupted (e.g.,
+// by a restart or an error) in which case the heights following the watermark need to be
+// reindexed before further events are processed.
+type HeightWatermark struct {
+	height      int64
+	reindexDone bool
+}
+
+// Height returns the last processed height.
+func (w *HeightWatermark) Height() int64 {
+	return w.height
+}
+
+// ShouldProcess returns true iff events at the given height have not yet been processed.
+func (w *HeightWatermark) ShouldProcess(height int64) bool {
+	return height > w.height
+}
+
+// Advance marks all heights up to and including the given height as processed.
+func (w *HeightWatermark) Advance(height int64) {
+	if height > w.height {
+		w.height = height
+	}
+}
+
+// NeedsReindex returns true iff the heights following the watermark need to be reindexed
+// before further events are processed.
+func (w *HeightWatermark) NeedsReindex() bool {
+	return !w.reindexDone
+}
+
+// MarkReindexed records that reindexing has been completed.
+func (w *HeightWatermark) MarkReindexed() {
+	w.reindexDone = true
+}
+
+// RequestReindex requests reindexing before further events are processed.
+func (w *HeightWatermark) RequestReindex() {
+	w.reindexDone = false
+}
+
+// NewHeightWatermark creates a new height watermark with the given last processed height.
+//
+// Reindexing is initially requested.
+func NewHeightWatermark(height int64) HeightWatermark {
+	return HeightWatermark{height: height}
+}
+
+// ReindexStartHeight returns the first height that needs to be reindexed given the last indexed
+// height. Heights that have already been pruned or precede the genesis height are skipped.
+func ReindexStartHeight(lastIndexedHeight, lastRetainedHeight, genesisHeight int64) int64 {
+	// +1 since we want the last non-seen height.
+	height := lastIndexedHeight + 1
+	if height < lastRetainedHeight {
+		height = lastRetainedHeight
+	}
+	if height < genesisHeight {
+		height = genesisHeight
+	}
+	return height
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
+)
+
+func TestEventTracker(t *testing.T) {
+	require := require.New(t)
+
+	beginIdx := consensusEvents.NewBlockIndex(consensusEvents.PhaseBeginBlock, 0)
+	endIdx := consensusEvents.NewBlockIndex(consensusEvents.PhaseEndBlock, 0)
+	txIdx := consensusEvents.NewTxIndex(0, 0)
+
+	et := NewEventTracker(4)
+	require.EqualValues(0, et.LastHeight(), "initial last height should be zero")
+
+	// New events should be observed once.
+	require.True(et.Observe(10, beginIdx), "new event should be observed")
+	require.False(et.Observe(10, beginIdx), "duplicate event should be ignored")
+	require.True(et.Observe(10, endIdx), "event at a different position should be observed")
+	require.True(et.Observe(10, txIdx), "transaction event should be observed")
+	require.False(et.Observe(10, txIdx), "duplicate transaction event should be ignored")
+	require.EqualValues(10, et.LastHeight(), "last height should be updated")
+
+	// Out-of-order delivery within the window.
+	require.True(et.Observe(12, beginIdx), "new event should be observed")
+	require.True(et.Observe(11, txIdx), "out-of-order event should be observed")
+	require.False(et.Observe(11, txIdx), "duplicate out-of-order event should be ignored")
+	require.False(et.Observe(10, endIdx), "duplicate event within the window should be ignored")
+	require.EqualValues(12, et.LastHeight(), "last height should not go backwards")
+
+	// Events at heights that fall out of the window are always considered new.
+	require.True(et.Observe(14, beginIdx), "new event should be observed")
+	require.True(et.Observe(10, beginIdx), "event outside the window should be observed")
+	require.True(et.Observe(10, beginIdx), "event outside the window should always be observed")
+	require.False(et.Observe(11, txIdx), "duplicate event within the window should be ignored")
+	require.Len(et.seen, 3, "heights outside the window should be pruned")
+	require.NotContains(et.seen, int64(10), "heights outside the window should be pruned")
+
+	// Default window.
+	et = NewEventTracker(0)
+	require.EqualValues(DefaultEventTrackerWindow, et.window, "default window should be used")
+}
+
+func TestHeightWatermark(t *testing.T) {
+	require := require.New(t)
+
+	w := NewHeightWatermark(10)
+	require.EqualValues(10, w.Height(), "initial height should be correct")
+	require.True(w.NeedsReindex(), "reindex should initially be requested")
+	require.False(w.ShouldProcess(9), "processed heights should be skipped")
+	require.False(w.ShouldProcess(10), "processed heights should be skipped")
+	require.True(w.ShouldProcess(11), "new heights should be processed")
+
+	w.MarkReindexed()
+	require.False(w.NeedsReindex(), "reindex should be done")
+
+	w.Advance(15)
+	require.EqualValues(15, w.Height(), "height should advance")
+	w.Advance(12)
+	require.EqualValues(15, w.Height(), "height should not go backwards")
+	require.False(w.ShouldProcess(15), "processed heights should be skipped")
+
+	w.RequestReindex()
+	require.True(w.NeedsReindex(), "reindex should be requested")
+}
+
+func TestReindexStartHeight(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		lastIndexed  int64
+		lastRetained int64
+		genesis      int64
+		expected     int64
+	}{
+		{0, 1, 1, 1},
+		{10, 1, 1, 11},
+		{10, 20, 1, 20},
+		{0, 1, 100, 100},
+		{150, 120, 100, 151},
+	} {
+		require.Equal(tc.expected, ReindexStartHeight(tc.lastIndexed, tc.lastRetained, tc.genesis),
+			"ReindexStartHeight(%d, %d, %d)", tc.lastIndexed, tc.lastRetained, tc.genesis)
+	}
+}
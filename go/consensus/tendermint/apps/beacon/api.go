@@ -22,4 +22,8 @@ var (
 	// KeyGenerated is the ABCI event attribute key for the new
 	// beacons (value is a CBOR serialized beacon.GenerateEvent).
 	KeyGenerated = []byte("generated")
+
+	// KeyPendingEpoch is the ABCI event attribute key for pending epoch
+	// transitions (value is a CBOR serialized beacon.PendingEpochEvent).
+	KeyPendingEpoch = []byte("pending_epoch")
 )
@@ -0,0 +1,41 @@
+package beacon
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/abci/types"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+// beaconBackend is a beacon entropy generation protocol.
+type beaconBackend interface {
+	// OnBeaconEpochChange generates the beacon for the given (new) epoch.
+	OnBeaconEpochChange(
+		ctx *api.Context,
+		params *beacon.ConsensusParameters,
+		epoch epochtime.EpochTime,
+		req types.RequestBeginBlock,
+	) ([]byte, error)
+
+	// ExecuteTx executes a protocol specific transaction.
+	ExecuteTx(ctx *api.Context, params *beacon.ConsensusParameters, tx *transaction.Transaction) error
+}
+
+// newBackend returns the beacon backend selected by the consensus parameters.
+func newBackend(params *beacon.ConsensusParameters) (beaconBackend, error) {
+	switch params.BackendName() {
+	case beacon.BackendInsecure:
+		return &backendInsecure{}, nil
+	case beacon.BackendVRF:
+		if params.VRFParameters == nil {
+			return nil, fmt.Errorf("tendermint/beacon: VRF backend parameters missing")
+		}
+		return &backendVRF{}, nil
+	default:
+		return nil, fmt.Errorf("tendermint/beacon: unsupported backend: '%s'", params.Backend)
+	}
+}
@@ -0,0 +1,85 @@
+package beacon
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/abci/types"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+var (
+	prodEntropyCtx  = []byte("EkB-tmnt")
+	DebugEntropyCtx = []byte("Ekb-Dumm")
+
+	_ beaconBackend = (*backendInsecure)(nil)
+)
+
+// backendInsecure is the insecure beacon backend which derives entropy from
+// block commit hashes.
+type backendInsecure struct{}
+
+func (b *backendInsecure) OnBeaconEpochChange(
+	ctx *api.Context,
+	params *beacon.ConsensusParameters,
+	epoch epochtime.EpochTime,
+	req types.RequestBeginBlock,
+) ([]byte, error) {
+	var entropyCtx, entropy []byte
+
+	switch params.DebugDeterministic {
+	case false:
+		entropyCtx = prodEntropyCtx
+
+		var err error
+		if entropy, err = insecureEntropy(ctx, req); err != nil {
+			return nil, err
+		}
+	case true:
+		// UNSAFE/DEBUG - Deterministic beacon.
+		entropyCtx = DebugEntropyCtx
+		// We're setting this random seed so that we have suitable committee schedules for Byzantine E2E scenarios,
+		// where we want nodes to be scheduled for only one committee. The permutations derived from this on the first
+		// epoch need to have (i) an index that's compute worker only and (ii) an index that's merge worker only. See
+		// /go/oasis-test-runner/scenario/e2e/byzantine.go for the permutations generated from this seed. These
+		// permutations are generated independently of the deterministic node IDs.
+		entropy = []byte("If you change this, you will fuck up the byzantine tests!!")
+	}
+
+	return GetBeacon(epoch, entropyCtx, entropy), nil
+}
+
+func (b *backendInsecure) ExecuteTx(ctx *api.Context, params *beacon.ConsensusParameters, tx *transaction.Transaction) error {
+	return beacon.ErrMethodNotSupported
+}
+
+// insecureEntropy returns entropy derived from the block commit hashes.
+func insecureEntropy(ctx *api.Context, req types.RequestBeginBlock) ([]byte, error) {
+	var entropy []byte
+
+	height := ctx.BlockHeight()
+	if height <= ctx.InitialHeight() {
+		// No meaningful previous commit, use the block hash.  This isn't
+		// fantastic, but it's only for one epoch.
+		ctx.Logger().Debug("onBeaconEpochChange: using block hash as entropy")
+		entropy = req.Hash
+	} else {
+		// Use the previous commit hash as the entropy input, under the theory
+		// that the merkle root of all the commits that went into the last
+		// block is harder for any single validator to game than the block
+		// hash.
+		//
+		// TODO: This still isn't ideal, and an entirely different beacon
+		// entropy source should be written, be it based around SCRAPE,
+		// a VDF, naive commit-reveal, or even just calling an SGX enclave.
+		ctx.Logger().Debug("onBeaconEpochChange: using commit hash as entropy")
+		entropy = req.Header.GetLastCommitHash()
+	}
+	if len(entropy) == 0 {
+		return nil, fmt.Errorf("onBeaconEpochChange: failed to obtain entropy")
+	}
+	return entropy, nil
+}
@@ -0,0 +1,168 @@
+package beacon
+
+import (
+	"errors"
+
+	"github.com/tendermint/tendermint/abci/types"
+	"golang.org/x/crypto/sha3"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon/state"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+var (
+	vrfEntropyCtx = []byte("EkB-vrf_")
+
+	_ beaconBackend = (*backendVRF)(nil)
+)
+
+// backendVRF is the beacon backend which derives entropy from VRF proofs
+// submitted by validator nodes during the previous epoch.
+//
+// Each validator can contribute a single VRF output per epoch, computed
+// over an input chained to the previous beacon. The beacon is derived from
+// all submitted outputs. Since validators can still bias the beacon by
+// withholding their proofs, this backend is experimental.
+type backendVRF struct{}
+
+func (b *backendVRF) OnBeaconEpochChange(
+	ctx *api.Context,
+	params *beacon.ConsensusParameters,
+	epoch epochtime.EpochTime,
+	req types.RequestBeginBlock,
+) ([]byte, error) {
+	state := beaconState.NewMutableState(ctx.State())
+
+	outputs, err := state.VRFOutputs(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if err = state.PruneVRFOutputs(ctx, epoch); err != nil {
+		return nil, err
+	}
+
+	// Outputs are ordered by VRF public key so the result does not depend on
+	// the order in which the proofs were submitted.
+	h := sha3.New256()
+	for _, output := range outputs {
+		_, _ = h.Write(output)
+	}
+
+	if numProofs := uint64(len(outputs)); numProofs < params.VRFParameters.MinProofs {
+		ctx.Logger().Warn("onBeaconEpochChange: not enough VRF proofs, using insecure entropy",
+			"epoch", epoch,
+			"num_proofs", numProofs,
+			"min_proofs", params.VRFParameters.MinProofs,
+			logging.LogEvent, beacon.LogEventInsufficientVRFProofs,
+		)
+
+		var entropy []byte
+		if entropy, err = insecureEntropy(ctx, req); err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(entropy)
+	}
+
+	return GetBeacon(epoch, vrfEntropyCtx, h.Sum(nil)), nil
+}
+
+func (b *backendVRF) ExecuteTx(ctx *api.Context, params *beacon.ConsensusParameters, tx *transaction.Transaction) error {
+	switch tx.Method {
+	case beacon.MethodVRFProve:
+		var prove beacon.VRFProve
+		if err := cbor.Unmarshal(tx.Body, &prove); err != nil {
+			return beacon.ErrInvalidArgument
+		}
+		return b.vrfProve(ctx, params, &prove)
+	default:
+		return beacon.ErrMethodNotSupported
+	}
+}
+
+func (b *backendVRF) vrfProve(ctx *api.Context, params *beacon.ConsensusParameters, prove *beacon.VRFProve) error {
+	// Charge gas for this transaction.
+	if err := ctx.Gas().UseGas(1, beacon.GasOpVRFProve, params.VRFParameters.GasCosts); err != nil {
+		return err
+	}
+
+	// Proofs are submitted during the epoch preceding the one the beacon is
+	// generated for.
+	epoch, err := ctx.AppState().GetCurrentEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	if prove.Epoch != epoch+1 {
+		ctx.Logger().Debug("VRFProve: proof for unexpected epoch",
+			"epoch", prove.Epoch,
+			"current_epoch", epoch,
+		)
+		return beacon.ErrInvalidArgument
+	}
+
+	// Only registered validator nodes with a VRF key may contribute.
+	regState := registryState.NewMutableState(ctx.State())
+	n, err := regState.Node(ctx, ctx.TxSigner())
+	if err != nil {
+		ctx.Logger().Debug("VRFProve: failed to get node",
+			"err", err,
+			"node_id", ctx.TxSigner(),
+		)
+		return beacon.ErrInvalidArgument
+	}
+	if n.IsExpired(uint64(epoch)) || !n.HasRoles(node.RoleValidator) || n.VRF == nil {
+		ctx.Logger().Debug("VRFProve: node not eligible to submit proofs",
+			"node_id", n.ID,
+		)
+		return beacon.ErrInvalidArgument
+	}
+	nodeStatus, err := regState.NodeStatus(ctx, n.ID)
+	if err != nil {
+		return err
+	}
+	if nodeStatus.IsFrozen() {
+		ctx.Logger().Debug("VRFProve: node is frozen",
+			"node_id", n.ID,
+		)
+		return beacon.ErrInvalidArgument
+	}
+
+	state := beaconState.NewMutableState(ctx.State())
+	exists, err := state.VRFOutputExists(ctx, prove.Epoch, n.VRF.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return beacon.ErrDuplicateProof
+	}
+
+	prevBeacon, err := state.Beacon(ctx)
+	switch {
+	case err == nil:
+	case errors.Is(err, beacon.ErrBeaconNotAvailable):
+		// No beacon has been generated yet.
+	default:
+		return err
+	}
+
+	output, err := n.VRF.ID.Verify(beacon.VRFAlpha(prove.Epoch, prevBeacon), prove.Pi)
+	if err != nil {
+		ctx.Logger().Debug("VRFProve: invalid proof",
+			"err", err,
+			"node_id", n.ID,
+		)
+		return beacon.ErrInvalidProof
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	return state.SetVRFOutput(ctx, prove.Epoch, n.VRF.ID, output)
+}
@@ -0,0 +1,155 @@
+package beacon
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/vrf"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon/state"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+func TestBackendVRF(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		BlockHeight:  10,
+		CurrentEpoch: 42,
+	})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	params := &beacon.ConsensusParameters{
+		Backend: beacon.BackendVRF,
+		VRFParameters: &beacon.VRFParameters{
+			MinProofs: 2,
+		},
+	}
+	backend, err := newBackend(params)
+	require.NoError(err, "newBackend")
+
+	state := beaconState.NewMutableState(ctx.State())
+	regState := registryState.NewMutableState(ctx.State())
+
+	prevBeacon := GetBeacon(42, prodEntropyCtx, []byte("previous beacon entropy"))
+	err = state.SetBeacon(ctx, prevBeacon)
+	require.NoError(err, "SetBeacon")
+
+	// Register validator nodes.
+	var (
+		nodeSigners []signature.Signer
+		vrfKeys     []*vrf.PrivateKey
+	)
+	for i, roles := range []node.RolesMask{node.RoleValidator, node.RoleValidator, node.RoleComputeWorker} {
+		nodeSigner := memorySigner.NewTestSigner("beacon vrf test node signer " + string(rune('a'+i)))
+		vrfKey, kerr := vrf.GenerateKey(rand.Reader)
+		require.NoError(kerr, "GenerateKey")
+
+		nod := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			Expiration: 100,
+			VRF: &node.VRFInfo{
+				ID: vrfKey.Public(),
+			},
+			Roles: roles,
+		}
+		sigNode, kerr := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, nod)
+		require.NoError(kerr, "MultiSignNode")
+		err = regState.SetNode(ctx, nil, nod, sigNode)
+		require.NoError(err, "SetNode")
+		err = regState.SetNodeStatus(ctx, nod.ID, &registry.NodeStatus{})
+		require.NoError(err, "SetNodeStatus")
+
+		nodeSigners = append(nodeSigners, nodeSigner)
+		vrfKeys = append(vrfKeys, vrfKey)
+	}
+
+	prove := func(signer signature.Signer, vrfKey *vrf.PrivateKey, epoch epochtime.EpochTime) error {
+		pi, perr := vrfKey.Prove(beacon.VRFAlpha(epoch, prevBeacon))
+		require.NoError(perr, "Prove")
+
+		ctx.SetTxSigner(signer.Public())
+		tx := beacon.NewVRFProveTx(0, nil, &beacon.VRFProve{Epoch: epoch, Pi: pi})
+		return backend.ExecuteTx(ctx, params, tx)
+	}
+
+	// Proofs for the wrong epoch should be rejected.
+	err = prove(nodeSigners[0], vrfKeys[0], 42)
+	require.True(errors.Is(err, beacon.ErrInvalidArgument), "proof for the current epoch")
+	err = prove(nodeSigners[0], vrfKeys[0], 44)
+	require.True(errors.Is(err, beacon.ErrInvalidArgument), "proof for a future epoch")
+	// Proofs from non-validators should be rejected.
+	err = prove(nodeSigners[2], vrfKeys[2], 43)
+	require.True(errors.Is(err, beacon.ErrInvalidArgument), "proof from a non-validator")
+	// Proofs made with a different key should be rejected.
+	err = prove(nodeSigners[0], vrfKeys[1], 43)
+	require.True(errors.Is(err, beacon.ErrInvalidProof), "proof made with a different key")
+
+	// Valid proofs should be accepted once per key.
+	err = prove(nodeSigners[0], vrfKeys[0], 43)
+	require.NoError(err, "valid proof")
+	err = prove(nodeSigners[0], vrfKeys[0], 43)
+	require.True(errors.Is(err, beacon.ErrDuplicateProof), "duplicate proof")
+	err = prove(nodeSigners[1], vrfKeys[1], 43)
+	require.NoError(err, "valid proof")
+
+	outputs, err := state.VRFOutputs(ctx, 43)
+	require.NoError(err, "VRFOutputs")
+	require.Len(outputs, 2, "both outputs should be stored")
+
+	bctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer bctx.Close()
+
+	newReq := func(commitHash string) types.RequestBeginBlock {
+		return types.RequestBeginBlock{
+			Hash: []byte("block hash"),
+			Header: tmproto.Header{
+				LastCommitHash: []byte(commitHash),
+			},
+		}
+	}
+
+	// With enough proofs, the beacon should only depend on the VRF outputs.
+	vrfBeacon, err := backend.OnBeaconEpochChange(bctx, params, 43, newReq("commit hash a"))
+	require.NoError(err, "OnBeaconEpochChange")
+	require.Len(vrfBeacon, beacon.BeaconSize, "beacon should have the correct size")
+
+	outputs, err = state.VRFOutputs(ctx, 43)
+	require.NoError(err, "VRFOutputs")
+	require.Empty(outputs, "outputs should be pruned after use")
+
+	for _, id := range []int{0, 1} {
+		pi, perr := vrfKeys[id].Prove(beacon.VRFAlpha(43, prevBeacon))
+		require.NoError(perr, "Prove")
+		output, perr := vrfKeys[id].Public().Verify(beacon.VRFAlpha(43, prevBeacon), pi)
+		require.NoError(perr, "Verify")
+		err = state.SetVRFOutput(ctx, 43, vrfKeys[id].Public(), output)
+		require.NoError(err, "SetVRFOutput")
+	}
+	vrfBeacon2, err := backend.OnBeaconEpochChange(bctx, params, 43, newReq("commit hash b"))
+	require.NoError(err, "OnBeaconEpochChange")
+	require.EqualValues(vrfBeacon, vrfBeacon2, "beacon should not depend on insecure entropy")
+
+	// Without enough proofs, insecure entropy should be mixed in.
+	insecureBeacon, err := backend.OnBeaconEpochChange(bctx, params, 43, newReq("commit hash a"))
+	require.NoError(err, "OnBeaconEpochChange")
+	insecureBeacon2, err := backend.OnBeaconEpochChange(bctx, params, 43, newReq("commit hash b"))
+	require.NoError(err, "OnBeaconEpochChange")
+	require.NotEqualValues(insecureBeacon, insecureBeacon2, "beacon should depend on insecure entropy")
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/tendermint/tendermint/abci/types"
 	"golang.org/x/crypto/sha3"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon/state"
@@ -16,10 +18,8 @@ import (
 )
 
 var (
-	prodEntropyCtx  = []byte("EkB-tmnt")
-	DebugEntropyCtx = []byte("Ekb-Dumm")
-
-	_ api.Application = (*beaconApplication)(nil)
+	_ api.Application            = (*beaconApplication)(nil)
+	_ api.TransactionPrioritizer = (*beaconApplication)(nil)
 )
 
 type beaconApplication struct {
@@ -35,13 +35,24 @@ func (app *beaconApplication) ID() uint8 {
 }
 
 func (app *beaconApplication) Methods() []transaction.MethodName {
-	return nil
+	return beacon.Methods
 }
 
 func (app *beaconApplication) Blessed() bool {
 	return false
 }
 
+// Implements api.TransactionPrioritizer.
+func (app *beaconApplication) PriorityClass(method transaction.MethodName) api.PriorityClass {
+	switch method {
+	case beacon.MethodVRFProve:
+		// VRF proofs must be submitted in time for the beacon to make progress.
+		return api.PriorityClassHigh
+	default:
+		return api.PriorityClassNormal
+	}
+}
+
 func (app *beaconApplication) Dependencies() []string {
 	return nil
 }
@@ -55,13 +66,29 @@ func (app *beaconApplication) OnCleanup() {
 
 func (app *beaconApplication) BeginBlock(ctx *api.Context, req types.RequestBeginBlock) error {
 	if changed, beaconEpoch := app.state.EpochChanged(ctx); changed {
-		return app.onBeaconEpochChange(ctx, beaconEpoch, req)
+		if err := app.onBeaconEpochChange(ctx, beaconEpoch, req); err != nil {
+			return err
+		}
 	}
-	return nil
+	return app.emitPendingEpoch(ctx)
 }
 
 func (app *beaconApplication) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
-	return fmt.Errorf("beacon: unexpected transaction")
+	state := beaconState.NewMutableState(ctx.State())
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+
+	backend, err := newBackend(params)
+	if err != nil {
+		return err
+	}
+
+	return backend.ExecuteTx(ctx, params, tx)
 }
 
 func (app *beaconApplication) ForeignExecuteTx(ctx *api.Context, other api.Application, tx *transaction.Transaction) error {
@@ -73,8 +100,6 @@ func (app *beaconApplication) EndBlock(ctx *api.Context, req types.RequestEndBlo
 }
 
 func (app *beaconApplication) onBeaconEpochChange(ctx *api.Context, epoch epochtime.EpochTime, req types.RequestBeginBlock) error {
-	var entropyCtx, entropy []byte
-
 	state := beaconState.NewMutableState(ctx.State())
 	params, err := state.ConsensusParameters(ctx)
 	if err != nil {
@@ -84,54 +109,75 @@ func (app *beaconApplication) onBeaconEpochChange(ctx *api.Context, epoch epocht
 		return err
 	}
 
-	switch params.DebugDeterministic {
-	case false:
-		entropyCtx = prodEntropyCtx
-
-		height := ctx.BlockHeight()
-		if height <= ctx.InitialHeight() {
-			// No meaningful previous commit, use the block hash.  This isn't
-			// fantastic, but it's only for one epoch.
-			ctx.Logger().Debug("onBeaconEpochChange: using block hash as entropy")
-			entropy = req.Hash
-		} else {
-			// Use the previous commit hash as the entropy input, under the theory
-			// that the merkle root of all the commits that went into the last
-			// block is harder for any single validator to game than the block
-			// hash.
-			//
-			// TODO: This still isn't ideal, and an entirely different beacon
-			// entropy source should be written, be it based around SCRAPE,
-			// a VDF, naive commit-reveal, or even just calling an SGX enclave.
-			ctx.Logger().Debug("onBeaconEpochChange: using commit hash as entropy")
-			entropy = req.Header.GetLastCommitHash()
-		}
-		if len(entropy) == 0 {
-			return fmt.Errorf("onBeaconEpochChange: failed to obtain entropy")
-		}
-	case true:
-		// UNSAFE/DEBUG - Deterministic beacon.
-		entropyCtx = DebugEntropyCtx
-		// We're setting this random seed so that we have suitable committee schedules for Byzantine E2E scenarios,
-		// where we want nodes to be scheduled for only one committee. The permutations derived from this on the first
-		// epoch need to have (i) an index that's compute worker only and (ii) an index that's merge worker only. See
-		// /go/oasis-test-runner/scenario/e2e/byzantine.go for the permutations generated from this seed. These
-		// permutations are generated independently of the deterministic node IDs.
-		entropy = []byte("If you change this, you will fuck up the byzantine tests!!")
+	backend, err := newBackend(params)
+	if err != nil {
+		return err
 	}
 
-	b := GetBeacon(epoch, entropyCtx, entropy)
+	b, err := backend.OnBeaconEpochChange(ctx, params, epoch, req)
+	if err != nil {
+		return err
+	}
 
 	ctx.Logger().Debug("onBeaconEpochChange: generated beacon",
 		"epoch", epoch,
 		"beacon", hex.EncodeToString(b),
-		"block_hash", hex.EncodeToString(entropy),
+		"backend", params.BackendName(),
 		"height", ctx.BlockHeight(),
 	)
 
 	return app.onNewBeacon(ctx, b)
 }
 
+// emitPendingEpoch emits a pending epoch transition event in case an epoch
+// transition happens the configured number of blocks after the current block.
+func (app *beaconApplication) emitPendingEpoch(ctx *api.Context) error {
+	state := beaconState.NewMutableState(ctx.State())
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if params.PendingEpochNotice == 0 {
+		return nil
+	}
+
+	// Epochs are derived from block heights, so the epoch transition
+	// height is known in advance.
+	height := ctx.BlockHeight() + 1 + int64(params.PendingEpochNotice)
+	if height-1 <= ctx.InitialHeight() {
+		// There is no transition at the first block after the initial block.
+		return nil
+	}
+	epoch, err := app.state.GetEpoch(ctx, height)
+	if err != nil {
+		return fmt.Errorf("tendermint/beacon: failed to get epoch at height %d: %w", height, err)
+	}
+	prevEpoch, err := app.state.GetEpoch(ctx, height-1)
+	if err != nil {
+		return fmt.Errorf("tendermint/beacon: failed to get epoch at height %d: %w", height-1, err)
+	}
+	if epoch == prevEpoch {
+		return nil
+	}
+
+	ctx.Logger().Debug("emitPendingEpoch: epoch transition pending",
+		"epoch", epoch,
+		"transition_height", height,
+		"height", ctx.BlockHeight()+1,
+	)
+
+	ev := &beacon.PendingEpochEvent{
+		Epoch:  epoch,
+		Height: height,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyPendingEpoch, cbor.Marshal(ev)))
+
+	return nil
+}
+
 func (app *beaconApplication) onNewBeacon(ctx *api.Context, beacon []byte) error {
 	state := beaconState.NewMutableState(ctx.State())
 
@@ -0,0 +1,68 @@
+package beacon
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon/state"
+)
+
+func TestPendingEpoch(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	for _, tc := range []struct {
+		notice      uint64
+		blockHeight int64
+		expected    *beacon.PendingEpochEvent
+	}{
+		{0, 6, nil},
+		{3, 5, nil},
+		{3, 6, &beacon.PendingEpochEvent{Epoch: 1, Height: 10}},
+		{3, 7, nil},
+		{1, 18, &beacon.PendingEpochEvent{Epoch: 2, Height: 20}},
+	} {
+		appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+			BlockHeight:   tc.blockHeight,
+			EpochInterval: 10,
+		})
+		ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+
+		state := beaconState.NewMutableState(ctx.State())
+		err := state.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+			PendingEpochNotice: tc.notice,
+		})
+		require.NoError(err, "SetConsensusParameters")
+
+		app := &beaconApplication{state: appState}
+		err = app.emitPendingEpoch(ctx)
+		require.NoError(err, "emitPendingEpoch")
+
+		var events []*beacon.PendingEpochEvent
+		for _, ev := range ctx.GetEvents() {
+			for _, pair := range ev.GetAttributes() {
+				if !bytes.Equal(pair.GetKey(), KeyPendingEpoch) {
+					continue
+				}
+				var pev beacon.PendingEpochEvent
+				err = cbor.Unmarshal(pair.GetValue(), &pev)
+				require.NoError(err, "malformed pending epoch event")
+				events = append(events, &pev)
+			}
+		}
+		ctx.Close()
+
+		if tc.expected == nil {
+			require.Empty(events, "no pending epoch event should be emitted (notice: %d height: %d)", tc.notice, tc.blockHeight)
+			continue
+		}
+		require.Len(events, 1, "pending epoch event should be emitted (notice: %d height: %d)", tc.notice, tc.blockHeight)
+		require.Equal(tc.expected, events[0])
+	}
+}
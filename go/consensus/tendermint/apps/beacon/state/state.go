@@ -1,14 +1,17 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/vrf"
 	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 )
 
@@ -21,6 +24,12 @@ var (
 	//
 	// Value is CBOR-serialized beacon.ConsensusParameters.
 	parametersKeyFmt = keyformat.New(0x41)
+	// vrfOutputKeyFmt is the key format used for VRF outputs submitted for
+	// the next beacon.
+	//
+	// Key format is: 0x42 <epoch (uint64)> <VRF public key (vrf.PublicKey)>.
+	// Value is the raw VRF output.
+	vrfOutputKeyFmt = keyformat.New(0x42, uint64(0), &vrf.PublicKey{})
 )
 
 // ImmutableState is the immutable beacon state wrapper.
@@ -65,6 +74,33 @@ func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*beacon.Conse
 	return &params, nil
 }
 
+// VRFOutputExists checks whether a VRF output for the given key has already
+// been submitted for the beacon of the given epoch.
+func (s *ImmutableState) VRFOutputExists(ctx context.Context, epoch epochtime.EpochTime, id vrf.PublicKey) (bool, error) {
+	data, err := s.is.Get(ctx, vrfOutputKeyFmt.Encode(uint64(epoch), &id))
+	if err != nil {
+		return false, abciAPI.UnavailableStateError(err)
+	}
+	return data != nil, nil
+}
+
+// VRFOutputs returns all VRF outputs submitted for the beacon of the given
+// epoch, ordered by VRF public key.
+func (s *ImmutableState) VRFOutputs(ctx context.Context, epoch epochtime.EpochTime) ([][]byte, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var outputs [][]byte
+	prefix := vrfOutputKeyFmt.Encode(uint64(epoch))
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		outputs = append(outputs, append([]byte{}, it.Value()...))
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return outputs, nil
+}
+
 // MutableState is a mutable beacon state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -86,6 +122,43 @@ func (s *MutableState) SetConsensusParameters(ctx context.Context, params *beaco
 	return abciAPI.UnavailableStateError(err)
 }
 
+// SetVRFOutput sets the VRF output submitted for the beacon of the given epoch.
+func (s *MutableState) SetVRFOutput(ctx context.Context, epoch epochtime.EpochTime, id vrf.PublicKey, output []byte) error {
+	if l := len(output); l != vrf.OutputSize {
+		return fmt.Errorf("tendermint/beacon: unexpected VRF output size: %d", l)
+	}
+
+	err := s.ms.Insert(ctx, vrfOutputKeyFmt.Encode(uint64(epoch), &id), output)
+	return abciAPI.UnavailableStateError(err)
+}
+
+// PruneVRFOutputs removes all VRF outputs submitted for beacons of epochs
+// up to and including the given epoch.
+func (s *MutableState) PruneVRFOutputs(ctx context.Context, epoch epochtime.EpochTime) error {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	prefix := vrfOutputKeyFmt.Encode()
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var outputEpoch uint64
+		if !vrfOutputKeyFmt.Decode(it.Key(), &outputEpoch) || outputEpoch > uint64(epoch) {
+			break
+		}
+		toDelete = append(toDelete, append([]byte{}, it.Key()...))
+	}
+	if it.Err() != nil {
+		return abciAPI.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
 // NewMutableState creates a new mutable beacon state wrapper.
 func NewMutableState(tree mkvs.KeyValueTree) *MutableState {
 	return &MutableState{
@@ -77,6 +77,22 @@ func (app *keymanagerApplication) InitChain(ctx *tmapi.Context, request types.Re
 		toEmit = append(toEmit, v)
 	}
 
+	for i, v := range st.AttestationEvidence {
+		if v == nil {
+			return fmt.Errorf("InitChain: Attestation evidence index %d is nil", i)
+		}
+		if rtMap[v.ID] == nil {
+			ctx.Logger().Error("InitChain: Attestation evidence for unknown key manager runtime",
+				"id", v.ID,
+			)
+			continue
+		}
+
+		if err := state.SetAttestationEvidence(ctx, v); err != nil {
+			return fmt.Errorf("tendermint/keymanager: failed to set attestation evidence: %w", err)
+		}
+	}
+
 	if len(toEmit) > 0 {
 		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal(toEmit)))
 	}
@@ -95,6 +111,14 @@ func (kq *keymanagerQuerier) Genesis(ctx context.Context) (*keymanager.Genesis,
 		status.Nodes = nil
 	}
 
-	gen := keymanager.Genesis{Statuses: statuses}
+	evidence, err := kq.state.AllAttestationEvidence(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := keymanager.Genesis{
+		Statuses:            statuses,
+		AttestationEvidence: evidence,
+	}
 	return &gen, nil
 }
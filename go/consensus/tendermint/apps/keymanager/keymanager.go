@@ -155,7 +155,15 @@ func (app *keymanagerApplication) onEpochChange(ctx *tmapi.Context, epoch epocht
 			return fmt.Errorf("failed to query key manager status: %w", err)
 		}
 
-		newStatus := app.generateStatus(ctx, rt, oldStatus, nodes)
+		newStatus, evidence := app.generateStatus(ctx, rt, oldStatus, nodes, epoch)
+
+		// Archive the attestation evidence of all nodes in the committee.
+		for _, ev := range evidence {
+			if err = state.ArchiveAttestationEvidence(ctx, ev); err != nil {
+				return fmt.Errorf("failed to archive key manager attestation evidence: %w", err)
+			}
+		}
+
 		if forceEmit || !bytes.Equal(cbor.Marshal(oldStatus), cbor.Marshal(newStatus)) {
 			ctx.Logger().Debug("status updated",
 				"id", newStatus.ID,
@@ -184,7 +192,13 @@ func (app *keymanagerApplication) onEpochChange(ctx *tmapi.Context, epoch epocht
 	return nil
 }
 
-func (app *keymanagerApplication) generateStatus(ctx *tmapi.Context, kmrt *registry.Runtime, oldStatus *api.Status, nodes []*node.Node) *api.Status {
+func (app *keymanagerApplication) generateStatus(
+	ctx *tmapi.Context,
+	kmrt *registry.Runtime,
+	oldStatus *api.Status,
+	nodes []*node.Node,
+	epoch epochtime.EpochTime,
+) (*api.Status, []*api.AttestationEvidence) {
 	status := &api.Status{
 		ID:            kmrt.ID,
 		IsInitialized: oldStatus.IsInitialized,
@@ -199,6 +213,8 @@ func (app *keymanagerApplication) generateStatus(ctx *tmapi.Context, kmrt *regis
 	}
 	policyHash := sha3.Sum256(rawPolicy)
 
+	var evidence []*api.AttestationEvidence
+
 	for _, n := range nodes {
 		if !n.HasRoles(node.RoleKeyManager) {
 			continue
@@ -297,9 +313,10 @@ func (app *keymanagerApplication) generateStatus(ctx *tmapi.Context, kmrt *regis
 		}
 
 		status.Nodes = append(status.Nodes, n.ID)
+		evidence = append(evidence, api.NewAttestationEvidence(kmrt.ID, n.ID, nodeRt, epoch, ctx.Now()))
 	}
 
-	return status
+	return status, evidence
 }
 
 // New constructs a new keymanager application instance.
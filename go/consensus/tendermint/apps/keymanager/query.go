@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	keymanagerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/keymanager/state"
 	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
@@ -13,6 +14,7 @@ import (
 type Query interface {
 	Status(context.Context, common.Namespace) (*keymanager.Status, error)
 	Statuses(context.Context) ([]*keymanager.Status, error)
+	AttestationEvidence(context.Context, common.Namespace, *signature.PublicKey) ([]*keymanager.AttestationEvidence, error)
 	Genesis(context.Context) (*keymanager.Genesis, error)
 }
 
@@ -42,6 +44,14 @@ func (kq *keymanagerQuerier) Statuses(ctx context.Context) ([]*keymanager.Status
 	return kq.state.Statuses(ctx)
 }
 
+func (kq *keymanagerQuerier) AttestationEvidence(
+	ctx context.Context,
+	id common.Namespace,
+	nodeID *signature.PublicKey,
+) ([]*keymanager.AttestationEvidence, error) {
+	return kq.state.AttestationEvidence(ctx, id, nodeID)
+}
+
 func (app *keymanagerApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -1,10 +1,13 @@
 package state
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	"github.com/oasisprotocol/oasis-core/go/keymanager/api"
@@ -16,6 +19,12 @@ import (
 // Value is CBOR-serialized key manager status.
 var statusKeyFmt = keyformat.New(0x70, keyformat.H(&common.Namespace{}))
 
+// attestationEvidenceKeyFmt is the key manager attestation evidence key format.
+//
+// Key format is: 0x71 H(<runtime-id>) H(<node-id>) <evidence-hash>
+// Value is CBOR-serialized attestation evidence.
+var attestationEvidenceKeyFmt = keyformat.New(0x71, keyformat.H(&common.Namespace{}), keyformat.H(&signature.PublicKey{}), &hash.Hash{})
+
 // ImmutableState is the immutable key manager state wrapper.
 type ImmutableState struct {
 	is *abciAPI.ImmutableState
@@ -72,6 +81,64 @@ func (st *ImmutableState) Status(ctx context.Context, id common.Namespace) (*api
 	return &status, nil
 }
 
+// AttestationEvidence returns the archived attestation evidence for the given key manager runtime,
+// optionally restricted to a single node.
+func (st *ImmutableState) AttestationEvidence(
+	ctx context.Context,
+	id common.Namespace,
+	nodeID *signature.PublicKey,
+) ([]*api.AttestationEvidence, error) {
+	it := st.is.NewIterator(ctx)
+	defer it.Close()
+
+	var prefix []byte
+	if nodeID != nil {
+		prefix = attestationEvidenceKeyFmt.Encode(&id, nodeID)
+	} else {
+		prefix = attestationEvidenceKeyFmt.Encode(&id)
+	}
+
+	var evidence []*api.AttestationEvidence
+	for it.Seek(prefix); it.Valid(); it.Next() {
+		if !bytes.HasPrefix(it.Key(), prefix) {
+			break
+		}
+
+		var ev api.AttestationEvidence
+		if err := cbor.Unmarshal(it.Value(), &ev); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		evidence = append(evidence, &ev)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return evidence, nil
+}
+
+// AllAttestationEvidence returns the archived attestation evidence for all key manager runtimes.
+func (st *ImmutableState) AllAttestationEvidence(ctx context.Context) ([]*api.AttestationEvidence, error) {
+	it := st.is.NewIterator(ctx)
+	defer it.Close()
+
+	var evidence []*api.AttestationEvidence
+	for it.Seek(attestationEvidenceKeyFmt.Encode()); it.Valid(); it.Next() {
+		if !attestationEvidenceKeyFmt.Decode(it.Key()) {
+			break
+		}
+
+		var ev api.AttestationEvidence
+		if err := cbor.Unmarshal(it.Value(), &ev); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		evidence = append(evidence, &ev)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return evidence, nil
+}
+
 func NewImmutableState(ctx context.Context, state abciAPI.ApplicationQueryState, version int64) (*ImmutableState, error) {
 	is, err := abciAPI.NewImmutableState(ctx, state, version)
 	if err != nil {
@@ -92,6 +159,34 @@ func (st *MutableState) SetStatus(ctx context.Context, status *api.Status) error
 	return abciAPI.UnavailableStateError(err)
 }
 
+// SetAttestationEvidence sets archived attestation evidence.
+func (st *MutableState) SetAttestationEvidence(ctx context.Context, ev *api.AttestationEvidence) error {
+	h := ev.Hash()
+	err := st.ms.Insert(ctx, attestationEvidenceKeyFmt.Encode(&ev.ID, &ev.NodeID, &h), cbor.Marshal(ev))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// ArchiveAttestationEvidence archives the given attestation evidence. In case evidence for the
+// same attested enclave instance has already been archived, only its last seen epoch and time are
+// updated.
+func (st *MutableState) ArchiveAttestationEvidence(ctx context.Context, ev *api.AttestationEvidence) error {
+	h := ev.Hash()
+	data, err := st.ms.Get(ctx, attestationEvidenceKeyFmt.Encode(&ev.ID, &ev.NodeID, &h))
+	if err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	if data != nil {
+		var existing api.AttestationEvidence
+		if err = cbor.Unmarshal(data, &existing); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+		existing.LastSeenEpoch = ev.LastSeenEpoch
+		existing.LastSeenTime = ev.LastSeenTime
+		ev = &existing
+	}
+	return st.SetAttestationEvidence(ctx, ev)
+}
+
 // NewMutableState creates a new mutable key manager state wrapper.
 func NewMutableState(tree mkvs.KeyValueTree) *MutableState {
 	return &MutableState{
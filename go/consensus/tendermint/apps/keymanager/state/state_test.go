@@ -0,0 +1,99 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/api"
+)
+
+var (
+	nodeSigner1 = memorySigner.NewTestSigner("consensus/tendermint/apps/keymanager/state: node signer 1")
+	nodeSigner2 = memorySigner.NewTestSigner("consensus/tendermint/apps/keymanager/state: node signer 2")
+	rakSigner1  = memorySigner.NewTestSigner("consensus/tendermint/apps/keymanager/state: rak signer 1")
+	rakSigner2  = memorySigner.NewTestSigner("consensus/tendermint/apps/keymanager/state: rak signer 2")
+)
+
+func TestAttestationEvidence(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	var id1, id2 common.Namespace
+	require.NoError(id1.UnmarshalHex("c000000000000000000000000000000000000000000000000000000000000001"), "UnmarshalHex")
+	require.NoError(id2.UnmarshalHex("c000000000000000000000000000000000000000000000000000000000000002"), "UnmarshalHex")
+
+	nodeRt := func(rak signature.Signer) *node.Runtime {
+		return &node.Runtime{
+			Capabilities: node.Capabilities{
+				TEE: &node.CapabilityTEE{
+					Hardware:    node.TEEHardwareIntelSGX,
+					RAK:         rak.Public(),
+					Attestation: []byte("not a valid attestation"),
+				},
+			},
+		}
+	}
+
+	// Archive evidence for multiple nodes and key manager runtimes.
+	for _, ev := range []*api.AttestationEvidence{
+		api.NewAttestationEvidence(id1, nodeSigner1.Public(), nodeRt(rakSigner1), 1, now),
+		api.NewAttestationEvidence(id1, nodeSigner2.Public(), nodeRt(rakSigner2), 1, now),
+		api.NewAttestationEvidence(id2, nodeSigner1.Public(), nodeRt(rakSigner1), 1, now),
+	} {
+		require.Nil(ev.TCB, "TCB information should not be available for an invalid attestation")
+		err := s.ArchiveAttestationEvidence(ctx, ev)
+		require.NoError(err, "ArchiveAttestationEvidence")
+	}
+
+	// Seeing the same enclave instance again should only update the last seen fields.
+	later := now.Add(time.Hour)
+	err := s.ArchiveAttestationEvidence(ctx, api.NewAttestationEvidence(id1, nodeSigner1.Public(), nodeRt(rakSigner1), 2, later))
+	require.NoError(err, "ArchiveAttestationEvidence")
+
+	// A new enclave instance (e.g., after a restart) should be archived separately.
+	err = s.ArchiveAttestationEvidence(ctx, api.NewAttestationEvidence(id1, nodeSigner1.Public(), nodeRt(rakSigner2), 3, later))
+	require.NoError(err, "ArchiveAttestationEvidence")
+
+	evidence, err := s.AttestationEvidence(ctx, id1, nil)
+	require.NoError(err, "AttestationEvidence")
+	require.Len(evidence, 3, "all evidence for the runtime should be returned")
+
+	nodeID := nodeSigner1.Public()
+	evidence, err = s.AttestationEvidence(ctx, id1, &nodeID)
+	require.NoError(err, "AttestationEvidence")
+	require.Len(evidence, 2, "all evidence for the node should be returned")
+	for _, ev := range evidence {
+		require.EqualValues(id1, ev.ID, "evidence should be for the queried runtime")
+		require.EqualValues(nodeID, ev.NodeID, "evidence should be for the queried node")
+		switch ev.RAK {
+		case rakSigner1.Public():
+			require.EqualValues(1, ev.FirstSeenEpoch, "first seen epoch should not be updated")
+			require.EqualValues(now.Unix(), ev.FirstSeenTime, "first seen time should not be updated")
+			require.EqualValues(2, ev.LastSeenEpoch, "last seen epoch should be updated")
+			require.EqualValues(later.Unix(), ev.LastSeenTime, "last seen time should be updated")
+		case rakSigner2.Public():
+			require.EqualValues(3, ev.FirstSeenEpoch, "first seen epoch should be correct")
+			require.EqualValues(3, ev.LastSeenEpoch, "last seen epoch should be correct")
+		default:
+			require.Fail("unexpected evidence", "rak: %s", ev.RAK)
+		}
+	}
+
+	evidence, err = s.AllAttestationEvidence(ctx)
+	require.NoError(err, "AllAttestationEvidence")
+	require.Len(evidence, 4, "all evidence should be returned")
+	require.NoError(api.SanityCheckAttestationEvidence(evidence), "archived evidence should pass sanity checks")
+}
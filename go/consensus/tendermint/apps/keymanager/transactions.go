@@ -73,13 +73,22 @@ func (app *keymanagerApplication) updatePolicy(
 	// TODO: It would be possible to update the cohort on each
 	// node-reregistration, but I'm not sure how often the policy
 	// will get updated.
+	epoch, err := app.state.GetCurrentEpoch(ctx)
+	if err != nil {
+		return err
+	}
 	nodes, _ := regState.Nodes(ctx)
 	registry.SortNodeList(nodes)
 	oldStatus.Policy = sigPol
-	newStatus := app.generateStatus(ctx, rt, oldStatus, nodes)
+	newStatus, evidence := app.generateStatus(ctx, rt, oldStatus, nodes, epoch)
 	if err := state.SetStatus(ctx, newStatus); err != nil {
 		panic(fmt.Errorf("failed to set keymanager status: %w", err))
 	}
+	for _, ev := range evidence {
+		if err := state.ArchiveAttestationEvidence(ctx, ev); err != nil {
+			panic(fmt.Errorf("failed to archive keymanager attestation evidence: %w", err))
+		}
+	}
 
 	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal([]*api.Status{newStatus})))
 
@@ -47,6 +47,20 @@ var (
 	// become unfrozen (value is CBOR serialized node ID).
 	KeyNodeUnfrozen = []byte("nodes.unfrozen")
 
+	// KeyNodeSuspended is the ABCI event attribute for when nodes
+	// become suspended by their owning entity (value is CBOR serialized
+	// node ID).
+	KeyNodeSuspended = []byte("nodes.suspended")
+
+	// KeyNodeResumed is the ABCI event attribute for when suspended nodes
+	// are resumed by their owning entity (value is CBOR serialized node ID).
+	KeyNodeResumed = []byte("nodes.resumed")
+
+	// KeyDeprecationWarning is the ABCI event attribute for registrations
+	// of descriptors that use deprecated features (value is a CBOR
+	// serialized DeprecationWarningEvent).
+	KeyDeprecationWarning = []byte("deprecation.warning")
+
 	// KeyRegistryNodeListEpoch is the ABCI event attribute for
 	// registry epochs.
 	KeyRegistryNodeListEpoch = []byte("nodes.epoch")
@@ -2,17 +2,26 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
+var validateLogger = logging.GetLogger("tendermint/registry/validate")
+
 // Query is the registry query interface.
 type Query interface {
 	Entity(context.Context, signature.PublicKey) (*entity.Entity, error)
@@ -20,10 +29,14 @@ type Query interface {
 	Node(context.Context, signature.PublicKey) (*node.Node, error)
 	NodeByConsensusAddress(context.Context, []byte) (*node.Node, error)
 	NodeStatus(context.Context, signature.PublicKey) (*registry.NodeStatus, error)
+	ValidateNode(context.Context, *node.MultiSignedNode) (*registry.NodeValidationResult, error)
 	Nodes(context.Context) ([]*node.Node, error)
+	FreshNodes(ctx context.Context, window int64) ([]*node.Node, error)
+	StaleNodes(ctx context.Context, epochs epochtime.EpochTime) (*registry.StaleNodes, error)
 	Runtime(context.Context, common.Namespace) (*registry.Runtime, error)
 	Runtimes(ctx context.Context, includeSuspended bool) ([]*registry.Runtime, error)
 	Genesis(context.Context) (*registry.Genesis, error)
+	ConsensusParameters(context.Context) (*registry.ConsensusParameters, error)
 }
 
 // QueryFactory is the registry query factory.
@@ -80,6 +93,139 @@ func (rq *registryQuerier) NodeStatus(ctx context.Context, id signature.PublicKe
 	return rq.state.NodeStatus(ctx, id)
 }
 
+// ValidateNode mirrors the node registration checks performed when executing a RegisterNode
+// transaction, except that it does not stop at the first failure and does not modify any state.
+func (rq *registryQuerier) ValidateNode(ctx context.Context, sigNode *node.MultiSignedNode) (*registry.NodeValidationResult, error) { // nolint: gocyclo
+	var result registry.NodeValidationResult
+
+	// Peek into the to-be-verified node to pull out the owning entity ID.
+	var untrustedNode node.Node
+	if err := cbor.Unmarshal(sigNode.Blob, &untrustedNode); err != nil {
+		result.AddViolation(registry.NodeCheckDescriptor, fmt.Errorf("%w: malformed node descriptor: %s", registry.ErrInvalidArgument, err))
+		return &result, nil
+	}
+
+	params, err := rq.state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consensus parameters: %w", err)
+	}
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	// In case the descriptor fails verification, the remaining checks are performed against the
+	// unverified descriptor so that all failures are reported at once.
+	var (
+		newNode      = &untrustedNode
+		paidRuntimes []*registry.Runtime
+		verified     bool
+	)
+	untrustedEntity, err := rq.state.Entity(ctx, untrustedNode.EntityID)
+	switch err {
+	case nil:
+		var (
+			verifiedNode *node.Node
+			rts          []*registry.Runtime
+		)
+		verifiedNode, rts, err = registry.VerifyRegisterNodeArgs(
+			ctx,
+			params,
+			validateLogger,
+			sigNode,
+			untrustedEntity,
+			time.Now(),
+			false,
+			false,
+			epoch,
+			rq.state,
+			rq.state,
+		)
+		if err != nil {
+			result.AddViolation(registry.NodeCheckDescriptor, err)
+			break
+		}
+		newNode, paidRuntimes, verified = verifiedNode, rts, true
+	case registry.ErrNoSuchEntity:
+		result.AddViolation(registry.NodeCheckEntity, err)
+	default:
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	if !verified {
+		for _, nodeRt := range newNode.Runtimes {
+			rt, rerr := rq.state.Runtime(ctx, nodeRt.ID)
+			if rerr != nil {
+				// Unknown runtimes are already reported by the descriptor check.
+				continue
+			}
+			paidRuntimes = append(paidRuntimes, rt)
+		}
+	}
+
+	// Check runtime's admission policy.
+	for _, rt := range paidRuntimes {
+		aerr := verifyRuntimeAdmission(ctx, rq.state, rt, newNode, epoch)
+		switch {
+		case aerr == nil:
+		case errors.Is(aerr, registry.ErrForbidden):
+			result.AddViolation(registry.NodeCheckRuntimeAdmission, aerr)
+		default:
+			return nil, aerr
+		}
+	}
+
+	// Check the owning entity's node quotas.
+	qerr := verifyEntityNodeQuota(ctx, rq.state, params, newNode, epoch)
+	switch {
+	case qerr == nil:
+	case errors.Is(qerr, registry.ErrEntityNodeQuotaExceeded):
+		result.AddViolation(registry.NodeCheckEntityQuota, qerr)
+	default:
+		return nil, qerr
+	}
+
+	// Ensure node is not expired.
+	if newNode.Expiration <= uint64(epoch) {
+		result.AddViolation(registry.NodeCheckExpiration, registry.ErrNodeExpired)
+	}
+
+	// If the node already exists make sure to verify the node update.
+	existingNode, err := rq.state.Node(ctx, newNode.ID)
+	switch err {
+	case nil:
+		if uerr := registry.VerifyNodeUpdate(validateLogger, existingNode, newNode); uerr != nil {
+			result.AddViolation(registry.NodeCheckUpdate, uerr)
+		}
+	case registry.ErrNoSuchNode:
+	default:
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	// Check that the entity has enough stake for this node registration.
+	if !params.DebugBypassStake {
+		stakeState, serr := stakingState.NewImmutableState(ctx, rq.queryState, rq.height)
+		if serr != nil {
+			return nil, fmt.Errorf("failed to get staking state: %w", serr)
+		}
+		thresholds, serr := stakeState.Thresholds(ctx)
+		if serr != nil {
+			return nil, fmt.Errorf("failed to get staking thresholds: %w", serr)
+		}
+		acct, serr := stakeState.Account(ctx, staking.NewAddress(newNode.EntityID))
+		if serr != nil {
+			return nil, fmt.Errorf("failed to get entity account: %w", serr)
+		}
+
+		claim := registry.StakeClaimForNode(newNode.ID)
+		nodeThresholds := registry.StakeThresholdsForNode(newNode, paidRuntimes)
+		if serr = acct.Escrow.AddStakeClaim(thresholds, claim, nodeThresholds); serr != nil {
+			result.AddViolation(registry.NodeCheckStake, serr)
+		}
+	}
+
+	return &result, nil
+}
+
 func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
 	if err != nil {
@@ -102,6 +248,46 @@ func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return filteredNodes, nil
 }
 
+func (rq *registryQuerier) FreshNodes(ctx context.Context, window int64) ([]*node.Node, error) {
+	nodes, err := rq.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	height := rq.height
+	if latest := rq.queryState.BlockHeight(); height <= 0 || height > latest {
+		height = latest
+	}
+
+	// Filter out nodes without a recent heartbeat.
+	var freshNodes []*node.Node
+	for _, n := range nodes {
+		status, serr := rq.state.NodeStatus(ctx, n.ID)
+		if serr != nil {
+			return nil, fmt.Errorf("failed to get node status: %w", serr)
+		}
+		if !status.IsFresh(height, window) {
+			continue
+		}
+		freshNodes = append(freshNodes, n)
+	}
+	return freshNodes, nil
+}
+
+func (rq *registryQuerier) StaleNodes(ctx context.Context, epochs epochtime.EpochTime) (*registry.StaleNodes, error) {
+	epoch, err := rq.queryState.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	nodes, err := rq.state.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.NewStaleNodes(epoch, epochs, nodes), nil
+}
+
 func (rq *registryQuerier) Runtime(ctx context.Context, id common.Namespace) (*registry.Runtime, error) {
 	return rq.state.Runtime(ctx, id)
 }
@@ -113,6 +299,10 @@ func (rq *registryQuerier) Runtimes(ctx context.Context, includeSuspended bool)
 	return rq.state.Runtimes(ctx)
 }
 
+func (rq *registryQuerier) ConsensusParameters(ctx context.Context) (*registry.ConsensusParameters, error) {
+	return rq.state.ConsensusParameters(ctx)
+}
+
 func (app *registryApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -20,7 +20,10 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-var _ api.Application = (*registryApplication)(nil)
+var (
+	_ api.Application            = (*registryApplication)(nil)
+	_ api.TransactionPrioritizer = (*registryApplication)(nil)
+)
 
 type registryApplication struct {
 	state api.ApplicationState
@@ -42,6 +45,17 @@ func (app *registryApplication) Blessed() bool {
 	return false
 }
 
+// Implements api.TransactionPrioritizer.
+func (app *registryApplication) PriorityClass(method transaction.MethodName) api.PriorityClass {
+	switch method {
+	case registry.MethodRegisterNode, registry.MethodUnfreezeNode, registry.MethodHeartbeatNode:
+		// Node registrations are required for nodes to remain eligible for committees.
+		return api.PriorityClassHigh
+	default:
+		return api.PriorityClassNormal
+	}
+}
+
 func (app *registryApplication) Dependencies() []string {
 	return []string{stakingapp.AppName}
 }
@@ -81,6 +95,13 @@ func (app *registryApplication) ExecuteTx(ctx *api.Context, tx *transaction.Tran
 		}
 
 		return app.registerNode(ctx, state, &sigNode)
+	case registry.MethodRegisterNodes:
+		var batch registry.RegisterNodes
+		if err := cbor.Unmarshal(tx.Body, &batch); err != nil {
+			return err
+		}
+
+		return app.registerNodes(ctx, state, &batch)
 	case registry.MethodUnfreezeNode:
 		var unfreeze registry.UnfreezeNode
 		if err := cbor.Unmarshal(tx.Body, &unfreeze); err != nil {
@@ -95,6 +116,22 @@ func (app *registryApplication) ExecuteTx(ctx *api.Context, tx *transaction.Tran
 		}
 
 		return app.registerRuntime(ctx, state, &sigRt)
+	case registry.MethodHeartbeatNode:
+		return app.heartbeatNode(ctx, state)
+	case registry.MethodSuspendNode:
+		var suspend registry.SuspendNode
+		if err := cbor.Unmarshal(tx.Body, &suspend); err != nil {
+			return err
+		}
+
+		return app.suspendNode(ctx, state, &suspend)
+	case registry.MethodResumeNode:
+		var resume registry.ResumeNode
+		if err := cbor.Unmarshal(tx.Body, &resume); err != nil {
+			return err
+		}
+
+		return app.resumeNode(ctx, state, &resume)
 	default:
 		return registry.ErrInvalidArgument
 	}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -69,6 +70,10 @@ var (
 	//
 	// Value is empty.
 	signedRuntimeByEntityKeyFmt = keyformat.New(0x19, keyformat.H(&signature.PublicKey{}), keyformat.H(&common.Namespace{}))
+	// nodeByRuntimeKeyFmt is the key format used for the node by runtime index.
+	//
+	// Value is empty.
+	nodeByRuntimeKeyFmt = keyformat.New(0x1a, keyformat.H(&common.Namespace{}), &signature.PublicKey{})
 )
 
 // ImmutableState is the immutable registry state wrapper.
@@ -443,6 +448,87 @@ func (s *ImmutableState) NodeStatus(ctx context.Context, id signature.PublicKey)
 	return &status, nil
 }
 
+// nodesByIndex returns all nodes whose identifiers are stored under the given index key prefix.
+func (s *ImmutableState) nodesByIndex(ctx context.Context, kf *keyformat.KeyFormat, prefix []byte) ([]*node.Node, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var ids []signature.PublicKey
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var (
+			hPrefix keyformat.PreHashed
+			id      signature.PublicKey
+		)
+		if !kf.Decode(it.Key(), &hPrefix, &id) {
+			break
+		}
+		ids = append(ids, id)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+
+	nodes := make([]*node.Node, 0, len(ids))
+	for _, id := range ids {
+		n, err := s.Node(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// RuntimeNodes returns a list of all registered nodes (including expired ones) that include the
+// given runtime in their descriptors.
+func (s *ImmutableState) RuntimeNodes(ctx context.Context, id common.Namespace) ([]*node.Node, error) {
+	return s.nodesByIndex(ctx, nodeByRuntimeKeyFmt, nodeByRuntimeKeyFmt.Encode(&id))
+}
+
+// EntityNodes returns a list of all registered nodes (including expired ones) owned by the given
+// entity.
+func (s *ImmutableState) EntityNodes(ctx context.Context, id signature.PublicKey) ([]*node.Node, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	// The node by entity index only stores hashed node identifiers, which are also used to key
+	// the signed nodes.
+	var hNodeIDs []keyformat.PreHashed
+	prefix := signedNodeByEntityKeyFmt.Encode(&id)
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var hEntityID, hNodeID keyformat.PreHashed
+		if !signedNodeByEntityKeyFmt.Decode(it.Key(), &hEntityID, &hNodeID) {
+			break
+		}
+		hNodeIDs = append(hNodeIDs, hNodeID)
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+
+	nodes := make([]*node.Node, 0, len(hNodeIDs))
+	for i := range hNodeIDs {
+		signedNodeRaw, err := s.is.Get(ctx, signedNodeKeyFmt.Encode(&hNodeIDs[i]))
+		if err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		if signedNodeRaw == nil {
+			return nil, registry.ErrNoSuchNode
+		}
+
+		var signedNode node.MultiSignedNode
+		if err = cbor.Unmarshal(signedNodeRaw, &signedNode); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		var n node.Node
+		if err = cbor.Unmarshal(signedNode.Blob, &n); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		nodes = append(nodes, &n)
+	}
+	return nodes, nil
+}
+
 // HasEntityNodes checks whether an entity has any registered nodes.
 func (s *ImmutableState) HasEntityNodes(ctx context.Context, id signature.PublicKey) (bool, error) {
 	it := s.is.NewIterator(ctx)
@@ -565,6 +651,24 @@ func (s *MutableState) SetNode(ctx context.Context, existingNode, node *node.Nod
 		return abciAPI.UnavailableStateError(err)
 	}
 
+	// Runtimes.
+	if existingNode != nil {
+		for _, rt := range existingNode.Runtimes {
+			if node.GetRuntime(rt.ID) != nil {
+				continue
+			}
+			// Remove old runtime mapping if the node no longer has the runtime.
+			if err = s.ms.Remove(ctx, nodeByRuntimeKeyFmt.Encode(&rt.ID, &node.ID)); err != nil {
+				return abciAPI.UnavailableStateError(err)
+			}
+		}
+	}
+	for _, rt := range node.Runtimes {
+		if err = s.ms.Insert(ctx, nodeByRuntimeKeyFmt.Encode(&rt.ID, &node.ID), []byte("")); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+
 	// Update indices mapping various keys to nodes.
 
 	// Consensus key.
@@ -622,6 +726,11 @@ func (s *MutableState) RemoveNode(ctx context.Context, node *node.Node) error {
 	if err := s.ms.Remove(ctx, nodeStatusKeyFmt.Encode(&node.ID)); err != nil {
 		return abciAPI.UnavailableStateError(err)
 	}
+	for _, rt := range node.Runtimes {
+		if err := s.ms.Remove(ctx, nodeByRuntimeKeyFmt.Encode(&rt.ID, &node.ID)); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
 
 	address := []byte(tmcrypto.PublicKeyToTendermint(&node.Consensus.ID).Address())
 	if err := s.ms.Remove(ctx, nodeByConsAddressKeyFmt.Encode(address)); err != nil {
@@ -641,6 +750,42 @@ func (s *MutableState) RemoveNode(ctx context.Context, node *node.Node) error {
 	return nil
 }
 
+// RebuildNodeByRuntimeIndex rebuilds the node by runtime index from the registered nodes.
+//
+// This is needed when upgrading state written before the index was introduced.
+func (s *MutableState) RebuildNodeByRuntimeIndex(ctx context.Context) error {
+	// Remove any existing index entries.
+	it := s.ms.NewIterator(ctx)
+	var staleKeys [][]byte
+	prefix := nodeByRuntimeKeyFmt.Encode()
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		staleKeys = append(staleKeys, append([]byte{}, it.Key()...))
+	}
+	err := it.Err()
+	it.Close()
+	if err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	for _, key := range staleKeys {
+		if err = s.ms.Remove(ctx, key); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+
+	nodes, err := s.Nodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		for _, rt := range n.Runtimes {
+			if err = s.ms.Insert(ctx, nodeByRuntimeKeyFmt.Encode(&rt.ID, &n.ID), []byte("")); err != nil {
+				return abciAPI.UnavailableStateError(err)
+			}
+		}
+	}
+	return nil
+}
+
 // SetRuntime sets a signed runtime descriptor for a registered runtime.
 func (s *MutableState) SetRuntime(ctx context.Context, rt *registry.Runtime, sigRt *registry.SignedRuntime, suspended bool) error {
 	if err := s.ms.Insert(ctx, signedRuntimeByEntityKeyFmt.Encode(&rt.EntityID, &rt.ID), []byte("")); err != nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
@@ -144,3 +145,170 @@ func TestNodeUpdate(t *testing.T) {
 	require.Error(err, "TLS mapping should be gone")
 	require.Equal(registry.ErrNoSuchNode, err, "TLS mapping should be gone")
 }
+
+func TestRuntimeNodes(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	rt1 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: runtime 1"), 0)
+	rt2 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: runtime 2"), 0)
+
+	// Create a new node registered for both runtimes.
+	n := node.Node{
+		Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:        nodeSigner.Public(),
+		P2P: node.P2PInfo{
+			ID: p2pSigner1.Public(),
+		},
+		Consensus: node.ConsensusInfo{
+			ID: consensusSigner1.Public(),
+		},
+		TLS: node.TLSInfo{
+			PubKey: tlsSigner1.Public(),
+		},
+		Runtimes: []*node.Runtime{
+			{ID: rt1},
+			{ID: rt2},
+		},
+	}
+	err := s.SetNode(ctx, nil, &n, mustMultiSignNode(t, &n))
+	require.NoError(err, "SetNode")
+
+	for _, id := range []common.Namespace{rt1, rt2} {
+		nodes, rerr := s.RuntimeNodes(ctx, id)
+		require.NoError(rerr, "RuntimeNodes")
+		require.Len(nodes, 1, "node should be indexed by runtime %s", id)
+		require.EqualValues(n, *nodes[0], "returned node should be correct")
+	}
+
+	// Remove one of the runtimes and make sure the index has been updated.
+	newNode := n
+	newNode.Runtimes = []*node.Runtime{
+		{ID: rt2},
+	}
+	err = s.SetNode(ctx, &n, &newNode, mustMultiSignNode(t, &newNode))
+	require.NoError(err, "SetNode")
+
+	nodes, err := s.RuntimeNodes(ctx, rt1)
+	require.NoError(err, "RuntimeNodes")
+	require.Empty(nodes, "old runtime mapping should be gone")
+	nodes, err = s.RuntimeNodes(ctx, rt2)
+	require.NoError(err, "RuntimeNodes")
+	require.Len(nodes, 1, "runtime mapping should be there")
+
+	// Remove the node and make sure the index is gone.
+	err = s.RemoveNode(ctx, &newNode)
+	require.NoError(err, "RemoveNode")
+
+	nodes, err = s.RuntimeNodes(ctx, rt2)
+	require.NoError(err, "RuntimeNodes")
+	require.Empty(nodes, "runtime mapping should be gone")
+}
+
+func TestEntityNodes(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	entitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: entity signer")
+	otherEntitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry/state: other entity signer")
+
+	n := node.Node{
+		Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:        nodeSigner.Public(),
+		EntityID:  entitySigner.Public(),
+		P2P: node.P2PInfo{
+			ID: p2pSigner1.Public(),
+		},
+		Consensus: node.ConsensusInfo{
+			ID: consensusSigner1.Public(),
+		},
+		TLS: node.TLSInfo{
+			PubKey: tlsSigner1.Public(),
+		},
+	}
+	err := s.SetNode(ctx, nil, &n, mustMultiSignNode(t, &n))
+	require.NoError(err, "SetNode")
+
+	nodes, err := s.EntityNodes(ctx, entitySigner.Public())
+	require.NoError(err, "EntityNodes")
+	require.Len(nodes, 1, "node should be indexed by entity")
+	require.EqualValues(n, *nodes[0], "returned node should be correct")
+
+	nodes, err = s.EntityNodes(ctx, otherEntitySigner.Public())
+	require.NoError(err, "EntityNodes")
+	require.Empty(nodes, "other entity should not have any nodes")
+
+	// Remove the node and make sure the index is gone.
+	err = s.RemoveNode(ctx, &n)
+	require.NoError(err, "RemoveNode")
+
+	nodes, err = s.EntityNodes(ctx, entitySigner.Public())
+	require.NoError(err, "EntityNodes")
+	require.Empty(nodes, "entity mapping should be gone")
+}
+
+func TestRebuildNodeByRuntimeIndex(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	rt1 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: runtime 1"), 0)
+	rt2 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry/state: runtime 2"), 0)
+
+	n := node.Node{
+		Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:        nodeSigner.Public(),
+		P2P: node.P2PInfo{
+			ID: p2pSigner1.Public(),
+		},
+		Consensus: node.ConsensusInfo{
+			ID: consensusSigner1.Public(),
+		},
+		TLS: node.TLSInfo{
+			PubKey: tlsSigner1.Public(),
+		},
+		Runtimes: []*node.Runtime{
+			{ID: rt1},
+		},
+	}
+	err := s.SetNode(ctx, nil, &n, mustMultiSignNode(t, &n))
+	require.NoError(err, "SetNode")
+
+	// Simulate state written before the index was introduced, with an additional stale entry.
+	err = s.ms.Remove(ctx, nodeByRuntimeKeyFmt.Encode(&rt1, &n.ID))
+	require.NoError(err, "Remove")
+	err = s.ms.Insert(ctx, nodeByRuntimeKeyFmt.Encode(&rt2, &n.ID), []byte(""))
+	require.NoError(err, "Insert")
+
+	nodes, err := s.RuntimeNodes(ctx, rt1)
+	require.NoError(err, "RuntimeNodes")
+	require.Empty(nodes, "node should not be indexed before the rebuild")
+
+	err = s.RebuildNodeByRuntimeIndex(ctx)
+	require.NoError(err, "RebuildNodeByRuntimeIndex")
+
+	nodes, err = s.RuntimeNodes(ctx, rt1)
+	require.NoError(err, "RuntimeNodes")
+	require.Len(nodes, 1, "node should be indexed after the rebuild")
+	require.EqualValues(n, *nodes[0], "returned node should be correct")
+	nodes, err = s.RuntimeNodes(ctx, rt2)
+	require.NoError(err, "RuntimeNodes")
+	require.Empty(nodes, "stale index entries should be removed")
+}
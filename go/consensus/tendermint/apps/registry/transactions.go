@@ -1,18 +1,99 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
+// verifyRuntimeAdmission verifies that the runtime's admission policy allows the given node to
+// register for the runtime.
+func verifyRuntimeAdmission(
+	ctx context.Context,
+	state *registryState.ImmutableState,
+	rt *registry.Runtime,
+	newNode *node.Node,
+	epoch epochtime.EpochTime,
+) error {
+	switch policy := rt.AdmissionPolicy; {
+	case policy.EntityWhitelist != nil:
+		if !policy.EntityWhitelist.Entities[newNode.EntityID] {
+			return fmt.Errorf("%w: entity not in the whitelist of runtime %s", registry.ErrForbidden, rt.ID)
+		}
+	case policy.NodeWhitelist != nil:
+		if !policy.NodeWhitelist.Nodes[newNode.ID] {
+			return fmt.Errorf("%w: node not in the whitelist of runtime %s", registry.ErrForbidden, rt.ID)
+		}
+		if len(policy.NodeWhitelist.MaxNodes) == 0 {
+			return nil
+		}
+
+		// Count the other nodes registered for the runtime with each limited role. Only the nodes
+		// registered for the runtime are loaded (via the runtime's node index).
+		nodes, err := state.RuntimeNodes(ctx, rt.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get runtime nodes: %w", err)
+		}
+		counts := make(map[node.RolesMask]uint16)
+		for _, n := range nodes {
+			if n.ID.Equal(newNode.ID) || n.IsExpired(uint64(epoch)) {
+				continue
+			}
+			for role := range policy.NodeWhitelist.MaxNodes {
+				if n.HasRoles(role) {
+					counts[role]++
+				}
+			}
+		}
+		for role, maxNodes := range policy.NodeWhitelist.MaxNodes {
+			if newNode.HasRoles(role) && counts[role] >= maxNodes {
+				return fmt.Errorf("%w: too many %s nodes registered for runtime %s", registry.ErrForbidden, role, rt.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyEntityNodeQuota verifies that registering the given node does not exceed the per-entity
+// node quotas of its owning entity.
+func verifyEntityNodeQuota(
+	ctx context.Context,
+	state *registryState.ImmutableState,
+	params *registry.ConsensusParameters,
+	newNode *node.Node,
+	epoch epochtime.EpochTime,
+) error {
+	if params.MaxNodesPerEntity == 0 && len(params.MaxNodesPerEntityRole) == 0 {
+		return nil
+	}
+
+	// Only the entity's own nodes are loaded (via the entity's node index).
+	nodes, err := state.EntityNodes(ctx, newNode.EntityID)
+	if err != nil {
+		return fmt.Errorf("failed to get entity nodes: %w", err)
+	}
+	var entityNodes []*node.Node
+	for _, n := range nodes {
+		if n.IsExpired(uint64(epoch)) {
+			continue
+		}
+		entityNodes = append(entityNodes, n)
+	}
+	return params.VerifyEntityNodeQuota(newNode, entityNodes)
+}
+
 func (app *registryApplication) registerEntity(
 	ctx *api.Context,
 	state *registryState.MutableState,
@@ -76,6 +157,18 @@ func (app *registryApplication) registerEntity(
 
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyEntityRegistered, cbor.Marshal(ent)))
 
+	if warnings := registry.EntityDeprecationWarnings(ent); len(warnings) > 0 {
+		ctx.Logger().Debug("RegisterEntity: descriptor uses deprecated features",
+			"entity", ent.ID,
+			"warnings", warnings,
+		)
+
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyDeprecationWarning, cbor.Marshal(&registry.DeprecationWarningEvent{
+			EntityID: ent.ID,
+			Warnings: warnings,
+		})))
+	}
+
 	return nil
 }
 
@@ -155,7 +248,7 @@ func (app *registryApplication) deregisterEntity(ctx *api.Context, state *regist
 	return nil
 }
 
-func (app *registryApplication) registerNode( // nolint: gocyclo
+func (app *registryApplication) registerNode(
 	ctx *api.Context,
 	state *registryState.MutableState,
 	sigNode *node.MultiSignedNode,
@@ -164,6 +257,114 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		return nil
 	}
 
+	// Create a new state checkpoint and rollback in case we fail.
+	sc := ctx.StartCheckpoint()
+	defer sc.Close()
+
+	// The passed state wraps the state tree from before the checkpoint was started, so make sure
+	// that all updates go through the checkpoint instead.
+	cpState := registryState.NewMutableState(ctx.State())
+
+	events, err := app.doRegisterNode(ctx, cpState, sigNode, false)
+	if err != nil {
+		return err
+	}
+
+	sc.Commit()
+	for _, ev := range events {
+		ctx.EmitEvent(ev)
+	}
+
+	return nil
+}
+
+func (app *registryApplication) registerNodes(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	batch *registry.RegisterNodes,
+) error {
+	if err := batch.ValidateBasic(); err != nil {
+		return err
+	}
+
+	// Make sure that each node is only registered once in the batch.
+	seen := make(map[signature.PublicKey]bool)
+	for i, sigNode := range batch.Nodes {
+		var untrustedNode node.Node
+		if err := cbor.Unmarshal(sigNode.Blob, &untrustedNode); err != nil {
+			return fmt.Errorf("%w: malformed node descriptor at index %d", registry.ErrInvalidArgument, i)
+		}
+		if seen[untrustedNode.ID] {
+			return fmt.Errorf("%w: duplicate node %s in registration batch", registry.ErrInvalidArgument, untrustedNode.ID)
+		}
+		seen[untrustedNode.ID] = true
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("RegisterNodes: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+
+	// Charge gas for all node registrations upfront.
+	if err = ctx.Gas().UseGas(len(batch.Nodes), registry.GasOpRegisterNode, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Create a new state checkpoint and rollback in case registering any of the nodes fails.
+	sc := ctx.StartCheckpoint()
+	defer sc.Close()
+
+	// The passed state wraps the state tree from before the checkpoint was started, so make sure
+	// that all updates go through the checkpoint instead.
+	cpState := registryState.NewMutableState(ctx.State())
+
+	var events []*api.EventBuilder
+	for i, sigNode := range batch.Nodes {
+		var nodeEvents []*api.EventBuilder
+		if nodeEvents, err = app.doRegisterNode(ctx, cpState, sigNode, true); err != nil {
+			ctx.Logger().Error("RegisterNodes: failed to register node",
+				"err", err,
+				"index", i,
+			)
+			return err
+		}
+		events = append(events, nodeEvents...)
+	}
+
+	sc.Commit()
+	for _, ev := range events {
+		ctx.EmitEvent(ev)
+	}
+
+	ctx.Logger().Debug("RegisterNodes: registered batch",
+		"num_nodes", len(batch.Nodes),
+	)
+
+	return nil
+}
+
+// doRegisterNode registers the given node. It returns the events that should be emitted once the
+// registration has been committed.
+//
+// The caller must ensure that a state checkpoint is active so that any changes are rolled back
+// in case the registration fails. In case the registration is part of a batch, the transaction
+// must be signed by the node's owning entity and the registration gas must have been charged by
+// the caller.
+func (app *registryApplication) doRegisterNode( // nolint: gocyclo
+	ctx *api.Context,
+	state *registryState.MutableState,
+	sigNode *node.MultiSignedNode,
+	batch bool,
+) ([]*api.EventBuilder, error) {
+	var events []*api.EventBuilder
+
 	// Peek into the to-be-verified node to pull out the owning entity ID.
 	var untrustedNode node.Node
 	if err := cbor.Unmarshal(sigNode.Blob, &untrustedNode); err != nil {
@@ -171,7 +372,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			"err", err,
 			"signed_node", sigNode,
 		)
-		return err
+		return nil, err
 	}
 	untrustedEntity, err := state.Entity(ctx, untrustedNode.EntityID)
 	if err != nil {
@@ -179,7 +380,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			"err", err,
 			"signed_node", sigNode,
 		)
-		return err
+		return nil, err
 	}
 
 	params, err := state.ConsensusParameters(ctx)
@@ -187,7 +388,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		ctx.Logger().Error("RegisterNode: failed to fetch consensus parameters",
 			"err", err,
 		)
-		return err
+		return nil, err
 	}
 
 	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
@@ -195,7 +396,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		ctx.Logger().Error("RegisterNode: failed to get epoch",
 			"err", err,
 		)
-		return err
+		return nil, err
 	}
 
 	newNode, paidRuntimes, err := registry.VerifyRegisterNodeArgs(
@@ -212,43 +413,74 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		state,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Charge gas for node registration if signed by entity. For node-signed
-	// registrations, the gas charges are pre-paid by the entity.
+	// registrations, the gas charges are pre-paid by the entity. For batch
+	// registrations, the gas charges are paid for the whole batch.
 	isEntitySigned := sigNode.MultiSigned.IsSignedBy(newNode.EntityID)
-	if isEntitySigned {
+	if isEntitySigned && !batch {
 		if err = ctx.Gas().UseGas(1, registry.GasOpRegisterNode, params.GasCosts); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// Make sure the signer of the transaction is the node identity key
-	// or the entity (iff the registration is entity signed).
+	// or the entity (iff the registration is entity signed or part of a batch).
 	// NOTE: If this is invoked during InitChain then there is no actual transaction
 	//       and thus no transaction signer so we must skip this check.
 	if !ctx.IsInitChain() {
 		expectedTxSigner := newNode.ID
-		if isEntitySigned {
+		if isEntitySigned || batch {
 			expectedTxSigner = newNode.EntityID
 		}
 		if !ctx.TxSigner().Equal(expectedTxSigner) {
-			return registry.ErrIncorrectTxSigner
+			return nil, registry.ErrIncorrectTxSigner
 		}
 	}
 
-	// Check runtime's whitelist.
+	// Check runtime's admission policy.
 	for _, rt := range paidRuntimes {
-		if rt.AdmissionPolicy.EntityWhitelist != nil && !rt.AdmissionPolicy.EntityWhitelist.Entities[newNode.EntityID] {
-			ctx.Logger().Error("RegisterNode: node's entity not in a runtime's whitelist",
+		err = verifyRuntimeAdmission(ctx, state.ImmutableState, rt, newNode, epoch)
+		switch {
+		case err == nil:
+		case errors.Is(err, registry.ErrForbidden):
+			ctx.Logger().Error("RegisterNode: node not admitted by a runtime's admission policy",
+				"err", err,
+				"node", newNode.ID,
 				"entity", newNode.EntityID,
 				"runtime", rt.ID,
 			)
-			return registry.ErrForbidden
+			return nil, registry.ErrForbidden
+		default:
+			ctx.Logger().Error("RegisterNode: failed to verify runtime admission policy",
+				"err", err,
+				"runtime", rt.ID,
+			)
+			return nil, err
 		}
 	}
 
+	// Check the owning entity's node quotas.
+	err = verifyEntityNodeQuota(ctx, state.ImmutableState, params, newNode, epoch)
+	switch {
+	case err == nil:
+	case errors.Is(err, registry.ErrEntityNodeQuotaExceeded):
+		ctx.Logger().Error("RegisterNode: entity node quota exceeded",
+			"err", err,
+			"node", newNode.ID,
+			"entity", newNode.EntityID,
+		)
+		return nil, registry.ErrEntityNodeQuotaExceeded
+	default:
+		ctx.Logger().Error("RegisterNode: failed to verify entity node quota",
+			"err", err,
+			"entity", newNode.EntityID,
+		)
+		return nil, err
+	}
+
 	// Ensure node is not expired. Even though the expiration in the
 	// current epoch is technically not yet expired, we treat it as
 	// expired as it doesn't make sense to have a new node that will
@@ -260,7 +492,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			"new_node", newNode,
 			"epoch", epoch,
 		)
-		return registry.ErrNodeExpired
+		return nil, registry.ErrNodeExpired
 	}
 
 	var additionalEpochs uint64
@@ -280,7 +512,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			"existing_node", existingNode,
 			"entity", newNode.EntityID,
 		)
-		return registry.ErrInvalidArgument
+		return nil, registry.ErrInvalidArgument
 	}
 
 	// For each runtime the node registers for, require it to pay a maintenance fee for
@@ -298,19 +530,15 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 	}
 	feeCount := len(paidRuntimes) * int(additionalEpochs)
 	if err = ctx.Gas().UseGas(feeCount, registry.GasOpRuntimeEpochMaintenance, params.GasCosts); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create a new state checkpoint and rollback in case we fail.
-	sc := ctx.StartCheckpoint()
-	defer sc.Close()
-
 	// Check that the entity has enough stake for this node registration.
 	var stakeAcc *stakingState.StakeAccumulatorCache
 	if !params.DebugBypassStake {
 		stakeAcc, err = stakingState.NewStakeAccumulatorCache(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to create stake accumulator cache: %w", err)
+			return nil, fmt.Errorf("failed to create stake accumulator cache: %w", err)
 		}
 
 		claim := registry.StakeClaimForNode(newNode.ID)
@@ -323,10 +551,10 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				"entity", newNode.EntityID,
 				"account", acctAddr,
 			)
-			return err
+			return nil, err
 		}
 		if err = stakeAcc.Commit(); err != nil {
-			return fmt.Errorf("failed to commit stake accumulator updates: %w", err)
+			return nil, fmt.Errorf("failed to commit stake accumulator updates: %w", err)
 		}
 	}
 
@@ -339,7 +567,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				"existing_node", existingNode,
 				"entity", newNode.EntityID,
 			)
-			return err
+			return nil, err
 		}
 	}
 	if err = state.SetNode(ctx, existingNode, newNode, sigNode); err != nil {
@@ -349,7 +577,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			"entity", newNode.EntityID,
 			"is_creation", existingNode == nil,
 		)
-		return fmt.Errorf("failed to set node: %w", err)
+		return nil, fmt.Errorf("failed to set node: %w", err)
 	}
 
 	if isNewNode || isExpiredNode {
@@ -361,7 +589,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				ctx.Logger().Error("RegisterNode: failed to get node status",
 					"err", err,
 				)
-				return registry.ErrInvalidArgument
+				return nil, registry.ErrInvalidArgument
 			}
 
 			// Reset expiration processed flag as the node is live again.
@@ -375,7 +603,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 			ctx.Logger().Error("RegisterNode: failed to set node status",
 				"err", err,
 			)
-			return fmt.Errorf("failed to set node status: %w", err)
+			return nil, fmt.Errorf("failed to set node status: %w", err)
 		}
 	}
 
@@ -398,7 +626,7 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				"runtime_id", rt.ID,
 			)
 
-			ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeRegistered, cbor.Marshal(rt)))
+			events = append(events, api.NewEventBuilder(app.Name()).Attribute(KeyRuntimeRegistered, cbor.Marshal(rt)))
 		case registry.ErrNoSuchRuntime:
 			// Runtime was not suspended.
 		default:
@@ -406,20 +634,31 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 				"err", err,
 				"runtime_id", rt.ID,
 			)
-			return fmt.Errorf("failed to resume suspended runtime %s: %w", rt.ID, err)
+			return nil, fmt.Errorf("failed to resume suspended runtime %s: %w", rt.ID, err)
 		}
 	}
 
-	sc.Commit()
-
 	ctx.Logger().Debug("RegisterNode: registered",
 		"node", newNode,
 		"roles", newNode.Roles,
 	)
 
-	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyNodeRegistered, cbor.Marshal(newNode)))
+	events = append(events, api.NewEventBuilder(app.Name()).Attribute(KeyNodeRegistered, cbor.Marshal(newNode)))
 
-	return nil
+	if warnings := registry.NodeDeprecationWarnings(newNode); len(warnings) > 0 {
+		ctx.Logger().Debug("RegisterNode: descriptor uses deprecated features",
+			"node", newNode.ID,
+			"warnings", warnings,
+		)
+
+		events = append(events, api.NewEventBuilder(app.Name()).Attribute(KeyDeprecationWarning, cbor.Marshal(&registry.DeprecationWarningEvent{
+			EntityID: newNode.EntityID,
+			NodeID:   &newNode.ID,
+			Warnings: warnings,
+		})))
+	}
+
+	return events, nil
 }
 
 func (app *registryApplication) unfreezeNode(
@@ -492,6 +731,163 @@ func (app *registryApplication) unfreezeNode(
 	return nil
 }
 
+func (app *registryApplication) heartbeatNode(ctx *api.Context, state *registryState.MutableState) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("HeartbeatNode: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, registry.GasOpHeartbeatNode, params.GasCosts); err != nil {
+		return err
+	}
+
+	if params.MinHeartbeatInterval == 0 {
+		return registry.ErrForbidden
+	}
+
+	// The heartbeat must be signed by the node itself.
+	nodeID := ctx.TxSigner()
+	node, err := state.Node(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+	if node.IsExpired(uint64(epoch)) {
+		return registry.ErrNodeExpired
+	}
+
+	// Fetch node status.
+	status, err := state.NodeStatus(ctx, nodeID)
+	if err != nil {
+		ctx.Logger().Error("HeartbeatNode: failed to fetch node status",
+			"err", err,
+			"node_id", nodeID,
+		)
+		return err
+	}
+
+	// Make sure that the node is not submitting heartbeats too frequently.
+	height := ctx.BlockHeight() + 1
+	if status.LastHeartbeatHeight > 0 && height-status.LastHeartbeatHeight < params.MinHeartbeatInterval {
+		return registry.ErrHeartbeatTooFrequent
+	}
+
+	status.LastHeartbeatHeight = height
+	if err = state.SetNodeStatus(ctx, nodeID, status); err != nil {
+		return fmt.Errorf("failed to set node status: %w", err)
+	}
+
+	ctx.Logger().Debug("HeartbeatNode: recorded heartbeat",
+		"node_id", nodeID,
+		"height", height,
+	)
+
+	return nil
+}
+
+func (app *registryApplication) suspendNode(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	suspend *registry.SuspendNode,
+) error {
+	return app.setNodeSuspended(ctx, state, "SuspendNode", registry.GasOpSuspendNode, suspend.NodeID, true)
+}
+
+func (app *registryApplication) resumeNode(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	resume *registry.ResumeNode,
+) error {
+	return app.setNodeSuspended(ctx, state, "ResumeNode", registry.GasOpResumeNode, resume.NodeID, false)
+}
+
+// setNodeSuspended updates the suspension flag of the given node on behalf of the owning entity.
+func (app *registryApplication) setNodeSuspended(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	method string,
+	gasOp transaction.Op,
+	nodeID signature.PublicKey,
+	suspended bool,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error(method+": failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, gasOp, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Fetch node descriptor.
+	node, err := state.Node(ctx, nodeID)
+	if err != nil {
+		ctx.Logger().Error(method+": failed to fetch node",
+			"err", err,
+			"node_id", nodeID,
+		)
+		return err
+	}
+	// Make sure that the request was signed by the owning entity.
+	if !ctx.TxSigner().Equal(node.EntityID) {
+		return registry.ErrBadEntityForNode
+	}
+
+	// Fetch node status.
+	status, err := state.NodeStatus(ctx, nodeID)
+	if err != nil {
+		ctx.Logger().Error(method+": failed to fetch node status",
+			"err", err,
+			"node_id", nodeID,
+			"entity_id", node.EntityID,
+		)
+		return err
+	}
+
+	switch {
+	case suspended && status.IsSuspended():
+		return registry.ErrNodeAlreadySuspended
+	case !suspended && !status.IsSuspended():
+		return registry.ErrNodeNotSuspended
+	}
+
+	status.Suspended = suspended
+	if err = state.SetNodeStatus(ctx, node.ID, status); err != nil {
+		return fmt.Errorf("failed to set node status: %w", err)
+	}
+
+	ctx.Logger().Debug(method+": updated node suspension",
+		"node_id", node.ID,
+		"entity_id", node.EntityID,
+		"suspended", suspended,
+	)
+
+	key := KeyNodeResumed
+	if suspended {
+		key = KeyNodeSuspended
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(key, cbor.Marshal(node.ID)))
+
+	return nil
+}
+
 func (app *registryApplication) registerRuntime( // nolint: gocyclo
 	ctx *api.Context,
 	state *registryState.MutableState,
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -203,6 +204,117 @@ func TestRegisterNode(t *testing.T) {
 			true,
 			true,
 		},
+		// Compute node that is whitelisted by a node whitelist admission policy.
+		{
+			"ComputeNodeNodeWhitelisted",
+			func(tcd *testCaseData) {
+				// Create a new runtime.
+				rtSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: runtime signer: ComputeNodeNodeWhitelisted")
+				rt := registry.Runtime{
+					Versioned: cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+					ID:        common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry: runtime: ComputeNodeNodeWhitelisted"), 0),
+					Kind:      registry.KindCompute,
+					AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+						NodeWhitelist: &registry.NodeWhitelistRuntimeAdmissionPolicy{
+							Nodes: map[signature.PublicKey]bool{
+								tcd.node.ID: true,
+							},
+							MaxNodes: map[node.RolesMask]uint16{
+								node.RoleComputeWorker: 1,
+							},
+						},
+					},
+				}
+				sigRt, _ := registry.SignRuntime(rtSigner, registry.RegisterRuntimeSignatureContext, &rt)
+				_ = state.SetRuntime(ctx, &rt, sigRt, false)
+
+				tcd.node.AddRoles(node.RoleComputeWorker)
+				tcd.node.Runtimes = []*node.Runtime{
+					{ID: rt.ID},
+				}
+			},
+			nil,
+			true,
+			true,
+		},
+		// Compute node that is not whitelisted by a node whitelist admission policy.
+		{
+			"ComputeNodeNotNodeWhitelisted",
+			func(tcd *testCaseData) {
+				// Create a new runtime.
+				rtSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: runtime signer: ComputeNodeNotNodeWhitelisted")
+				rt := registry.Runtime{
+					Versioned: cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+					ID:        common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry: runtime: ComputeNodeNotNodeWhitelisted"), 0),
+					Kind:      registry.KindCompute,
+					AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+						NodeWhitelist: &registry.NodeWhitelistRuntimeAdmissionPolicy{
+							Nodes: map[signature.PublicKey]bool{},
+						},
+					},
+				}
+				sigRt, _ := registry.SignRuntime(rtSigner, registry.RegisterRuntimeSignatureContext, &rt)
+				_ = state.SetRuntime(ctx, &rt, sigRt, false)
+
+				tcd.node.AddRoles(node.RoleComputeWorker)
+				tcd.node.Runtimes = []*node.Runtime{
+					{ID: rt.ID},
+				}
+			},
+			nil,
+			false,
+			false,
+		},
+		// Whitelisted compute node exceeding the per-role node limit of a node whitelist admission policy.
+		{
+			"ComputeNodeNodeWhitelistTooManyNodes",
+			func(tcd *testCaseData) {
+				otherSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: other node signer: ComputeNodeNodeWhitelistTooManyNodes")
+
+				// Create a new runtime.
+				rtSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: runtime signer: ComputeNodeNodeWhitelistTooManyNodes")
+				rt := registry.Runtime{
+					Versioned: cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+					ID:        common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/apps/registry: runtime: ComputeNodeNodeWhitelistTooManyNodes"), 0),
+					Kind:      registry.KindCompute,
+					AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+						NodeWhitelist: &registry.NodeWhitelistRuntimeAdmissionPolicy{
+							Nodes: map[signature.PublicKey]bool{
+								tcd.node.ID:          true,
+								otherSigner.Public(): true,
+							},
+							MaxNodes: map[node.RolesMask]uint16{
+								node.RoleComputeWorker: 1,
+							},
+						},
+					},
+				}
+				sigRt, _ := registry.SignRuntime(rtSigner, registry.RegisterRuntimeSignatureContext, &rt)
+				_ = state.SetRuntime(ctx, &rt, sigRt, false)
+
+				// Register another compute node for the runtime (hacky, directly in state).
+				otherKeySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: other node key signer: ComputeNodeNodeWhitelistTooManyNodes")
+				other := tcd.node
+				other.ID = otherSigner.Public()
+				other.P2P.ID = otherKeySigner.Public()
+				other.Consensus = node.ConsensusInfo{ID: otherKeySigner.Public()}
+				other.TLS = node.TLSInfo{PubKey: otherKeySigner.Public()}
+				other.AddRoles(node.RoleComputeWorker)
+				other.Runtimes = []*node.Runtime{
+					{ID: rt.ID},
+				}
+				sigOther, _ := node.MultiSignNode([]signature.Signer{otherSigner}, registry.RegisterNodeSignatureContext, &other)
+				_ = state.SetNode(ctx, nil, &other, sigOther)
+
+				tcd.node.AddRoles(node.RoleComputeWorker)
+				tcd.node.Runtimes = []*node.Runtime{
+					{ID: rt.ID},
+				}
+			},
+			nil,
+			false,
+			false,
+		},
 		// Compute node with enough (per-runtime) stake for one runtime, but not for two.
 		{
 			"ComputeNodeWithoutPerRuntimeStakeMulti",
@@ -344,6 +456,34 @@ func TestRegisterNode(t *testing.T) {
 			false,
 			true, // We tried to update an existing node, so it should keep existing.
 		},
+		// Validator node exceeding the per-entity node limit.
+		{
+			"ValidatorNodeEntityQuotaExceeded",
+			func(tcd *testCaseData) {
+				err = state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+					MaxNodeExpiration: 5,
+					MaxNodesPerEntity: 1,
+				})
+				require.NoError(err, "registry.SetConsensusParameters")
+
+				tcd.node.AddRoles(node.RoleValidator)
+				tcd.node.Expiration = 12
+
+				// Register another node of the same entity (hacky, directly in state).
+				otherSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: other node signer: ValidatorNodeEntityQuotaExceeded")
+				otherKeySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: other node key signer: ValidatorNodeEntityQuotaExceeded")
+				other := tcd.node
+				other.ID = otherSigner.Public()
+				other.P2P.ID = otherKeySigner.Public()
+				other.Consensus = node.ConsensusInfo{ID: otherKeySigner.Public()}
+				other.TLS = node.TLSInfo{PubKey: otherKeySigner.Public()}
+				sigOther, _ := node.MultiSignNode([]signature.Signer{otherSigner}, registry.RegisterNodeSignatureContext, &other)
+				_ = state.SetNode(ctx, nil, &other, sigOther)
+			},
+			nil,
+			false,
+			false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -445,3 +585,371 @@ func TestRegisterNode(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterNodes(t *testing.T) {
+	require := requirePkg.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := registryApplication{appState}
+	state := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	err := stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		Thresholds: map[staking.ThresholdKind]quantity.Quantity{
+			staking.KindEntity:        *quantity.NewFromUint64(0),
+			staking.KindNodeValidator: *quantity.NewFromUint64(0),
+		},
+	})
+	require.NoError(err, "staking.SetConsensusParameters")
+	err = state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration: 5,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+
+	nodeSigners := make(map[string]signature.Signer)
+	for _, name := range []string{"1", "2", "3", "4"} {
+		nodeSigners[name] = memorySigner.NewTestSigner("consensus/tendermint/apps/registry: batch node signer: " + name)
+	}
+
+	// The entity whitelists all nodes except for node 4.
+	entitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: batch entity signer")
+	ent := entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestEntityDescriptorVersion),
+		ID:        entitySigner.Public(),
+		Nodes: []signature.PublicKey{
+			nodeSigners["1"].Public(),
+			nodeSigners["2"].Public(),
+			nodeSigners["3"].Public(),
+		},
+	}
+	sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+	require.NoError(err, "SignEntity")
+	err = state.SetEntity(ctx, &ent, sigEnt)
+	require.NoError(err, "SetEntity")
+
+	var address node.Address
+	err = address.UnmarshalText([]byte("8.8.8.8:1234"))
+	require.NoError(err, "address.UnmarshalText")
+
+	newNode := func(name string, entitySigned bool) (*node.Node, *node.MultiSignedNode) {
+		nodeSigner := nodeSigners[name]
+		consensusSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: batch consensus signer: " + name)
+		p2pSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: batch p2p signer: " + name)
+		tlsSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: batch tls signer: " + name)
+
+		n := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   ent.ID,
+			Expiration: 3,
+			P2P: node.P2PInfo{
+				ID:        p2pSigner.Public(),
+				Addresses: []node.Address{address},
+			},
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+				Addresses: []node.ConsensusAddress{
+					{ID: consensusSigner.Public(), Address: address},
+				},
+			},
+			TLS: node.TLSInfo{
+				PubKey: tlsSigner.Public(),
+				Addresses: []node.TLSAddress{
+					{PubKey: tlsSigner.Public(), Address: address},
+				},
+			},
+			Roles: node.RoleValidator,
+		}
+		signers := []signature.Signer{nodeSigner, p2pSigner, consensusSigner, tlsSigner}
+		if entitySigned {
+			signers = append(signers, entitySigner)
+		}
+
+		sigNode, serr := node.MultiSignNode(signers, registry.RegisterNodeSignatureContext, n)
+		require.NoError(serr, "MultiSignNode")
+		return n, sigNode
+	}
+
+	n1, sigNode1 := newNode("1", false)
+	n2, sigNode2 := newNode("2", false)
+	n3, sigNode3 := newNode("3", true)
+	n4, sigNode4 := newNode("4", false)
+
+	ctx.SetTxSigner(entitySigner.Public())
+
+	// Empty batches should be rejected.
+	err = app.registerNodes(ctx, state, &registry.RegisterNodes{})
+	require.True(errors.Is(err, registry.ErrInvalidArgument), "empty batch should be rejected")
+
+	// Batches registering the same node multiple times should be rejected.
+	err = app.registerNodes(ctx, state, &registry.RegisterNodes{Nodes: []*node.MultiSignedNode{sigNode1, sigNode1}})
+	require.True(errors.Is(err, registry.ErrInvalidArgument), "batch with duplicate nodes should be rejected")
+
+	// Batches are all-or-nothing: in case any of the nodes fails validation, no node should be
+	// registered.
+	for _, tc := range []struct {
+		invalid    *node.Node
+		invalidSig *node.MultiSignedNode
+		msg        string
+	}{
+		{n3, sigNode3, "entity-signed node descriptor"},
+		{n4, sigNode4, "node not whitelisted by the entity"},
+	} {
+		err = app.registerNodes(ctx, state, &registry.RegisterNodes{Nodes: []*node.MultiSignedNode{sigNode1, tc.invalidSig}})
+		require.Error(err, "batch with an invalid node should be rejected (%s)", tc.msg)
+		_, err = state.Node(ctx, n1.ID)
+		require.Equal(registry.ErrNoSuchNode, err, "no node from a failed batch should be registered (%s)", tc.msg)
+		_, err = state.Node(ctx, tc.invalid.ID)
+		require.Equal(registry.ErrNoSuchNode, err, "no node from a failed batch should be registered (%s)", tc.msg)
+	}
+
+	// Batches must be submitted by the owning entity.
+	ctx.SetTxSigner(n1.ID)
+	err = app.registerNodes(ctx, state, &registry.RegisterNodes{Nodes: []*node.MultiSignedNode{sigNode1, sigNode2}})
+	require.Equal(registry.ErrIncorrectTxSigner, err, "batch not submitted by the entity should be rejected")
+
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.registerNodes(ctx, state, &registry.RegisterNodes{Nodes: []*node.MultiSignedNode{sigNode1, sigNode2}})
+	require.NoError(err, "batch registration should succeed")
+
+	for _, n := range []*node.Node{n1, n2} {
+		var regNode *node.Node
+		regNode, err = state.Node(ctx, n.ID)
+		require.NoError(err, "node should be registered")
+		require.EqualValues(n, regNode, "registered node descriptor should be correct")
+	}
+}
+
+func TestVerifyEntityNodeQuota(t *testing.T) {
+	require := requirePkg.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	state := registryState.NewMutableState(ctx.State())
+
+	entitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota entity signer")
+	otherEntitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota other entity signer")
+
+	newTestNode := func(seed string, entityID signature.PublicKey, expiration uint64) *node.Node {
+		nodeSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota node " + seed)
+		n := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entityID,
+			Expiration: expiration,
+			Roles:      node.RoleComputeWorker,
+			P2P: node.P2PInfo{
+				ID: memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota p2p " + seed).Public(),
+			},
+			Consensus: node.ConsensusInfo{
+				ID: memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota consensus " + seed).Public(),
+			},
+			TLS: node.TLSInfo{
+				PubKey: memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota tls " + seed).Public(),
+			},
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, n)
+		require.NoError(err, "MultiSignNode")
+		err = state.SetNode(ctx, nil, n, sigNode)
+		require.NoError(err, "SetNode")
+		return n
+	}
+
+	// Register nodes via the node by entity index that also exists for nodes registered before
+	// per-entity quotas were introduced.
+	newTestNode("1", entitySigner.Public(), 10)
+	newTestNode("2", entitySigner.Public(), 10)
+	newTestNode("expired", entitySigner.Public(), 1)
+	newTestNode("other", otherEntitySigner.Public(), 10)
+
+	nodes, err := state.EntityNodes(ctx, entitySigner.Public())
+	require.NoError(err, "EntityNodes")
+	require.Len(nodes, 3, "all of the entity's nodes should be returned")
+
+	newNode := &node.Node{
+		ID:       memorySigner.NewTestSigner("consensus/tendermint/apps/registry: quota new node").Public(),
+		EntityID: entitySigner.Public(),
+		Roles:    node.RoleComputeWorker,
+	}
+
+	params := &registry.ConsensusParameters{MaxNodesPerEntity: 3}
+	err = verifyEntityNodeQuota(ctx, state.ImmutableState, params, newNode, 5)
+	require.NoError(err, "expired nodes should not count towards the quota")
+
+	params = &registry.ConsensusParameters{MaxNodesPerEntity: 2}
+	err = verifyEntityNodeQuota(ctx, state.ImmutableState, params, newNode, 5)
+	require.Error(err, "registered nodes should count towards the quota")
+	require.True(errors.Is(err, registry.ErrEntityNodeQuotaExceeded), "quota error should be returned")
+
+	params = &registry.ConsensusParameters{
+		MaxNodesPerEntityRole: map[node.RolesMask]uint16{
+			node.RoleComputeWorker: 2,
+		},
+	}
+	err = verifyEntityNodeQuota(ctx, state.ImmutableState, params, newNode, 5)
+	require.True(errors.Is(err, registry.ErrEntityNodeQuotaExceeded), "per-role quota should be enforced")
+
+	newNode.EntityID = otherEntitySigner.Public()
+	params = &registry.ConsensusParameters{MaxNodesPerEntity: 2}
+	err = verifyEntityNodeQuota(ctx, state.ImmutableState, params, newNode, 5)
+	require.NoError(err, "other entity's nodes should not count towards the quota")
+}
+
+func TestHeartbeatNode(t *testing.T) {
+	require := requirePkg.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{
+		BlockHeight:  100,
+		CurrentEpoch: 1,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := registryApplication{appState}
+	state := registryState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration:    5,
+		MinHeartbeatInterval: 10,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+
+	nodeSigner := memorySigner.NewTestSigner("heartbeat node signer")
+	n := &node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         nodeSigner.Public(),
+		EntityID:   memorySigner.NewTestSigner("heartbeat entity signer").Public(),
+		Expiration: 3,
+	}
+
+	// Heartbeats from unregistered nodes should be rejected.
+	ctx.SetTxSigner(nodeSigner.Public())
+	err = app.heartbeatNode(ctx, state)
+	require.Equal(registry.ErrNoSuchNode, err, "heartbeat from an unregistered node should fail")
+
+	sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, n)
+	require.NoError(err, "MultiSignNode")
+	err = state.SetNode(ctx, nil, n, sigNode)
+	require.NoError(err, "SetNode")
+	err = state.SetNodeStatus(ctx, n.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+
+	err = app.heartbeatNode(ctx, state)
+	require.NoError(err, "heartbeat should succeed")
+
+	status, err := state.NodeStatus(ctx, n.ID)
+	require.NoError(err, "NodeStatus")
+	require.EqualValues(101, status.LastHeartbeatHeight, "last heartbeat height should be recorded")
+	require.True(status.IsFresh(101, 1), "node should be fresh")
+	require.False(status.IsFresh(111, 10), "node should not be fresh after the window passes")
+
+	// Heartbeats should be rate limited.
+	cfg.BlockHeight = 105
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+	ctx.SetTxSigner(nodeSigner.Public())
+
+	err = app.heartbeatNode(ctx, state)
+	require.Equal(registry.ErrHeartbeatTooFrequent, err, "too frequent heartbeat should fail")
+
+	cfg.BlockHeight = 110
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+	ctx.SetTxSigner(nodeSigner.Public())
+
+	err = app.heartbeatNode(ctx, state)
+	require.NoError(err, "heartbeat should succeed after the minimum interval")
+
+	// Heartbeats from expired nodes should be rejected.
+	cfg.BlockHeight = 200
+	cfg.CurrentEpoch = 10
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+	ctx.SetTxSigner(nodeSigner.Public())
+
+	err = app.heartbeatNode(ctx, state)
+	require.Equal(registry.ErrNodeExpired, err, "heartbeat from an expired node should fail")
+}
+
+func TestSuspendNode(t *testing.T) {
+	require := requirePkg.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{
+		BlockHeight:  100,
+		CurrentEpoch: 1,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := registryApplication{appState}
+	state := registryState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration: 5,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+
+	nodeSigner := memorySigner.NewTestSigner("suspend node signer")
+	entitySigner := memorySigner.NewTestSigner("suspend entity signer")
+	n := &node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         nodeSigner.Public(),
+		EntityID:   entitySigner.Public(),
+		Expiration: 3,
+	}
+
+	// Suspending unregistered nodes should fail.
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.suspendNode(ctx, state, &registry.SuspendNode{NodeID: n.ID})
+	require.Equal(registry.ErrNoSuchNode, err, "suspending an unregistered node should fail")
+
+	sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, n)
+	require.NoError(err, "MultiSignNode")
+	err = state.SetNode(ctx, nil, n, sigNode)
+	require.NoError(err, "SetNode")
+	err = state.SetNodeStatus(ctx, n.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+
+	// Only the owning entity may suspend or resume the node.
+	ctx.SetTxSigner(nodeSigner.Public())
+	err = app.suspendNode(ctx, state, &registry.SuspendNode{NodeID: n.ID})
+	require.Equal(registry.ErrBadEntityForNode, err, "suspending a node by the node itself should fail")
+
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.resumeNode(ctx, state, &registry.ResumeNode{NodeID: n.ID})
+	require.Equal(registry.ErrNodeNotSuspended, err, "resuming a node that is not suspended should fail")
+
+	err = app.suspendNode(ctx, state, &registry.SuspendNode{NodeID: n.ID})
+	require.NoError(err, "suspending a node should succeed")
+
+	status, err := state.NodeStatus(ctx, n.ID)
+	require.NoError(err, "NodeStatus")
+	require.True(status.IsSuspended(), "node should be suspended")
+
+	err = app.suspendNode(ctx, state, &registry.SuspendNode{NodeID: n.ID})
+	require.Equal(registry.ErrNodeAlreadySuspended, err, "suspending a suspended node should fail")
+
+	ctx.SetTxSigner(nodeSigner.Public())
+	err = app.resumeNode(ctx, state, &registry.ResumeNode{NodeID: n.ID})
+	require.Equal(registry.ErrBadEntityForNode, err, "resuming a node by the node itself should fail")
+
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.resumeNode(ctx, state, &registry.ResumeNode{NodeID: n.ID})
+	require.NoError(err, "resuming a node should succeed")
+
+	status, err = state.NodeStatus(ctx, n.ID)
+	require.NoError(err, "NodeStatus")
+	require.False(status.IsSuspended(), "node should no longer be suspended")
+}
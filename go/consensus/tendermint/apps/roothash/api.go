@@ -44,6 +44,21 @@ var (
 	// merge discrepancy detected events (value is a CBOR serialized
 	// ValueExecutionDiscrepancyDetected).
 	KeyExecutionDiscrepancyDetected = []byte("execution-discrepancy")
+	// KeyExecutorStragglers is an ABCI event attribute key for executor
+	// stragglers events (value is a CBOR serialized ValueExecutorStragglers).
+	KeyExecutorStragglers = []byte("executor-stragglers")
+	// KeyLivenessSummary is an ABCI event attribute key for runtime liveness
+	// summary events (value is a CBOR serialized ValueLivenessSummary).
+	KeyLivenessSummary = []byte("liveness-summary")
+	// KeyExecutorParticipation is an ABCI event attribute key for executor
+	// participation events (value is a CBOR serialized ValueExecutorParticipation).
+	KeyExecutorParticipation = []byte("executor-participation")
+	// KeyRuntimeReset is an ABCI event attribute key for runtime reset events
+	// (value is a CBOR serialized ValueRuntimeReset).
+	KeyRuntimeReset = []byte("runtime-reset")
+	// KeyRoundSuspended is an ABCI event attribute key for round suspended events
+	// (value is a CBOR serialized ValueRoundSuspended).
+	KeyRoundSuspended = []byte("round-suspended")
 	// KeyFinalized is an ABCI event attribute key for finalized blocks
 	// (value is a CBOR serialized ValueFinalized).
 	KeyFinalized = []byte("finalized")
@@ -79,3 +94,33 @@ type ValueExecutionDiscrepancyDetected struct {
 	ID    common.Namespace                           `json:"id"`
 	Event roothash.ExecutionDiscrepancyDetectedEvent `json:"event"`
 }
+
+// ValueExecutorStragglers is the value component of a KeyExecutorStragglers.
+type ValueExecutorStragglers struct {
+	ID    common.Namespace                 `json:"id"`
+	Event roothash.ExecutorStragglersEvent `json:"event"`
+}
+
+// ValueLivenessSummary is the value component of a KeyLivenessSummary.
+type ValueLivenessSummary struct {
+	ID    common.Namespace              `json:"id"`
+	Event roothash.LivenessSummaryEvent `json:"event"`
+}
+
+// ValueExecutorParticipation is the value component of a KeyExecutorParticipation.
+type ValueExecutorParticipation struct {
+	ID    common.Namespace                    `json:"id"`
+	Event roothash.ExecutorParticipationEvent `json:"event"`
+}
+
+// ValueRuntimeReset is the value component of a KeyRuntimeReset.
+type ValueRuntimeReset struct {
+	ID    common.Namespace           `json:"id"`
+	Event roothash.RuntimeResetEvent `json:"event"`
+}
+
+// ValueRoundSuspended is the value component of a KeyRoundSuspended.
+type ValueRoundSuspended struct {
+	ID    common.Namespace             `json:"id"`
+	Event roothash.RoundSuspendedEvent `json:"event"`
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 // Query is the roothash query interface.
@@ -15,6 +18,10 @@ type Query interface {
 	LatestBlock(context.Context, common.Namespace) (*block.Block, error)
 	GenesisBlock(context.Context, common.Namespace) (*block.Block, error)
 	Genesis(context.Context) (*roothash.Genesis, error)
+	StragglerCounters(context.Context, common.Namespace, epochtime.EpochTime) (map[signature.PublicKey]uint64, error)
+	LivenessSummary(context.Context, common.Namespace, epochtime.EpochTime) (*roothash.LivenessSummary, error)
+	ExecutorParticipation(context.Context, common.Namespace, epochtime.EpochTime) (map[signature.PublicKey]*roothash.ExecutorParticipation, error)
+	StakingEventSubscription(context.Context, common.Namespace) (*roothash.StakingEventSubscription, error)
 }
 
 // QueryFactory is the roothash query factory.
@@ -51,6 +58,50 @@ func (rq *rootHashQuerier) GenesisBlock(ctx context.Context, id common.Namespace
 	return runtime.GenesisBlock, nil
 }
 
+func (rq *rootHashQuerier) StragglerCounters(
+	ctx context.Context,
+	id common.Namespace,
+	epoch epochtime.EpochTime,
+) (map[signature.PublicKey]uint64, error) {
+	return rq.state.StragglerCounters(ctx, id, epoch)
+}
+
+func (rq *rootHashQuerier) LivenessSummary(
+	ctx context.Context,
+	id common.Namespace,
+	epoch epochtime.EpochTime,
+) (*roothash.LivenessSummary, error) {
+	return rq.state.LivenessSummary(ctx, id, epoch)
+}
+
+func (rq *rootHashQuerier) ExecutorParticipation(
+	ctx context.Context,
+	id common.Namespace,
+	epoch epochtime.EpochTime,
+) (map[signature.PublicKey]*roothash.ExecutorParticipation, error) {
+	return rq.state.ExecutorParticipation(ctx, id, epoch)
+}
+
+func (rq *rootHashQuerier) StakingEventSubscription(
+	ctx context.Context,
+	id common.Namespace,
+) (*roothash.StakingEventSubscription, error) {
+	runtime, err := rq.state.RuntimeState(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &roothash.StakingEventSubscription{
+		Subscribed: runtime.StakingEventsSubscribed,
+		Address:    staking.NewRuntimeAddress(id),
+	}
+	if sub.Subscribed {
+		sub.FromHeight = runtime.StakingEventsHeight
+		sub.ToHeight = runtime.CurrentBlockHeight
+	}
+	return sub, nil
+}
+
 func (app *rootHashApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -10,7 +10,9 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	registryapp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry"
@@ -29,7 +31,24 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-var _ tmapi.Application = (*rootHashApplication)(nil)
+const (
+	// stragglerCountersRetentionEpochs is the number of epochs for which executor straggler
+	// counters are retained in state.
+	stragglerCountersRetentionEpochs = 16
+
+	// livenessSummaryRetentionEpochs is the number of epochs for which runtime liveness
+	// summaries are retained in state.
+	livenessSummaryRetentionEpochs = 16
+
+	// executorParticipationRetentionEpochs is the number of epochs for which executor
+	// participation statistics are retained in state.
+	executorParticipationRetentionEpochs = 16
+)
+
+var (
+	_ tmapi.Application            = (*rootHashApplication)(nil)
+	_ tmapi.TransactionPrioritizer = (*rootHashApplication)(nil)
+)
 
 type rootHashApplication struct {
 	state tmapi.ApplicationState
@@ -51,6 +70,17 @@ func (app *rootHashApplication) Blessed() bool {
 	return false
 }
 
+// Implements tmapi.TransactionPrioritizer.
+func (app *rootHashApplication) PriorityClass(method transaction.MethodName) tmapi.PriorityClass {
+	switch method {
+	case roothash.MethodExecutorCommit, roothash.MethodExecutorProposerTimeout, roothash.MethodEvidence:
+		// Runtime liveness depends on commitments being processed in time.
+		return tmapi.PriorityClassHigh
+	default:
+		return tmapi.PriorityClassNormal
+	}
+}
+
 func (app *rootHashApplication) Dependencies() []string {
 	return []string{schedulerapp.AppName, stakingapp.AppName}
 }
@@ -68,12 +98,140 @@ func (app *rootHashApplication) BeginBlock(ctx *tmapi.Context, request types.Req
 	// Check if there was an epoch transition.
 	epochChanged, epoch := app.state.EpochChanged(ctx)
 
+	if epochChanged && epoch > stragglerCountersRetentionEpochs {
+		state := roothashState.NewMutableState(ctx.State())
+		if err := state.PruneStragglerCounters(ctx, epoch-stragglerCountersRetentionEpochs); err != nil {
+			return fmt.Errorf("failed to prune straggler counters: %w", err)
+		}
+	}
+	if epochChanged {
+		if err := app.onEpochEnd(ctx, epoch); err != nil {
+			return err
+		}
+	}
+
 	if epochChanged || rescheduled {
 		return app.onCommitteeChanged(ctx, epoch)
 	}
 	return nil
 }
 
+// onEpochEnd emits the liveness summaries and executor participation statistics of all runtimes
+// for the epoch that just ended and prunes old ones.
+func (app *rootHashApplication) onEpochEnd(ctx *tmapi.Context, epoch epochtime.EpochTime) error {
+	state := roothashState.NewMutableState(ctx.State())
+
+	previousEpoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight())
+	if err != nil {
+		return fmt.Errorf("failed to get previous epoch: %w", err)
+	}
+
+	runtimes, err := state.Runtimes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get runtimes: %w", err)
+	}
+	for _, rtState := range runtimes {
+		var summary *roothash.LivenessSummary
+		if summary, err = state.LivenessSummary(ctx, rtState.Runtime.ID, previousEpoch); err != nil {
+			return fmt.Errorf("failed to get liveness summary: %w", err)
+		}
+
+		tagV := ValueLivenessSummary{
+			ID: rtState.Runtime.ID,
+			Event: roothash.LivenessSummaryEvent{
+				Epoch:   previousEpoch,
+				Summary: *summary,
+			},
+		}
+		ctx.EmitEvent(
+			tmapi.NewEventBuilder(app.Name()).
+				Attribute(KeyLivenessSummary, cbor.Marshal(tagV)).
+				Attribute(KeyRuntimeID, ValueRuntimeID(rtState.Runtime.ID)),
+		)
+
+		var participation map[signature.PublicKey]*roothash.ExecutorParticipation
+		if participation, err = state.ExecutorParticipation(ctx, rtState.Runtime.ID, previousEpoch); err != nil {
+			return fmt.Errorf("failed to get executor participation: %w", err)
+		}
+		if len(participation) == 0 {
+			continue
+		}
+
+		partV := ValueExecutorParticipation{
+			ID: rtState.Runtime.ID,
+			Event: roothash.ExecutorParticipationEvent{
+				Epoch: previousEpoch,
+				Nodes: make(map[signature.PublicKey]roothash.ExecutorParticipation),
+			},
+		}
+		for id, p := range participation {
+			partV.Event.Nodes[id] = *p
+		}
+		ctx.EmitEvent(
+			tmapi.NewEventBuilder(app.Name()).
+				Attribute(KeyExecutorParticipation, cbor.Marshal(partV)).
+				Attribute(KeyRuntimeID, ValueRuntimeID(rtState.Runtime.ID)),
+		)
+	}
+
+	if epoch > livenessSummaryRetentionEpochs {
+		if err = state.PruneLivenessSummaries(ctx, epoch-livenessSummaryRetentionEpochs); err != nil {
+			return fmt.Errorf("failed to prune liveness summaries: %w", err)
+		}
+	}
+	if epoch > executorParticipationRetentionEpochs {
+		if err = state.PruneExecutorParticipation(ctx, epoch-executorParticipationRetentionEpochs); err != nil {
+			return fmt.Errorf("failed to prune executor participation: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateLivenessSummary updates the runtime's liveness summary for the current epoch.
+func (app *rootHashApplication) updateLivenessSummary(
+	ctx *tmapi.Context,
+	runtimeID common.Namespace,
+	fn func(*roothash.LivenessSummary),
+) error {
+	state := roothashState.NewMutableState(ctx.State())
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	summary, err := state.LivenessSummary(ctx, runtimeID, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to get liveness summary: %w", err)
+	}
+	fn(summary)
+	if err = state.SetLivenessSummary(ctx, runtimeID, epoch, summary); err != nil {
+		return fmt.Errorf("failed to set liveness summary: %w", err)
+	}
+	return nil
+}
+
+// updateExecutorParticipation updates the participation statistics of the given executor node
+// for the current epoch.
+func (app *rootHashApplication) updateExecutorParticipation(
+	ctx *tmapi.Context,
+	runtimeID common.Namespace,
+	nodeID signature.PublicKey,
+	fn func(*roothash.ExecutorParticipation),
+) error {
+	state := roothashState.NewMutableState(ctx.State())
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	if err = state.UpdateExecutorParticipation(ctx, runtimeID, epoch, nodeID, fn); err != nil {
+		return fmt.Errorf("failed to update executor participation: %w", err)
+	}
+	return nil
+}
+
 func (app *rootHashApplication) onCommitteeChanged(ctx *tmapi.Context, epoch epochtime.EpochTime) error {
 	state := roothashState.NewMutableState(ctx.State())
 	schedState := schedulerState.NewMutableState(ctx.State())
@@ -110,6 +268,7 @@ func (app *rootHashApplication) onCommitteeChanged(ctx *tmapi.Context, epoch epo
 		// Since the runtime is in the list of active runtimes in the registry we
 		// can safely clear the suspended flag.
 		rtState.Suspended = false
+		rtState.RoundSuspended = false
 
 		// Prepare new runtime committees based on what the scheduler did.
 		executorPool, empty, err := app.prepareNewCommittees(ctx, epoch, rtState, schedState, regState)
@@ -166,9 +325,24 @@ func (app *rootHashApplication) onCommitteeChanged(ctx *tmapi.Context, epoch epo
 		// Update the runtime descriptor to the latest per-epoch value.
 		rtState.Runtime = rt
 
+		// Make sure that the new committee is live enough to start the new round.
+		if !rtState.Suspended {
+			if err = app.checkRoundLiveness(ctx, rtState); err != nil {
+				return err
+			}
+		}
+
 		if err = state.SetRuntimeState(ctx, rtState); err != nil {
 			return fmt.Errorf("failed to set runtime state: %w", err)
 		}
+
+		// Prune processed evidence markers that can no longer be used as the evidence has
+		// expired anyway.
+		if round := rtState.CurrentBlock.Header.Round; round > params.MaxEvidenceAge {
+			if err = state.PruneEvidence(ctx, rt.ID, round-params.MaxEvidenceAge); err != nil {
+				return fmt.Errorf("failed to prune evidence: %w", err)
+			}
+		}
 	}
 
 	return nil
@@ -198,6 +372,90 @@ func (app *rootHashApplication) suspendUnpaidRuntime(
 	return nil
 }
 
+// committeeLiveness returns the number of executor committee members that are registered and not
+// frozen, the committee size and whether the runtime's minimum live committee percentage is
+// satisfied.
+func (app *rootHashApplication) committeeLiveness(
+	ctx *tmapi.Context,
+	rtState *roothashState.RuntimeState,
+) (live, total uint64, ok bool, err error) {
+	minPercent := uint64(rtState.Runtime.Executor.MinLiveCommitteePercent)
+	if minPercent == 0 || rtState.ExecutorPool == nil || rtState.ExecutorPool.Committee == nil {
+		return 0, 0, true, nil
+	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	regState := registryState.NewMutableState(ctx.State())
+	for _, member := range rtState.ExecutorPool.Committee.Members {
+		total++
+
+		var n *node.Node
+		n, err = regState.Node(ctx, member.PublicKey)
+		switch err {
+		case nil:
+		case registry.ErrNoSuchNode:
+			continue
+		default:
+			return 0, 0, false, fmt.Errorf("failed to fetch node %s: %w", member.PublicKey, err)
+		}
+		if n.IsExpired(uint64(epoch)) {
+			continue
+		}
+
+		var status *registry.NodeStatus
+		if status, err = regState.NodeStatus(ctx, member.PublicKey); err != nil {
+			return 0, 0, false, fmt.Errorf("failed to fetch node status %s: %w", member.PublicKey, err)
+		}
+		if status.IsFrozen() {
+			continue
+		}
+		live++
+	}
+
+	return live, total, live*100 >= minPercent*total, nil
+}
+
+// checkRoundLiveness checks whether enough executor committee members are live at the start of
+// the current round and suspends the round otherwise.
+func (app *rootHashApplication) checkRoundLiveness(ctx *tmapi.Context, rtState *roothashState.RuntimeState) error {
+	live, total, ok, err := app.committeeLiveness(ctx, rtState)
+	if err != nil {
+		return err
+	}
+	rtState.RoundSuspended = !ok
+	if ok {
+		return nil
+	}
+
+	round := rtState.CurrentBlock.Header.Round + 1
+	ctx.Logger().Warn("not enough live executor committee members, suspending round",
+		"runtime_id", rtState.Runtime.ID,
+		"round", round,
+		"live_members", live,
+		"committee_size", total,
+		"min_live_committee_percent", rtState.Runtime.Executor.MinLiveCommitteePercent,
+	)
+
+	tagV := ValueRoundSuspended{
+		ID: rtState.Runtime.ID,
+		Event: roothash.RoundSuspendedEvent{
+			Round:         round,
+			LiveMembers:   live,
+			CommitteeSize: total,
+		},
+	}
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			Attribute(KeyRoundSuspended, cbor.Marshal(tagV)).
+			Attribute(KeyRuntimeID, ValueRuntimeID(rtState.Runtime.ID)),
+	)
+	return nil
+}
+
 func (app *rootHashApplication) prepareNewCommittees(
 	ctx *tmapi.Context,
 	epoch epochtime.EpochTime,
@@ -239,6 +497,14 @@ func (app *rootHashApplication) emitEmptyBlock(ctx *tmapi.Context, runtime *root
 
 	runtime.CurrentBlock = blk
 	runtime.CurrentBlockHeight = ctx.BlockHeight()
+	if hdrType == block.RoundFailed {
+		err := app.updateLivenessSummary(ctx, runtime.Runtime.ID, func(s *roothash.LivenessSummary) {
+			s.FailedRounds++
+		})
+		if err != nil {
+			return err
+		}
+	}
 	if runtime.ExecutorPool != nil {
 		// Clear timeout if there was one scheduled.
 		if runtime.ExecutorPool.NextTimeout != commitment.TimeoutNever {
@@ -280,6 +546,20 @@ func (app *rootHashApplication) ExecuteTx(ctx *tmapi.Context, tx *transaction.Tr
 		}
 
 		return app.executorProposerTimeout(ctx, state, &xc)
+	case roothash.MethodEvidence:
+		var ev roothash.Evidence
+		if err := cbor.Unmarshal(tx.Body, &ev); err != nil {
+			return err
+		}
+
+		return app.submitEvidence(ctx, state, &ev)
+	case roothash.MethodRuntimeReset:
+		var rr roothash.RuntimeReset
+		if err := cbor.Unmarshal(tx.Body, &rr); err != nil {
+			return err
+		}
+
+		return app.runtimeReset(ctx, state, &rr)
 	default:
 		return roothash.ErrInvalidArgument
 	}
@@ -419,6 +699,17 @@ func (app *rootHashApplication) processRoundTimeout(ctx *tmapi.Context, state *r
 		return fmt.Errorf("no scheduled timeout")
 	}
 
+	err = app.updateLivenessSummary(ctx, runtimeID, func(s *roothash.LivenessSummary) {
+		s.Timeouts++
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = app.processStragglers(ctx, state, rtState); err != nil {
+		return fmt.Errorf("failed to process stragglers: %w", err)
+	}
+
 	if err = app.tryFinalizeBlock(ctx, rtState, true); err != nil {
 		ctx.Logger().Error("failed to finalize block",
 			"err", err,
@@ -432,6 +723,60 @@ func (app *rootHashApplication) processRoundTimeout(ctx *tmapi.Context, state *r
 	return nil
 }
 
+// processStragglers records the executor committee members that failed to submit their
+// commitments before the round timeout.
+func (app *rootHashApplication) processStragglers(
+	ctx *tmapi.Context,
+	state *roothashState.MutableState,
+	rtState *roothashState.RuntimeState,
+) error {
+	stragglers := rtState.ExecutorPool.GetStragglers()
+	if len(stragglers) == 0 {
+		return nil
+	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+
+	round := rtState.CurrentBlock.Header.Round + 1
+	ctx.Logger().Warn("executor committee members failed to commit in time",
+		"runtime_id", rtState.Runtime.ID,
+		"round", round,
+		"discrepancy", rtState.ExecutorPool.Discrepancy,
+		"stragglers", stragglers,
+	)
+
+	for _, id := range stragglers {
+		if err = state.IncrementStragglerCounter(ctx, rtState.Runtime.ID, epoch, id); err != nil {
+			return fmt.Errorf("failed to increment straggler counter: %w", err)
+		}
+		err = state.UpdateExecutorParticipation(ctx, rtState.Runtime.ID, epoch, id, func(p *roothash.ExecutorParticipation) {
+			p.Failures++
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update executor participation: %w", err)
+		}
+	}
+
+	tagV := ValueExecutorStragglers{
+		ID: rtState.Runtime.ID,
+		Event: roothash.ExecutorStragglersEvent{
+			Round:       round,
+			Discrepancy: rtState.ExecutorPool.Discrepancy,
+			Nodes:       stragglers,
+		},
+	}
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			Attribute(KeyExecutorStragglers, cbor.Marshal(tagV)).
+			Attribute(KeyRuntimeID, ValueRuntimeID(rtState.Runtime.ID)),
+	)
+
+	return nil
+}
+
 // tryFinalizeExecutorCommits tries to finalize the executor commitments into a new runtime block.
 // The caller must take care of clearing and scheduling the round timeouts.
 func (app *rootHashApplication) tryFinalizeExecutorCommits(
@@ -456,7 +801,19 @@ func (app *rootHashApplication) tryFinalizeExecutorCommits(
 		blk := block.NewEmptyBlock(rtState.CurrentBlock, uint64(ctx.Now().Unix()), block.Normal)
 		blk.Header.IORoot = *hdr.IORoot
 		blk.Header.StateRoot = *hdr.StateRoot
-		// Messages omitted on purpose.
+		blk.Header.Messages = hdr.Messages
+
+		// Record the transaction scheduler that proposed the finalized round.
+		var proposer *scheduler.CommitteeNode
+		if proposer, err = commitment.GetTransactionScheduler(rtState.ExecutorPool.Committee, blockNr); err != nil {
+			return nil, fmt.Errorf("failed to get transaction scheduler: %w", err)
+		}
+		err = app.updateExecutorParticipation(ctx, runtime.ID, proposer.PublicKey, func(p *roothash.ExecutorParticipation) {
+			p.Proposals++
+		})
+		if err != nil {
+			return nil, err
+		}
 
 		// Timeout will be cleared by caller.
 		rtState.ExecutorPool.ResetCommitments()
@@ -487,7 +844,11 @@ func (app *rootHashApplication) tryFinalizeExecutorCommits(
 				Attribute(KeyExecutionDiscrepancyDetected, cbor.Marshal(tagV)).
 				Attribute(KeyRuntimeID, ValueRuntimeID(runtime.ID)),
 		)
-		return nil, nil
+
+		err = app.updateLivenessSummary(ctx, runtime.ID, func(s *roothash.LivenessSummary) {
+			s.Discrepancies++
+		})
+		return nil, err
 	default:
 	}
 
@@ -509,11 +870,15 @@ func (app *rootHashApplication) postProcessFinalizedBlock(ctx *tmapi.Context, rt
 	sc := ctx.StartCheckpoint()
 	defer sc.Close()
 
+	stakingEventsSubscribed := rtState.StakingEventsSubscribed
 	for _, message := range blk.Header.Messages {
-		// Currently there are no valid roothash messages, so any message
-		// is treated as unsatisfactory. This is the place which would
-		// otherwise contain message handlers.
-		unsat := errors.New("tendermint/roothash: message is invalid")
+		var unsat error
+		switch {
+		case message.StakingSubscribe != nil:
+			stakingEventsSubscribed = message.StakingSubscribe.Subscribe
+		default:
+			unsat = errors.New("tendermint/roothash: message is invalid")
+		}
 
 		if unsat != nil {
 			ctx.Logger().Error("handler not satisfied with message",
@@ -533,10 +898,32 @@ func (app *rootHashApplication) postProcessFinalizedBlock(ctx *tmapi.Context, rt
 
 	sc.Commit()
 
+	// All staking events up to the height of the parent block have been delivered to the
+	// runtime as inputs for this round.
+	if stakingEventsSubscribed {
+		if !rtState.StakingEventsSubscribed {
+			ctx.Logger().Debug("runtime subscribed to staking events",
+				"runtime_id", rtState.Runtime.ID,
+				"round", blk.Header.Round,
+			)
+		}
+		rtState.StakingEventsHeight = rtState.CurrentBlockHeight
+	} else {
+		rtState.StakingEventsHeight = 0
+	}
+	rtState.StakingEventsSubscribed = stakingEventsSubscribed
+
 	// All good. Hook up the new block.
 	rtState.CurrentBlock = blk
 	rtState.CurrentBlockHeight = ctx.BlockHeight()
 
+	err := app.updateLivenessSummary(ctx, rtState.Runtime.ID, func(s *roothash.LivenessSummary) {
+		s.FinalizedRounds++
+	})
+	if err != nil {
+		return err
+	}
+
 	tagV := ValueFinalized{
 		ID:    rtState.Runtime.ID,
 		Round: blk.Header.Round,
@@ -590,16 +977,22 @@ func (app *rootHashApplication) tryFinalizeBlock(
 		}
 	}(rtState.ExecutorPool.NextTimeout)
 
+	round := rtState.CurrentBlock.Header.Round
 	finalizedBlock, err := app.tryFinalizeExecutorCommits(ctx, rtState, forced)
 	if err != nil {
 		return fmt.Errorf("failed to finalize executor commits: %w", err)
 	}
-	if finalizedBlock == nil {
-		return nil
+	if finalizedBlock != nil {
+		if err = app.postProcessFinalizedBlock(ctx, rtState, finalizedBlock); err != nil {
+			return fmt.Errorf("failed to post process finalized block: %w", err)
+		}
 	}
 
-	if err = app.postProcessFinalizedBlock(ctx, rtState, finalizedBlock); err != nil {
-		return fmt.Errorf("failed to post process finalized block: %w", err)
+	// In case a new round has started, make sure that the committee is still live enough.
+	if rtState.CurrentBlock.Header.Round != round {
+		if err = app.checkRoundLiveness(ctx, rtState); err != nil {
+			return fmt.Errorf("failed to check committee liveness: %w", err)
+		}
 	}
 	return nil
 }
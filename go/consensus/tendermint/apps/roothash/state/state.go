@@ -1,13 +1,16 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
@@ -28,6 +31,29 @@ var (
 	//
 	// The format is (height, runtimeID). Value is runtimeID.
 	roundTimeoutQueueKeyFmt = keyformat.New(0x22, int64(0), keyformat.H(&common.Namespace{}))
+	// stragglerCountersKeyFmt is the key format used for per-epoch executor straggler counters.
+	//
+	// The format is (epoch, runtimeID, nodeID). Value is a CBOR-serialized counter.
+	stragglerCountersKeyFmt = keyformat.New(0x23, uint64(0), keyformat.H(&common.Namespace{}), &signature.PublicKey{})
+	// evidenceKeyFmt is the key format used for tracking processed evidence of node misbehavior.
+	//
+	// The format is (runtimeID, round, nodeID). Value is the CBOR-serialized consensus height
+	// at which the evidence was processed.
+	evidenceKeyFmt = keyformat.New(0x24, keyformat.H(&common.Namespace{}), uint64(0), &signature.PublicKey{})
+	// livenessSummaryKeyFmt is the key format used for per-epoch runtime liveness summaries.
+	//
+	// The format is (epoch, runtimeID). Value is CBOR-serialized roothash.LivenessSummary.
+	livenessSummaryKeyFmt = keyformat.New(0x25, uint64(0), keyformat.H(&common.Namespace{}))
+	// archivedRuntimeStateKeyFmt is the key format used for runtime states archived on reset.
+	//
+	// The format is (runtimeID, height). Value is CBOR-serialized ArchivedRuntimeState.
+	archivedRuntimeStateKeyFmt = keyformat.New(0x26, keyformat.H(&common.Namespace{}), int64(0))
+	// executorParticipationKeyFmt is the key format used for per-epoch executor participation
+	// statistics.
+	//
+	// The format is (epoch, runtimeID, nodeID). Value is CBOR-serialized
+	// roothash.ExecutorParticipation.
+	executorParticipationKeyFmt = keyformat.New(0x27, uint64(0), keyformat.H(&common.Namespace{}), &signature.PublicKey{})
 )
 
 // RuntimeState is the per-runtime roothash state.
@@ -35,12 +61,32 @@ type RuntimeState struct {
 	Runtime   *registry.Runtime `json:"runtime"`
 	Suspended bool              `json:"suspended,omitempty"`
 
+	// RoundSuspended is true iff the current round has been suspended because not enough
+	// executor committee members were live at the start of the round.
+	RoundSuspended bool `json:"round_suspended,omitempty"`
+
 	GenesisBlock *block.Block `json:"genesis_block"`
 
 	CurrentBlock       *block.Block `json:"current_block"`
 	CurrentBlockHeight int64        `json:"current_block_height"`
 
 	ExecutorPool *commitment.Pool `json:"executor_pool"`
+
+	// StakingEventsSubscribed is true iff the runtime is subscribed to staking events affecting
+	// its runtime account.
+	StakingEventsSubscribed bool `json:"staking_events_subscribed,omitempty"`
+	// StakingEventsHeight is the consensus height up to (and including) which staking events
+	// affecting the runtime account have already been delivered to the runtime.
+	StakingEventsHeight int64 `json:"staking_events_height,omitempty"`
+}
+
+// ArchivedRuntimeState is a per-runtime roothash state that was archived when the runtime was
+// reset.
+type ArchivedRuntimeState struct {
+	// Height is the consensus height at which the runtime state was archived.
+	Height int64 `json:"height"`
+	// State is the archived runtime state.
+	State *RuntimeState `json:"state"`
 }
 
 // ImmutableState is the immutable roothash state wrapper.
@@ -143,6 +189,132 @@ func (s *ImmutableState) Runtimes(ctx context.Context) ([]*RuntimeState, error)
 	return runtimes, nil
 }
 
+// ArchivedRuntimeStates returns the runtime states archived on reset of the given runtime, ordered
+// by the consensus height at which they were archived.
+func (s *ImmutableState) ArchivedRuntimeStates(ctx context.Context, id common.Namespace) ([]*ArchivedRuntimeState, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	prefix := archivedRuntimeStateKeyFmt.Encode(&id)
+	var archived []*ArchivedRuntimeState
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var state ArchivedRuntimeState
+		if err := cbor.Unmarshal(it.Value(), &state); err != nil {
+			return nil, api.UnavailableStateError(err)
+		}
+
+		archived = append(archived, &state)
+	}
+	if it.Err() != nil {
+		return nil, api.UnavailableStateError(it.Err())
+	}
+	return archived, nil
+}
+
+// StragglerCounters returns the executor straggler counters of all committee members of the
+// given runtime for the given epoch.
+func (s *ImmutableState) StragglerCounters(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+) (map[signature.PublicKey]uint64, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	prefix := stragglerCountersKeyFmt.Encode(uint64(epoch), &runtimeID)
+	counters := make(map[signature.PublicKey]uint64)
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var (
+			decEpoch   uint64
+			hRuntimeID keyformat.PreHashed
+			nodeID     signature.PublicKey
+		)
+		if !stragglerCountersKeyFmt.Decode(it.Key(), &decEpoch, &hRuntimeID, &nodeID) {
+			break
+		}
+
+		var count uint64
+		if err := cbor.Unmarshal(it.Value(), &count); err != nil {
+			return nil, api.UnavailableStateError(err)
+		}
+		counters[nodeID] = count
+	}
+	if it.Err() != nil {
+		return nil, api.UnavailableStateError(it.Err())
+	}
+	return counters, nil
+}
+
+// LivenessSummary returns the liveness summary of the given runtime for the given epoch.
+func (s *ImmutableState) LivenessSummary(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+) (*roothash.LivenessSummary, error) {
+	raw, err := s.is.Get(ctx, livenessSummaryKeyFmt.Encode(uint64(epoch), &runtimeID))
+	if err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+
+	var summary roothash.LivenessSummary
+	if raw == nil {
+		return &summary, nil
+	}
+	if err = cbor.Unmarshal(raw, &summary); err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+	return &summary, nil
+}
+
+// ExecutorParticipation returns the participation statistics of the executor nodes of the given
+// runtime for the given epoch.
+func (s *ImmutableState) ExecutorParticipation(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+) (map[signature.PublicKey]*roothash.ExecutorParticipation, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	prefix := executorParticipationKeyFmt.Encode(uint64(epoch), &runtimeID)
+	participation := make(map[signature.PublicKey]*roothash.ExecutorParticipation)
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var (
+			decEpoch   uint64
+			hRuntimeID keyformat.PreHashed
+			nodeID     signature.PublicKey
+		)
+		if !executorParticipationKeyFmt.Decode(it.Key(), &decEpoch, &hRuntimeID, &nodeID) {
+			break
+		}
+
+		var p roothash.ExecutorParticipation
+		if err := cbor.Unmarshal(it.Value(), &p); err != nil {
+			return nil, api.UnavailableStateError(err)
+		}
+		participation[nodeID] = &p
+	}
+	if it.Err() != nil {
+		return nil, api.UnavailableStateError(it.Err())
+	}
+	return participation, nil
+}
+
+// EvidenceProcessed returns true iff evidence of misbehavior of the given node in the given
+// runtime round has already been processed.
+func (s *ImmutableState) EvidenceProcessed(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	round uint64,
+	nodeID signature.PublicKey,
+) (bool, error) {
+	raw, err := s.is.Get(ctx, evidenceKeyFmt.Encode(&runtimeID, round, &nodeID))
+	if err != nil {
+		return false, api.UnavailableStateError(err)
+	}
+	return raw != nil, nil
+}
+
 // ConsensusParameters returns the roothash consensus parameters.
 func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*roothash.ConsensusParameters, error) {
 	raw, err := s.is.Get(ctx, parametersKeyFmt.Encode())
@@ -200,3 +372,197 @@ func (s *MutableState) ClearRoundTimeout(ctx context.Context, runtimeID common.N
 	err := s.ms.Remove(ctx, roundTimeoutQueueKeyFmt.Encode(height, &runtimeID))
 	return api.UnavailableStateError(err)
 }
+
+// IncrementStragglerCounter increments the executor straggler counter of the given node for
+// the given runtime and epoch.
+func (s *MutableState) IncrementStragglerCounter(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+	nodeID signature.PublicKey,
+) error {
+	key := stragglerCountersKeyFmt.Encode(uint64(epoch), &runtimeID, &nodeID)
+	raw, err := s.ms.Get(ctx, key)
+	if err != nil {
+		return api.UnavailableStateError(err)
+	}
+
+	var count uint64
+	if raw != nil {
+		if err = cbor.Unmarshal(raw, &count); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	count++
+
+	err = s.ms.Insert(ctx, key, cbor.Marshal(count))
+	return api.UnavailableStateError(err)
+}
+
+// PruneStragglerCounters removes all executor straggler counters for epochs before the given
+// epoch.
+func (s *MutableState) PruneStragglerCounters(ctx context.Context, before epochtime.EpochTime) error {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Seek(stragglerCountersKeyFmt.Encode()); it.Valid(); it.Next() {
+		var epoch uint64
+		if !stragglerCountersKeyFmt.Decode(it.Key(), &epoch) || epoch >= uint64(before) {
+			break
+		}
+		toDelete = append(toDelete, append([]byte{}, it.Key()...))
+	}
+	if it.Err() != nil {
+		return api.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
+// UpdateExecutorParticipation updates the participation statistics of the given executor node
+// for the given runtime and epoch.
+func (s *MutableState) UpdateExecutorParticipation(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+	nodeID signature.PublicKey,
+	fn func(*roothash.ExecutorParticipation),
+) error {
+	key := executorParticipationKeyFmt.Encode(uint64(epoch), &runtimeID, &nodeID)
+	raw, err := s.ms.Get(ctx, key)
+	if err != nil {
+		return api.UnavailableStateError(err)
+	}
+
+	var p roothash.ExecutorParticipation
+	if raw != nil {
+		if err = cbor.Unmarshal(raw, &p); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	fn(&p)
+
+	err = s.ms.Insert(ctx, key, cbor.Marshal(&p))
+	return api.UnavailableStateError(err)
+}
+
+// PruneExecutorParticipation removes all executor participation statistics for epochs before
+// the given epoch.
+func (s *MutableState) PruneExecutorParticipation(ctx context.Context, before epochtime.EpochTime) error {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Seek(executorParticipationKeyFmt.Encode()); it.Valid(); it.Next() {
+		var epoch uint64
+		if !executorParticipationKeyFmt.Decode(it.Key(), &epoch) || epoch >= uint64(before) {
+			break
+		}
+		toDelete = append(toDelete, append([]byte{}, it.Key()...))
+	}
+	if it.Err() != nil {
+		return api.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
+// SetLivenessSummary sets the liveness summary of the given runtime for the given epoch.
+func (s *MutableState) SetLivenessSummary(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	epoch epochtime.EpochTime,
+	summary *roothash.LivenessSummary,
+) error {
+	err := s.ms.Insert(ctx, livenessSummaryKeyFmt.Encode(uint64(epoch), &runtimeID), cbor.Marshal(summary))
+	return api.UnavailableStateError(err)
+}
+
+// PruneLivenessSummaries removes all runtime liveness summaries for epochs before the given
+// epoch.
+func (s *MutableState) PruneLivenessSummaries(ctx context.Context, before epochtime.EpochTime) error {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Seek(livenessSummaryKeyFmt.Encode()); it.Valid(); it.Next() {
+		var epoch uint64
+		if !livenessSummaryKeyFmt.Decode(it.Key(), &epoch) || epoch >= uint64(before) {
+			break
+		}
+		toDelete = append(toDelete, append([]byte{}, it.Key()...))
+	}
+	if it.Err() != nil {
+		return api.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
+// ArchiveRuntimeState archives the given runtime state at the given consensus height.
+func (s *MutableState) ArchiveRuntimeState(ctx context.Context, state *RuntimeState, height int64) error {
+	err := s.ms.Insert(ctx, archivedRuntimeStateKeyFmt.Encode(&state.Runtime.ID, height), cbor.Marshal(&ArchivedRuntimeState{
+		Height: height,
+		State:  state,
+	}))
+	return api.UnavailableStateError(err)
+}
+
+// SetEvidenceProcessed marks evidence of misbehavior of the given node in the given runtime
+// round as processed at the given consensus height.
+func (s *MutableState) SetEvidenceProcessed(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	round uint64,
+	nodeID signature.PublicKey,
+	height int64,
+) error {
+	err := s.ms.Insert(ctx, evidenceKeyFmt.Encode(&runtimeID, round, &nodeID), cbor.Marshal(height))
+	return api.UnavailableStateError(err)
+}
+
+// PruneEvidence removes all processed evidence markers of the given runtime for rounds before
+// the given round.
+func (s *MutableState) PruneEvidence(ctx context.Context, runtimeID common.Namespace, before uint64) error {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	prefix := evidenceKeyFmt.Encode(&runtimeID)
+	var toDelete [][]byte
+	for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+		var (
+			hRuntimeID keyformat.PreHashed
+			round      uint64
+		)
+		if !evidenceKeyFmt.Decode(it.Key(), &hRuntimeID, &round) || round >= before {
+			break
+		}
+		toDelete = append(toDelete, append([]byte{}, it.Key()...))
+	}
+	if it.Err() != nil {
+		return api.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
@@ -12,10 +12,12 @@ import (
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
+	stakingapp "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 var _ commitment.SignatureVerifier = (*roothashSignatureVerifier)(nil)
@@ -98,6 +100,17 @@ func (app *rootHashApplication) getRuntimeState(
 	if rtState.ExecutorPool == nil {
 		return nil, nil, nil, roothash.ErrNoExecutorPool
 	}
+	if rtState.RoundSuspended {
+		// Resume the round in case enough committee members have become live in the meantime.
+		var ok bool
+		if _, _, ok, err = app.committeeLiveness(ctx, rtState); err != nil {
+			return nil, nil, nil, fmt.Errorf("roothash: failed to check committee liveness: %w", err)
+		}
+		if !ok {
+			return nil, nil, nil, roothash.ErrRoundSuspended
+		}
+		rtState.RoundSuspended = false
+	}
 
 	// Create signature verifier.
 	sv := &roothashSignatureVerifier{
@@ -113,6 +126,22 @@ func (app *rootHashApplication) getRuntimeState(
 	return rtState, sv, nl, nil
 }
 
+// checkExecutorCommitLimits checks the executor commitments against the configured commitment
+// limits that do not depend on the runtime state.
+func checkExecutorCommitLimits(params *roothash.ConsensusParameters, cc *roothash.ExecutorCommit) error {
+	if params.MaxExecutorCommitments > 0 && uint64(len(cc.Commits)) > params.MaxExecutorCommitments {
+		return roothash.ErrTooManyCommitments
+	}
+	if params.MaxExecutorCommitmentSize > 0 {
+		for _, commit := range cc.Commits {
+			if uint64(len(cbor.Marshal(commit))) > params.MaxExecutorCommitmentSize {
+				return roothash.ErrCommitmentTooLarge
+			}
+		}
+	}
+	return nil
+}
+
 func (app *rootHashApplication) executorProposerTimeout(
 	ctx *abciAPI.Context,
 	state *roothashState.MutableState,
@@ -170,6 +199,9 @@ func (app *rootHashApplication) executorProposerTimeout(
 	if err = app.emitEmptyBlock(ctx, rtState, block.RoundFailed); err != nil {
 		return fmt.Errorf("failed to emit empty block: %w", err)
 	}
+	if err = app.checkRoundLiveness(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to check committee liveness: %w", err)
+	}
 
 	// Update runtime state.
 	if err = state.SetRuntimeState(ctx, rtState); err != nil {
@@ -184,11 +216,6 @@ func (app *rootHashApplication) executorCommit(
 	state *roothashState.MutableState,
 	cc *roothash.ExecutorCommit,
 ) (err error) {
-	if ctx.IsCheckOnly() {
-		return nil
-	}
-
-	// Charge gas for this transaction.
 	params, err := state.ConsensusParameters(ctx)
 	if err != nil {
 		ctx.Logger().Error("ComputeCommit: failed to fetch consensus parameters",
@@ -196,6 +223,21 @@ func (app *rootHashApplication) executorCommit(
 		)
 		return err
 	}
+
+	// Enforce commitment limits early so that oversized submissions are rejected on check.
+	if err = checkExecutorCommitLimits(params, cc); err != nil {
+		ctx.Logger().Debug("ComputeCommit: commitment limits exceeded",
+			"err", err,
+			"num_commits", len(cc.Commits),
+		)
+		return err
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
 	if err = ctx.Gas().UseGas(1, roothash.GasOpComputeCommit, params.GasCosts); err != nil {
 		return err
 	}
@@ -206,6 +248,17 @@ func (app *rootHashApplication) executorCommit(
 	}
 
 	for _, commit := range cc.Commits {
+		// Bound the number of outstanding commitments in the executor pool.
+		outstanding := uint64(len(rtState.ExecutorPool.ExecuteCommitments))
+		if params.MaxExecutorCommitments > 0 && outstanding >= params.MaxExecutorCommitments {
+			ctx.Logger().Error("failed to add compute commitment to round, too many outstanding commitments",
+				"round", rtState.CurrentBlock.Header.Round,
+				"outstanding", outstanding,
+				"max_executor_commitments", params.MaxExecutorCommitments,
+			)
+			return roothash.ErrTooManyCommitments
+		}
+
 		if err = rtState.ExecutorPool.AddExecutorCommitment(ctx, rtState.CurrentBlock, sv, nl, &commit); err != nil { // nolint: gosec
 			ctx.Logger().Error("failed to add compute commitment to round",
 				"err", err,
@@ -213,6 +266,13 @@ func (app *rootHashApplication) executorCommit(
 			)
 			return err
 		}
+
+		err = app.updateExecutorParticipation(ctx, cc.ID, commit.Signature.PublicKey, func(p *roothash.ExecutorParticipation) {
+			p.Commits++
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Try to finalize round.
@@ -245,3 +305,227 @@ func (app *rootHashApplication) executorCommit(
 
 	return nil
 }
+
+func (app *rootHashApplication) submitEvidence(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	evidence *roothash.Evidence,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("Evidence: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if params.MaxEvidenceAge == 0 {
+		ctx.Logger().Debug("Evidence: evidence submission disabled")
+		return roothash.ErrInvalidEvidence
+	}
+
+	// Perform stateless evidence checks early so that invalid evidence is rejected on check.
+	if err = evidence.ValidateBasic(); err != nil {
+		ctx.Logger().Debug("Evidence: invalid evidence",
+			"err", err,
+		)
+		return roothash.ErrInvalidEvidence
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, roothash.GasOpEvidence, params.GasCosts); err != nil {
+		return err
+	}
+
+	rtState, err := state.RuntimeState(ctx, evidence.ID)
+	if err != nil {
+		return roothash.ErrInvalidRuntime
+	}
+
+	// The commitments have already been verified by ValidateBasic.
+	commit, err := evidence.EquivocationExecutor.CommitA.Open()
+	if err != nil {
+		return roothash.ErrInvalidEvidence
+	}
+	nodeID := commit.Signature.PublicKey
+	round := commit.Body.Header.Round
+
+	// Executor commitments are for the round following the current block, so evidence
+	// for later rounds cannot be valid.
+	currentRound := rtState.CurrentBlock.Header.Round
+	if round > currentRound+1 {
+		ctx.Logger().Debug("Evidence: evidence for a future round",
+			"round", round,
+			"current_round", currentRound,
+		)
+		return roothash.ErrInvalidEvidence
+	}
+	if round+params.MaxEvidenceAge <= currentRound {
+		ctx.Logger().Debug("Evidence: evidence expired",
+			"round", round,
+			"current_round", currentRound,
+			"max_evidence_age", params.MaxEvidenceAge,
+		)
+		return roothash.ErrEvidenceExpired
+	}
+	// For the round that is currently in progress, the commitments must build on the
+	// current block of this runtime.
+	if round == currentRound+1 && !commit.Body.Header.IsParentOf(&rtState.CurrentBlock.Header) {
+		ctx.Logger().Debug("Evidence: evidence not for the current block",
+			"round", round,
+			"current_round", currentRound,
+		)
+		return roothash.ErrInvalidEvidence
+	}
+
+	processed, err := state.EvidenceProcessed(ctx, evidence.ID, round, nodeID)
+	if err != nil {
+		return err
+	}
+	if processed {
+		return roothash.ErrDuplicateEvidence
+	}
+
+	// Make sure the commitments were signed by a node registered for the runtime.
+	regState := registryState.NewMutableState(ctx.State())
+	node, err := regState.Node(ctx, nodeID)
+	if err != nil {
+		ctx.Logger().Debug("Evidence: failed to get node",
+			"err", err,
+			"node_id", nodeID,
+		)
+		return roothash.ErrInvalidEvidence
+	}
+	if node.GetRuntime(evidence.ID) == nil {
+		ctx.Logger().Debug("Evidence: node not registered for runtime",
+			"node_id", nodeID,
+			"runtime_id", evidence.ID,
+		)
+		return roothash.ErrInvalidEvidence
+	}
+
+	ctx.Logger().Warn("Evidence: executor commitment equivocation detected",
+		"runtime_id", evidence.ID,
+		"round", round,
+		"node_id", nodeID,
+	)
+
+	if !params.DebugBypassStake {
+		if err = stakingapp.SlashAndFreezeNode(ctx, node, staking.SlashRuntimeEquivocation); err != nil {
+			return fmt.Errorf("failed to slash node: %w", err)
+		}
+	}
+
+	if err = state.SetEvidenceProcessed(ctx, evidence.ID, round, nodeID, ctx.BlockHeight()); err != nil {
+		return fmt.Errorf("failed to mark evidence as processed: %w", err)
+	}
+
+	return nil
+}
+
+func (app *rootHashApplication) runtimeReset(
+	ctx *abciAPI.Context,
+	state *roothashState.MutableState,
+	rr *roothash.RuntimeReset,
+) error {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("RuntimeReset: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if !params.DebugAllowRuntimeReset {
+		return roothash.ErrRuntimeResetNotAllowed
+	}
+
+	rtState, err := state.RuntimeState(ctx, rr.ID)
+	if err != nil {
+		return roothash.ErrInvalidRuntime
+	}
+
+	// Ensure that the tx signer is the runtime owner.
+	if !rtState.Runtime.EntityID.Equal(ctx.TxSigner()) {
+		ctx.Logger().Debug("RuntimeReset: signer is not the runtime owner",
+			"runtime_id", rr.ID,
+			"signer", ctx.TxSigner(),
+		)
+		return roothash.ErrRuntimeResetNotAllowed
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, roothash.GasOpRuntimeReset, params.GasCosts); err != nil {
+		return err
+	}
+
+	// The new genesis block directly follows the current block so that runtime rounds remain
+	// contiguous for runtime history and storage.
+	archivedRound := rtState.CurrentBlock.Header.Round
+	round := archivedRound + 1
+
+	ctx.Logger().Warn("RuntimeReset: resetting runtime round state",
+		"runtime_id", rr.ID,
+		"archived_round", archivedRound,
+		"round", round,
+		"state_root", rr.StateRoot,
+	)
+
+	// Archive the current runtime state.
+	if err = state.ArchiveRuntimeState(ctx, rtState, ctx.BlockHeight()); err != nil {
+		return fmt.Errorf("failed to archive runtime state: %w", err)
+	}
+
+	// Discard any in-progress round.
+	if rtState.ExecutorPool != nil {
+		if rtState.ExecutorPool.NextTimeout != commitment.TimeoutNever {
+			if err = state.ClearRoundTimeout(ctx, rr.ID, rtState.ExecutorPool.NextTimeout); err != nil {
+				return fmt.Errorf("failed to clear round timeout: %w", err)
+			}
+		}
+		rtState.ExecutorPool.ResetCommitments()
+	}
+
+	// Reinitialize the runtime state from the new genesis block.
+	genesisBlock := block.NewGenesisBlock(rr.ID, uint64(ctx.Now().Unix()))
+	genesisBlock.Header.Round = round
+	genesisBlock.Header.StateRoot = rr.StateRoot
+	rtState.GenesisBlock = genesisBlock
+	rtState.CurrentBlock = genesisBlock
+	rtState.CurrentBlockHeight = ctx.BlockHeight()
+	if err = app.checkRoundLiveness(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to check committee liveness: %w", err)
+	}
+	if err = state.SetRuntimeState(ctx, rtState); err != nil {
+		return fmt.Errorf("failed to set runtime state: %w", err)
+	}
+
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			Attribute(KeyRuntimeReset, cbor.Marshal(&ValueRuntimeReset{
+				ID: rr.ID,
+				Event: roothash.RuntimeResetEvent{
+					ArchivedRound: archivedRound,
+					Round:         round,
+				},
+			})).
+			Attribute(KeyRuntimeID, ValueRuntimeID(rr.ID)),
+	)
+	// The new genesis block is the runtime's latest block.
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			Attribute(KeyFinalized, cbor.Marshal(&ValueFinalized{
+				ID:    rr.ID,
+				Round: round,
+			})).
+			Attribute(KeyRuntimeID, ValueRuntimeID(rr.ID)),
+	)
+
+	return nil
+}
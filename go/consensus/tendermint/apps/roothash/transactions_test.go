@@ -0,0 +1,397 @@
+package roothash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+func TestCheckExecutorCommitLimits(t *testing.T) {
+	require := require.New(t)
+
+	newCommit := func(size int) commitment.ExecutorCommitment {
+		return commitment.ExecutorCommitment{
+			Signed: signature.Signed{
+				Blob: make([]byte, size),
+			},
+		}
+	}
+	cc := &roothash.ExecutorCommit{
+		Commits: []commitment.ExecutorCommitment{
+			newCommit(32),
+			newCommit(1024),
+		},
+	}
+
+	// No limits.
+	var params roothash.ConsensusParameters
+	require.NoError(checkExecutorCommitLimits(&params, cc), "no limits")
+
+	// Commitment count limit.
+	params.MaxExecutorCommitments = 2
+	require.NoError(checkExecutorCommitLimits(&params, cc), "commitments within limit")
+	params.MaxExecutorCommitments = 1
+	require.Equal(roothash.ErrTooManyCommitments, checkExecutorCommitLimits(&params, cc), "too many commitments")
+	params.MaxExecutorCommitments = 0
+
+	// Commitment size limit.
+	params.MaxExecutorCommitmentSize = 2048
+	require.NoError(checkExecutorCommitLimits(&params, cc), "commitments within size limit")
+	params.MaxExecutorCommitmentSize = 512
+	require.Equal(roothash.ErrCommitmentTooLarge, checkExecutorCommitLimits(&params, cc), "commitment too large")
+}
+
+func TestRuntimeReset(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := rootHashApplication{appState}
+	state := roothashState.NewMutableState(ctx.State())
+
+	entitySigner := memorySigner.NewTestSigner("consensus/tendermint/apps/roothash: runtime reset entity")
+	otherSigner := memorySigner.NewTestSigner("consensus/tendermint/apps/roothash: runtime reset other")
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "UnmarshalHex")
+
+	blk := block.NewGenesisBlock(runtimeID, 0)
+	blk.Header.Round = 10
+	err := state.SetRuntimeState(ctx, &roothashState.RuntimeState{
+		Runtime:      &registry.Runtime{ID: runtimeID, EntityID: entitySigner.Public()},
+		GenesisBlock: block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlock: blk,
+	})
+	require.NoError(err, "SetRuntimeState")
+
+	var stateRoot hash.Hash
+	stateRoot.FromBytes([]byte("new state root"))
+	rr := &roothash.RuntimeReset{ID: runtimeID, StateRoot: stateRoot}
+
+	// Runtime reset should be disabled by default.
+	err = state.SetConsensusParameters(ctx, &roothash.ConsensusParameters{})
+	require.NoError(err, "SetConsensusParameters")
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.runtimeReset(ctx, state, rr)
+	require.Equal(roothash.ErrRuntimeResetNotAllowed, err, "runtime reset should not be allowed by default")
+
+	err = state.SetConsensusParameters(ctx, &roothash.ConsensusParameters{DebugAllowRuntimeReset: true})
+	require.NoError(err, "SetConsensusParameters")
+
+	// Only the runtime owner should be able to reset the runtime.
+	ctx.SetTxSigner(otherSigner.Public())
+	err = app.runtimeReset(ctx, state, rr)
+	require.Equal(roothash.ErrRuntimeResetNotAllowed, err, "runtime reset should only be allowed for the owner")
+
+	ctx.SetTxSigner(entitySigner.Public())
+	err = app.runtimeReset(ctx, state, rr)
+	require.NoError(err, "runtime reset should succeed")
+
+	rtState, err := state.RuntimeState(ctx, runtimeID)
+	require.NoError(err, "RuntimeState")
+	require.EqualValues(11, rtState.CurrentBlock.Header.Round, "new genesis block should follow the current block")
+	require.EqualValues(stateRoot, rtState.CurrentBlock.Header.StateRoot, "new genesis block should have the given state root")
+	require.EqualValues(rtState.CurrentBlock, rtState.GenesisBlock, "new genesis block should be the genesis block")
+
+	archived, err := state.ArchivedRuntimeStates(ctx, runtimeID)
+	require.NoError(err, "ArchivedRuntimeStates")
+	require.Len(archived, 1, "previous runtime state should be archived")
+	require.EqualValues(10, archived[0].State.CurrentBlock.Header.Round, "archived state should contain the previous current block")
+	require.EqualValues(ctx.BlockHeight(), archived[0].Height, "archived state should be archived at the current height")
+}
+
+func TestCommitteeLiveness(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{
+		BlockHeight:  100,
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := rootHashApplication{appState}
+	state := roothashState.NewMutableState(ctx.State())
+	regState := registryState.NewMutableState(ctx.State())
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "UnmarshalHex")
+
+	var signers []signature.Signer
+	committee := &scheduler.Committee{
+		Kind:      scheduler.KindComputeExecutor,
+		RuntimeID: runtimeID,
+	}
+	for i := 0; i < 4; i++ {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/roothash: committee liveness node %d", i))
+		signers = append(signers, signer)
+		committee.Members = append(committee.Members, &scheduler.CommitteeNode{
+			Role:      scheduler.RoleWorker,
+			PublicKey: signer.Public(),
+		})
+	}
+
+	registerNode := func(signer signature.Signer, expiration uint64, frozen bool) {
+		n := &node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         signer.Public(),
+			Expiration: expiration,
+		}
+		sigNode, err := node.MultiSignNode([]signature.Signer{signer}, registry.RegisterNodeSignatureContext, n)
+		require.NoError(err, "MultiSignNode")
+		err = regState.SetNode(ctx, nil, n, sigNode)
+		require.NoError(err, "SetNode")
+
+		var status registry.NodeStatus
+		if frozen {
+			status.FreezeEndTime = registry.FreezeForever
+		}
+		err = regState.SetNodeStatus(ctx, n.ID, &status)
+		require.NoError(err, "SetNodeStatus")
+	}
+
+	// Only one of the committee members is live: one is frozen, one is expired and one is not
+	// registered at all.
+	registerNode(signers[0], 10, false)
+	registerNode(signers[1], 10, true)
+	registerNode(signers[2], 1, false)
+
+	rt := &registry.Runtime{
+		ID: runtimeID,
+		Executor: registry.ExecutorParameters{
+			MinLiveCommitteePercent: 50,
+		},
+	}
+	rtState := &roothashState.RuntimeState{
+		Runtime:      rt,
+		GenesisBlock: block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlock: block.NewGenesisBlock(runtimeID, 0),
+		ExecutorPool: &commitment.Pool{
+			Runtime:   rt,
+			Committee: committee,
+		},
+	}
+
+	live, total, ok, err := app.committeeLiveness(ctx, rtState)
+	require.NoError(err, "committeeLiveness")
+	require.EqualValues(1, live, "only registered and non-frozen members should be live")
+	require.EqualValues(4, total, "committee size should be correct")
+	require.False(ok, "committee liveness should be insufficient")
+
+	// The round should be suspended.
+	err = app.checkRoundLiveness(ctx, rtState)
+	require.NoError(err, "checkRoundLiveness")
+	require.True(rtState.RoundSuspended, "round should be suspended")
+	err = state.SetRuntimeState(ctx, rtState)
+	require.NoError(err, "SetRuntimeState")
+
+	_, _, _, err = app.getRuntimeState(ctx, state, runtimeID)
+	require.Equal(roothash.ErrRoundSuspended, err, "commitments should be rejected for a suspended round")
+
+	// Once enough committee members are live, the round should be resumed.
+	registerNode(signers[3], 10, false)
+
+	rtState, _, _, err = app.getRuntimeState(ctx, state, runtimeID)
+	require.NoError(err, "getRuntimeState")
+	require.False(rtState.RoundSuspended, "round should be resumed")
+
+	// Disabling the check should never suspend the round.
+	rtState.Runtime.Executor.MinLiveCommitteePercent = 0
+	registerNode(signers[0], 10, true)
+	err = app.checkRoundLiveness(ctx, rtState)
+	require.NoError(err, "checkRoundLiveness")
+	require.False(rtState.RoundSuspended, "round should not be suspended with the check disabled")
+}
+
+func TestExecutorParticipation(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{
+		BlockHeight:  100,
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	app := rootHashApplication{appState}
+	state := roothashState.NewMutableState(ctx.State())
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "UnmarshalHex")
+
+	var nodeIDs []signature.PublicKey
+	committee := &scheduler.Committee{
+		Kind:      scheduler.KindComputeExecutor,
+		RuntimeID: runtimeID,
+	}
+	for i := 0; i < 3; i++ {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("consensus/tendermint/apps/roothash: executor participation node %d", i))
+		nodeIDs = append(nodeIDs, signer.Public())
+		committee.Members = append(committee.Members, &scheduler.CommitteeNode{
+			Role:      scheduler.RoleWorker,
+			PublicKey: signer.Public(),
+		})
+	}
+
+	rt := &registry.Runtime{ID: runtimeID}
+	rtState := &roothashState.RuntimeState{
+		Runtime:      rt,
+		GenesisBlock: block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlock: block.NewGenesisBlock(runtimeID, 0),
+		ExecutorPool: &commitment.Pool{
+			Runtime:   rt,
+			Committee: committee,
+		},
+	}
+	err := state.SetRuntimeState(ctx, rtState)
+	require.NoError(err, "SetRuntimeState")
+
+	// The first node submitted two commitments and proposed a round.
+	for i := 0; i < 2; i++ {
+		err = app.updateExecutorParticipation(ctx, runtimeID, nodeIDs[0], func(p *roothash.ExecutorParticipation) {
+			p.Commits++
+		})
+		require.NoError(err, "updateExecutorParticipation")
+	}
+	err = app.updateExecutorParticipation(ctx, runtimeID, nodeIDs[0], func(p *roothash.ExecutorParticipation) {
+		p.Proposals++
+	})
+	require.NoError(err, "updateExecutorParticipation")
+
+	// All nodes failed to commit in time, so all of them are stragglers.
+	err = app.processStragglers(ctx, state, rtState)
+	require.NoError(err, "processStragglers")
+
+	participation, err := state.ExecutorParticipation(ctx, runtimeID, cfg.CurrentEpoch)
+	require.NoError(err, "ExecutorParticipation")
+	require.Len(participation, 3, "all nodes should have participation statistics")
+	require.EqualValues(&roothash.ExecutorParticipation{Commits: 2, Proposals: 1, Failures: 1}, participation[nodeIDs[0]])
+	require.EqualValues(&roothash.ExecutorParticipation{Failures: 1}, participation[nodeIDs[1]])
+
+	// Statistics should be emitted at the end of the epoch.
+	err = app.onEpochEnd(ctx, cfg.CurrentEpoch+1)
+	require.NoError(err, "onEpochEnd")
+
+	var emitted *ValueExecutorParticipation
+	for _, ev := range ctx.GetEvents() {
+		for _, pair := range ev.Attributes {
+			if !bytes.Equal(pair.GetKey(), KeyExecutorParticipation) {
+				continue
+			}
+			var v ValueExecutorParticipation
+			require.NoError(cbor.Unmarshal(pair.GetValue(), &v), "Unmarshal")
+			emitted = &v
+		}
+	}
+	require.NotNil(emitted, "executor participation event should be emitted")
+	require.Equal(runtimeID, emitted.ID, "event should be for the runtime")
+	require.Equal(cfg.CurrentEpoch, emitted.Event.Epoch, "event should be for the previous epoch")
+	require.Len(emitted.Event.Nodes, 3, "event should include all nodes")
+	require.EqualValues(1, emitted.Event.Nodes[nodeIDs[0]].Proposals, "event should include statistics")
+
+	// Old statistics should be pruned.
+	err = state.PruneExecutorParticipation(ctx, cfg.CurrentEpoch+1)
+	require.NoError(err, "PruneExecutorParticipation")
+	participation, err = state.ExecutorParticipation(ctx, runtimeID, cfg.CurrentEpoch)
+	require.NoError(err, "ExecutorParticipation")
+	require.Empty(participation, "old statistics should be pruned")
+}
+
+func TestStakingEventSubscription(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	cfg := abciAPI.MockApplicationStateConfig{
+		BlockHeight:  100,
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	app := rootHashApplication{appState}
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "UnmarshalHex")
+
+	rtState := &roothashState.RuntimeState{
+		Runtime:            &registry.Runtime{ID: runtimeID},
+		GenesisBlock:       block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlock:       block.NewGenesisBlock(runtimeID, 0),
+		CurrentBlockHeight: 99,
+	}
+
+	finalize := func(height int64, messages ...*block.Message) {
+		cfg.BlockHeight = height
+		ctx := appState.NewContext(abciAPI.ContextEndBlock, now)
+		defer ctx.Close()
+
+		blk := block.NewEmptyBlock(rtState.CurrentBlock, uint64(now.Unix()), block.Normal)
+		blk.Header.Messages = messages
+		err := app.postProcessFinalizedBlock(ctx, rtState, blk)
+		require.NoError(err, "postProcessFinalizedBlock")
+		require.EqualValues(height, rtState.CurrentBlockHeight, "block should be finalized at the current height")
+	}
+	subscribe := func(subscribe bool) *block.Message {
+		return &block.Message{StakingSubscribe: &block.StakingSubscribeMessage{Subscribe: subscribe}}
+	}
+
+	// Runtimes are not subscribed by default.
+	finalize(100)
+	require.False(rtState.StakingEventsSubscribed, "runtime should not be subscribed")
+	require.EqualValues(0, rtState.StakingEventsHeight, "staking events height should not be set")
+
+	// Subscribing should start delivering events after the height of the parent block.
+	finalize(101, subscribe(true))
+	require.Equal(block.Normal, rtState.CurrentBlock.Header.HeaderType, "block should be finalized")
+	require.True(rtState.StakingEventsSubscribed, "runtime should be subscribed")
+	require.EqualValues(100, rtState.StakingEventsHeight, "staking events height should be the parent block height")
+
+	// While subscribed, each finalized block should advance the delivered height.
+	finalize(105)
+	require.True(rtState.StakingEventsSubscribed, "runtime should remain subscribed")
+	require.EqualValues(101, rtState.StakingEventsHeight, "staking events height should advance")
+
+	// Subscribing again should not change anything.
+	finalize(106, subscribe(true))
+	require.True(rtState.StakingEventsSubscribed, "runtime should remain subscribed")
+	require.EqualValues(105, rtState.StakingEventsHeight, "staking events height should advance")
+
+	// Unsubscribing should clear the delivered height.
+	finalize(107, subscribe(false))
+	require.False(rtState.StakingEventsSubscribed, "runtime should be unsubscribed")
+	require.EqualValues(0, rtState.StakingEventsHeight, "staking events height should be cleared")
+
+	// The last message in a block should take effect.
+	finalize(108, subscribe(false), subscribe(true))
+	require.True(rtState.StakingEventsSubscribed, "runtime should be subscribed")
+	require.EqualValues(107, rtState.StakingEventsHeight, "staking events height should be the parent block height")
+
+	// Blocks with invalid messages should fail the round and leave the subscription untouched.
+	finalize(109, subscribe(false), &block.Message{})
+	require.Equal(block.RoundFailed, rtState.CurrentBlock.Header.HeaderType, "round should fail")
+	require.True(rtState.StakingEventsSubscribed, "runtime should remain subscribed")
+	require.EqualValues(107, rtState.StakingEventsHeight, "staking events height should not change")
+}
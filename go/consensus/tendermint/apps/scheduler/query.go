@@ -2,11 +2,17 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -16,6 +22,7 @@ type Query interface {
 	AllCommittees(context.Context) ([]*scheduler.Committee, error)
 	KindsCommittees(context.Context, []scheduler.CommitteeKind) ([]*scheduler.Committee, error)
 	Genesis(context.Context) (*scheduler.Genesis, error)
+	ElectionSimulation(context.Context, common.Namespace, scheduler.CommitteeKind, epochtime.EpochTime, time.Time) (*scheduler.ElectionSimulation, error)
 }
 
 // QueryFactory is the scheduler query factory.
@@ -36,10 +43,18 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 		return nil, err
 	}
 
-	return &schedulerQuerier{state, regState}, nil
+	return &schedulerQuerier{
+		queryState: sf.state,
+		height:     height,
+		state:      state,
+		regState:   regState,
+	}, nil
 }
 
 type schedulerQuerier struct {
+	queryState abciAPI.ApplicationQueryState
+	height     int64
+
 	state    *schedulerState.ImmutableState
 	regState *registryState.ImmutableState
 }
@@ -98,6 +113,43 @@ func (sq *schedulerQuerier) KindsCommittees(ctx context.Context, kinds []schedul
 	return sq.state.KindsCommittees(ctx, kinds)
 }
 
+func (sq *schedulerQuerier) ElectionSimulation(
+	ctx context.Context,
+	runtimeID common.Namespace,
+	kind scheduler.CommitteeKind,
+	epoch epochtime.EpochTime,
+	now time.Time,
+) (*scheduler.ElectionSimulation, error) {
+	// Election simulation also needs access to the beacon and staking state.
+	beacState, err := beaconState.NewImmutableState(ctx, sq.queryState, sq.height)
+	if err != nil {
+		return nil, err
+	}
+	stakeState, err := stakingState.NewImmutableState(ctx, sq.queryState, sq.height)
+	if err != nil {
+		return nil, err
+	}
+
+	beacon, err := beacState.Beacon(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get beacon: %w", err)
+	}
+	params, err := sq.state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := simulateElection(ctx, sq.regState, stakeState, params, beacon, epoch, runtimeID, kind, now)
+	if err != nil {
+		return nil, err
+	}
+	sim.Height = sq.height
+	if sim.Committee, err = sq.state.Committee(ctx, kind, runtimeID); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
 func (app *schedulerApplication) QueryFactory() interface{} {
 	return &QueryFactory{app.state}
 }
@@ -3,9 +3,11 @@ package scheduler
 import (
 	"bytes"
 	"crypto"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sort"
+	"time"
 
 	"github.com/tendermint/tendermint/abci/types"
 
@@ -35,6 +37,10 @@ import (
 var (
 	_ api.Application = (*schedulerApplication)(nil)
 
+	// errTEEVerificationFailed is the error returned when a node's TEE attestation fails to
+	// verify.
+	errTEEVerificationFailed = errors.New("failed to verify node TEE attestation")
+
 	RNGContextExecutor   = []byte("EkS-ABCI-Compute")
 	RNGContextStorage    = []byte("EkS-ABCI-Storage")
 	RNGContextValidators = []byte("EkS-ABCI-Validators")
@@ -121,6 +127,10 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 			if status.IsFrozen() {
 				continue
 			}
+			// Nodes which are currently suspended by their entity cannot be scheduled.
+			if status.IsSuspended() {
+				continue
+			}
 			// Expired nodes cannot be scheduled (nodes can be expired and not yet removed).
 			if node.IsExpired(uint64(epoch)) {
 				continue
@@ -192,7 +202,7 @@ func (app *schedulerApplication) BeginBlock(ctx *api.Context, request types.Requ
 		if entitiesEligibleForReward != nil {
 			accountAddrs := stakingAddressMapToSortedSlice(entitiesEligibleForReward)
 			stakingSt := stakingState.NewMutableState(ctx.State())
-			if err = stakingSt.AddRewards(ctx, epoch, &params.RewardFactorEpochElectionAny, accountAddrs); err != nil {
+			if err = stakingSt.AddRewards(ctx, epoch, &params.RewardFactorEpochElectionAny, staking.RewardEpochElection, accountAddrs); err != nil {
 				return fmt.Errorf("tendermint/scheduler: failed to add rewards: %w", err)
 			}
 		}
@@ -276,9 +286,11 @@ func (app *schedulerApplication) EndBlock(ctx *api.Context, req types.RequestEnd
 	return resp, nil
 }
 
-func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+// checkSuitableExecutorWorker checks whether the given node is suitable for being elected into
+// an executor committee of the given runtime.
+func checkSuitableExecutorWorker(n *node.Node, rt *registry.Runtime, now time.Time) error {
 	if !n.HasRoles(node.RoleComputeWorker) {
-		return false
+		return fmt.Errorf("node does not have the %s role", node.RoleComputeWorker)
 	}
 	for _, nrt := range n.Runtimes {
 		if !nrt.ID.Equal(&rt.ID) {
@@ -287,42 +299,69 @@ func (app *schedulerApplication) isSuitableExecutorWorker(ctx *api.Context, n *n
 		switch rt.TEEHardware {
 		case node.TEEHardwareInvalid:
 			if nrt.Capabilities.TEE != nil {
-				return false
+				return fmt.Errorf("node has TEE capabilities for a non-TEE runtime")
 			}
-			return true
+			return nil
 		default:
 			if nrt.Capabilities.TEE == nil {
-				return false
+				return fmt.Errorf("node has no TEE capabilities")
 			}
 			if nrt.Capabilities.TEE.Hardware != rt.TEEHardware {
-				return false
-			}
-			if err := nrt.Capabilities.TEE.Verify(ctx.Now()); err != nil {
-				ctx.Logger().Warn("failed to verify node TEE attestaion",
-					"err", err,
-					"node", n,
-					"time_stamp", ctx.Now(),
-					"runtime", rt.ID,
+				return fmt.Errorf("node TEE hardware mismatch (expected: %s got: %s)",
+					rt.TEEHardware,
+					nrt.Capabilities.TEE.Hardware,
 				)
-				return false
 			}
-			return true
+			if err := nrt.Capabilities.TEE.Verify(now); err != nil {
+				return fmt.Errorf("%w: %s", errTEEVerificationFailed, err)
+			}
+			return nil
 		}
 	}
-	return false
+	return fmt.Errorf("node does not support the runtime")
 }
 
-func (app *schedulerApplication) isSuitableStorageWorker(ctx *api.Context, n *node.Node, rt *registry.Runtime) bool {
+// checkSuitableStorageWorker checks whether the given node is suitable for being elected into
+// a storage committee of the given runtime.
+func checkSuitableStorageWorker(n *node.Node, rt *registry.Runtime, now time.Time) error {
 	if !n.HasRoles(node.RoleStorageWorker) {
-		return false
+		return fmt.Errorf("node does not have the %s role", node.RoleStorageWorker)
 	}
 	for _, nrt := range n.Runtimes {
 		if !nrt.ID.Equal(&rt.ID) {
 			continue
 		}
-		return true
+		return nil
+	}
+	return fmt.Errorf("node does not support the runtime")
+}
+
+// committeeElectionParams are the parameters of a committee election.
+type committeeElectionParams struct {
+	rngCtx        []byte
+	checkSuitable func(*node.Node, *registry.Runtime, time.Time) error
+
+	workerSize, backupSize int
+}
+
+func getCommitteeElectionParams(kind scheduler.CommitteeKind, rt *registry.Runtime) (*committeeElectionParams, error) {
+	switch kind {
+	case scheduler.KindComputeExecutor:
+		return &committeeElectionParams{
+			rngCtx:        RNGContextExecutor,
+			checkSuitable: checkSuitableExecutorWorker,
+			workerSize:    int(rt.Executor.GroupSize),
+			backupSize:    int(rt.Executor.GroupBackupSize),
+		}, nil
+	case scheduler.KindStorage:
+		return &committeeElectionParams{
+			rngCtx:        RNGContextStorage,
+			checkSuitable: checkSuitableStorageWorker,
+			workerSize:    int(rt.Storage.GroupSize),
+		}, nil
+	default:
+		return nil, fmt.Errorf("tendermint/scheduler: invalid committee type: %v", kind)
 	}
-	return false
 }
 
 // GetPerm generates a permutation that we use to choose nodes from a list of eligible nodes to elect.
@@ -335,6 +374,28 @@ func GetPerm(beacon []byte, runtimeID common.Namespace, rngCtx []byte, nrNodes i
 	return rng.Perm(nrNodes), nil
 }
 
+// electMembers elects the committee members from the list of eligible nodes based on the given
+// election permutation.
+func electMembers(idxs []int, nodeList []*node.Node, workerSize, backupSize int) []*scheduler.CommitteeNode {
+	wantedNodes := workerSize + backupSize
+
+	var members []*scheduler.CommitteeNode
+	for i := 0; i < len(idxs); i++ {
+		role := scheduler.RoleWorker
+		if i >= workerSize {
+			role = scheduler.RoleBackupWorker
+		}
+		members = append(members, &scheduler.CommitteeNode{
+			Role:      role,
+			PublicKey: nodeList[idxs[i]].ID,
+		})
+		if len(members) >= wantedNodes {
+			break
+		}
+	}
+	return members
+}
+
 // Operates on consensus connection.
 // Return error if node should crash.
 // For non-fatal problems, save a problem condition to the state and return successfully.
@@ -355,30 +416,13 @@ func (app *schedulerApplication) electCommittee(
 
 	// Determine the context, committee size, and pre-filter the node-list
 	// based on eligibility and entity stake.
-	var (
-		err      error
-		nodeList []*node.Node
-
-		rngCtx       []byte
-		isSuitableFn func(*api.Context, *node.Node, *registry.Runtime) bool
-
-		workerSize, backupSize int
-	)
-
-	switch kind {
-	case scheduler.KindComputeExecutor:
-		rngCtx = RNGContextExecutor
-		isSuitableFn = app.isSuitableExecutorWorker
-		workerSize = int(rt.Executor.GroupSize)
-		backupSize = int(rt.Executor.GroupBackupSize)
-	case scheduler.KindStorage:
-		rngCtx = RNGContextStorage
-		isSuitableFn = app.isSuitableStorageWorker
-		workerSize = int(rt.Storage.GroupSize)
-	default:
-		return fmt.Errorf("tendermint/scheduler: invalid committee type: %v", kind)
+	ep, err := getCommitteeElectionParams(kind, rt)
+	if err != nil {
+		return err
 	}
+	workerSize, backupSize := ep.workerSize, ep.backupSize
 
+	var nodeList []*node.Node
 	for _, n := range nodes {
 		// Check if an entity has enough stake.
 		entAddr := staking.NewAddress(n.EntityID)
@@ -387,11 +431,21 @@ func (app *schedulerApplication) electCommittee(
 				continue
 			}
 		}
-		if isSuitableFn(ctx, n, rt) {
-			nodeList = append(nodeList, n)
-			if entitiesEligibleForReward != nil {
-				entitiesEligibleForReward[entAddr] = true
+		if err = ep.checkSuitable(n, rt, ctx.Now()); err != nil {
+			if errors.Is(err, errTEEVerificationFailed) {
+				ctx.Logger().Warn("failed to verify node TEE attestaion",
+					"err", err,
+					"node", n,
+					"time_stamp", ctx.Now(),
+					"runtime", rt.ID,
+				)
 			}
+			continue
+		}
+
+		nodeList = append(nodeList, n)
+		if entitiesEligibleForReward != nil {
+			entitiesEligibleForReward[entAddr] = true
 		}
 	}
 
@@ -423,25 +477,11 @@ func (app *schedulerApplication) electCommittee(
 	}
 
 	// Do the actual election.
-	idxs, err := GetPerm(beacon, rt.ID, rngCtx, nrNodes)
+	idxs, err := GetPerm(beacon, rt.ID, ep.rngCtx, nrNodes)
 	if err != nil {
 		return err
 	}
-
-	var members []*scheduler.CommitteeNode
-	for i := 0; i < len(idxs); i++ {
-		role := scheduler.RoleWorker
-		if i >= workerSize {
-			role = scheduler.RoleBackupWorker
-		}
-		members = append(members, &scheduler.CommitteeNode{
-			Role:      role,
-			PublicKey: nodeList[idxs[i]].ID,
-		})
-		if len(members) >= wantedNodes {
-			break
-		}
-	}
+	members := electMembers(idxs, nodeList, workerSize, backupSize)
 
 	if len(members) != wantedNodes {
 		ctx.Logger().Error("insufficient nodes with adequate stake to elect",
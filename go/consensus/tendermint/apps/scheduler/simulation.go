@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// simulateElection deterministically re-runs the committee election of the given kind for the
+// given runtime using the provided state, recording all of the election inputs.
+//
+// The simulation follows the same steps as the actual election performed by the scheduler
+// application on epoch transitions.
+func simulateElection(
+	ctx context.Context,
+	regState *registryState.ImmutableState,
+	stakeState *stakingState.ImmutableState,
+	params *scheduler.ConsensusParameters,
+	beacon []byte,
+	epoch epochtime.EpochTime,
+	runtimeID common.Namespace,
+	kind scheduler.CommitteeKind,
+	now time.Time,
+) (*scheduler.ElectionSimulation, error) {
+	rt, err := regState.Runtime(ctx, runtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &scheduler.ElectionSimulation{
+		Epoch:     epoch,
+		RuntimeID: runtimeID,
+		Kind:      kind,
+		Beacon:    beacon,
+	}
+
+	// Only generic compute runtimes need to elect all the committees.
+	if !rt.IsCompute() && kind != scheduler.KindComputeExecutor {
+		sim.Failure = fmt.Sprintf("%s committees are not elected for this runtime", kind)
+		return sim, nil
+	}
+
+	ep, err := getCommitteeElectionParams(kind, rt)
+	if err != nil {
+		return nil, err
+	}
+	sim.WorkerSize, sim.BackupSize = ep.workerSize, ep.backupSize
+
+	var thresholds map[staking.ThresholdKind]quantity.Quantity
+	if !params.DebugBypassStake {
+		if thresholds, err = stakeState.Thresholds(ctx); err != nil {
+			return nil, fmt.Errorf("tendermint/scheduler: failed to query thresholds: %w", err)
+		}
+	}
+
+	allNodes, err := regState.Nodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint/scheduler: couldn't get nodes: %w", err)
+	}
+
+	accounts := make(map[staking.Address]*staking.Account)
+	var nodeList []*node.Node
+	for _, n := range allNodes {
+		entAddr := staking.NewAddress(n.EntityID)
+		acct := accounts[entAddr]
+		if acct == nil {
+			if acct, err = stakeState.Account(ctx, entAddr); err != nil {
+				return nil, fmt.Errorf("tendermint/scheduler: couldn't get entity account: %w", err)
+			}
+			accounts[entAddr] = acct
+		}
+
+		candidate := &scheduler.ElectionCandidate{
+			ID:          n.ID,
+			EntityID:    n.EntityID,
+			EntityStake: acct.Escrow.Active.Balance,
+		}
+		sim.Candidates = append(sim.Candidates, candidate)
+
+		var status *registry.NodeStatus
+		if status, err = regState.NodeStatus(ctx, n.ID); err != nil {
+			return nil, fmt.Errorf("tendermint/scheduler: couldn't get node status: %w", err)
+		}
+
+		switch {
+		case status.IsFrozen():
+			candidate.Reason = "node is frozen"
+		case status.IsSuspended():
+			candidate.Reason = "node is suspended"
+		case n.IsExpired(uint64(epoch)):
+			candidate.Reason = "node is expired"
+		default:
+			if thresholds != nil {
+				if err = acct.Escrow.CheckStakeClaims(thresholds); err != nil {
+					candidate.Reason = fmt.Sprintf("entity stake claims not satisfied: %s", err)
+					break
+				}
+			}
+			if err = ep.checkSuitable(n, rt, now); err != nil {
+				candidate.Reason = err.Error()
+				break
+			}
+
+			candidate.Eligible = true
+			nodeList = append(nodeList, n)
+			sim.Eligible = append(sim.Eligible, n.ID)
+		}
+	}
+
+	// Ensure that it is theoretically possible to elect a valid committee.
+	if ep.workerSize == 0 {
+		sim.Failure = "empty committee not allowed"
+		return sim, nil
+	}
+
+	nrNodes, wantedNodes := len(nodeList), ep.workerSize+ep.backupSize
+	if wantedNodes > nrNodes {
+		sim.Failure = fmt.Sprintf("committee size exceeds available nodes (wanted: %d available: %d)", wantedNodes, nrNodes)
+		return sim, nil
+	}
+
+	// Do the actual election.
+	if sim.Permutation, err = GetPerm(beacon, rt.ID, ep.rngCtx, nrNodes); err != nil {
+		return nil, err
+	}
+	members := electMembers(sim.Permutation, nodeList, ep.workerSize, ep.backupSize)
+	for i, m := range members {
+		sim.Steps = append(sim.Steps, &scheduler.ElectionStep{
+			Index: sim.Permutation[i],
+			Node:  *m,
+		})
+	}
+
+	if len(members) != wantedNodes {
+		sim.Failure = fmt.Sprintf("insufficient nodes with adequate stake to elect (available: %d)", len(members))
+	}
+
+	return sim, nil
+}
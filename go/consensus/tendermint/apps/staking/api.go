@@ -37,10 +37,26 @@ var (
 	// an api.BurnEvent).
 	KeyBurn = []byte("burn")
 
+	// KeyMint is an ABCI event attribute key for Mint calls (value is
+	// an api.MintEvent).
+	KeyMint = []byte("mint")
+
+	// KeyAlias is an ABCI event attribute key for RegisterAlias calls (value
+	// is an api.AliasEvent).
+	KeyAlias = []byte("alias")
+
 	// KeyAddEscrow is an ABCI event attribute key for AddEscrow calls
 	// (value is an api.AddEscrowEvent).
 	KeyAddEscrow = stakingState.KeyAddEscrow
 
 	// KeyAllowanceChange is an ABCI event attribute key for AllowanceChangeEvents.
-	KeyAllowanceChange = []byte("allowance_change")
+	KeyAllowanceChange = stakingState.KeyAllowanceChange
+
+	// KeyReward is an ABCI event attribute key for reward disbursements
+	// (value is an api.RewardEvent).
+	KeyReward = stakingState.KeyReward
+
+	// KeySlash is an ABCI event attribute key for slashing (value is an
+	// api.SlashEvent).
+	KeySlash = stakingState.KeySlash
 )
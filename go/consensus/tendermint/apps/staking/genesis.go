@@ -242,7 +242,7 @@ func (app *stakingApplication) initDebondingDelegations(ctx *abciAPI.Context, st
 					)
 				}
 
-				if err := state.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, uint64(idx), delegation); err != nil {
+				if err := state.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, uint64(idx), 0, delegation); err != nil {
 					return fmt.Errorf("tendermint/staking: failed to set debonding delegation to %s from %s index %d: %w",
 						escrowAddr, delegatorAddr, idx, err,
 					)
@@ -268,6 +268,25 @@ func (app *stakingApplication) initDebondingDelegations(ctx *abciAPI.Context, st
 	return nil
 }
 
+func (app *stakingApplication) initAliases(ctx *abciAPI.Context, state *stakingState.MutableState, st *staking.Genesis) error {
+	for alias, info := range st.Aliases {
+		if err := staking.ValidateAlias(alias); err != nil {
+			return fmt.Errorf("tendermint/staking: genesis alias '%s' is invalid: %w", alias, err)
+		}
+		if info == nil {
+			return fmt.Errorf("tendermint/staking: genesis alias '%s' is nil", alias)
+		}
+		if !info.Address.IsValid() {
+			return fmt.Errorf("tendermint/staking: genesis alias '%s': address is invalid", alias)
+		}
+
+		if err := state.SetAlias(ctx, alias, info); err != nil {
+			return fmt.Errorf("tendermint/staking: failed to set genesis alias '%s': %w", alias, err)
+		}
+	}
+	return nil
+}
+
 // InitChain initializes the chain from genesis.
 func (app *stakingApplication) InitChain(ctx *abciAPI.Context, request types.RequestInitChain, doc *genesis.Document) error {
 	st := &doc.Staking
@@ -305,6 +324,10 @@ func (app *stakingApplication) InitChain(ctx *abciAPI.Context, request types.Req
 		return err
 	}
 
+	if err := app.initAliases(ctx, state, st); err != nil {
+		return err
+	}
+
 	ctx.Logger().Debug("InitChain: allocations complete",
 		"common_pool", st.CommonPool,
 		"total_supply", totalSupply,
@@ -356,6 +379,11 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 		return nil, err
 	}
 
+	aliases, err := sq.state.Aliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params, err := sq.state.ConsensusParameters(ctx)
 	if err != nil {
 		return nil, err
@@ -369,6 +397,7 @@ func (sq *stakingQuerier) Genesis(ctx context.Context) (*staking.Genesis, error)
 		Ledger:               ledger,
 		Delegations:          delegations,
 		DebondingDelegations: debondingDelegations,
+		Aliases:              aliases,
 	}
 	return &gen, nil
 }
@@ -64,6 +64,7 @@ func (app *stakingApplication) rewardBlockProposing(
 		&params.RewardFactorBlockProposed,
 		numSigningEntities,
 		numEligibleValidators,
+		staking.RewardBlockProposed,
 		proposerAddr,
 	); err != nil {
 		return fmt.Errorf("adding rewards: %w", err)
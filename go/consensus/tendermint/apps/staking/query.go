@@ -20,7 +20,9 @@ type Query interface {
 	Addresses(context.Context) ([]staking.Address, error)
 	Account(context.Context, staking.Address) (*staking.Account, error)
 	Delegations(context.Context, staking.Address) (map[staking.Address]*staking.Delegation, error)
+	DelegationsTo(context.Context, staking.Address) (map[staking.Address]*staking.Delegation, error)
 	DebondingDelegations(context.Context, staking.Address) (map[staking.Address][]*staking.DebondingDelegation, error)
+	Alias(context.Context, string) (*staking.AliasInfo, error)
 	Genesis(context.Context) (*staking.Genesis, error)
 	ConsensusParameters(context.Context) (*staking.ConsensusParameters, error)
 }
@@ -107,10 +109,18 @@ func (sq *stakingQuerier) Delegations(ctx context.Context, addr staking.Address)
 	return sq.state.DelegationsFor(ctx, addr)
 }
 
+func (sq *stakingQuerier) DelegationsTo(ctx context.Context, addr staking.Address) (map[staking.Address]*staking.Delegation, error) {
+	return sq.state.DelegationsTo(ctx, addr)
+}
+
 func (sq *stakingQuerier) DebondingDelegations(ctx context.Context, addr staking.Address) (map[staking.Address][]*staking.DebondingDelegation, error) {
 	return sq.state.DebondingDelegationsFor(ctx, addr)
 }
 
+func (sq *stakingQuerier) Alias(ctx context.Context, alias string) (*staking.AliasInfo, error) {
+	return sq.state.Alias(ctx, alias)
+}
+
 func (sq *stakingQuerier) ConsensusParameters(ctx context.Context) (*staking.ConsensusParameters, error) {
 	return sq.state.ConsensusParameters(ctx)
 }
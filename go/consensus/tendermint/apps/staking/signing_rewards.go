@@ -70,7 +70,7 @@ func (app *stakingApplication) rewardEpochSigning(ctx *abciAPI.Context, time epo
 		eligibleEntitiesAddrs = append(eligibleEntitiesAddrs, staking.NewAddress(entity))
 	}
 
-	if err := stakeState.AddRewards(ctx, time, &params.RewardFactorEpochSigned, eligibleEntitiesAddrs); err != nil {
+	if err := stakeState.AddRewards(ctx, time, &params.RewardFactorEpochSigned, staking.RewardEpochSigned, eligibleEntitiesAddrs); err != nil {
 		return fmt.Errorf("adding rewards: %w", err)
 	}
 
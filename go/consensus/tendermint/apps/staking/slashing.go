@@ -8,10 +8,10 @@ import (
 
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
-	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
@@ -24,7 +24,6 @@ func onEvidenceDoubleSign(
 	power int64,
 ) error {
 	regState := registryState.NewMutableState(ctx.State())
-	stakeState := stakingState.NewMutableState(ctx.State())
 
 	// Resolve consensus node. Note that in order for this to work even in light
 	// of node expirations, the node descriptor must be available for at least
@@ -38,45 +37,57 @@ func onEvidenceDoubleSign(
 		return nil
 	}
 
-	nodeStatus, err := regState.NodeStatus(ctx, node.ID)
+	return SlashAndFreezeNode(ctx, node, staking.SlashDoubleSigning)
+}
+
+// SlashAndFreezeNode slashes the entity operating the given node and freezes the node as
+// configured in the slashing parameters for the given reason.
+//
+// Frozen nodes are not slashed again.
+func SlashAndFreezeNode(ctx *abciAPI.Context, n *node.Node, reason staking.SlashReason) error {
+	regState := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	nodeStatus, err := regState.NodeStatus(ctx, n.ID)
 	if err != nil {
-		ctx.Logger().Warn("failed to get validator node status",
+		ctx.Logger().Warn("failed to get node status",
 			"err", err,
-			"node_id", node.ID,
+			"node_id", n.ID,
 		)
 		return nil
 	}
 
-	// Do not slash a frozen validator.
+	// Do not slash a frozen node.
 	if nodeStatus.IsFrozen() {
-		ctx.Logger().Debug("not slashing frozen validator",
-			"node_id", node.ID,
-			"entity_id", node.EntityID,
+		ctx.Logger().Debug("not slashing frozen node",
+			"node_id", n.ID,
+			"entity_id", n.EntityID,
 			"freeze_end_time", nodeStatus.FreezeEndTime,
+			"reason", reason,
 		)
 		return nil
 	}
 
-	// Retrieve the slash procedure for double signing.
+	// Retrieve the slash procedure for the given reason.
 	st, err := stakeState.Slashing(ctx)
 	if err != nil {
-		ctx.Logger().Error("failed to get slashing table entry for double signing",
+		ctx.Logger().Error("failed to get slashing table entry",
 			"err", err,
+			"reason", reason,
 		)
 		return err
 	}
 
-	penalty := st[staking.SlashDoubleSigning]
+	penalty := st[reason]
 
-	// Freeze validator to prevent it being slashed again. This also prevents the
-	// validator from being scheduled in the next epoch.
-	if penalty.FreezeInterval > 0 {
-		var epoch epochtime.EpochTime
-		epoch, err = ctx.AppState().GetEpoch(context.Background(), ctx.BlockHeight()+1)
-		if err != nil {
-			return err
-		}
+	epoch, err := ctx.AppState().GetEpoch(context.Background(), ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
 
+	// Freeze node to prevent it being slashed again. This also prevents the
+	// node from being scheduled in the next epoch.
+	if penalty.FreezeInterval > 0 {
 		// Check for overflow.
 		if math.MaxUint64-penalty.FreezeInterval < epoch {
 			nodeStatus.FreezeEndTime = registry.FreezeForever
@@ -85,30 +96,32 @@ func onEvidenceDoubleSign(
 		}
 	}
 
-	// Slash validator.
-	entityAddr := staking.NewAddress(node.EntityID)
-	_, err = stakeState.SlashEscrow(ctx, entityAddr, &penalty.Amount)
+	// Slash node entity.
+	entityAddr := staking.NewAddress(n.EntityID)
+	_, err = stakeState.SlashEscrow(ctx, epoch, reason, entityAddr, &penalty.Amount)
 	if err != nil {
-		ctx.Logger().Error("failed to slash validator entity",
+		ctx.Logger().Error("failed to slash node entity",
 			"err", err,
-			"node_id", node.ID,
-			"entity_id", node.EntityID,
+			"node_id", n.ID,
+			"entity_id", n.EntityID,
+			"reason", reason,
 		)
 		return err
 	}
 
-	if err = regState.SetNodeStatus(ctx, node.ID, nodeStatus); err != nil {
-		ctx.Logger().Error("failed to set validator node status",
+	if err = regState.SetNodeStatus(ctx, n.ID, nodeStatus); err != nil {
+		ctx.Logger().Error("failed to set node status",
 			"err", err,
-			"node_id", node.ID,
-			"entity_id", node.EntityID,
+			"node_id", n.ID,
+			"entity_id", n.EntityID,
 		)
 		return err
 	}
 
-	ctx.Logger().Warn("slashed validator for double signing",
-		"node_id", node.ID,
-		"entity_id", node.EntityID,
+	ctx.Logger().Warn("slashed node",
+		"node_id", n.ID,
+		"entity_id", n.EntityID,
+		"reason", reason,
 	)
 
 	return nil
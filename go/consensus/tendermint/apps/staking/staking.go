@@ -16,7 +16,10 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-var _ api.Application = (*stakingApplication)(nil)
+var (
+	_ api.Application            = (*stakingApplication)(nil)
+	_ api.TransactionPrioritizer = (*stakingApplication)(nil)
+)
 
 type stakingApplication struct {
 	state api.ApplicationState
@@ -38,6 +41,16 @@ func (app *stakingApplication) Blessed() bool {
 	return false
 }
 
+// Implements api.TransactionPrioritizer.
+func (app *stakingApplication) PriorityClass(method transaction.MethodName) api.PriorityClass {
+	switch method {
+	case staking.MethodTransfer, staking.MethodBurn, staking.MethodAllow, staking.MethodWithdraw:
+		return api.PriorityClassLow
+	default:
+		return api.PriorityClassNormal
+	}
+}
+
 func (app *stakingApplication) Dependencies() []string {
 	return nil
 }
@@ -151,6 +164,20 @@ func (app *stakingApplication) ExecuteTx(ctx *api.Context, tx *transaction.Trans
 		}
 
 		return app.withdraw(ctx, state, &withdraw)
+	case staking.MethodMint:
+		var mint staking.Mint
+		if err := cbor.Unmarshal(tx.Body, &mint); err != nil {
+			return err
+		}
+
+		return app.mint(ctx, state, &mint)
+	case staking.MethodRegisterAlias:
+		var reg staking.RegisterAlias
+		if err := cbor.Unmarshal(tx.Body, &reg); err != nil {
+			return err
+		}
+
+		return app.registerAlias(ctx, state, &reg)
 	default:
 		return staking.ErrInvalidArgument
 	}
@@ -223,10 +250,10 @@ func (app *stakingApplication) onEpochChange(ctx *api.Context, epoch epochtime.E
 		}
 
 		// Update state.
-		if err = state.RemoveFromDebondingQueue(ctx, e.Epoch, e.DelegatorAddr, e.EscrowAddr, e.Seq); err != nil {
+		if err = state.RemoveFromDebondingQueue(ctx, e.Epoch, e.DelegatorAddr, e.EscrowAddr, e.Seq, e.Tranche); err != nil {
 			return fmt.Errorf("failed to remove from debonding queue: %w", err)
 		}
-		if err = state.SetDebondingDelegation(ctx, e.DelegatorAddr, e.EscrowAddr, e.Seq, nil); err != nil {
+		if err = state.SetDebondingDelegation(ctx, e.DelegatorAddr, e.EscrowAddr, e.Seq, e.Tranche, nil); err != nil {
 			return fmt.Errorf("failed to set debonding delegation: %w", err)
 		}
 		if err = state.SetAccount(ctx, e.DelegatorAddr, delegator); err != nil {
@@ -245,13 +272,33 @@ func (app *stakingApplication) onEpochChange(ctx *api.Context, epoch epochtime.E
 		)
 
 		evt := staking.ReclaimEscrowEvent{
-			Owner:  e.DelegatorAddr,
-			Escrow: e.EscrowAddr,
-			Amount: *stakeAmount,
+			V:                staking.EscrowEventVersionShares,
+			Owner:            e.DelegatorAddr,
+			Escrow:           e.EscrowAddr,
+			Amount:           *stakeAmount,
+			Shares:           *shareAmount,
+			DebondingBalance: escrow.Escrow.Debonding.Balance,
+			DebondingShares:  escrow.Escrow.Debonding.TotalShares,
 		}
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyReclaimEscrow, cbor.Marshal(evt)))
 	}
 
+	// Remove expired address aliases.
+	expiredAliases, err := state.ExpiredAliases(ctx, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to query expired aliases: %w", err)
+	}
+	for _, alias := range expiredAliases {
+		if err = state.RemoveAlias(ctx, alias); err != nil {
+			return fmt.Errorf("failed to remove expired alias: %w", err)
+		}
+
+		ctx.Logger().Debug("removed expired alias",
+			"alias", alias,
+			"epoch", epoch,
+		)
+	}
+
 	// Add signing rewards.
 	if err := app.rewardEpochSigning(ctx, epoch); err != nil {
 		ctx.Logger().Error("failed to add signing rewards",
@@ -70,13 +70,22 @@ func AuthenticateAndPayFees(
 		fee = &transaction.Fee{}
 	}
 
+	// Determine the account that pays the fees.
+	payerAddr, payer := addr, account
+	var allowance *quantity.Quantity
+	if fee.Sponsor != nil {
+		if payerAddr, payer, allowance, err = sponsorFee(ctx, state, addr, fee); err != nil {
+			return err
+		}
+	}
+
 	if ctx.IsCheckOnly() {
 		// Configure gas accountant on the context so that we can report gas wanted.
 		ctx.SetGasAccountant(abciAPI.NewGasAccountant(fee.Gas))
 
 		// Check that there is enough balance to pay fees. For the non-CheckTx case
 		// this happens during Move below.
-		if account.General.Balance.Cmp(&fee.Amount) < 0 {
+		if payer.General.Balance.Cmp(&fee.Amount) < 0 {
 			return transaction.ErrInsufficientFeeBalance
 		}
 
@@ -95,24 +104,40 @@ func AuthenticateAndPayFees(
 
 	// Transfer fee to per-block fee accumulator.
 	feeAcc := ctx.BlockContext().Get(feeAccumulatorKey{}).(*feeAccumulator)
-	if err := quantity.Move(&feeAcc.balance, &account.General.Balance, &fee.Amount); err != nil {
+	if err = quantity.Move(&feeAcc.balance, &payer.General.Balance, &fee.Amount); err != nil {
 		return fmt.Errorf("staking: failed to pay fees: %w", err)
 	}
 
 	account.General.Nonce++
-	if err := state.SetAccount(ctx, addr, account); err != nil {
+	if err = state.SetAccount(ctx, addr, account); err != nil {
 		return fmt.Errorf("failed to set account: %w", err)
 	}
+	if fee.Sponsor != nil {
+		if err = state.SetAccount(ctx, payerAddr, payer); err != nil {
+			return fmt.Errorf("failed to set sponsor account: %w", err)
+		}
+	}
 
 	// Emit transfer event if fee is non-zero.
 	if !fee.Amount.IsZero() {
 		ev := cbor.Marshal(&staking.TransferEvent{
-			From:   addr,
+			From:   payerAddr,
 			To:     staking.FeeAccumulatorAddress,
 			Amount: fee.Amount,
 		})
 		ctx.EmitEvent(abciAPI.NewEventBuilder(AppName).Attribute(KeyTransfer, ev))
 	}
+	// Emit allowance change event if the fee was paid by a sponsor.
+	if fee.Sponsor != nil {
+		ev := cbor.Marshal(&staking.AllowanceChangeEvent{
+			Owner:        payerAddr,
+			Beneficiary:  addr,
+			Allowance:    *allowance,
+			Negative:     true,
+			AmountChange: fee.Amount,
+		})
+		ctx.EmitEvent(abciAPI.NewEventBuilder(AppName).Attribute(KeyAllowanceChange, ev))
+	}
 
 	// Configure gas accountant on the context.
 	ctx.SetGasAccountant(abciAPI.NewCompositeGasAccountant(
@@ -123,6 +148,58 @@ func AuthenticateAndPayFees(
 	return nil
 }
 
+// sponsorFee validates that the fee sponsor is allowed to pay the given fee on behalf of the
+// signer and deducts the fee amount from the allowance granted to the signer.
+//
+// Returns the sponsor's address, its (updated) account and the remaining allowance.
+func sponsorFee(
+	ctx *abciAPI.Context,
+	state *MutableState,
+	signerAddr staking.Address,
+	fee *transaction.Fee,
+) (staking.Address, *staking.Account, *quantity.Quantity, error) {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return staking.Address{}, nil, nil, fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+
+	// Fee sponsorship is disabled in case either the maximum sponsored fee is zero or if
+	// allowances are disabled.
+	if params.MaxSponsoredFee.IsZero() || params.DisableTransfers || params.MaxAllowances == 0 {
+		return staking.Address{}, nil, nil, transaction.ErrInvalidFeeSponsor
+	}
+	// Sponsored transactions must pay a non-zero fee which does not exceed the limit.
+	if fee.Amount.IsZero() || fee.Amount.Cmp(&params.MaxSponsoredFee) > 0 {
+		return staking.Address{}, nil, nil, transaction.ErrInvalidFeeSponsor
+	}
+
+	sponsorAddr := staking.NewAddress(*fee.Sponsor)
+	if sponsorAddr.IsReserved() || sponsorAddr.Equal(signerAddr) {
+		return staking.Address{}, nil, nil, transaction.ErrInvalidFeeSponsor
+	}
+
+	sponsor, err := state.Account(ctx, sponsorAddr)
+	if err != nil {
+		return staking.Address{}, nil, nil, fmt.Errorf("failed to fetch sponsor account state: %w", err)
+	}
+	allowance, ok := sponsor.General.Allowances[signerAddr]
+	if !ok {
+		return staking.Address{}, nil, nil, transaction.ErrInvalidFeeSponsor
+	}
+	if err = allowance.Sub(&fee.Amount); err != nil {
+		return staking.Address{}, nil, nil, transaction.ErrInvalidFeeSponsor
+	}
+	if allowance.IsZero() {
+		// In case the new allowance is equal to zero, remove it.
+		delete(sponsor.General.Allowances, signerAddr)
+	} else {
+		// Otherwise update the allowance.
+		sponsor.General.Allowances[signerAddr] = allowance
+	}
+
+	return sponsorAddr, sponsor, &allowance, nil
+}
+
 // BlockFees returns the accumulated fee balance for the current block.
 func BlockFees(ctx *abciAPI.Context) quantity.Quantity {
 	// Fetch accumulated fees in the current block.
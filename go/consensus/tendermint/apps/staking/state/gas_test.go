@@ -0,0 +1,111 @@
+package state
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestAuthenticateAndPayFeesSponsored(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		MinGasPrice: quantity.NewQuantity(),
+	})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+	ctx.BlockContext().Set(abciAPI.GasAccountantKey{}, abciAPI.NewNopGasAccountant())
+
+	s := NewMutableState(ctx.State())
+	params := &staking.ConsensusParameters{
+		MaxAllowances:   1,
+		MaxSponsoredFee: mustInitQuantity(t, 100),
+	}
+	err := s.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	fac := memorySigner.NewFactory()
+	signer, err := fac.Generate(signature.SignerEntity, rand.Reader)
+	require.NoError(err, "generating signer")
+	signerAddr := staking.NewAddress(signer.Public())
+	sponsorSigner, err := fac.Generate(signature.SignerEntity, rand.Reader)
+	require.NoError(err, "generating sponsor signer")
+	sponsorPk := sponsorSigner.Public()
+	sponsorAddr := staking.NewAddress(sponsorPk)
+
+	err = s.SetAccount(ctx, sponsorAddr, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: mustInitQuantity(t, 1000),
+			Allowances: map[staking.Address]quantity.Quantity{
+				signerAddr: mustInitQuantity(t, 150),
+			},
+		},
+	})
+	require.NoError(err, "SetAccount")
+
+	pay := func(nonce uint64, amount int64, sponsor *signature.PublicKey) error {
+		return AuthenticateAndPayFees(ctx, signer.Public(), nonce, &transaction.Fee{
+			Amount:  mustInitQuantity(t, amount),
+			Gas:     1000,
+			Sponsor: sponsor,
+		})
+	}
+
+	// Fees exceeding the maximum sponsored fee should be rejected.
+	err = pay(0, 101, &sponsorPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "fee above the limit should be rejected")
+	// Zero fees should be rejected.
+	err = pay(0, 0, &sponsorPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "zero fee should be rejected")
+	// Self-sponsorship should be rejected.
+	signerPk := signer.Public()
+	err = pay(0, 10, &signerPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "self-sponsorship should be rejected")
+
+	// Valid sponsored fee payment.
+	err = pay(0, 100, &sponsorPk)
+	require.NoError(err, "AuthenticateAndPayFees")
+
+	sponsor, err := s.Account(ctx, sponsorAddr)
+	require.NoError(err, "Account")
+	require.Equal(mustInitQuantity(t, 900), sponsor.General.Balance, "sponsor should pay the fee")
+	require.Equal(mustInitQuantity(t, 50), sponsor.General.Allowances[signerAddr], "allowance should be reduced")
+	require.Zero(sponsor.General.Nonce, "sponsor nonce should not change")
+	acct, err := s.Account(ctx, signerAddr)
+	require.NoError(err, "Account")
+	require.EqualValues(1, acct.General.Nonce, "signer nonce should be incremented")
+	require.Equal(mustInitQuantity(t, 100), BlockFees(ctx), "fees should be accumulated")
+
+	// Fees exceeding the remaining allowance should be rejected.
+	err = pay(1, 51, &sponsorPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "fee above the allowance should be rejected")
+
+	// Using up the allowance should remove it.
+	err = pay(1, 50, &sponsorPk)
+	require.NoError(err, "AuthenticateAndPayFees")
+	sponsor, err = s.Account(ctx, sponsorAddr)
+	require.NoError(err, "Account")
+	require.Empty(sponsor.General.Allowances, "exhausted allowance should be removed")
+
+	// Sponsorship should fail without an allowance.
+	err = pay(2, 10, &sponsorPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "fee without an allowance should be rejected")
+
+	// Sponsorship should fail when disabled.
+	params.MaxSponsoredFee = *quantity.NewQuantity()
+	err = s.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+	err = pay(2, 10, &sponsorPk)
+	require.True(errors.Is(err, transaction.ErrInvalidFeeSponsor), "sponsorship should be disabled")
+}
@@ -31,6 +31,14 @@ var (
 	// KeyTransfer is an ABCI event attribute key for Transfers (value is
 	// an app.TransferEvent).
 	KeyTransfer = []byte("transfer")
+	// KeyAllowanceChange is an ABCI event attribute key for AllowanceChangeEvents.
+	KeyAllowanceChange = []byte("allowance_change")
+	// KeyReward is an ABCI event attribute key for reward disbursements
+	// (value is an api.RewardEvent).
+	KeyReward = []byte("reward")
+	// KeySlash is an ABCI event attribute key for slashing (value is an
+	// api.SlashEvent).
+	KeySlash = []byte("slash")
 
 	// accountKeyFmt is the key format used for accounts (account addresses).
 	//
@@ -52,11 +60,17 @@ var (
 	// debondingDelegationKeyFmt is the key format used for debonding delegations
 	// (delegator address, escrow address, seq no).
 	//
+	// Only the first tranche of a debonding delegation is stored under this key
+	// format, see debondingDelegationTrancheKeyFmt.
+	//
 	// Value is CBOR-serialized debonding delegation.
 	debondingDelegationKeyFmt = keyformat.New(0x54, &staking.Address{}, &staking.Address{}, uint64(0))
 	// debondingQueueKeyFmt is the debonding queue key format (epoch,
 	// delegator address, escrow address, seq no).
 	//
+	// Only the first tranche of a debonding delegation is stored under this key
+	// format, see debondingQueueTrancheKeyFmt.
+	//
 	// Value is empty.
 	debondingQueueKeyFmt = keyformat.New(0x55, uint64(0), &staking.Address{}, &staking.Address{}, uint64(0))
 	// parametersKeyFmt is the key format used for consensus parameters.
@@ -71,11 +85,51 @@ var (
 	//
 	// Value is CBOR-serialized EpochSigning.
 	epochSigningKeyFmt = keyformat.New(0x58)
+	// aliasKeyFmt is the key format used for address aliases (alias).
+	//
+	// Value is CBOR-serialized staking.AliasInfo.
+	aliasKeyFmt = keyformat.New(0x59, []byte{})
+	// aliasExpirationQueueKeyFmt is the address alias expiration queue key
+	// format (expiration epoch, alias).
+	//
+	// Value is empty.
+	aliasExpirationQueueKeyFmt = keyformat.New(0x5a, uint64(0), []byte{})
+	// debondingDelegationTrancheKeyFmt is the key format used for debonding delegations
+	// of additional tranches (delegator address, escrow address, seq no, tranche).
+	//
+	// Value is CBOR-serialized debonding delegation.
+	debondingDelegationTrancheKeyFmt = keyformat.New(0x5b, &staking.Address{}, &staking.Address{}, uint64(0), uint32(0))
+	// debondingQueueTrancheKeyFmt is the debonding queue key format for additional
+	// tranches (epoch, delegator address, escrow address, seq no, tranche).
+	//
+	// Value is empty.
+	debondingQueueTrancheKeyFmt = keyformat.New(0x5c, uint64(0), &staking.Address{}, &staking.Address{}, uint64(0), uint32(0))
 
 	logger = logging.GetLogger("tendermint/staking")
 )
 
 // ImmutableState is the immutable staking state wrapper.
+// debondingDelegationKey returns the key of the given debonding delegation tranche.
+func debondingDelegationKey(delegatorAddr, escrowAddr staking.Address, seq uint64, tranche uint32) []byte {
+	if tranche == 0 {
+		return debondingDelegationKeyFmt.Encode(&delegatorAddr, &escrowAddr, seq)
+	}
+	return debondingDelegationTrancheKeyFmt.Encode(&delegatorAddr, &escrowAddr, seq, tranche)
+}
+
+// debondingQueueKey returns the debonding queue key of the given debonding delegation tranche.
+func debondingQueueKey(
+	epoch epochtime.EpochTime,
+	delegatorAddr, escrowAddr staking.Address,
+	seq uint64,
+	tranche uint32,
+) []byte {
+	if tranche == 0 {
+		return debondingQueueKeyFmt.Encode(uint64(epoch), &delegatorAddr, &escrowAddr, seq)
+	}
+	return debondingQueueTrancheKeyFmt.Encode(uint64(epoch), &delegatorAddr, &escrowAddr, seq, tranche)
+}
+
 type ImmutableState struct {
 	is *abciAPI.ImmutableState
 }
@@ -295,29 +349,31 @@ func (s *ImmutableState) DelegationsFor(
 	return delegations, nil
 }
 
-func (s *ImmutableState) DebondingDelegations(
+// DelegationsTo returns all active delegations to the given escrow account.
+func (s *ImmutableState) DelegationsTo(
 	ctx context.Context,
-) (map[staking.Address]map[staking.Address][]*staking.DebondingDelegation, error) {
+	escrowAddr staking.Address,
+) (map[staking.Address]*staking.Delegation, error) {
 	it := s.is.NewIterator(ctx)
 	defer it.Close()
 
-	delegations := make(map[staking.Address]map[staking.Address][]*staking.DebondingDelegation)
-	for it.Seek(debondingDelegationKeyFmt.Encode()); it.Valid(); it.Next() {
-		var escrowAddr staking.Address
+	delegations := make(map[staking.Address]*staking.Delegation)
+	for it.Seek(delegationKeyFmt.Encode(&escrowAddr)); it.Valid(); it.Next() {
+		var decEscrowAddr staking.Address
 		var delegatorAddr staking.Address
-		if !debondingDelegationKeyFmt.Decode(it.Key(), &delegatorAddr, &escrowAddr) {
+		if !delegationKeyFmt.Decode(it.Key(), &decEscrowAddr, &delegatorAddr) {
+			break
+		}
+		if !decEscrowAddr.Equal(escrowAddr) {
 			break
 		}
 
-		var deb staking.DebondingDelegation
-		if err := cbor.Unmarshal(it.Value(), &deb); err != nil {
+		var del staking.Delegation
+		if err := cbor.Unmarshal(it.Value(), &del); err != nil {
 			return nil, abciAPI.UnavailableStateError(err)
 		}
 
-		if delegations[escrowAddr] == nil {
-			delegations[escrowAddr] = make(map[staking.Address][]*staking.DebondingDelegation)
-		}
-		delegations[escrowAddr][delegatorAddr] = append(delegations[escrowAddr][delegatorAddr], &deb)
+		delegations[delegatorAddr] = &del
 	}
 	if it.Err() != nil {
 		return nil, abciAPI.UnavailableStateError(it.Err())
@@ -325,33 +381,68 @@ func (s *ImmutableState) DebondingDelegations(
 	return delegations, nil
 }
 
-func (s *ImmutableState) DebondingDelegationsFor(
+// iterateDebondingDelegations calls the given function for each debonding delegation, in both
+// the legacy and the tranche key formats. In case delegatorAddr is not nil, only debonding
+// delegations of the given delegator are visited.
+func (s *ImmutableState) iterateDebondingDelegations(
 	ctx context.Context,
-	delegatorAddr staking.Address,
-) (map[staking.Address][]*staking.DebondingDelegation, error) {
+	delegatorAddr *staking.Address,
+	fn func(delegatorAddr, escrowAddr staking.Address, deb *staking.DebondingDelegation),
+) error {
 	it := s.is.NewIterator(ctx)
 	defer it.Close()
 
-	delegations := make(map[staking.Address][]*staking.DebondingDelegation)
-	for it.Seek(debondingDelegationKeyFmt.Encode(&delegatorAddr)); it.Valid(); it.Next() {
-		var escrowAddr staking.Address
-		var decDelegatorAddr staking.Address
-		if !debondingDelegationKeyFmt.Decode(it.Key(), &decDelegatorAddr, &escrowAddr) {
-			break
-		}
-		if !decDelegatorAddr.Equal(delegatorAddr) {
-			continue
+	for _, kf := range []*keyformat.KeyFormat{debondingDelegationKeyFmt, debondingDelegationTrancheKeyFmt} {
+		prefix := kf.Encode()
+		if delegatorAddr != nil {
+			prefix = kf.Encode(delegatorAddr)
 		}
+		for it.Seek(prefix); it.Valid() && bytes.HasPrefix(it.Key(), prefix); it.Next() {
+			var decDelegatorAddr, escrowAddr staking.Address
+			if !kf.Decode(it.Key(), &decDelegatorAddr, &escrowAddr) {
+				break
+			}
 
-		var deb staking.DebondingDelegation
-		if err := cbor.Unmarshal(it.Value(), &deb); err != nil {
-			return nil, abciAPI.UnavailableStateError(err)
+			var deb staking.DebondingDelegation
+			if err := cbor.Unmarshal(it.Value(), &deb); err != nil {
+				return abciAPI.UnavailableStateError(err)
+			}
+
+			fn(decDelegatorAddr, escrowAddr, &deb)
+		}
+		if it.Err() != nil {
+			return abciAPI.UnavailableStateError(it.Err())
 		}
+	}
+	return nil
+}
 
-		delegations[escrowAddr] = append(delegations[escrowAddr], &deb)
+func (s *ImmutableState) DebondingDelegations(
+	ctx context.Context,
+) (map[staking.Address]map[staking.Address][]*staking.DebondingDelegation, error) {
+	delegations := make(map[staking.Address]map[staking.Address][]*staking.DebondingDelegation)
+	err := s.iterateDebondingDelegations(ctx, nil, func(delegatorAddr, escrowAddr staking.Address, deb *staking.DebondingDelegation) {
+		if delegations[escrowAddr] == nil {
+			delegations[escrowAddr] = make(map[staking.Address][]*staking.DebondingDelegation)
+		}
+		delegations[escrowAddr][delegatorAddr] = append(delegations[escrowAddr][delegatorAddr], deb)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if it.Err() != nil {
-		return nil, abciAPI.UnavailableStateError(it.Err())
+	return delegations, nil
+}
+
+func (s *ImmutableState) DebondingDelegationsFor(
+	ctx context.Context,
+	delegatorAddr staking.Address,
+) (map[staking.Address][]*staking.DebondingDelegation, error) {
+	delegations := make(map[staking.Address][]*staking.DebondingDelegation)
+	err := s.iterateDebondingDelegations(ctx, &delegatorAddr, func(_, escrowAddr staking.Address, deb *staking.DebondingDelegation) {
+		delegations[escrowAddr] = append(delegations[escrowAddr], deb)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return delegations, nil
 }
@@ -360,8 +451,9 @@ func (s *ImmutableState) DebondingDelegation(
 	ctx context.Context,
 	delegatorAddr, escrowAddr staking.Address,
 	seq uint64,
+	tranche uint32,
 ) (*staking.DebondingDelegation, error) {
-	value, err := s.is.Get(ctx, debondingDelegationKeyFmt.Encode(&delegatorAddr, &escrowAddr, seq))
+	value, err := s.is.Get(ctx, debondingDelegationKey(delegatorAddr, escrowAddr, seq, tranche))
 	if err != nil {
 		return nil, abciAPI.UnavailableStateError(err)
 	}
@@ -381,6 +473,7 @@ type DebondingQueueEntry struct {
 	DelegatorAddr staking.Address
 	EscrowAddr    staking.Address
 	Seq           uint64
+	Tranche       uint32
 	Delegation    *staking.DebondingDelegation
 }
 
@@ -389,29 +482,45 @@ func (s *ImmutableState) ExpiredDebondingQueue(ctx context.Context, epoch epocht
 	defer it.Close()
 
 	var entries []*DebondingQueueEntry
-	for it.Seek(debondingQueueKeyFmt.Encode()); it.Valid(); it.Next() {
-		var decEpoch, seq uint64
-		var escrowAddr staking.Address
-		var delegatorAddr staking.Address
-		if !debondingQueueKeyFmt.Decode(it.Key(), &decEpoch, &delegatorAddr, &escrowAddr, &seq) || decEpoch > uint64(epoch) {
-			break
-		}
+	for _, kf := range []*keyformat.KeyFormat{debondingQueueKeyFmt, debondingQueueTrancheKeyFmt} {
+		for it.Seek(kf.Encode()); it.Valid(); it.Next() {
+			var decEpoch, seq uint64
+			var tranche uint32
+			var escrowAddr staking.Address
+			var delegatorAddr staking.Address
+			var ok bool
+			switch kf {
+			case debondingQueueKeyFmt:
+				ok = kf.Decode(it.Key(), &decEpoch, &delegatorAddr, &escrowAddr, &seq)
+			default:
+				ok = kf.Decode(it.Key(), &decEpoch, &delegatorAddr, &escrowAddr, &seq, &tranche)
+			}
+			if !ok || decEpoch > uint64(epoch) {
+				break
+			}
 
-		deb, err := s.DebondingDelegation(ctx, delegatorAddr, escrowAddr, seq)
-		if err != nil {
-			return nil, err
-		}
-		entries = append(entries, &DebondingQueueEntry{
-			Epoch:         epochtime.EpochTime(decEpoch),
-			DelegatorAddr: delegatorAddr,
-			EscrowAddr:    escrowAddr,
-			Seq:           seq,
-			Delegation:    deb,
-		})
-	}
-	if it.Err() != nil {
-		return nil, abciAPI.UnavailableStateError(it.Err())
+			deb, err := s.DebondingDelegation(ctx, delegatorAddr, escrowAddr, seq, tranche)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, &DebondingQueueEntry{
+				Epoch:         epochtime.EpochTime(decEpoch),
+				DelegatorAddr: delegatorAddr,
+				EscrowAddr:    escrowAddr,
+				Seq:           seq,
+				Tranche:       tranche,
+				Delegation:    deb,
+			})
+		}
+		if it.Err() != nil {
+			return nil, abciAPI.UnavailableStateError(it.Err())
+		}
 	}
+
+	// Process entries from both queues in epoch order.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Epoch < entries[j].Epoch
+	})
 	return entries, nil
 }
 
@@ -503,6 +612,71 @@ func (s *ImmutableState) EpochSigning(ctx context.Context) (*EpochSigning, error
 	return &es, nil
 }
 
+// Alias returns the registration record of the given address alias.
+func (s *ImmutableState) Alias(ctx context.Context, alias string) (*staking.AliasInfo, error) {
+	value, err := s.is.Get(ctx, aliasKeyFmt.Encode([]byte(alias)))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if value == nil {
+		return nil, staking.ErrAliasNotFound
+	}
+
+	var info staking.AliasInfo
+	if err = cbor.Unmarshal(value, &info); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &info, nil
+}
+
+// Aliases returns all registered address aliases.
+func (s *ImmutableState) Aliases(ctx context.Context) (map[string]*staking.AliasInfo, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	aliases := make(map[string]*staking.AliasInfo)
+	for it.Seek(aliasKeyFmt.Encode()); it.Valid(); it.Next() {
+		var alias []byte
+		if !aliasKeyFmt.Decode(it.Key(), &alias) {
+			break
+		}
+
+		var info staking.AliasInfo
+		if err := cbor.Unmarshal(it.Value(), &info); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		aliases[string(alias)] = &info
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return aliases, nil
+}
+
+// ExpiredAliases returns the address aliases the registration of which
+// expires at or before the given epoch.
+func (s *ImmutableState) ExpiredAliases(ctx context.Context, epoch epochtime.EpochTime) ([]string, error) {
+	it := s.is.NewIterator(ctx)
+	defer it.Close()
+
+	var aliases []string
+	for it.Seek(aliasExpirationQueueKeyFmt.Encode()); it.Valid(); it.Next() {
+		var (
+			expiration uint64
+			alias      []byte
+		)
+		if !aliasExpirationQueueKeyFmt.Decode(it.Key(), &expiration, &alias) || expiration > uint64(epoch) {
+			break
+		}
+
+		aliases = append(aliases, string(alias))
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return aliases, nil
+}
+
 func NewImmutableState(ctx context.Context, state abciAPI.ApplicationQueryState, version int64) (*ImmutableState, error) {
 	is, err := abciAPI.NewImmutableState(ctx, state, version)
 	if err != nil {
@@ -558,9 +732,10 @@ func (s *MutableState) SetDebondingDelegation(
 	ctx context.Context,
 	delegatorAddr, escrowAddr staking.Address,
 	seq uint64,
+	tranche uint32,
 	d *staking.DebondingDelegation,
 ) error {
-	key := debondingDelegationKeyFmt.Encode(&delegatorAddr, &escrowAddr, seq)
+	key := debondingDelegationKey(delegatorAddr, escrowAddr, seq, tranche)
 
 	if d == nil {
 		// Remove descriptor.
@@ -571,11 +746,7 @@ func (s *MutableState) SetDebondingDelegation(
 	// Add to debonding queue.
 	if err := s.ms.Insert(
 		ctx,
-		debondingQueueKeyFmt.Encode(uint64(d.DebondEndTime),
-			&delegatorAddr,
-			&escrowAddr,
-			seq,
-		),
+		debondingQueueKey(d.DebondEndTime, delegatorAddr, escrowAddr, seq, tranche),
 		[]byte{},
 	); err != nil {
 		return abciAPI.UnavailableStateError(err)
@@ -592,11 +763,50 @@ func (s *MutableState) RemoveFromDebondingQueue(
 	epoch epochtime.EpochTime,
 	delegatorAddr, escrowAddr staking.Address,
 	seq uint64,
+	tranche uint32,
 ) error {
-	err := s.ms.Remove(ctx, debondingQueueKeyFmt.Encode(uint64(epoch), &delegatorAddr, &escrowAddr, seq))
+	err := s.ms.Remove(ctx, debondingQueueKey(epoch, delegatorAddr, escrowAddr, seq, tranche))
 	return abciAPI.UnavailableStateError(err)
 }
 
+// SetAlias sets the registration record of the given address alias, replacing
+// any existing registration.
+func (s *MutableState) SetAlias(ctx context.Context, alias string, info *staking.AliasInfo) error {
+	if err := s.RemoveAlias(ctx, alias); err != nil {
+		return err
+	}
+
+	if err := s.ms.Insert(ctx, aliasExpirationQueueKeyFmt.Encode(uint64(info.Expiration), []byte(alias)), []byte{}); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	if err := s.ms.Insert(ctx, aliasKeyFmt.Encode([]byte(alias)), cbor.Marshal(info)); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	return nil
+}
+
+// RemoveAlias removes the registration record of the given address alias.
+//
+// Removing an alias that is not registered is not an error.
+func (s *MutableState) RemoveAlias(ctx context.Context, alias string) error {
+	info, err := s.Alias(ctx, alias)
+	switch err {
+	case nil:
+	case staking.ErrAliasNotFound:
+		return nil
+	default:
+		return err
+	}
+
+	if err = s.ms.Remove(ctx, aliasExpirationQueueKeyFmt.Encode(uint64(info.Expiration), []byte(alias))); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	if err = s.ms.Remove(ctx, aliasKeyFmt.Encode([]byte(alias))); err != nil {
+		return abciAPI.UnavailableStateError(err)
+	}
+	return nil
+}
+
 func (s *MutableState) SetLastBlockFees(ctx context.Context, q *quantity.Quantity) error {
 	err := s.ms.Insert(ctx, lastBlockFeesKeyFmt.Encode(), cbor.Marshal(q))
 	return abciAPI.UnavailableStateError(err)
@@ -637,6 +847,8 @@ func slashPool(dst *quantity.Quantity, p *staking.SharePool, amount, total *quan
 // and MUST NOT be exposed outside of backend implementations.
 func (s *MutableState) SlashEscrow(
 	ctx *abciAPI.Context,
+	epoch epochtime.EpochTime,
+	reason staking.SlashReason,
 	fromAddr staking.Address,
 	amount *quantity.Quantity,
 ) (bool, error) {
@@ -688,6 +900,14 @@ func (s *MutableState) SlashEscrow(
 			Amount: *totalSlashed,
 		})
 		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyTakeEscrow, ev))
+
+		ev = cbor.Marshal(&staking.SlashEvent{
+			Account: fromAddr,
+			Epoch:   epoch,
+			Amount:  *totalSlashed,
+			Reason:  reason,
+		})
+		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeySlash, ev))
 	}
 
 	return true, nil
@@ -750,6 +970,7 @@ func (s *MutableState) AddRewards(
 	ctx *abciAPI.Context,
 	time epochtime.EpochTime,
 	factor *quantity.Quantity,
+	reason staking.RewardReason,
 	addresses []staking.Address,
 ) error {
 	steps, err := s.RewardSchedule(ctx)
@@ -795,6 +1016,7 @@ func (s *MutableState) AddRewards(
 		if q.IsZero() {
 			continue
 		}
+		total := q.Clone()
 
 		var com *quantity.Quantity
 		rate := ent.Escrow.CommissionSchedule.CurrentRate(time)
@@ -818,9 +1040,12 @@ func (s *MutableState) AddRewards(
 				return fmt.Errorf("tendermint/staking: failed transferring to active escrow balance from common pool: %w", err)
 			}
 			ev := cbor.Marshal(&staking.AddEscrowEvent{
-				Owner:  staking.CommonPoolAddress,
-				Escrow: addr,
-				Amount: *q,
+				V:             staking.EscrowEventVersionShares,
+				Owner:         staking.CommonPoolAddress,
+				Escrow:        addr,
+				Amount:        *q,
+				ActiveBalance: ent.Escrow.Active.Balance,
+				ActiveShares:  ent.Escrow.Active.TotalShares,
 			})
 			ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 		}
@@ -832,6 +1057,10 @@ func (s *MutableState) AddRewards(
 				return fmt.Errorf("tendermint/staking: failed to query delegation: %w", err)
 			}
 
+			var newShares *quantity.Quantity
+			if newShares, err = ent.Escrow.Active.SharesForStake(com); err != nil {
+				return fmt.Errorf("tendermint/staking: failed computing commission shares: %w", err)
+			}
 			if err = ent.Escrow.Active.Deposit(&delegation.Shares, commonPool, com); err != nil {
 				return fmt.Errorf("tendermint/staking: depositing commission: %w", err)
 			}
@@ -841,9 +1070,13 @@ func (s *MutableState) AddRewards(
 			}
 
 			ev := cbor.Marshal(&staking.AddEscrowEvent{
-				Owner:  staking.CommonPoolAddress,
-				Escrow: addr,
-				Amount: *com,
+				V:             staking.EscrowEventVersionShares,
+				Owner:         staking.CommonPoolAddress,
+				Escrow:        addr,
+				Amount:        *com,
+				NewShares:     *newShares,
+				ActiveBalance: ent.Escrow.Active.Balance,
+				ActiveShares:  ent.Escrow.Active.TotalShares,
 			})
 			ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 		}
@@ -851,6 +1084,14 @@ func (s *MutableState) AddRewards(
 		if err = s.SetAccount(ctx, addr, ent); err != nil {
 			return fmt.Errorf("tendermint/staking: failed to set account: %w", err)
 		}
+
+		ev := cbor.Marshal(&staking.RewardEvent{
+			Account: addr,
+			Epoch:   time,
+			Amount:  *total,
+			Reason:  reason,
+		})
+		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyReward, ev))
 	}
 
 	if err = s.SetCommonPool(ctx, commonPool); err != nil {
@@ -866,6 +1107,7 @@ func (s *MutableState) AddRewardSingleAttenuated(
 	time epochtime.EpochTime,
 	factor *quantity.Quantity,
 	attenuationNumerator, attenuationDenominator int,
+	reason staking.RewardReason,
 	address staking.Address,
 ) error {
 	steps, err := s.RewardSchedule(ctx)
@@ -923,6 +1165,7 @@ func (s *MutableState) AddRewardSingleAttenuated(
 	if q.IsZero() {
 		return nil
 	}
+	total := q.Clone()
 
 	var com *quantity.Quantity
 	rate := acct.Escrow.CommissionSchedule.CurrentRate(time)
@@ -946,9 +1189,12 @@ func (s *MutableState) AddRewardSingleAttenuated(
 			return fmt.Errorf("tendermint/staking: failed transferring to active escrow balance from common pool: %w", err)
 		}
 		ev := cbor.Marshal(&staking.AddEscrowEvent{
-			Owner:  staking.CommonPoolAddress,
-			Escrow: address,
-			Amount: *q,
+			V:             staking.EscrowEventVersionShares,
+			Owner:         staking.CommonPoolAddress,
+			Escrow:        address,
+			Amount:        *q,
+			ActiveBalance: acct.Escrow.Active.Balance,
+			ActiveShares:  acct.Escrow.Active.TotalShares,
 		})
 		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 	}
@@ -960,6 +1206,10 @@ func (s *MutableState) AddRewardSingleAttenuated(
 			return fmt.Errorf("tendermint/staking: failed to query delegation: %w", err)
 		}
 
+		var newShares *quantity.Quantity
+		if newShares, err = acct.Escrow.Active.SharesForStake(com); err != nil {
+			return fmt.Errorf("tendermint/staking: failed computing commission shares: %w", err)
+		}
 		if err = acct.Escrow.Active.Deposit(&delegation.Shares, commonPool, com); err != nil {
 			return fmt.Errorf("tendermint/staking: failed depositing commission: %w", err)
 		}
@@ -969,9 +1219,13 @@ func (s *MutableState) AddRewardSingleAttenuated(
 		}
 
 		ev := cbor.Marshal(&staking.AddEscrowEvent{
-			Owner:  staking.CommonPoolAddress,
-			Escrow: address,
-			Amount: *com,
+			V:             staking.EscrowEventVersionShares,
+			Owner:         staking.CommonPoolAddress,
+			Escrow:        address,
+			Amount:        *com,
+			NewShares:     *newShares,
+			ActiveBalance: acct.Escrow.Active.Balance,
+			ActiveShares:  acct.Escrow.Active.TotalShares,
 		})
 		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyAddEscrow, ev))
 	}
@@ -984,6 +1238,14 @@ func (s *MutableState) AddRewardSingleAttenuated(
 		return fmt.Errorf("tendermint/staking: failed to set common pool: %w", err)
 	}
 
+	ev := cbor.Marshal(&staking.RewardEvent{
+		Account: address,
+		Epoch:   time,
+		Amount:  *total,
+		Reason:  reason,
+	})
+	ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyReward, ev))
+
 	return nil
 }
 
@@ -8,8 +8,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
@@ -89,7 +91,7 @@ func TestDelegationQueries(t *testing.T) {
 		require.NoError(err, "SetAccount")
 		err = s.SetDelegation(ctx, addr, escrowAddr, &del)
 		require.NoError(err, "SetDelegation")
-		err = s.SetDebondingDelegation(ctx, addr, escrowAddr, uint64(i), &deb)
+		err = s.SetDebondingDelegation(ctx, addr, escrowAddr, uint64(i), 0, &deb)
 		require.NoError(err, "SetDebondingDelegation")
 	}
 
@@ -105,6 +107,12 @@ func TestDelegationQueries(t *testing.T) {
 	delegations, err := s.Delegations(ctx)
 	require.NoError(err, "state.Delegations")
 	require.EqualValues(expectedDelegations, delegations, "Delegations should match expected delegations")
+	escrowDelegations, err := s.DelegationsTo(ctx, escrowAddr)
+	require.NoError(err, "DelegationsTo")
+	require.EqualValues(expectedDelegations[escrowAddr], escrowDelegations, "DelegationsTo should match expected delegations")
+	escrowDelegations, err = s.DelegationsTo(ctx, delegatorAddrs[0])
+	require.NoError(err, "DelegationsTo")
+	require.Empty(escrowDelegations, "DelegationsTo account without delegations should be empty")
 
 	// Test debonding delegation queries.
 	for _, addr := range delegatorAddrs {
@@ -120,6 +128,86 @@ func TestDelegationQueries(t *testing.T) {
 	require.EqualValues(expectedDebDelegations, debDelegations, "DebondingDelegations should match expected")
 }
 
+func TestDebondingDelegationTranches(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	delegatorAddr := staking.NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	escrowAddr := staking.NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	// Debonding delegations written before tranches were supported use a layout without
+	// the tranche index.
+	oldDelegationKeyFmt := keyformat.New(0x54, &staking.Address{}, &staking.Address{}, uint64(0))
+	oldQueueKeyFmt := keyformat.New(0x55, uint64(0), &staking.Address{}, &staking.Address{}, uint64(0))
+
+	oldDeb := staking.DebondingDelegation{
+		Shares:        mustInitQuantity(t, 10),
+		DebondEndTime: 15,
+	}
+	err := s.ms.Insert(ctx, oldDelegationKeyFmt.Encode(&delegatorAddr, &escrowAddr, uint64(1)), cbor.Marshal(&oldDeb))
+	require.NoError(err, "Insert")
+	err = s.ms.Insert(ctx, oldQueueKeyFmt.Encode(uint64(15), &delegatorAddr, &escrowAddr, uint64(1)), []byte{})
+	require.NoError(err, "Insert")
+
+	// Add a debonding delegation split into tranches.
+	trancheDebs := []*staking.DebondingDelegation{
+		{Shares: mustInitQuantity(t, 20), DebondEndTime: 14},
+		{Shares: mustInitQuantity(t, 30), DebondEndTime: 15},
+		{Shares: mustInitQuantity(t, 40), DebondEndTime: 16},
+	}
+	for i, deb := range trancheDebs {
+		err = s.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, 2, uint32(i), deb)
+		require.NoError(err, "SetDebondingDelegation")
+	}
+
+	deb, err := s.DebondingDelegation(ctx, delegatorAddr, escrowAddr, 1, 0)
+	require.NoError(err, "DebondingDelegation")
+	require.Equal(&oldDeb, deb, "debonding delegation in the old layout should be readable")
+	for i, expected := range trancheDebs {
+		deb, err = s.DebondingDelegation(ctx, delegatorAddr, escrowAddr, 2, uint32(i))
+		require.NoError(err, "DebondingDelegation")
+		require.Equal(expected, deb, "debonding delegation tranche %d should be readable", i)
+	}
+
+	debs, err := s.DebondingDelegations(ctx)
+	require.NoError(err, "DebondingDelegations")
+	require.Len(debs[escrowAddr][delegatorAddr], 4, "all debonding delegations should be returned")
+	debsFor, err := s.DebondingDelegationsFor(ctx, delegatorAddr)
+	require.NoError(err, "DebondingDelegationsFor")
+	require.Len(debsFor[escrowAddr], 4, "all debonding delegations should be returned")
+
+	expired, err := s.ExpiredDebondingQueue(ctx, 15)
+	require.NoError(err, "ExpiredDebondingQueue")
+	require.Len(expired, 3, "expired debonding delegations from both layouts should be returned")
+	for i, e := range expired {
+		if i > 0 {
+			require.True(expired[i-1].Epoch <= e.Epoch, "expired entries should be in epoch order")
+		}
+	}
+	require.EqualValues(14, expired[0].Epoch, "first expired entry should be the earliest tranche")
+	require.EqualValues(2, expired[0].Seq, "first expired entry should be the earliest tranche")
+	require.EqualValues(0, expired[0].Tranche, "first expired entry should be the earliest tranche")
+
+	// Remove the old layout entry.
+	err = s.RemoveFromDebondingQueue(ctx, 15, delegatorAddr, escrowAddr, 1, 0)
+	require.NoError(err, "RemoveFromDebondingQueue")
+	err = s.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, 1, 0, nil)
+	require.NoError(err, "SetDebondingDelegation")
+
+	expired, err = s.ExpiredDebondingQueue(ctx, 15)
+	require.NoError(err, "ExpiredDebondingQueue")
+	require.Len(expired, 2, "removed entry should no longer be in the queue")
+	debsFor, err = s.DebondingDelegationsFor(ctx, delegatorAddr)
+	require.NoError(err, "DebondingDelegationsFor")
+	require.Len(debsFor[escrowAddr], 3, "removed debonding delegation should no longer be returned")
+}
+
 func TestRewardAndSlash(t *testing.T) {
 	require := require.New(t)
 
@@ -205,11 +293,11 @@ func TestRewardAndSlash(t *testing.T) {
 	require.NoError(err, "SetAccount")
 	err = s.SetDelegation(ctx, delegatorAddr, escrowAddr, del)
 	require.NoError(err, "SetDelegation")
-	err = s.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, 1, &deb)
+	err = s.SetDebondingDelegation(ctx, delegatorAddr, escrowAddr, 1, 0, &deb)
 	require.NoError(err, "SetDebondingDelegation")
 
 	// Epoch 10 is during the first step.
-	require.NoError(s.AddRewards(ctx, 10, mustInitQuantityP(t, 100_000), escrowAddrAsList), "add rewards epoch 10")
+	require.NoError(s.AddRewards(ctx, 10, mustInitQuantityP(t, 100_000), staking.RewardEpochSigned, escrowAddrAsList), "add rewards epoch 10")
 
 	// 100% gain.
 	delegatorAccount, err = s.Account(ctx, delegatorAddr)
@@ -233,7 +321,7 @@ func TestRewardAndSlash(t *testing.T) {
 	require.Equal(mustInitQuantityP(t, 9900), commonPool, "reward first step - common pool")
 
 	// Epoch 30 is in the second step.
-	require.NoError(s.AddRewards(ctx, 30, mustInitQuantityP(t, 100_000), escrowAddrAsList), "add rewards epoch 30")
+	require.NoError(s.AddRewards(ctx, 30, mustInitQuantityP(t, 100_000), staking.RewardEpochSigned, escrowAddrAsList), "add rewards epoch 30")
 
 	// 50% gain.
 	escrowAccount, err = s.Account(ctx, escrowAddr)
@@ -244,14 +332,14 @@ func TestRewardAndSlash(t *testing.T) {
 	require.Equal(mustInitQuantityP(t, 9800), commonPool, "reward first step - common pool")
 
 	// Epoch 99 is after the end of the schedule
-	require.NoError(s.AddRewards(ctx, 99, mustInitQuantityP(t, 100_000), escrowAddrAsList), "add rewards epoch 99")
+	require.NoError(s.AddRewards(ctx, 99, mustInitQuantityP(t, 100_000), staking.RewardEpochSigned, escrowAddrAsList), "add rewards epoch 99")
 
 	// No change.
 	escrowAccount, err = s.Account(ctx, escrowAddr)
 	require.NoError(err, "Account")
 	require.Equal(mustInitQuantity(t, 300), escrowAccount.Escrow.Active.Balance, "reward late epoch - escrow active escrow")
 
-	slashedNonzero, err := s.SlashEscrow(ctx, escrowAddr, mustInitQuantityP(t, 40))
+	slashedNonzero, err := s.SlashEscrow(ctx, 99, staking.SlashDoubleSigning, escrowAddr, mustInitQuantityP(t, 40))
 	require.NoError(err, "slash escrow")
 	require.True(slashedNonzero, "slashed nonzero")
 
@@ -268,7 +356,7 @@ func TestRewardAndSlash(t *testing.T) {
 	require.Equal(mustInitQuantityP(t, 9840), commonPool, "slash - common pool")
 
 	// Epoch 10 is during the first step.
-	require.NoError(s.AddRewardSingleAttenuated(ctx, 10, mustInitQuantityP(t, 10_000), 5, 10, escrowAddr), "add attenuated rewards epoch 30")
+	require.NoError(s.AddRewardSingleAttenuated(ctx, 10, mustInitQuantityP(t, 10_000), 5, 10, staking.RewardBlockProposed, escrowAddr), "add attenuated rewards epoch 30")
 
 	// 5% gain.
 	escrowAccount, err = s.Account(ctx, escrowAddr)
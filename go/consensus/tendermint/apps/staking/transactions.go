@@ -7,6 +7,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -40,6 +41,11 @@ func (app *stakingApplication) transfer(ctx *api.Context, state *stakingState.Mu
 		return staking.ErrForbidden
 	}
 
+	// Check if sender provided at least a minimum amount of stake.
+	if xfer.Amount.Cmp(&params.MinTransferAmount) < 0 {
+		return staking.ErrInvalidArgument
+	}
+
 	from, err := state.Account(ctx, fromAddr)
 	if err != nil {
 		return fmt.Errorf("failed to fetch account: %w", err)
@@ -161,6 +167,80 @@ func (app *stakingApplication) burn(ctx *api.Context, state *stakingState.Mutabl
 	return nil
 }
 
+func isMintPermitted(params *staking.ConsensusParameters, minterAddr staking.Address) bool {
+	return params.MintAllowlist != nil && params.MintAllowlist[minterAddr]
+}
+
+func (app *stakingApplication) mint(ctx *api.Context, state *stakingState.MutableState, mint *staking.Mint) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpMint, params.GasCosts); err != nil {
+		return err
+	}
+
+	minterAddr := staking.NewAddress(ctx.TxSigner())
+	if minterAddr.IsReserved() || !isMintPermitted(params, minterAddr) {
+		return staking.ErrForbidden
+	}
+	if mint.To.IsReserved() {
+		return staking.ErrForbidden
+	}
+	if mint.Amount.IsZero() {
+		return staking.ErrInvalidArgument
+	}
+
+	to, err := state.Account(ctx, mint.To)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+	if err = to.General.Balance.Add(&mint.Amount); err != nil {
+		ctx.Logger().Error("Mint: failed to mint stake",
+			"err", err,
+			"minter", minterAddr,
+			"to", mint.To,
+			"amount", mint.Amount,
+		)
+		return err
+	}
+
+	totalSupply, err := state.TotalSupply(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch total supply: %w", err)
+	}
+	if err = totalSupply.Add(&mint.Amount); err != nil {
+		return fmt.Errorf("failed to update total supply: %w", err)
+	}
+
+	if err = state.SetAccount(ctx, mint.To, to); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+	if err = state.SetTotalSupply(ctx, totalSupply); err != nil {
+		return fmt.Errorf("failed to set total supply: %w", err)
+	}
+
+	ctx.Logger().Info("Mint: minted stake",
+		"minter", minterAddr,
+		"to", mint.To,
+		"amount", mint.Amount,
+	)
+
+	evt := &staking.MintEvent{
+		Minter: minterAddr,
+		To:     mint.To,
+		Amount: mint.Amount,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyMint, cbor.Marshal(evt)))
+
+	return nil
+}
+
 func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.MutableState, escrow *staking.Escrow) error {
 	if ctx.IsCheckOnly() {
 		return nil
@@ -213,6 +293,15 @@ func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.M
 		return fmt.Errorf("failed to fetch delegation: %w", err)
 	}
 
+	newShares, err := to.Escrow.Active.SharesForStake(&escrow.Amount)
+	if err != nil {
+		ctx.Logger().Error("AddEscrow: failed to compute new shares",
+			"err", err,
+			"to", escrow.Account,
+			"amount", escrow.Amount,
+		)
+		return err
+	}
 	if err = to.Escrow.Active.Deposit(&delegation.Shares, &from.General.Balance, &escrow.Amount); err != nil {
 		ctx.Logger().Error("AddEscrow: failed to escrow stake",
 			"err", err,
@@ -244,9 +333,13 @@ func (app *stakingApplication) addEscrow(ctx *api.Context, state *stakingState.M
 	)
 
 	evt := &staking.AddEscrowEvent{
-		Owner:  fromAddr,
-		Escrow: escrow.Account,
-		Amount: escrow.Amount,
+		V:             staking.EscrowEventVersionShares,
+		Owner:         fromAddr,
+		Escrow:        escrow.Account,
+		Amount:        escrow.Amount,
+		NewShares:     *newShares,
+		ActiveBalance: to.Escrow.Active.Balance,
+		ActiveShares:  to.Escrow.Active.TotalShares,
 	}
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyAddEscrow, cbor.Marshal(evt)))
 
@@ -268,7 +361,14 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 	if err != nil {
 		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
 	}
-	if err = ctx.Gas().UseGas(1, staking.GasOpReclaimEscrow, params.GasCosts); err != nil {
+
+	// Splitting the reclaimed stake into multiple debonding tranches must be
+	// enabled and each tranche is charged separately.
+	numTranches := reclaim.NumTranches()
+	if numTranches > 1 && numTranches > params.MaxReclaimEscrowTranches {
+		return staking.ErrInvalidArgument
+	}
+	if err = ctx.Gas().UseGas(int(numTranches), staking.GasOpReclaimEscrow, params.GasCosts); err != nil {
 		return err
 	}
 
@@ -318,10 +418,6 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 		return err
 	}
 
-	deb := staking.DebondingDelegation{
-		DebondEndTime: epoch + debondingInterval,
-	}
-
 	var baseUnits quantity.Quantity
 
 	if err = from.Escrow.Active.Withdraw(&baseUnits, &delegation.Shares, &reclaim.Shares); err != nil {
@@ -335,15 +431,39 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 	}
 	stakeAmount := baseUnits.Clone()
 
-	if err = from.Escrow.Debonding.Deposit(&deb.Shares, &baseUnits, stakeAmount); err != nil {
-		ctx.Logger().Error("ReclaimEscrow: failed to debond shares",
-			"err", err,
-			"to", toAddr,
-			"from", reclaim.Account,
-			"shares", reclaim.Shares,
-			"base_units", stakeAmount,
-		)
-		return err
+	// Split the reclaimed stake into equal tranches debonding over consecutive
+	// epochs. Any remainder is added to the last tranche.
+	trancheAmount := stakeAmount.Clone()
+	if err = trancheAmount.Quo(quantity.NewFromUint64(uint64(numTranches))); err != nil {
+		return fmt.Errorf("failed to compute tranche amount: %w", err)
+	}
+	if numTranches > 1 && trancheAmount.IsZero() {
+		// Not enough stake to fill every tranche.
+		return staking.ErrInvalidArgument
+	}
+
+	debs := make([]*staking.DebondingDelegation, numTranches)
+	for i := range debs {
+		amount := trancheAmount
+		if i == len(debs)-1 {
+			amount = baseUnits.Clone()
+		}
+
+		deb := &staking.DebondingDelegation{
+			DebondEndTime: epoch + debondingInterval + epochtime.EpochTime(i),
+		}
+		if err = from.Escrow.Debonding.Deposit(&deb.Shares, &baseUnits, amount); err != nil {
+			ctx.Logger().Error("ReclaimEscrow: failed to debond shares",
+				"err", err,
+				"to", toAddr,
+				"from", reclaim.Account,
+				"shares", reclaim.Shares,
+				"base_units", stakeAmount,
+				"tranche", i,
+			)
+			return err
+		}
+		debs[i] = deb
 	}
 
 	if !baseUnits.IsZero() {
@@ -353,9 +473,12 @@ func (app *stakingApplication) reclaimEscrow(ctx *api.Context, state *stakingSta
 		return staking.ErrInvalidArgument
 	}
 
-	// Include the nonce as the final disambiguator to prevent overwriting debonding delegations.
-	if err = state.SetDebondingDelegation(ctx, toAddr, reclaim.Account, to.General.Nonce, &deb); err != nil {
-		return fmt.Errorf("failed to set debonding delegation: %w", err)
+	// Include the nonce and tranche index as the final disambiguators to prevent overwriting
+	// debonding delegations.
+	for i, deb := range debs {
+		if err = state.SetDebondingDelegation(ctx, toAddr, reclaim.Account, to.General.Nonce, uint32(i), deb); err != nil {
+			return fmt.Errorf("failed to set debonding delegation: %w", err)
+		}
 	}
 
 	if err = state.SetDelegation(ctx, toAddr, reclaim.Account, delegation); err != nil {
@@ -595,3 +718,122 @@ func (app *stakingApplication) withdraw(
 
 	return nil
 }
+
+func (app *stakingApplication) registerAlias(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	reg *staking.RegisterAlias,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpRegisterAlias, params.GasCosts); err != nil {
+		return err
+	}
+
+	// Alias registration is disabled in case the maximum registration period is zero.
+	if params.MaxAliasRegistrationEpochs == 0 {
+		return staking.ErrForbidden
+	}
+
+	ownerAddr := staking.NewAddress(ctx.TxSigner())
+	if ownerAddr.IsReserved() {
+		return staking.ErrForbidden
+	}
+	if err = staking.ValidateAlias(reg.Alias); err != nil {
+		return err
+	}
+	if reg.Epochs == 0 || reg.Epochs > params.MaxAliasRegistrationEpochs {
+		return staking.ErrInvalidArgument
+	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return err
+	}
+
+	// New registrations start at the current epoch while renewals extend the
+	// existing registration.
+	expiration := epoch + reg.Epochs
+	info, err := state.Alias(ctx, reg.Alias)
+	switch err {
+	case nil:
+		if !info.Address.Equal(ownerAddr) {
+			return staking.ErrAliasTaken
+		}
+		expiration = info.Expiration + reg.Epochs
+		if expiration-epoch > params.MaxAliasRegistrationEpochs {
+			return staking.ErrInvalidArgument
+		}
+	case staking.ErrAliasNotFound:
+	default:
+		return fmt.Errorf("failed to fetch alias: %w", err)
+	}
+
+	// Transfer the registration fee into the common pool.
+	fee := params.AliasRegistrationFee.Clone()
+	if err = fee.Mul(quantity.NewFromUint64(uint64(reg.Epochs))); err != nil {
+		return fmt.Errorf("failed to compute alias registration fee: %w", err)
+	}
+	owner, err := state.Account(ctx, ownerAddr)
+	if err != nil {
+		return fmt.Errorf("failed to fetch account: %w", err)
+	}
+	commonPool, err := state.CommonPool(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch common pool: %w", err)
+	}
+	if err = quantity.Move(commonPool, &owner.General.Balance, fee); err != nil {
+		ctx.Logger().Error("RegisterAlias: failed to pay registration fee",
+			"err", err,
+			"owner", ownerAddr,
+			"alias", reg.Alias,
+			"fee", fee,
+		)
+		return staking.ErrInsufficientBalance
+	}
+
+	if err = state.SetAccount(ctx, ownerAddr, owner); err != nil {
+		return fmt.Errorf("failed to set account: %w", err)
+	}
+	if err = state.SetCommonPool(ctx, commonPool); err != nil {
+		return fmt.Errorf("failed to set common pool: %w", err)
+	}
+	if err = state.SetAlias(ctx, reg.Alias, &staking.AliasInfo{
+		Address:    ownerAddr,
+		Expiration: expiration,
+	}); err != nil {
+		return fmt.Errorf("failed to set alias: %w", err)
+	}
+
+	ctx.Logger().Debug("RegisterAlias: registered alias",
+		"owner", ownerAddr,
+		"alias", reg.Alias,
+		"expiration", expiration,
+		"fee", fee,
+	)
+
+	if !fee.IsZero() {
+		xferEvt := &staking.TransferEvent{
+			From:   ownerAddr,
+			To:     staking.CommonPoolAddress,
+			Amount: *fee,
+		}
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyTransfer, cbor.Marshal(xferEvt)))
+	}
+
+	aliasEvt := &staking.AliasEvent{
+		Alias:      reg.Alias,
+		Owner:      ownerAddr,
+		Expiration: expiration,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyAlias, cbor.Marshal(aliasEvt)))
+
+	return nil
+}
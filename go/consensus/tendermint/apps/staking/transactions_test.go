@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -112,6 +113,9 @@ func TestReservedAddresses(t *testing.T) {
 
 	err = app.withdraw(ctx, stakeState, &staking.Withdraw{})
 	require.EqualError(err, "staking: forbidden by policy", "withdraw for reserved address should error")
+
+	err = app.mint(ctx, stakeState, &staking.Mint{})
+	require.EqualError(err, "staking: forbidden by policy", "mint for reserved address should error")
 }
 
 func TestAllow(t *testing.T) {
@@ -516,3 +520,500 @@ func TestWithdraw(t *testing.T) {
 		require.Equal(expectedBalance, afterAcct.General.Balance, "general balance should be correct after withdraw")
 	}
 }
+
+func TestTransferMinAmount(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		MinTransferAmount: *quantity.NewFromUint64(10),
+	})
+	require.NoError(err, "setting staking consensus parameters should not error")
+
+	err = stakeState.SetAccount(ctx, addr1, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(100),
+		},
+	})
+	require.NoError(err, "SetAccount")
+
+	ctx.SetTxSigner(pk1)
+
+	for _, tc := range []struct {
+		msg    string
+		amount uint64
+		err    error
+	}{
+		{"should fail with amount below the minimum", 9, staking.ErrInvalidArgument},
+		{"should succeed with amount equal to the minimum", 10, nil},
+		{"should succeed with amount above the minimum", 11, nil},
+	} {
+		beforeAcct, err := stakeState.Account(ctx, addr2)
+		require.NoError(err, "reading account state should not error")
+
+		xfer := &staking.Transfer{
+			To:     addr2,
+			Amount: *quantity.NewFromUint64(tc.amount),
+		}
+		err = app.transfer(ctx, stakeState, xfer)
+		require.Equal(tc.err, err, tc.msg)
+
+		afterAcct, err := stakeState.Account(ctx, addr2)
+		require.NoError(err, "reading account state should not error")
+
+		expectedBalance := beforeAcct.General.Balance.Clone()
+		if tc.err == nil {
+			err = expectedBalance.Add(&xfer.Amount)
+			require.NoError(err, "computing expected balance should not fail")
+		}
+		require.Equal(*expectedBalance, afterAcct.General.Balance, "general balance should be correct after transfer")
+	}
+}
+
+func TestMint(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	reservedPK := signature.NewPublicKey("badbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	reservedAddr := staking.NewReservedAddress(reservedPK)
+
+	err = stakeState.SetTotalSupply(ctx, quantity.NewFromUint64(100))
+	require.NoError(err, "SetTotalSupply")
+
+	for _, tc := range []struct {
+		msg      string
+		params   *staking.ConsensusParameters
+		txSigner signature.PublicKey
+		mint     *staking.Mint
+		err      error
+	}{
+		{
+			"should fail with empty allowlist",
+			&staking.ConsensusParameters{},
+			pk1,
+			&staking.Mint{
+				To:     addr2,
+				Amount: *quantity.NewFromUint64(10),
+			},
+			staking.ErrForbidden,
+		},
+		{
+			"should fail if signer is not in the allowlist",
+			&staking.ConsensusParameters{
+				MintAllowlist: map[staking.Address]bool{
+					addr1: true,
+				},
+			},
+			pk2,
+			&staking.Mint{
+				To:     addr2,
+				Amount: *quantity.NewFromUint64(10),
+			},
+			staking.ErrForbidden,
+		},
+		{
+			"should fail with reserved destination address",
+			&staking.ConsensusParameters{
+				MintAllowlist: map[staking.Address]bool{
+					addr1: true,
+				},
+			},
+			pk1,
+			&staking.Mint{
+				To:     reservedAddr,
+				Amount: *quantity.NewFromUint64(10),
+			},
+			staking.ErrForbidden,
+		},
+		{
+			"should fail with zero amount",
+			&staking.ConsensusParameters{
+				MintAllowlist: map[staking.Address]bool{
+					addr1: true,
+				},
+			},
+			pk1,
+			&staking.Mint{
+				To: addr2,
+			},
+			staking.ErrInvalidArgument,
+		},
+		{
+			"should succeed",
+			&staking.ConsensusParameters{
+				MintAllowlist: map[staking.Address]bool{
+					addr1: true,
+				},
+			},
+			pk1,
+			&staking.Mint{
+				To:     addr2,
+				Amount: *quantity.NewFromUint64(10),
+			},
+			nil,
+		},
+		{
+			"should succeed minting to self",
+			&staking.ConsensusParameters{
+				MintAllowlist: map[staking.Address]bool{
+					addr1: true,
+				},
+			},
+			pk1,
+			&staking.Mint{
+				To:     addr1,
+				Amount: *quantity.NewFromUint64(25),
+			},
+			nil,
+		},
+	} {
+		err = stakeState.SetConsensusParameters(ctx, tc.params)
+		require.NoError(err, "setting staking consensus parameters should not error")
+
+		ctx.SetTxSigner(tc.txSigner)
+
+		var beforeBalance quantity.Quantity
+		if !tc.mint.To.IsReserved() {
+			var beforeAcct *staking.Account
+			beforeAcct, err = stakeState.Account(ctx, tc.mint.To)
+			require.NoError(err, "reading account state should not error")
+			beforeBalance = beforeAcct.General.Balance
+		}
+		beforeSupply, err := stakeState.TotalSupply(ctx)
+		require.NoError(err, "reading total supply should not error")
+
+		err = app.mint(ctx, stakeState, tc.mint)
+		require.Equal(tc.err, err, tc.msg)
+
+		afterSupply, err := stakeState.TotalSupply(ctx)
+		require.NoError(err, "reading total supply should not error")
+
+		expectedSupply := beforeSupply.Clone()
+		if tc.err == nil {
+			err = expectedSupply.Add(&tc.mint.Amount)
+			require.NoError(err, "computing expected total supply should not fail")
+		}
+		require.Equal(expectedSupply, afterSupply, "total supply should be correct after mint")
+
+		if tc.mint.To.IsReserved() {
+			continue
+		}
+		afterAcct, err := stakeState.Account(ctx, tc.mint.To)
+		require.NoError(err, "reading account state should not error")
+
+		expectedBalance := beforeBalance.Clone()
+		if tc.err == nil {
+			err = expectedBalance.Add(&tc.mint.Amount)
+			require.NoError(err, "computing expected balance should not fail")
+		}
+		require.Equal(*expectedBalance, afterAcct.General.Balance, "general balance should be correct after mint")
+	}
+}
+
+func TestRegisterAlias(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	err = stakeState.SetAccount(ctx, addr1, &staking.Account{
+		General: staking.GeneralAccount{Balance: *quantity.NewFromUint64(100)},
+	})
+	require.NoError(err, "SetAccount")
+	err = stakeState.SetAccount(ctx, addr2, &staking.Account{
+		General: staking.GeneralAccount{Balance: *quantity.NewFromUint64(1)},
+	})
+	require.NoError(err, "SetAccount")
+
+	enabledParams := &staking.ConsensusParameters{
+		AliasRegistrationFee:       *quantity.NewFromUint64(2),
+		MaxAliasRegistrationEpochs: 20,
+	}
+
+	for _, tc := range []struct {
+		msg        string
+		params     *staking.ConsensusParameters
+		txSigner   signature.PublicKey
+		reg        *staking.RegisterAlias
+		err        error
+		expiration epochtime.EpochTime
+	}{
+		{
+			"should fail when alias registration is disabled",
+			&staking.ConsensusParameters{},
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 5},
+			staking.ErrForbidden,
+			0,
+		},
+		{
+			"should fail with zero epochs",
+			enabledParams,
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange"},
+			staking.ErrInvalidArgument,
+			0,
+		},
+		{
+			"should fail with too many epochs",
+			enabledParams,
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 21},
+			staking.ErrInvalidArgument,
+			0,
+		},
+		{
+			"should fail with insufficient balance",
+			enabledParams,
+			pk2,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 5},
+			staking.ErrInsufficientBalance,
+			0,
+		},
+		{
+			"should succeed",
+			enabledParams,
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 5},
+			nil,
+			15,
+		},
+		{
+			"should fail if alias is registered to a different account",
+			&staking.ConsensusParameters{MaxAliasRegistrationEpochs: 20},
+			pk2,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 5},
+			staking.ErrAliasTaken,
+			15,
+		},
+		{
+			"should succeed renewing",
+			enabledParams,
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 10},
+			nil,
+			25,
+		},
+		{
+			"should fail renewing beyond the maximum registration period",
+			enabledParams,
+			pk1,
+			&staking.RegisterAlias{Alias: "exchange", Epochs: 10},
+			staking.ErrInvalidArgument,
+			25,
+		},
+	} {
+		err = stakeState.SetConsensusParameters(ctx, tc.params)
+		require.NoError(err, "setting staking consensus parameters should not error")
+
+		ctx.SetTxSigner(tc.txSigner)
+		signerAddr := staking.NewAddress(tc.txSigner)
+
+		beforeAcct, err := stakeState.Account(ctx, signerAddr)
+		require.NoError(err, "reading account state should not error")
+		beforeCommonPool, err := stakeState.CommonPool(ctx)
+		require.NoError(err, "reading common pool should not error")
+
+		err = app.registerAlias(ctx, stakeState, tc.reg)
+		require.Equal(tc.err, err, tc.msg)
+
+		info, err := stakeState.Alias(ctx, tc.reg.Alias)
+		if tc.expiration == 0 {
+			require.Equal(staking.ErrAliasNotFound, err, tc.msg)
+			continue
+		}
+		require.NoError(err, "reading alias should not error")
+		require.Equal(addr1, info.Address, tc.msg)
+		require.Equal(tc.expiration, info.Expiration, tc.msg)
+
+		afterAcct, err := stakeState.Account(ctx, signerAddr)
+		require.NoError(err, "reading account state should not error")
+		afterCommonPool, err := stakeState.CommonPool(ctx)
+		require.NoError(err, "reading common pool should not error")
+
+		fee := quantity.NewQuantity()
+		if tc.err == nil {
+			fee = tc.params.AliasRegistrationFee.Clone()
+			err = fee.Mul(quantity.NewFromUint64(uint64(tc.reg.Epochs)))
+			require.NoError(err, "computing expected fee should not fail")
+		}
+		expectedBalance := beforeAcct.General.Balance.Clone()
+		require.NoError(expectedBalance.Sub(fee), "computing expected balance should not fail")
+		require.Equal(*expectedBalance, afterAcct.General.Balance, "general balance should be correct after registration")
+		expectedCommonPool := beforeCommonPool.Clone()
+		require.NoError(expectedCommonPool.Add(fee), "computing expected common pool should not fail")
+		require.Equal(expectedCommonPool, afterCommonPool, "common pool should be correct after registration")
+	}
+
+	// Expired aliases should be removed.
+	expired, err := stakeState.ExpiredAliases(ctx, 24)
+	require.NoError(err, "ExpiredAliases")
+	require.Empty(expired, "alias should not expire before its expiration epoch")
+	expired, err = stakeState.ExpiredAliases(ctx, 25)
+	require.NoError(err, "ExpiredAliases")
+	require.Equal([]string{"exchange"}, expired, "alias should expire at its expiration epoch")
+
+	err = stakeState.RemoveAlias(ctx, "exchange")
+	require.NoError(err, "RemoveAlias")
+	_, err = stakeState.Alias(ctx, "exchange")
+	require.Equal(staking.ErrAliasNotFound, err, "removed alias should no longer be registered")
+	expired, err = stakeState.ExpiredAliases(ctx, 25)
+	require.NoError(err, "ExpiredAliases")
+	require.Empty(expired, "removed alias should be removed from the expiration queue")
+}
+
+func TestReclaimEscrowTranches(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	})
+	ctx := appState.NewContext(abciAPI.ContextDeliverTx, now)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	app := &stakingApplication{
+		state: appState,
+	}
+
+	pk := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr := staking.NewAddress(pk)
+	escrowPK := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	escrowAddr := staking.NewAddress(escrowPK)
+
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		DebondingInterval:        5,
+		MaxReclaimEscrowTranches: 3,
+	})
+	require.NoError(err, "setting staking consensus parameters should not error")
+
+	err = stakeState.SetAccount(ctx, escrowAddr, &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     *quantity.NewFromUint64(1000),
+				TotalShares: *quantity.NewFromUint64(1000),
+			},
+		},
+	})
+	require.NoError(err, "SetAccount")
+	err = stakeState.SetDelegation(ctx, addr, escrowAddr, &staking.Delegation{
+		Shares: *quantity.NewFromUint64(1000),
+	})
+	require.NoError(err, "SetDelegation")
+
+	ctx.SetTxSigner(pk)
+
+	err = app.reclaimEscrow(ctx, stakeState, &staking.ReclaimEscrow{
+		Account:  escrowAddr,
+		Shares:   *quantity.NewFromUint64(100),
+		Tranches: 4,
+	})
+	require.Equal(staking.ErrInvalidArgument, err, "reclaiming more than the maximum number of tranches should fail")
+
+	err = app.reclaimEscrow(ctx, stakeState, &staking.ReclaimEscrow{
+		Account:  escrowAddr,
+		Shares:   *quantity.NewFromUint64(2),
+		Tranches: 3,
+	})
+	require.Equal(staking.ErrInvalidArgument, err, "reclaiming less stake than the number of tranches should fail")
+
+	err = app.reclaimEscrow(ctx, stakeState, &staking.ReclaimEscrow{
+		Account:  escrowAddr,
+		Shares:   *quantity.NewFromUint64(100),
+		Tranches: 3,
+	})
+	require.NoError(err, "reclaiming escrow in tranches should work")
+
+	debs, err := stakeState.DebondingDelegationsFor(ctx, addr)
+	require.NoError(err, "DebondingDelegationsFor")
+	require.Len(debs[escrowAddr], 3, "there should be one debonding delegation per tranche")
+
+	var total quantity.Quantity
+	debondEndTimes := make(map[epochtime.EpochTime]quantity.Quantity)
+	for _, deb := range debs[escrowAddr] {
+		debondEndTimes[deb.DebondEndTime] = deb.Shares
+		require.NoError(total.Add(&deb.Shares), "adding debonding shares should not fail")
+	}
+	require.Equal(map[epochtime.EpochTime]quantity.Quantity{
+		15: *quantity.NewFromUint64(33),
+		16: *quantity.NewFromUint64(33),
+		17: *quantity.NewFromUint64(34),
+	}, debondEndTimes, "tranches should debond over consecutive epochs")
+	require.Equal(*quantity.NewFromUint64(100), total, "tranches should add up to the reclaimed shares")
+
+	escrow, err := stakeState.Account(ctx, escrowAddr)
+	require.NoError(err, "Account")
+	require.Equal(*quantity.NewFromUint64(900), escrow.Escrow.Active.Balance, "active balance should be reduced")
+	require.Equal(*quantity.NewFromUint64(100), escrow.Escrow.Debonding.Balance, "debonding balance should be increased")
+
+	// Each tranche should be released at its debond end epoch.
+	for epoch, numExpired := range map[epochtime.EpochTime]int{14: 0, 15: 1, 16: 2, 17: 3} {
+		expired, err := stakeState.ExpiredDebondingQueue(ctx, epoch)
+		require.NoError(err, "ExpiredDebondingQueue")
+		require.Len(expired, numExpired, "expired tranches at epoch %d", epoch)
+	}
+
+	// Tranches should be keyed by the account nonce and an explicit tranche index.
+	expired, err := stakeState.ExpiredDebondingQueue(ctx, 17)
+	require.NoError(err, "ExpiredDebondingQueue")
+	for i, e := range expired {
+		require.EqualValues(0, e.Seq, "sequence number should be the account nonce")
+		require.EqualValues(i, e.Tranche, "tranche index should be part of the key")
+		require.EqualValues(15+i, e.Delegation.DebondEndTime, "tranche should debond at the correct epoch")
+
+		deb, err := stakeState.DebondingDelegation(ctx, addr, escrowAddr, e.Seq, e.Tranche)
+		require.NoError(err, "DebondingDelegation")
+		require.Equal(e.Delegation, deb, "debonding delegation should be keyed by tranche")
+	}
+}
@@ -2,14 +2,25 @@
 package beacon
 
 import (
+	"bytes"
 	"context"
+	"errors"
 
+	"github.com/tendermint/tendermint/abci/types"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/beacon"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 )
 
 // ServiceClient is the beacon service client interface.
@@ -21,9 +32,14 @@ type ServiceClient interface {
 type serviceClient struct {
 	tmapi.BaseServiceClient
 
+	ctx    context.Context
 	logger *logging.Logger
 
-	querier *app.QueryFactory
+	backend  tmapi.Backend
+	identity *identity.Identity
+	querier  *app.QueryFactory
+
+	pendingEpochNotifier *pubsub.Broker
 }
 
 func (sc *serviceClient) GetBeacon(ctx context.Context, height int64) ([]byte, error) {
@@ -44,13 +60,141 @@ func (sc *serviceClient) StateToGenesis(ctx context.Context, height int64) (*api
 	return q.Genesis(ctx)
 }
 
+func (sc *serviceClient) WatchPendingEpochs(ctx context.Context) (<-chan *api.PendingEpochEvent, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.PendingEpochEvent)
+	sub := sc.pendingEpochNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
 // Implements api.ServiceClient.
 func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []tmpubsub.Query{app.QueryApp})
 }
 
+// Implements api.ServiceClient.
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *types.Event) error {
+	for _, pair := range ev.GetAttributes() {
+		switch {
+		case bytes.Equal(pair.GetKey(), app.KeyGenerated):
+			// Proof submission blocks until the transaction is included in a block.
+			go sc.submitVRFProof(height, pair.GetValue())
+		case bytes.Equal(pair.GetKey(), app.KeyPendingEpoch):
+			var ev api.PendingEpochEvent
+			if err := cbor.Unmarshal(pair.GetValue(), &ev); err != nil {
+				sc.logger.Error("worker: malformed pending epoch event",
+					"err", err,
+				)
+				continue
+			}
+
+			sc.pendingEpochNotifier.Broadcast(&ev)
+		}
+	}
+	return nil
+}
+
+// submitVRFProof submits a VRF proof for the beacon of the epoch following the one for which
+// the given beacon has been generated in case the VRF backend is used and the local node is an
+// eligible validator.
+func (sc *serviceClient) submitVRFProof(height int64, beacon []byte) {
+	if sc.identity == nil || sc.identity.VRFKey == nil {
+		return
+	}
+
+	q, err := sc.querier.QueryAt(sc.ctx, height)
+	if err != nil {
+		sc.logger.Error("failed to query beacon state",
+			"err", err,
+			"height", height,
+		)
+		return
+	}
+	genesis, err := q.Genesis(sc.ctx)
+	if err != nil {
+		sc.logger.Error("failed to query beacon consensus parameters",
+			"err", err,
+			"height", height,
+		)
+		return
+	}
+	if genesis.Parameters.BackendName() != api.BackendVRF {
+		return
+	}
+
+	// Skip stale beacons (e.g., while catching up).
+	epoch, err := sc.backend.EpochTime().GetEpoch(sc.ctx, height)
+	if err != nil {
+		sc.logger.Error("failed to query epoch",
+			"err", err,
+			"height", height,
+		)
+		return
+	}
+	latestEpoch, err := sc.backend.EpochTime().GetEpoch(sc.ctx, consensus.HeightLatest)
+	if err != nil {
+		sc.logger.Error("failed to query latest epoch",
+			"err", err,
+		)
+		return
+	}
+	if epoch != latestEpoch {
+		return
+	}
+
+	// Only registered validator nodes with a VRF key may contribute.
+	n, err := sc.backend.Registry().GetNode(sc.ctx, &registry.IDQuery{
+		ID:     sc.identity.NodeSigner.Public(),
+		Height: consensus.HeightLatest,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, registry.ErrNoSuchNode):
+		return
+	default:
+		sc.logger.Error("failed to query own node descriptor",
+			"err", err,
+		)
+		return
+	}
+	if !n.HasRoles(node.RoleValidator) || n.VRF == nil || !n.VRF.ID.Equal(sc.identity.VRFKey.Public()) {
+		return
+	}
+
+	pi, err := sc.identity.VRFKey.Prove(api.VRFAlpha(epoch+1, beacon))
+	if err != nil {
+		sc.logger.Error("failed to generate VRF proof",
+			"err", err,
+			"epoch", epoch+1,
+			logging.LogEvent, api.LogEventVRFProofFailed,
+		)
+		return
+	}
+
+	tx := api.NewVRFProveTx(0, nil, &api.VRFProve{
+		Epoch: epoch + 1,
+		Pi:    pi,
+	})
+	if err = consensus.SignAndSubmitTx(sc.ctx, sc.backend, sc.identity.NodeSigner, tx); err != nil {
+		sc.logger.Error("failed to submit VRF proof",
+			"err", err,
+			"epoch", epoch+1,
+			logging.LogEvent, api.LogEventVRFProofFailed,
+		)
+		return
+	}
+
+	sc.logger.Debug("submitted VRF proof",
+		"epoch", epoch+1,
+	)
+}
+
 // New constructs a new tendermint backed beacon Backend instance.
-func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
+//
+// In case an identity is given and the VRF beacon backend is used, the service will submit VRF
+// proofs on behalf of the node when it is a registered validator.
+func New(ctx context.Context, backend tmapi.Backend, identity *identity.Identity) (ServiceClient, error) {
 	// Initialize and register the tendermint service component.
 	a := app.New()
 	if err := backend.RegisterApplication(a); err != nil {
@@ -58,8 +202,13 @@ func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
 	}
 
 	sc := &serviceClient{
-		logger:  logging.GetLogger("beacon/tendermint"),
-		querier: a.QueryFactory().(*app.QueryFactory),
+		ctx:      ctx,
+		logger:   logging.GetLogger("beacon/tendermint"),
+		backend:  backend,
+		identity: identity,
+		querier:  a.QueryFactory().(*app.QueryFactory),
+
+		pendingEpochNotifier: pubsub.NewBroker(false),
 	}
 
 	return sc, nil
@@ -0,0 +1,17 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+// ConsensusAddressToTendermint converts a consensus address to the tendermint
+// peer address format (ID@host:port), as used for persistent peers and seeds.
+func ConsensusAddressToTendermint(addr *node.ConsensusAddress) string {
+	// Tendermint peer IDs need to be lowercase as the public key validation
+	// performs a case sensitive string comparison.
+	id := strings.ToLower(PublicKeyToTendermint(&addr.ID).Address().String())
+	return fmt.Sprintf("%s@%s", id, addr.Address.String())
+}
@@ -0,0 +1,21 @@
+package crypto
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+func TestConsensusAddressToTendermint(t *testing.T) {
+	var addr node.ConsensusAddress
+	addr.ID = signature.NewPublicKey("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr.Address.IP = net.ParseIP("192.168.0.1")
+	addr.Address.Port = 26656
+
+	peer := ConsensusAddressToTendermint(&addr)
+	require.Equal(t, "af9613760f72635fbdb44a5a0a63c39f12af30f9@192.168.0.1:26656", peer)
+}
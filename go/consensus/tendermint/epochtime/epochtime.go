@@ -31,6 +31,7 @@ type serviceClient struct {
 	interval     int64
 	lastNotified api.EpochTime
 	epoch        api.EpochTime
+	currentBlock int64
 	base         api.EpochTime
 }
 
@@ -58,6 +59,16 @@ func (sc *serviceClient) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	return height, nil
 }
 
+func (sc *serviceClient) GetNextEpochBlock(ctx context.Context, height int64) (int64, error) {
+	if height == 0 {
+		sc.RLock()
+		height = sc.currentBlock
+		sc.RUnlock()
+	}
+	// Epochs start at heights which are a multiple of the interval.
+	return (height/sc.interval + 1) * sc.interval, nil
+}
+
 func (sc *serviceClient) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	typedCh := make(chan api.EpochTime)
 	sub := sc.notifier.Subscribe()
@@ -98,6 +109,7 @@ func (sc *serviceClient) DeliverBlock(ctx context.Context, height int64) error {
 	defer sc.Unlock()
 
 	sc.epoch = epoch
+	sc.currentBlock = height
 
 	if sc.lastNotified != epoch {
 		sc.logger.Debug("epoch transition",
@@ -18,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/epochtime_mock"
@@ -97,6 +98,11 @@ func (sc *serviceClient) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	}
 }
 
+func (sc *serviceClient) GetNextEpochBlock(ctx context.Context, height int64) (int64, error) {
+	// Epoch transitions only happen when the epoch is explicitly set.
+	return -1, api.ErrTransitionNotScheduled
+}
+
 func (sc *serviceClient) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	typedCh := make(chan api.EpochTime)
 	sub := sc.notifier.Subscribe()
@@ -179,7 +185,7 @@ func (sc *serviceClient) DeliverBlock(ctx context.Context, height int64) error {
 }
 
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
 	for _, pair := range ev.GetAttributes() {
 		if bytes.Equal(pair.GetKey(), app.KeyEpoch) {
 			var epoch api.EpochTime
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -38,10 +39,12 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	cmservice "github.com/oasisprotocol/oasis-core/go/common/service"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
 	"github.com/oasisprotocol/oasis-core/go/consensus/metrics"
@@ -56,6 +59,7 @@ import (
 	tmepochtimemock "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/epochtime_mock"
 	tmkeymanager "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/keymanager"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/light"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/peers"
 	tmregistry "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/registry"
 	tmroothash "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/roothash"
 	tmscheduler "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/scheduler"
@@ -80,6 +84,8 @@ const (
 	CfgABCIPruneStrategy = "consensus.tendermint.abci.prune.strategy"
 	// CfgABCIPruneNumKept configures the amount of kept heights if pruning is enabled.
 	CfgABCIPruneNumKept = "consensus.tendermint.abci.prune.num_kept"
+	// CfgABCILogBlockStats enables logging of per-block resource usage statistics.
+	CfgABCILogBlockStats = "consensus.tendermint.abci.log_block_stats"
 
 	// CfgCheckpointerDisabled disables the ABCI state checkpointer.
 	CfgCheckpointerDisabled = "consensus.tendermint.checkpointer.disabled"
@@ -107,6 +113,13 @@ const (
 	// CfgDebugDisableCheckTx disables CheckTx.
 	CfgDebugDisableCheckTx = "consensus.tendermint.debug.disable_check_tx"
 
+	// CfgMempoolReservedHigh configures the fraction of mempool capacity reserved for high
+	// priority transactions.
+	CfgMempoolReservedHigh = "consensus.tendermint.mempool.reserved.high"
+	// CfgMempoolReservedNormal configures the fraction of mempool capacity reserved for normal
+	// priority transactions (in addition to the capacity reserved for high priority ones).
+	CfgMempoolReservedNormal = "consensus.tendermint.mempool.reserved.normal"
+
 	// CfgSupplementarySanityEnabled is the supplementary sanity enabled flag.
 	CfgSupplementarySanityEnabled = "consensus.tendermint.supplementarysanity.enabled"
 	// CfgSupplementarySanityInterval configures the supplementary sanity check interval.
@@ -123,6 +136,9 @@ const (
 	CfgConsensusStateSyncTrustHeight = "consensus.tendermint.state_sync.trust_height"
 	// CfgConsensusStateSyncTrustHash is the known trusted block header hash for the light client.
 	CfgConsensusStateSyncTrustHash = "consensus.tendermint.state_sync.trust_hash"
+	// CfgConsensusStateSyncDiscoveryTime is the time spent discovering checkpoints offered by
+	// peers before selecting one for restoration.
+	CfgConsensusStateSyncDiscoveryTime = "consensus.tendermint.state_sync.discovery_time"
 )
 
 const (
@@ -170,6 +186,7 @@ type fullService struct { // nolint: maligned
 	staking       stakingAPI.Backend
 	scheduler     schedulerAPI.Backend
 	submissionMgr consensusAPI.SubmissionManager
+	peerManager   *peers.Manager
 
 	serviceClients   []api.ServiceClient
 	serviceClientsWg sync.WaitGroup
@@ -178,6 +195,7 @@ type fullService struct { // nolint: maligned
 	genesisProvider          genesisAPI.Provider
 	identity                 *identity.Identity
 	dataDir                  string
+	commonStore              *persistent.CommonStore
 	isInitialized, isStarted bool
 	startedCh                chan struct{}
 	syncedCh                 chan struct{}
@@ -382,6 +400,7 @@ func (t *fullService) StateToGenesis(ctx context.Context, blockHeight int64) (*g
 	}
 
 	return &genesisAPI.Document{
+		Version:    genesisAPI.LatestDocumentVersion,
 		Height:     blockHeight,
 		ChainID:    genesisDoc.ChainID,
 		HaltEpoch:  genesisDoc.HaltEpoch,
@@ -446,12 +465,7 @@ func (t *fullService) SubmitTx(ctx context.Context, tx *transaction.SignedTransa
 		return v
 	case v := <-txSub.Out():
 		if result := v.Data().(tmtypes.EventDataTx).Result; !result.IsOK() {
-			err := errors.FromCode(result.GetCodespace(), result.GetCode())
-			if err == nil {
-				// Fallback to an ordinary error.
-				err = fmt.Errorf(result.GetLog())
-			}
-			return err
+			return errors.FromCodeWithMessage(result.GetCodespace(), result.GetCode(), result.GetLog())
 		}
 		return nil
 	case <-txSub.Cancelled():
@@ -483,12 +497,7 @@ func (t *fullService) broadcastTxRaw(data []byte) error {
 
 	rsp := <-ch
 	if result := rsp.GetCheckTx(); !result.IsOK() {
-		err := errors.FromCode(result.GetCodespace(), result.GetCode())
-		if err == nil {
-			// Fallback to an ordinary error.
-			err = fmt.Errorf(result.GetLog())
-		}
-		return err
+		return errors.FromCodeWithMessage(result.GetCodespace(), result.GetCode(), result.GetLog())
 	}
 
 	return nil
@@ -520,6 +529,33 @@ func (t *fullService) EstimateGas(ctx context.Context, req *consensusAPI.Estimat
 	return t.mux.EstimateGas(req.Signer, req.Transaction)
 }
 
+func (t *fullService) SimulateTx(ctx context.Context, req *consensusAPI.SimulateTxRequest) (*consensusAPI.SimulateTxResponse, error) {
+	if req.Transaction == nil {
+		return nil, fmt.Errorf("tendermint: missing transaction")
+	}
+
+	gasUsed, tmEvents, txErr := t.mux.SimulateTx(req.Signer, req.Transaction)
+
+	result := &results.Result{}
+	if txErr != nil {
+		result.Error = results.NewError(txErr)
+	}
+
+	// The transaction is simulated as if it was included in the next block as its first
+	// transaction.
+	height := t.mux.State().BlockHeight() + 1
+	events, err := eventsFromTendermint(cbor.Marshal(req.Transaction), height, 0, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	result.Events = events
+
+	return &consensusAPI.SimulateTxResponse{
+		GasUsed: gasUsed,
+		Result:  result,
+	}, nil
+}
+
 func (t *fullService) subscribe(subscriber string, query tmpubsub.Query) (tmtypes.Subscription, error) {
 	// Note: The tendermint documentation claims using SubscribeUnbuffered can
 	// freeze the server, however, the buffered Subscribe can drop events, and
@@ -584,6 +620,13 @@ func (t *fullService) SubmissionManager() consensusAPI.SubmissionManager {
 	return t.submissionMgr
 }
 
+func (t *fullService) PeerManager() consensusAPI.PeerManager {
+	if t.peerManager == nil {
+		return nil
+	}
+	return t.peerManager
+}
+
 func (t *fullService) EpochTime() epochtimeAPI.Backend {
 	return t.epochtime
 }
@@ -674,6 +717,50 @@ func (t *fullService) GetTransactions(ctx context.Context, height int64) ([][]by
 	return txs, nil
 }
 
+// eventsFromTendermint extracts the staking, registry and roothash events from the given
+// Tendermint events emitted by a transaction, in the order in which they were emitted.
+func eventsFromTendermint(
+	tx tmtypes.Tx,
+	height int64,
+	txIndex uint32,
+	tmEvents []tmabcitypes.Event,
+) ([]*results.Event, error) {
+	var events []*results.Event
+
+	base := consensusEvents.NewTxIndex(txIndex, 0)
+	stakingEvents, err := tmstaking.EventsFromTendermint(tx, height, base, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range stakingEvents {
+		events = append(events, &results.Event{Staking: e})
+	}
+
+	registryEvents, _, err := tmregistry.EventsFromTendermint(tx, height, base, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range registryEvents {
+		events = append(events, &results.Event{Registry: e})
+	}
+
+	roothashEvents, err := tmroothash.EventsFromTendermint(tx, height, base, tmEvents)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range roothashEvents {
+		events = append(events, &results.Event{RootHash: e})
+	}
+
+	// Restore the emission order across services. The sort is stable so events decoded from the
+	// same Tendermint event retain their relative order.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Index().Less(events[j].Index())
+	})
+
+	return events, nil
+}
+
 func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int64) (*consensusAPI.TransactionsWithResults, error) {
 	var txsWithResults consensusAPI.TransactionsWithResults
 
@@ -702,44 +789,16 @@ func (t *fullService) GetTransactionsWithResults(ctx context.Context, height int
 			},
 		}
 
-		// Transaction staking events.
-		stakingEvents, err := tmstaking.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
-		if err != nil {
-			return nil, err
-		}
-		for _, e := range stakingEvents {
-			result.Events = append(result.Events, &results.Event{Staking: e})
-		}
-
-		// Transaction registry events.
-		registryEvents, _, err := tmregistry.EventsFromTendermint(
+		// Transaction events.
+		if result.Events, err = eventsFromTendermint(
 			txsWithResults.Transactions[txIdx],
 			blk.Height,
+			uint32(txIdx),
 			rs.Events,
-		)
-		if err != nil {
+		); err != nil {
 			return nil, err
 		}
-		for _, e := range registryEvents {
-			result.Events = append(result.Events, &results.Event{Registry: e})
-		}
 
-		// Transaction roothash events.
-		roothashEvents, err := tmroothash.EventsFromTendermint(
-			txsWithResults.Transactions[txIdx],
-			blk.Height,
-			rs.Events,
-		)
-		if err != nil {
-			return nil, err
-		}
-		for _, e := range roothashEvents {
-			result.Events = append(result.Events, &results.Event{RootHash: e})
-		}
 		txsWithResults.Results = append(txsWithResults.Results, result)
 	}
 	return &txsWithResults, nil
@@ -823,6 +882,9 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 	consensusAddr := []byte(crypto.PublicKeyToTendermint(&consensusPk).Address())
 	status.IsValidator = vals.HasAddress(consensusAddr)
 
+	// State sync progress.
+	status.StateSync = t.mux.StateSyncStatus()
+
 	return status, nil
 }
 
@@ -849,6 +911,89 @@ func (t *fullService) WatchBlocks(ctx context.Context) (<-chan *consensusAPI.Blo
 	return mapCh, sub, nil
 }
 
+func (t *fullService) WatchBlocksFrom(ctx context.Context, height int64) (<-chan *consensusAPI.Block, pubsub.ClosableSubscription, error) {
+	if height < 0 {
+		return nil, nil, fmt.Errorf("tendermint: invalid height: %d", height)
+	}
+	if err := t.ensureStarted(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	// Subscribe to new blocks before determining the latest height so that no blocks are missed
+	// during the transition from replayed to new blocks.
+	ch, sub := t.WatchTendermintBlocks()
+	latest := t.mux.State().BlockHeight()
+
+	// Height zero means that the next height will be determined by the first new block.
+	next := height
+	if next == consensusAPI.HeightLatest {
+		next = latest
+	}
+	if next != 0 && next <= latest {
+		// Make sure that the starting height is still available.
+		if _, err := t.GetTendermintBlock(ctx, next); err != nil {
+			sub.Close()
+			return nil, nil, fmt.Errorf("%w: %s", consensusAPI.ErrVersionNotFound, err.Error())
+		}
+	}
+
+	mapCh := make(chan *consensusAPI.Block)
+	go func() {
+		defer close(mapCh)
+
+		for {
+			// In case we have caught up, wait for a new block.
+			if next == 0 || next > latest {
+				select {
+				case tmBlk, ok := <-ch:
+					if !ok {
+						return
+					}
+
+					latest = tmBlk.Height
+					if next == 0 {
+						next = latest
+					}
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			// Always fetch the block from storage, even if it has just been received, so that a
+			// slow consumer does not cause new blocks to pile up in the subscription.
+			tmBlk, err := t.GetTendermintBlock(ctx, next)
+			if err != nil {
+				t.Logger.Error("failed to fetch block for replay",
+					"err", err,
+					"height", next,
+				)
+				return
+			}
+			blk := api.NewBlock(tmBlk)
+
+		sendLoop:
+			for {
+				select {
+				case mapCh <- blk:
+					break sendLoop
+				case newBlk, ok := <-ch:
+					// Only keep track of the latest height while waiting for the consumer.
+					if !ok {
+						return
+					}
+					latest = newBlk.Height
+				case <-ctx.Done():
+					return
+				}
+			}
+			next++
+		}
+	}()
+
+	return mapCh, sub, nil
+}
+
 func (t *fullService) ensureStarted(ctx context.Context) error {
 	// Make sure that the Tendermint service has started so that we
 	// have the client interface available.
@@ -896,7 +1041,7 @@ func (t *fullService) initialize() error {
 	// Initialize the rest of backends.
 	var err error
 	var scBeacon tmbeacon.ServiceClient
-	if scBeacon, err = tmbeacon.New(t.ctx, t); err != nil {
+	if scBeacon, err = tmbeacon.New(t.ctx, t, t.identity); err != nil {
 		t.Logger.Error("initialize: failed to initialize beacon backend",
 			"err", err,
 		)
@@ -952,7 +1097,7 @@ func (t *fullService) initialize() error {
 	t.svcMgr.RegisterCleanupOnly(t.scheduler, "scheduler backend")
 
 	var scRootHash tmroothash.ServiceClient
-	if scRootHash, err = tmroothash.New(t.ctx, t.dataDir, t); err != nil {
+	if scRootHash, err = tmroothash.New(t.ctx, t.dataDir, t, t.commonStore); err != nil {
 		t.Logger.Error("roothash: failed to initialize roothash backend",
 			"err", err,
 		)
@@ -1094,6 +1239,11 @@ func (t *fullService) lazyInit() error {
 		DisableCheckpointer:       viper.GetBool(CfgCheckpointerDisabled),
 		CheckpointerCheckInterval: viper.GetDuration(CfgCheckpointerCheckInterval),
 		InitialHeight:             uint64(t.genesis.Height),
+		LogBlockStats:             viper.GetBool(CfgABCILogBlockStats),
+		MempoolReservation: abci.MempoolReservationConfig{
+			High:   viper.GetFloat64(CfgMempoolReservedHigh),
+			Normal: viper.GetFloat64(CfgMempoolReservedNormal),
+		},
 	}
 	t.mux, err = abci.NewApplicationServer(t.ctx, t.upgrader, appConfig)
 	if err != nil {
@@ -1172,6 +1322,7 @@ func (t *fullService) lazyInit() error {
 		tenderConfig.P2P.PrivatePeerIDs += "," + sentryUpstreamIDsStr
 		tenderConfig.P2P.UnconditionalPeerIDs += "," + sentryUpstreamIDsStr
 	}
+	t.peerManager = peers.New(strings.Split(tenderConfig.P2P.PersistentPeers, ","))
 
 	if !tenderConfig.P2P.PexReactor {
 		t.Logger.Info("pex reactor disabled",
@@ -1230,6 +1381,7 @@ func (t *fullService) lazyInit() error {
 		// Enable state sync in the configuration.
 		tenderConfig.StateSync.Enable = true
 		tenderConfig.StateSync.TrustHash = viper.GetString(CfgConsensusStateSyncTrustHash)
+		tenderConfig.StateSync.DiscoveryTime = viper.GetDuration(CfgConsensusStateSyncDiscoveryTime)
 
 		// Create new state sync state provider.
 		cfg := light.ClientConfig{
@@ -1294,6 +1446,10 @@ func (t *fullService) lazyInit() error {
 			// Sanity check for the above wrapDbProvider hack in case the DB provider changes.
 			return fmt.Errorf("tendermint: internal error: state database not set")
 		}
+		t.node.Switch().AddReactor(peers.ReactorName, t.peerManager)
+		if err = t.mux.SetMempool(t.node.Mempool(), tenderConfig.Mempool.Size, tenderConfig.Mempool.MaxTxsBytes); err != nil {
+			return fmt.Errorf("tendermint: failed to configure mempool: %w", err)
+		}
 		t.client = tmcli.New(t.node)
 		t.failMonitor = newFailMonitor(t.ctx, t.Logger, t.node.ConsensusState().Wait)
 
@@ -1430,6 +1586,7 @@ func New(
 	identity *identity.Identity,
 	upgrader upgradeAPI.Backend,
 	genesisProvider genesisAPI.Provider,
+	commonStore *persistent.CommonStore,
 ) (consensusAPI.Backend, error) {
 	// Retrieve the genesis document early so that it is possible to
 	// use it while initializing other things.
@@ -1456,6 +1613,7 @@ func New(
 		genesisProvider:       genesisProvider,
 		ctx:                   ctx,
 		dataDir:               dataDir,
+		commonStore:           commonStore,
 		startedCh:             make(chan struct{}),
 		syncedCh:              make(chan struct{}),
 	}
@@ -1475,6 +1633,7 @@ func New(
 func init() {
 	Flags.String(CfgABCIPruneStrategy, abci.PruneDefault, "ABCI state pruning strategy")
 	Flags.Uint64(CfgABCIPruneNumKept, 3600, "ABCI state versions kept (when applicable)")
+	Flags.Bool(CfgABCILogBlockStats, false, "Log per-block resource usage statistics")
 	Flags.Bool(CfgCheckpointerDisabled, false, "Disable the ABCI state checkpointer")
 	Flags.Duration(CfgCheckpointerCheckInterval, 1*time.Minute, "ABCI state checkpointer check interval")
 	Flags.StringSlice(CfgSentryUpstreamAddress, []string{}, "Tendermint nodes for which we act as sentry of the form ID@ip:port")
@@ -1484,6 +1643,8 @@ func init() {
 	Flags.Duration(CfgP2PPersistenPeersMaxDialPeriod, 0*time.Second, "Tendermint max timeout when redialing a persistent peer (default: unlimited)")
 	Flags.Uint64(CfgMinGasPrice, 0, "minimum gas price")
 	Flags.Bool(CfgDebugDisableCheckTx, false, "do not perform CheckTx on incoming transactions (UNSAFE)")
+	Flags.Float64(CfgMempoolReservedHigh, 0.1, "fraction of mempool capacity reserved for high priority transactions")
+	Flags.Float64(CfgMempoolReservedNormal, 0.2, "fraction of mempool capacity reserved for normal priority transactions")
 	Flags.Bool(CfgDebugUnsafeReplayRecoverCorruptedWAL, false, "Enable automatic recovery from corrupted WAL during replay (UNSAFE).")
 
 	Flags.Bool(CfgSupplementarySanityEnabled, false, "enable supplementary sanity checks (slows down consensus)")
@@ -1495,6 +1656,7 @@ func init() {
 	Flags.Duration(CfgConsensusStateSyncTrustPeriod, 24*time.Hour, "state sync: light client trust period")
 	Flags.Uint64(CfgConsensusStateSyncTrustHeight, 0, "state sync: light client trusted height")
 	Flags.String(CfgConsensusStateSyncTrustHash, "", "state sync: light client trusted consensus header hash")
+	Flags.Duration(CfgConsensusStateSyncDiscoveryTime, 15*time.Second, "state sync: time spent discovering checkpoints offered by peers")
 
 	_ = Flags.MarkHidden(CfgDebugDisableCheckTx)
 	_ = Flags.MarkHidden(CfgDebugUnsafeReplayRecoverCorruptedWAL)
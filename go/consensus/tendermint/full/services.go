@@ -10,6 +10,7 @@ import (
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmtypes "github.com/tendermint/tendermint/types"
 
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 )
 
@@ -153,16 +154,29 @@ func (t *fullService) serviceClientWorker(ctx context.Context, svc api.ServiceCl
 			var (
 				tx       tmtypes.Tx
 				tmEvents []tmabcitypes.Event
+				// eventIndex returns the position of the i-th event within the block.
+				eventIndex func(i int) consensusEvents.Index
 			)
 			switch {
 			case ev.Block != nil:
 				height = ev.Block.Header.Height
-				tmEvents = append([]tmabcitypes.Event{}, ev.Block.ResultBeginBlock.GetEvents()...)
+				beginEvents := ev.Block.ResultBeginBlock.GetEvents()
+				tmEvents = append([]tmabcitypes.Event{}, beginEvents...)
 				tmEvents = append(tmEvents, ev.Block.ResultEndBlock.GetEvents()...)
+				eventIndex = func(i int) consensusEvents.Index {
+					if i < len(beginEvents) {
+						return consensusEvents.NewBlockIndex(consensusEvents.PhaseBeginBlock, uint32(i))
+					}
+					return consensusEvents.NewBlockIndex(consensusEvents.PhaseEndBlock, uint32(i-len(beginEvents)))
+				}
 			case ev.Tx != nil:
 				height = ev.Tx.Height
 				tx = ev.Tx.Tx
 				tmEvents = ev.Tx.Result.Events
+				txIndex := ev.Tx.Index
+				eventIndex = func(i int) consensusEvents.Index {
+					return consensusEvents.NewTxIndex(txIndex, uint32(i))
+				}
 			default:
 				logger.Warn("unknown event",
 					"ev", fmt.Sprintf("%+v", ev),
@@ -188,7 +202,7 @@ func (t *fullService) serviceClientWorker(ctx context.Context, svc api.ServiceCl
 					continue
 				}
 
-				if err := svc.DeliverEvent(ctx, height, tx, &tmEvents[i]); err != nil {
+				if err := svc.DeliverEvent(ctx, height, tx, eventIndex(i), &tmEvents[i]); err != nil {
 					logger.Error("failed to deliver event to service client",
 						"err", err,
 					)
@@ -16,6 +16,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/keymanager"
 	"github.com/oasisprotocol/oasis-core/go/keymanager/api"
@@ -55,6 +56,15 @@ func (sc *serviceClient) GetStatuses(ctx context.Context, height int64) ([]*api.
 	return q.Statuses(ctx)
 }
 
+func (sc *serviceClient) GetAttestationEvidence(ctx context.Context, query *api.AttestationEvidenceQuery) ([]*api.AttestationEvidence, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.AttestationEvidence(ctx, query.ID, query.NodeID)
+}
+
 func (sc *serviceClient) WatchStatuses() (<-chan *api.Status, *pubsub.Subscription) {
 	sub := sc.notifier.Subscribe()
 	ch := make(chan *api.Status)
@@ -78,7 +88,7 @@ func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 }
 
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
 	for _, pair := range ev.GetAttributes() {
 		if bytes.Equal(pair.GetKey(), app.KeyStatusUpdate) {
 			var statuses []*api.Status
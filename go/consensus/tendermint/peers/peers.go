@@ -0,0 +1,414 @@
+// Package peers implements the tendermint consensus layer peer manager.
+package peers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tmp2p "github.com/tendermint/tendermint/p2p"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+const (
+	// ReactorName is the name under which the peer manager is registered with the p2p switch.
+	ReactorName = "OASIS_PEER_MANAGER"
+
+	// maxScore is the maximum (and initial) peer reputation score.
+	maxScore = 100
+	// errorPenalty is the score penalty applied each time a peer is disconnected due to an error.
+	errorPenalty = 10
+	// scoreRecoveryInterval is the interval after which a peer recovers a single score point.
+	scoreRecoveryInterval = 1 * time.Minute
+)
+
+var _ consensus.PeerManager = (*Manager)(nil)
+
+type peerState struct {
+	id      tmp2p.ID
+	address string
+	moniker string
+
+	peer           tmp2p.Peer
+	connectedSince time.Time
+
+	score            uint64
+	scoreUpdated     time.Time
+	errorDisconnects uint64
+	lastError        string
+
+	ban *consensus.PeerBan
+}
+
+// currentScore returns the peer reputation score at the given time, taking recovery into account.
+func (ps *peerState) currentScore(now time.Time) uint64 {
+	if ps.score >= maxScore {
+		return maxScore
+	}
+	recovered := uint64(now.Sub(ps.scoreUpdated) / scoreRecoveryInterval)
+	if ps.score+recovered >= maxScore {
+		return maxScore
+	}
+	return ps.score + recovered
+}
+
+// penalize applies the error penalty to the peer reputation score.
+func (ps *peerState) penalize(now time.Time, reason interface{}) {
+	score := ps.currentScore(now)
+	if score > errorPenalty {
+		score -= errorPenalty
+	} else {
+		score = 0
+	}
+	ps.score = score
+	ps.scoreUpdated = now
+	ps.errorDisconnects++
+	ps.lastError = fmt.Sprintf("%v", reason)
+}
+
+// isBanned checks whether the peer is banned at the given time, removing any expired bans.
+func (ps *peerState) isBanned(now time.Time) bool {
+	if ps.ban == nil {
+		return false
+	}
+	if !ps.ban.Until.IsZero() && !now.Before(ps.ban.Until) {
+		ps.ban = nil
+		return false
+	}
+	return true
+}
+
+// Manager is the tendermint consensus layer peer manager.
+//
+// The manager is registered with the p2p switch as a reactor without any channels so that it gets
+// notified about peers being added and removed.
+type Manager struct {
+	tmp2p.BaseReactor
+
+	sync.Mutex
+
+	sw         *tmp2p.Switch
+	peers      map[tmp2p.ID]*peerState
+	persistent []string
+
+	nowFn  func() time.Time
+	logger *logging.Logger
+}
+
+// SetSwitch implements p2p.Reactor.
+func (m *Manager) SetSwitch(sw *tmp2p.Switch) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.sw = sw
+	m.BaseReactor.SetSwitch(sw)
+}
+
+// AddPeer implements p2p.Reactor.
+func (m *Manager) AddPeer(peer tmp2p.Peer) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.nowFn()
+	ps := m.getOrCreateLocked(peer.ID(), now)
+	ps.peer = peer
+	ps.connectedSince = now
+	if addr := peer.SocketAddr(); addr != nil {
+		ps.address = addr.String()
+	}
+	if ni, ok := peer.NodeInfo().(tmp2p.DefaultNodeInfo); ok {
+		ps.moniker = ni.Moniker
+	}
+
+	if ps.isBanned(now) && m.sw != nil {
+		m.logger.Info("disconnecting from banned peer",
+			"peer_id", peer.ID(),
+			"address", ps.address,
+		)
+		// The switch calls AddPeer for all reactors in sequence so the peer must be stopped
+		// asynchronously.
+		go m.sw.StopPeerForError(peer, consensus.ErrPeerBanned)
+	}
+}
+
+// RemovePeer implements p2p.Reactor.
+func (m *Manager) RemovePeer(peer tmp2p.Peer, reason interface{}) {
+	m.Lock()
+	defer m.Unlock()
+
+	ps := m.peers[peer.ID()]
+	if ps == nil || ps.peer != peer {
+		return
+	}
+	ps.peer = nil
+	ps.connectedSince = time.Time{}
+
+	now := m.nowFn()
+	if reason != nil && !ps.isBanned(now) {
+		ps.penalize(now, reason)
+	}
+	m.pruneLocked(now)
+}
+
+func (m *Manager) getOrCreateLocked(id tmp2p.ID, now time.Time) *peerState {
+	ps := m.peers[id]
+	if ps == nil {
+		ps = &peerState{
+			id:           id,
+			score:        maxScore,
+			scoreUpdated: now,
+		}
+		m.peers[id] = ps
+	}
+	return ps
+}
+
+func (m *Manager) isPersistentLocked(id tmp2p.ID) bool {
+	for _, addr := range m.persistent {
+		if peerIDFromAddress(addr) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneLocked removes state for peers that are not connected, persistent or banned and that have
+// fully recovered their reputation score.
+func (m *Manager) pruneLocked(now time.Time) {
+	for id, ps := range m.peers {
+		if ps.peer != nil || ps.isBanned(now) || m.isPersistentLocked(id) {
+			continue
+		}
+		if ps.currentScore(now) < maxScore {
+			continue
+		}
+		delete(m.peers, id)
+	}
+}
+
+func (m *Manager) setPersistentLocked(addrs []string) error {
+	if m.sw == nil {
+		m.persistent = addrs
+		return nil
+	}
+	if err := m.sw.AddPersistentPeers(addrs); err != nil {
+		return fmt.Errorf("%w: %s", consensus.ErrInvalidPeer, err)
+	}
+	m.persistent = addrs
+	return nil
+}
+
+// GetPeers implements consensus.PeerManager.
+func (m *Manager) GetPeers(ctx context.Context) ([]*consensus.PeerStatus, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.nowFn()
+	m.pruneLocked(now)
+
+	// Make sure that persistent peers are always included.
+	for _, addr := range m.persistent {
+		ps := m.getOrCreateLocked(peerIDFromAddress(addr), now)
+		if ps.address == "" {
+			ps.address = addr
+		}
+	}
+
+	statuses := make([]*consensus.PeerStatus, 0, len(m.peers))
+	for id, ps := range m.peers {
+		status := &consensus.PeerStatus{
+			ID:               string(id),
+			Address:          ps.address,
+			Moniker:          ps.moniker,
+			Connected:        ps.peer != nil,
+			Persistent:       m.isPersistentLocked(id),
+			Score:            ps.currentScore(now),
+			ErrorDisconnects: ps.errorDisconnects,
+			LastError:        ps.lastError,
+		}
+		if ps.peer != nil {
+			connectedSince := ps.connectedSince
+			status.ConnectedSince = &connectedSince
+			status.Outbound = ps.peer.IsOutbound()
+		}
+		if ps.isBanned(now) {
+			ban := *ps.ban
+			status.Ban = &ban
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+
+	return statuses, nil
+}
+
+// BanPeer implements consensus.PeerManager.
+func (m *Manager) BanPeer(ctx context.Context, req *consensus.BanPeerRequest) error {
+	id, err := parsePeerID(req.ID)
+	if err != nil {
+		return err
+	}
+	if req.Duration < 0 {
+		return fmt.Errorf("%w: negative ban duration", consensus.ErrInvalidPeer)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.nowFn()
+	ps := m.getOrCreateLocked(id, now)
+	ps.ban = &consensus.PeerBan{
+		Reason: req.Reason,
+		Since:  now,
+	}
+	if req.Duration > 0 {
+		ps.ban.Until = now.Add(req.Duration)
+	}
+
+	// Banned peers must not be redialed.
+	if m.isPersistentLocked(id) {
+		var persistent []string
+		for _, addr := range m.persistent {
+			if peerIDFromAddress(addr) != id {
+				persistent = append(persistent, addr)
+			}
+		}
+		if err = m.setPersistentLocked(persistent); err != nil {
+			return err
+		}
+	}
+
+	if ps.peer != nil && m.sw != nil {
+		go m.sw.StopPeerForError(ps.peer, consensus.ErrPeerBanned)
+	}
+
+	m.logger.Info("peer banned",
+		"peer_id", id,
+		"reason", req.Reason,
+		"until", ps.ban.Until,
+	)
+
+	return nil
+}
+
+// UnbanPeer implements consensus.PeerManager.
+func (m *Manager) UnbanPeer(ctx context.Context, rawID string) error {
+	id, err := parsePeerID(rawID)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	ps := m.peers[id]
+	if ps == nil || !ps.isBanned(m.nowFn()) {
+		return consensus.ErrPeerNotFound
+	}
+	ps.ban = nil
+
+	m.logger.Info("peer unbanned",
+		"peer_id", id,
+	)
+
+	return nil
+}
+
+// UpdatePersistentPeers implements consensus.PeerManager.
+func (m *Manager) UpdatePersistentPeers(ctx context.Context, req *consensus.PersistentPeersUpdate) ([]string, error) {
+	remove := make(map[tmp2p.ID]bool)
+	for _, rawID := range req.Remove {
+		id, err := parsePeerID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		remove[id] = true
+	}
+
+	var add []string
+	for _, addr := range req.Add {
+		// Peer IDs need to be lowercase as the switch uses case sensitive comparisons.
+		addr = strings.ToLower(strings.TrimSpace(addr))
+		if _, err := parsePeerID(string(peerIDFromAddress(addr))); err != nil || !strings.Contains(addr, "@") {
+			return nil, fmt.Errorf("%w: malformed peer address: %s", consensus.ErrInvalidPeer, addr)
+		}
+		add = append(add, addr)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.nowFn()
+	for _, addr := range add {
+		if ps := m.peers[peerIDFromAddress(addr)]; ps != nil && ps.isBanned(now) {
+			return nil, fmt.Errorf("%w: %s", consensus.ErrPeerBanned, peerIDFromAddress(addr))
+		}
+	}
+
+	var persistent []string
+	seen := make(map[tmp2p.ID]bool)
+	for _, addr := range append(append([]string{}, m.persistent...), add...) {
+		id := peerIDFromAddress(addr)
+		if remove[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		persistent = append(persistent, addr)
+	}
+	if err := m.setPersistentLocked(persistent); err != nil {
+		return nil, err
+	}
+
+	if m.sw != nil && len(add) > 0 {
+		if err := m.sw.DialPeersAsync(add); err != nil {
+			m.logger.Warn("failed to dial new persistent peers",
+				"err", err,
+				"addrs", add,
+			)
+		}
+	}
+
+	m.logger.Info("persistent peers updated",
+		"added", add,
+		"removed", req.Remove,
+	)
+
+	return append([]string{}, m.persistent...), nil
+}
+
+// parsePeerID parses and validates a tendermint peer identifier.
+func parsePeerID(raw string) (tmp2p.ID, error) {
+	id := strings.ToLower(strings.TrimSpace(raw))
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != tmp2p.IDByteLength {
+		return "", fmt.Errorf("%w: malformed peer ID: %s", consensus.ErrInvalidPeer, raw)
+	}
+	return tmp2p.ID(id), nil
+}
+
+// peerIDFromAddress extracts the peer identifier from an address in the ID@host:port form.
+func peerIDFromAddress(addr string) tmp2p.ID {
+	return tmp2p.ID(strings.ToLower(strings.SplitN(addr, "@", 2)[0]))
+}
+
+// New creates a new peer manager with the given initial set of persistent peers.
+func New(persistentPeers []string) *Manager {
+	m := &Manager{
+		peers:  make(map[tmp2p.ID]*peerState),
+		nowFn:  time.Now,
+		logger: logging.GetLogger("consensus/tendermint/peers"),
+	}
+	for _, addr := range persistentPeers {
+		if addr = strings.ToLower(strings.TrimSpace(addr)); addr != "" {
+			m.persistent = append(m.persistent, addr)
+		}
+	}
+	m.BaseReactor = *tmp2p.NewBaseReactor(ReactorName, m)
+	return m
+}
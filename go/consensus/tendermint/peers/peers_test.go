@@ -0,0 +1,141 @@
+package peers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmp2pmock "github.com/tendermint/tendermint/p2p/mock"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+func TestPeerScore(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1600000000, 0)
+	m := New(nil)
+	m.nowFn = func() time.Time { return now }
+
+	peer := tmp2pmock.NewPeer(net.IPv4(127, 0, 0, 1))
+	m.AddPeer(peer)
+
+	peers, err := m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 1)
+	require.EqualValues(peer.ID(), peers[0].ID)
+	require.True(peers[0].Connected, "peer should be connected")
+	require.EqualValues(maxScore, peers[0].Score)
+
+	// Error disconnects should reduce the score.
+	m.RemovePeer(peer, fmt.Errorf("misbehaving"))
+	m.AddPeer(peer)
+	m.RemovePeer(peer, fmt.Errorf("misbehaving again"))
+
+	peers, err = m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 1)
+	require.False(peers[0].Connected, "peer should not be connected")
+	require.EqualValues(maxScore-2*errorPenalty, peers[0].Score)
+	require.EqualValues(2, peers[0].ErrorDisconnects)
+	require.Equal("misbehaving again", peers[0].LastError)
+
+	// The score should recover over time and fully recovered idle peers should be pruned.
+	now = now.Add(5 * scoreRecoveryInterval)
+	peers, err = m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 1)
+	require.EqualValues(maxScore-2*errorPenalty+5, peers[0].Score)
+
+	now = now.Add(2 * errorPenalty * scoreRecoveryInterval)
+	peers, err = m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 0, "fully recovered idle peers should be pruned")
+
+	// Graceful disconnects should not affect the score.
+	m.AddPeer(peer)
+	m.RemovePeer(peer, nil)
+	peers, err = m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 0)
+}
+
+func TestPeerBan(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1600000000, 0)
+	peer := tmp2pmock.NewPeer(net.IPv4(127, 0, 0, 1))
+	other := tmp2pmock.NewPeer(net.IPv4(127, 0, 0, 2))
+	m := New([]string{
+		string(peer.ID()) + "@127.0.0.1:26656",
+		string(other.ID()) + "@127.0.0.2:26656",
+	})
+	m.nowFn = func() time.Time { return now }
+
+	err := m.BanPeer(context.Background(), &consensus.BanPeerRequest{ID: "invalid"})
+	require.Error(err, "BanPeer should fail with a malformed peer ID")
+	require.True(errors.Is(err, consensus.ErrInvalidPeer))
+
+	err = m.UnbanPeer(context.Background(), string(peer.ID()))
+	require.Error(err, "UnbanPeer should fail for a peer that is not banned")
+	require.True(errors.Is(err, consensus.ErrPeerNotFound))
+
+	err = m.BanPeer(context.Background(), &consensus.BanPeerRequest{
+		ID:       string(peer.ID()),
+		Reason:   "testing",
+		Duration: time.Hour,
+	})
+	require.NoError(err, "BanPeer")
+
+	peers, err := m.GetPeers(context.Background())
+	require.NoError(err, "GetPeers")
+	require.Len(peers, 2)
+	for _, ps := range peers {
+		switch ps.ID {
+		case string(peer.ID()):
+			require.NotNil(ps.Ban, "peer should be banned")
+			require.Equal("testing", ps.Ban.Reason)
+			require.Equal(now.Add(time.Hour), ps.Ban.Until)
+			require.False(ps.Persistent, "banned peer should be removed from persistent peers")
+		case string(other.ID()):
+			require.Nil(ps.Ban, "other peer should not be banned")
+			require.True(ps.Persistent, "other peer should remain persistent")
+		}
+	}
+
+	// Banned peers must not be added as persistent peers.
+	_, err = m.UpdatePersistentPeers(context.Background(), &consensus.PersistentPeersUpdate{
+		Add: []string{string(peer.ID()) + "@127.0.0.1:26656"},
+	})
+	require.Error(err, "UpdatePersistentPeers should fail for a banned peer")
+	require.True(errors.Is(err, consensus.ErrPeerBanned))
+
+	// Bans should expire.
+	now = now.Add(time.Hour)
+	err = m.UnbanPeer(context.Background(), string(peer.ID()))
+	require.Error(err, "UnbanPeer should fail for an expired ban")
+
+	// Permanent bans can be lifted.
+	err = m.BanPeer(context.Background(), &consensus.BanPeerRequest{ID: string(peer.ID())})
+	require.NoError(err, "BanPeer")
+	now = now.Add(24 * time.Hour)
+	err = m.UnbanPeer(context.Background(), string(peer.ID()))
+	require.NoError(err, "UnbanPeer")
+
+	persistent, err := m.UpdatePersistentPeers(context.Background(), &consensus.PersistentPeersUpdate{
+		Add:    []string{string(peer.ID()) + "@127.0.0.1:26656"},
+		Remove: []string{string(other.ID())},
+	})
+	require.NoError(err, "UpdatePersistentPeers")
+	require.Equal([]string{string(peer.ID()) + "@127.0.0.1:26656"}, persistent)
+
+	_, err = m.UpdatePersistentPeers(context.Background(), &consensus.PersistentPeersUpdate{
+		Add: []string{"127.0.0.1:26656"},
+	})
+	require.Error(err, "UpdatePersistentPeers should fail with a malformed address")
+	require.True(errors.Is(err, consensus.ErrInvalidPeer))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry"
 	"github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -39,11 +40,14 @@ type serviceClient struct {
 
 	backend tmapi.Backend
 	querier *app.QueryFactory
+	tracker *tmapi.EventTracker
 
 	entityNotifier   *pubsub.Broker
 	nodeNotifier     *pubsub.Broker
 	nodeListNotifier *pubsub.Broker
 	runtimeNotifier  *pubsub.Broker
+
+	deprecationNotifier *pubsub.Broker
 }
 
 // NodeListEpochInternalEvent is the per-epoch node list event.
@@ -99,6 +103,19 @@ func (sc *serviceClient) GetNodeStatus(ctx context.Context, query *api.IDQuery)
 	return q.NodeStatus(ctx, query.ID)
 }
 
+func (sc *serviceClient) ValidateNode(ctx context.Context, query *api.ValidateNodeQuery) (*api.NodeValidationResult, error) {
+	if query.Node == nil {
+		return nil, fmt.Errorf("%w: missing node descriptor", api.ErrInvalidArgument)
+	}
+
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ValidateNode(ctx, query.Node)
+}
+
 func (sc *serviceClient) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -108,6 +125,28 @@ func (sc *serviceClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return q.Nodes(ctx)
 }
 
+func (sc *serviceClient) GetFreshNodes(ctx context.Context, query *api.FreshNodesQuery) ([]*node.Node, error) {
+	if query.Window < 1 {
+		return nil, fmt.Errorf("%w: freshness window must be positive", api.ErrInvalidArgument)
+	}
+
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.FreshNodes(ctx, query.Window)
+}
+
+func (sc *serviceClient) GetStaleNodes(ctx context.Context, query *api.StaleNodesQuery) (*api.StaleNodes, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.StaleNodes(ctx, query.Epochs)
+}
+
 func (sc *serviceClient) GetNodeByConsensusAddress(ctx context.Context, query *api.ConsensusAddressQuery) (*node.Node, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -133,6 +172,14 @@ func (sc *serviceClient) WatchNodeList(ctx context.Context) (<-chan *api.NodeLis
 	return typedCh, sub, nil
 }
 
+func (sc *serviceClient) WatchDeprecationWarnings(ctx context.Context) (<-chan *api.DeprecationWarningEvent, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.DeprecationWarningEvent)
+	sub := sc.deprecationNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
 func (sc *serviceClient) GetRuntime(ctx context.Context, query *api.NamespaceQuery) (*api.Runtime, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -170,7 +217,68 @@ func (sc *serviceClient) StateToGenesis(ctx context.Context, height int64) (*api
 	return q.Genesis(ctx)
 }
 
+func (sc *serviceClient) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
+	q, err := sc.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ConsensusParameters(ctx)
+}
+
 func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Event, error) {
+	return sc.eventsAt(ctx, height)
+}
+
+func (sc *serviceClient) GetEventsRange(ctx context.Context, query *api.EventsRangeQuery) (*api.EventsRangePage, error) {
+	if query.FromHeight <= 0 || query.ToHeight < query.FromHeight {
+		return nil, fmt.Errorf("%w: invalid height range", api.ErrInvalidArgument)
+	}
+	if query.ToHeight-query.FromHeight >= api.MaxEventsRangeQueryRange {
+		return nil, fmt.Errorf("%w: height range too large (max: %d)", api.ErrInvalidArgument, api.MaxEventsRangeQueryRange)
+	}
+	limit := query.Limit
+	if limit == 0 || limit > api.MaxEventsRangePageSize {
+		limit = api.MaxEventsRangePageSize
+	}
+
+	fromHeight, skip := query.FromHeight, uint64(0)
+	if query.Cursor != nil {
+		if query.Cursor.Height < query.FromHeight || query.Cursor.Height > query.ToHeight {
+			return nil, fmt.Errorf("%w: cursor outside of height range", api.ErrInvalidArgument)
+		}
+		fromHeight, skip = query.Cursor.Height, query.Cursor.Index
+	}
+
+	page := &api.EventsRangePage{}
+	for height := fromHeight; height <= query.ToHeight; height++ {
+		evs, err := sc.eventsAt(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := skip; idx < uint64(len(evs)); idx++ {
+			if !query.Matches(evs[idx]) {
+				continue
+			}
+			if uint64(len(page.Events)) == limit {
+				// There are more matching events, return a cursor pointing to the next one.
+				page.Next = &api.EventsCursor{Height: height, Index: idx}
+				return page, nil
+			}
+			page.Events = append(page.Events, evs[idx])
+		}
+		skip = 0
+	}
+
+	return page, nil
+}
+
+// eventsAt returns all registry events emitted at the given height.
+//
+// As the block transactions are only needed to derive transaction hashes, they are only fetched
+// in case any of the transaction results actually contain registry events.
+func (sc *serviceClient) eventsAt(ctx context.Context, height int64) ([]*api.Event, error) {
 	// Get block results at given height.
 	var results *tmrpctypes.ResultBlockResults
 	results, err := sc.backend.GetBlockResults(ctx, height)
@@ -181,53 +289,74 @@ func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 		)
 		return nil, err
 	}
-	// Get transactions at given height.
-	txns, err := sc.backend.GetTransactions(ctx, height)
-	if err != nil {
-		sc.logger.Error("failed to get tendermint transactions",
-			"err", err,
-			"height", height,
-		)
-		return nil, err
-	}
 
+	// Decode events in the order in which they were emitted.
 	var events []*api.Event
-	// Decode events from block results.
-	blockEvs, _, err := EventsFromTendermint(nil, results.Height, results.BeginBlockEvents)
+	blockEvs, _, err := EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseBeginBlock, 0), results.BeginBlockEvents)
 	if err != nil {
 		return nil, err
 	}
 	events = append(events, blockEvs...)
 
-	blockEvs, _, err = EventsFromTendermint(nil, results.Height, results.EndBlockEvents)
-	if err != nil {
-		return nil, err
-	}
-	events = append(events, blockEvs...)
-
-	// Decode events from transaction results.
+	var txns [][]byte
 	for txIdx, txResult := range results.TxsResults {
+		if !hasRegistryEvents(txResult.Events) {
+			continue
+		}
+
+		// Get transactions at given height.
+		if txns == nil {
+			txns, err = sc.backend.GetTransactions(ctx, height)
+			if err != nil {
+				sc.logger.Error("failed to get tendermint transactions",
+					"err", err,
+					"height", height,
+				)
+				return nil, err
+			}
+		}
+
 		// The order of transactions in txns and results.TxsResults is
 		// supposed to match, so the same index in both slices refers to the
 		// same transaction.
-		txEvs, _, txErr := EventsFromTendermint(txns[txIdx], results.Height, txResult.Events)
+		txEvs, _, txErr := EventsFromTendermint(txns[txIdx], results.Height, consensusEvents.NewTxIndex(uint32(txIdx), 0), txResult.Events)
 		if txErr != nil {
 			return nil, txErr
 		}
 		events = append(events, txEvs...)
 	}
 
+	blockEvs, _, err = EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseEndBlock, 0), results.EndBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, blockEvs...)
+
 	return events, nil
 }
 
+func hasRegistryEvents(tmEvents []tmabcitypes.Event) bool {
+	for _, tmEv := range tmEvents {
+		if tmEv.GetType() == app.EventType {
+			return true
+		}
+	}
+	return false
+}
+
 // Implements api.ServiceClient.
 func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []tmpubsub.Query{app.QueryApp})
 }
 
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
-	events, nodeListEvents, err := EventsFromTendermint(tx, height, []tmabcitypes.Event{*ev})
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
+	// Ignore events that have already been delivered.
+	if !sc.tracker.Observe(height, idx) {
+		return nil
+	}
+
+	events, nodeListEvents, err := EventsFromTendermint(tx, height, idx, []tmabcitypes.Event{*ev})
 	if err != nil {
 		return fmt.Errorf("scheduler: failed to process tendermint events: %w", err)
 	}
@@ -256,6 +385,9 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmty
 		if ev.RuntimeEvent != nil {
 			sc.runtimeNotifier.Broadcast(ev.RuntimeEvent.Runtime)
 		}
+		if ev.DeprecationWarningEvent != nil {
+			sc.deprecationNotifier.Broadcast(ev.DeprecationWarningEvent)
+		}
 	}
 
 	return nil
@@ -265,6 +397,7 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmty
 func EventsFromTendermint(
 	tx tmtypes.Tx,
 	height int64,
+	base consensusEvents.Index,
 	tmEvents []tmabcitypes.Event,
 ) ([]*api.Event, []*NodeListEpochInternalEvent, error) {
 	var txHash hash.Hash
@@ -278,11 +411,12 @@ func EventsFromTendermint(
 	var events []*api.Event
 	var nodeListEvents []*NodeListEpochInternalEvent
 	var errs error
-	for _, tmEv := range tmEvents {
+	for i, tmEv := range tmEvents {
 		// Ignore events that don't relate to the registry app.
 		if tmEv.GetType() != app.EventType {
 			continue
 		}
+		idx := base.Offset(i)
 
 		for _, pair := range tmEv.GetAttributes() {
 			key := pair.GetKey()
@@ -306,7 +440,7 @@ func EventsFromTendermint(
 						Node:           node,
 						IsRegistration: false,
 					}
-					events = append(events, &api.Event{Height: height, TxHash: txHash, NodeEvent: ne})
+					events = append(events, &api.Event{Height: height, TxHash: txHash, Index: idx, NodeEvent: ne})
 				}
 			case bytes.Equal(key, app.KeyRuntimeRegistered):
 				// Runtime registered event.
@@ -319,6 +453,7 @@ func EventsFromTendermint(
 				evt := &api.Event{
 					Height:       height,
 					TxHash:       txHash,
+					Index:        idx,
 					RuntimeEvent: &api.RuntimeEvent{Runtime: &rt},
 				}
 				events = append(events, evt)
@@ -334,7 +469,7 @@ func EventsFromTendermint(
 					Entity:         &ent,
 					IsRegistration: true,
 				}
-				events = append(events, &api.Event{Height: height, TxHash: txHash, EntityEvent: eev})
+				events = append(events, &api.Event{Height: height, TxHash: txHash, Index: idx, EntityEvent: eev})
 			case bytes.Equal(key, app.KeyEntityDeregistered):
 				// Entity deregistered event.
 				var dereg app.EntityDeregistration
@@ -347,7 +482,7 @@ func EventsFromTendermint(
 					Entity:         &dereg.Entity,
 					IsRegistration: false,
 				}
-				events = append(events, &api.Event{Height: height, TxHash: txHash, EntityEvent: eev})
+				events = append(events, &api.Event{Height: height, TxHash: txHash, Index: idx, EntityEvent: eev})
 			case bytes.Equal(key, app.KeyNodeRegistered):
 				// Node registered event.
 				var n node.Node
@@ -360,7 +495,7 @@ func EventsFromTendermint(
 					Node:           &n,
 					IsRegistration: true,
 				}
-				events = append(events, &api.Event{Height: height, TxHash: txHash, NodeEvent: nev})
+				events = append(events, &api.Event{Height: height, TxHash: txHash, Index: idx, NodeEvent: nev})
 			case bytes.Equal(key, app.KeyNodeUnfrozen):
 				// Node unfrozen event.
 				var nid signature.PublicKey
@@ -371,11 +506,58 @@ func EventsFromTendermint(
 				evt := &api.Event{
 					Height: height,
 					TxHash: txHash,
+					Index:  idx,
 					NodeUnfrozenEvent: &api.NodeUnfrozenEvent{
 						NodeID: nid,
 					},
 				}
 				events = append(events, evt)
+			case bytes.Equal(key, app.KeyNodeSuspended):
+				// Node suspended event.
+				var nid signature.PublicKey
+				if err := cbor.Unmarshal(val, &nid); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("registry: corrupt NodeSuspended event: %w", err))
+					continue
+				}
+				evt := &api.Event{
+					Height: height,
+					TxHash: txHash,
+					Index:  idx,
+					NodeSuspendedEvent: &api.NodeSuspendedEvent{
+						NodeID: nid,
+					},
+				}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeyNodeResumed):
+				// Node resumed event.
+				var nid signature.PublicKey
+				if err := cbor.Unmarshal(val, &nid); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("registry: corrupt NodeResumed event: %w", err))
+					continue
+				}
+				evt := &api.Event{
+					Height: height,
+					TxHash: txHash,
+					Index:  idx,
+					NodeResumedEvent: &api.NodeResumedEvent{
+						NodeID: nid,
+					},
+				}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeyDeprecationWarning):
+				// Deprecation warning event.
+				var dwe api.DeprecationWarningEvent
+				if err := cbor.Unmarshal(val, &dwe); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("registry: corrupt DeprecationWarning event: %w", err))
+					continue
+				}
+				evt := &api.Event{
+					Height:                  height,
+					TxHash:                  txHash,
+					Index:                   idx,
+					DeprecationWarningEvent: &dwe,
+				}
+				events = append(events, evt)
 			}
 		}
 	}
@@ -410,11 +592,13 @@ func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
 	}
 
 	sc := &serviceClient{
-		logger:         logging.GetLogger("registry/tendermint"),
-		backend:        backend,
-		querier:        a.QueryFactory().(*app.QueryFactory),
-		entityNotifier: pubsub.NewBroker(false),
-		nodeNotifier:   pubsub.NewBroker(false),
+		logger:              logging.GetLogger("registry/tendermint"),
+		backend:             backend,
+		querier:             a.QueryFactory().(*app.QueryFactory),
+		tracker:             tmapi.NewEventTracker(tmapi.DefaultEventTrackerWindow),
+		entityNotifier:      pubsub.NewBroker(false),
+		nodeNotifier:        pubsub.NewBroker(false),
+		deprecationNotifier: pubsub.NewBroker(false),
 	}
 	sc.nodeListNotifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
 		wr := ch.In()
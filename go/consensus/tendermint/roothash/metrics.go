@@ -0,0 +1,66 @@
+package roothash
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+)
+
+var (
+	indexedHeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_tendermint_roothash_indexed_height",
+			Help: "Last consensus height at which a runtime block was indexed.",
+		},
+		[]string{"runtime"},
+	)
+	indexedHeightLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_tendermint_roothash_indexed_height_lag",
+			Help: "Number of consensus heights by which runtime block indexing is behind consensus.",
+		},
+		[]string{"runtime"},
+	)
+	reindexDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_tendermint_roothash_reindex_duration",
+			Help: "Time spent reindexing runtime blocks (seconds).",
+		},
+		[]string{"runtime"},
+	)
+	finalizedEventsProcessed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_tendermint_roothash_finalized_events",
+			Help: "Number of processed finalized events.",
+		},
+		[]string{"runtime"},
+	)
+	finalizedEventProcessingDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_tendermint_roothash_finalized_event_processing_duration",
+			Help: "Time spent processing a finalized event (seconds).",
+		},
+		[]string{"runtime"},
+	)
+	roothashCollectors = []prometheus.Collector{
+		indexedHeight,
+		indexedHeightLag,
+		reindexDuration,
+		finalizedEventsProcessed,
+		finalizedEventProcessingDuration,
+	}
+
+	metricsOnce sync.Once
+)
+
+func runtimeLabels(runtimeID common.Namespace) prometheus.Labels {
+	return prometheus.Labels{"runtime": runtimeID.String()}
+}
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(roothashCollectors...)
+	})
+}
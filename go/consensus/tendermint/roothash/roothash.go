@@ -6,7 +6,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/eapache/channels"
 	"github.com/hashicorp/go-multierror"
@@ -19,8 +21,11 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/roothash"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api"
@@ -28,7 +33,16 @@ import (
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 )
 
-const crashPointBlockBeforeIndex = "roothash.before_index"
+const (
+	crashPointBlockBeforeIndex = "roothash.before_index"
+
+	// storeBucketName is the name of the persistent service store bucket.
+	storeBucketName = "tendermint.roothash"
+)
+
+// trackedRuntimesKey is the persistent store key under which the set of
+// tracked runtimes is stored.
+var trackedRuntimesKey = []byte("tracked_runtimes")
 
 // ServiceClient is the roothash service client interface.
 type ServiceClient interface {
@@ -49,14 +63,29 @@ type runtimeBrokers struct {
 type trackedRuntime struct {
 	runtimeID common.Namespace
 
-	height       int64
+	watermark    tmapi.HeightWatermark
 	blockHistory api.BlockHistory
-	reindexDone  bool
+
+	// restoredHeight is the last processed height restored from the persistent store. Once a
+	// block history is attached, reindexing resumes from this height.
+	restoredHeight int64
 }
 
 type cmdTrackRuntime struct {
 	runtimeID    common.Namespace
 	blockHistory api.BlockHistory
+
+	// lastHeight is the last processed height of a runtime restored from
+	// the persistent store.
+	lastHeight int64
+}
+
+// persistedTrackedRuntime is the persisted state of a tracked runtime.
+type persistedTrackedRuntime struct {
+	// RuntimeID is the identifier of the tracked runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Height is the last consensus height processed for the runtime.
+	Height int64 `json:"height"`
 }
 
 type serviceClient struct {
@@ -68,6 +97,8 @@ type serviceClient struct {
 
 	backend tmapi.Backend
 	querier *app.QueryFactory
+	store   *persistent.ServiceStore
+	tracker *tmapi.EventTracker
 
 	allBlockNotifier *pubsub.Broker
 	runtimeNotifiers map[common.Namespace]*runtimeBrokers
@@ -217,6 +248,54 @@ func (sc *serviceClient) StateToGenesis(ctx context.Context, height int64) (*api
 	return q.Genesis(ctx)
 }
 
+func (sc *serviceClient) GetStragglerCounters(
+	ctx context.Context,
+	query *api.StragglerCountersQuery,
+) (map[signature.PublicKey]uint64, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.StragglerCounters(ctx, query.RuntimeID, query.Epoch)
+}
+
+func (sc *serviceClient) GetLivenessSummary(
+	ctx context.Context,
+	query *api.LivenessSummaryQuery,
+) (*api.LivenessSummary, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.LivenessSummary(ctx, query.RuntimeID, query.Epoch)
+}
+
+func (sc *serviceClient) GetExecutorParticipation(
+	ctx context.Context,
+	query *api.ExecutorParticipationQuery,
+) (map[signature.PublicKey]*api.ExecutorParticipation, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ExecutorParticipation(ctx, query.RuntimeID, query.Epoch)
+}
+
+func (sc *serviceClient) GetStakingEventSubscription(
+	ctx context.Context,
+	request *api.RuntimeRequest,
+) (*api.StakingEventSubscription, error) {
+	q, err := sc.querier.QueryAt(ctx, request.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.StakingEventSubscription(ctx, request.RuntimeID)
+}
+
 func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Event, error) {
 	// Get block results at given height.
 	var results *tmrpctypes.ResultBlockResults
@@ -239,32 +318,31 @@ func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 		return nil, err
 	}
 
+	// Decode events in the order in which they were emitted.
 	var events []*api.Event
-	// Decode events from block results.
-	blockEvs, err := EventsFromTendermint(nil, results.Height, results.BeginBlockEvents)
-	if err != nil {
-		return nil, err
-	}
-	events = append(events, blockEvs...)
-
-	blockEvs, err = EventsFromTendermint(nil, results.Height, results.EndBlockEvents)
+	blockEvs, err := EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseBeginBlock, 0), results.BeginBlockEvents)
 	if err != nil {
 		return nil, err
 	}
 	events = append(events, blockEvs...)
 
-	// Decode events from transaction results.
 	for txIdx, txResult := range results.TxsResults {
 		// The order of transactions in txns and results.TxsResults is
 		// supposed to match, so the same index in both slices refers to the
 		// same transaction.
-		evs, txErr := EventsFromTendermint(txns[txIdx], results.Height, txResult.Events)
+		evs, txErr := EventsFromTendermint(txns[txIdx], results.Height, consensusEvents.NewTxIndex(uint32(txIdx), 0), txResult.Events)
 		if txErr != nil {
 			return nil, txErr
 		}
 		events = append(events, evs...)
 	}
 
+	blockEvs, err = EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseEndBlock, 0), results.EndBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, blockEvs...)
+
 	return events, nil
 }
 
@@ -287,45 +365,40 @@ func (sc *serviceClient) getRuntimeNotifiers(id common.Namespace) *runtimeBroker
 	return notifiers
 }
 
-func (sc *serviceClient) reindexBlocks(currentHeight int64, bh api.BlockHistory) error {
+func (sc *serviceClient) reindexBlocks(currentHeight int64, tr *trackedRuntime) error {
 	if currentHeight <= 0 {
 		return nil
 	}
 
+	bh := tr.blockHistory
+
 	logger := sc.logger.With("runtime_id", bh.RuntimeID())
+	labels := runtimeLabels(bh.RuntimeID())
+	start := time.Now()
 
 	var err error
-	var lastHeight int64
-	if lastHeight, err = bh.LastConsensusHeight(); err != nil {
+	var lastIndexedHeight int64
+	if lastIndexedHeight, err = bh.LastConsensusHeight(); err != nil {
 		sc.logger.Error("failed to get last indexed height",
 			"err", err,
 		)
 		return fmt.Errorf("failed to get last indexed height: %w", err)
 	}
-	// +1 since we want the last non-seen height.
-	lastHeight++
+	// Heights up to the watermark have already been processed, so there is no need to fetch them.
+	if h := tr.watermark.Height(); h > lastIndexedHeight {
+		lastIndexedHeight = h
+	}
 
-	// Take prune strategy into account.
+	// Take prune strategy and initial genesis height into account.
 	lastRetainedHeight, err := sc.backend.GetLastRetainedVersion(sc.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get last retained height: %w", err)
 	}
-	if lastHeight < lastRetainedHeight {
-		logger.Debug("last height pruned, skipping until last retained",
-			"last_retained_height", lastRetainedHeight,
-			"last_height", lastHeight,
-		)
-		lastHeight = lastRetainedHeight
-	}
-
-	// Take initial genesis height into account.
 	genesisDoc, err := sc.backend.GetGenesisDocument(sc.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get genesis document: %w", err)
 	}
-	if lastHeight < genesisDoc.Height {
-		lastHeight = genesisDoc.Height
-	}
+	lastHeight := tmapi.ReindexStartHeight(lastIndexedHeight, lastRetainedHeight, genesisDoc.Height)
 
 	// Scan all blocks between last indexed height and current height.
 	logger.Debug("reindexing blocks",
@@ -347,11 +420,12 @@ func (sc *serviceClient) reindexBlocks(currentHeight int64, bh api.BlockHistory)
 			return fmt.Errorf("failed to get tendermint block results: %w", err)
 		}
 
-		// Index block.
-		tmEvents := append(results.BeginBlockEvents, results.EndBlockEvents...)
+		// Index block, processing events in the order in which they were emitted.
+		tmEvents := append([]tmabcitypes.Event{}, results.BeginBlockEvents...)
 		for _, txResults := range results.TxsResults {
 			tmEvents = append(tmEvents, txResults.Events...)
 		}
+		tmEvents = append(tmEvents, results.EndBlockEvents...)
 		for _, tmEv := range tmEvents {
 			if tmEv.GetType() != app.EventType {
 				continue
@@ -378,8 +452,12 @@ func (sc *serviceClient) reindexBlocks(currentHeight int64, bh api.BlockHistory)
 				}
 			}
 		}
+
+		// The current height is being processed, so we are behind by the remaining heights.
+		indexedHeightLag.With(labels).Set(float64(currentHeight + 1 - height))
 	}
 
+	reindexDuration.With(labels).Observe(time.Since(start).Seconds())
 	sc.logger.Debug("block reindex complete")
 
 	return nil
@@ -395,27 +473,21 @@ func (sc *serviceClient) DeliverCommand(ctx context.Context, height int64, cmd i
 	switch c := cmd.(type) {
 	case *cmdTrackRuntime:
 		// Request to track a new runtime.
-		etr := sc.trackedRuntime[c.runtimeID]
-		if etr != nil {
-			// Ignore duplicate runtime tracking requests unless this updates the block history.
-			if etr.blockHistory != nil || c.blockHistory == nil {
-				break
-			}
-		} else {
+		tr, isNew := sc.addTrackedRuntime(c)
+		if tr == nil {
+			// Ignore duplicate runtime tracking requests.
+			break
+		}
+		if isNew {
 			sc.logger.Debug("tracking new runtime",
 				"runtime_id", c.runtimeID,
 				"height", height,
+				"last_height", c.lastHeight,
 			)
-		}
 
-		// We need to start watching a new block history.
-		tr := &trackedRuntime{
-			runtimeID:    c.runtimeID,
-			blockHistory: c.blockHistory,
+			// Request subscription to events for this runtime.
+			sc.queryCh <- app.QueryForRuntime(tr.runtimeID)
 		}
-		sc.trackedRuntime[c.runtimeID] = tr
-		// Request subscription to events for this runtime.
-		sc.queryCh <- app.QueryForRuntime(tr.runtimeID)
 
 		// Emit latest block.
 		if err := sc.processFinalizedEvent(ctx, height, tr.runtimeID, nil, true); err != nil {
@@ -426,16 +498,53 @@ func (sc *serviceClient) DeliverCommand(ctx context.Context, height int64, cmd i
 			)
 		}
 		// Make sure we reindex again when receiving the first event.
-		tr.reindexDone = false
+		tr.watermark.RequestReindex()
 	default:
 		return fmt.Errorf("roothash: unknown command: %T", cmd)
 	}
 	return nil
 }
 
+// addTrackedRuntime adds the runtime to the set of tracked runtimes or attaches a block history
+// to an already tracked runtime.
+//
+// Returns the updated tracked runtime (or nil in case the request is a duplicate) and a flag
+// indicating whether the runtime was not tracked before.
+func (sc *serviceClient) addTrackedRuntime(c *cmdTrackRuntime) (*trackedRuntime, bool) {
+	tr := sc.trackedRuntime[c.runtimeID]
+	if tr != nil {
+		// Ignore duplicate runtime tracking requests unless this updates the block history.
+		if tr.blockHistory != nil || c.blockHistory == nil {
+			return nil, false
+		}
+
+		// We need to start watching a new block history. Any heights processed so far without a
+		// block history still need to be indexed, so resume from the restored height.
+		tr.blockHistory = c.blockHistory
+		tr.watermark = tmapi.NewHeightWatermark(tr.restoredHeight)
+		return tr, false
+	}
+
+	tr = &trackedRuntime{
+		runtimeID:      c.runtimeID,
+		blockHistory:   c.blockHistory,
+		watermark:      tmapi.NewHeightWatermark(c.lastHeight),
+		restoredHeight: c.lastHeight,
+	}
+	sc.trackedRuntime[c.runtimeID] = tr
+	// Only persist when the set of tracked runtimes changes.
+	sc.persistTrackedRuntimes()
+	return tr, true
+}
+
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
-	events, err := EventsFromTendermint(tx, height, []tmabcitypes.Event{*ev})
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
+	// Ignore events that have already been delivered (e.g., via a resubscribed query).
+	if !sc.tracker.Observe(height, idx) {
+		return nil
+	}
+
+	events, err := EventsFromTendermint(tx, height, idx, []tmabcitypes.Event{*ev})
 	if err != nil {
 		return fmt.Errorf("roothash: failed to process tendermint events: %w", err)
 	}
@@ -481,13 +590,24 @@ func (sc *serviceClient) processFinalizedEvent(
 			return
 		}
 
-		tr.reindexDone = false
+		tr.watermark.RequestReindex()
 	}()
 
-	if height <= tr.height {
+	if !tr.watermark.ShouldProcess(height) {
 		return nil
 	}
 
+	start := time.Now()
+	labels := runtimeLabels(runtimeID)
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		finalizedEventsProcessed.With(labels).Inc()
+		finalizedEventProcessingDuration.With(labels).Observe(time.Since(start).Seconds())
+	}()
+
 	// Process finalized event.
 	var blk *block.Block
 	if blk, err = sc.getLatestBlockAt(ctx, runtimeID, height); err != nil {
@@ -516,17 +636,17 @@ func (sc *serviceClient) processFinalizedEvent(
 		crash.Here(crashPointBlockBeforeIndex)
 
 		// Perform reindex if required.
-		if reindex && !tr.reindexDone {
+		if reindex && tr.watermark.NeedsReindex() {
 			// Note that we need to reindex up to the previous height as the current height is
 			// already being processed right now.
-			if err = sc.reindexBlocks(height-1, tr.blockHistory); err != nil {
+			if err = sc.reindexBlocks(height-1, tr); err != nil {
 				sc.logger.Error("failed to reindex blocks",
 					"err", err,
 					"runtime_id", runtimeID,
 				)
 				return fmt.Errorf("failed to reindex blocks: %w", err)
 			}
-			tr.reindexDone = true
+			tr.watermark.MarkReindexed()
 		}
 
 		sc.logger.Debug("commit block",
@@ -545,6 +665,11 @@ func (sc *serviceClient) processFinalizedEvent(
 			)
 			return fmt.Errorf("failed to commit block to history keeper: %w", err)
 		}
+		indexedHeight.With(labels).Set(float64(height))
+		if reindex {
+			// Blocks are indexed as they are finalized so there is no lag.
+			indexedHeightLag.With(labels).Set(0)
+		}
 	}
 
 	// Skip emitting events if we are reindexing.
@@ -561,8 +686,70 @@ func (sc *serviceClient) processFinalizedEvent(
 
 	sc.allBlockNotifier.Broadcast(blk)
 	notifiers.blockNotifier.Broadcast(annBlk)
-	tr.height = height
+	tr.watermark.Advance(height)
+
+	return nil
+}
+
+// persistTrackedRuntimes stores the set of tracked runtimes together with
+// their last processed heights so that tracking can be resumed on restart.
+//
+// The set is only persisted when it changes, so the stored heights are a
+// lower bound and reindexing on restart may cover some already indexed
+// heights.
+func (sc *serviceClient) persistTrackedRuntimes() {
+	if sc.store == nil {
+		return
+	}
+
+	state := make([]*persistedTrackedRuntime, 0, len(sc.trackedRuntime))
+	for _, tr := range sc.trackedRuntime {
+		state = append(state, &persistedTrackedRuntime{
+			RuntimeID: tr.runtimeID,
+			Height:    tr.watermark.Height(),
+		})
+	}
+	sort.Slice(state, func(i, j int) bool {
+		return bytes.Compare(state[i].RuntimeID[:], state[j].RuntimeID[:]) < 0
+	})
+
+	if err := sc.store.PutCBOR(trackedRuntimesKey, state); err != nil {
+		sc.logger.Error("failed to persist tracked runtimes",
+			"err", err,
+		)
+	}
+}
+
+// restoreTrackedRuntimes loads the set of previously tracked runtimes from
+// the persistent store and requests tracking for each of them.
+func (sc *serviceClient) restoreTrackedRuntimes() error {
+	if sc.store == nil {
+		return nil
+	}
+
+	var state []*persistedTrackedRuntime
+	switch err := sc.store.GetCBOR(trackedRuntimesKey, &state); err {
+	case nil:
+	case persistent.ErrNotFound:
+		return nil
+	default:
+		return fmt.Errorf("roothash: failed to load tracked runtimes: %w", err)
+	}
+
+	if len(state) > cap(sc.cmdCh) {
+		return fmt.Errorf("roothash: too many persisted tracked runtimes: %d", len(state))
+	}
+	for _, pr := range state {
+		sc.logger.Info("restoring tracked runtime",
+			"runtime_id", pr.RuntimeID,
+			"last_height", pr.Height,
+		)
 
+		sc.cmdCh <- &cmdTrackRuntime{
+			runtimeID:  pr.RuntimeID,
+			lastHeight: pr.Height,
+		}
+	}
 	return nil
 }
 
@@ -570,6 +757,7 @@ func (sc *serviceClient) processFinalizedEvent(
 func EventsFromTendermint(
 	tx tmtypes.Tx,
 	height int64,
+	base consensusEvents.Index,
 	tmEvents []tmabcitypes.Event,
 ) ([]*api.Event, error) {
 	var txHash hash.Hash
@@ -582,11 +770,12 @@ func EventsFromTendermint(
 
 	var events []*api.Event
 	var errs error
-	for _, tmEv := range tmEvents {
+	for i, tmEv := range tmEvents {
 		// Ignore events that don't relate to the roothash app.
 		if tmEv.GetType() != app.EventType {
 			continue
 		}
+		idx := base.Offset(i)
 
 		for _, pair := range tmEv.GetAttributes() {
 			key := pair.GetKey()
@@ -601,7 +790,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, FinalizedEvent: &api.FinalizedEvent{Round: value.Round}}
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, FinalizedEvent: &api.FinalizedEvent{Round: value.Round}}
 				events = append(events, ev)
 			case bytes.Equal(key, app.KeyExecutionDiscrepancyDetected):
 				// An execution discrepancy has been detected.
@@ -611,7 +800,57 @@ func EventsFromTendermint(
 					continue
 				}
 
-				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, ExecutionDiscrepancyDetected: &value.Event}
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, ExecutionDiscrepancyDetected: &value.Event}
+				events = append(events, ev)
+			case bytes.Equal(key, app.KeyExecutorStragglers):
+				// Executor committee members failed to commit in time.
+				var value app.ValueExecutorStragglers
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt ValueExecutorStragglers event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, ExecutorStragglers: &value.Event}
+				events = append(events, ev)
+			case bytes.Equal(key, app.KeyLivenessSummary):
+				// Runtime liveness summary at the end of an epoch.
+				var value app.ValueLivenessSummary
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt ValueLivenessSummary event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, LivenessSummary: &value.Event}
+				events = append(events, ev)
+			case bytes.Equal(key, app.KeyExecutorParticipation):
+				// Executor participation statistics at the end of an epoch.
+				var value app.ValueExecutorParticipation
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt ValueExecutorParticipation event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, ExecutorParticipation: &value.Event}
+				events = append(events, ev)
+			case bytes.Equal(key, app.KeyRuntimeReset):
+				// A runtime's round state has been reset.
+				var value app.ValueRuntimeReset
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt ValueRuntimeReset event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, RuntimeReset: &value.Event}
+				events = append(events, ev)
+			case bytes.Equal(key, app.KeyRoundSuspended):
+				// A round has been suspended due to insufficient committee liveness.
+				var value app.ValueRoundSuspended
+				if err := cbor.Unmarshal(val, &value); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("roothash: corrupt ValueRoundSuspended event: %w", err))
+					continue
+				}
+
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, RoundSuspended: &value.Event}
 				events = append(events, ev)
 			case bytes.Equal(key, app.KeyExecutorCommitted):
 				// An executor commit has been processed.
@@ -621,7 +860,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, ExecutorCommitted: &value.Event}
+				ev := &api.Event{RuntimeID: value.ID, Height: height, TxHash: txHash, Index: idx, ExecutorCommitted: &value.Event}
 				events = append(events, ev)
 			case bytes.Equal(key, app.KeyRuntimeID):
 				// Runtime ID attribute (Base64-encoded to allow queries).
@@ -638,25 +877,43 @@ func New(
 	ctx context.Context,
 	dataDir string,
 	backend tmapi.Backend,
+	commonStore *persistent.CommonStore,
 ) (ServiceClient, error) {
+	initMetrics()
+
 	// Initialize and register the tendermint service component.
 	a := app.New()
 	if err := backend.RegisterApplication(a); err != nil {
 		return nil, err
 	}
 
-	return &serviceClient{
+	sc := &serviceClient{
 		ctx:              ctx,
 		logger:           logging.GetLogger("roothash/tendermint"),
 		backend:          backend,
 		querier:          a.QueryFactory().(*app.QueryFactory),
+		tracker:          tmapi.NewEventTracker(tmapi.DefaultEventTrackerWindow),
 		allBlockNotifier: pubsub.NewBroker(false),
 		runtimeNotifiers: make(map[common.Namespace]*runtimeBrokers),
 		genesisBlocks:    make(map[common.Namespace]*block.Block),
 		queryCh:          make(chan tmpubsub.Query, runtimeRegistry.MaxRuntimeCount),
 		cmdCh:            make(chan interface{}, runtimeRegistry.MaxRuntimeCount),
 		trackedRuntime:   make(map[common.Namespace]*trackedRuntime),
-	}, nil
+	}
+
+	// Restore the set of tracked runtimes so that block history tracking
+	// survives restarts.
+	if commonStore != nil {
+		var err error
+		if sc.store, err = commonStore.GetServiceStore(storeBucketName); err != nil {
+			return nil, fmt.Errorf("roothash: failed to open service store: %w", err)
+		}
+		if err = sc.restoreTrackedRuntimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	return sc, nil
 }
 
 func init() {
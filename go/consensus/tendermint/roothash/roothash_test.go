@@ -0,0 +1,122 @@
+package roothash
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+)
+
+type testBlockHistory struct {
+	api.BlockHistory
+
+	runtimeID common.Namespace
+}
+
+func (h *testBlockHistory) RuntimeID() common.Namespace {
+	return h.runtimeID
+}
+
+func newTestServiceClient(t *testing.T, commonStore *persistent.CommonStore) *serviceClient {
+	store, err := commonStore.GetServiceStore(storeBucketName)
+	require.NoError(t, err, "GetServiceStore")
+
+	return &serviceClient{
+		logger:         logging.GetLogger("roothash/tendermint/test"),
+		store:          store,
+		cmdCh:          make(chan interface{}, runtimeRegistry.MaxRuntimeCount),
+		trackedRuntime: make(map[common.Namespace]*trackedRuntime),
+	}
+}
+
+func TestTrackedRuntimesRestore(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-core-unittests")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	commonStore, err := persistent.NewCommonStore(dir)
+	require.NoError(err, "NewCommonStore")
+	defer commonStore.Close()
+
+	rt1 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/roothash: runtime 1"), 0)
+	rt2 := common.NewTestNamespaceFromSeed([]byte("consensus/tendermint/roothash: runtime 2"), 0)
+
+	store, err := commonStore.GetServiceStore(storeBucketName)
+	require.NoError(err, "GetServiceStore")
+	loadPersisted := func() map[common.Namespace]int64 {
+		var state []*persistedTrackedRuntime
+		err = store.GetCBOR(trackedRuntimesKey, &state)
+		require.NoError(err, "GetCBOR")
+
+		heights := make(map[common.Namespace]int64)
+		for _, pr := range state {
+			heights[pr.RuntimeID] = pr.Height
+		}
+		return heights
+	}
+
+	// Start tracking a runtime and process some heights.
+	sc := newTestServiceClient(t, commonStore)
+	tr, isNew := sc.addTrackedRuntime(&cmdTrackRuntime{runtimeID: rt1})
+	require.NotNil(tr, "runtime should be tracked")
+	require.True(isNew, "runtime should be new")
+	tr.watermark.Advance(10)
+	require.EqualValues(map[common.Namespace]int64{rt1: 0}, loadPersisted(),
+		"processing heights should not rewrite the store",
+	)
+
+	// Tracking another runtime changes the tracked set and should persist it.
+	tr, isNew = sc.addTrackedRuntime(&cmdTrackRuntime{runtimeID: rt2})
+	require.NotNil(tr, "runtime should be tracked")
+	require.True(isNew, "runtime should be new")
+	require.EqualValues(map[common.Namespace]int64{rt1: 10, rt2: 0}, loadPersisted(),
+		"changing the tracked set should persist it",
+	)
+
+	// Duplicate requests should be ignored.
+	tr, _ = sc.addTrackedRuntime(&cmdTrackRuntime{runtimeID: rt1})
+	require.Nil(tr, "duplicate request should be ignored")
+
+	// Restart and restore the tracked runtimes.
+	sc = newTestServiceClient(t, commonStore)
+	err = sc.restoreTrackedRuntimes()
+	require.NoError(err, "restoreTrackedRuntimes")
+	require.Len(sc.cmdCh, 2, "all persisted runtimes should be restored")
+	for len(sc.cmdCh) > 0 {
+		cmd := (<-sc.cmdCh).(*cmdTrackRuntime)
+		tr, isNew = sc.addTrackedRuntime(cmd)
+		require.NotNil(tr, "runtime should be tracked")
+		require.True(isNew, "runtime should be new")
+	}
+	tr = sc.trackedRuntime[rt1]
+	require.NotNil(tr, "runtime should be restored")
+	require.EqualValues(10, tr.watermark.Height(), "watermark should be restored")
+	require.Nil(tr.blockHistory, "restored runtime should not have a block history")
+
+	// Process some heights without a block history.
+	tr.watermark.Advance(15)
+	tr.watermark.MarkReindexed()
+
+	// Attaching a block history should keep the restored state and resume from the stored height.
+	bh := &testBlockHistory{runtimeID: rt1}
+	tr, isNew = sc.addTrackedRuntime(&cmdTrackRuntime{runtimeID: rt1, blockHistory: bh})
+	require.NotNil(tr, "block history should be attached")
+	require.False(isNew, "runtime should not be new")
+	require.Same(sc.trackedRuntime[rt1], tr, "restored runtime should not be replaced")
+	require.Equal(bh, tr.blockHistory, "block history should be attached")
+	require.EqualValues(10, tr.watermark.Height(), "reindexing should resume from the stored height")
+	require.True(tr.watermark.NeedsReindex(), "reindexing should be requested")
+
+	// Once the block history is attached, further requests should be ignored.
+	tr, _ = sc.addTrackedRuntime(&cmdTrackRuntime{runtimeID: rt1, blockHistory: bh})
+	require.Nil(tr, "duplicate request should be ignored")
+}
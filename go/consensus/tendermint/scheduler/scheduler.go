@@ -15,6 +15,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/scheduler"
 	"github.com/oasisprotocol/oasis-core/go/scheduler/api"
@@ -31,6 +32,7 @@ type serviceClient struct {
 
 	logger *logging.Logger
 
+	backend  tmapi.Backend
 	querier  *app.QueryFactory
 	notifier *pubsub.Broker
 }
@@ -76,6 +78,34 @@ func (sc *serviceClient) GetCommittees(ctx context.Context, request *api.GetComm
 	return runtimeCommittees, nil
 }
 
+func (sc *serviceClient) SimulateElection(ctx context.Context, request *api.SimulateElectionRequest) (*api.ElectionSimulation, error) {
+	// Elections happen at the start of each epoch.
+	height, err := sc.backend.EpochTime().GetEpochBlock(ctx, request.Epoch)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: failed to get epoch block: %w", err)
+	}
+	if height <= 0 {
+		return nil, fmt.Errorf("scheduler: no elections in epoch %d", request.Epoch)
+	}
+
+	// Use the block timestamp to verify node TEE attestations the same way as during the
+	// actual election.
+	blk, err := sc.backend.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: failed to get block at height %d: %w", height, err)
+	}
+	if blk == nil {
+		return nil, consensus.ErrVersionNotFound
+	}
+
+	q, err := sc.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ElectionSimulation(ctx, request.RuntimeID, request.Kind, request.Epoch, blk.Header.Time)
+}
+
 func (sc *serviceClient) WatchCommittees(ctx context.Context) (<-chan *api.Committee, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.Committee)
 	sub := sc.notifier.Subscribe()
@@ -99,7 +129,7 @@ func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 }
 
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
 	for _, pair := range ev.GetAttributes() {
 		if bytes.Equal(pair.GetKey(), app.KeyElected) {
 			var kinds []api.CommitteeKind
@@ -144,6 +174,7 @@ func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
 
 	sc := &serviceClient{
 		logger:  logging.GetLogger("scheduler/tendermint"),
+		backend: backend,
 		querier: a.QueryFactory().(*app.QueryFactory),
 	}
 	sc.notifier = pubsub.NewBrokerEx(func(ch channels.Channel) {
@@ -28,6 +28,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	tmcommon "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
+	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/peers"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
@@ -64,6 +65,8 @@ type seedService struct {
 	addrBook  pex.AddrBook
 	p2pSwitch *p2p.Switch
 
+	peerManager *peers.Manager
+
 	stopOnce sync.Once
 	quitCh   chan struct{}
 }
@@ -188,6 +191,11 @@ func (srv *seedService) EstimateGas(ctx context.Context, req *consensus.Estimate
 	return 0, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) SimulateTx(ctx context.Context, req *consensus.SimulateTxRequest) (*consensus.SimulateTxResponse, error) {
+	return nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) WaitEpoch(ctx context.Context, epoch epochtime.EpochTime) error {
 	return consensus.ErrUnsupported
@@ -223,6 +231,11 @@ func (srv *seedService) WatchBlocks(ctx context.Context) (<-chan *consensus.Bloc
 	return nil, nil, consensus.ErrUnsupported
 }
 
+// Implements Backend.
+func (srv *seedService) WatchBlocksFrom(ctx context.Context, height int64) (<-chan *consensus.Block, pubsub.ClosableSubscription, error) {
+	return nil, nil, consensus.ErrUnsupported
+}
+
 // Implements Backend.
 func (srv *seedService) GetSignerNonce(ctx context.Context, req *consensus.GetSignerNonceRequest) (uint64, error) {
 	return 0, consensus.ErrUnsupported
@@ -300,6 +313,11 @@ func (srv *seedService) SubmissionManager() consensus.SubmissionManager {
 	panic(consensus.ErrUnsupported)
 }
 
+// Implements Backend.
+func (srv *seedService) PeerManager() consensus.PeerManager {
+	return srv.peerManager
+}
+
 // New creates a new seed-only consensus service.
 func New(dataDir string, identity *identity.Identity, genesisProvider genesis.Provider) (consensus.Backend, error) {
 	var err error
@@ -391,6 +409,8 @@ func New(dataDir string, identity *identity.Identity, genesisProvider genesis.Pr
 	srv.p2pSwitch.SetNodeKey(nodeKey)
 	srv.p2pSwitch.SetAddrBook(srv.addrBook)
 	srv.p2pSwitch.AddReactor("pex", pexReactor)
+	srv.peerManager = peers.New(nil)
+	srv.p2pSwitch.AddReactor(peers.ReactorName, srv.peerManager)
 	srv.p2pSwitch.SetNodeInfo(nodeInfo)
 
 	return srv, nil
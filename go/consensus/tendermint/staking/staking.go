@@ -17,6 +17,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensusEvents "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/staking"
 	"github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -35,6 +36,7 @@ type serviceClient struct {
 
 	backend tmapi.Backend
 	querier *app.QueryFactory
+	tracker *tmapi.EventTracker
 
 	eventNotifier *pubsub.Broker
 }
@@ -120,6 +122,15 @@ func (sc *serviceClient) Delegations(ctx context.Context, query *api.OwnerQuery)
 	return q.Delegations(ctx, query.Owner)
 }
 
+func (sc *serviceClient) DelegationsTo(ctx context.Context, query *api.OwnerQuery) (map[api.Address]*api.Delegation, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.DelegationsTo(ctx, query.Owner)
+}
+
 func (sc *serviceClient) DebondingDelegations(ctx context.Context, query *api.OwnerQuery) (map[api.Address][]*api.DebondingDelegation, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -142,6 +153,15 @@ func (sc *serviceClient) Allowance(ctx context.Context, query *api.AllowanceQuer
 	return &allowance, nil
 }
 
+func (sc *serviceClient) Alias(ctx context.Context, query *api.AliasQuery) (*api.AliasInfo, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Alias(ctx, query.Alias)
+}
+
 func (sc *serviceClient) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	// Query the staking genesis state.
 	q, err := sc.querier.QueryAt(ctx, height)
@@ -188,32 +208,66 @@ func (sc *serviceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 		return nil, err
 	}
 
+	// Decode events in the order in which they were emitted.
 	var events []*api.Event
-	// Decode events from block results.
-	blockEvs, err := EventsFromTendermint(nil, results.Height, results.BeginBlockEvents)
-	if err != nil {
-		return nil, err
-	}
-	events = append(events, blockEvs...)
-
-	blockEvs, err = EventsFromTendermint(nil, results.Height, results.EndBlockEvents)
+	blockEvs, err := EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseBeginBlock, 0), results.BeginBlockEvents)
 	if err != nil {
 		return nil, err
 	}
 	events = append(events, blockEvs...)
 
-	// Decode events from transaction results.
 	for txIdx, txResult := range results.TxsResults {
 		// The order of transactions in txns and results.TxsResults is
 		// supposed to match, so the same index in both slices refers to the
 		// same transaction.
-		evs, txErr := EventsFromTendermint(txns[txIdx], results.Height, txResult.Events)
+		evs, txErr := EventsFromTendermint(txns[txIdx], results.Height, consensusEvents.NewTxIndex(uint32(txIdx), 0), txResult.Events)
 		if txErr != nil {
 			return nil, txErr
 		}
 		events = append(events, evs...)
 	}
 
+	blockEvs, err = EventsFromTendermint(nil, results.Height, consensusEvents.NewBlockIndex(consensusEvents.PhaseEndBlock, 0), results.EndBlockEvents)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, blockEvs...)
+
+	return events, nil
+}
+
+func (sc *serviceClient) GetRewardEvents(ctx context.Context, query *api.RewardEventsQuery) ([]*api.Event, error) {
+	if query.FromHeight <= 0 || query.ToHeight < query.FromHeight {
+		return nil, fmt.Errorf("%w: invalid height range", api.ErrInvalidArgument)
+	}
+	if query.ToHeight-query.FromHeight >= api.MaxRewardEventsQueryRange {
+		return nil, fmt.Errorf("%w: height range too large (max: %d)", api.ErrInvalidArgument, api.MaxRewardEventsQueryRange)
+	}
+
+	var events []*api.Event
+	for height := query.FromHeight; height <= query.ToHeight; height++ {
+		evs, err := sc.GetEvents(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ev := range evs {
+			var account api.Address
+			switch {
+			case ev.Reward != nil:
+				account = ev.Reward.Account
+			case ev.Slash != nil:
+				account = ev.Slash.Account
+			default:
+				continue
+			}
+			if query.Account != nil && !query.Account.Equal(account) {
+				continue
+			}
+			events = append(events, ev)
+		}
+	}
+
 	return events, nil
 }
 
@@ -225,6 +279,45 @@ func (sc *serviceClient) WatchEvents(ctx context.Context) (<-chan *api.Event, pu
 	return typedCh, sub, nil
 }
 
+func (sc *serviceClient) WatchFilteredEvents(ctx context.Context, filter *api.EventFilter) (<-chan *api.Event, pubsub.ClosableSubscription, error) {
+	if filter == nil {
+		filter = &api.EventFilter{}
+	}
+	for _, kind := range filter.Kinds {
+		if kind == api.EventKindInvalid || kind > api.EventKindMax {
+			return nil, nil, fmt.Errorf("%w: invalid event kind: %d", api.ErrInvalidArgument, kind)
+		}
+	}
+
+	// Each subscription gets its own copy of the filter so that the caller
+	// is free to modify it after the call.
+	f := &api.EventFilter{
+		Addresses: append([]api.Address{}, filter.Addresses...),
+		Kinds:     append([]api.EventKind{}, filter.Kinds...),
+	}
+
+	typedCh := make(chan *api.Event)
+	sub := sc.eventNotifier.Subscribe()
+	go func() {
+		defer close(typedCh)
+
+		for v := range sub.Untyped() {
+			ev := v.(*api.Event)
+			if !f.Matches(ev) {
+				continue
+			}
+
+			select {
+			case typedCh <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return typedCh, sub, nil
+}
+
 func (sc *serviceClient) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -243,8 +336,13 @@ func (sc *serviceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 }
 
 // Implements api.ServiceClient.
-func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, ev *tmabcitypes.Event) error {
-	events, err := EventsFromTendermint(tx, height, []tmabcitypes.Event{*ev})
+func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmtypes.Tx, idx consensusEvents.Index, ev *tmabcitypes.Event) error {
+	// Ignore events that have already been delivered.
+	if !sc.tracker.Observe(height, idx) {
+		return nil
+	}
+
+	events, err := EventsFromTendermint(tx, height, idx, []tmabcitypes.Event{*ev})
 	if err != nil {
 		return fmt.Errorf("staking: failed to process tendermint events: %w", err)
 	}
@@ -261,6 +359,7 @@ func (sc *serviceClient) DeliverEvent(ctx context.Context, height int64, tx tmty
 func EventsFromTendermint(
 	tx tmtypes.Tx,
 	height int64,
+	base consensusEvents.Index,
 	tmEvents []tmabcitypes.Event,
 ) ([]*api.Event, error) {
 	var txHash hash.Hash
@@ -273,11 +372,12 @@ func EventsFromTendermint(
 
 	var events []*api.Event
 	var errs error
-	for _, tmEv := range tmEvents {
+	for i, tmEv := range tmEvents {
 		// Ignore events that don't relate to the staking app.
 		if tmEv.GetType() != app.EventType {
 			continue
 		}
+		idx := base.Offset(i)
 
 		for _, pair := range tmEv.GetAttributes() {
 			key := pair.GetKey()
@@ -292,7 +392,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, Escrow: &api.EscrowEvent{Take: &e}}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Escrow: &api.EscrowEvent{Take: &e}}
 				events = append(events, evt)
 			case bytes.Equal(key, app.KeyTransfer):
 				// Transfer event.
@@ -302,7 +402,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, Transfer: &e}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Transfer: &e}
 				events = append(events, evt)
 			case bytes.Equal(key, app.KeyReclaimEscrow):
 				// Reclaim escrow event.
@@ -312,7 +412,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, Escrow: &api.EscrowEvent{Reclaim: &e}}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Escrow: &api.EscrowEvent{Reclaim: &e}}
 				events = append(events, evt)
 			case bytes.Equal(key, app.KeyAddEscrow):
 				// Add escrow event.
@@ -322,7 +422,7 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, Escrow: &api.EscrowEvent{Add: &e}}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Escrow: &api.EscrowEvent{Add: &e}}
 				events = append(events, evt)
 			case bytes.Equal(key, app.KeyBurn):
 				// Burn event.
@@ -332,7 +432,27 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, Burn: &e}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Burn: &e}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeyMint):
+				// Mint event.
+				var e api.MintEvent
+				if err := cbor.Unmarshal(val, &e); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("staking: corrupt Mint event: %w", err))
+					continue
+				}
+
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Mint: &e}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeyAlias):
+				// Alias event.
+				var e api.AliasEvent
+				if err := cbor.Unmarshal(val, &e); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("staking: corrupt Alias event: %w", err))
+					continue
+				}
+
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Alias: &e}
 				events = append(events, evt)
 			case bytes.Equal(key, app.KeyAllowanceChange):
 				// Allowance change event.
@@ -342,7 +462,27 @@ func EventsFromTendermint(
 					continue
 				}
 
-				evt := &api.Event{Height: height, TxHash: txHash, AllowanceChange: &e}
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, AllowanceChange: &e}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeyReward):
+				// Reward event.
+				var e api.RewardEvent
+				if err := cbor.Unmarshal(val, &e); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("staking: corrupt Reward event: %w", err))
+					continue
+				}
+
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Reward: &e}
+				events = append(events, evt)
+			case bytes.Equal(key, app.KeySlash):
+				// Slash event.
+				var e api.SlashEvent
+				if err := cbor.Unmarshal(val, &e); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("staking: corrupt Slash event: %w", err))
+					continue
+				}
+
+				evt := &api.Event{Height: height, TxHash: txHash, Index: idx, Slash: &e}
 				events = append(events, evt)
 			default:
 				errs = multierror.Append(errs, fmt.Errorf("staking: unknown event type: key: %s, val: %s", key, val))
@@ -370,6 +510,7 @@ func New(ctx context.Context, backend tmapi.Backend) (ServiceClient, error) {
 		logger:        logging.GetLogger("staking/tendermint"),
 		backend:       backend,
 		querier:       a.QueryFactory().(*app.QueryFactory),
+		tracker:       tmapi.NewEventTracker(tmapi.DefaultEventTrackerWindow),
 		eventNotifier: pubsub.NewBroker(false),
 	}, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/common"
 	"github.com/oasisprotocol/oasis-core/go/consensus/tendermint/full"
@@ -39,11 +40,12 @@ func New(
 	identity *identity.Identity,
 	upgrader upgradeAPI.Backend,
 	genesisProvider genesisAPI.Provider,
+	commonStore *persistent.CommonStore,
 ) (consensusAPI.Backend, error) {
 	switch mode := viper.GetString(CfgMode); mode {
 	case ModeFull:
 		// Full node.
-		return full.New(ctx, dataDir, identity, upgrader, genesisProvider)
+		return full.New(ctx, dataDir, identity, upgrader, genesisProvider, commonStore)
 	case ModeSeed:
 		// Seed-only node.
 		return seed.New(dataDir, identity, genesisProvider)
@@ -41,6 +41,7 @@ func (p *testNodeGenesisProvider) GetTendermintGenesisDocument() (*tmtypes.Genes
 // running a single node "network", only for testing.
 func NewTestNodeGenesisProvider(identity *identity.Identity) (genesis.Provider, error) {
 	doc := &genesis.Document{
+		Version:   genesis.LatestDocumentVersion,
 		Height:    1,
 		ChainID:   genesisTestHelpers.TestChainID,
 		Time:      time.Now(),
@@ -90,6 +90,23 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 		}
 	}
 
+	// Replaying blocks from an earlier height should produce all blocks in order without any gaps.
+	// Only wait for already finalized blocks to avoid exhausting the shared test timeout.
+	fromHeight := status.LatestHeight - 1
+	replayCh, replaySub, err := backend.WatchBlocksFrom(ctx, fromHeight)
+	require.NoError(err, "WatchBlocksFrom")
+	defer replaySub.Close()
+
+	for height := fromHeight; height <= blk.Height; height++ {
+		select {
+		case replayBlk := <-replayCh:
+			require.NotNil(replayBlk, "returned block should not be nil")
+			require.EqualValues(height, replayBlk.Height, "replayed blocks should be in order without gaps")
+		case <-time.After(recvTimeout):
+			t.Fatalf("failed to receive replayed consensus block")
+		}
+	}
+
 	epoch, err := backend.GetEpoch(ctx, consensus.HeightLatest)
 	require.NoError(err, "GetEpoch")
 	require.True(epoch > 0, "epoch height should be greater than zero")
@@ -100,6 +117,13 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	})
 	require.NoError(err, "EstimateGas")
 
+	simRsp, err := backend.SimulateTx(ctx, &consensus.SimulateTxRequest{
+		Signer:      memorySigner.NewTestSigner("simulate tx signer").Public(),
+		Transaction: transaction.NewTransaction(0, nil, staking.MethodTransfer, &staking.Transfer{}),
+	})
+	require.NoError(err, "SimulateTx")
+	require.NotNil(simRsp.Result, "SimulateTx should return a result")
+
 	nonce, err := backend.GetSignerNonce(ctx, &consensus.GetSignerNonceRequest{
 		AccountAddress: staking.NewAddress(
 			signature.NewPublicKey("badfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -11,9 +12,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
@@ -28,6 +33,14 @@ type NodeController interface {
 	// shutdown to complete.
 	RequestShutdown(ctx context.Context, wait bool) error
 
+	// RequestShutdownAtEpoch requests the node to shut down gracefully once the
+	// given epoch has been reached.
+	//
+	// The node first waits for the requested epoch, then deregisters so that it
+	// stops accepting new work, waits for its registration to expire or be
+	// removed, persists local storage and exits.
+	RequestShutdownAtEpoch(ctx context.Context, req *ShutdownRequest) error
+
 	// WaitSync waits for the node to finish syncing.
 	WaitSync(ctx context.Context) error
 
@@ -50,6 +63,110 @@ type NodeController interface {
 
 	// GetStatus returns the current status overview of the node.
 	GetStatus(ctx context.Context) (*Status, error)
+
+	// GetMempool returns an overview of the transactions currently pending in the local node's
+	// consensus mempool.
+	GetMempool(ctx context.Context) (*MempoolStatus, error)
+
+	// GetConsensusPeers returns the status of the consensus layer peers known to the local node,
+	// including their reputation scores and any active bans.
+	GetConsensusPeers(ctx context.Context) ([]*consensus.PeerStatus, error)
+
+	// BanConsensusPeer disconnects from the given consensus layer peer and refuses any further
+	// connections with it until the ban expires or is lifted.
+	BanConsensusPeer(ctx context.Context, req *consensus.BanPeerRequest) error
+
+	// UnbanConsensusPeer lifts the ban on the given consensus layer peer.
+	UnbanConsensusPeer(ctx context.Context, id string) error
+
+	// UpdatePersistentPeers updates the set of consensus layer persistent peers without requiring
+	// a node restart and returns the updated set.
+	UpdatePersistentPeers(ctx context.Context, req *consensus.PersistentPeersUpdate) ([]string, error)
+
+	// RestartSubsystem restarts an individual node subsystem in-process, tearing it down cleanly
+	// and re-initializing it without affecting the rest of the node (e.g., consensus connectivity).
+	RestartSubsystem(ctx context.Context, req *RestartSubsystemRequest) error
+
+	// GetEpochSnapshots returns the current epoch snapshot of each runtime the node is a
+	// committee node for, including the elected committees and the roles of the local node.
+	GetEpochSnapshots(ctx context.Context) (map[common.Namespace]*commonWorker.EpochSnapshot, error)
+
+	// WatchEvents returns a channel that produces a stream of node events derived from critical
+	// log events (e.g., round failures).
+	WatchEvents(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error)
+}
+
+// NodeEvent is a structured node event derived from a critical log event.
+type NodeEvent struct {
+	// Kind is the log event value (e.g., roothash/round_failed).
+	Kind string `json:"kind"`
+	// Module is the module that emitted the event.
+	Module string `json:"module,omitempty"`
+	// Level is the level at which the event was logged.
+	Level string `json:"level"`
+	// Message is the log message.
+	Message string `json:"msg"`
+	// Timestamp is the time at which the event was emitted.
+	Timestamp time.Time `json:"ts"`
+	// Fields are additional event details taken from the log entry.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Subsystem is the name of a node subsystem that can be restarted.
+type Subsystem string
+
+const (
+	// SubsystemStorageWorker is the storage worker for a given runtime.
+	SubsystemStorageWorker Subsystem = "storage_worker"
+	// SubsystemRegistration is the node registration worker.
+	SubsystemRegistration Subsystem = "registration"
+	// SubsystemKeyManagerClient is the key manager client for a given runtime.
+	SubsystemKeyManagerClient Subsystem = "keymanager_client"
+)
+
+// IsPerRuntime returns true iff the subsystem is instantiated for each runtime.
+func (s Subsystem) IsPerRuntime() bool {
+	switch s {
+	case SubsystemStorageWorker, SubsystemKeyManagerClient:
+		return true
+	default:
+		return false
+	}
+}
+
+// RestartSubsystemRequest is a subsystem restart request.
+type RestartSubsystemRequest struct {
+	// Subsystem is the name of the subsystem to restart.
+	Subsystem Subsystem `json:"subsystem"`
+
+	// RuntimeID is the identifier of the runtime in case the subsystem is instantiated for each
+	// runtime.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+}
+
+// ValidateBasic performs basic restart request validity checks.
+func (r *RestartSubsystemRequest) ValidateBasic() error {
+	switch r.Subsystem {
+	case SubsystemStorageWorker, SubsystemRegistration, SubsystemKeyManagerClient:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownSubsystem, r.Subsystem)
+	}
+	switch {
+	case r.Subsystem.IsPerRuntime() && r.RuntimeID == nil:
+		return fmt.Errorf("%w: subsystem %s requires a runtime ID", ErrMalformedRestartRequest, r.Subsystem)
+	case !r.Subsystem.IsPerRuntime() && r.RuntimeID != nil:
+		return fmt.Errorf("%w: subsystem %s does not take a runtime ID", ErrMalformedRestartRequest, r.Subsystem)
+	}
+	return nil
+}
+
+// ShutdownRequest is a graceful shutdown request.
+type ShutdownRequest struct {
+	// Wait specifies whether the call should wait for the shutdown to complete.
+	Wait bool `json:"wait,omitempty"`
+
+	// Epoch is the epoch at (or after) which the shutdown should be started.
+	Epoch epochtime.EpochTime `json:"epoch"`
 }
 
 // Status is the current status overview.
@@ -95,6 +212,29 @@ type RegistrationStatus struct {
 	// Descriptor is the node descriptor that the node successfully registered with. In case the
 	// node did not successfully register yet, it will be nil.
 	Descriptor *node.Node `json:"descriptor,omitempty"`
+
+	// AdditionalEntities is the registration status of the nodes registered on behalf of any
+	// additional entities, keyed by entity ID.
+	AdditionalEntities map[signature.PublicKey]*EntityRegistrationStatus `json:"additional_entities,omitempty"`
+}
+
+// EntityRegistrationStatus is the registration status of a node registered on behalf of an
+// additional entity.
+type EntityRegistrationStatus struct {
+	// NodeID is the identifier of the node registered on behalf of the entity.
+	NodeID signature.PublicKey `json:"node_id"`
+
+	// LastRegistration is the time of the last successful registration with the consensus registry
+	// service. In case the node did not successfully register yet, it will be the zero timestamp.
+	LastRegistration time.Time `json:"last_registration"`
+
+	// LastError is the error returned by the last failed registration attempt. It is cleared on
+	// successful registration.
+	LastError string `json:"last_error,omitempty"`
+
+	// Descriptor is the node descriptor that the node successfully registered with. In case the
+	// node did not successfully register yet, it will be nil.
+	Descriptor *node.Node `json:"descriptor,omitempty"`
 }
 
 // RuntimeStatus is the per-runtime status overview.
@@ -123,6 +263,39 @@ type RuntimeStatus struct {
 	Storage *storageWorker.Status `json:"storage"`
 }
 
+// MempoolStatus is the overview of the local node's consensus mempool.
+type MempoolStatus struct {
+	// Transactions is a list of transactions currently pending in the mempool.
+	Transactions []*MempoolTransaction `json:"transactions"`
+
+	// MethodCounts is the number of pending transactions for each method.
+	MethodCounts map[transaction.MethodName]uint64 `json:"method_counts"`
+
+	// InvalidCount is the number of pending transactions that could not be decoded.
+	InvalidCount uint64 `json:"invalid_count"`
+}
+
+// MempoolTransaction is a decoded transaction pending in the mempool.
+type MempoolTransaction struct {
+	// Hash is the hash of the signed transaction.
+	Hash hash.Hash `json:"hash"`
+	// Size is the size of the encoded signed transaction in bytes.
+	Size uint64 `json:"size"`
+
+	// Method is the transaction method name.
+	Method transaction.MethodName `json:"method"`
+	// Signer is the public key of the transaction signer.
+	Signer signature.PublicKey `json:"signer"`
+	// Sender is the staking account address of the transaction signer.
+	Sender staking.Address `json:"sender"`
+	// Nonce is the transaction nonce.
+	Nonce uint64 `json:"nonce"`
+	// Fee is the transaction fee.
+	Fee *transaction.Fee `json:"fee,omitempty"`
+	// GasPrice is the gas price implied by the transaction fee.
+	GasPrice *quantity.Quantity `json:"gas_price,omitempty"`
+}
+
 // ControlledNode is an internal interface that the controlled oasis-node must provide.
 type ControlledNode interface {
 	// RequestShutdown is the method called by the control server to trigger node shutdown.
@@ -139,8 +312,27 @@ type ControlledNode interface {
 
 	// GetRuntimeStatus returns the node's current per-runtime status.
 	GetRuntimeStatus(ctx context.Context) (map[common.Namespace]RuntimeStatus, error)
+
+	// RestartSubsystem restarts the given node subsystem.
+	RestartSubsystem(ctx context.Context, req *RestartSubsystemRequest) error
+
+	// GetEpochSnapshots returns the node's current per-runtime epoch snapshots.
+	GetEpochSnapshots(ctx context.Context) (map[common.Namespace]*commonWorker.EpochSnapshot, error)
 }
 
+// ModuleName is the module name for the node controller service.
+const ModuleName = "control"
+
+var (
+	// ErrUnknownSubsystem is the error returned when restarting an unknown subsystem.
+	ErrUnknownSubsystem = errors.New(ModuleName, 1, "control: unknown subsystem")
+	// ErrMalformedRestartRequest is the error returned for malformed subsystem restart requests.
+	ErrMalformedRestartRequest = errors.New(ModuleName, 2, "control: malformed subsystem restart request")
+	// ErrSubsystemNotAvailable is the error returned when the requested subsystem is not running
+	// on this node.
+	ErrSubsystemNotAvailable = errors.New(ModuleName, 3, "control: subsystem not available")
+)
+
 // DebugModuleName is the module name for the debug controller service.
 const DebugModuleName = "control/debug"
 
@@ -5,8 +5,12 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
 )
 
 var (
@@ -15,6 +19,8 @@ var (
 
 	// methodRequestShutdown is the RequestShutdown method.
 	methodRequestShutdown = serviceName.NewMethod("RequestShutdown", false)
+	// methodRequestShutdownAtEpoch is the RequestShutdownAtEpoch method.
+	methodRequestShutdownAtEpoch = serviceName.NewMethod("RequestShutdownAtEpoch", ShutdownRequest{})
 	// methodWaitSync is the WaitSync method.
 	methodWaitSync = serviceName.NewMethod("WaitSync", nil)
 	// methodIsSynced is the IsSynced method.
@@ -29,6 +35,22 @@ var (
 	methodCancelUpgrade = serviceName.NewMethod("CancelUpgrade", nil)
 	// methodGetStatus is the GetStatus method.
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
+	// methodGetMempool is the GetMempool method.
+	methodGetMempool = serviceName.NewMethod("GetMempool", nil)
+	// methodGetConsensusPeers is the GetConsensusPeers method.
+	methodGetConsensusPeers = serviceName.NewMethod("GetConsensusPeers", nil)
+	// methodBanConsensusPeer is the BanConsensusPeer method.
+	methodBanConsensusPeer = serviceName.NewMethod("BanConsensusPeer", consensus.BanPeerRequest{})
+	// methodUnbanConsensusPeer is the UnbanConsensusPeer method.
+	methodUnbanConsensusPeer = serviceName.NewMethod("UnbanConsensusPeer", "")
+	// methodUpdatePersistentPeers is the UpdatePersistentPeers method.
+	methodUpdatePersistentPeers = serviceName.NewMethod("UpdatePersistentPeers", consensus.PersistentPeersUpdate{})
+	// methodRestartSubsystem is the RestartSubsystem method.
+	methodRestartSubsystem = serviceName.NewMethod("RestartSubsystem", RestartSubsystemRequest{})
+	// methodGetEpochSnapshots is the GetEpochSnapshots method.
+	methodGetEpochSnapshots = serviceName.NewMethod("GetEpochSnapshots", nil)
+	// methodWatchEvents is the WatchEvents method.
+	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -39,6 +61,10 @@ var (
 				MethodName: methodRequestShutdown.ShortName(),
 				Handler:    handlerRequestShutdown,
 			},
+			{
+				MethodName: methodRequestShutdownAtEpoch.ShortName(),
+				Handler:    handlerRequestShutdownAtEpoch,
+			},
 			{
 				MethodName: methodWaitSync.ShortName(),
 				Handler:    handlerWaitSync,
@@ -67,8 +93,42 @@ var (
 				MethodName: methodGetStatus.ShortName(),
 				Handler:    handlerGetStatus,
 			},
+			{
+				MethodName: methodGetMempool.ShortName(),
+				Handler:    handlerGetMempool,
+			},
+			{
+				MethodName: methodGetConsensusPeers.ShortName(),
+				Handler:    handlerGetConsensusPeers,
+			},
+			{
+				MethodName: methodBanConsensusPeer.ShortName(),
+				Handler:    handlerBanConsensusPeer,
+			},
+			{
+				MethodName: methodUnbanConsensusPeer.ShortName(),
+				Handler:    handlerUnbanConsensusPeer,
+			},
+			{
+				MethodName: methodUpdatePersistentPeers.ShortName(),
+				Handler:    handlerUpdatePersistentPeers,
+			},
+			{
+				MethodName: methodRestartSubsystem.ShortName(),
+				Handler:    handlerRestartSubsystem,
+			},
+			{
+				MethodName: methodGetEpochSnapshots.ShortName(),
+				Handler:    handlerGetEpochSnapshots,
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    methodWatchEvents.ShortName(),
+				Handler:       handlerWatchEvents,
+				ServerStreams: true,
+			},
 		},
-		Streams: []grpc.StreamDesc{},
 	}
 )
 
@@ -95,6 +155,29 @@ func handlerRequestShutdown( // nolint: golint
 	return interceptor(ctx, wait, info, handler)
 }
 
+func handlerRequestShutdownAtEpoch( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req ShutdownRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).RequestShutdownAtEpoch(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRequestShutdownAtEpoch.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).RequestShutdownAtEpoch(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func handlerWaitSync( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -232,6 +315,183 @@ func handlerGetStatus( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetMempool( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetMempool(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetMempool.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetMempool(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetConsensusPeers( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetConsensusPeers(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetConsensusPeers.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetConsensusPeers(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerBanConsensusPeer( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req consensus.BanPeerRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).BanConsensusPeer(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodBanConsensusPeer.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).BanConsensusPeer(ctx, req.(*consensus.BanPeerRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerUnbanConsensusPeer( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var id string
+	if err := dec(&id); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).UnbanConsensusPeer(ctx, id)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodUnbanConsensusPeer.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).UnbanConsensusPeer(ctx, req.(string))
+	}
+	return interceptor(ctx, id, info, handler)
+}
+
+func handlerUpdatePersistentPeers( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req consensus.PersistentPeersUpdate
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).UpdatePersistentPeers(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodUpdatePersistentPeers.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).UpdatePersistentPeers(ctx, req.(*consensus.PersistentPeersUpdate))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerRestartSubsystem( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var req RestartSubsystemRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).RestartSubsystem(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRestartSubsystem.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(NodeController).RestartSubsystem(ctx, req.(*RestartSubsystemRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerGetEpochSnapshots( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetEpochSnapshots(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEpochSnapshots.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetEpochSnapshots(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerWatchEvents(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(NodeController).WatchEvents(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -245,6 +505,10 @@ func (c *nodeControllerClient) RequestShutdown(ctx context.Context, wait bool) e
 	return c.conn.Invoke(ctx, methodRequestShutdown.FullName(), wait, nil)
 }
 
+func (c *nodeControllerClient) RequestShutdownAtEpoch(ctx context.Context, req *ShutdownRequest) error {
+	return c.conn.Invoke(ctx, methodRequestShutdownAtEpoch.FullName(), req, nil)
+}
+
 func (c *nodeControllerClient) WaitSync(ctx context.Context) error {
 	return c.conn.Invoke(ctx, methodWaitSync.FullName(), nil, nil)
 }
@@ -285,6 +549,85 @@ func (c *nodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
 	return &rsp, nil
 }
 
+func (c *nodeControllerClient) GetMempool(ctx context.Context) (*MempoolStatus, error) {
+	var rsp MempoolStatus
+	if err := c.conn.Invoke(ctx, methodGetMempool.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *nodeControllerClient) GetConsensusPeers(ctx context.Context) ([]*consensus.PeerStatus, error) {
+	var rsp []*consensus.PeerStatus
+	if err := c.conn.Invoke(ctx, methodGetConsensusPeers.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) BanConsensusPeer(ctx context.Context, req *consensus.BanPeerRequest) error {
+	return c.conn.Invoke(ctx, methodBanConsensusPeer.FullName(), req, nil)
+}
+
+func (c *nodeControllerClient) UnbanConsensusPeer(ctx context.Context, id string) error {
+	return c.conn.Invoke(ctx, methodUnbanConsensusPeer.FullName(), id, nil)
+}
+
+func (c *nodeControllerClient) UpdatePersistentPeers(ctx context.Context, req *consensus.PersistentPeersUpdate) ([]string, error) {
+	var rsp []string
+	if err := c.conn.Invoke(ctx, methodUpdatePersistentPeers.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) RestartSubsystem(ctx context.Context, req *RestartSubsystemRequest) error {
+	return c.conn.Invoke(ctx, methodRestartSubsystem.FullName(), req, nil)
+}
+
+func (c *nodeControllerClient) GetEpochSnapshots(ctx context.Context) (map[common.Namespace]*commonWorker.EpochSnapshot, error) {
+	var rsp map[common.Namespace]*commonWorker.EpochSnapshot
+	if err := c.conn.Invoke(ctx, methodGetEpochSnapshots.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *nodeControllerClient) WatchEvents(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], methodWatchEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *NodeEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev NodeEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // NewNodeControllerClient creates a new gRPC node controller client service.
 func NewNodeControllerClient(c *grpc.ClientConn) NodeController {
 	return &nodeControllerClient{c}
@@ -5,16 +5,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
 )
 
 type nodeController struct {
+	logger      *logging.Logger
+	auditLogger *logging.Logger
+
 	node      control.ControlledNode
 	consensus consensus.Backend
 	upgrader  upgrade.Backend
+
+	events *eventBridge
 }
 
 func (c *nodeController) RequestShutdown(ctx context.Context, wait bool) error {
@@ -33,6 +47,59 @@ func (c *nodeController) RequestShutdown(ctx context.Context, wait bool) error {
 	return nil
 }
 
+func (c *nodeController) RequestShutdownAtEpoch(ctx context.Context, req *control.ShutdownRequest) error {
+	epoch, err := c.consensus.EpochTime().GetEpoch(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+	if epoch >= req.Epoch {
+		return c.RequestShutdown(ctx, req.Wait)
+	}
+
+	c.logger.Info("scheduling shutdown",
+		"current_epoch", epoch,
+		"shutdown_epoch", req.Epoch,
+	)
+
+	ch, sub := c.consensus.EpochTime().WatchEpochs()
+	if !req.Wait {
+		go func() {
+			defer sub.Close()
+
+			for epoch := range ch {
+				if epoch < req.Epoch {
+					continue
+				}
+				// Use a fresh context as the request context is gone by now.
+				if err := c.RequestShutdown(context.Background(), false); err != nil {
+					c.logger.Error("failed to request scheduled shutdown",
+						"err", err,
+						"epoch", epoch,
+					)
+				}
+				return
+			}
+		}()
+		return nil
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case epoch, ok := <-ch:
+			if !ok {
+				return context.Canceled
+			}
+			if epoch < req.Epoch {
+				continue
+			}
+			return c.RequestShutdown(ctx, true)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func (c *nodeController) WaitSync(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -113,11 +180,166 @@ func (c *nodeController) GetStatus(ctx context.Context) (*control.Status, error)
 	}, nil
 }
 
+func (c *nodeController) GetMempool(ctx context.Context) (*control.MempoolStatus, error) {
+	txs, err := c.consensus.GetUnconfirmedTransactions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unconfirmed transactions: %w", err)
+	}
+
+	status := &control.MempoolStatus{
+		Transactions: []*control.MempoolTransaction{},
+		MethodCounts: make(map[transaction.MethodName]uint64),
+	}
+	for _, raw := range txs {
+		var sigTx transaction.SignedTransaction
+		if err = cbor.Unmarshal(raw, &sigTx); err != nil {
+			status.InvalidCount++
+			continue
+		}
+		var tx transaction.Transaction
+		if err = sigTx.Open(&tx); err != nil {
+			status.InvalidCount++
+			continue
+		}
+
+		mtx := &control.MempoolTransaction{
+			Hash:   sigTx.Hash(),
+			Size:   uint64(len(raw)),
+			Method: tx.Method,
+			Signer: sigTx.Signature.PublicKey,
+			Sender: staking.NewAddress(sigTx.Signature.PublicKey),
+			Nonce:  tx.Nonce,
+			Fee:    tx.Fee,
+		}
+		if tx.Fee != nil {
+			mtx.GasPrice = tx.Fee.GasPrice()
+		}
+
+		status.Transactions = append(status.Transactions, mtx)
+		status.MethodCounts[tx.Method]++
+	}
+
+	return status, nil
+}
+
+func (c *nodeController) peerManager() (consensus.PeerManager, error) {
+	pm := c.consensus.PeerManager()
+	if pm == nil {
+		return nil, consensus.ErrUnsupported
+	}
+	return pm, nil
+}
+
+func (c *nodeController) GetConsensusPeers(ctx context.Context) ([]*consensus.PeerStatus, error) {
+	pm, err := c.peerManager()
+	if err != nil {
+		return nil, err
+	}
+	return pm.GetPeers(ctx)
+}
+
+func (c *nodeController) BanConsensusPeer(ctx context.Context, req *consensus.BanPeerRequest) error {
+	pm, err := c.peerManager()
+	if err != nil {
+		return err
+	}
+	if err = pm.BanPeer(ctx, req); err != nil {
+		c.auditLogger.Warn("failed to ban consensus peer",
+			"err", err,
+			"peer_id", req.ID,
+			"reason", req.Reason,
+			"duration", req.Duration,
+		)
+		return err
+	}
+
+	c.auditLogger.Info("banned consensus peer",
+		"peer_id", req.ID,
+		"reason", req.Reason,
+		"duration", req.Duration,
+	)
+	return nil
+}
+
+func (c *nodeController) UnbanConsensusPeer(ctx context.Context, id string) error {
+	pm, err := c.peerManager()
+	if err != nil {
+		return err
+	}
+	if err = pm.UnbanPeer(ctx, id); err != nil {
+		c.auditLogger.Warn("failed to unban consensus peer",
+			"err", err,
+			"peer_id", id,
+		)
+		return err
+	}
+
+	c.auditLogger.Info("unbanned consensus peer",
+		"peer_id", id,
+	)
+	return nil
+}
+
+func (c *nodeController) UpdatePersistentPeers(ctx context.Context, req *consensus.PersistentPeersUpdate) ([]string, error) {
+	pm, err := c.peerManager()
+	if err != nil {
+		return nil, err
+	}
+	peers, err := pm.UpdatePersistentPeers(ctx, req)
+	if err != nil {
+		c.auditLogger.Warn("failed to update persistent consensus peers",
+			"err", err,
+			"added", req.Add,
+			"removed", req.Remove,
+		)
+		return nil, err
+	}
+
+	c.auditLogger.Info("updated persistent consensus peers",
+		"added", req.Add,
+		"removed", req.Remove,
+		"persistent_peers", peers,
+	)
+	return peers, nil
+}
+
+func (c *nodeController) RestartSubsystem(ctx context.Context, req *control.RestartSubsystemRequest) error {
+	if err := req.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := c.node.RestartSubsystem(ctx, req); err != nil {
+		c.auditLogger.Warn("failed to restart subsystem",
+			"err", err,
+			"subsystem", req.Subsystem,
+			"runtime_id", req.RuntimeID,
+		)
+		return err
+	}
+
+	c.auditLogger.Info("restarted subsystem",
+		"subsystem", req.Subsystem,
+		"runtime_id", req.RuntimeID,
+	)
+	return nil
+}
+
+func (c *nodeController) GetEpochSnapshots(ctx context.Context) (map[common.Namespace]*commonWorker.EpochSnapshot, error) {
+	return c.node.GetEpochSnapshots(ctx)
+}
+
+func (c *nodeController) WatchEvents(ctx context.Context) (<-chan *control.NodeEvent, pubsub.ClosableSubscription, error) {
+	ch, sub := c.events.watch()
+	return ch, sub, nil
+}
+
 // New creates a new oasis-node controller.
 func New(node control.ControlledNode, consensus consensus.Backend, upgrader upgrade.Backend) control.NodeController {
 	return &nodeController{
-		node:      node,
-		consensus: consensus,
-		upgrader:  upgrader,
+		logger:      logging.GetLogger("control"),
+		auditLogger: logging.GetLogger("control/audit"),
+		node:        node,
+		consensus:   consensus,
+		upgrader:    upgrader,
+		events:      newEventBridge(viper.GetString(CfgEventsWebhookURL), viper.GetDuration(CfgEventsWebhookTimeout)),
 	}
 }
@@ -0,0 +1,84 @@
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+type testConsensusBackend struct {
+	consensus.Backend
+
+	unconfirmed [][]byte
+}
+
+func (b *testConsensusBackend) GetUnconfirmedTransactions(ctx context.Context) ([][]byte, error) {
+	return b.unconfirmed, nil
+}
+
+func TestGetMempool(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-core tests")
+
+	backend := &testConsensusBackend{}
+	ctrl := New(nil, backend, nil)
+
+	// An empty mempool should be reported as such.
+	status, err := ctrl.GetMempool(context.Background())
+	require.NoError(err, "GetMempool")
+	require.Empty(status.Transactions, "mempool should be empty")
+	require.Empty(status.MethodCounts, "mempool should be empty")
+	require.EqualValues(0, status.InvalidCount, "mempool should be empty")
+
+	// Submitted but unconfirmed transactions should show up in the mempool.
+	signer := memorySigner.NewTestSigner("control: mempool test signer")
+	fee := &transaction.Fee{
+		Amount: *quantity.NewFromUint64(100),
+		Gas:    10,
+	}
+	tx := transaction.NewTransaction(42, fee, staking.MethodTransfer, &staking.Transfer{})
+	sigTx, err := transaction.Sign(signer, tx)
+	require.NoError(err, "Sign")
+	rawTx := cbor.Marshal(sigTx)
+
+	noFeeTx := transaction.NewTransaction(43, nil, staking.MethodTransfer, &staking.Transfer{})
+	noFeeSigTx, err := transaction.Sign(signer, noFeeTx)
+	require.NoError(err, "Sign")
+
+	backend.unconfirmed = [][]byte{
+		rawTx,
+		cbor.Marshal(noFeeSigTx),
+		[]byte("not a transaction"),
+	}
+
+	status, err = ctrl.GetMempool(context.Background())
+	require.NoError(err, "GetMempool")
+	require.Len(status.Transactions, 2, "decodable transactions should be listed")
+	require.EqualValues(1, status.InvalidCount, "undecodable transactions should be counted")
+	require.EqualValues(map[transaction.MethodName]uint64{staking.MethodTransfer: 2}, status.MethodCounts)
+
+	mtx := status.Transactions[0]
+	require.Equal(sigTx.Hash(), mtx.Hash, "Hash")
+	require.EqualValues(len(rawTx), mtx.Size, "Size")
+	require.Equal(staking.MethodTransfer, mtx.Method, "Method")
+	require.Equal(signer.Public(), mtx.Signer, "Signer")
+	require.Equal(staking.NewAddress(signer.Public()), mtx.Sender, "Sender")
+	require.EqualValues(42, mtx.Nonce, "Nonce")
+	require.Equal(fee, mtx.Fee, "Fee")
+	require.Equal(quantity.NewFromUint64(10), mtx.GasPrice, "GasPrice")
+
+	mtx = status.Transactions[1]
+	require.EqualValues(43, mtx.Nonce, "Nonce")
+	require.Nil(mtx.Fee, "Fee")
+	require.Nil(mtx.GasPrice, "GasPrice should not be set without a fee")
+}
@@ -0,0 +1,147 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	storageCommittee "github.com/oasisprotocol/oasis-core/go/worker/storage/committee"
+)
+
+const (
+	// CfgEventsWebhookURL configures the URL that node events are posted to.
+	CfgEventsWebhookURL = "control.events.webhook.url"
+	// CfgEventsWebhookTimeout configures the timeout for posting a node event.
+	CfgEventsWebhookTimeout = "control.events.webhook.timeout"
+
+	// eventsWebhookQueueSize is the number of node events that can be pending
+	// delivery to the webhook before further events are dropped.
+	eventsWebhookQueueSize = 128
+)
+
+// Flags has the configuration flags.
+var Flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// CriticalLogEvents are the log events that are converted into node events.
+var CriticalLogEvents = []string{
+	storageCommittee.LogEventCheckpointSyncSuccess,
+	roothash.LogEventRoundFailed,
+	roothash.LogEventExecutionDiscrepancyDetected,
+	beacon.LogEventVRFProofFailed,
+	beacon.LogEventInsufficientVRFProofs,
+}
+
+type eventBridge struct {
+	logger *logging.Logger
+
+	notifier *pubsub.Broker
+
+	webhookURL     string
+	webhookTimeout time.Duration
+	webhookClient  *http.Client
+	webhookCh      chan *control.NodeEvent
+}
+
+func (b *eventBridge) onLogEvent(ev *logging.Event) {
+	nev := &control.NodeEvent{
+		Kind:      ev.Event,
+		Module:    ev.Module,
+		Level:     ev.Level,
+		Message:   ev.Message,
+		Timestamp: ev.Timestamp,
+		Fields:    ev.Fields,
+	}
+	b.notifier.Broadcast(nev)
+
+	if b.webhookCh == nil {
+		return
+	}
+	select {
+	case b.webhookCh <- nev:
+	default:
+		b.logger.Warn("node event webhook queue full, dropping event",
+			"kind", nev.Kind,
+		)
+	}
+}
+
+func (b *eventBridge) watch() (<-chan *control.NodeEvent, pubsub.ClosableSubscription) {
+	typedCh := make(chan *control.NodeEvent)
+	sub := b.notifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
+func (b *eventBridge) webhookWorker() {
+	for ev := range b.webhookCh {
+		if err := b.postWebhook(ev); err != nil {
+			b.logger.Error("failed to deliver node event to webhook",
+				"err", err,
+				"kind", ev.Kind,
+			)
+		}
+	}
+}
+
+func (b *eventBridge) postWebhook(ev *control.NodeEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := b.webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", rsp.StatusCode)
+	}
+	return nil
+}
+
+func newEventBridge(webhookURL string, webhookTimeout time.Duration) *eventBridge {
+	b := &eventBridge{
+		logger:         logging.GetLogger("control/events"),
+		notifier:       pubsub.NewBroker(false),
+		webhookURL:     webhookURL,
+		webhookTimeout: webhookTimeout,
+		webhookClient:  &http.Client{},
+	}
+	if webhookURL != "" {
+		b.webhookCh = make(chan *control.NodeEvent, eventsWebhookQueueSize)
+		go b.webhookWorker()
+	}
+	logging.RegisterEventHandler(CriticalLogEvents, b.onLogEvent)
+
+	return b
+}
+
+func init() {
+	Flags.String(CfgEventsWebhookURL, "", "URL to post node events derived from critical log events to")
+	Flags.Duration(CfgEventsWebhookTimeout, 10*time.Second, "timeout for posting a node event to the webhook")
+
+	_ = viper.BindPFlags(Flags)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 )
@@ -19,6 +20,11 @@ type EpochTime uint64
 // EpochInvalid is the placeholder invalid epoch.
 const EpochInvalid EpochTime = 0xffffffffffffffff // ~50 quadrillion years away.
 
+// ErrTransitionNotScheduled is the error returned when the height of the next
+// epoch transition cannot be determined as the backend does not schedule epoch
+// transitions (e.g., the mock backend).
+var ErrTransitionNotScheduled = errors.New(ModuleName, 1, "epochtime: epoch transition not scheduled")
+
 // Backend is a timekeeping implementation.
 type Backend interface {
 	// GetBaseEpoch returns the base epoch.
@@ -33,6 +39,16 @@ type Backend interface {
 	// epoch.
 	GetEpochBlock(context.Context, EpochTime) (int64, error)
 
+	// GetNextEpochBlock returns the expected block height at the start of
+	// the epoch following the epoch of the specified block height, under the
+	// current backend parameters.
+	// Calling this method with height `0`, should return the expected start
+	// of the epoch following the epoch of the latest known block.
+	//
+	// In case the backend does not schedule epoch transitions, this method
+	// returns ErrTransitionNotScheduled.
+	GetNextEpochBlock(context.Context, int64) (int64, error)
+
 	// WatchEpochs returns a channel that produces a stream of messages
 	// on epoch transitions.
 	//
@@ -26,6 +26,9 @@ func EpochtimeSetableImplementationTest(t *testing.T, backend api.Backend) {
 	epoch, err := timeSource.GetEpoch(context.Background(), consensus.HeightLatest)
 	require.NoError(err, "GetEpoch")
 
+	_, err = timeSource.GetNextEpochBlock(context.Background(), consensus.HeightLatest)
+	require.Equal(api.ErrTransitionNotScheduled, err, "GetNextEpochBlock should fail for mock backend")
+
 	var e api.EpochTime
 
 	ch, sub := timeSource.WatchEpochs()
@@ -19,10 +19,19 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-const filePerm = 0o600
+const (
+	filePerm = 0o600
+
+	// LatestDocumentVersion is the latest genesis document version. Documents with older versions
+	// must be migrated (see the genesis/migrations package) before they can be used.
+	LatestDocumentVersion = 1
+)
 
 // Document is a genesis document.
 type Document struct {
+	// Version is the genesis document version. Documents without a version are considered to be
+	// version 0.
+	Version uint64 `json:"version,omitempty"`
 	// Height is the block height at which the document was generated.
 	Height int64 `json:"height"`
 	// Time is the time the genesis block was constructed.
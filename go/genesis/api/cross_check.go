@@ -0,0 +1,366 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// ViolationCode is a stable identifier of a cross-module referential integrity violation.
+type ViolationCode string
+
+const (
+	// ViolationSanityCheckFailed is reported when the per-module sanity checks fail.
+	ViolationSanityCheckFailed ViolationCode = "sanity_check_failed"
+	// ViolationNotCanonical is reported when the document is not in the canonical form.
+	ViolationNotCanonical ViolationCode = "not_canonical"
+	// ViolationMalformedDescriptor is reported when a registry descriptor cannot be decoded.
+	ViolationMalformedDescriptor ViolationCode = "malformed_descriptor"
+	// ViolationNodeEntityMissing is reported when a node references a missing entity.
+	ViolationNodeEntityMissing ViolationCode = "node_entity_missing"
+	// ViolationNodeRuntimeMissing is reported when a node references a missing runtime.
+	ViolationNodeRuntimeMissing ViolationCode = "node_runtime_missing"
+	// ViolationRuntimeEntityMissing is reported when a runtime references a missing entity.
+	ViolationRuntimeEntityMissing ViolationCode = "runtime_entity_missing"
+	// ViolationRuntimeKeyManagerMissing is reported when a runtime references a missing key
+	// manager runtime.
+	ViolationRuntimeKeyManagerMissing ViolationCode = "runtime_key_manager_missing"
+	// ViolationRuntimeKeyManagerInvalid is reported when a runtime references a key manager
+	// runtime which is not of the key manager kind.
+	ViolationRuntimeKeyManagerInvalid ViolationCode = "runtime_key_manager_invalid"
+	// ViolationKeyManagerRuntimeMissing is reported when a key manager status references a
+	// missing key manager runtime.
+	ViolationKeyManagerRuntimeMissing ViolationCode = "key_manager_runtime_missing"
+	// ViolationKeyManagerNodeMissing is reported when a key manager status references a
+	// missing node.
+	ViolationKeyManagerNodeMissing ViolationCode = "key_manager_node_missing"
+	// ViolationEscrowClaimInvalid is reported when a stake claim does not have a known form.
+	ViolationEscrowClaimInvalid ViolationCode = "escrow_claim_invalid"
+	// ViolationEscrowClaimEntityMissing is reported when a stake claim references a missing
+	// entity.
+	ViolationEscrowClaimEntityMissing ViolationCode = "escrow_claim_entity_missing"
+	// ViolationEscrowClaimNodeMissing is reported when a stake claim references a node that
+	// is missing or is not owned by the account's entity.
+	ViolationEscrowClaimNodeMissing ViolationCode = "escrow_claim_node_missing"
+	// ViolationEscrowClaimRuntimeMissing is reported when a stake claim references a runtime
+	// that is missing or is not owned by the account's entity.
+	ViolationEscrowClaimRuntimeMissing ViolationCode = "escrow_claim_runtime_missing"
+	// ViolationSchedulerInsufficientValidators is reported when the scheduler would not be
+	// able to elect the minimum number of validators from the genesis nodes and stake.
+	ViolationSchedulerInsufficientValidators ViolationCode = "scheduler_insufficient_validators"
+)
+
+// Violation is a single violation found while checking a genesis document.
+type Violation struct {
+	// Code is the stable violation code.
+	Code ViolationCode `json:"code"`
+	// Module is the name of the module that contains the offending entry.
+	Module string `json:"module"`
+	// Message is a human readable description of the violation.
+	Message string `json:"message"`
+}
+
+// String returns a string representation of the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Module, v.Message, v.Code)
+}
+
+// CheckReport is a machine-readable report of genesis document violations.
+type CheckReport struct {
+	// Violations is the list of all found violations.
+	Violations []Violation `json:"violations"`
+}
+
+// AddViolation adds a new violation to the report.
+func (r *CheckReport) AddViolation(code ViolationCode, module, format string, a ...interface{}) {
+	r.Violations = append(r.Violations, Violation{
+		Code:    code,
+		Module:  module,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// IsValid returns true iff the report contains no violations.
+func (r *CheckReport) IsValid() bool {
+	return len(r.Violations) == 0
+}
+
+// CrossCheck verifies referential integrity between the genesis states of the
+// different modules and returns a report of all found violations.
+//
+// In contrast to SanityCheck it does not stop at the first error and it does
+// not verify descriptor signatures, so it should be used in addition to it.
+func (d *Document) CrossCheck() *CheckReport { // nolint: gocyclo
+	var report CheckReport
+
+	// Decode registry descriptors.
+	entities := make(map[signature.PublicKey]*entity.Entity)
+	for _, se := range d.Registry.Entities {
+		var ent entity.Entity
+		if err := cbor.Unmarshal(se.Blob, &ent); err != nil {
+			report.AddViolation(ViolationMalformedDescriptor, registry.ModuleName, "malformed entity descriptor: %s", err)
+			continue
+		}
+		entities[ent.ID] = &ent
+	}
+
+	runtimes := make(map[common.Namespace]*registry.Runtime)
+	var runtimeList []*registry.Runtime
+	for _, srts := range [][]*registry.SignedRuntime{d.Registry.Runtimes, d.Registry.SuspendedRuntimes} {
+		for _, srt := range srts {
+			var rt registry.Runtime
+			if err := cbor.Unmarshal(srt.Blob, &rt); err != nil {
+				report.AddViolation(ViolationMalformedDescriptor, registry.ModuleName, "malformed runtime descriptor: %s", err)
+				continue
+			}
+			runtimes[rt.ID] = &rt
+			runtimeList = append(runtimeList, &rt)
+		}
+	}
+
+	nodes := make(map[signature.PublicKey]*node.Node)
+	var nodeList []*node.Node
+	for _, sn := range d.Registry.Nodes {
+		var n node.Node
+		if err := cbor.Unmarshal(sn.Blob, &n); err != nil {
+			report.AddViolation(ViolationMalformedDescriptor, registry.ModuleName, "malformed node descriptor: %s", err)
+			continue
+		}
+		nodes[n.ID] = &n
+		nodeList = append(nodeList, &n)
+	}
+
+	// Registry: nodes and runtimes must reference existing entities and runtimes.
+	for _, n := range nodeList {
+		if _, ok := entities[n.EntityID]; !ok {
+			report.AddViolation(ViolationNodeEntityMissing, registry.ModuleName,
+				"node %s references a missing entity %s", n.ID, n.EntityID,
+			)
+		}
+		for _, nrt := range n.Runtimes {
+			if _, ok := runtimes[nrt.ID]; !ok {
+				report.AddViolation(ViolationNodeRuntimeMissing, registry.ModuleName,
+					"node %s references a missing runtime %s", n.ID, nrt.ID,
+				)
+			}
+		}
+	}
+	for _, rt := range runtimeList {
+		if _, ok := entities[rt.EntityID]; !ok {
+			report.AddViolation(ViolationRuntimeEntityMissing, registry.ModuleName,
+				"runtime %s references a missing entity %s", rt.ID, rt.EntityID,
+			)
+		}
+		if rt.KeyManager == nil {
+			continue
+		}
+		km, ok := runtimes[*rt.KeyManager]
+		switch {
+		case !ok:
+			report.AddViolation(ViolationRuntimeKeyManagerMissing, registry.ModuleName,
+				"runtime %s references a missing key manager runtime %s", rt.ID, rt.KeyManager,
+			)
+		case km.Kind != registry.KindKeyManager:
+			report.AddViolation(ViolationRuntimeKeyManagerInvalid, registry.ModuleName,
+				"runtime %s references runtime %s which is not a key manager", rt.ID, rt.KeyManager,
+			)
+		}
+	}
+
+	// Key manager: statuses must reference existing key manager runtimes and nodes.
+	for _, st := range d.KeyManager.Statuses {
+		if st == nil {
+			continue
+		}
+		if rt, ok := runtimes[st.ID]; !ok || rt.Kind != registry.KindKeyManager {
+			report.AddViolation(ViolationKeyManagerRuntimeMissing, keymanager.ModuleName,
+				"status references a missing key manager runtime %s", st.ID,
+			)
+		}
+		for _, id := range st.Nodes {
+			if _, ok := nodes[id]; !ok {
+				report.AddViolation(ViolationKeyManagerNodeMissing, keymanager.ModuleName,
+					"status of key manager %s references a missing node %s", st.ID, id,
+				)
+			}
+		}
+	}
+
+	// Staking: escrow stake claims must reference registered entities, nodes and runtimes
+	// owned by the account.
+	entityByAddr := make(map[staking.Address]signature.PublicKey)
+	for id := range entities {
+		entityByAddr[staking.NewAddress(id)] = id
+	}
+	claims := make(map[staking.StakeClaim]func(entityID signature.PublicKey) bool)
+	for _, n := range nodeList {
+		n := n
+		claims[registry.StakeClaimForNode(n.ID)] = func(id signature.PublicKey) bool { return n.EntityID.Equal(id) }
+	}
+	for _, rt := range runtimeList {
+		rt := rt
+		claims[registry.StakeClaimForRuntime(rt.ID)] = func(id signature.PublicKey) bool { return rt.EntityID.Equal(id) }
+	}
+	for _, addr := range sortedAddresses(d.Staking.Ledger) {
+		acct := d.Staking.Ledger[addr]
+		if acct == nil {
+			continue
+		}
+		for _, claim := range sortedClaims(acct.Escrow.StakeAccumulator.Claims) {
+			entityID, isEntity := entityByAddr[addr]
+			switch {
+			case claim == registry.StakeClaimRegisterEntity:
+				if !isEntity {
+					report.AddViolation(ViolationEscrowClaimEntityMissing, staking.ModuleName,
+						"account %s has claim %s but is not a registered entity", addr, claim,
+					)
+				}
+			case claims[claim] != nil:
+				if isEntity && claims[claim](entityID) {
+					continue
+				}
+				code := ViolationEscrowClaimNodeMissing
+				if isRuntimeClaim(claim) {
+					code = ViolationEscrowClaimRuntimeMissing
+				}
+				report.AddViolation(code, staking.ModuleName,
+					"account %s has claim %s for a descriptor owned by a different entity", addr, claim,
+				)
+			case isNodeClaim(claim):
+				report.AddViolation(ViolationEscrowClaimNodeMissing, staking.ModuleName,
+					"account %s has claim %s for a missing node", addr, claim,
+				)
+			case isRuntimeClaim(claim):
+				report.AddViolation(ViolationEscrowClaimRuntimeMissing, staking.ModuleName,
+					"account %s has claim %s for a missing runtime", addr, claim,
+				)
+			default:
+				report.AddViolation(ViolationEscrowClaimInvalid, staking.ModuleName,
+					"account %s has unknown claim %s", addr, claim,
+				)
+			}
+		}
+	}
+
+	// Scheduler: it must be possible to elect the minimum number of validators.
+	d.crossCheckValidators(&report, nodeList, runtimes)
+
+	return &report
+}
+
+func (d *Document) crossCheckValidators(
+	report *CheckReport,
+	nodeList []*node.Node,
+	runtimes map[common.Namespace]*registry.Runtime,
+) {
+	params := d.Scheduler.Parameters
+	if params.DebugStaticValidators {
+		return
+	}
+	bypassStake := params.DebugBypassStake || d.Registry.Parameters.DebugBypassStake
+
+	// Generate the stake claims the same way as the registry would and count the
+	// validator nodes of entities that satisfy all of their claims.
+	escrows := make(map[staking.Address]*staking.EscrowAccount)
+	getEscrow := func(id signature.PublicKey) *staking.EscrowAccount {
+		addr := staking.NewAddress(id)
+		if escrow, ok := escrows[addr]; ok {
+			return escrow
+		}
+		escrow := &staking.EscrowAccount{}
+		if acct, ok := d.Staking.Ledger[addr]; ok && acct != nil {
+			escrow.Active.Balance = acct.Escrow.Active.Balance
+		}
+		escrow.StakeAccumulator.AddClaimUnchecked(registry.StakeClaimRegisterEntity, staking.GlobalStakeThresholds(staking.KindEntity))
+		escrows[addr] = escrow
+		return escrow
+	}
+	for _, n := range nodeList {
+		var nodeRts []*registry.Runtime
+		for _, nrt := range n.Runtimes {
+			if rt, ok := runtimes[nrt.ID]; ok {
+				nodeRts = append(nodeRts, rt)
+			}
+		}
+		getEscrow(n.EntityID).StakeAccumulator.AddClaimUnchecked(registry.StakeClaimForNode(n.ID), registry.StakeThresholdsForNode(n, nodeRts))
+	}
+	for _, rt := range runtimes {
+		getEscrow(rt.EntityID).StakeAccumulator.AddClaimUnchecked(registry.StakeClaimForRuntime(rt.ID), registry.StakeThresholdsForRuntime(rt))
+	}
+
+	perEntity := make(map[staking.Address]int)
+	for _, n := range nodeList {
+		if !n.HasRoles(node.RoleValidator) || n.IsExpired(uint64(d.EpochTime.Base)) {
+			continue
+		}
+		if status := d.Registry.NodeStatuses[n.ID]; status != nil && (status.IsFrozen() || status.IsSuspended()) {
+			continue
+		}
+		addr := staking.NewAddress(n.EntityID)
+		if !bypassStake {
+			if err := getEscrow(n.EntityID).CheckStakeClaims(d.Staking.Parameters.Thresholds); err != nil {
+				continue
+			}
+		}
+		perEntity[addr]++
+	}
+
+	var electable int
+	for _, count := range perEntity {
+		if count > params.MaxValidatorsPerEntity {
+			count = params.MaxValidatorsPerEntity
+		}
+		electable += count
+	}
+	if electable > params.MaxValidators {
+		electable = params.MaxValidators
+	}
+	if electable < params.MinValidators {
+		report.AddViolation(ViolationSchedulerInsufficientValidators, scheduler.ModuleName,
+			"only %d validators can be elected (minimum: %d)", electable, params.MinValidators,
+		)
+	}
+}
+
+func sortedAddresses(ledger map[staking.Address]*staking.Account) []staking.Address {
+	addrs := make([]staking.Address, 0, len(ledger))
+	for addr := range ledger {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+	return addrs
+}
+
+func sortedClaims(claims map[staking.StakeClaim][]staking.StakeThreshold) []staking.StakeClaim {
+	sorted := make([]staking.StakeClaim, 0, len(claims))
+	for claim := range claims {
+		sorted = append(sorted, claim)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+func isNodeClaim(claim staking.StakeClaim) bool {
+	var id string
+	_, err := fmt.Sscanf(string(claim), registry.StakeClaimRegisterNode, &id)
+	return err == nil
+}
+
+func isRuntimeClaim(claim staking.StakeClaim) bool {
+	var id string
+	_, err := fmt.Sscanf(string(claim), registry.StakeClaimRegisterRuntime, &id)
+	return err == nil
+}
@@ -9,6 +9,19 @@ import (
 
 // SanityCheck does basic sanity checking on the contents of the genesis document.
 func (d *Document) SanityCheck() error {
+	switch {
+	case d.Version < LatestDocumentVersion:
+		return fmt.Errorf("genesis: sanity check failed: document version %d requires migration to version %d",
+			d.Version,
+			LatestDocumentVersion,
+		)
+	case d.Version > LatestDocumentVersion:
+		return fmt.Errorf("genesis: sanity check failed: unsupported document version %d (latest: %d)",
+			d.Version,
+			LatestDocumentVersion,
+		)
+	}
+
 	if d.Height < 1 {
 		return fmt.Errorf("genesis: sanity check failed: height must be >= 1")
 	}
@@ -43,7 +56,7 @@ func (d *Document) SanityCheck() error {
 	if err := d.Scheduler.SanityCheck(&d.Staking.TotalSupply); err != nil {
 		return err
 	}
-	if err := d.Beacon.SanityCheck(); err != nil {
+	if err := d.Beacon.SanityCheck(&d.EpochTime.Parameters); err != nil {
 		return err
 	}
 
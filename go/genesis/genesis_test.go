@@ -39,6 +39,7 @@ import (
 // the node that is spun up as part of the tests, you really want
 // consensus/tendermint/tests/genesis/genesis.go.
 var testDoc = &genesis.Document{
+	Version:   genesis.LatestDocumentVersion,
 	Height:    1,
 	ChainID:   genesisTestHelpers.TestChainID,
 	Time:      time.Unix(1574858284, 0),
@@ -134,7 +135,7 @@ func TestGenesisChainContext(t *testing.T) {
 	//       on each run.
 	stableDoc.Staking = staking.Genesis{}
 
-	require.Equal(t, "5a67a9135f2ef388205023fd525b920852126603fafed80dbf1a20c0e41b3372", stableDoc.ChainContext())
+	require.Equal(t, "de4738e356b70bbb21e63c4853a6546cbf68c34ba43e38741290e0e673c97eed", stableDoc.ChainContext())
 }
 
 func TestGenesisSanityCheck(t *testing.T) {
@@ -275,6 +276,14 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.Height = 0
 	require.Error(d.SanityCheck(), "height < 1 should be invalid")
 
+	d = *testDoc
+	d.Version = genesis.LatestDocumentVersion - 1
+	require.Error(d.SanityCheck(), "document requiring migration should be invalid")
+
+	d = *testDoc
+	d.Version = genesis.LatestDocumentVersion + 1
+	require.Error(d.SanityCheck(), "unsupported document version should be invalid")
+
 	d = *testDoc
 	d.ChainID = "   \t"
 	require.Error(d.SanityCheck(), "empty chain ID should be invalid")
@@ -305,6 +314,20 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.EpochTime.Parameters.DebugMockBackend = false
 	require.Error(d.SanityCheck(), "invalid epoch interval should be rejected")
 
+	// Test beacon genesis checks.
+	d = *testDoc
+	d.Beacon.Parameters.PendingEpochNotice = 1
+	require.Error(d.SanityCheck(), "pending epoch notice with mock epochtime should be rejected")
+
+	d = *testDoc
+	d.EpochTime.Parameters.Interval = 10
+	d.EpochTime.Parameters.DebugMockBackend = false
+	d.Beacon.Parameters.PendingEpochNotice = 10
+	require.Error(d.SanityCheck(), "pending epoch notice not less than the epoch interval should be rejected")
+
+	d.Beacon.Parameters.PendingEpochNotice = 9
+	require.NoError(d.SanityCheck(), "valid pending epoch notice should be accepted")
+
 	// Test keymanager genesis checks.
 	d = *testDoc
 	d.KeyManager = keymanager.Genesis{
@@ -404,6 +427,31 @@ func TestGenesisSanityCheck(t *testing.T) {
 	require.NoError(rtsSanityCheck(d.RootHash, false), "non-empty StateRoot with non-empty State and all valid StorageReceipts should pass")
 	require.NoError(rtsSanityCheck(d.RootHash, true), "non-empty StateRoot with non-empty State and all valid StorageReceipts should pass, if isGenesis=true")
 
+	d.RootHash.RuntimeStates = make(map[common.Namespace]*registry.RuntimeGenesis)
+	d.RootHash.RuntimeStates[validNS] = &registry.RuntimeGenesis{
+		StateRoot: nonEmptyHash,
+		StateCheckpoint: &registry.RuntimeGenesisCheckpoint{
+			Version: 1,
+			Chunks:  []hash.Hash{nonEmptyHash},
+		},
+	}
+	require.NoError(rtsSanityCheck(d.RootHash, false), "non-empty StateRoot with StateCheckpoint should pass")
+	require.NoError(rtsSanityCheck(d.RootHash, true), "non-empty StateRoot with StateCheckpoint should pass, if isGenesis=true")
+
+	d.RootHash.RuntimeStates[validNS].StateCheckpoint.Chunks = nil
+	require.Error(rtsSanityCheck(d.RootHash, false), "StateCheckpoint without chunks should be rejected")
+	require.Error(rtsSanityCheck(d.RootHash, true), "StateCheckpoint without chunks should be rejected, if isGenesis=true")
+
+	d.RootHash.RuntimeStates[validNS] = &registry.RuntimeGenesis{
+		State:     nonEmptyState,
+		StateRoot: nonEmptyHash,
+		StateCheckpoint: &registry.RuntimeGenesisCheckpoint{
+			Version: 1,
+			Chunks:  []hash.Hash{nonEmptyHash},
+		},
+	}
+	require.Error(rtsSanityCheck(d.RootHash, false), "non-empty State with StateCheckpoint should be rejected")
+
 	// Test registry genesis checks.
 	d = *testDoc
 	d.Registry.Entities = []*entity.SignedEntity{signedTestEntity}
@@ -468,6 +516,33 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.Registry.Nodes = []*node.MultiSignedNode{signedTestNode}
 	require.NoError(d.SanityCheck(), "entity with node should pass")
 
+	d.Registry.NodeStatuses = map[signature.PublicKey]*registry.NodeStatus{
+		testNode.ID: {Suspended: true},
+	}
+	require.NoError(d.SanityCheck(), "suspended node status should pass")
+
+	d.Registry.NodeStatuses = map[signature.PublicKey]*registry.NodeStatus{
+		testNode.ID: nil,
+	}
+	require.Error(d.SanityCheck(), "nil node status should be rejected")
+
+	d.Registry.NodeStatuses = map[signature.PublicKey]*registry.NodeStatus{
+		unknownPK: {Suspended: true},
+	}
+	require.Error(d.SanityCheck(), "node status for a missing node should be rejected")
+	d.Registry.NodeStatuses = nil
+
+	d.Registry.Parameters.MaxNodesPerEntity = 1
+	require.NoError(d.SanityCheck(), "entity within the per-entity node limit should pass")
+
+	d.Registry.Parameters.MaxNodesPerEntityRole = map[node.RolesMask]uint16{node.RoleValidator: 0}
+	require.Error(d.SanityCheck(), "entity exceeding the per-entity per-role node limit should be rejected")
+
+	d.Registry.Parameters.MaxNodesPerEntityRole = map[node.RolesMask]uint16{node.RoleValidator | node.RoleComputeWorker: 1}
+	require.Error(d.SanityCheck(), "per-entity node limit for multiple roles should be rejected")
+	d.Registry.Parameters.MaxNodesPerEntity = 0
+	d.Registry.Parameters.MaxNodesPerEntityRole = nil
+
 	d = *testDoc
 	te = *testEntity
 	te.Nodes = []signature.PublicKey{unknownPK}
@@ -743,3 +818,97 @@ func TestGenesisSanityCheck(t *testing.T) {
 	}
 	require.Error(d.SanityCheck(), "invalid debonding delegation should be rejected")
 }
+
+func TestGenesisCrossCheck(t *testing.T) {
+	require := require.New(t)
+
+	signer := memorySigner.NewTestSigner("genesis cross checks signer")
+	nodeSigner := memorySigner.NewTestSigner("node genesis cross checks signer")
+	unknownPK := memorySigner.NewTestSigner("unknown genesis cross checks signer").Public()
+
+	testEntity := &entity.Entity{
+		Versioned:              cbor.NewVersioned(entity.LatestEntityDescriptorVersion),
+		ID:                     signer.Public(),
+		AllowEntitySignedNodes: true,
+	}
+	signedTestEntity := signEntityOrDie(signer, testEntity)
+
+	kmRuntimeID := hex2ns("4000000000000000ffffffffffffffffffffffffffffffffffffffffffffffff", false)
+	testRuntimeID := hex2ns("0000000000000000000000000000000000000000000000000000000000000001", false)
+	testRuntime := &registry.Runtime{
+		Versioned:  cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+		ID:         testRuntimeID,
+		EntityID:   testEntity.ID,
+		Kind:       registry.KindCompute,
+		KeyManager: &kmRuntimeID,
+	}
+	signedTestRuntime := signRuntimeOrDie(signer, testRuntime)
+
+	testNode := &node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         nodeSigner.Public(),
+		EntityID:   testEntity.ID,
+		Expiration: 10,
+		Roles:      node.RoleValidator,
+		Runtimes: []*node.Runtime{
+			{ID: testRuntimeID},
+		},
+	}
+	signedTestNode := signNodeOrDie([]signature.Signer{nodeSigner}, testNode)
+
+	codes := func(report *genesis.CheckReport) (codes []genesis.ViolationCode) {
+		for _, v := range report.Violations {
+			codes = append(codes, v.Code)
+		}
+		return
+	}
+
+	require.True(testDoc.CrossCheck().IsValid(), "test genesis document should pass cross-check")
+
+	d := *testDoc
+	d.Registry.Entities = []*entity.SignedEntity{signedTestEntity}
+	d.Registry.Runtimes = []*registry.SignedRuntime{signedTestRuntime}
+	d.Registry.Nodes = []*node.MultiSignedNode{signedTestNode}
+	d.KeyManager.Statuses = []*keymanager.Status{
+		{ID: kmRuntimeID, Nodes: []signature.PublicKey{unknownPK}},
+	}
+	d.Staking.Ledger = map[staking.Address]*staking.Account{
+		staking.NewAddress(unknownPK): {
+			Escrow: staking.EscrowAccount{
+				StakeAccumulator: staking.StakeAccumulator{
+					Claims: map[staking.StakeClaim][]staking.StakeThreshold{
+						registry.StakeClaimRegisterEntity:       nil,
+						registry.StakeClaimForNode(testNode.ID): nil,
+						"unknown.claim":                         nil,
+					},
+				},
+			},
+		},
+	}
+	d.Scheduler.Parameters.DebugStaticValidators = false
+	d.Scheduler.Parameters.MinValidators = 2
+	require.ElementsMatch([]genesis.ViolationCode{
+		genesis.ViolationRuntimeKeyManagerMissing,
+		genesis.ViolationKeyManagerRuntimeMissing,
+		genesis.ViolationKeyManagerNodeMissing,
+		genesis.ViolationEscrowClaimEntityMissing,
+		genesis.ViolationEscrowClaimNodeMissing,
+		genesis.ViolationEscrowClaimInvalid,
+		genesis.ViolationSchedulerInsufficientValidators,
+	}, codes(d.CrossCheck()), "all violations should be reported")
+
+	d.Scheduler.Parameters.MinValidators = 1
+	d.Registry.NodeStatuses = map[signature.PublicKey]*registry.NodeStatus{
+		testNode.ID: {Suspended: true},
+	}
+	require.Contains(codes(d.CrossCheck()), genesis.ViolationSchedulerInsufficientValidators,
+		"suspended validators should not be electable",
+	)
+
+	d = *testDoc
+	d.Registry.Entities = []*entity.SignedEntity{signedTestEntity}
+	d.Registry.Nodes = []*node.MultiSignedNode{signedTestNode}
+	require.Equal([]genesis.ViolationCode{genesis.ViolationNodeRuntimeMissing}, codes(d.CrossCheck()),
+		"node referencing a missing runtime should be reported",
+	)
+}
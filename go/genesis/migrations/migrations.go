@@ -0,0 +1,223 @@
+// Package migrations implements genesis document migrations.
+//
+// Migrations are versioned transforms of the genesis document that are applied when upgrading a
+// network via the dump and restore procedure in case the genesis document format changed in an
+// incompatible way (e.g., parameters have been renamed or the ledger has been reshaped).
+package migrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+)
+
+// versionField is the name of the genesis document version field.
+const versionField = "version"
+
+var (
+	registeredMigrations sync.Map
+
+	// ErrNoMigration is the error returned when there is no migration registered for a version.
+	ErrNoMigration = errors.New("genesis/migrations: no migration registered")
+)
+
+// Document is a genesis document in its generic form.
+//
+// Migrations operate on the generic form as documents with older versions may not be
+// representable by the current genesis document type.
+type Document map[string]interface{}
+
+// Lookup returns the value at the given path in the document.
+func (d Document) Lookup(path ...string) (interface{}, bool) {
+	var v interface{} = map[string]interface{}(d)
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Set sets the value at the given path in the document, creating any missing intermediate
+// objects.
+func (d Document) Set(value interface{}, path ...string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("genesis/migrations: empty path")
+	}
+
+	m := map[string]interface{}(d)
+	for i, key := range path[:len(path)-1] {
+		next, ok := m[key]
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		if m, ok = next.(map[string]interface{}); !ok {
+			return fmt.Errorf("genesis/migrations: %v is not an object", path[:i+1])
+		}
+	}
+	m[path[len(path)-1]] = value
+	return nil
+}
+
+// Delete removes the value at the given path in the document and returns it.
+func (d Document) Delete(path ...string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+
+	parent, ok := d.Lookup(path[:len(path)-1]...)
+	if !ok {
+		return nil, false
+	}
+	m, ok := parent.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := m[path[len(path)-1]]
+	if !ok {
+		return nil, false
+	}
+	delete(m, path[len(path)-1])
+	return v, true
+}
+
+// Rename moves the value at the given path to a new path. It is not an error if there is no value
+// at the given path.
+func (d Document) Rename(from, to []string) error {
+	v, ok := d.Delete(from...)
+	if !ok {
+		return nil
+	}
+	return d.Set(v, to...)
+}
+
+// Version returns the genesis document version.
+func (d Document) Version() (uint64, error) {
+	v, ok := d[versionField]
+	if !ok {
+		// Documents without a version are considered to be version 0.
+		return 0, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("genesis/migrations: malformed document version")
+	}
+	version, err := strconv.ParseUint(n.String(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("genesis/migrations: malformed document version: %w", err)
+	}
+	return version, nil
+}
+
+func (d Document) setVersion(version uint64) {
+	d[versionField] = json.Number(strconv.FormatUint(version, 10))
+}
+
+// Migration is a genesis document migration.
+type Migration struct {
+	// Version is the document version produced by the migration. The migration is applied to
+	// documents with version Version-1.
+	Version uint64
+
+	// Description is a human readable description of the migration.
+	Description string
+
+	// Migrate transforms the given document in place. The document version is updated after the
+	// migration completes so the migration itself does not need to update it.
+	Migrate func(doc Document) error
+}
+
+// Register registers a new genesis document migration.
+//
+// This method will panic if a migration for the same version has already been registered.
+func Register(m *Migration) {
+	if m.Version == 0 || m.Version > genesis.LatestDocumentVersion {
+		panic(fmt.Sprintf("genesis/migrations: invalid migration version: %d", m.Version))
+	}
+	if _, isRegistered := registeredMigrations.LoadOrStore(m.Version, m); isRegistered {
+		panic(fmt.Sprintf("genesis/migrations: migration already registered: %d", m.Version))
+	}
+}
+
+// Migrations returns all registered migrations ordered by version.
+func Migrations() []*Migration {
+	var ms []*Migration
+	registeredMigrations.Range(func(k, v interface{}) bool {
+		ms = append(ms, v.(*Migration))
+		return true
+	})
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms
+}
+
+// Migrate applies all migrations needed to transform the given document from version from to
+// version to.
+func Migrate(doc Document, from, to uint64) error {
+	version, err := doc.Version()
+	if err != nil {
+		return err
+	}
+	switch {
+	case version != from:
+		return fmt.Errorf("genesis/migrations: document version mismatch (expected: %d got: %d)", from, version)
+	case to < from:
+		return fmt.Errorf("genesis/migrations: downgrades are not supported")
+	case to > genesis.LatestDocumentVersion:
+		return fmt.Errorf("genesis/migrations: unsupported target version: %d", to)
+	}
+
+	for v := from + 1; v <= to; v++ {
+		m, ok := registeredMigrations.Load(v)
+		if !ok {
+			return fmt.Errorf("%w: version %d", ErrNoMigration, v)
+		}
+		if err = m.(*Migration).Migrate(doc); err != nil {
+			return fmt.Errorf("genesis/migrations: migration to version %d failed: %w", v, err)
+		}
+		doc.setVersion(v)
+	}
+	return nil
+}
+
+// UnmarshalDocument unmarshals a JSON-encoded genesis document into its generic form.
+func UnmarshalDocument(raw []byte) (Document, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	// Make sure that numbers do not lose precision.
+	dec.UseNumber()
+
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("genesis/migrations: malformed genesis document: %w", err)
+	}
+	return doc, nil
+}
+
+// ToDocument converts the generic document into a genesis document and performs sanity checks.
+//
+// The generic document must be at the latest document version.
+func (d Document) ToDocument() (*genesis.Document, error) {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("genesis/migrations: failed to marshal document: %w", err)
+	}
+
+	var doc genesis.Document
+	if err = json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("genesis/migrations: malformed migrated document: %w", err)
+	}
+	if err = doc.SanityCheck(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
@@ -0,0 +1,109 @@
+package migrations
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
+)
+
+func TestMigrationsRegistered(t *testing.T) {
+	require := require.New(t)
+
+	ms := Migrations()
+	require.Len(ms, genesis.LatestDocumentVersion, "there should be a migration for each version")
+	for i, m := range ms {
+		require.EqualValues(i+1, m.Version, "migrations should be ordered by version")
+		require.NotEmpty(m.Description, "migration should have a description")
+		require.NotNil(m.Migrate, "migration should have a transform")
+	}
+
+	require.Panics(func() {
+		Register(&Migration{Version: 1, Migrate: func(Document) error { return nil }})
+	}, "registering a duplicate migration should panic")
+	require.Panics(func() {
+		Register(&Migration{Version: genesis.LatestDocumentVersion + 1, Migrate: func(Document) error { return nil }})
+	}, "registering a migration for an unsupported version should panic")
+}
+
+func TestDocumentHelpers(t *testing.T) {
+	require := require.New(t)
+
+	doc, err := UnmarshalDocument([]byte(`{"height":9007199254740993,"staking":{"params":{"old":"value"}}}`))
+	require.NoError(err, "UnmarshalDocument")
+
+	version, err := doc.Version()
+	require.NoError(err, "Version")
+	require.EqualValues(0, version, "documents without a version should be version 0")
+
+	v, ok := doc.Lookup("staking", "params", "old")
+	require.True(ok, "Lookup")
+	require.Equal("value", v)
+	_, ok = doc.Lookup("staking", "params", "old", "nested")
+	require.False(ok, "Lookup through a non-object should fail")
+
+	err = doc.Rename([]string{"staking", "params", "old"}, []string{"staking", "parameters", "new"})
+	require.NoError(err, "Rename")
+	_, ok = doc.Lookup("staking", "params", "old")
+	require.False(ok, "renamed value should be removed from the old path")
+	v, ok = doc.Lookup("staking", "parameters", "new")
+	require.True(ok, "renamed value should be available at the new path")
+	require.Equal("value", v)
+
+	err = doc.Rename([]string{"does", "not", "exist"}, []string{"other"})
+	require.NoError(err, "Rename of a missing value should be a no-op")
+	_, ok = doc.Lookup("other")
+	require.False(ok)
+
+	err = doc.Set("x", "height", "nested")
+	require.Error(err, "Set through a non-object should fail")
+
+	// Numbers must not lose precision.
+	raw, err := json.Marshal(doc)
+	require.NoError(err, "Marshal")
+	require.Contains(string(raw), `"height":9007199254740993`)
+}
+
+func TestMigrate(t *testing.T) {
+	require := require.New(t)
+
+	doc, err := UnmarshalDocument([]byte(`{"height":1}`))
+	require.NoError(err, "UnmarshalDocument")
+
+	err = Migrate(doc, 1, genesis.LatestDocumentVersion)
+	require.Error(err, "Migrate should fail with a version mismatch")
+	err = Migrate(doc, 0, genesis.LatestDocumentVersion+1)
+	require.Error(err, "Migrate should fail with an unsupported target version")
+
+	err = Migrate(doc, 0, genesis.LatestDocumentVersion)
+	require.NoError(err, "Migrate")
+	version, err := doc.Version()
+	require.NoError(err, "Version")
+	require.EqualValues(genesis.LatestDocumentVersion, version, "document version should be updated")
+
+	err = Migrate(doc, genesis.LatestDocumentVersion, 0)
+	require.Error(err, "Migrate should not support downgrades")
+
+	// Migrating to the same version should be a no-op.
+	err = Migrate(doc, genesis.LatestDocumentVersion, genesis.LatestDocumentVersion)
+	require.NoError(err, "Migrate")
+
+	// A migrated document that is not valid should fail sanity checks.
+	_, err = doc.ToDocument()
+	require.Error(err, "ToDocument should fail sanity checks")
+
+	// Failed migrations should be reported.
+	testErr := errors.New("test error")
+	registeredMigrations.Store(uint64(genesis.LatestDocumentVersion), &Migration{
+		Version: genesis.LatestDocumentVersion,
+		Migrate: func(Document) error { return testErr },
+	})
+	defer registeredMigrations.Delete(uint64(genesis.LatestDocumentVersion))
+	doc, err = UnmarshalDocument([]byte(`{"version":0}`))
+	require.NoError(err, "UnmarshalDocument")
+	err = Migrate(doc, 0, genesis.LatestDocumentVersion)
+	require.True(errors.Is(err, testErr), "Migrate should propagate migration errors")
+}
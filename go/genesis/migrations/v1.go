@@ -0,0 +1,12 @@
+package migrations
+
+func init() {
+	Register(&Migration{
+		Version:     1,
+		Description: "introduce genesis document versioning",
+		Migrate: func(doc Document) error {
+			// Version 1 only introduces the document version field, which is set automatically.
+			return nil
+		},
+	})
+}
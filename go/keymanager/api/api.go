@@ -82,6 +82,10 @@ type Backend interface {
 	// GetStatuses returns all currently tracked key manager statuses.
 	GetStatuses(context.Context, int64) ([]*Status, error)
 
+	// GetAttestationEvidence returns the archived attestation evidence of nodes that have been
+	// part of the given key manager committee.
+	GetAttestationEvidence(context.Context, *AttestationEvidenceQuery) ([]*AttestationEvidence, error)
+
 	// WatchStatuses returns a channel that produces a stream of messages
 	// containing the key manager statuses as it changes over time.
 	//
@@ -156,6 +160,9 @@ func VerifyExtraInfo(logger *logging.Logger, rt *registry.Runtime, nodeRt *node.
 // Genesis is the key manager management genesis state.
 type Genesis struct {
 	Statuses []*Status `json:"statuses,omitempty"`
+
+	// AttestationEvidence is the archived attestation evidence of key manager nodes.
+	AttestationEvidence []*AttestationEvidence `json:"attestation_evidence,omitempty"`
 }
 
 // SanityCheckStatuses examines the statuses table.
@@ -190,6 +197,11 @@ func (g *Genesis) SanityCheck() error {
 		return err
 	}
 
+	err = SanityCheckAttestationEvidence(g.AttestationEvidence)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
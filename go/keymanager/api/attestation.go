@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+// AttestationTCB is the trusted computing base information extracted from an attestation.
+type AttestationTCB struct {
+	// QuoteStatus is the enclave quote status reported by the attestation service.
+	QuoteStatus string `json:"quote_status"`
+	// AdvisoryIDs are the security advisories that apply to the attested platform.
+	AdvisoryIDs []string `json:"advisory_ids,omitempty"`
+	// ReportTimestamp is the time at which the attestation report was generated.
+	ReportTimestamp string `json:"report_timestamp"`
+
+	// CPUSVN is the security version of the CPU.
+	CPUSVN []byte `json:"cpu_svn"`
+	// ISVSVN is the security version of the enclave.
+	ISVSVN uint16 `json:"isv_svn"`
+	// QESVN is the security version of the quoting enclave.
+	QESVN uint16 `json:"qe_svn"`
+	// PCESVN is the security version of the provisioning certification enclave.
+	PCESVN uint16 `json:"pce_svn"`
+}
+
+// AttestationEvidence is the archived attestation evidence of a key manager node that was part of
+// the key manager committee.
+type AttestationEvidence struct {
+	// ID is the runtime ID of the key manager.
+	ID common.Namespace `json:"id"`
+	// NodeID is the identifier of the key manager node.
+	NodeID signature.PublicKey `json:"node_id"`
+
+	// Hardware is the TEE hardware type.
+	Hardware node.TEEHardware `json:"hardware"`
+	// RAK is the runtime attestation key of the enclave.
+	RAK signature.PublicKey `json:"rak"`
+	// Attestation is the raw attestation (e.g., the AVR bundle for Intel SGX).
+	Attestation []byte `json:"attestation,omitempty"`
+	// TCB is the trusted computing base information extracted from the attestation, if
+	// available.
+	TCB *AttestationTCB `json:"tcb,omitempty"`
+
+	// FirstSeenEpoch is the epoch in which the node was first part of the key manager committee
+	// with this attestation.
+	FirstSeenEpoch epochtime.EpochTime `json:"first_seen_epoch"`
+	// FirstSeenTime is the time (UNIX timestamp) at which the node was first part of the key
+	// manager committee with this attestation.
+	FirstSeenTime int64 `json:"first_seen_time"`
+	// LastSeenEpoch is the epoch in which the node was last part of the key manager committee
+	// with this attestation.
+	LastSeenEpoch epochtime.EpochTime `json:"last_seen_epoch"`
+	// LastSeenTime is the time (UNIX timestamp) at which the node was last part of the key
+	// manager committee with this attestation.
+	LastSeenTime int64 `json:"last_seen_time"`
+}
+
+// Hash returns a hash that uniquely identifies the attested enclave instance.
+func (e *AttestationEvidence) Hash() hash.Hash {
+	return hash.NewFrom(struct {
+		Hardware    node.TEEHardware    `json:"hardware"`
+		RAK         signature.PublicKey `json:"rak"`
+		Attestation []byte              `json:"attestation,omitempty"`
+	}{e.Hardware, e.RAK, e.Attestation})
+}
+
+// NewAttestationEvidence creates new attestation evidence for the given key manager node runtime
+// first seen at the given epoch and time.
+func NewAttestationEvidence(
+	id common.Namespace,
+	nodeID signature.PublicKey,
+	nodeRt *node.Runtime,
+	epoch epochtime.EpochTime,
+	ts time.Time,
+) *AttestationEvidence {
+	ev := &AttestationEvidence{
+		ID:             id,
+		NodeID:         nodeID,
+		Hardware:       node.TEEHardwareInvalid,
+		FirstSeenEpoch: epoch,
+		FirstSeenTime:  ts.Unix(),
+		LastSeenEpoch:  epoch,
+		LastSeenTime:   ts.Unix(),
+	}
+	if tee := nodeRt.Capabilities.TEE; tee != nil {
+		ev.Hardware = tee.Hardware
+		ev.RAK = tee.RAK
+		ev.Attestation = tee.Attestation
+
+		if tee.Hardware == node.TEEHardwareIntelSGX {
+			// The attestation has already been verified during node registration so the TCB
+			// information is only extracted on a best effort basis.
+			ev.TCB, _ = attestationTCBFromAVRBundle(tee.Attestation, ts)
+		}
+	}
+	return ev
+}
+
+func attestationTCBFromAVRBundle(attestation []byte, ts time.Time) (*AttestationTCB, error) {
+	var avrBundle ias.AVRBundle
+	if err := cbor.Unmarshal(attestation, &avrBundle); err != nil {
+		return nil, err
+	}
+	avr, err := avrBundle.Open(ias.IntelTrustRoots, ts)
+	if err != nil {
+		return nil, err
+	}
+	q, err := avr.Quote()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttestationTCB{
+		QuoteStatus:     avr.ISVEnclaveQuoteStatus.String(),
+		AdvisoryIDs:     avr.AdvisoryIDs,
+		ReportTimestamp: avr.Timestamp,
+		CPUSVN:          append([]byte{}, q.Report.CPUSVN[:]...),
+		ISVSVN:          q.Report.ISVSVN,
+		QESVN:           q.Body.ISVSVNQuotingEnclave,
+		PCESVN:          q.Body.ISVSVNProvisioningCertificationEnclave,
+	}, nil
+}
+
+// AttestationEvidenceQuery is a query for archived key manager attestation evidence.
+type AttestationEvidenceQuery struct {
+	// Height is the consensus height to query at.
+	Height int64 `json:"height"`
+	// ID is the runtime ID of the key manager.
+	ID common.Namespace `json:"id"`
+	// NodeID optionally restricts the query to evidence of a single key manager node.
+	NodeID *signature.PublicKey `json:"node_id,omitempty"`
+}
+
+// SanityCheckAttestationEvidence examines the archived attestation evidence.
+func SanityCheckAttestationEvidence(evidence []*AttestationEvidence) error {
+	for _, ev := range evidence {
+		if !ev.ID.IsKeyManager() {
+			return fmt.Errorf("keymanager: sanity check failed: attestation evidence key manager runtime ID %s is invalid", ev.ID)
+		}
+		if !ev.NodeID.IsValid() {
+			return fmt.Errorf("keymanager: sanity check failed: attestation evidence node ID %s is invalid", ev.NodeID)
+		}
+		if ev.FirstSeenEpoch > ev.LastSeenEpoch || ev.FirstSeenTime > ev.LastSeenTime {
+			return fmt.Errorf("keymanager: sanity check failed: attestation evidence for node %s last seen before first seen", ev.NodeID)
+		}
+	}
+	return nil
+}
@@ -24,6 +24,8 @@ var (
 	methodGetStatus = serviceName.NewMethod("GetStatus", registry.NamespaceQuery{})
 	// methodGetStatuses is the GetStatuses method.
 	methodGetStatuses = serviceName.NewMethod("GetStatuses", int64(0))
+	// methodGetAttestationEvidence is the GetAttestationEvidence method.
+	methodGetAttestationEvidence = serviceName.NewMethod("GetAttestationEvidence", AttestationEvidenceQuery{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -38,6 +40,10 @@ var (
 				MethodName: methodGetStatuses.ShortName(),
 				Handler:    handlerGetStatuses,
 			},
+			{
+				MethodName: methodGetAttestationEvidence.ShortName(),
+				Handler:    handlerGetAttestationEvidence,
+			},
 		},
 	}
 )
@@ -115,6 +121,29 @@ func handlerGetStatuses( //nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetAttestationEvidence( //nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query AttestationEvidenceQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetAttestationEvidence(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetAttestationEvidence.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetAttestationEvidence(ctx, req.(*AttestationEvidenceQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 // RegisterService registers a new keymanager backend service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -141,6 +170,14 @@ func (c *KeymanagerClient) GetStatuses(ctx context.Context, height int64) ([]*St
 	return resp, nil
 }
 
+func (c *KeymanagerClient) GetAttestationEvidence(ctx context.Context, query *AttestationEvidenceQuery) ([]*AttestationEvidence, error) {
+	var resp []*AttestationEvidence
+	if err := c.conn.Invoke(ctx, methodGetAttestationEvidence.FullName(), query, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // NewKeymanagerClient creates a new gRPC keymanager client service.
 func NewKeymanagerClient(c *grpc.ClientConn) *KeymanagerClient {
 	return &KeymanagerClient{c}
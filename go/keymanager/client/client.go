@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -38,11 +39,17 @@ type Client struct {
 
 	backend  api.Backend
 	registry registry.Backend
+	identity *identity.Identity
 
 	ctx         context.Context
 	initCh      chan struct{}
 	initialized bool
 
+	// runLock serializes restarts and guards the per-run state below.
+	runLock         sync.RWMutex
+	runCtx          context.Context
+	runCancel       context.CancelFunc
+	runQuitCh       chan struct{}
 	committeeNodes  committee.NodeDescriptorWatcher
 	committeeClient committee.Client
 
@@ -68,7 +75,11 @@ func (c *Client) CallRemote(ctx context.Context, data []byte) ([]byte, error) {
 
 	var resp []byte
 	call := func() error {
-		conn := c.committeeClient.GetConnection()
+		c.runLock.RLock()
+		committeeClient := c.committeeClient
+		c.runLock.RUnlock()
+
+		conn := committeeClient.GetConnection()
 		if conn == nil {
 			c.logger.Warn("no key manager connection for runtime")
 			return ErrKeyManagerNotAvailable
@@ -102,7 +113,7 @@ func (c *Client) CallRemote(ctx context.Context, data []byte) ([]byte, error) {
 			fallthrough
 		default:
 			// Request failed, communicate that to the node selection policy.
-			c.committeeClient.UpdateNodeSelectionPolicy(committee.NodeSelectionFeedback{Bad: err})
+			committeeClient.UpdateNodeSelectionPolicy(committee.NodeSelectionFeedback{Bad: err})
 			return backoff.Permanent(err)
 		}
 		return nil
@@ -114,7 +125,67 @@ func (c *Client) CallRemote(ctx context.Context, data []byte) ([]byte, error) {
 	return resp, err
 }
 
-func (c *Client) worker() {
+// Restart tears down the connections to the key manager committee and re-initializes them from
+// the current key manager status.
+func (c *Client) Restart(ctx context.Context) error {
+	c.runLock.Lock()
+	defer c.runLock.Unlock()
+
+	c.logger.Info("restarting key manager client")
+
+	c.runCancel()
+	select {
+	case <-c.runQuitCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := c.initRunLocked(); err != nil {
+		return err
+	}
+	go c.worker(c.runCtx, c.runQuitCh, c.committeeNodes)
+
+	c.logger.Info("key manager client restarted")
+
+	return nil
+}
+
+func (c *Client) initRunLocked() error {
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	committeeNodes, err := committee.NewNodeDescriptorWatcher(ctx, c.registry)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("keymanager/client: failed to create node descriptor watcher: %w", err)
+	}
+
+	var opts []committee.ClientOption
+	if c.identity != nil {
+		opts = append(opts, committee.WithClientAuthentication(c.identity))
+	}
+	committeeClient, err := committee.NewClient(ctx, committeeNodes, opts...)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("keymanager/client: failed to create committee client: %w", err)
+	}
+
+	c.runCtx = ctx
+	c.runCancel = cancel
+	c.runQuitCh = make(chan struct{})
+	c.committeeNodes = committeeNodes
+	c.committeeClient = committeeClient
+
+	return nil
+}
+
+func (c *Client) worker(ctx context.Context, quitCh chan struct{}, committeeNodes committee.NodeDescriptorWatcher) {
+	defer close(quitCh)
+
 	stCh, stSub := c.backend.WatchStatuses()
 	defer stSub.Close()
 
@@ -130,7 +201,7 @@ func (c *Client) worker() {
 	var kmID *common.Namespace
 	for {
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return
 		case st := <-stCh:
 			// Ignore status updates if key manager is not yet known (is nil) or if the status
@@ -139,7 +210,7 @@ func (c *Client) worker() {
 				continue
 			}
 
-			c.updateState(st)
+			c.updateState(ctx, committeeNodes, st)
 		case rt := <-rtCh:
 			kmID = rt.KeyManager
 			if kmID == nil {
@@ -154,7 +225,7 @@ func (c *Client) worker() {
 			}
 
 			// Fetch current key manager status.
-			st, err := c.backend.GetStatus(c.ctx, &registry.NamespaceQuery{
+			st, err := c.backend.GetStatus(ctx, &registry.NamespaceQuery{
 				ID:     *kmID,
 				Height: consensus.HeightLatest,
 			})
@@ -165,18 +236,18 @@ func (c *Client) worker() {
 				continue
 			}
 
-			c.updateState(st)
+			c.updateState(ctx, committeeNodes, st)
 		}
 	}
 }
 
-func (c *Client) updateState(status *api.Status) {
+func (c *Client) updateState(ctx context.Context, committeeNodes committee.NodeDescriptorWatcher, status *api.Status) {
 	c.logger.Debug("updating connection state",
 		"id", status.ID,
 	)
 
-	c.committeeNodes.Reset()
-	defer c.committeeNodes.Freeze(0)
+	committeeNodes.Reset()
+	defer committeeNodes.Freeze(0)
 
 	// It's not possible to service requests for this key manager.
 	if !status.IsInitialized || len(status.Nodes) == 0 {
@@ -188,7 +259,7 @@ func (c *Client) updateState(status *api.Status) {
 	}
 
 	for _, nodeID := range status.Nodes {
-		_, err := c.committeeNodes.WatchNode(c.ctx, nodeID)
+		_, err := committeeNodes.WatchNode(ctx, nodeID)
 		if err != nil {
 			c.logger.Warn("failed to watch node",
 				"err", err,
@@ -211,31 +282,19 @@ func New(
 	registry registry.Backend,
 	identity *identity.Identity,
 ) (*Client, error) {
-	committeeNodes, err := committee.NewNodeDescriptorWatcher(ctx, registry)
-	if err != nil {
-		return nil, fmt.Errorf("keymanager/client: failed to create node descriptor watcher: %w", err)
-	}
-
-	var opts []committee.ClientOption
-	if identity != nil {
-		opts = append(opts, committee.WithClientAuthentication(identity))
-	}
-	committeeClient, err := committee.NewClient(ctx, committeeNodes, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("keymanager/client: failed to create committee client: %w", err)
-	}
-
 	c := &Client{
-		runtime:         runtime,
-		backend:         backend,
-		registry:        registry,
-		ctx:             ctx,
-		initCh:          make(chan struct{}),
-		committeeNodes:  committeeNodes,
-		committeeClient: committeeClient,
-		logger:          logging.GetLogger("keymanager/client").With("runtime_id", runtime.ID()),
+		runtime:  runtime,
+		backend:  backend,
+		registry: registry,
+		identity: identity,
+		ctx:      ctx,
+		initCh:   make(chan struct{}),
+		logger:   logging.GetLogger("keymanager/client").With("runtime_id", runtime.ID()),
+	}
+	if err := c.initRunLocked(); err != nil {
+		return nil, err
 	}
-	go c.worker()
+	go c.worker(c.runCtx, c.runQuitCh, c.committeeNodes)
 
 	return c, nil
 }
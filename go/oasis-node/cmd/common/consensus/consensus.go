@@ -12,14 +12,17 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	signerFile "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
 	signerPlugin "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/plugin"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 )
 
@@ -33,11 +36,18 @@ const (
 	// CfgTxFeeGas configures the maximum gas limit.
 	CfgTxFeeGas = "transaction.fee.gas"
 
+	// CfgTxFeeSponsor configures the public key of the account sponsoring the fee.
+	CfgTxFeeSponsor = "transaction.fee.sponsor"
+
 	// CfgTxFile configures the filename for the transaction.
 	CfgTxFile = "transaction.file"
 
 	// CfgTxUnsigned makes SaveTx save an unsigned transaction.
 	CfgTxUnsigned = "transaction.unsigned"
+
+	// CfgTxDryRun makes SignAndSaveTx simulate the transaction against a node instead of signing
+	// and saving it.
+	CfgTxDryRun = "transaction.dry_run"
 )
 
 var (
@@ -48,6 +58,11 @@ var (
 )
 
 func AssertTxFileOK() {
+	if viper.GetBool(CfgTxDryRun) {
+		// Transactions are not saved in dry run mode.
+		return
+	}
+
 	f := viper.GetString(CfgTxFile)
 	if f == "" {
 		logger.Error("failed to determine tx file")
@@ -90,21 +105,19 @@ func GetTxNonceAndFee() (uint64, *transaction.Fee) {
 		os.Exit(1)
 	}
 	fee.Gas = transaction.Gas(viper.GetUint64(CfgTxFeeGas))
-	return nonce, &fee
-}
-
-func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction) {
-	if viper.GetBool(CfgTxUnsigned) {
-		rawUnsignedTx := cbor.Marshal(tx)
-		if err := ioutil.WriteFile(viper.GetString(CfgTxFile), rawUnsignedTx, 0o600); err != nil {
-			logger.Error("failed to save unsigned transaction",
+	if sponsor := viper.GetString(CfgTxFeeSponsor); sponsor != "" {
+		fee.Sponsor = new(signature.PublicKey)
+		if err := fee.Sponsor.UnmarshalText([]byte(sponsor)); err != nil {
+			logger.Error("failed to parse fee sponsor public key",
 				"err", err,
 			)
 			os.Exit(1)
 		}
-		return
 	}
+	return nonce, &fee
+}
 
+func loadSigner() signature.Signer {
 	entityDir, err := cmdSigner.CLIDirOrPwd()
 	if err != nil {
 		logger.Error("failed to retrieve signer dir",
@@ -119,6 +132,89 @@ func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction) {
 		)
 		os.Exit(1)
 	}
+	return signer
+}
+
+// SimulateTx simulates the given transaction against the configured node and prints the
+// expected gas usage, fees, events and errors. The transaction is not submitted.
+func SimulateTx(ctx context.Context, tx *transaction.Transaction) {
+	signer := loadSigner()
+	defer signer.Reset()
+
+	conn, err := cmdGrpc.NewClientFromConfig()
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	client := consensus.NewConsensusClient(conn)
+
+	rsp, err := client.SimulateTx(ctx, &consensus.SimulateTxRequest{
+		Signer:      signer.Public(),
+		Transaction: tx,
+	})
+	if err != nil {
+		logger.Error("failed to simulate transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Simulated the following transaction (dry run, not submitted):\n")
+	tx.PrettyPrint(ctx, "  ", os.Stdout)
+
+	fmt.Printf("\nSimulation results:\n")
+	fmt.Printf("  Gas used: %d\n", rsp.GasUsed)
+	if tx.Fee != nil {
+		fmt.Printf("  Fee:\n")
+		tx.Fee.PrettyPrint(ctx, "    ", os.Stdout)
+	} else {
+		fmt.Printf("  Fee:      none\n")
+	}
+	if rsp.Result.IsSuccess() {
+		fmt.Printf("  Status:   ok\n")
+	} else {
+		fmt.Printf("  Status:   failed (module: %s code: %d): %s\n",
+			rsp.Result.Error.Module,
+			rsp.Result.Error.Code,
+			rsp.Result.Error.Message,
+		)
+	}
+
+	rawEvents, err := json.MarshalIndent(rsp.Result.Events, "  ", "  ")
+	if err != nil {
+		logger.Error("failed to marshal events",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("  Events:   %s\n", rawEvents)
+
+	if !rsp.Result.IsSuccess() {
+		os.Exit(1)
+	}
+}
+
+func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction) {
+	if viper.GetBool(CfgTxDryRun) {
+		SimulateTx(ctx, tx)
+		return
+	}
+
+	if viper.GetBool(CfgTxUnsigned) {
+		rawUnsignedTx := cbor.Marshal(tx)
+		if err := ioutil.WriteFile(viper.GetString(CfgTxFile), rawUnsignedTx, 0o600); err != nil {
+			logger.Error("failed to save unsigned transaction",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
+	signer := loadSigner()
 	defer signer.Reset()
 
 	fmt.Printf("You are about to sign the following transaction:\n")
@@ -167,11 +263,14 @@ func init() {
 	TxFlags.Uint64(CfgTxNonce, 0, "nonce of the signing account")
 	TxFlags.Uint64(CfgTxFeeAmount, 0, "transaction fee in base units")
 	TxFlags.String(CfgTxFeeGas, "0", "maximum transaction gas limit")
+	TxFlags.String(CfgTxFeeSponsor, "", "public key of the account paying the fee from its allowance to the signer")
 	TxFlags.Bool(CfgTxUnsigned, false, "generate an unsigned transaction")
+	TxFlags.Bool(CfgTxDryRun, false, "simulate the transaction against a node without signing or saving it")
 	_ = viper.BindPFlags(TxFlags)
 	TxFlags.AddFlagSet(TxFileFlags)
 	TxFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	TxFlags.AddFlagSet(cmdSigner.Flags)
 	TxFlags.AddFlagSet(cmdSigner.CLIFlags)
 	TxFlags.AddFlagSet(cmdFlags.GenesisFileFlags)
+	TxFlags.AddFlagSet(cmdGrpc.ClientFlags)
 }
@@ -0,0 +1,207 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// ledgerCSVHeader is the expected header of a staking ledger CSV file.
+var ledgerCSVHeader = []string{"address", "balance", "escrow", "delegation_target"}
+
+type ledgerCSVEntry struct {
+	row int
+
+	address staking.Address
+	balance quantity.Quantity
+	escrow  quantity.Quantity
+	target  *staking.Address
+}
+
+func parseLedgerCSVAddress(raw string) (*staking.Address, error) {
+	var addr staking.Address
+	if err := addr.UnmarshalText([]byte(raw)); err != nil {
+		return nil, fmt.Errorf("malformed address '%s': %w", raw, err)
+	}
+	if !addr.IsValid() {
+		return nil, fmt.Errorf("invalid address: %s", addr)
+	}
+	return &addr, nil
+}
+
+func parseLedgerCSVEntry(row int, record []string) (*ledgerCSVEntry, error) {
+	entry := ledgerCSVEntry{row: row}
+
+	addr, err := parseLedgerCSVAddress(strings.TrimSpace(record[0]))
+	if err != nil {
+		return nil, err
+	}
+	entry.address = *addr
+
+	if err = entry.balance.UnmarshalText([]byte(strings.TrimSpace(record[1]))); err != nil {
+		return nil, fmt.Errorf("malformed balance '%s': %w", record[1], err)
+	}
+	if err = entry.escrow.UnmarshalText([]byte(strings.TrimSpace(record[2]))); err != nil {
+		return nil, fmt.Errorf("malformed escrow '%s': %w", record[2], err)
+	}
+
+	switch rawTarget := strings.TrimSpace(record[3]); rawTarget {
+	case "":
+		if !entry.escrow.IsZero() {
+			return nil, fmt.Errorf("escrow without a delegation target")
+		}
+	default:
+		if entry.escrow.IsZero() {
+			return nil, fmt.Errorf("delegation target without escrow")
+		}
+		if entry.target, err = parseLedgerCSVAddress(rawTarget); err != nil {
+			return nil, fmt.Errorf("delegation target: %w", err)
+		}
+	}
+
+	return &entry, nil
+}
+
+// key returns the key that uniquely identifies the entry.
+func (e *ledgerCSVEntry) key() string {
+	if e.target == nil {
+		return e.address.String() + ","
+	}
+	return e.address.String() + "," + e.target.String()
+}
+
+// ImportLedgerCSV imports accounts and delegations from a CSV file into the
+// staking genesis state.
+//
+// The CSV file must start with the header row
+// `address,balance,escrow,delegation_target`, followed by rows that each
+// specify an account's general balance and optionally an amount of stake
+// (escrow) that the account delegates to the delegation target. Amounts are
+// specified in base units. An account may appear in multiple rows in order to
+// delegate to multiple targets (in which case the balances from all rows are
+// added up), but each (address, delegation target) pair may only appear once.
+//
+// Imported accounts must not already exist in the ledger. The rows are applied
+// in a deterministic order regardless of their order in the file and the total
+// supply is updated to include all imported stake.
+func (st *AppendableStakingState) ImportLedgerCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(ledgerCSVHeader)
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return fmt.Errorf("genesis/staking: failed to read ledger CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("genesis/staking: ledger CSV is empty")
+	}
+	for i, column := range ledgerCSVHeader {
+		if strings.TrimSpace(records[0][i]) != column {
+			return fmt.Errorf("genesis/staking: malformed ledger CSV header (expected: %s)",
+				strings.Join(ledgerCSVHeader, ","),
+			)
+		}
+	}
+
+	// Parse and validate all entries.
+	entries := make([]*ledgerCSVEntry, 0, len(records)-1)
+	seen := make(map[string]int)
+	imported := make(map[staking.Address]bool)
+	for i, record := range records[1:] {
+		row := i + 1
+		var entry *ledgerCSVEntry
+		if entry, err = parseLedgerCSVEntry(row, record); err != nil {
+			return fmt.Errorf("genesis/staking: ledger CSV row %d: %w", row, err)
+		}
+
+		key := entry.key()
+		if prevRow, ok := seen[key]; ok {
+			return fmt.Errorf("genesis/staking: ledger CSV row %d: duplicate of row %d", row, prevRow)
+		}
+		seen[key] = row
+
+		if st.State.Ledger[entry.address] != nil && !imported[entry.address] {
+			return fmt.Errorf("genesis/staking: ledger CSV row %d: account %s already in ledger", row, entry.address)
+		}
+		if entry.target != nil && st.State.Delegations[*entry.target][entry.address] != nil {
+			return fmt.Errorf("genesis/staking: ledger CSV row %d: delegation from %s to %s already exists",
+				row, entry.address, *entry.target,
+			)
+		}
+
+		entries = append(entries, entry)
+		imported[entry.address] = true
+	}
+
+	// Apply entries in a deterministic order.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if c := bytes.Compare(entries[i].address[:], entries[j].address[:]); c != 0 {
+			return c < 0
+		}
+		switch {
+		case entries[i].target == nil:
+			return entries[j].target != nil
+		case entries[j].target == nil:
+			return false
+		default:
+			return bytes.Compare(entries[i].target[:], entries[j].target[:]) < 0
+		}
+	})
+	for _, entry := range entries {
+		if err = st.applyLedgerCSVEntry(entry); err != nil {
+			return fmt.Errorf("genesis/staking: ledger CSV row %d: %w", entry.row, err)
+		}
+	}
+
+	return nil
+}
+
+func (st *AppendableStakingState) ledgerAccount(addr staking.Address) *staking.Account {
+	acct := st.State.Ledger[addr]
+	if acct == nil {
+		acct = &staking.Account{}
+		st.State.Ledger[addr] = acct
+	}
+	return acct
+}
+
+func (st *AppendableStakingState) applyLedgerCSVEntry(entry *ledgerCSVEntry) error {
+	acct := st.ledgerAccount(entry.address)
+	if err := acct.General.Balance.Add(&entry.balance); err != nil {
+		return fmt.Errorf("failed to add balance: %w", err)
+	}
+	if err := st.State.TotalSupply.Add(&entry.balance); err != nil {
+		return fmt.Errorf("failed to update total supply: %w", err)
+	}
+
+	if entry.target == nil {
+		return nil
+	}
+
+	target := st.ledgerAccount(*entry.target)
+	var delegation staking.Delegation
+	stakeSrc := entry.escrow.Clone()
+	if err := target.Escrow.Active.Deposit(&delegation.Shares, stakeSrc, &entry.escrow); err != nil {
+		return fmt.Errorf("failed to escrow stake: %w", err)
+	}
+	if err := st.State.TotalSupply.Add(&entry.escrow); err != nil {
+		return fmt.Errorf("failed to update total supply: %w", err)
+	}
+
+	if st.State.Delegations == nil {
+		st.State.Delegations = make(map[staking.Address]map[staking.Address]*staking.Delegation)
+	}
+	if st.State.Delegations[*entry.target] == nil {
+		st.State.Delegations[*entry.target] = make(map[staking.Address]*staking.Delegation)
+	}
+	st.State.Delegations[*entry.target][entry.address] = &delegation
+
+	return nil
+}
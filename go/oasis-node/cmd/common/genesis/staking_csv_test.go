@@ -0,0 +1,101 @@
+package genesis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestImportLedgerCSV(t *testing.T) {
+	require := require.New(t)
+
+	addr1 := staking.NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := staking.NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr3 := staking.NewAddress(signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	reservedAddr := staking.NewReservedAddress(signature.NewPublicKey("badaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	header := "address,balance,escrow,delegation_target\n"
+	csv := header + fmt.Sprintf(
+		"%s,100,0,\n"+
+			"%s,0,50,%s\n"+
+			"%s,10,20,%s\n"+
+			"%s,5,30,%s\n",
+		addr1,
+		addr1, addr2,
+		addr3, addr2,
+		addr3, addr3,
+	)
+
+	st, err := NewAppendableStakingState()
+	require.NoError(err, "NewAppendableStakingState")
+	err = st.ImportLedgerCSV(strings.NewReader(csv))
+	require.NoError(err, "ImportLedgerCSV")
+
+	require.Len(st.State.Ledger, 3, "all accounts should be in the ledger")
+	require.Equal(*quantity.NewFromUint64(100), st.State.Ledger[addr1].General.Balance)
+	require.True(st.State.Ledger[addr2].General.Balance.IsZero())
+	require.Equal(*quantity.NewFromUint64(15), st.State.Ledger[addr3].General.Balance)
+	require.Equal(*quantity.NewFromUint64(70), st.State.Ledger[addr2].Escrow.Active.Balance)
+	require.Equal(*quantity.NewFromUint64(70), st.State.Ledger[addr2].Escrow.Active.TotalShares)
+	require.Equal(*quantity.NewFromUint64(30), st.State.Ledger[addr3].Escrow.Active.Balance)
+	require.Equal(*quantity.NewFromUint64(50), st.State.Delegations[addr2][addr1].Shares)
+	require.Equal(*quantity.NewFromUint64(20), st.State.Delegations[addr2][addr3].Shares)
+	require.Equal(*quantity.NewFromUint64(30), st.State.Delegations[addr3][addr3].Shares)
+	require.Equal(*quantity.NewFromUint64(215), st.State.TotalSupply)
+
+	// Row order should not matter.
+	rows := strings.Split(strings.TrimSpace(csv), "\n")
+	reordered := rows[0] + "\n" + strings.Join([]string{rows[4], rows[2], rows[1], rows[3]}, "\n") + "\n"
+	st2, err := NewAppendableStakingState()
+	require.NoError(err, "NewAppendableStakingState")
+	err = st2.ImportLedgerCSV(strings.NewReader(reordered))
+	require.NoError(err, "ImportLedgerCSV")
+	require.EqualValues(st.State, st2.State, "import should be deterministic")
+
+	// Invalid files.
+	for _, tc := range []struct {
+		msg string
+		csv string
+	}{
+		{"empty", ""},
+		{"missing header", fmt.Sprintf("%s,100,0,\n", addr1)},
+		{"malformed header", "address,balance,escrow,target\n"},
+		{"missing field", header + fmt.Sprintf("%s,100,0\n", addr1)},
+		{"malformed address", header + "invalid,100,0,\n"},
+		{"reserved address", header + fmt.Sprintf("%s,100,0,\n", reservedAddr)},
+		{"reserved delegation target", header + fmt.Sprintf("%s,0,100,%s\n", addr1, reservedAddr)},
+		{"malformed balance", header + fmt.Sprintf("%s,-100,0,\n", addr1)},
+		{"malformed escrow", header + fmt.Sprintf("%s,0,1.5,%s\n", addr1, addr2)},
+		{"escrow without target", header + fmt.Sprintf("%s,0,100,\n", addr1)},
+		{"target without escrow", header + fmt.Sprintf("%s,100,0,%s\n", addr1, addr2)},
+		{"duplicate account", header + fmt.Sprintf("%s,100,0,\n%s,50,0,\n", addr1, addr1)},
+		{"duplicate delegation", header + fmt.Sprintf("%s,0,10,%s\n%s,0,20,%s\n", addr1, addr2, addr1, addr2)},
+	} {
+		st, err = NewAppendableStakingState()
+		require.NoError(err, "NewAppendableStakingState")
+		err = st.ImportLedgerCSV(strings.NewReader(tc.csv))
+		require.Error(err, tc.msg)
+		require.Empty(st.State.Ledger, "ledger should not be modified on failure (%s)", tc.msg)
+	}
+
+	// Accounts already in the ledger should be rejected.
+	st, err = NewAppendableStakingState()
+	require.NoError(err, "NewAppendableStakingState")
+	st.State.Ledger[addr1] = &staking.Account{}
+	err = st.ImportLedgerCSV(strings.NewReader(header + fmt.Sprintf("%s,100,0,\n", addr1)))
+	require.Error(err, "ImportLedgerCSV should fail for existing accounts")
+
+	// Delegations to accounts already in the ledger should be allowed.
+	st, err = NewAppendableStakingState()
+	require.NoError(err, "NewAppendableStakingState")
+	st.State.Ledger[addr2] = &staking.Account{}
+	err = st.ImportLedgerCSV(strings.NewReader(header + fmt.Sprintf("%s,0,100,%s\n", addr1, addr2)))
+	require.NoError(err, "ImportLedgerCSV should allow delegations to existing accounts")
+	require.Equal(*quantity.NewFromUint64(100), st.State.Ledger[addr2].Escrow.Active.Balance)
+}
@@ -90,7 +90,17 @@ func NewServerLocal(installWrapper bool) (*cmnGrpc.Server, error) {
 
 func NewClient(cmd *cobra.Command) (*grpc.ClientConn, error) {
 	addr, _ := cmd.Flags().GetString(CfgAddress)
+	return newClient(addr)
+}
+
+// NewClientFromConfig creates a new gRPC client for the remote address configured via viper.
+//
+// This is useful for helpers that do not have access to the command being executed.
+func NewClientFromConfig() (*grpc.ClientConn, error) {
+	return newClient(viper.GetString(CfgAddress))
+}
 
+func newClient(addr string) (*grpc.ClientConn, error) {
 	if _, err := os.Stat(addr); err == nil {
 		logger.Warn(fmt.Sprintf("'%s' is a file name. Assuming 'unix:%s'.", addr, addr))
 		addr = "unix:" + addr
@@ -0,0 +1,202 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+const (
+	// CfgOffline is the flag used to request an offline signing payload
+	// instead of signing with the configured signer.
+	CfgOffline = "offline"
+
+	// CfgOfflinePayload is the flag used to specify the path of the offline
+	// signing payload.
+	CfgOfflinePayload = "offline.payload"
+
+	// CfgOfflineSignature is the flag used to specify the path(s) of the
+	// externally produced signature(s).
+	CfgOfflineSignature = "offline.signature"
+
+	// CfgSignedOutput is the flag used to specify the path of the signed
+	// descriptor.
+	CfgSignedOutput = "signed_output"
+)
+
+// OfflineFlags has the offline signing related flags.
+var OfflineFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// Offline returns true iff the offline flag is set.
+func Offline() bool {
+	return viper.GetBool(CfgOffline)
+}
+
+// OfflinePayload is a signing payload that can be transferred to an offline
+// (air-gapped) machine for signing.
+type OfflinePayload struct {
+	// Context is the signature context.
+	Context signature.Context `json:"context"`
+
+	// Blob is the canonical CBOR serialization of the message to be signed.
+	Blob []byte `json:"blob"`
+
+	// Signers are the public keys that are expected to sign the payload.
+	Signers []signature.PublicKey `json:"signers"`
+}
+
+// IsExpectedSigner returns true iff the given public key is expected to sign
+// the payload.
+func (p *OfflinePayload) IsExpectedSigner(pk signature.PublicKey) bool {
+	for _, v := range p.Signers {
+		if v.Equal(pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign signs the payload with the given signer.
+func (p *OfflinePayload) Sign(signer signature.Signer) (*signature.Signature, error) {
+	if !p.IsExpectedSigner(signer.Public()) {
+		return nil, fmt.Errorf("signer %s is not expected to sign the payload", signer.Public())
+	}
+	return signature.Sign(signer, p.Context, p.Blob)
+}
+
+// Signatures verifies the given signatures over the payload and returns them
+// ordered by the expected signers.
+//
+// Exactly one valid signature is required from each of the expected signers.
+func (p *OfflinePayload) Signatures(sigs []signature.Signature) ([]signature.Signature, error) {
+	bySigner := make(map[signature.PublicKey]signature.Signature)
+	for _, sig := range sigs {
+		if !p.IsExpectedSigner(sig.PublicKey) {
+			return nil, fmt.Errorf("unexpected signature by %s", sig.PublicKey)
+		}
+		if _, ok := bySigner[sig.PublicKey]; ok {
+			return nil, fmt.Errorf("duplicate signature by %s", sig.PublicKey)
+		}
+		if !sig.Verify(p.Context, p.Blob) {
+			return nil, fmt.Errorf("invalid signature by %s: %w", sig.PublicKey, signature.ErrVerifyFailed)
+		}
+		bySigner[sig.PublicKey] = sig
+	}
+
+	ordered := make([]signature.Signature, 0, len(p.Signers))
+	for _, pk := range p.Signers {
+		sig, ok := bySigner[pk]
+		if !ok {
+			return nil, fmt.Errorf("missing signature by %s", pk)
+		}
+		ordered = append(ordered, sig)
+	}
+	return ordered, nil
+}
+
+// Signed assembles a signed blob from the given signature over the payload.
+func (p *OfflinePayload) Signed(sig signature.Signature) (*signature.Signed, error) {
+	if len(p.Signers) != 1 {
+		return nil, fmt.Errorf("payload requires %d signatures", len(p.Signers))
+	}
+	sigs, err := p.Signatures([]signature.Signature{sig})
+	if err != nil {
+		return nil, err
+	}
+	return &signature.Signed{Blob: p.Blob, Signature: sigs[0]}, nil
+}
+
+// MultiSigned assembles a multi-signed blob from the given signatures over
+// the payload.
+func (p *OfflinePayload) MultiSigned(sigs []signature.Signature) (*signature.MultiSigned, error) {
+	ordered, err := p.Signatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	return &signature.MultiSigned{Blob: p.Blob, Signatures: ordered}, nil
+}
+
+// NewOfflinePayload creates a new offline signing payload for the
+// CBOR-serialized message.
+func NewOfflinePayload(context signature.Context, src interface{}, signers []signature.PublicKey) *OfflinePayload {
+	return &OfflinePayload{
+		Context: context,
+		Blob:    cbor.Marshal(src),
+		Signers: signers,
+	}
+}
+
+// SaveOfflinePayload writes the CBOR-serialized offline signing payload to a
+// file.
+func SaveOfflinePayload(path string, p *OfflinePayload) error {
+	return ioutil.WriteFile(path, cbor.Marshal(p), 0o600)
+}
+
+// LoadOfflinePayload loads an offline signing payload from a file.
+func LoadOfflinePayload(path string) (*OfflinePayload, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p OfflinePayload
+	if err = cbor.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("malformed offline signing payload: %w", err)
+	}
+	if len(p.Signers) == 0 {
+		return nil, fmt.Errorf("malformed offline signing payload: no expected signers")
+	}
+	return &p, nil
+}
+
+// SaveSignatures writes the PEM-encoded signatures to a file.
+func SaveSignatures(path string, sigs []signature.Signature) error {
+	var raw []byte
+	for _, sig := range sigs {
+		rawSig, err := sig.MarshalPEM()
+		if err != nil {
+			return err
+		}
+		raw = append(raw, rawSig...)
+	}
+	return ioutil.WriteFile(path, raw, 0o600)
+}
+
+// LoadSignatures loads PEM-encoded signatures from files. Each file may
+// contain multiple signatures.
+func LoadSignatures(paths []string) ([]signature.Signature, error) {
+	var sigs []signature.Signature
+	for _, path := range paths {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for len(bytes.TrimSpace(raw)) > 0 {
+			// Each signature consists of a public key and a raw signature block.
+			_, rest := pem.Decode(raw)
+			_, rest = pem.Decode(rest)
+
+			var sig signature.Signature
+			if err = sig.UnmarshalPEM(raw[:len(raw)-len(rest)]); err != nil {
+				return nil, fmt.Errorf("malformed signature in '%s': %w", path, err)
+			}
+			sigs = append(sigs, sig)
+			raw = rest
+		}
+	}
+	return sigs, nil
+}
+
+func init() {
+	OfflineFlags.Bool(CfgOffline, false, "write an offline signing payload instead of signing")
+	OfflineFlags.String(CfgOfflinePayload, "", "path to the offline signing payload")
+	OfflineFlags.StringSlice(CfgOfflineSignature, nil, "path(s) to the PEM-encoded signature(s) over the offline signing payload")
+	OfflineFlags.String(CfgSignedOutput, "", "path to write the signed descriptor to")
+	_ = viper.BindPFlags(OfflineFlags)
+}
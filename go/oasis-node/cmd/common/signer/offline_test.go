@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+var testOfflineSignatureContext = signature.NewContext("oasis-core/cmd/signer: offline test")
+
+func TestOfflinePayload(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-node-test_signer_offline_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	fac := memorySigner.NewFactory()
+	var signers []signature.Signer
+	var pks []signature.PublicKey
+	for i := 0; i < 2; i++ {
+		signer, gerr := fac.Generate(signature.SignerNode, rand.Reader)
+		require.NoError(gerr, "Generate")
+		signers = append(signers, signer)
+		pks = append(pks, signer.Public())
+	}
+	other, err := fac.Generate(signature.SignerNode, rand.Reader)
+	require.NoError(err, "Generate")
+
+	msg := map[string]string{"descriptor": "test"}
+
+	// Round-trip the payload through a file.
+	payloadPath := filepath.Join(dataDir, "payload.cbor")
+	err = SaveOfflinePayload(payloadPath, NewOfflinePayload(testOfflineSignatureContext, msg, pks))
+	require.NoError(err, "SaveOfflinePayload")
+	payload, err := LoadOfflinePayload(payloadPath)
+	require.NoError(err, "LoadOfflinePayload")
+	require.Equal(testOfflineSignatureContext, payload.Context, "context should round-trip")
+	require.Equal(pks, payload.Signers, "signers should round-trip")
+
+	_, err = payload.Sign(other)
+	require.Error(err, "Sign should fail for unexpected signers")
+
+	// Sign in reverse order and round-trip the signatures through files.
+	var sigPaths []string
+	for i := len(signers) - 1; i >= 0; i-- {
+		sig, serr := payload.Sign(signers[i])
+		require.NoError(serr, "Sign")
+
+		sigPath := filepath.Join(dataDir, fmt.Sprintf("signature-%d.pem", i))
+		require.NoError(SaveSignatures(sigPath, []signature.Signature{*sig}), "SaveSignatures")
+		sigPaths = append(sigPaths, sigPath)
+	}
+	sigs, err := LoadSignatures(sigPaths)
+	require.NoError(err, "LoadSignatures")
+	require.Len(sigs, 2, "all signatures should be loaded")
+
+	// Multiple signatures in a single file.
+	multiPath := filepath.Join(dataDir, "multi.pem")
+	require.NoError(SaveSignatures(multiPath, sigs), "SaveSignatures")
+	multiSigs, err := LoadSignatures([]string{multiPath})
+	require.NoError(err, "LoadSignatures")
+	require.Equal(sigs, multiSigs, "signatures should round-trip")
+
+	ms, err := payload.MultiSigned(sigs)
+	require.NoError(err, "MultiSigned")
+	require.True(ms.IsOnlySignedBy(pks), "all expected signers should sign")
+	require.True(ms.Signatures[0].PublicKey.Equal(pks[0]), "signatures should be ordered by expected signers")
+	var decoded map[string]string
+	require.NoError(ms.Open(testOfflineSignatureContext, &decoded), "Open")
+	require.Equal(msg, decoded, "message should round-trip")
+
+	_, err = payload.MultiSigned(sigs[:1])
+	require.Error(err, "MultiSigned should fail with missing signatures")
+	_, err = payload.MultiSigned(append(sigs, sigs[0]))
+	require.Error(err, "MultiSigned should fail with duplicate signatures")
+	_, err = payload.Signed(sigs[0])
+	require.Error(err, "Signed should fail when multiple signatures are expected")
+
+	tampered := sigs[0]
+	tampered.Signature[0] ^= 0xff
+	_, err = payload.MultiSigned([]signature.Signature{tampered, sigs[1]})
+	require.Error(err, "MultiSigned should fail with invalid signatures")
+
+	// Single signer payloads.
+	single := NewOfflinePayload(testOfflineSignatureContext, msg, pks[:1])
+	sig, err := single.Sign(signers[0])
+	require.NoError(err, "Sign")
+	signed, err := single.Signed(*sig)
+	require.NoError(err, "Signed")
+	require.NoError(signed.Open(testOfflineSignatureContext, &decoded), "Open")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	compositeSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/composite"
 	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	ledgerSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/ledger"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	pluginSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/plugin"
 	remoteSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/remote"
@@ -37,6 +38,8 @@ const (
 
 	cfgSignerCompositeBackends = "signer.composite.backends"
 
+	cfgSignerLedgerIndex = "signer.ledger.index"
+
 	cfgSignerPluginName   = "signer.plugin.name"
 	cfgSignerPluginPath   = "signer.plugin.path"
 	cfgSignerPluginConfig = "signer.plugin.config"
@@ -114,6 +117,11 @@ func doNewFactory(signerBackend, signerDir string, roles ...signature.SignerRole
 		config.ServerCertificate = serverCert
 
 		return remoteSigner.NewFactory(config, roles...)
+	case ledgerSigner.SignerName:
+		config := &ledgerSigner.FactoryConfig{
+			Index: viper.GetUint32(cfgSignerLedgerIndex),
+		}
+		return ledgerSigner.NewFactory(config, roles...)
 	case pluginSigner.SignerName:
 		config := &pluginSigner.FactoryConfig{
 			Name:   viper.GetString(cfgSignerPluginName),
@@ -176,12 +184,13 @@ func doNewComposite(signerDir string, roles ...signature.SignerRole) (signature.
 }
 
 func init() {
-	Flags.StringP(CfgSigner, "s", "file", "signer backend [file, plugin, remote, composite]")
+	Flags.StringP(CfgSigner, "s", "file", "signer backend [file, ledger, plugin, remote, composite]")
 	Flags.String(cfgSignerRemoteAddress, "", "remote signer server address")
 	Flags.String(cfgSignerRemoteClientCert, "", "remote signer client certificate path")
 	Flags.String(cfgSignerRemoteClientKey, "", "remote signer client certificate key path")
 	Flags.String(cfgSignerRemoteServerCert, "", "remote signer server certificate path")
 	Flags.String(cfgSignerCompositeBackends, "", "composite signer backends")
+	Flags.Uint32(cfgSignerLedgerIndex, 0, "ledger signer account index")
 	Flags.String(cfgSignerPluginName, "", "plugin signer backend name")
 	Flags.String(cfgSignerPluginPath, "", "plugin signer binary path")
 	Flags.String(cfgSignerPluginConfig, "", "plugin signer configuration")
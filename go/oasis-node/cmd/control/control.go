@@ -7,19 +7,32 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 var (
-	shutdownWait = false
+	shutdownWait  = false
+	shutdownEpoch uint64
+
+	banReason   string
+	banDuration time.Duration
+
+	persistentPeersAdd    []string
+	persistentPeersRemove []string
+
+	restartRuntimeID string
 
 	controlCmd = &cobra.Command{
 		Use:   "control",
@@ -63,6 +76,57 @@ var (
 		Run:   doStatus,
 	}
 
+	controlMempoolCmd = &cobra.Command{
+		Use:   "mempool",
+		Short: "show pending transactions in the local consensus mempool",
+		Run:   doMempool,
+	}
+
+	controlEpochSnapshotsCmd = &cobra.Command{
+		Use:   "epoch-snapshots",
+		Short: "show the current per-runtime committee epoch snapshots",
+		Run:   doEpochSnapshots,
+	}
+
+	controlWatchEventsCmd = &cobra.Command{
+		Use:   "watch-events",
+		Short: "watch node events derived from critical log events",
+		Run:   doWatchEvents,
+	}
+
+	controlPeersCmd = &cobra.Command{
+		Use:   "peers",
+		Short: "show consensus peers with their reputation scores and bans",
+		Run:   doPeers,
+	}
+
+	controlBanPeerCmd = &cobra.Command{
+		Use:   "ban-peer <peer-id>",
+		Short: "disconnect from a consensus peer and refuse further connections",
+		Args:  cobra.ExactArgs(1),
+		Run:   doBanPeer,
+	}
+
+	controlUnbanPeerCmd = &cobra.Command{
+		Use:   "unban-peer <peer-id>",
+		Short: "lift the ban on a consensus peer",
+		Args:  cobra.ExactArgs(1),
+		Run:   doUnbanPeer,
+	}
+
+	controlPersistentPeersCmd = &cobra.Command{
+		Use:   "persistent-peers",
+		Short: "update the set of consensus persistent peers",
+		Run:   doPersistentPeers,
+	}
+
+	controlRestartSubsystemCmd = &cobra.Command{
+		Use:   "restart-subsystem <subsystem>",
+		Short: "restart a node subsystem (storage_worker, registration, keymanager_client) in-process",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRestartSubsystem,
+	}
+
 	logger = logging.GetLogger("cmd/control")
 )
 
@@ -128,7 +192,15 @@ func doShutdown(cmd *cobra.Command, args []string) {
 	conn, client := DoConnect(cmd)
 	defer conn.Close()
 
-	err := client.RequestShutdown(context.Background(), shutdownWait)
+	var err error
+	if cmd.Flags().Changed("epoch") {
+		err = client.RequestShutdownAtEpoch(context.Background(), &control.ShutdownRequest{
+			Wait:  shutdownWait,
+			Epoch: epochtime.EpochTime(shutdownEpoch),
+		})
+	} else {
+		err = client.RequestShutdown(context.Background(), shutdownWait)
+	}
 	if err != nil {
 		logger.Error("failed to send shutdown request",
 			"err", err,
@@ -207,11 +279,201 @@ func doStatus(cmd *cobra.Command, args []string) {
 	fmt.Println(string(formatted))
 }
 
+func doMempool(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	logger.Debug("querying mempool")
+
+	// Use background context to block until the result comes in.
+	mempool, err := client.GetMempool(context.Background())
+	if err != nil {
+		logger.Error("failed to query mempool",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	formatted, err := json.MarshalIndent(mempool, "", "  ")
+	if err != nil {
+		logger.Error("failed to format mempool",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(formatted))
+}
+
+func doEpochSnapshots(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	logger.Debug("querying epoch snapshots")
+
+	snapshots, err := client.GetEpochSnapshots(context.Background())
+	if err != nil {
+		logger.Error("failed to query epoch snapshots",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	formatted, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		logger.Error("failed to format epoch snapshots",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(formatted))
+}
+
+func doWatchEvents(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	logger.Debug("watching node events")
+
+	ch, sub, err := client.WatchEvents(context.Background())
+	if err != nil {
+		logger.Error("failed to watch node events",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	defer sub.Close()
+
+	for ev := range ch {
+		formatted, err := json.Marshal(ev)
+		if err != nil {
+			logger.Error("failed to format node event",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		fmt.Println(string(formatted))
+	}
+}
+
+func doPeers(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	logger.Debug("querying consensus peers")
+
+	peers, err := client.GetConsensusPeers(context.Background())
+	if err != nil {
+		logger.Error("failed to query consensus peers",
+			"err", err,
+		)
+		os.Exit(128)
+	}
+	formatted, err := json.MarshalIndent(peers, "", "  ")
+	if err != nil {
+		logger.Error("failed to format consensus peers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(formatted))
+}
+
+func doBanPeer(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	req := &consensus.BanPeerRequest{
+		ID:       args[0],
+		Reason:   banReason,
+		Duration: banDuration,
+	}
+	if err := client.BanConsensusPeer(context.Background(), req); err != nil {
+		logger.Error("failed to ban consensus peer",
+			"err", err,
+			"peer_id", req.ID,
+		)
+		os.Exit(1)
+	}
+}
+
+func doUnbanPeer(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.UnbanConsensusPeer(context.Background(), args[0]); err != nil {
+		logger.Error("failed to unban consensus peer",
+			"err", err,
+			"peer_id", args[0],
+		)
+		os.Exit(1)
+	}
+}
+
+func doPersistentPeers(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	peers, err := client.UpdatePersistentPeers(context.Background(), &consensus.PersistentPeersUpdate{
+		Add:    persistentPeersAdd,
+		Remove: persistentPeersRemove,
+	})
+	if err != nil {
+		logger.Error("failed to update persistent peers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	for _, peer := range peers {
+		fmt.Println(peer)
+	}
+}
+
+func doRestartSubsystem(cmd *cobra.Command, args []string) {
+	req := &control.RestartSubsystemRequest{
+		Subsystem: control.Subsystem(args[0]),
+	}
+	if restartRuntimeID != "" {
+		var runtimeID common.Namespace
+		if err := runtimeID.UnmarshalHex(restartRuntimeID); err != nil {
+			logger.Error("malformed runtime ID",
+				"err", err,
+				"runtime_id", restartRuntimeID,
+			)
+			os.Exit(1)
+		}
+		req.RuntimeID = &runtimeID
+	}
+	if err := req.ValidateBasic(); err != nil {
+		logger.Error("invalid restart request",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.RestartSubsystem(context.Background(), req); err != nil {
+		logger.Error("failed to restart subsystem",
+			"err", err,
+			"subsystem", req.Subsystem,
+		)
+		os.Exit(1)
+	}
+}
+
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 
 	controlShutdownCmd.Flags().BoolVarP(&shutdownWait, "wait", "w", false, "wait for the node to finish shutdown")
+	controlShutdownCmd.Flags().Uint64Var(&shutdownEpoch, "epoch", 0, "only start the shutdown once the given epoch is reached")
+
+	controlBanPeerCmd.Flags().StringVar(&banReason, "reason", "", "reason for the ban")
+	controlBanPeerCmd.Flags().DurationVar(&banDuration, "duration", 0, "duration of the ban (default: until unbanned)")
+
+	controlPersistentPeersCmd.Flags().StringSliceVar(&persistentPeersAdd, "add", nil, "persistent peer(s) to add of the form ID@ip:port")
+	controlPersistentPeersCmd.Flags().StringSliceVar(&persistentPeersRemove, "remove", nil, "ID(s) of persistent peer(s) to remove")
+
+	controlRestartSubsystemCmd.Flags().StringVar(&restartRuntimeID, "runtime", "", "runtime ID (hex) for per-runtime subsystems")
 
 	controlCmd.AddCommand(controlIsSyncedCmd)
 	controlCmd.AddCommand(controlWaitSyncCmd)
@@ -219,5 +481,13 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.AddCommand(controlUpgradeBinaryCmd)
 	controlCmd.AddCommand(controlCancelUpgradeCmd)
 	controlCmd.AddCommand(controlStatusCmd)
+	controlCmd.AddCommand(controlMempoolCmd)
+	controlCmd.AddCommand(controlEpochSnapshotsCmd)
+	controlCmd.AddCommand(controlWatchEventsCmd)
+	controlCmd.AddCommand(controlPeersCmd)
+	controlCmd.AddCommand(controlBanPeerCmd)
+	controlCmd.AddCommand(controlUnbanPeerCmd)
+	controlCmd.AddCommand(controlPersistentPeersCmd)
+	controlCmd.AddCommand(controlRestartSubsystemCmd)
 	parentCmd.AddCommand(controlCmd)
 }
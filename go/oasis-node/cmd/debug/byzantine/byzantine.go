@@ -255,6 +255,7 @@ func init() {
 	storageFlags.Uint64(CfgNumStorageFailApplyBatch, 0, "Number of ApplyBatch requests to fail")
 	storageFlags.Uint64(CfgNumStorageFailApply, 0, "Number of Apply requests to fail")
 	storageFlags.Bool(CfgFailReadRequests, false, "If storage worker should fail read requests")
+	storageFlags.String(CfgStorageMode, ModeStorageHonest.String(), "configures storage mode")
 	_ = viper.BindPFlags(storageFlags)
 	byzantineCmd.PersistentFlags().AddFlagSet(storageFlags)
 
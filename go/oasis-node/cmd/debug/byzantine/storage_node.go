@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	flag "github.com/spf13/pflag"
@@ -17,6 +18,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/database"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 const (
@@ -28,8 +30,60 @@ const (
 	CfgNumStorageFailApplyBatch = "num_storage_fail_apply_batch"
 	// CfgFailReadRequests configures if storage node should fail read requests.
 	CfgFailReadRequests = "fail_read_requests"
+	// CfgStorageMode configures the byzantine storage mode.
+	CfgStorageMode = "storage_mode"
 )
 
+// StorageMode represents the byzantine storage mode.
+type StorageMode uint32
+
+// Storage modes.
+const (
+	ModeStorageHonest                   StorageMode = 0
+	ModeStorageCorruptDiffs             StorageMode = 1
+	ModeStorageWithholdCheckpointChunks StorageMode = 2
+	ModeStorageWrongProofs              StorageMode = 3
+
+	modeStorageHonestString                   = "storage_honest"
+	modeStorageCorruptDiffsString             = "storage_corrupt_diffs"
+	modeStorageWithholdCheckpointChunksString = "storage_withhold_checkpoint_chunks"
+	modeStorageWrongProofsString              = "storage_wrong_proofs"
+)
+
+// String returns a string representation of a storage mode.
+func (m StorageMode) String() string {
+	switch m {
+	case ModeStorageHonest:
+		return modeStorageHonestString
+	case ModeStorageCorruptDiffs:
+		return modeStorageCorruptDiffsString
+	case ModeStorageWithholdCheckpointChunks:
+		return modeStorageWithholdCheckpointChunksString
+	case ModeStorageWrongProofs:
+		return modeStorageWrongProofsString
+	default:
+		return "[unsupported storage mode]"
+	}
+}
+
+// FromString deserializes a string into a storage mode.
+func (m *StorageMode) FromString(str string) error {
+	switch strings.ToLower(str) {
+	case modeStorageHonestString:
+		*m = ModeStorageHonest
+	case modeStorageCorruptDiffsString:
+		*m = ModeStorageCorruptDiffs
+	case modeStorageWithholdCheckpointChunksString:
+		*m = ModeStorageWithholdCheckpointChunks
+	case modeStorageWrongProofsString:
+		*m = ModeStorageWrongProofs
+	default:
+		return fmt.Errorf("invalid storage mode: %s", str)
+	}
+
+	return nil
+}
+
 var (
 	_ storage.Backend = (*storageWorker)(nil)
 
@@ -48,9 +102,15 @@ type storageWorker struct {
 	numFailApply      uint64
 	numFailApplyBatch uint64
 	failReadRequests  bool
+	mode              StorageMode
 }
 
 func newStorageNode(id *identity.Identity, namespace common.Namespace, datadir string) (*storageWorker, error) {
+	var mode StorageMode
+	if err := mode.FromString(viper.GetString(CfgStorageMode)); err != nil {
+		return nil, err
+	}
+
 	initCh := make(chan struct{})
 	defer close(initCh)
 
@@ -74,15 +134,49 @@ func newStorageNode(id *identity.Identity, namespace common.Namespace, datadir s
 		numFailApply:      viper.GetUint64(CfgNumStorageFailApply),
 		numFailApplyBatch: viper.GetUint64(CfgNumStorageFailApplyBatch),
 		failReadRequests:  viper.GetBool(CfgFailReadRequests),
+		mode:              mode,
 	}, nil
 }
 
+// corruptProof tampers with the given proof so that it no longer verifies
+// against the root it claims to be for.
+func corruptProof(rsp *syncer.ProofResponse) *syncer.ProofResponse {
+	proof := &rsp.Proof
+	switch proof.V {
+	case 0, syncer.ProofVersionV1:
+		for i, entry := range proof.Entries {
+			// Skip empty subtrees and entries that only contain the type.
+			if len(entry) < 2 {
+				continue
+			}
+			corrupted := append([]byte{}, entry...)
+			corrupted[len(corrupted)-1] ^= 0xff
+			proof.Entries[i] = corrupted
+			break
+		}
+	default:
+		if len(proof.Data) > 0 {
+			corrupted := append([]byte{}, proof.Data...)
+			corrupted[len(corrupted)-1] ^= 0xff
+			proof.Data = corrupted
+		}
+	}
+	return rsp
+}
+
+func (w *storageWorker) maybeCorruptProof(rsp *syncer.ProofResponse, err error) (*syncer.ProofResponse, error) {
+	if err != nil || w.mode != ModeStorageWrongProofs {
+		return rsp, err
+	}
+	return corruptProof(rsp), nil
+}
+
 func (w *storageWorker) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer.ProofResponse, error) {
 	if w.failReadRequests {
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncGet(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncGet(ctx, request))
 }
 
 func (w *storageWorker) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
@@ -90,7 +184,7 @@ func (w *storageWorker) SyncGetPrefixes(ctx context.Context, request *syncer.Get
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncGetPrefixes(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncGetPrefixes(ctx, request))
 }
 
 func (w *storageWorker) SyncIterate(ctx context.Context, request *syncer.IterateRequest) (*syncer.ProofResponse, error) {
@@ -98,7 +192,7 @@ func (w *storageWorker) SyncIterate(ctx context.Context, request *syncer.Iterate
 		return nil, errByzantine
 	}
 
-	return w.backend.SyncIterate(ctx, request)
+	return w.maybeCorruptProof(w.backend.SyncIterate(ctx, request))
 }
 
 func (w *storageWorker) Apply(ctx context.Context, request *storage.ApplyRequest) ([]*storage.Receipt, error) {
@@ -130,7 +224,31 @@ func (w *storageWorker) GetDiff(ctx context.Context, request *storage.GetDiffReq
 		return nil, errByzantine
 	}
 
-	return w.backend.GetDiff(ctx, request)
+	it, err := w.backend.GetDiff(ctx, request)
+	if err != nil || w.mode != ModeStorageCorruptDiffs {
+		return it, err
+	}
+
+	// Serve a diff with all of the values replaced.
+	var wl writelog.WriteLog
+	for {
+		var more bool
+		if more, err = it.Next(); err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+
+		var entry writelog.LogEntry
+		if entry, err = it.Value(); err != nil {
+			return nil, err
+		}
+		entry.Value = []byte("byzantine")
+		wl = append(wl, entry)
+	}
+
+	return writelog.NewStaticIterator(wl), nil
 }
 
 func (w *storageWorker) GetCheckpoints(ctx context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
@@ -142,7 +260,7 @@ func (w *storageWorker) GetCheckpoints(ctx context.Context, request *checkpoint.
 }
 
 func (w *storageWorker) GetCheckpointChunk(ctx context.Context, chunk *checkpoint.ChunkMetadata, wr io.Writer) error {
-	if w.failReadRequests {
+	if w.failReadRequests || w.mode == ModeStorageWithholdCheckpointChunks {
 		return fmt.Errorf("failing request")
 	}
 
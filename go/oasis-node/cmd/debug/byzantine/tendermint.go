@@ -37,7 +37,7 @@ func (ht *honestTendermint) start(id *identity.Identity, dataDir string) error {
 	}
 	genesisDoc.SetChainContext()
 
-	ht.service, err = tendermint.New(context.Background(), dataDir, id, upgrade.NewDummyUpgradeManager(), genesis)
+	ht.service, err = tendermint.New(context.Background(), dataDir, id, upgrade.NewDummyUpgradeManager(), genesis, nil)
 	if err != nil {
 		return fmt.Errorf("tendermint New: %w", err)
 	}
@@ -97,6 +97,7 @@ func (m *mockChain) stateToGenesis(ctx context.Context) (*genesis.Document, erro
 	var err error
 
 	doc := &genesis.Document{
+		Version: genesis.LatestDocumentVersion,
 		Height:  m.height,
 		Time:    m.now,
 		ChainID: m.cfg.genesisDoc.ChainID,
@@ -33,6 +33,13 @@ func (b *simTimeSource) GetEpochBlock(ctx context.Context, epoch api.EpochTime)
 	return height, nil
 }
 
+func (b *simTimeSource) GetNextEpochBlock(ctx context.Context, height int64) (int64, error) {
+	if height == 0 {
+		return int64(b.current-b.base+1) * b.interval, nil
+	}
+	return (height/b.interval + 1) * b.interval, nil
+}
+
 func (b *simTimeSource) WatchEpochs() (<-chan api.EpochTime, *pubsub.Subscription) {
 	panic("consim/epochtime: WatchEpochs not supported")
 }
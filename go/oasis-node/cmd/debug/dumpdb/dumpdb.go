@@ -144,6 +144,7 @@ func doDumpDB(cmd *cobra.Command, args []string) {
 		height: dumpVersion,
 	}
 	doc := &genesis.Document{
+		Version:   genesis.LatestDocumentVersion,
 		Height:    qs.BlockHeight(),
 		Time:      time.Now(), // XXX: Make this deterministic?
 		ChainID:   oldDoc.ChainID,
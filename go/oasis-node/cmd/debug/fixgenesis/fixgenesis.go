@@ -140,6 +140,7 @@ func doFixGenesis(cmd *cobra.Command, args []string) {
 func updateGenesisDoc(oldDoc *oldDocument) (*genesis.Document, error) {
 	// Create the new genesis document template.
 	newDoc := &genesis.Document{
+		Version:    genesis.LatestDocumentVersion,
 		Height:     oldDoc.Height,
 		Time:       oldDoc.Time,
 		ChainID:    oldDoc.ChainID,
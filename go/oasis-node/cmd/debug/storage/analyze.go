@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+)
+
+const (
+	cfgAnalyzePrefixLen   = "storage.analyze.prefix_len"
+	cfgAnalyzeTopPrefixes = "storage.analyze.top_prefixes"
+	cfgAnalyzeJSON        = "storage.analyze.json"
+)
+
+var (
+	storageAnalyzeCmd = &cobra.Command{
+		Use:   "analyze db-path",
+		Short: "report space usage of an (offline) node database",
+		Long: "Analyzes the given Badger node database and reports per-version node counts\n" +
+			"and sizes, write log sizes, estimated garbage and the leaf key prefixes using\n" +
+			"the most space. The database must not be in use by a running node.",
+		Args: cobra.ExactArgs(1),
+		Run:  doAnalyze,
+	}
+
+	storageAnalyzeFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doAnalyze(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	report, err := badgerNodedb.Analyze(context.Background(), &badgerNodedb.AnalyzeConfig{
+		DB:             args[0],
+		KeyPrefixLen:   viper.GetInt(cfgAnalyzePrefixLen),
+		NumTopPrefixes: viper.GetInt(cfgAnalyzeTopPrefixes),
+	})
+	if err != nil {
+		logger.Error("failed to analyze node database",
+			"err", err,
+			"db", args[0],
+		)
+		os.Exit(1)
+	}
+
+	if viper.GetBool(cfgAnalyzeJSON) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(report); err != nil {
+			logger.Error("failed to encode report",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Namespace:              %s\n", report.Namespace)
+	fmt.Printf("Earliest version:       %d\n", report.EarliestVersion)
+	if report.LastFinalizedVersion != nil {
+		fmt.Printf("Last finalized version: %d\n", *report.LastFinalizedVersion)
+	} else {
+		fmt.Printf("Last finalized version: none\n")
+	}
+	fmt.Printf("On-disk size:           %d bytes (LSM: %d, value log: %d)\n",
+		report.LSMSize+report.ValueLogSize,
+		report.LSMSize,
+		report.ValueLogSize,
+	)
+	fmt.Printf("Live size:              %d bytes\n", report.LiveBytes)
+	fmt.Printf("Estimated garbage:      %d bytes (%d entries)\n", report.GarbageBytes, report.GarbageEntries)
+	fmt.Printf("Space amplification:    %.2f\n", report.SpaceAmplification())
+
+	fmt.Printf("\nEntries by type:\n")
+	fmt.Printf("  %-24s %12s %16s\n", "TYPE", "ENTRIES", "BYTES")
+	for _, kt := range report.KeyTypes {
+		fmt.Printf("  %-24s %12d %16d\n", kt.Name, kt.Entries, kt.Bytes)
+	}
+
+	fmt.Printf("\nEntries by version:\n")
+	fmt.Printf("  %-12s %12s %16s %12s %16s %16s\n", "VERSION", "NODES", "NODE BYTES", "WRITE LOGS", "WRITE LOG BYTES", "GARBAGE BYTES")
+	for _, vs := range report.Versions {
+		fmt.Printf("  %-12d %12d %16d %12d %16d %16d\n",
+			vs.Version,
+			vs.Nodes,
+			vs.NodeBytes,
+			vs.WriteLogs,
+			vs.WriteLogBytes,
+			vs.GarbageBytes,
+		)
+	}
+
+	fmt.Printf("\nTop leaf key prefixes by size:\n")
+	fmt.Printf("  %-24s %12s %16s\n", "PREFIX", "LEAVES", "BYTES")
+	for _, ps := range report.TopPrefixes {
+		fmt.Printf("  %-24s %12d %16d\n", hex.EncodeToString(ps.Prefix), ps.Leaves, ps.Bytes)
+	}
+}
+
+func init() {
+	storageAnalyzeFlags.Int(cfgAnalyzePrefixLen, 1, "length (in bytes) of leaf key prefixes to aggregate by")
+	storageAnalyzeFlags.Int(cfgAnalyzeTopPrefixes, 10, "number of largest leaf key prefixes to report")
+	storageAnalyzeFlags.Bool(cfgAnalyzeJSON, false, "output the report in JSON format")
+	_ = viper.BindPFlags(storageAnalyzeFlags)
+}
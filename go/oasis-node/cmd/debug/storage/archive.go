@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+)
+
+const (
+	cfgArchiveDir       = "storage.archive.dir"
+	cfgArchiveRound     = "storage.archive.round"
+	cfgArchiveChunkSize = "storage.archive.chunk_size"
+)
+
+var (
+	storageExportArchiveCmd = &cobra.Command{
+		Use:   "export-archive runtime-id (hex)",
+		Short: "export the state of a finalized round into a portable archive",
+		Long: "Writes all roots of the given finalized round from the local node database into\n" +
+			"an archive that can be restored on another node using import-archive. The node\n" +
+			"database must not be in use by a running node.",
+		Args: validateRuntimeIDArg,
+		Run:  doExportArchive,
+	}
+
+	storageImportArchiveCmd = &cobra.Command{
+		Use:   "import-archive",
+		Short: "import the state of a finalized round from a portable archive",
+		Long: "Restores all roots contained in an archive created by export-archive into the\n" +
+			"local node database and finalizes the archived round. The node database must not\n" +
+			"be in use by a running node.",
+		Args: cobra.NoArgs,
+		Run:  doImportArchive,
+	}
+
+	storageArchiveFlags       = flag.NewFlagSet("", flag.ContinueOnError)
+	storageExportArchiveFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func openLocalStorageBackend(runtimeID common.Namespace) (storageAPI.LocalBackend, error) {
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		return nil, fmt.Errorf("data directory must be set")
+	}
+	dataDir = filepath.Join(dataDir, runtimeRegistry.RuntimesDir, runtimeID.String())
+
+	backend, err := newDirectStorageBackend(dataDir, runtimeID)
+	if err != nil {
+		return nil, err
+	}
+	localBackend, ok := backend.(storageAPI.LocalBackend)
+	if !ok {
+		backend.Cleanup()
+		return nil, fmt.Errorf("storage backend does not support local access")
+	}
+	<-localBackend.Initialized()
+	return localBackend, nil
+}
+
+func doExportArchive(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	archiveDir := viper.GetString(cfgArchiveDir)
+	if archiveDir == "" {
+		logger.Error("archive directory must be set")
+		os.Exit(1)
+	}
+
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(args[0]); err != nil {
+		logger.Error("malformed runtime id",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	backend, err := openLocalStorageBackend(runtimeID)
+	if err != nil {
+		logger.Error("failed to open local storage backend",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer backend.Cleanup()
+
+	round := viper.GetUint64(cfgArchiveRound)
+	manifest, err := checkpoint.ExportArchive(
+		context.Background(),
+		backend.NodeDB(),
+		runtimeID,
+		round,
+		uint64(viper.GetSizeInBytes(cfgArchiveChunkSize)),
+		archiveDir,
+	)
+	if err != nil {
+		logger.Error("failed to export archive",
+			"err", err,
+			"round", round,
+		)
+		backend.Cleanup()
+		os.Exit(1)
+	}
+
+	logger.Info("archive exported",
+		"round", manifest.RootVersion,
+		"roots", manifest.Roots(),
+		"dir", archiveDir,
+	)
+}
+
+func doImportArchive(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	archiveDir := viper.GetString(cfgArchiveDir)
+	if archiveDir == "" {
+		logger.Error("archive directory must be set")
+		os.Exit(1)
+	}
+
+	manifest, err := checkpoint.ReadArchiveManifest(archiveDir)
+	if err != nil {
+		logger.Error("failed to read archive manifest",
+			"err", err,
+			"dir", archiveDir,
+		)
+		os.Exit(1)
+	}
+
+	backend, err := openLocalStorageBackend(manifest.Namespace)
+	if err != nil {
+		logger.Error("failed to open local storage backend",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer backend.Cleanup()
+
+	if _, err = checkpoint.ImportArchive(context.Background(), backend.NodeDB(), archiveDir); err != nil {
+		logger.Error("failed to import archive",
+			"err", err,
+			"round", manifest.RootVersion,
+		)
+		backend.Cleanup()
+		os.Exit(1)
+	}
+
+	logger.Info("archive imported",
+		"runtime_id", manifest.Namespace,
+		"round", manifest.RootVersion,
+		"roots", manifest.Roots(),
+	)
+}
+
+func init() {
+	storageArchiveFlags.String(cfgArchiveDir, "", "the storage archive directory")
+	_ = viper.BindPFlags(storageArchiveFlags)
+
+	storageExportArchiveFlags.Uint64(cfgArchiveRound, 0, "the (finalized) round to export")
+	storageExportArchiveFlags.String(cfgArchiveChunkSize, "8mb", "the archive chunk size (in bytes)")
+	_ = viper.BindPFlags(storageExportArchiveFlags)
+}
@@ -29,7 +29,8 @@ const (
 )
 
 var (
-	finalizeRound uint64
+	finalizeRound   uint64
+	checkpointRound uint64
 
 	storageCmd = &cobra.Command{
 		Use:   "storage",
@@ -74,6 +75,27 @@ var (
 		Run: doForceFinalize,
 	}
 
+	storageCreateCheckpointCmd = &cobra.Command{
+		Use:   "create-checkpoint runtime-id (hex)",
+		Short: "create checkpoints for a finalized round on demand",
+		Args:  validateRuntimeIDArg,
+		Run:   doCreateCheckpoint,
+	}
+
+	storageDeleteCheckpointCmd = &cobra.Command{
+		Use:   "delete-checkpoint runtime-id (hex)",
+		Short: "delete checkpoints for a round that have been created on demand",
+		Args:  validateRuntimeIDArg,
+		Run:   doDeleteCheckpoint,
+	}
+
+	storageTriggerGCCmd = &cobra.Command{
+		Use:   "trigger-gc runtime-id (hex)",
+		Short: "run storage database garbage collection immediately",
+		Args:  validateRuntimeIDArg,
+		Run:   doTriggerGC,
+	}
+
 	logger = logging.GetLogger("cmd/storage")
 )
 
@@ -83,6 +105,16 @@ func ValidateRuntimeIDStr(idStr string) error {
 	return ns.UnmarshalHex(idStr)
 }
 
+func validateRuntimeIDArg(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return err
+	}
+	if err := ValidateRuntimeIDStr(args[0]); err != nil {
+		return fmt.Errorf("malformed runtime id '%v': %w", args[0], err)
+	}
+	return nil
+}
+
 func checkDiff(ctx context.Context, storageClient storageAPI.Backend, root string, oldRoot, newRoot node.Root) {
 	it, err := storageClient.GetDiff(ctx, &storageAPI.GetDiffRequest{StartRoot: oldRoot, EndRoot: newRoot})
 	if err != nil {
@@ -270,6 +302,81 @@ func doForceFinalize(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doCreateCheckpoint(cmd *cobra.Command, args []string) {
+	if !cmd.Flags().Changed("round") {
+		logger.Error("round to checkpoint must be specified")
+		os.Exit(1)
+	}
+
+	conn, _ := cmdControl.DoConnect(cmd)
+	storageWorkerClient := storageWorkerAPI.NewStorageWorkerClient(conn)
+	defer conn.Close()
+
+	var id common.Namespace
+	_ = id.UnmarshalHex(args[0])
+
+	cps, err := storageWorkerClient.CreateCheckpoint(context.Background(), &storageWorkerAPI.CheckpointRequest{
+		RuntimeID: id,
+		Round:     checkpointRound,
+	})
+	if err != nil {
+		logger.Error("failed to create checkpoint",
+			"err", err,
+			"round", checkpointRound,
+		)
+		os.Exit(1)
+	}
+	for _, cp := range cps {
+		fmt.Printf("%s (version %d, %d chunks)\n", cp.Root.Hash, cp.Root.Version, len(cp.Chunks))
+	}
+}
+
+func doDeleteCheckpoint(cmd *cobra.Command, args []string) {
+	if !cmd.Flags().Changed("round") {
+		logger.Error("round of the checkpoint must be specified")
+		os.Exit(1)
+	}
+
+	conn, _ := cmdControl.DoConnect(cmd)
+	storageWorkerClient := storageWorkerAPI.NewStorageWorkerClient(conn)
+	defer conn.Close()
+
+	var id common.Namespace
+	_ = id.UnmarshalHex(args[0])
+
+	err := storageWorkerClient.DeleteCheckpoint(context.Background(), &storageWorkerAPI.CheckpointRequest{
+		RuntimeID: id,
+		Round:     checkpointRound,
+	})
+	if err != nil {
+		logger.Error("failed to delete checkpoint",
+			"err", err,
+			"round", checkpointRound,
+		)
+		os.Exit(1)
+	}
+}
+
+func doTriggerGC(cmd *cobra.Command, args []string) {
+	conn, _ := cmdControl.DoConnect(cmd)
+	storageWorkerClient := storageWorkerAPI.NewStorageWorkerClient(conn)
+	defer conn.Close()
+
+	var id common.Namespace
+	_ = id.UnmarshalHex(args[0])
+
+	rsp, err := storageWorkerClient.TriggerGC(context.Background(), &storageWorkerAPI.TriggerGCRequest{
+		RuntimeID: id,
+	})
+	if err != nil {
+		logger.Error("failed to trigger garbage collection",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Printf("garbage collection completed (%d log files rewritten)\n", rsp.Rewrites)
+}
+
 // Register registers the storage sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	storageCheckRootsCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -279,16 +386,37 @@ func Register(parentCmd *cobra.Command) {
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 
+	for _, cmd := range []*cobra.Command{storageCreateCheckpointCmd, storageDeleteCheckpointCmd} {
+		cmd.Flags().Uint64Var(&checkpointRound, "round", 0, "the (finalized) round of the checkpoint")
+		cmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	}
+
+	storageTriggerGCCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+
 	storageExportCmd.Flags().AddFlagSet(storage.Flags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	storageExportCmd.Flags().AddFlagSet(storageExportFlags)
 
+	for _, cmd := range []*cobra.Command{storageExportArchiveCmd, storageImportArchiveCmd} {
+		cmd.Flags().AddFlagSet(storage.Flags)
+		cmd.Flags().AddFlagSet(storageArchiveFlags)
+	}
+	storageExportArchiveCmd.Flags().AddFlagSet(storageExportArchiveFlags)
+
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
+	storageAnalyzeCmd.Flags().AddFlagSet(storageAnalyzeFlags)
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageForceFinalizeCmd)
+	storageCmd.AddCommand(storageCreateCheckpointCmd)
+	storageCmd.AddCommand(storageDeleteCheckpointCmd)
+	storageCmd.AddCommand(storageTriggerGCCmd)
 	storageCmd.AddCommand(storageExportCmd)
+	storageCmd.AddCommand(storageExportArchiveCmd)
+	storageCmd.AddCommand(storageImportArchiveCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
+	storageCmd.AddCommand(storageAnalyzeCmd)
 	parentCmd.AddCommand(storageCmd)
 }
@@ -31,6 +31,7 @@ import (
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
+	"github.com/oasisprotocol/oasis-core/go/genesis/migrations"
 	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -48,6 +49,7 @@ const (
 	cfgRootHash      = "roothash"
 	cfgKeyManager    = "keymanager"
 	cfgStaking       = "staking"
+	cfgStakingLedger = "staking.ledger_csv"
 	cfgBlockHeight   = "height"
 	cfgChainID       = "chain.id"
 	cfgHaltEpoch     = "halt.epoch"
@@ -56,6 +58,9 @@ const (
 	// Registry config flags.
 	CfgRegistryMaxNodeExpiration                      = "registry.max_node_expiration"
 	CfgRegistryDisableRuntimeRegistration             = "registry.disable_runtime_registration"
+	CfgRegistryMinHeartbeatInterval                   = "registry.min_heartbeat_interval"
+	CfgRegistryMaxNodesPerEntity                      = "registry.max_nodes_per_entity"
+	CfgRegistryMaxNodesPerEntityRole                  = "registry.max_nodes_per_entity_role"
 	cfgRegistryDebugAllowUnroutableAddresses          = "registry.debug.allow_unroutable_addresses"
 	CfgRegistryDebugAllowTestRuntimes                 = "registry.debug.allow_test_runtimes"
 	cfgRegistryDebugAllowEntitySignedNodeRegistration = "registry.debug.allow_entity_signed_registration"
@@ -69,6 +74,9 @@ const (
 	cfgSchedulerDebugStaticValidators  = "scheduler.debug.static_validators"
 
 	// Beacon config flags.
+	cfgBeaconBackend            = "beacon.backend"
+	cfgBeaconVRFMinProofs       = "beacon.vrf.min_proofs"
+	cfgBeaconPendingEpochNotice = "beacon.pending_epoch_notice"
 	cfgBeaconDebugDeterministic = "beacon.debug.deterministic"
 
 	// EpochTime config flags.
@@ -76,8 +84,12 @@ const (
 	cfgEpochTimeTendermintInterval = "epochtime.tendermint.interval"
 
 	// Roothash config flags.
+	cfgRoothashMaxExecutorCommitments    = "roothash.max_executor_commitments"
+	cfgRoothashMaxExecutorCommitmentSize = "roothash.max_executor_commitment_size"
+	cfgRoothashMaxEvidenceAge            = "roothash.max_evidence_age"
 	cfgRoothashDebugDoNotSuspendRuntimes = "roothash.debug.do_not_suspend_runtimes"
 	cfgRoothashDebugBypassStake          = "roothash.debug.bypass_stake" // nolint: gosec
+	cfgRoothashDebugAllowRuntimeReset    = "roothash.debug.allow_runtime_reset"
 
 	// Staking config flags.
 	CfgStakingTokenSymbol        = "staking.token_symbol"
@@ -107,12 +119,21 @@ const (
 	// Check command.
 	// Number of lines to print if document not in canonical form.
 	checkNotCanonicalLines = 10
+	cfgCheckFormat         = "format"
+	checkFormatText        = "text"
+	checkFormatJSON        = "json"
+
+	// Migrate command.
+	cfgMigrateFrom       = "from"
+	cfgMigrateTo         = "to"
+	cfgMigrateNewGenesis = "genesis.new_file"
 )
 
 var (
-	checkGenesisFlags = flag.NewFlagSet("", flag.ContinueOnError)
-	dumpGenesisFlags  = flag.NewFlagSet("", flag.ContinueOnError)
-	initGenesisFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	checkGenesisFlags   = flag.NewFlagSet("", flag.ContinueOnError)
+	dumpGenesisFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	initGenesisFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	migrateGenesisFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	genesisCmd = &cobra.Command{
 		Use:   "genesis",
@@ -137,6 +158,12 @@ var (
 		Run:   doCheckGenesis,
 	}
 
+	migrateGenesisCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "migrate the genesis file to a newer document version",
+		Run:   doMigrateGenesis,
+	}
+
 	logger = logging.GetLogger("cmd/genesis")
 )
 
@@ -166,6 +193,7 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 
 	// Build the genesis state, if any.
 	doc := &genesis.Document{
+		Version:   genesis.LatestDocumentVersion,
 		Height:    viper.GetInt64(cfgInitialHeight),
 		ChainID:   chainID,
 		Time:      time.Now(),
@@ -198,7 +226,8 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 	}
 
 	stakingStatePath := viper.GetString(cfgStaking)
-	if err := appendStakingState(doc, stakingStatePath); err != nil {
+	stakingLedgerPath := viper.GetString(cfgStakingLedger)
+	if err := appendStakingState(doc, stakingStatePath, stakingLedgerPath); err != nil {
 		logger.Error("failed to append staking genesis state",
 			"err", err,
 		)
@@ -217,9 +246,17 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 
 	doc.Beacon = beacon.Genesis{
 		Parameters: beacon.ConsensusParameters{
+			Backend:            viper.GetString(cfgBeaconBackend),
+			PendingEpochNotice: viper.GetUint64(cfgBeaconPendingEpochNotice),
 			DebugDeterministic: viper.GetBool(cfgBeaconDebugDeterministic),
 		},
 	}
+	if doc.Beacon.Parameters.Backend == beacon.BackendVRF {
+		doc.Beacon.Parameters.VRFParameters = &beacon.VRFParameters{
+			MinProofs: viper.GetUint64(cfgBeaconVRFMinProofs),
+			GasCosts:  beacon.DefaultVRFGasCosts, // TODO: Make these configurable.
+		}
+	}
 
 	doc.EpochTime = epochtime.Genesis{
 		Parameters: epochtime.ConsensusParameters{
@@ -291,11 +328,35 @@ func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []stri
 			GasCosts:                               registry.DefaultGasCosts, // TODO: Make these configurable.
 			MaxNodeExpiration:                      viper.GetUint64(CfgRegistryMaxNodeExpiration),
 			DisableRuntimeRegistration:             viper.GetBool(CfgRegistryDisableRuntimeRegistration),
+			MinHeartbeatInterval:                   viper.GetInt64(CfgRegistryMinHeartbeatInterval),
+			MaxNodesPerEntity:                      uint16(viper.GetUint(CfgRegistryMaxNodesPerEntity)),
 		},
 		Entities: make([]*entity.SignedEntity, 0, len(entities)),
 		Runtimes: make([]*registry.SignedRuntime, 0, len(runtimes)),
 		Nodes:    make([]*node.MultiSignedNode, 0, len(nodes)),
 	}
+	if maxNodes := viper.GetStringMapString(CfgRegistryMaxNodesPerEntityRole); len(maxNodes) > 0 {
+		regSt.Parameters.MaxNodesPerEntityRole = make(map[node.RolesMask]uint16)
+		for roleRaw, countRaw := range maxNodes {
+			role, err := node.RoleFromString(roleRaw)
+			if err != nil {
+				l.Error("invalid role in per-entity node limits",
+					"err", err,
+					"role", roleRaw,
+				)
+				return err
+			}
+			count, err := strconv.ParseUint(countRaw, 10, 16)
+			if err != nil {
+				l.Error("invalid per-entity node limit",
+					"err", err,
+					"role", roleRaw,
+				)
+				return err
+			}
+			regSt.Parameters.MaxNodesPerEntityRole[role] = uint16(count)
+		}
+	}
 
 	entMap := make(map[signature.PublicKey]bool)
 	appendToEntities := func(signedEntity *entity.SignedEntity, ent *entity.Entity) error {
@@ -428,8 +489,12 @@ func AppendRootHashState(doc *genesis.Document, exports []string, l *logging.Log
 		RuntimeStates: make(map[common.Namespace]*registry.RuntimeGenesis),
 
 		Parameters: roothash.ConsensusParameters{
+			MaxExecutorCommitments:    viper.GetUint64(cfgRoothashMaxExecutorCommitments),
+			MaxExecutorCommitmentSize: viper.GetUint64(cfgRoothashMaxExecutorCommitmentSize),
+			MaxEvidenceAge:            viper.GetUint64(cfgRoothashMaxEvidenceAge),
 			DebugDoNotSuspendRuntimes: viper.GetBool(cfgRoothashDebugDoNotSuspendRuntimes),
 			DebugBypassStake:          viper.GetBool(cfgRoothashDebugBypassStake),
+			DebugAllowRuntimeReset:    viper.GetBool(cfgRoothashDebugAllowRuntimeReset),
 			// TODO: Make these configurable.
 			GasCosts: roothash.DefaultGasCosts,
 		},
@@ -504,7 +569,7 @@ func AppendKeyManagerState(doc *genesis.Document, statuses []string, l *logging.
 	return nil
 }
 
-func appendStakingState(doc *genesis.Document, statePath string) error {
+func appendStakingState(doc *genesis.Document, statePath, ledgerPath string) error {
 	var (
 		st  *cmdCmnGenesis.AppendableStakingState
 		err error
@@ -520,6 +585,18 @@ func appendStakingState(doc *genesis.Document, statePath string) error {
 		return err
 	}
 
+	if ledgerPath != "" {
+		var f *os.File
+		if f, err = os.Open(ledgerPath); err != nil {
+			return fmt.Errorf("failed to open staking ledger CSV: %w", err)
+		}
+		defer f.Close()
+
+		if err = st.ImportLedgerCSV(f); err != nil {
+			return err
+		}
+	}
+
 	// Apply config based overrides to the state.
 	st.DebugTestEntity = flags.DebugTestEntity()
 	if tokenSymbol := viper.GetString(CfgStakingTokenSymbol); tokenSymbol != "" {
@@ -601,6 +678,14 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 		cmdCommon.EarlyLogAndExit(err)
 	}
 
+	format := viper.GetString(cfgCheckFormat)
+	switch format {
+	case checkFormatText, checkFormatJSON:
+	default:
+		logger.Error("unsupported output format", "format", format)
+		os.Exit(1)
+	}
+
 	filename := flags.GenesisFile()
 	provider, err := genesisFile.NewFileProvider(filename)
 	if err != nil {
@@ -613,11 +698,15 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	err = doc.SanityCheck()
-	if err != nil {
-		logger.Error("genesis document sanity check failed", "err", err)
-		os.Exit(1)
+	var report genesis.CheckReport
+	if err = doc.SanityCheck(); err != nil {
+		if format == checkFormatText {
+			logger.Error("genesis document sanity check failed", "err", err)
+			os.Exit(1)
+		}
+		report.AddViolation(genesis.ViolationSanityCheckFailed, "genesis", "%s", err)
 	}
+	report.Violations = append(report.Violations, doc.CrossCheck().Violations...)
 
 	// Load raw genesis file.
 	rawFile, err := ioutil.ReadFile(filename)
@@ -632,7 +721,38 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 	// Genesis file should equal the canonical form.
-	if !bytes.Equal(rawFile, rawCanonical) {
+	isCanonical := bytes.Equal(rawFile, rawCanonical)
+
+	if format == checkFormatJSON {
+		if !isCanonical {
+			report.AddViolation(genesis.ViolationNotCanonical, "genesis", "genesis document is not marshalled in the canonical form")
+		}
+		if report.Violations == nil {
+			report.Violations = []genesis.Violation{}
+		}
+		var rawReport []byte
+		if rawReport, err = json.MarshalIndent(report, "", "  "); err != nil {
+			logger.Error("failed to marshal check report", "err", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(rawReport))
+		if !report.IsValid() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if !report.IsValid() {
+		for _, v := range report.Violations {
+			logger.Error("genesis document cross-check failed",
+				"code", v.Code,
+				"module", v.Module,
+				"err", v.Message,
+			)
+		}
+		os.Exit(1)
+	}
+	if !isCanonical {
 		fileLines := strings.Split(string(rawFile), "\n")
 		if len(fileLines) > checkNotCanonicalLines {
 			fileLines = fileLines[:checkNotCanonicalLines]
@@ -652,17 +772,83 @@ func doCheckGenesis(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doMigrateGenesis(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	filename := flags.GenesisFile()
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		logger.Error("failed to read genesis file", "err", err)
+		os.Exit(1)
+	}
+	doc, err := migrations.UnmarshalDocument(raw)
+	if err != nil {
+		logger.Error("failed to parse genesis file", "err", err)
+		os.Exit(1)
+	}
+
+	// Default to migrating from the version of the document.
+	from, err := doc.Version()
+	if err != nil {
+		logger.Error("failed to determine genesis document version", "err", err)
+		os.Exit(1)
+	}
+	if cmd.Flags().Changed(cfgMigrateFrom) {
+		from = viper.GetUint64(cfgMigrateFrom)
+	}
+	to := viper.GetUint64(cfgMigrateTo)
+
+	logger.Info("migrating genesis document",
+		"from", from,
+		"to", to,
+	)
+	if err = migrations.Migrate(doc, from, to); err != nil {
+		logger.Error("failed to migrate genesis document", "err", err)
+		os.Exit(1)
+	}
+
+	var out interface{} = doc
+	if to == genesis.LatestDocumentVersion {
+		// Make sure the migrated document is valid and marshal it in the canonical form.
+		if out, err = doc.ToDocument(); err != nil {
+			logger.Error("migrated genesis document sanity check failed", "err", err)
+			os.Exit(1)
+		}
+	}
+	if raw, err = json.MarshalIndent(out, "", "  "); err != nil {
+		logger.Error("failed to marshal migrated genesis document", "err", err)
+		os.Exit(1)
+	}
+
+	w, shouldClose, err := cmdCommon.GetOutputWriter(cmd, cfgMigrateNewGenesis)
+	if err != nil {
+		logger.Error("failed to get writer for migrated genesis file", "err", err)
+		os.Exit(1)
+	}
+	if shouldClose {
+		defer w.Close()
+	}
+	if _, err = w.Write(raw); err != nil {
+		logger.Error("failed to write migrated genesis file", "err", err)
+		os.Exit(1)
+	}
+}
+
 // Register registers the genesis sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	initGenesisCmd.Flags().AddFlagSet(initGenesisFlags)
 	dumpGenesisCmd.Flags().AddFlagSet(dumpGenesisFlags)
 	dumpGenesisCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	checkGenesisCmd.Flags().AddFlagSet(checkGenesisFlags)
+	migrateGenesisCmd.Flags().AddFlagSet(migrateGenesisFlags)
 
 	for _, v := range []*cobra.Command{
 		initGenesisCmd,
 		dumpGenesisCmd,
 		checkGenesisCmd,
+		migrateGenesisCmd,
 	} {
 		genesisCmd.AddCommand(v)
 	}
@@ -671,6 +857,7 @@ func Register(parentCmd *cobra.Command) {
 }
 
 func init() {
+	checkGenesisFlags.String(cfgCheckFormat, checkFormatText, "output format (text, json)")
 	_ = viper.BindPFlags(checkGenesisFlags)
 	checkGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 
@@ -678,10 +865,17 @@ func init() {
 	_ = viper.BindPFlags(dumpGenesisFlags)
 	dumpGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 
+	migrateGenesisFlags.Uint64(cfgMigrateFrom, 0, "genesis document version to migrate from (default: version of the document)")
+	migrateGenesisFlags.Uint64(cfgMigrateTo, genesis.LatestDocumentVersion, "genesis document version to migrate to")
+	migrateGenesisFlags.String(cfgMigrateNewGenesis, "genesis_migrated.json", "path to migrated genesis document")
+	_ = viper.BindPFlags(migrateGenesisFlags)
+	migrateGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
+
 	initGenesisFlags.StringSlice(cfgRuntime, nil, "path to runtime registration file")
 	initGenesisFlags.StringSlice(cfgNode, nil, "path to node registration file")
 	initGenesisFlags.StringSlice(cfgRootHash, nil, "path to roothash genesis runtime states file")
 	initGenesisFlags.String(cfgStaking, "", "path to staking genesis file")
+	initGenesisFlags.String(cfgStakingLedger, "", "path to staking ledger CSV file to import")
 	initGenesisFlags.StringSlice(cfgKeyManager, nil, "path to key manager genesis status file")
 	initGenesisFlags.String(cfgChainID, "", "genesis chain id")
 	initGenesisFlags.Uint64(cfgHaltEpoch, math.MaxUint64, "genesis halt epoch height")
@@ -690,6 +884,9 @@ func init() {
 	// Registry config flags.
 	initGenesisFlags.Uint64(CfgRegistryMaxNodeExpiration, 5, "maximum node registration lifespan in epochs")
 	initGenesisFlags.Bool(CfgRegistryDisableRuntimeRegistration, false, "disable non-genesis runtime registration")
+	initGenesisFlags.Int64(CfgRegistryMinHeartbeatInterval, 0, "minimum number of blocks between node heartbeats (0 to disable heartbeats)")
+	initGenesisFlags.Uint16(CfgRegistryMaxNodesPerEntity, 0, "maximum number of nodes per entity (0 for no limit)")
+	initGenesisFlags.StringToString(CfgRegistryMaxNodesPerEntityRole, nil, "maximum number of nodes with a given role per entity (role=count)")
 	initGenesisFlags.Bool(cfgRegistryDebugAllowUnroutableAddresses, false, "allow unroutable addreses (UNSAFE)")
 	initGenesisFlags.Bool(CfgRegistryDebugAllowTestRuntimes, false, "enable test runtime registration")
 	initGenesisFlags.Bool(cfgRegistryDebugAllowEntitySignedNodeRegistration, false, "allow entity signed node registration (UNSAFE)")
@@ -709,6 +906,9 @@ func init() {
 	_ = initGenesisFlags.MarkHidden(cfgSchedulerDebugStaticValidators)
 
 	// Beacon config flags.
+	initGenesisFlags.String(cfgBeaconBackend, beacon.BackendInsecure, "beacon backend (insecure, vrf)")
+	initGenesisFlags.Uint64(cfgBeaconVRFMinProofs, 1, "minimum number of VRF proofs required for a VRF-only beacon")
+	initGenesisFlags.Uint64(cfgBeaconPendingEpochNotice, 0, "number of blocks before an epoch transition to emit a pending epoch event at (0 to disable)")
 	initGenesisFlags.Bool(cfgBeaconDebugDeterministic, false, "enable deterministic beacon output (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgBeaconDebugDeterministic)
 
@@ -718,10 +918,15 @@ func init() {
 	_ = initGenesisFlags.MarkHidden(cfgEpochTimeDebugMockBackend)
 
 	// Roothash config flags.
+	initGenesisFlags.Uint64(cfgRoothashMaxExecutorCommitments, 256, "maximum number of outstanding executor commitments per runtime (0 for no limit)")
+	initGenesisFlags.Uint64(cfgRoothashMaxExecutorCommitmentSize, 65536, "maximum size of a single executor commitment in bytes (0 for no limit)")
+	initGenesisFlags.Uint64(cfgRoothashMaxEvidenceAge, 100, "maximum age of submitted evidence in runtime rounds (0 to disable evidence submission)")
 	initGenesisFlags.Bool(cfgRoothashDebugDoNotSuspendRuntimes, false, "do not suspend runtimes (UNSAFE)")
 	initGenesisFlags.Bool(cfgRoothashDebugBypassStake, false, "bypass all roothash stake checks and operations (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugDoNotSuspendRuntimes)
+	initGenesisFlags.Bool(cfgRoothashDebugAllowRuntimeReset, false, "allow runtime owners to reset runtime round state (UNSAFE)")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugBypassStake)
+	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugAllowRuntimeReset)
 
 	// Staking config flags.
 	initGenesisFlags.String(CfgStakingTokenSymbol, "", "token's ticker symbol")
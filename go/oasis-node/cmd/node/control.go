@@ -2,6 +2,8 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -9,6 +11,7 @@ import (
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
 
@@ -19,9 +22,32 @@ var (
 
 // Implements registration.Delegate.
 func (n *Node) RegistrationStopped() {
+	n.syncStorage()
 	n.Stop()
 }
 
+// syncStorage makes sure that local storage for all runtimes is persisted before the node
+// is stopped.
+func (n *Node) syncStorage() {
+	// Seed node doesn't have a runtime registry.
+	if n.RuntimeRegistry == nil || n.StorageWorker == nil {
+		return
+	}
+
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		storageNode := n.StorageWorker.GetRuntime(rt.ID())
+		if storageNode == nil {
+			continue
+		}
+		if err := storageNode.SyncStorage(); err != nil {
+			n.logger.Error("failed to sync local storage",
+				"err", err,
+				"runtime_id", rt.ID(),
+			)
+		}
+	}
+}
+
 // Implements control.ControlledNode.
 func (n *Node) RequestShutdown() (<-chan struct{}, error) {
 	if n.RegistrationWorker == nil {
@@ -148,3 +174,62 @@ func (n *Node) GetRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 	}
 	return runtimes, nil
 }
+
+// Implements control.ControlledNode.
+func (n *Node) RestartSubsystem(ctx context.Context, req *control.RestartSubsystemRequest) error {
+	switch req.Subsystem {
+	case control.SubsystemRegistration:
+		if n.RegistrationWorker == nil {
+			return fmt.Errorf("%w: registration worker not enabled", control.ErrSubsystemNotAvailable)
+		}
+		err := n.RegistrationWorker.Restart(ctx)
+		if errors.Is(err, registration.ErrNotRunning) {
+			return fmt.Errorf("%w: %s", control.ErrSubsystemNotAvailable, err)
+		}
+		return err
+	case control.SubsystemStorageWorker:
+		if n.StorageWorker == nil {
+			return fmt.Errorf("%w: storage worker not enabled", control.ErrSubsystemNotAvailable)
+		}
+		storageNode := n.StorageWorker.GetRuntime(*req.RuntimeID)
+		if storageNode == nil {
+			return fmt.Errorf("%w: no storage worker for runtime %s", control.ErrSubsystemNotAvailable, req.RuntimeID)
+		}
+		return storageNode.Restart(ctx)
+	case control.SubsystemKeyManagerClient:
+		if n.CommonWorker == nil {
+			return fmt.Errorf("%w: runtime workers not enabled", control.ErrSubsystemNotAvailable)
+		}
+		rtNode := n.CommonWorker.GetRuntime(*req.RuntimeID)
+		if rtNode == nil || rtNode.KeyManagerClient == nil {
+			return fmt.Errorf("%w: no key manager client for runtime %s", control.ErrSubsystemNotAvailable, req.RuntimeID)
+		}
+		return rtNode.KeyManagerClient.Restart(ctx)
+	default:
+		return fmt.Errorf("%w: %s", control.ErrUnknownSubsystem, req.Subsystem)
+	}
+}
+
+// Implements control.ControlledNode.
+func (n *Node) GetEpochSnapshots(ctx context.Context) (map[common.Namespace]*commonWorker.EpochSnapshot, error) {
+	snapshots := make(map[common.Namespace]*commonWorker.EpochSnapshot)
+
+	// Seed node doesn't have a runtime registry and non-worker nodes don't have committees.
+	if n.RuntimeRegistry == nil || n.CommonWorker == nil {
+		return snapshots, nil
+	}
+
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		rtNode := n.CommonWorker.GetRuntime(rt.ID())
+		if rtNode == nil {
+			continue
+		}
+
+		snapshot, err := rtNode.GetEpochSnapshot(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get epoch snapshot for runtime %s: %w", rt.ID(), err)
+		}
+		snapshots[rt.ID()] = snapshot
+	}
+	return snapshots, nil
+}
@@ -691,7 +691,7 @@ func newNode(testNode bool) (node *Node, err error) { // nolint: gocyclo
 	logger.Info("starting Oasis node")
 
 	// Initialize Tendermint consensus backend.
-	node.Consensus, err = tendermint.New(node.svcMgr.Ctx, dataDir, node.Identity, node.Upgrader, node.Genesis)
+	node.Consensus, err = tendermint.New(node.svcMgr.Ctx, dataDir, node.Identity, node.Upgrader, node.Genesis, node.commonStore)
 	if err != nil {
 		logger.Error("failed to initialize tendermint service",
 			"err", err,
@@ -762,6 +762,7 @@ func init() {
 	for _, v := range []*flag.FlagSet{
 		metrics.Flags,
 		tracing.Flags,
+		control.Flags,
 		cmdGrpc.ServerLocalFlags,
 		cmdSigner.Flags,
 		pprof.Flags,
@@ -35,6 +35,7 @@ const (
 	CfgNodeDescriptor         = "entity.node.descriptor"
 	CfgReuseSigner            = "entity.reuse_signer"
 
+	entityFilename        = "entity.json"
 	entityGenesisFilename = "entity_genesis.json"
 )
 
@@ -43,6 +44,7 @@ var (
 	initFlags                 = flag.NewFlagSet("", flag.ContinueOnError)
 	updateFlags               = flag.NewFlagSet("", flag.ContinueOnError)
 	registerOrDeregisterFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	signFlags                 = flag.NewFlagSet("", flag.ContinueOnError)
 
 	entityCmd = &cobra.Command{
 		Use:   "entity",
@@ -79,6 +81,24 @@ var (
 		Run:   doList,
 	}
 
+	signCmd = &cobra.Command{
+		Use:   "sign",
+		Short: "sign the entity descriptor or write an offline signing payload",
+		Run:   doSign,
+	}
+
+	signPayloadCmd = &cobra.Command{
+		Use:   "sign_payload",
+		Short: "sign an offline entity descriptor signing payload",
+		Run:   doSignPayload,
+	}
+
+	assembleCmd = &cobra.Command{
+		Use:   "assemble",
+		Short: "assemble a signed entity descriptor from an offline signing payload and signature",
+		Run:   doAssemble,
+	}
+
 	logger = logging.GetLogger("cmd/registry/entity")
 )
 
@@ -360,6 +380,208 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doSign(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	entityDir, err := cmdSigner.CLIDirOrPwd()
+	if err != nil {
+		logger.Error("failed to retrieve entity dir",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if cmdSigner.Offline() {
+		// Only the entity descriptor is needed, the signer may not be available.
+		payloadPath := viper.GetString(cmdSigner.CfgOfflinePayload)
+		if payloadPath == "" {
+			logger.Error("offline signing payload path not specified")
+			os.Exit(1)
+		}
+
+		var ent *entity.Entity
+		if ent, err = entity.LoadDescriptor(filepath.Join(entityDir, entityFilename)); err != nil {
+			logger.Error("failed to load entity descriptor",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		payload := cmdSigner.NewOfflinePayload(
+			registry.RegisterEntitySignatureContext,
+			ent,
+			[]signature.PublicKey{ent.ID},
+		)
+		if err = cmdSigner.SaveOfflinePayload(payloadPath, payload); err != nil {
+			logger.Error("failed to write offline signing payload",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		logger.Info("wrote offline entity signing payload",
+			"entity", ent.ID,
+			"path", payloadPath,
+		)
+		return
+	}
+
+	ent, signer, err := cmdCommon.LoadEntity(cmdSigner.Backend(), entityDir)
+	if err != nil {
+		logger.Error("failed to load entity",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	signed, err := entity.SignEntity(signer, registry.RegisterEntitySignatureContext, ent)
+	if err != nil {
+		logger.Error("failed to sign entity descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	writeSignedEntity(signed)
+}
+
+func doSignPayload(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	payload, ent := loadOfflinePayload()
+
+	sigPaths := viper.GetStringSlice(cmdSigner.CfgOfflineSignature)
+	if len(sigPaths) != 1 {
+		logger.Error("exactly one signature path must be specified")
+		os.Exit(1)
+	}
+
+	entityDir, err := cmdSigner.CLIDirOrPwd()
+	if err != nil {
+		logger.Error("failed to retrieve entity dir",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	// Only the signer is needed, the entity descriptor is part of the payload.
+	entitySignerFactory, err := cmdSigner.NewFactory(cmdSigner.Backend(), entityDir, signature.SignerEntity)
+	if err != nil {
+		logger.Error("failed to create signer factory",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	signer, err := entitySignerFactory.Load(signature.SignerEntity)
+	if err != nil {
+		logger.Error("failed to load entity signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	fmt.Printf("You are about to sign the following entity descriptor:\n")
+	b, _ := json.MarshalIndent(ent, "  ", "  ")
+	fmt.Printf("  %s\n", b)
+	if !cmdFlags.AssumeYes() {
+		if !cmdCommon.GetUserConfirmation("\nAre you sure you want to continue? (y)es/(n)o: ") {
+			os.Exit(1)
+		}
+	}
+
+	sig, err := payload.Sign(signer)
+	if err != nil {
+		logger.Error("failed to sign offline signing payload",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = cmdSigner.SaveSignatures(sigPaths[0], []signature.Signature{*sig}); err != nil {
+		logger.Error("failed to write signature",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doAssemble(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	payload, _ := loadOfflinePayload()
+
+	sigs, err := cmdSigner.LoadSignatures(viper.GetStringSlice(cmdSigner.CfgOfflineSignature))
+	if err != nil {
+		logger.Error("failed to load signatures",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if len(sigs) != 1 {
+		logger.Error("exactly one signature must be specified")
+		os.Exit(1)
+	}
+
+	signed, err := payload.Signed(sigs[0])
+	if err != nil {
+		logger.Error("failed to assemble signed entity descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	writeSignedEntity(&entity.SignedEntity{Signed: *signed})
+}
+
+func loadOfflinePayload() (*cmdSigner.OfflinePayload, *entity.Entity) {
+	payload, err := cmdSigner.LoadOfflinePayload(viper.GetString(cmdSigner.CfgOfflinePayload))
+	if err != nil {
+		logger.Error("failed to load offline signing payload",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if payload.Context != registry.RegisterEntitySignatureContext {
+		logger.Error("offline signing payload is not an entity descriptor",
+			"context", payload.Context,
+		)
+		os.Exit(1)
+	}
+
+	var ent entity.Entity
+	if err = cbor.Unmarshal(payload.Blob, &ent); err != nil {
+		logger.Error("failed to parse entity descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if len(payload.Signers) != 1 || !payload.Signers[0].Equal(ent.ID) {
+		logger.Error("offline signing payload must be signed by the entity")
+		os.Exit(1)
+	}
+	return payload, &ent
+}
+
+func writeSignedEntity(signed *entity.SignedEntity) {
+	outputPath := viper.GetString(cmdSigner.CfgSignedOutput)
+	if outputPath == "" {
+		logger.Error("signed entity descriptor output path not specified")
+		os.Exit(1)
+	}
+
+	b, _ := json.Marshal(signed)
+	if err := ioutil.WriteFile(outputPath, b, 0o600); err != nil {
+		logger.Error("failed to write signed entity descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
 func loadOrGenerateEntity(dataDir string, generate bool) (*entity.Entity, signature.Signer, error) {
 	if cmdFlags.DebugTestEntity() {
 		return entity.TestEntity()
@@ -409,6 +631,9 @@ func Register(parentCmd *cobra.Command) {
 		registerCmd,
 		deregisterCmd,
 		listCmd,
+		signCmd,
+		signPayloadCmd,
+		assembleCmd,
 	} {
 		entityCmd.AddCommand(v)
 	}
@@ -421,6 +646,11 @@ func Register(parentCmd *cobra.Command) {
 	listCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
 	listCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	signCmd.Flags().AddFlagSet(signFlags)
+	signPayloadCmd.Flags().AddFlagSet(signFlags)
+	signPayloadCmd.Flags().AddFlagSet(cmdFlags.AssumeYesFlag)
+	assembleCmd.Flags().AddFlagSet(cmdSigner.OfflineFlags)
+
 	parentCmd.AddCommand(entityCmd)
 }
 
@@ -448,4 +678,9 @@ func init() {
 	registerOrDeregisterFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	registerOrDeregisterFlags.AddFlagSet(cmdConsensus.TxFlags)
 	registerOrDeregisterFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
+	signFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	signFlags.AddFlagSet(cmdSigner.Flags)
+	signFlags.AddFlagSet(cmdSigner.CLIFlags)
+	signFlags.AddFlagSet(cmdSigner.OfflineFlags)
 }
@@ -2,12 +2,14 @@
 package node
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -23,11 +25,15 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	tmcrypto "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/crypto"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
 )
 
@@ -40,6 +46,8 @@ const (
 	CfgRole             = "node.role"
 	CfgSelfSigned       = "node.is_self_signed"
 	CfgNodeRuntimeID    = "node.runtime.id"
+	CfgHeight           = "height"
+	CfgStaleEpochs      = "node.stale_epochs"
 
 	optRoleComputeWorker = "compute-worker"
 	optRoleStorageWorker = "storage-worker"
@@ -52,7 +60,11 @@ const (
 )
 
 var (
-	flags = flag.NewFlagSet("", flag.ContinueOnError)
+	flags              = flag.NewFlagSet("", flag.ContinueOnError)
+	heightFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	staleFlags         = flag.NewFlagSet("", flag.ContinueOnError)
+	registerBatchFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	signFlags          = flag.NewFlagSet("", flag.ContinueOnError)
 
 	nodeCmd = &cobra.Command{
 		Use:   "node",
@@ -77,6 +89,51 @@ var (
 		Run:   doIsRegistered,
 	}
 
+	validateCmd = &cobra.Command{
+		Use:   "validate <signed-node-descriptor.json>",
+		Short: "validate a signed node descriptor against the registry without registering it",
+		Args:  cobra.ExactArgs(1),
+		Run:   doValidate,
+	}
+
+	validatorPeersCmd = &cobra.Command{
+		Use:   "validator-peers",
+		Short: "list consensus addresses of the validator set in Tendermint peer format",
+		Run:   doValidatorPeers,
+	}
+
+	staleCmd = &cobra.Command{
+		Use:   "stale",
+		Short: "report registered nodes whose descriptors are approaching expiration",
+		Run:   doStale,
+	}
+
+	genRegisterBatchCmd = &cobra.Command{
+		Use:   "gen_register_batch <descriptor-dir>",
+		Short: "generate a batch register nodes transaction from a directory of signed node descriptors",
+		Args:  cobra.ExactArgs(1),
+		Run:   doGenRegisterBatch,
+	}
+
+	signCmd = &cobra.Command{
+		Use:   "sign <node-descriptor.json>",
+		Short: "sign a node descriptor or write an offline signing payload",
+		Args:  cobra.ExactArgs(1),
+		Run:   doSign,
+	}
+
+	signPayloadCmd = &cobra.Command{
+		Use:   "sign_payload",
+		Short: "sign an offline node descriptor signing payload",
+		Run:   doSignPayload,
+	}
+
+	assembleCmd = &cobra.Command{
+		Use:   "assemble",
+		Short: "assemble a signed node descriptor from an offline signing payload and signatures",
+		Run:   doAssemble,
+	}
+
 	logger = logging.GetLogger("cmd/registry/node")
 )
 
@@ -197,6 +254,9 @@ func doInit(cmd *cobra.Command, args []string) { // nolint: gocyclo
 		Consensus: node.ConsensusInfo{
 			ID: nodeIdentity.ConsensusSigner.Public(),
 		},
+		VRF: &node.VRFInfo{
+			ID: nodeIdentity.VRFKey.Public(),
+		},
 	}
 	if n.Roles, err = argsToRolesMask(); err != nil {
 		logger.Error("failed to parse node roles mask",
@@ -401,6 +461,448 @@ func doIsRegistered(cmd *cobra.Command, args []string) {
 	os.Exit(1)
 }
 
+func doValidate(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	rawSigNode, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		logger.Error("failed to read signed node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	var sigNode node.MultiSignedNode
+	if err = json.Unmarshal(rawSigNode, &sigNode); err != nil {
+		logger.Error("failed to parse signed node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	result, err := client.ValidateNode(context.Background(), &registry.ValidateNodeQuery{
+		Height: consensus.HeightLatest,
+		Node:   &sigNode,
+	})
+	if err != nil {
+		logger.Error("failed to validate node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if result.IsValid() {
+		fmt.Println("node descriptor is valid")
+		return
+	}
+	for _, v := range result.Violations {
+		fmt.Printf("%s: %s\n", v.Check, v.Message)
+	}
+	os.Exit(1)
+}
+
+func doValidatorPeers(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+	schedClient := scheduler.NewSchedulerClient(conn)
+
+	ctx := context.Background()
+	height := viper.GetInt64(CfgHeight)
+	validators, err := schedClient.GetValidators(ctx, height)
+	if err != nil {
+		logger.Error("failed to query validators",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var peers []string
+	seen := make(map[string]bool)
+	for _, v := range validators {
+		var n *node.Node
+		n, err = client.GetNode(ctx, &registry.IDQuery{Height: height, ID: v.ID})
+		if err != nil {
+			logger.Error("failed to query validator node",
+				"err", err,
+				"id", v.ID,
+			)
+			os.Exit(1)
+		}
+
+		for i := range n.Consensus.Addresses {
+			peer := tmcrypto.ConsensusAddressToTendermint(&n.Consensus.Addresses[i])
+			if seen[peer] {
+				continue
+			}
+			seen[peer] = true
+			peers = append(peers, peer)
+		}
+	}
+
+	// The comma-separated list can be used directly for the Tendermint persistent peers and
+	// seeds configuration, while the verbose output lists one peer per line.
+	switch cmdFlags.Verbose() {
+	case true:
+		for _, peer := range peers {
+			fmt.Println(peer)
+		}
+	default:
+		fmt.Println(strings.Join(peers, ","))
+	}
+}
+
+func doStale(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	stale, err := client.GetStaleNodes(context.Background(), &registry.StaleNodesQuery{
+		Height: viper.GetInt64(CfgHeight),
+		Epochs: epochtime.EpochTime(viper.GetUint64(CfgStaleEpochs)),
+	})
+	if err != nil {
+		logger.Error("failed to query stale nodes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if cmdFlags.Verbose() {
+		b, _ := json.Marshal(stale)
+		fmt.Println(string(b))
+		return
+	}
+
+	fmt.Printf("Epoch: %d\n", stale.Epoch)
+	fmt.Printf("Stale nodes: %d\n", len(stale.Nodes))
+	for _, n := range stale.Nodes {
+		fmt.Printf("  %s (entity: %s, expiration: %d)\n", n.ID, n.EntityID, n.Expiration)
+	}
+
+	entities := make([]signature.PublicKey, 0, len(stale.EntityCounts))
+	for id := range stale.EntityCounts {
+		entities = append(entities, id)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return stale.EntityCounts[entities[i]] > stale.EntityCounts[entities[j]] ||
+			(stale.EntityCounts[entities[i]] == stale.EntityCounts[entities[j]] &&
+				bytes.Compare(entities[i][:], entities[j][:]) < 0)
+	})
+	fmt.Println("Stale nodes by entity:")
+	for _, id := range entities {
+		fmt.Printf("  %s: %d\n", id, stale.EntityCounts[id])
+	}
+}
+
+func loadSignedNodeDescriptors(dir string) ([]*node.MultiSignedNode, error) {
+	// Glob returns the matches in lexical order, so the batch is deterministic.
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no signed node descriptors found in '%s'", dir)
+	}
+
+	var sigNodes []*node.MultiSignedNode
+	for _, path := range paths {
+		var rawSigNode []byte
+		if rawSigNode, err = ioutil.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+		var sigNode node.MultiSignedNode
+		if err = json.Unmarshal(rawSigNode, &sigNode); err != nil {
+			return nil, fmt.Errorf("failed to parse '%s': %w", path, err)
+		}
+		sigNodes = append(sigNodes, &sigNode)
+	}
+	return sigNodes, nil
+}
+
+func doGenRegisterBatch(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	sigNodes, err := loadSignedNodeDescriptors(args[0])
+	if err != nil {
+		logger.Error("failed to load signed node descriptors",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	batch := &registry.RegisterNodes{Nodes: sigNodes}
+	if err = batch.ValidateBasic(); err != nil {
+		logger.Error("invalid node registration batch",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := registry.NewRegisterNodesTx(nonce, fee, batch)
+
+	cmdConsensus.SignAndSaveTx(context.Background(), tx)
+}
+
+// nodeDescriptorSigners returns the public keys that are expected to sign the
+// (self-signed) node descriptor.
+func nodeDescriptorSigners(n *node.Node) []signature.PublicKey {
+	return []signature.PublicKey{
+		n.ID,
+		n.P2P.ID,
+		n.Consensus.ID,
+		n.TLS.PubKey,
+	}
+}
+
+// loadNodeIdentitySigners loads the node identity and returns the signers for
+// the keys expected to sign the given node descriptor.
+func loadNodeIdentitySigners(n *node.Node) ([]signature.Signer, error) {
+	dataDir, err := cmdCommon.DataDirOrPwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data directory: %w", err)
+	}
+	nodeSignerFactory, err := cmdSigner.NewFactory(
+		cmdSigner.Backend(),
+		dataDir,
+		signature.SignerNode,
+		signature.SignerP2P,
+		signature.SignerConsensus,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize signer backend: %w", err)
+	}
+	nodeIdentity, err := identity.Load(dataDir, nodeSignerFactory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load node identity: %w", err)
+	}
+
+	signers := []signature.Signer{
+		nodeIdentity.NodeSigner,
+		nodeIdentity.P2PSigner,
+		nodeIdentity.ConsensusSigner,
+		nodeIdentity.GetTLSSigner(),
+	}
+	for i, pk := range nodeDescriptorSigners(n) {
+		if !signers[i].Public().Equal(pk) {
+			return nil, fmt.Errorf("node identity key mismatch (expected: %s got: %s)", pk, signers[i].Public())
+		}
+	}
+	return signers, nil
+}
+
+func writeSignedNode(signed *node.MultiSignedNode) {
+	outputPath := viper.GetString(cmdSigner.CfgSignedOutput)
+	if outputPath == "" {
+		logger.Error("signed node descriptor output path not specified")
+		os.Exit(1)
+	}
+
+	b, _ := json.Marshal(signed)
+	if err := ioutil.WriteFile(outputPath, b, 0o600); err != nil {
+		logger.Error("failed to write signed node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doSign(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	rawNode, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		logger.Error("failed to read node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	var n node.Node
+	if err = json.Unmarshal(rawNode, &n); err != nil {
+		logger.Error("failed to parse node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = n.ValidateBasic(false); err != nil {
+		logger.Error("invalid node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	if cmdSigner.Offline() {
+		payloadPath := viper.GetString(cmdSigner.CfgOfflinePayload)
+		if payloadPath == "" {
+			logger.Error("offline signing payload path not specified")
+			os.Exit(1)
+		}
+
+		payload := cmdSigner.NewOfflinePayload(
+			registry.RegisterNodeSignatureContext,
+			&n,
+			nodeDescriptorSigners(&n),
+		)
+		if err = cmdSigner.SaveOfflinePayload(payloadPath, payload); err != nil {
+			logger.Error("failed to write offline signing payload",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		logger.Info("wrote offline node signing payload",
+			"node", n.ID,
+			"path", payloadPath,
+		)
+		return
+	}
+
+	signers, err := loadNodeIdentitySigners(&n)
+	if err != nil {
+		logger.Error("failed to load node identity signers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	signed, err := node.MultiSignNode(signers, registry.RegisterNodeSignatureContext, &n)
+	if err != nil {
+		logger.Error("failed to sign node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	writeSignedNode(signed)
+}
+
+func loadOfflinePayload() (*cmdSigner.OfflinePayload, *node.Node) {
+	payload, err := cmdSigner.LoadOfflinePayload(viper.GetString(cmdSigner.CfgOfflinePayload))
+	if err != nil {
+		logger.Error("failed to load offline signing payload",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if payload.Context != registry.RegisterNodeSignatureContext {
+		logger.Error("offline signing payload is not a node descriptor",
+			"context", payload.Context,
+		)
+		os.Exit(1)
+	}
+
+	var n node.Node
+	if err = cbor.Unmarshal(payload.Blob, &n); err != nil {
+		logger.Error("failed to parse node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	expected := nodeDescriptorSigners(&n)
+	if len(payload.Signers) != len(expected) {
+		logger.Error("offline signing payload has unexpected signers")
+		os.Exit(1)
+	}
+	for i, pk := range expected {
+		if !payload.Signers[i].Equal(pk) {
+			logger.Error("offline signing payload has unexpected signers")
+			os.Exit(1)
+		}
+	}
+	return payload, &n
+}
+
+func doSignPayload(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	payload, n := loadOfflinePayload()
+
+	sigPaths := viper.GetStringSlice(cmdSigner.CfgOfflineSignature)
+	if len(sigPaths) != 1 {
+		logger.Error("exactly one signature path must be specified")
+		os.Exit(1)
+	}
+
+	signers, err := loadNodeIdentitySigners(n)
+	if err != nil {
+		logger.Error("failed to load node identity signers",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("You are about to sign the following node descriptor:\n")
+	b, _ := json.MarshalIndent(n, "  ", "  ")
+	fmt.Printf("  %s\n", b)
+	if !cmdFlags.AssumeYes() {
+		if !cmdCommon.GetUserConfirmation("\nAre you sure you want to continue? (y)es/(n)o: ") {
+			os.Exit(1)
+		}
+	}
+
+	sigs := make([]signature.Signature, 0, len(signers))
+	for _, signer := range signers {
+		var sig *signature.Signature
+		if sig, err = payload.Sign(signer); err != nil {
+			logger.Error("failed to sign offline signing payload",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		sigs = append(sigs, *sig)
+	}
+	if err = cmdSigner.SaveSignatures(sigPaths[0], sigs); err != nil {
+		logger.Error("failed to write signatures",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doAssemble(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	payload, _ := loadOfflinePayload()
+
+	sigs, err := cmdSigner.LoadSignatures(viper.GetStringSlice(cmdSigner.CfgOfflineSignature))
+	if err != nil {
+		logger.Error("failed to load signatures",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	signed, err := payload.MultiSigned(sigs)
+	if err != nil {
+		logger.Error("failed to assemble signed node descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	writeSignedNode(&node.MultiSignedNode{MultiSigned: *signed})
+}
+
 // Register registers the node sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	initCmd.Flags().AddFlagSet(flags)
@@ -413,10 +915,35 @@ func Register(parentCmd *cobra.Command) {
 
 	isRegisteredCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	validateCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	validatorPeersCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	validatorPeersCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	validatorPeersCmd.Flags().AddFlagSet(heightFlags)
+
+	staleCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	staleCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	staleCmd.Flags().AddFlagSet(heightFlags)
+	staleCmd.Flags().AddFlagSet(staleFlags)
+
+	genRegisterBatchCmd.Flags().AddFlagSet(registerBatchFlags)
+
+	signCmd.Flags().AddFlagSet(signFlags)
+	signPayloadCmd.Flags().AddFlagSet(signFlags)
+	signPayloadCmd.Flags().AddFlagSet(cmdFlags.AssumeYesFlag)
+	assembleCmd.Flags().AddFlagSet(cmdSigner.OfflineFlags)
+
 	for _, subCmd := range []*cobra.Command{
 		initCmd,
 		listCmd,
 		isRegisteredCmd,
+		validateCmd,
+		validatorPeersCmd,
+		staleCmd,
+		genRegisterBatchCmd,
+		signCmd,
+		signPayloadCmd,
+		assembleCmd,
 	} {
 		nodeCmd.AddCommand(subCmd)
 	}
@@ -434,4 +961,17 @@ func init() {
 	flags.StringSlice(CfgNodeRuntimeID, nil, "Hex Encoded Runtime ID(s) of the node.")
 
 	_ = viper.BindPFlags(flags)
+
+	heightFlags.Int64(CfgHeight, consensus.HeightLatest, "Consensus height at which to query")
+	_ = viper.BindPFlags(heightFlags)
+
+	staleFlags.Uint64(CfgStaleEpochs, 1, "Report nodes whose descriptors expire within this many epochs")
+	_ = viper.BindPFlags(staleFlags)
+
+	registerBatchFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	registerBatchFlags.AddFlagSet(cmdConsensus.TxFlags)
+	registerBatchFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
+	signFlags.AddFlagSet(cmdSigner.Flags)
+	signFlags.AddFlagSet(cmdSigner.OfflineFlags)
 }
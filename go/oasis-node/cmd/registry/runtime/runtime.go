@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -23,6 +24,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
@@ -34,24 +36,33 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 const (
-	CfgID             = "runtime.id"
-	CfgTEEHardware    = "runtime.tee_hardware"
-	CfgGenesisState   = "runtime.genesis.state"
-	CfgGenesisRound   = "runtime.genesis.round"
-	CfgKind           = "runtime.kind"
-	CfgKeyManager     = "runtime.keymanager"
-	cfgOutput         = "runtime.genesis.file"
-	CfgVersion        = "runtime.version"
-	CfgVersionEnclave = "runtime.version.enclave"
+	CfgID                = "runtime.id"
+	CfgTEEHardware       = "runtime.tee_hardware"
+	CfgGenesisState      = "runtime.genesis.state"
+	CfgGenesisRound      = "runtime.genesis.round"
+	CfgGenesisCheckpoint = "runtime.genesis.checkpoint"
+	CfgKind              = "runtime.kind"
+	CfgKeyManager        = "runtime.keymanager"
+	cfgOutput            = "runtime.genesis.file"
+	CfgVersion           = "runtime.version"
+	CfgVersionEnclave    = "runtime.version.enclave"
+
+	// SGX attestation policy flags.
+	CfgAttestationMaxAge               = "runtime.attestation.max_age"
+	CfgAttestationMinQESVN             = "runtime.attestation.min_qe_svn"
+	CfgAttestationMinPCESVN            = "runtime.attestation.min_pce_svn"
+	CfgAttestationAllowedQuoteStatuses = "runtime.attestation.allowed_quote_statuses"
 
 	// Executor committee flags.
-	CfgExecutorGroupSize         = "runtime.executor.group_size"
-	CfgExecutorGroupBackupSize   = "runtime.executor.group_backup_size"
-	CfgExecutorAllowedStragglers = "runtime.executor.allowed_stragglers"
-	CfgExecutorRoundTimeout      = "runtime.executor.round_timeout"
+	CfgExecutorGroupSize               = "runtime.executor.group_size"
+	CfgExecutorGroupBackupSize         = "runtime.executor.group_backup_size"
+	CfgExecutorAllowedStragglers       = "runtime.executor.allowed_stragglers"
+	CfgExecutorRoundTimeout            = "runtime.executor.round_timeout"
+	CfgExecutorMinLiveCommitteePercent = "runtime.executor.min_live_committee_percent"
 
 	// Storage committee flags.
 	CfgStorageGroupSize               = "runtime.storage.group_size"
@@ -70,10 +81,13 @@ const (
 	CfgTxnSchedulerProposerTimeout   = "runtime.txn_scheduler.proposer_timeout"
 
 	// Admission policy flags.
-	CfgAdmissionPolicy                 = "runtime.admission_policy"
-	CfgAdmissionPolicyEntityWhitelist  = "runtime.admission_policy_entity_whitelist"
-	AdmissionPolicyNameAnyNode         = "any-node"
-	AdmissionPolicyNameEntityWhitelist = "entity-whitelist"
+	CfgAdmissionPolicy                      = "runtime.admission_policy"
+	CfgAdmissionPolicyEntityWhitelist       = "runtime.admission_policy_entity_whitelist"
+	CfgAdmissionPolicyNodeWhitelist         = "runtime.admission_policy_node_whitelist"
+	CfgAdmissionPolicyNodeWhitelistMaxNodes = "runtime.admission_policy_node_whitelist_max_nodes"
+	AdmissionPolicyNameAnyNode              = "any-node"
+	AdmissionPolicyNameEntityWhitelist      = "entity-whitelist"
+	AdmissionPolicyNameNodeWhitelist        = "node-whitelist"
 
 	// Staking parameters flags.
 	CfgStakingThreshold = "runtime.staking.threshold"
@@ -307,8 +321,44 @@ func runtimeFromFlags() (*registry.Runtime, signature.Signer, error) { // nolint
 	// TODO: Support root upload when registering.
 	gen := registry.RuntimeGenesis{}
 	gen.Round = viper.GetUint64(CfgGenesisRound)
-	switch state := viper.GetString(CfgGenesisState); state {
-	case "":
+	switch state, cp := viper.GetString(CfgGenesisState), viper.GetString(CfgGenesisCheckpoint); {
+	case state != "" && cp != "":
+		logger.Error("runtime genesis state and checkpoint are mutually exclusive")
+		return nil, nil, fmt.Errorf("invalid runtime genesis flags")
+	case cp != "":
+		var b []byte
+		b, err = ioutil.ReadFile(cp)
+		if err != nil {
+			logger.Error("failed to load runtime genesis checkpoint metadata",
+				"err", err,
+				"filename", cp,
+			)
+			return nil, nil, err
+		}
+
+		var meta checkpoint.Metadata
+		if err = json.Unmarshal(b, &meta); err != nil {
+			logger.Error("failed to parse runtime genesis checkpoint metadata",
+				"err", err,
+				"filename", cp,
+			)
+			return nil, nil, err
+		}
+		if !meta.Root.Namespace.Equal(&id) || meta.Root.Version != gen.Round {
+			logger.Error("runtime genesis checkpoint root mismatch",
+				"root", meta.Root,
+				"id", id,
+				"round", gen.Round,
+			)
+			return nil, nil, fmt.Errorf("invalid runtime genesis checkpoint")
+		}
+
+		gen.StateRoot = meta.Root.Hash
+		gen.StateCheckpoint = &registry.RuntimeGenesisCheckpoint{
+			Version: meta.Version,
+			Chunks:  meta.Chunks,
+		}
+	case state == "":
 		gen.StateRoot.Empty()
 	default:
 		var b []byte
@@ -370,10 +420,11 @@ func runtimeFromFlags() (*registry.Runtime, signature.Signer, error) { // nolint
 		},
 		KeyManager: kmID,
 		Executor: registry.ExecutorParameters{
-			GroupSize:         viper.GetUint64(CfgExecutorGroupSize),
-			GroupBackupSize:   viper.GetUint64(CfgExecutorGroupBackupSize),
-			AllowedStragglers: viper.GetUint64(CfgExecutorAllowedStragglers),
-			RoundTimeout:      viper.GetInt64(CfgExecutorRoundTimeout),
+			GroupSize:               viper.GetUint64(CfgExecutorGroupSize),
+			GroupBackupSize:         viper.GetUint64(CfgExecutorGroupBackupSize),
+			AllowedStragglers:       viper.GetUint64(CfgExecutorAllowedStragglers),
+			RoundTimeout:            viper.GetInt64(CfgExecutorRoundTimeout),
+			MinLiveCommitteePercent: uint8(viper.GetUint(CfgExecutorMinLiveCommitteePercent)),
 		},
 		TxnScheduler: registry.TxnSchedulerParameters{
 			Algorithm:         viper.GetString(CfgTxnSchedulerAlgorithm),
@@ -404,6 +455,12 @@ func runtimeFromFlags() (*registry.Runtime, signature.Signer, error) { // nolint
 			}
 			vi.Enclaves = append(vi.Enclaves, enclaveID)
 		}
+		if vi.Policy, err = attestationPolicyFromFlags(); err != nil {
+			logger.Error("failed to parse SGX attestation policy",
+				"err", err,
+			)
+			return nil, nil, err
+		}
 		rt.Version.TEE = cbor.Marshal(vi)
 	}
 	switch sap := viper.GetString(CfgAdmissionPolicy); sap {
@@ -425,6 +482,13 @@ func runtimeFromFlags() (*registry.Runtime, signature.Signer, error) { // nolint
 		rt.AdmissionPolicy.EntityWhitelist = &registry.EntityWhitelistRuntimeAdmissionPolicy{
 			Entities: entities,
 		}
+	case AdmissionPolicyNameNodeWhitelist:
+		if rt.AdmissionPolicy.NodeWhitelist, err = nodeWhitelistAdmissionPolicyFromFlags(); err != nil {
+			logger.Error("failed to parse node whitelist runtime admission policy",
+				"err", err,
+			)
+			return nil, nil, err
+		}
 	default:
 		logger.Error("invalid runtime admission policy",
 			CfgAdmissionPolicy, sap,
@@ -514,6 +578,63 @@ func Register(parentCmd *cobra.Command) {
 	parentCmd.AddCommand(runtimeCmd)
 }
 
+func attestationPolicyFromFlags() (*registry.AttestationPolicyIntelSGX, error) {
+	policy := registry.AttestationPolicyIntelSGX{
+		MaxAttestationAge: uint64(viper.GetDuration(CfgAttestationMaxAge) / time.Second),
+		MinQESVN:          uint16(viper.GetUint(CfgAttestationMinQESVN)),
+		MinPCESVN:         uint16(viper.GetUint(CfgAttestationMinPCESVN)),
+	}
+	for _, v := range viper.GetStringSlice(CfgAttestationAllowedQuoteStatuses) {
+		var status ias.ISVEnclaveQuoteStatus
+		if err := status.UnmarshalText([]byte(v)); err != nil {
+			return nil, err
+		}
+		policy.AllowedQuoteStatuses = append(policy.AllowedQuoteStatuses, status)
+	}
+	if policy.MaxAttestationAge == 0 && policy.MinQESVN == 0 && policy.MinPCESVN == 0 && len(policy.AllowedQuoteStatuses) == 0 {
+		// No attestation policy configured.
+		return nil, nil
+	}
+	if err := policy.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func nodeWhitelistAdmissionPolicyFromFlags() (*registry.NodeWhitelistRuntimeAdmissionPolicy, error) {
+	policy := registry.NodeWhitelistRuntimeAdmissionPolicy{
+		Nodes: make(map[signature.PublicKey]bool),
+	}
+	for _, sn := range viper.GetStringSlice(CfgAdmissionPolicyNodeWhitelist) {
+		var n signature.PublicKey
+		if err := n.UnmarshalText([]byte(sn)); err != nil {
+			return nil, fmt.Errorf("bad node ID (%s): %w", sn, err)
+		}
+		policy.Nodes[n] = true
+	}
+
+	if maxNodes := viper.GetStringMapString(CfgAdmissionPolicyNodeWhitelistMaxNodes); len(maxNodes) > 0 {
+		policy.MaxNodes = make(map[node.RolesMask]uint16)
+		for roleRaw, countRaw := range maxNodes {
+			role, err := node.RoleFromString(roleRaw)
+			if err != nil {
+				return nil, fmt.Errorf("bad role (%s): %w", roleRaw, err)
+			}
+
+			count, err := strconv.ParseUint(countRaw, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("bad maximum number of %s nodes (%s): %w", roleRaw, countRaw, err)
+			}
+			policy.MaxNodes[role] = uint16(count)
+		}
+	}
+
+	if err := policy.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
 func init() {
 	outputFlags.String(cfgOutput, runtimeGenesisFilename, "File name of the document to be written under datadir")
 	_ = viper.BindPFlags(outputFlags)
@@ -522,16 +643,24 @@ func init() {
 	runtimeFlags.String(CfgTEEHardware, "invalid", "Type of TEE hardware.  Supported values are \"invalid\" and \"intel-sgx\"")
 	runtimeFlags.String(CfgGenesisState, "", "Runtime state at genesis")
 	runtimeFlags.Uint64(CfgGenesisRound, 0, "Runtime round at genesis")
+	runtimeFlags.String(CfgGenesisCheckpoint, "", "Runtime state checkpoint metadata at genesis")
 	runtimeFlags.String(CfgKeyManager, "", "Key Manager Runtime ID")
 	runtimeFlags.String(CfgKind, "compute", "Kind of runtime.  Supported values are \"compute\" and \"keymanager\"")
 	runtimeFlags.String(CfgVersion, "", "Runtime version. Value is 64-bit hex e.g. 0x0000000100020003 for 1.2.3")
 	runtimeFlags.StringSlice(CfgVersionEnclave, nil, "Runtime TEE enclave version(s)")
 
+	// Init SGX attestation policy flags.
+	runtimeFlags.Duration(CfgAttestationMaxAge, 0, "Maximum age of SGX attestations (0 = unlimited)")
+	runtimeFlags.Uint16(CfgAttestationMinQESVN, 0, "Minimum SGX quoting enclave SVN")
+	runtimeFlags.Uint16(CfgAttestationMinPCESVN, 0, "Minimum SGX provisioning certification enclave SVN")
+	runtimeFlags.StringSlice(CfgAttestationAllowedQuoteStatuses, nil, "Allowed SGX quote statuses (e.g., OK, GROUP_OUT_OF_DATE), defaults to OK only")
+
 	// Init Executor committee flags.
 	runtimeFlags.Uint64(CfgExecutorGroupSize, 1, "Number of workers in the runtime executor group/committee")
 	runtimeFlags.Uint64(CfgExecutorGroupBackupSize, 0, "Number of backup workers in the runtime executor group/committee")
 	runtimeFlags.Uint64(CfgExecutorAllowedStragglers, 0, "Number of stragglers allowed per round in the runtime executor group")
 	runtimeFlags.Int64(CfgExecutorRoundTimeout, 5, "Executor committee round timeout for this runtime (in consensus blocks)")
+	runtimeFlags.Uint8(CfgExecutorMinLiveCommitteePercent, 0, "Minimum percentage of executor committee members that must be registered and not frozen for a round to proceed (0 disables the check)")
 
 	// Init Transaction scheduler flags.
 	runtimeFlags.String(CfgTxnSchedulerAlgorithm, registry.TxnSchedulerSimple, "Transaction scheduling algorithm")
@@ -552,6 +681,8 @@ func init() {
 	// Init Admission policy flags.
 	runtimeFlags.String(CfgAdmissionPolicy, "", "What type of node admission policy to have")
 	runtimeFlags.StringSlice(CfgAdmissionPolicyEntityWhitelist, nil, "For entity whitelist node admission policies, the IDs (hex) of the entities in the whitelist")
+	runtimeFlags.StringSlice(CfgAdmissionPolicyNodeWhitelist, nil, "For node whitelist node admission policies, the IDs (hex) of the nodes in the whitelist")
+	runtimeFlags.StringToString(CfgAdmissionPolicyNodeWhitelistMaxNodes, nil, "For node whitelist node admission policies, the maximum number of nodes with a given role (<role>=<count>)")
 
 	// Init Staking flags.
 	runtimeFlags.StringToString(CfgStakingThreshold, nil, "Additional staking threshold for this runtime (<kind>=<value>)")
@@ -2,13 +2,17 @@
 package signer
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	ledgerSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/ledger"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 var (
@@ -23,6 +27,17 @@ var (
 		Run:   doExport,
 	}
 
+	ledgerCmd = &cobra.Command{
+		Use:   "ledger",
+		Short: "Ledger signer utilities",
+	}
+
+	ledgerShowAddressCmd = &cobra.Command{
+		Use:   "show-address",
+		Short: "display the account address on the Ledger device for verification",
+		Run:   doLedgerShowAddress,
+	}
+
 	logger = logging.GetLogger("cmd/signer")
 )
 
@@ -45,10 +60,66 @@ func doExport(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doLedgerShowAddress(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	factory, err := cmdSigner.NewFactory(ledgerSigner.SignerName, "", signature.SignerEntity)
+	if err != nil {
+		logger.Error("failed to create Ledger signer factory",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	signer, err := factory.Load(signature.SignerEntity)
+	if err != nil {
+		logger.Error("failed to load Ledger signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	ls := signer.(*ledgerSigner.Signer)
+
+	version, err := ls.Version()
+	if err != nil {
+		logger.Error("failed to get Oasis app version",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	// Make sure the address reported by the device matches its public key.
+	addr := staking.NewAddress(ls.Public())
+	if addr.String() != ls.Address() {
+		logger.Error("address reported by the device does not match its public key",
+			"address", ls.Address(),
+			"expected_address", addr,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Oasis app version: %s\n", version)
+	fmt.Printf("Public key: %s\n", ls.Public())
+	fmt.Printf("Address: %s\n", addr)
+	fmt.Println("Please verify that the address shown on the device matches the above.")
+
+	if err = ls.ConfirmAddress(); err != nil {
+		logger.Error("failed to confirm address on device",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
 func Register(parentCmd *cobra.Command) {
 	exportCmd.Flags().AddFlagSet(cmdSigner.Flags)
 	exportCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
 
+	ledgerShowAddressCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	ledgerCmd.AddCommand(ledgerShowAddressCmd)
+
 	signerCmd.AddCommand(exportCmd)
+	signerCmd.AddCommand(ledgerCmd)
 	parentCmd.AddCommand(signerCmd)
 }
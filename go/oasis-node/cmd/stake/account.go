@@ -3,20 +3,26 @@ package stake
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
 )
 
 const (
@@ -32,14 +38,26 @@ const (
 	// CfgTransferDestination configures the transfer destination address.
 	CfgTransferDestination = "stake.transfer.destination"
 
+	// CfgMintDestination configures the mint destination address.
+	CfgMintDestination = "stake.mint.destination"
+
 	// CfgEscrowAccount configures the escrow address.
 	CfgEscrowAccount = "stake.escrow.account"
 
+	// CfgReclaimEscrowTranches configures the number of debonding tranches of a reclamation.
+	CfgReclaimEscrowTranches = "stake.reclaim_escrow.tranches"
+
 	// CfgCommissionScheduleRates configures the commission schedule rate steps.
 	CfgCommissionScheduleRates = "stake.commission_schedule.rates"
 
 	// CfgCommissionScheduleBounds configures the commission schedule rate bound steps.
 	CfgCommissionScheduleBounds = "stake.commission_schedule.bounds"
+
+	// CfgAlias configures the address alias.
+	CfgAlias = "stake.alias"
+
+	// CfgAliasEpochs configures the number of epochs to register an address alias for.
+	CfgAliasEpochs = "stake.alias.epochs"
 )
 
 var (
@@ -47,9 +65,13 @@ var (
 	amountFlags             = flag.NewFlagSet("", flag.ContinueOnError)
 	sharesFlags             = flag.NewFlagSet("", flag.ContinueOnError)
 	commonEscrowFlags       = flag.NewFlagSet("", flag.ContinueOnError)
+	reclaimEscrowFlags      = flag.NewFlagSet("", flag.ContinueOnError)
 	commissionScheduleFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	accountTransferFlags    = flag.NewFlagSet("", flag.ContinueOnError)
 	accountBurnFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	accountMintFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	aliasInfoFlags          = flag.NewFlagSet("", flag.ContinueOnError)
+	registerAliasFlags      = flag.NewFlagSet("", flag.ContinueOnError)
 
 	accountCmd = &cobra.Command{
 		Use:   "account",
@@ -62,6 +84,12 @@ var (
 		Run:   doAccountInfo,
 	}
 
+	accountDetailsCmd = &cobra.Command{
+		Use:   "details",
+		Short: "query account info together with its delegations",
+		Run:   doAccountDetails,
+	}
+
 	accountTransferCmd = &cobra.Command{
 		Use:   "gen_transfer",
 		Short: "generate a transfer transaction",
@@ -74,6 +102,12 @@ var (
 		Run:   doAccountBurn,
 	}
 
+	accountMintCmd = &cobra.Command{
+		Use:   "gen_mint",
+		Short: "Generate a mint transaction (test networks only)",
+		Run:   doAccountMint,
+	}
+
 	accountEscrowCmd = &cobra.Command{
 		Use:   "gen_escrow",
 		Short: "Generate an escrow (stake) transaction",
@@ -91,41 +125,193 @@ var (
 		Short: "Generate an amend_commission_schedule transaction",
 		Run:   doAccountAmendCommissionSchedule,
 	}
+
+	accountAliasInfoCmd = &cobra.Command{
+		Use:   "alias_info",
+		Short: "query address alias info",
+		Run:   doAccountAliasInfo,
+	}
+
+	accountRegisterAliasCmd = &cobra.Command{
+		Use:   "gen_register_alias",
+		Short: "Generate a register_alias transaction",
+		Run:   doAccountRegisterAlias,
+	}
 )
 
 // getCtxWithInfo returns a new context with values that contain additional
 // information (ticker symbol, value base-10 exponent, genesis document's hash).
 func getCtxWithInfo(genesis *genesisAPI.Document) context.Context {
 	ctx := context.Background()
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, genesis.Staking.TokenSymbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, genesis.Staking.TokenValueExponent)
+	ctx = token.ContextWithDenomination(ctx, genesis.Staking.Denomination())
 	ctx = context.WithValue(ctx, prettyprint.ContextKeyGenesisHash, genesis.Hash())
 	return ctx
 }
 
+// resolveAccountAddress resolves the configured account address, which may
+// also be an address alias, at the given height.
+func resolveAccountAddress(ctx context.Context, height int64, client api.Backend) api.Address {
+	addr, err := api.ResolveAddress(ctx, client, height, viper.GetString(CfgAccountAddr))
+	if err != nil {
+		logger.Error("failed to resolve account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return addr
+}
+
 func doAccountInfo(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
 	}
 
-	var addr api.Address
-	if err := addr.UnmarshalText([]byte(viper.GetString(CfgAccountAddr))); err != nil {
-		logger.Error("failed to parse account address",
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	addr := resolveAccountAddress(ctx, consensus.HeightLatest, client)
+	acct := getAccount(ctx, cmd, addr, consensus.HeightLatest, client)
+	ctx = token.ContextWithDenomination(ctx, getDenomination(ctx, cmd, client))
+	acct.PrettyPrint(ctx, "", os.Stdout)
+}
+
+// sortAddresses sorts the given addresses so that the output is deterministic.
+func sortAddresses(addrs []api.Address) []api.Address {
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+	return addrs
+}
+
+// prettyPrintShares writes the amount of base units the given shares of the
+// share pool are worth and the shares themselves to the given writer.
+func prettyPrintShares(ctx context.Context, pool *api.SharePool, shares *quantity.Quantity, prefix string, w io.Writer) {
+	amount, err := pool.StakeForShares(shares)
+	if err != nil {
+		logger.Error("failed to compute amount for shares",
+			"shares", shares,
 			"err", err,
 		)
 		os.Exit(1)
 	}
 
+	fmt.Fprintf(w, "%sAmount: ", prefix)
+	token.PrettyPrintAmount(ctx, *amount, w)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%sShares: %s\n", prefix, shares)
+}
+
+func doAccountDetails(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
 	conn, client := doConnect(cmd)
 	defer conn.Close()
 
 	ctx := context.Background()
-	acct := getAccount(ctx, cmd, addr, client)
-	symbol := getTokenSymbol(ctx, cmd, client)
-	exp := getTokenValueExponent(ctx, cmd, client)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, exp)
-	acct.PrettyPrint(ctx, "", os.Stdout)
+	ctx = token.ContextWithDenomination(ctx, getDenomination(ctx, cmd, client))
+
+	// Query everything at the same height so that the share pools and the
+	// delegations are consistent with each other.
+	blk, err := consensus.NewConsensusClient(conn).GetBlock(ctx, consensus.HeightLatest)
+	if err != nil {
+		logger.Error("failed to query latest block",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	height := blk.Height
+	addr := resolveAccountAddress(ctx, height, client)
+	query := &api.OwnerQuery{Owner: addr, Height: height}
+
+	acct := getAccount(ctx, cmd, addr, height, client)
+	delegations, err := client.Delegations(ctx, query)
+	if err != nil {
+		logger.Error("failed to query delegations",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	delegationsTo, err := client.DelegationsTo(ctx, query)
+	if err != nil {
+		logger.Error("failed to query delegations to account",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	debDelegations, err := client.DebondingDelegations(ctx, query)
+	if err != nil {
+		logger.Error("failed to query debonding delegations",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	// Fetch escrow accounts that the account delegates to in order to resolve
+	// shares to amounts.
+	escrowAccts := map[api.Address]*api.Account{addr: acct}
+	getEscrowAccount := func(escrowAddr api.Address) *api.Account {
+		if escrowAcct, ok := escrowAccts[escrowAddr]; ok {
+			return escrowAcct
+		}
+		escrowAcct := getAccount(ctx, cmd, escrowAddr, height, client)
+		escrowAccts[escrowAddr] = escrowAcct
+		return escrowAcct
+	}
+
+	w := os.Stdout
+	fmt.Fprintf(w, "Height: %d\n", height)
+	acct.PrettyPrint(ctx, "", w)
+
+	fmt.Fprintf(w, "Outgoing Delegations:\n")
+	if len(delegations) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	var escrowAddrs []api.Address
+	for a := range delegations {
+		escrowAddrs = append(escrowAddrs, a)
+	}
+	for _, escrowAddr := range sortAddresses(escrowAddrs) {
+		fmt.Fprintf(w, "  %s:\n", escrowAddr)
+		escrowAcct := getEscrowAccount(escrowAddr)
+		prettyPrintShares(ctx, &escrowAcct.Escrow.Active, &delegations[escrowAddr].Shares, "    ", w)
+	}
+
+	fmt.Fprintf(w, "Incoming Delegations:\n")
+	if len(delegationsTo) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	var delegatorAddrs []api.Address
+	for a := range delegationsTo {
+		delegatorAddrs = append(delegatorAddrs, a)
+	}
+	for _, delegatorAddr := range sortAddresses(delegatorAddrs) {
+		fmt.Fprintf(w, "  %s:\n", delegatorAddr)
+		prettyPrintShares(ctx, &acct.Escrow.Active, &delegationsTo[delegatorAddr].Shares, "    ", w)
+	}
+
+	fmt.Fprintf(w, "Debonding Delegations:\n")
+	if len(debDelegations) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	var debEscrowAddrs []api.Address
+	for a := range debDelegations {
+		debEscrowAddrs = append(debEscrowAddrs, a)
+	}
+	for _, escrowAddr := range sortAddresses(debEscrowAddrs) {
+		fmt.Fprintf(w, "  %s:\n", escrowAddr)
+		escrowAcct := getEscrowAccount(escrowAddr)
+		debs := debDelegations[escrowAddr]
+		sort.SliceStable(debs, func(i, j int) bool {
+			return debs[i].DebondEndTime < debs[j].DebondEndTime
+		})
+		for _, deb := range debs {
+			fmt.Fprintf(w, "    - Debond End Epoch: %d\n", deb.DebondEndTime)
+			prettyPrintShares(ctx, &escrowAcct.Escrow.Debonding, &deb.Shares, "      ", w)
+		}
+	}
 }
 
 func doAccountTransfer(cmd *cobra.Command, args []string) {
@@ -178,6 +364,34 @@ func doAccountBurn(cmd *cobra.Command, args []string) {
 	cmdConsensus.SignAndSaveTx(getCtxWithInfo(genesis), tx)
 }
 
+func doAccountMint(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	genesis := cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	var mint api.Mint
+	if err := mint.To.UnmarshalText([]byte(viper.GetString(CfgMintDestination))); err != nil {
+		logger.Error("failed to parse mint destination account address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err := mint.Amount.UnmarshalText([]byte(viper.GetString(CfgAmount))); err != nil {
+		logger.Error("failed to parse mint amount",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := api.NewMintTx(nonce, fee, &mint)
+
+	cmdConsensus.SignAndSaveTx(getCtxWithInfo(genesis), tx)
+}
+
 func doAccountEscrow(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -227,6 +441,9 @@ func doAccountReclaimEscrow(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
+	if tranches := uint16(viper.GetUint(CfgReclaimEscrowTranches)); tranches > 1 {
+		reclaim.Tranches = tranches
+	}
 
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := api.NewReclaimEscrowTx(nonce, fee, &reclaim)
@@ -312,30 +529,91 @@ func doAccountAmendCommissionSchedule(cmd *cobra.Command, args []string) {
 	cmdConsensus.SignAndSaveTx(getCtxWithInfo(genesis), tx)
 }
 
+func doAccountAliasInfo(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	info, err := client.Alias(ctx, &api.AliasQuery{
+		Height: consensus.HeightLatest,
+		Alias:  viper.GetString(CfgAlias),
+	})
+	if err != nil {
+		logger.Error("failed to query alias",
+			"alias", viper.GetString(CfgAlias),
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	info.PrettyPrint(ctx, "", os.Stdout)
+}
+
+func doAccountRegisterAlias(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	genesis := cmdConsensus.InitGenesis()
+	cmdConsensus.AssertTxFileOK()
+
+	reg := api.RegisterAlias{
+		Alias:  viper.GetString(CfgAlias),
+		Epochs: epochtime.EpochTime(viper.GetUint64(CfgAliasEpochs)),
+	}
+	if err := api.ValidateAlias(reg.Alias); err != nil {
+		logger.Error("invalid alias",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if reg.Epochs == 0 {
+		logger.Error("number of registration epochs must be non-zero")
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	tx := api.NewRegisterAliasTx(nonce, fee, &reg)
+
+	cmdConsensus.SignAndSaveTx(getCtxWithInfo(genesis), tx)
+}
+
 func registerAccountCmd() {
 	for _, v := range []*cobra.Command{
 		accountInfoCmd,
+		accountDetailsCmd,
 		accountTransferCmd,
 		accountBurnCmd,
+		accountMintCmd,
 		accountEscrowCmd,
 		accountReclaimEscrowCmd,
 		accountAmendCommissionScheduleCmd,
+		accountAliasInfoCmd,
+		accountRegisterAliasCmd,
 	} {
 		accountCmd.AddCommand(v)
 	}
 
 	accountInfoCmd.Flags().AddFlagSet(accountInfoFlags)
+	accountDetailsCmd.Flags().AddFlagSet(accountInfoFlags)
 	accountTransferCmd.Flags().AddFlagSet(accountTransferFlags)
 	accountBurnCmd.Flags().AddFlagSet(accountBurnFlags)
+	accountMintCmd.Flags().AddFlagSet(accountMintFlags)
 	accountEscrowCmd.Flags().AddFlagSet(commonEscrowFlags)
 	accountEscrowCmd.Flags().AddFlagSet(amountFlags)
 	accountReclaimEscrowCmd.Flags().AddFlagSet(commonEscrowFlags)
 	accountReclaimEscrowCmd.Flags().AddFlagSet(sharesFlags)
+	accountReclaimEscrowCmd.Flags().AddFlagSet(reclaimEscrowFlags)
 	accountAmendCommissionScheduleCmd.Flags().AddFlagSet(commissionScheduleFlags)
+	accountAliasInfoCmd.Flags().AddFlagSet(aliasInfoFlags)
+	accountRegisterAliasCmd.Flags().AddFlagSet(registerAliasFlags)
 }
 
 func init() {
-	accountInfoFlags.String(CfgAccountAddr, "", "account address")
+	accountInfoFlags.String(CfgAccountAddr, "", "account address or address alias")
 	_ = viper.BindPFlags(accountInfoFlags)
 	accountInfoFlags.AddFlagSet(cmdGrpc.ClientFlags)
 
@@ -355,11 +633,20 @@ func init() {
 	accountBurnFlags.AddFlagSet(amountFlags)
 	accountBurnFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
 
+	accountMintFlags.String(CfgMintDestination, "", "mint destination account address")
+	_ = viper.BindPFlags(accountMintFlags)
+	accountMintFlags.AddFlagSet(cmdConsensus.TxFlags)
+	accountMintFlags.AddFlagSet(amountFlags)
+	accountMintFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
 	commonEscrowFlags.String(CfgEscrowAccount, "", "address of the escrow account")
 	_ = viper.BindPFlags(commonEscrowFlags)
 	commonEscrowFlags.AddFlagSet(cmdConsensus.TxFlags)
 	commonEscrowFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
 
+	reclaimEscrowFlags.Uint16(CfgReclaimEscrowTranches, 1, "number of equal tranches debonding over consecutive epochs")
+	_ = viper.BindPFlags(reclaimEscrowFlags)
+
 	commissionScheduleFlags.StringSlice(CfgCommissionScheduleRates, nil, fmt.Sprintf(
 		"commission rate step. Multiple of this flag is allowed. "+
 			"Each step is in the format start_epoch/rate_numerator. "+
@@ -374,4 +661,14 @@ func init() {
 	_ = viper.BindPFlags(commissionScheduleFlags)
 	commissionScheduleFlags.AddFlagSet(cmdConsensus.TxFlags)
 	commissionScheduleFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
+
+	aliasInfoFlags.String(CfgAlias, "", "address alias")
+	_ = viper.BindPFlags(aliasInfoFlags)
+	aliasInfoFlags.AddFlagSet(cmdGrpc.ClientFlags)
+
+	registerAliasFlags.String(CfgAlias, "", "address alias to register for the signer's account")
+	registerAliasFlags.Uint64(CfgAliasEpochs, 0, "number of epochs to register (or extend) the alias for")
+	_ = viper.BindPFlags(registerAliasFlags)
+	registerAliasFlags.AddFlagSet(cmdConsensus.TxFlags)
+	registerAliasFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
 }
@@ -15,7 +15,6 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
-	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -71,7 +70,7 @@ func doConnect(cmd *cobra.Command) (*grpc.ClientConn, api.Backend) {
 	return conn, client
 }
 
-func getTokenSymbol(ctx context.Context, cmd *cobra.Command, client api.Backend) string {
+func getDenomination(ctx context.Context, cmd *cobra.Command, client api.Backend) *token.Denomination {
 	symbol, err := client.TokenSymbol(ctx)
 	if err != nil {
 		logger.Error("failed to query token's symbol",
@@ -79,10 +78,6 @@ func getTokenSymbol(ctx context.Context, cmd *cobra.Command, client api.Backend)
 		)
 		os.Exit(1)
 	}
-	return symbol
-}
-
-func getTokenValueExponent(ctx context.Context, cmd *cobra.Command, client api.Backend) uint8 {
 	exp, err := client.TokenValueExponent(ctx)
 	if err != nil {
 		logger.Error("failed to query token's value exponent",
@@ -90,14 +85,15 @@ func getTokenValueExponent(ctx context.Context, cmd *cobra.Command, client api.B
 		)
 		os.Exit(1)
 	}
-	return exp
+	return &token.Denomination{Symbol: symbol, Exponent: exp}
 }
 
-func getAccount(ctx context.Context, cmd *cobra.Command, addr api.Address, client api.Backend) *api.Account {
-	acct, err := client.Account(ctx, &api.OwnerQuery{Owner: addr, Height: consensus.HeightLatest})
+func getAccount(ctx context.Context, cmd *cobra.Command, addr api.Address, height int64, client api.Backend) *api.Account {
+	acct, err := client.Account(ctx, &api.OwnerQuery{Owner: addr, Height: height})
 	if err != nil {
 		logger.Error("failed to query account",
 			"address", addr,
+			"height", height,
 			"err", err,
 		)
 		os.Exit(1)
@@ -115,14 +111,11 @@ func doInfo(cmd *cobra.Command, args []string) {
 
 	ctx := context.Background()
 
-	symbol := getTokenSymbol(ctx, cmd, client)
-	fmt.Printf("Token's ticker symbol: %s\n", symbol)
-
-	exp := getTokenValueExponent(ctx, cmd, client)
-	fmt.Printf("Token's value base-10 exponent: %d\n", exp)
+	denomination := getDenomination(ctx, cmd, client)
+	fmt.Printf("Token's ticker symbol: %s\n", denomination.Symbol)
+	fmt.Printf("Token's value base-10 exponent: %d\n", denomination.Exponent)
 
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, exp)
+	ctx = token.ContextWithDenomination(ctx, denomination)
 
 	totalSupply, err := client.TotalSupply(ctx, consensus.HeightLatest)
 	if err != nil {
@@ -209,7 +202,7 @@ func doList(cmd *cobra.Command, args []string) {
 			// NOTE: getAccount()'s output doesn't contain an account's address,
 			// so we need to add it manually.
 			acctMap := make(map[api.Address]*api.Account)
-			acctMap[addr] = getAccount(ctx, cmd, addr, client)
+			acctMap[addr] = getAccount(ctx, cmd, addr, consensus.HeightLatest, client)
 			b, _ := json.Marshal(acctMap)
 			s = string(b)
 		default:
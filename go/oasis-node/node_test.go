@@ -244,6 +244,7 @@ func TestNode(t *testing.T) {
 
 		{"Consensus", testConsensus},
 		{"ConsensusClient", testConsensusClient},
+		{"Control", testControl},
 		{"EpochTime", testEpochTime},
 		{"Beacon", testBeacon},
 		{"Storage", testStorage},
@@ -366,6 +367,23 @@ func testConsensusClient(t *testing.T, node *testNode) {
 	consensusTests.ConsensusImplementationTests(t, client)
 }
 
+func testControl(t *testing.T, node *testNode) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	synced, err := node.NodeController.IsSynced(ctx)
+	require.NoError(err, "IsSynced")
+	require.True(synced, "node should be synced")
+
+	status, err := node.NodeController.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.Equal(node.Identity.NodeSigner.Public(), status.Identity.Node, "node identity should match")
+
+	mempool, err := node.NodeController.GetMempool(ctx)
+	require.NoError(err, "GetMempool")
+	require.EqualValues(0, mempool.InvalidCount, "mempool should not contain invalid transactions")
+}
+
 func testEpochTime(t *testing.T, node *testNode) {
 	epochtimeTests.EpochtimeSetableImplementationTest(t, node.Consensus.EpochTime())
 }
@@ -89,6 +89,20 @@ func (r *RegistryHelpers) runRegistryRuntimeSubcommand(
 				"--"+cmdRegRt.CfgAdmissionPolicyEntityWhitelist, e.String(),
 			)
 		}
+	} else if runtime.AdmissionPolicy.NodeWhitelist != nil {
+		args = append(args,
+			"--"+cmdRegRt.CfgAdmissionPolicy, cmdRegRt.AdmissionPolicyNameNodeWhitelist,
+		)
+		for n := range runtime.AdmissionPolicy.NodeWhitelist.Nodes {
+			args = append(args,
+				"--"+cmdRegRt.CfgAdmissionPolicyNodeWhitelist, n.String(),
+			)
+		}
+		for role, maxNodes := range runtime.AdmissionPolicy.NodeWhitelist.MaxNodes {
+			args = append(args,
+				"--"+cmdRegRt.CfgAdmissionPolicyNodeWhitelistMaxNodes, fmt.Sprintf("%s=%d", role, maxNodes),
+			)
+		}
 	} else {
 		return fmt.Errorf("invalid admission policy")
 	}
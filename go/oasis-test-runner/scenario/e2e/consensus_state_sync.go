@@ -148,5 +148,22 @@ func (sc *consensusStateSyncImpl) Run(childEnv *env.Env) error {
 		return err
 	}
 
+	// Make sure that the state sync progress is reported in the node status.
+	status, err := valCtrl.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get status for validator %s: %w", val.Name, err)
+	}
+	stateSync := status.Consensus.StateSync
+	switch {
+	case stateSync == nil:
+		return fmt.Errorf("validator %s did not report state sync status", val.Name)
+	case !stateSync.Done:
+		return fmt.Errorf("validator %s reported state sync as not done", val.Name)
+	case stateSync.RestoredChunks != stateSync.TotalChunks:
+		return fmt.Errorf("validator %s reported %d restored chunks (expected: %d)",
+			val.Name, stateSync.RestoredChunks, stateSync.TotalChunks,
+		)
+	}
+
 	return nil
 }
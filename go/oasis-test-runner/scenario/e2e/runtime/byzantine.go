@@ -190,6 +190,44 @@ var (
 			"--" + byzantine.CfgFailReadRequests,
 		},
 	)
+	// ByzantineStorageCorruptDiffs is the byzantine storage node scenario that serves corrupt
+	// write log diffs.
+	ByzantineStorageCorruptDiffs scenario.Scenario = newByzantineImpl(
+		"storage-corrupt-diffs",
+		"storage",
+		// Diffs are verified by the syncing nodes so there should be no discrepancy or round
+		// failures.
+		nil,
+		oasis.ByzantineDefaultIdentitySeed,
+		[]string{
+			"--" + byzantine.CfgStorageMode, byzantine.ModeStorageCorruptDiffs.String(),
+		},
+	)
+	// ByzantineStorageWithholdCheckpointChunks is the byzantine storage node scenario that
+	// advertises checkpoints but withholds their chunks.
+	ByzantineStorageWithholdCheckpointChunks scenario.Scenario = newByzantineImpl(
+		"storage-withhold-checkpoint-chunks",
+		"storage",
+		// There should be no discrepancy or round failures.
+		nil,
+		oasis.ByzantineDefaultIdentitySeed,
+		[]string{
+			"--" + byzantine.CfgStorageMode, byzantine.ModeStorageWithholdCheckpointChunks.String(),
+		},
+	)
+	// ByzantineStorageWrongProofs is the byzantine storage node scenario that serves proofs
+	// which do not verify.
+	ByzantineStorageWrongProofs scenario.Scenario = newByzantineImpl(
+		"storage-wrong-proofs",
+		"storage",
+		// Proofs are verified by the storage clients so there should be no discrepancy or round
+		// failures.
+		nil,
+		oasis.ByzantineDefaultIdentitySeed,
+		[]string{
+			"--" + byzantine.CfgStorageMode, byzantine.ModeStorageWrongProofs.String(),
+		},
+	)
 )
 
 type byzantineImpl struct {
@@ -507,6 +507,9 @@ func RegisterScenarios() error {
 		ByzantineStorageFailApply,
 		ByzantineStorageFailApplyBatch,
 		ByzantineStorageFailRead,
+		ByzantineStorageCorruptDiffs,
+		ByzantineStorageWithholdCheckpointChunks,
+		ByzantineStorageWrongProofs,
 		// Storage sync test.
 		StorageSync,
 		// Sentry test.
@@ -3,6 +3,7 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -92,8 +93,7 @@ func (sc *seedAPI) Run(childEnv *env.Env) error { // nolint: gocyclo
 	if len(status.Runtimes) != 0 {
 		return fmt.Errorf("seed node reports configured runtimes")
 	}
-	rs := api.RegistrationStatus{}
-	if status.Registration != rs {
+	if !reflect.DeepEqual(status.Registration, api.RegistrationStatus{}) {
 		return fmt.Errorf("seed reports as registered")
 	}
 	if len(status.Consensus.NodePeers) == 0 {
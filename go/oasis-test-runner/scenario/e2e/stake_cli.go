@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -25,6 +26,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis/cli"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
 	"github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
 )
 
 const (
@@ -94,18 +96,10 @@ func mustInitQuantity(i int64) (q quantity.Quantity) {
 
 // Return a context with values of token's ticker symbol and token's value base-10 exponent.
 func contextWithTokenInfo() context.Context {
-	ctx := context.Background()
-	ctx = context.WithValue(
-		ctx,
-		prettyprint.ContextKeyTokenSymbol,
-		genesisTestHelpers.TestStakingTokenSymbol,
-	)
-	ctx = context.WithValue(
-		ctx,
-		prettyprint.ContextKeyTokenValueExponent,
-		genesisTestHelpers.TestStakingTokenValueExponent,
-	)
-	return ctx
+	return token.ContextWithDenomination(context.Background(), &token.Denomination{
+		Symbol:   genesisTestHelpers.TestStakingTokenSymbol,
+		Exponent: genesisTestHelpers.TestStakingTokenValueExponent,
+	})
 }
 
 type stakeCLIImpl struct {
@@ -294,6 +288,12 @@ func (sc *stakeCLIImpl) testTransfer(childEnv *env.Env, cli *cli.Helpers, src, d
 	}
 
 	transferTxPath := filepath.Join(childEnv.Dir(), "stake_transfer.json")
+	if err = sc.dryRunTransferTx(childEnv, transferAmount, srcNonce, dst, transferTxPath); err != nil {
+		return err
+	}
+	if _, err = os.Stat(transferTxPath); !os.IsNotExist(err) {
+		return fmt.Errorf("dry run should not save the transaction (err: %v)", err)
+	}
 	if err = sc.genTransferTx(childEnv, transferAmount, srcNonce, dst, transferTxPath); err != nil {
 		return err
 	}
@@ -465,6 +465,22 @@ func (sc *stakeCLIImpl) testReclaimEscrow(childEnv *env.Env, cli *cli.Helpers, s
 		return err
 	}
 
+	if err := sc.checkAccountDetailsDelegation(
+		ctx, childEnv, src, "Outgoing Delegations", escrow, expectedEscrowActiveBalance, expectedEscrowActiveShares,
+	); err != nil {
+		return err
+	}
+	if err := sc.checkAccountDetailsDelegation(
+		ctx, childEnv, escrow, "Incoming Delegations", src, expectedEscrowActiveBalance, expectedEscrowActiveShares,
+	); err != nil {
+		return err
+	}
+	if err := sc.checkAccountDetailsDelegation(
+		ctx, childEnv, src, "Debonding Delegations", escrow, expectedEscrowDebondingBalance, expectedEscrowDebondingShares,
+	); err != nil {
+		return err
+	}
+
 	// Advance epochs to trigger reclaim processing.
 	if err := sc.Net.Controller().SetEpoch(context.Background(), 1); err != nil {
 		return fmt.Errorf("failed to set epoch: %w", err)
@@ -662,6 +678,73 @@ func (sc *stakeCLIImpl) checkEscrowAccountSharePool(
 	return nil
 }
 
+func (sc *stakeCLIImpl) getAccountDetails(childEnv *env.Env, src api.Address) (string, error) {
+	sc.Logger.Info("checking account details", stake.CfgAccountAddr, src.String())
+	args := []string{
+		"stake", "account", "details",
+		"--" + stake.CfgAccountAddr, src.String(),
+		"--" + grpc.CfgAddress, "unix:" + sc.Net.Validators()[0].SocketPath(),
+	}
+
+	out, err := cli.RunSubCommandWithOutput(childEnv, sc.Logger, "details", sc.Net.Config().NodeBinary, args)
+	if err != nil {
+		return "", fmt.Errorf("failed to check account details: error: %w output: %s", err, out.String())
+	}
+
+	return out.String(), nil
+}
+
+func (sc *stakeCLIImpl) checkAccountDetailsDelegation(
+	ctx context.Context,
+	childEnv *env.Env,
+	src api.Address,
+	section string,
+	other api.Address,
+	expectedAmount quantity.Quantity,
+	expectedShares quantity.Quantity,
+) error {
+	accountDetails, err := sc.getAccountDetails(childEnv, src)
+	if err != nil {
+		return err
+	}
+
+	// Only look at the given section of the account details.
+	idx := strings.Index(accountDetails, section+":\n")
+	if idx == -1 {
+		return fmt.Errorf("checkAccountDetailsDelegation: couldn't find section %s in account details", section)
+	}
+	var sectionBody strings.Builder
+	for _, line := range strings.SplitAfter(accountDetails[idx+len(section)+2:], "\n") {
+		if !strings.HasPrefix(line, " ") {
+			break
+		}
+		sectionBody.WriteString(line)
+	}
+
+	indent := "    "
+	regexPattern := regexp.QuoteMeta(fmt.Sprintf("  %s:\n", other))
+	if section == "Debonding Delegations" {
+		regexPattern += regexp.QuoteMeta("    - Debond End Epoch: ") + `\d+\n`
+		indent = "      "
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%sAmount: ", indent)
+	token.PrettyPrintAmount(ctx, expectedAmount, &b)
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%sShares: %s\n", indent, expectedShares)
+	regexPattern += regexp.QuoteMeta(b.String())
+
+	match := regexp.MustCompile(regexPattern).FindStringSubmatch(sectionBody.String())
+	if match == nil {
+		return fmt.Errorf(
+			"checkAccountDetailsDelegation: couldn't find expected delegation %s (amount: %s, shares: %s) in %s",
+			other, expectedAmount, expectedShares, section,
+		)
+	}
+
+	return nil
+}
+
 func (sc *stakeCLIImpl) checkCommissionScheduleRates(
 	ctx context.Context,
 	childEnv *env.Env,
@@ -778,6 +861,34 @@ func (sc *stakeCLIImpl) genTransferTx(childEnv *env.Env, amount int, nonce uint6
 	return nil
 }
 
+func (sc *stakeCLIImpl) dryRunTransferTx(childEnv *env.Env, amount int, nonce uint64, dst api.Address, txPath string) error {
+	sc.Logger.Info("simulating stake transfer tx", stake.CfgTransferDestination, dst)
+
+	args := []string{
+		"stake", "account", "gen_transfer",
+		"--" + stake.CfgAmount, strconv.Itoa(amount),
+		"--" + consensus.CfgTxNonce, strconv.FormatUint(nonce, 10),
+		"--" + consensus.CfgTxFile, txPath,
+		"--" + stake.CfgTransferDestination, dst.String(),
+		"--" + consensus.CfgTxFeeAmount, strconv.Itoa(feeAmount),
+		"--" + consensus.CfgTxFeeGas, strconv.Itoa(feeGas),
+		"--" + consensus.CfgTxDryRun,
+		"--" + grpc.CfgAddress, "unix:" + sc.Net.Validators()[0].SocketPath(),
+		"--" + flags.CfgDebugDontBlameOasis,
+		"--" + flags.CfgDebugTestEntity,
+		"--" + common.CfgDebugAllowTestKeys,
+		"--" + flags.CfgGenesisFile, sc.Net.GenesisPath(),
+	}
+	out, err := cli.RunSubCommandWithOutput(childEnv, sc.Logger, "gen_transfer", sc.Net.Config().NodeBinary, args)
+	if err != nil {
+		return fmt.Errorf("dryRunTransferTx: failed to simulate transfer tx: error: %w output: %s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Status:   ok") {
+		return fmt.Errorf("dryRunTransferTx: unexpected simulation output: %s", out.String())
+	}
+	return nil
+}
+
 func (sc *stakeCLIImpl) genBurnTx(childEnv *env.Env, amount int, nonce uint64, txPath string) error {
 	sc.Logger.Info("generating stake burn tx")
 
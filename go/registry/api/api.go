@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"sort"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -131,24 +133,57 @@ var (
 	// has runtimes.
 	ErrEntityHasRuntimes = errors.New(ModuleName, 19, "registry: entity still has runtimes")
 
+	// ErrHeartbeatTooFrequent is the error returned when a node submits a heartbeat before the
+	// minimum heartbeat interval has passed since its last heartbeat.
+	ErrHeartbeatTooFrequent = errors.New(ModuleName, 20, "registry: node heartbeat too frequent")
+
+	// ErrAttestationPolicyViolation is the error returned when a node tries to
+	// register with a TEE attestation that does not satisfy the runtime's
+	// attestation policy.
+	ErrAttestationPolicyViolation = errors.New(ModuleName, 21, "registry: attestation does not satisfy runtime policy")
+
+	// ErrNodeAlreadySuspended is the error returned when suspending a node that
+	// is already suspended.
+	ErrNodeAlreadySuspended = errors.New(ModuleName, 22, "registry: node already suspended")
+
+	// ErrNodeNotSuspended is the error returned when resuming a node that is
+	// not suspended.
+	ErrNodeNotSuspended = errors.New(ModuleName, 23, "registry: node not suspended")
+
+	// ErrEntityNodeQuotaExceeded is the error returned when registering a node
+	// would exceed the number of nodes its owning entity is allowed to have.
+	ErrEntityNodeQuotaExceeded = errors.New(ModuleName, 24, "registry: entity node quota exceeded")
+
 	// MethodRegisterEntity is the method name for entity registrations.
 	MethodRegisterEntity = transaction.NewMethodName(ModuleName, "RegisterEntity", entity.SignedEntity{})
 	// MethodDeregisterEntity is the method name for entity deregistrations.
 	MethodDeregisterEntity = transaction.NewMethodName(ModuleName, "DeregisterEntity", nil)
 	// MethodRegisterNode is the method name for node registrations.
 	MethodRegisterNode = transaction.NewMethodName(ModuleName, "RegisterNode", node.MultiSignedNode{})
+	// MethodRegisterNodes is the method name for batch node registrations.
+	MethodRegisterNodes = transaction.NewMethodName(ModuleName, "RegisterNodes", RegisterNodes{})
 	// MethodUnfreezeNode is the method name for unfreezing nodes.
 	MethodUnfreezeNode = transaction.NewMethodName(ModuleName, "UnfreezeNode", UnfreezeNode{})
 	// MethodRegisterRuntime is the method name for registering runtimes.
 	MethodRegisterRuntime = transaction.NewMethodName(ModuleName, "RegisterRuntime", SignedRuntime{})
+	// MethodHeartbeatNode is the method name for node heartbeats.
+	MethodHeartbeatNode = transaction.NewMethodName(ModuleName, "HeartbeatNode", nil)
+	// MethodSuspendNode is the method name for suspending nodes.
+	MethodSuspendNode = transaction.NewMethodName(ModuleName, "SuspendNode", SuspendNode{})
+	// MethodResumeNode is the method name for resuming suspended nodes.
+	MethodResumeNode = transaction.NewMethodName(ModuleName, "ResumeNode", ResumeNode{})
 
 	// Methods is the list of all methods supported by the registry backend.
 	Methods = []transaction.MethodName{
 		MethodRegisterEntity,
 		MethodDeregisterEntity,
 		MethodRegisterNode,
+		MethodRegisterNodes,
 		MethodUnfreezeNode,
 		MethodRegisterRuntime,
+		MethodHeartbeatNode,
+		MethodSuspendNode,
+		MethodResumeNode,
 	}
 
 	// RuntimesRequiredRoles are the Node roles that require runtimes.
@@ -197,6 +232,18 @@ type Backend interface {
 	// GetNodes gets a list of all registered nodes.
 	GetNodes(context.Context, int64) ([]*node.Node, error)
 
+	// GetFreshNodes returns the registered nodes that have submitted a heartbeat within the given
+	// freshness window.
+	GetFreshNodes(context.Context, *FreshNodesQuery) ([]*node.Node, error)
+
+	// GetStaleNodes returns the registered nodes whose descriptors expire within the given number
+	// of epochs unless refreshed, together with per-entity counts.
+	GetStaleNodes(context.Context, *StaleNodesQuery) (*StaleNodes, error)
+
+	// ValidateNode runs the node registration validation against the state at the specified
+	// block height without registering the node and returns all found validation failures.
+	ValidateNode(context.Context, *ValidateNodeQuery) (*NodeValidationResult, error)
+
 	// GetNodeByConsensusAddress looks up a node by its consensus address at the
 	// specified block height. The nature and format of the consensus address depends
 	// on the specific consensus backend implementation used.
@@ -228,9 +275,21 @@ type Backend interface {
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(context.Context, int64) (*Genesis, error)
 
+	// ConsensusParameters returns the registry consensus parameters.
+	ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error)
+
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]*Event, error)
 
+	// GetEventsRange returns a page of events emitted in the given block height range, optionally
+	// filtered by event kind and by the entity, node or runtime that the events relate to.
+	GetEventsRange(ctx context.Context, query *EventsRangeQuery) (*EventsRangePage, error)
+
+	// WatchDeprecationWarnings returns a channel that produces a stream of
+	// DeprecationWarningEvent on registrations of descriptors that use
+	// deprecated features.
+	WatchDeprecationWarnings(context.Context) (<-chan *DeprecationWarningEvent, pubsub.ClosableSubscription, error)
+
 	// Cleanup cleans up the registry backend.
 	Cleanup()
 }
@@ -253,6 +312,49 @@ type GetRuntimesQuery struct {
 	IncludeSuspended bool  `json:"include_suspended"`
 }
 
+// FreshNodesQuery is a registry query for nodes that have recently submitted a heartbeat.
+type FreshNodesQuery struct {
+	Height int64 `json:"height"`
+	// Window is the freshness window in blocks. Only nodes whose last heartbeat was included at a
+	// height within the window ending at the query height are returned.
+	Window int64 `json:"window"`
+}
+
+// StaleNodesQuery is a registry query for nodes whose descriptors are approaching expiration.
+type StaleNodesQuery struct {
+	Height int64 `json:"height"`
+	// Epochs is the staleness threshold in epochs. Only nodes whose descriptors expire within the
+	// given number of epochs after the epoch at the query height are returned.
+	Epochs epochtime.EpochTime `json:"epochs"`
+}
+
+// StaleNodes is the result of a stale nodes query.
+type StaleNodes struct {
+	// Epoch is the epoch at the query height.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Nodes are the registered nodes whose descriptors are approaching expiration.
+	Nodes []*node.Node `json:"nodes"`
+	// EntityCounts are the numbers of stale nodes, keyed by the controlling entity.
+	EntityCounts map[signature.PublicKey]uint64 `json:"entity_counts"`
+}
+
+// NewStaleNodes filters the given non-expired nodes down to those whose descriptors expire
+// within the given number of epochs after the given epoch.
+func NewStaleNodes(epoch, epochs epochtime.EpochTime, nodes []*node.Node) *StaleNodes {
+	stale := StaleNodes{
+		Epoch:        epoch,
+		EntityCounts: make(map[signature.PublicKey]uint64),
+	}
+	for _, n := range nodes {
+		if n.IsExpired(uint64(epoch)) || n.Expiration-uint64(epoch) > uint64(epochs) {
+			continue
+		}
+		stale.Nodes = append(stale.Nodes, n)
+		stale.EntityCounts[n.EntityID]++
+	}
+	return &stale
+}
+
 // ConsensusAddressQuery is a registry query by consensus address.
 // The nature and format of the consensus address depends on the specific
 // consensus backend implementation used.
@@ -261,6 +363,65 @@ type ConsensusAddressQuery struct {
 	Address []byte `json:"address"`
 }
 
+// ValidateNodeQuery is a registry query for validating a node descriptor without registering it.
+type ValidateNodeQuery struct {
+	Height int64                 `json:"height"`
+	Node   *node.MultiSignedNode `json:"node"`
+}
+
+const (
+	// NodeCheckEntity is the node validation check for the owning entity.
+	NodeCheckEntity = "entity"
+	// NodeCheckDescriptor is the node validation check for the descriptor itself (signatures,
+	// roles, runtimes, addresses and TLS/P2P/consensus keys).
+	NodeCheckDescriptor = "descriptor"
+	// NodeCheckRuntimeAdmission is the node validation check for runtime admission policies.
+	NodeCheckRuntimeAdmission = "runtime_admission"
+	// NodeCheckExpiration is the node validation check for the descriptor expiration.
+	NodeCheckExpiration = "expiration"
+	// NodeCheckUpdate is the node validation check for changes to an existing node descriptor.
+	NodeCheckUpdate = "update"
+	// NodeCheckStake is the node validation check for the owning entity's stake claims.
+	NodeCheckStake = "stake"
+	// NodeCheckEntityQuota is the node validation check for the owning entity's node quotas.
+	NodeCheckEntityQuota = "entity_quota"
+)
+
+// NodeValidationViolation is a single node registration validation failure.
+type NodeValidationViolation struct {
+	// Check is the name of the failed validation check.
+	Check string `json:"check"`
+	// Module is the module of the error that would be returned on registration.
+	Module string `json:"module,omitempty"`
+	// Code is the code of the error that would be returned on registration.
+	Code uint32 `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message"`
+}
+
+// NodeValidationResult is the result of a node descriptor validation.
+type NodeValidationResult struct {
+	// Violations are the node registration validation failures. Registration of the node
+	// descriptor is expected to succeed iff there are none.
+	Violations []*NodeValidationViolation `json:"violations,omitempty"`
+}
+
+// IsValid returns true iff there are no validation failures.
+func (r *NodeValidationResult) IsValid() bool {
+	return len(r.Violations) == 0
+}
+
+// AddViolation records a validation failure of the given check.
+func (r *NodeValidationResult) AddViolation(check string, err error) {
+	module, code := errors.Code(err)
+	r.Violations = append(r.Violations, &NodeValidationViolation{
+		Check:   check,
+		Module:  module,
+		Code:    code,
+		Message: err.Error(),
+	})
+}
+
 // NewRegisterEntityTx creates a new register entity transaction.
 func NewRegisterEntityTx(nonce uint64, fee *transaction.Fee, sigEnt *entity.SignedEntity) *transaction.Transaction {
 	return transaction.NewTransaction(nonce, fee, MethodRegisterEntity, sigEnt)
@@ -276,6 +437,41 @@ func NewRegisterNodeTx(nonce uint64, fee *transaction.Fee, sigNode *node.MultiSi
 	return transaction.NewTransaction(nonce, fee, MethodRegisterNode, sigNode)
 }
 
+// MaxRegisterNodesBatchSize is the maximum number of nodes that can be registered in a single
+// batch node registration transaction.
+const MaxRegisterNodesBatchSize = 128
+
+// RegisterNodes is a batch node registration request.
+//
+// All node descriptors must be signed by the owning entity, which must also be the signer of the
+// transaction. Each node is validated in the same way as in a regular node registration and either
+// all or none of the nodes are registered.
+type RegisterNodes struct {
+	// Nodes are the signed node descriptors to register.
+	Nodes []*node.MultiSignedNode `json:"nodes"`
+}
+
+// ValidateBasic performs basic batch node registration request validity checks.
+func (rn *RegisterNodes) ValidateBasic() error {
+	if len(rn.Nodes) == 0 {
+		return fmt.Errorf("%w: empty node registration batch", ErrInvalidArgument)
+	}
+	if len(rn.Nodes) > MaxRegisterNodesBatchSize {
+		return fmt.Errorf("%w: too many nodes in registration batch (max: %d)", ErrInvalidArgument, MaxRegisterNodesBatchSize)
+	}
+	for i, sigNode := range rn.Nodes {
+		if sigNode == nil {
+			return fmt.Errorf("%w: missing node descriptor at index %d", ErrInvalidArgument, i)
+		}
+	}
+	return nil
+}
+
+// NewRegisterNodesTx creates a new batch register nodes transaction.
+func NewRegisterNodesTx(nonce uint64, fee *transaction.Fee, nodes *RegisterNodes) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodRegisterNodes, nodes)
+}
+
 // NewUnfreezeNodeTx creates a new unfreeze node transaction.
 func NewUnfreezeNodeTx(nonce uint64, fee *transaction.Fee, unfreeze *UnfreezeNode) *transaction.Transaction {
 	return transaction.NewTransaction(nonce, fee, MethodUnfreezeNode, unfreeze)
@@ -286,6 +482,21 @@ func NewRegisterRuntimeTx(nonce uint64, fee *transaction.Fee, sigRt *SignedRunti
 	return transaction.NewTransaction(nonce, fee, MethodRegisterRuntime, sigRt)
 }
 
+// NewHeartbeatNodeTx creates a new node heartbeat transaction.
+func NewHeartbeatNodeTx(nonce uint64, fee *transaction.Fee) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodHeartbeatNode, nil)
+}
+
+// NewSuspendNodeTx creates a new suspend node transaction.
+func NewSuspendNodeTx(nonce uint64, fee *transaction.Fee, suspend *SuspendNode) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSuspendNode, suspend)
+}
+
+// NewResumeNodeTx creates a new resume node transaction.
+func NewResumeNodeTx(nonce uint64, fee *transaction.Fee, resume *ResumeNode) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodResumeNode, resume)
+}
+
 // EntityEvent is the event that is returned via WatchEntities to signify
 // entity registration changes and updates.
 type EntityEvent struct {
@@ -310,15 +521,153 @@ type NodeUnfrozenEvent struct {
 	NodeID signature.PublicKey `json:"node_id"`
 }
 
+// NodeSuspendedEvent signifies when node becomes suspended by its owning entity.
+type NodeSuspendedEvent struct {
+	NodeID signature.PublicKey `json:"node_id"`
+}
+
+// NodeResumedEvent signifies when a suspended node is resumed by its owning entity.
+type NodeResumedEvent struct {
+	NodeID signature.PublicKey `json:"node_id"`
+}
+
 // Event is a registry event returned via GetEvents.
 type Event struct {
-	Height int64     `json:"height,omitempty"`
-	TxHash hash.Hash `json:"tx_hash,omitempty"`
+	Height int64        `json:"height,omitempty"`
+	TxHash hash.Hash    `json:"tx_hash,omitempty"`
+	Index  events.Index `json:"index"`
 
 	RuntimeEvent      *RuntimeEvent      `json:"runtime,omitempty"`
 	EntityEvent       *EntityEvent       `json:"entity,omitempty"`
 	NodeEvent         *NodeEvent         `json:"node,omitempty"`
 	NodeUnfrozenEvent *NodeUnfrozenEvent `json:"node_unfrozen,omitempty"`
+
+	NodeSuspendedEvent *NodeSuspendedEvent `json:"node_suspended,omitempty"`
+	NodeResumedEvent   *NodeResumedEvent   `json:"node_resumed,omitempty"`
+
+	DeprecationWarningEvent *DeprecationWarningEvent `json:"deprecation_warning,omitempty"`
+}
+
+// MaxEventsRangeQueryRange is the maximum number of blocks that can be covered by a single
+// events range query.
+const MaxEventsRangeQueryRange = 1000
+
+// MaxEventsRangePageSize is the maximum number of events returned in a single events range page.
+const MaxEventsRangePageSize = 1000
+
+// EventKind is a bitmask of registry event kinds, used to filter events.
+type EventKind uint8
+
+const (
+	// EventKindEntity matches entity events.
+	EventKindEntity EventKind = 1 << iota
+	// EventKindNode matches node events.
+	EventKindNode
+	// EventKindNodeUnfrozen matches node unfrozen events.
+	EventKindNodeUnfrozen
+	// EventKindRuntime matches runtime events.
+	EventKindRuntime
+	// EventKindDeprecationWarning matches deprecation warning events.
+	EventKindDeprecationWarning
+	// EventKindNodeSuspended matches node suspended events.
+	EventKindNodeSuspended
+	// EventKindNodeResumed matches node resumed events.
+	EventKindNodeResumed
+)
+
+// EventsCursor is a position within the list of events emitted in a block height range.
+type EventsCursor struct {
+	// Height is the block height of the next event.
+	Height int64 `json:"height"`
+	// Index is the index of the next event among all registry events emitted at the given height.
+	Index uint64 `json:"index"`
+}
+
+// EventsRangeQuery is a registry events range query.
+type EventsRangeQuery struct {
+	// FromHeight is the first block height (inclusive) to query.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the last block height (inclusive) to query.
+	ToHeight int64 `json:"to_height"`
+
+	// Kinds is an optional event kind filter. If zero, events of all kinds are returned.
+	Kinds EventKind `json:"kinds,omitempty"`
+	// EntityID is an optional entity filter. Only entity events for the given entity and node,
+	// runtime and deprecation warning events for nodes, runtimes and descriptors controlled by the
+	// entity are returned.
+	EntityID *signature.PublicKey `json:"entity_id,omitempty"`
+	// NodeID is an optional node filter. Only node, node unfrozen, node suspended, node resumed
+	// and deprecation warning events for the given node are returned.
+	NodeID *signature.PublicKey `json:"node_id,omitempty"`
+	// RuntimeID is an optional runtime filter. Only runtime events for the given runtime and node
+	// events for nodes that support the given runtime are returned.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+
+	// Limit is the maximum number of events to return. If zero, MaxEventsRangePageSize is used.
+	Limit uint64 `json:"limit,omitempty"`
+	// Cursor is an optional cursor returned with a previous page to continue from.
+	Cursor *EventsCursor `json:"cursor,omitempty"`
+}
+
+// Matches checks whether the given event satisfies the query filters.
+func (q *EventsRangeQuery) Matches(ev *Event) bool {
+	var (
+		kind       EventKind
+		entityID   *signature.PublicKey
+		nodeID     *signature.PublicKey
+		hasRuntime bool
+	)
+	switch {
+	case ev.EntityEvent != nil:
+		kind = EventKindEntity
+		entityID = &ev.EntityEvent.Entity.ID
+	case ev.NodeEvent != nil:
+		kind = EventKindNode
+		entityID = &ev.NodeEvent.Node.EntityID
+		nodeID = &ev.NodeEvent.Node.ID
+		hasRuntime = q.RuntimeID != nil && ev.NodeEvent.Node.GetRuntime(*q.RuntimeID) != nil
+	case ev.NodeUnfrozenEvent != nil:
+		kind = EventKindNodeUnfrozen
+		nodeID = &ev.NodeUnfrozenEvent.NodeID
+	case ev.NodeSuspendedEvent != nil:
+		kind = EventKindNodeSuspended
+		nodeID = &ev.NodeSuspendedEvent.NodeID
+	case ev.NodeResumedEvent != nil:
+		kind = EventKindNodeResumed
+		nodeID = &ev.NodeResumedEvent.NodeID
+	case ev.RuntimeEvent != nil:
+		kind = EventKindRuntime
+		entityID = &ev.RuntimeEvent.Runtime.EntityID
+		hasRuntime = q.RuntimeID != nil && ev.RuntimeEvent.Runtime.ID.Equal(q.RuntimeID)
+	case ev.DeprecationWarningEvent != nil:
+		kind = EventKindDeprecationWarning
+		entityID = &ev.DeprecationWarningEvent.EntityID
+		nodeID = ev.DeprecationWarningEvent.NodeID
+	default:
+		return false
+	}
+
+	switch {
+	case q.Kinds != 0 && q.Kinds&kind == 0:
+		return false
+	case q.EntityID != nil && (entityID == nil || !entityID.Equal(*q.EntityID)):
+		return false
+	case q.NodeID != nil && (nodeID == nil || !nodeID.Equal(*q.NodeID)):
+		return false
+	case q.RuntimeID != nil && !hasRuntime:
+		return false
+	default:
+		return true
+	}
+}
+
+// EventsRangePage is a page of events returned by an events range query.
+type EventsRangePage struct {
+	// Events are the matching events in order of emission.
+	Events []*Event `json:"events"`
+	// Next is the cursor to pass in the query to fetch the next page. It is nil in case there
+	// are no more events in the queried range.
+	Next *EventsCursor `json:"next,omitempty"`
 }
 
 // NodeList is a per-epoch immutable node list.
@@ -577,6 +926,24 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 				return nil, nil, err
 			}
 
+			// Validate advertised storage capabilities.
+			if rt.Capabilities.Storage != nil {
+				if !n.HasRoles(node.RoleStorageWorker) {
+					return nil, nil, fmt.Errorf("%w: storage capability not allowed", ErrInvalidArgument)
+				}
+				if len(rt.Capabilities.Storage.Checkpoints) > node.MaxStorageCheckpoints {
+					return nil, nil, fmt.Errorf("%w: too many advertised storage checkpoints", ErrInvalidArgument)
+				}
+				if storeURL := rt.Capabilities.Storage.CheckpointStoreURL; storeURL != "" {
+					if len(storeURL) > node.MaxStorageCheckpointStoreURLLength {
+						return nil, nil, fmt.Errorf("%w: advertised checkpoint store URL too long", ErrInvalidArgument)
+					}
+					if u, err := url.Parse(storeURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+						return nil, nil, fmt.Errorf("%w: malformed advertised checkpoint store URL", ErrInvalidArgument)
+					}
+				}
+			}
+
 			// Enforce what kinds of runtimes are allowed.
 			if regRt.Kind == KindKeyManager && !n.HasRoles(KeyManagerRuntimeAllowedRoles) {
 				return nil, nil, fmt.Errorf("%w: key manager runtime not allowed", ErrInvalidArgument)
@@ -665,6 +1032,14 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 		return nil, nil, err
 	}
 
+	// Validate VRFInfo.
+	if n.VRF != nil && !n.VRF.ID.IsValid() {
+		logger.Error("RegisterNode: invalid VRF ID",
+			"node", n,
+		)
+		return nil, nil, fmt.Errorf("%w: invalid VRF ID", ErrInvalidArgument)
+	}
+
 	// Make sure that the consensus, TLS and P2P keys are unique (between
 	// themselves and compared to other nodes).
 	//
@@ -799,6 +1174,19 @@ func VerifyNodeRuntimeEnclaveIDs(logger *logging.Logger, rt *node.Runtime, regRt
 			)
 			return ErrBadEnclaveIdentity
 		}
+
+		if vi.Policy != nil {
+			if err := vi.Policy.Verify(avr, ts); err != nil {
+				logger.Error("VerifyNodeRuntimeEnclaveIDs: attestation policy violation",
+					"quote", q,
+					"node_runtime", rt,
+					"registry_runtime", regRt,
+					"ts", ts,
+					"err", err,
+				)
+				return fmt.Errorf("%w: %s", ErrAttestationPolicyViolation, err)
+			}
+		}
 	default:
 		return ErrBadCapabilitiesTEEHardware
 	}
@@ -1072,16 +1460,38 @@ func VerifyRegisterRuntimeArgs( // nolint: gocyclo
 			if len(vi.Enclaves) == 0 {
 				return nil, fmt.Errorf("%w: invalid VersionInfo", ErrNoEnclaveForRuntime)
 			}
+			if vi.Policy != nil {
+				if err := vi.Policy.ValidateBasic(); err != nil {
+					logger.Error("RegisterRuntime: invalid SGX attestation policy",
+						"policy", vi.Policy,
+						"err", err,
+					)
+					return nil, fmt.Errorf("%w: invalid attestation policy: %s", ErrInvalidArgument, err)
+				}
+			}
 		}
 	}
 
 	// Ensure there's a valid admission policy.
-	if !exactlyOneTrue(rt.AdmissionPolicy.AnyNode != nil, rt.AdmissionPolicy.EntityWhitelist != nil) {
+	if !exactlyOneTrue(
+		rt.AdmissionPolicy.AnyNode != nil,
+		rt.AdmissionPolicy.EntityWhitelist != nil,
+		rt.AdmissionPolicy.NodeWhitelist != nil,
+	) {
 		logger.Error("RegisterRuntime: invalid admission policy. exactly one policy should be non-nil",
 			"admission_policy", rt.AdmissionPolicy,
 		)
 		return nil, fmt.Errorf("%w: invalid admission policy", ErrInvalidArgument)
 	}
+	if nw := rt.AdmissionPolicy.NodeWhitelist; nw != nil {
+		if err := nw.ValidateBasic(); err != nil {
+			logger.Error("RegisterRuntime: invalid node whitelist admission policy",
+				"admission_policy", rt.AdmissionPolicy,
+				"err", err,
+			)
+			return nil, fmt.Errorf("%w: invalid admission policy: %s", ErrInvalidArgument, err)
+		}
+	}
 
 	return &rt, nil
 }
@@ -1227,6 +1637,62 @@ type ConsensusParameters struct {
 	// MaxNodeExpiration is the maximum number of epochs relative to the epoch
 	// at registration time that a single node registration is valid for.
 	MaxNodeExpiration uint64 `json:"max_node_expiration,omitempty"`
+
+	// MinHeartbeatInterval is the minimum number of blocks between two heartbeats
+	// of the same node. Zero means that node heartbeats are disabled.
+	MinHeartbeatInterval int64 `json:"min_heartbeat_interval,omitempty"`
+
+	// MaxNodesPerEntity is the maximum number of (non-expired) nodes a single
+	// entity can have registered. Zero means that the number is not limited.
+	MaxNodesPerEntity uint16 `json:"max_nodes_per_entity,omitempty"`
+
+	// MaxNodesPerEntityRole is the maximum number of (non-expired) nodes with
+	// the given role a single entity can have registered. Roles that are not
+	// present are not limited.
+	MaxNodesPerEntityRole map[node.RolesMask]uint16 `json:"max_nodes_per_entity_role,omitempty"`
+}
+
+// VerifyEntityNodeQuota verifies that registering the given node does not exceed the per-entity
+// node quotas, given the other (non-expired) nodes registered by the owning entity.
+func (p *ConsensusParameters) VerifyEntityNodeQuota(newNode *node.Node, entityNodes []*node.Node) error {
+	if p.MaxNodesPerEntity == 0 && len(p.MaxNodesPerEntityRole) == 0 {
+		return nil
+	}
+
+	var total uint16
+	roleCounts := make(map[node.RolesMask]uint16)
+	for _, n := range entityNodes {
+		if n.ID.Equal(newNode.ID) || !n.EntityID.Equal(newNode.EntityID) {
+			continue
+		}
+		total++
+		for role := range p.MaxNodesPerEntityRole {
+			if n.HasRoles(role) {
+				roleCounts[role]++
+			}
+		}
+	}
+
+	if p.MaxNodesPerEntity > 0 && total >= p.MaxNodesPerEntity {
+		return fmt.Errorf("%w: entity %s already has %d nodes (max: %d)",
+			ErrEntityNodeQuotaExceeded,
+			newNode.EntityID,
+			total,
+			p.MaxNodesPerEntity,
+		)
+	}
+	for role, maxNodes := range p.MaxNodesPerEntityRole {
+		if newNode.HasRoles(role) && roleCounts[role] >= maxNodes {
+			return fmt.Errorf("%w: entity %s already has %d %s nodes (max: %d)",
+				ErrEntityNodeQuotaExceeded,
+				newNode.EntityID,
+				roleCounts[role],
+				role,
+				maxNodes,
+			)
+		}
+	}
+	return nil
 }
 
 const (
@@ -1246,6 +1712,12 @@ const (
 	// GasOpUpdateKeyManager is the gas operation identifier for key manager
 	// policy updates costs.
 	GasOpUpdateKeyManager transaction.Op = "update_keymanager"
+	// GasOpHeartbeatNode is the gas operation identifier for node heartbeats.
+	GasOpHeartbeatNode transaction.Op = "heartbeat_node"
+	// GasOpSuspendNode is the gas operation identifier for suspending nodes.
+	GasOpSuspendNode transaction.Op = "suspend_node"
+	// GasOpResumeNode is the gas operation identifier for resuming nodes.
+	GasOpResumeNode transaction.Op = "resume_node"
 )
 
 // XXX: Define reasonable default gas costs.
@@ -1259,6 +1731,9 @@ var DefaultGasCosts = transaction.Costs{
 	GasOpRegisterRuntime:         1000,
 	GasOpRuntimeEpochMaintenance: 1000,
 	GasOpUpdateKeyManager:        1000,
+	GasOpHeartbeatNode:           100,
+	GasOpSuspendNode:             1000,
+	GasOpResumeNode:              1000,
 }
 
 const (
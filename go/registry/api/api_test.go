@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+func TestNewStaleNodes(t *testing.T) {
+	require := require.New(t)
+
+	var entityA, entityB signature.PublicKey
+	_ = entityA.UnmarshalHex("4ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+	_ = entityB.UnmarshalHex("5ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+
+	nodes := []*node.Node{
+		{EntityID: entityA, Expiration: 9},  // Expired.
+		{EntityID: entityA, Expiration: 10}, // Expires at the end of the current epoch.
+		{EntityID: entityA, Expiration: 12},
+		{EntityID: entityB, Expiration: 12},
+		{EntityID: entityB, Expiration: 13},
+	}
+
+	stale := NewStaleNodes(10, 2, nodes)
+	require.EqualValues(10, stale.Epoch, "epoch should be recorded")
+	require.Equal(nodes[1:4], stale.Nodes, "only nodes expiring within the threshold should be stale")
+	require.Len(stale.EntityCounts, 2, "stale nodes should be counted per entity")
+	require.EqualValues(2, stale.EntityCounts[entityA], "entity A stale node count")
+	require.EqualValues(1, stale.EntityCounts[entityB], "entity B stale node count")
+
+	stale = NewStaleNodes(10, 0, nodes)
+	require.Equal(nodes[1:2], stale.Nodes, "zero threshold should only include nodes expiring in the current epoch")
+
+	stale = NewStaleNodes(20, 5, nodes)
+	require.Empty(stale.Nodes, "expired nodes should never be stale")
+	require.Empty(stale.EntityCounts, "expired nodes should not be counted")
+}
+
+func TestVerifyEntityNodeQuota(t *testing.T) {
+	require := require.New(t)
+
+	var entityA, entityB, nodeA, nodeB, nodeC signature.PublicKey
+	_ = entityA.UnmarshalHex("4ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+	_ = entityB.UnmarshalHex("5ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+	_ = nodeA.UnmarshalHex("6ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+	_ = nodeB.UnmarshalHex("7ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+	_ = nodeC.UnmarshalHex("8ea5328f943ef6f66daaed74cb0e99c3b1c45f76307b425003dbc7cb3638ed35")
+
+	entityNodes := []*node.Node{
+		{ID: nodeA, EntityID: entityA, Roles: node.RoleComputeWorker},
+		{ID: nodeB, EntityID: entityA, Roles: node.RoleComputeWorker | node.RoleStorageWorker},
+		{ID: nodeC, EntityID: entityB, Roles: node.RoleValidator}, // Different entity.
+	}
+	newNode := &node.Node{EntityID: entityA, Roles: node.RoleValidator}
+
+	var params ConsensusParameters
+	require.NoError(params.VerifyEntityNodeQuota(newNode, entityNodes), "no limits should pass")
+
+	params.MaxNodesPerEntity = 3
+	require.NoError(params.VerifyEntityNodeQuota(newNode, entityNodes), "node within the global limit should pass")
+
+	params.MaxNodesPerEntity = 2
+	err := params.VerifyEntityNodeQuota(newNode, entityNodes)
+	require.True(errors.Is(err, ErrEntityNodeQuotaExceeded), "node exceeding the global limit should be rejected")
+
+	// Updating an existing node should not count the node itself.
+	updatedNode := *entityNodes[1]
+	require.NoError(params.VerifyEntityNodeQuota(&updatedNode, entityNodes), "node update within the global limit should pass")
+
+	params.MaxNodesPerEntity = 0
+	params.MaxNodesPerEntityRole = map[node.RolesMask]uint16{
+		node.RoleComputeWorker: 2,
+		node.RoleStorageWorker: 1,
+	}
+	require.NoError(params.VerifyEntityNodeQuota(newNode, entityNodes), "node without limited roles should pass")
+
+	newNode.Roles = node.RoleComputeWorker
+	err = params.VerifyEntityNodeQuota(newNode, entityNodes)
+	require.True(errors.Is(err, ErrEntityNodeQuotaExceeded), "node exceeding the compute limit should be rejected")
+
+	newNode.Roles = node.RoleStorageWorker
+	err = params.VerifyEntityNodeQuota(newNode, entityNodes)
+	require.True(errors.Is(err, ErrEntityNodeQuotaExceeded), "node exceeding the storage limit should be rejected")
+
+	params.MaxNodesPerEntityRole[node.RoleStorageWorker] = 2
+	require.NoError(params.VerifyEntityNodeQuota(newNode, entityNodes), "node within the per-role limits should pass")
+}
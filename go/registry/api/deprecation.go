@@ -0,0 +1,58 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+// DeprecationWarning is a warning about the use of a deprecated descriptor field or descriptor
+// version. Descriptors using deprecated features are still accepted, but may be rejected in a
+// future release.
+type DeprecationWarning struct {
+	// Field is the name of the deprecated descriptor field.
+	Field string `json:"field"`
+	// Message is a human readable description of the deprecation.
+	Message string `json:"message"`
+}
+
+// String returns a string representation of the deprecation warning.
+func (w DeprecationWarning) String() string {
+	return w.Field + ": " + w.Message
+}
+
+// DeprecationWarningEvent signifies that a registered descriptor uses deprecated features.
+type DeprecationWarningEvent struct {
+	// EntityID is the identifier of the entity that controls the descriptor.
+	EntityID signature.PublicKey `json:"entity_id"`
+	// NodeID is the identifier of the node in case the descriptor is a node descriptor.
+	NodeID *signature.PublicKey `json:"node_id,omitempty"`
+	// Warnings are the deprecation warnings.
+	Warnings []DeprecationWarning `json:"warnings"`
+}
+
+// descriptorVersionDeprecation returns a deprecation warning in case the given descriptor
+// version is still allowed, but older than the latest descriptor version.
+func descriptorVersionDeprecation(kind string, v, latest uint16) []DeprecationWarning {
+	if v >= latest {
+		return nil
+	}
+	return []DeprecationWarning{
+		{
+			Field:   "v",
+			Message: fmt.Sprintf("%s descriptor version %d is deprecated, use version %d", kind, v, latest),
+		},
+	}
+}
+
+// EntityDeprecationWarnings returns the deprecation warnings for the given entity descriptor.
+func EntityDeprecationWarnings(ent *entity.Entity) []DeprecationWarning {
+	return descriptorVersionDeprecation("entity", ent.Versioned.V, entity.LatestEntityDescriptorVersion)
+}
+
+// NodeDeprecationWarnings returns the deprecation warnings for the given node descriptor.
+func NodeDeprecationWarnings(n *node.Node) []DeprecationWarning {
+	return descriptorVersionDeprecation("node", n.Versioned.V, node.LatestNodeDescriptorVersion)
+}
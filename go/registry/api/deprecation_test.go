@@ -0,0 +1,82 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	require := require.New(t)
+
+	ent := &entity.Entity{Versioned: cbor.NewVersioned(entity.LatestEntityDescriptorVersion)}
+	require.Empty(EntityDeprecationWarnings(ent), "latest entity descriptor version should not be deprecated")
+
+	n := &node.Node{Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion)}
+	require.Empty(NodeDeprecationWarnings(n), "latest node descriptor version should not be deprecated")
+
+	warnings := descriptorVersionDeprecation("node", 1, 2)
+	require.Len(warnings, 1, "older descriptor version should be deprecated")
+	require.Equal("v", warnings[0].Field)
+	require.Equal("v: node descriptor version 1 is deprecated, use version 2", warnings[0].String())
+
+	require.Empty(descriptorVersionDeprecation("node", 2, 2), "latest descriptor version should not be deprecated")
+}
+
+func TestDeprecationWarningEventMatches(t *testing.T) {
+	require := require.New(t)
+
+	entityID := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	nodeID := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	otherID := signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	ev := &Event{
+		DeprecationWarningEvent: &DeprecationWarningEvent{
+			EntityID: entityID,
+			NodeID:   &nodeID,
+			Warnings: []DeprecationWarning{{Field: "v", Message: "deprecated"}},
+		},
+	}
+
+	for _, tc := range []struct {
+		query   EventsRangeQuery
+		matches bool
+		msg     string
+	}{
+		{EventsRangeQuery{}, true, "empty query"},
+		{EventsRangeQuery{Kinds: EventKindDeprecationWarning}, true, "deprecation warning kind"},
+		{EventsRangeQuery{Kinds: EventKindNode}, false, "node kind"},
+		{EventsRangeQuery{EntityID: &entityID}, true, "matching entity"},
+		{EventsRangeQuery{EntityID: &otherID}, false, "other entity"},
+		{EventsRangeQuery{NodeID: &nodeID}, true, "matching node"},
+		{EventsRangeQuery{NodeID: &otherID}, false, "other node"},
+	} {
+		require.Equal(tc.matches, tc.query.Matches(ev), tc.msg)
+	}
+
+	// Entity deprecation warnings do not match node filters.
+	ev.DeprecationWarningEvent.NodeID = nil
+	require.False((&EventsRangeQuery{NodeID: &nodeID}).Matches(ev), "entity warning with node filter")
+}
+
+func TestNodeSuspensionEventMatches(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	otherID := signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	suspended := &Event{NodeSuspendedEvent: &NodeSuspendedEvent{NodeID: nodeID}}
+	resumed := &Event{NodeResumedEvent: &NodeResumedEvent{NodeID: nodeID}}
+
+	require.True((&EventsRangeQuery{Kinds: EventKindNodeSuspended}).Matches(suspended), "node suspended kind")
+	require.False((&EventsRangeQuery{Kinds: EventKindNodeResumed}).Matches(suspended), "node resumed kind")
+	require.True((&EventsRangeQuery{Kinds: EventKindNodeResumed}).Matches(resumed), "node resumed kind")
+	require.True((&EventsRangeQuery{NodeID: &nodeID}).Matches(resumed), "matching node")
+	require.False((&EventsRangeQuery{NodeID: &otherID}).Matches(suspended), "other node")
+	require.False((&EventsRangeQuery{EntityID: &otherID}).Matches(suspended), "entity filter")
+}
@@ -25,16 +25,26 @@ var (
 	methodGetNodeByConsensusAddress = serviceName.NewMethod("GetNodeByConsensusAddress", ConsensusAddressQuery{})
 	// methodGetNodeStatus is the GetNodeStatus method.
 	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{})
+	// methodValidateNode is the ValidateNode method.
+	methodValidateNode = serviceName.NewMethod("ValidateNode", ValidateNodeQuery{})
 	// methodGetNodes is the GetNodes method.
 	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0))
+	// methodGetFreshNodes is the GetFreshNodes method.
+	methodGetFreshNodes = serviceName.NewMethod("GetFreshNodes", FreshNodesQuery{})
+	// methodGetStaleNodes is the GetStaleNodes method.
+	methodGetStaleNodes = serviceName.NewMethod("GetStaleNodes", StaleNodesQuery{})
 	// methodGetRuntime is the GetRuntime method.
 	methodGetRuntime = serviceName.NewMethod("GetRuntime", NamespaceQuery{})
 	// methodGetRuntimes is the GetRuntimes method.
 	methodGetRuntimes = serviceName.NewMethod("GetRuntimes", int64(0))
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
+	// methodConsensusParameters is the ConsensusParameters method.
+	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	// methodGetEventsRange is the GetEventsRange method.
+	methodGetEventsRange = serviceName.NewMethod("GetEventsRange", EventsRangeQuery{})
 
 	// methodWatchEntities is the WatchEntities method.
 	methodWatchEntities = serviceName.NewMethod("WatchEntities", nil)
@@ -44,6 +54,8 @@ var (
 	methodWatchNodeList = serviceName.NewMethod("WatchNodeList", nil)
 	// methodWatchRuntimes is the WatchRuntimes method.
 	methodWatchRuntimes = serviceName.NewMethod("WatchRuntimes", nil)
+	// methodWatchDeprecationWarnings is the WatchDeprecationWarnings method.
+	methodWatchDeprecationWarnings = serviceName.NewMethod("WatchDeprecationWarnings", nil)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -70,10 +82,22 @@ var (
 				MethodName: methodGetNodeStatus.ShortName(),
 				Handler:    handlerGetNodeStatus,
 			},
+			{
+				MethodName: methodValidateNode.ShortName(),
+				Handler:    handlerValidateNode,
+			},
 			{
 				MethodName: methodGetNodes.ShortName(),
 				Handler:    handlerGetNodes,
 			},
+			{
+				MethodName: methodGetFreshNodes.ShortName(),
+				Handler:    handlerGetFreshNodes,
+			},
+			{
+				MethodName: methodGetStaleNodes.ShortName(),
+				Handler:    handlerGetStaleNodes,
+			},
 			{
 				MethodName: methodGetRuntime.ShortName(),
 				Handler:    handlerGetRuntime,
@@ -86,10 +110,18 @@ var (
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
 			},
+			{
+				MethodName: methodConsensusParameters.ShortName(),
+				Handler:    handlerConsensusParameters,
+			},
 			{
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetEventsRange.ShortName(),
+				Handler:    handlerGetEventsRange,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -112,6 +144,11 @@ var (
 				Handler:       handlerWatchRuntimes,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchDeprecationWarnings.ShortName(),
+				Handler:       handlerWatchDeprecationWarnings,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -162,6 +199,52 @@ func handlerGetEntities( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetFreshNodes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query FreshNodesQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetFreshNodes(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetFreshNodes.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetFreshNodes(ctx, req.(*FreshNodesQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetStaleNodes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query StaleNodesQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetStaleNodes(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetStaleNodes.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetStaleNodes(ctx, req.(*StaleNodesQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetNode( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -231,6 +314,29 @@ func handlerGetNodeStatus( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerValidateNode( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query ValidateNodeQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).ValidateNode(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodValidateNode.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).ValidateNode(ctx, req.(*ValidateNodeQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetNodes( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -323,6 +429,29 @@ func handlerStateToGenesis( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerConsensusParameters( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).ConsensusParameters(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodConsensusParameters.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).ConsensusParameters(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerGetEvents( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -346,6 +475,29 @@ func handlerGetEvents( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetEventsRange( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query EventsRangeQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEventsRange(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEventsRange.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetEventsRange(ctx, req.(*EventsRangeQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerWatchEntities(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -458,6 +610,34 @@ func handlerWatchRuntimes(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchDeprecationWarnings(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchDeprecationWarnings(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new registry backend service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -542,6 +722,14 @@ func (c *registryClient) GetNodeStatus(ctx context.Context, query *IDQuery) (*No
 	return &rsp, nil
 }
 
+func (c *registryClient) ValidateNode(ctx context.Context, query *ValidateNodeQuery) (*NodeValidationResult, error) {
+	var rsp NodeValidationResult
+	if err := c.conn.Invoke(ctx, methodValidateNode.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	var rsp []*node.Node
 	if err := c.conn.Invoke(ctx, methodGetNodes.FullName(), height, &rsp); err != nil {
@@ -550,6 +738,22 @@ func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return rsp, nil
 }
 
+func (c *registryClient) GetFreshNodes(ctx context.Context, query *FreshNodesQuery) ([]*node.Node, error) {
+	var rsp []*node.Node
+	if err := c.conn.Invoke(ctx, methodGetFreshNodes.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *registryClient) GetStaleNodes(ctx context.Context, query *StaleNodesQuery) (*StaleNodes, error) {
+	var rsp StaleNodes
+	if err := c.conn.Invoke(ctx, methodGetStaleNodes.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) WatchNodes(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -671,6 +875,41 @@ func (c *registryClient) WatchRuntimes(ctx context.Context) (<-chan *Runtime, pu
 	return ch, sub, nil
 }
 
+func (c *registryClient) WatchDeprecationWarnings(ctx context.Context) (<-chan *DeprecationWarningEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[4], methodWatchDeprecationWarnings.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *DeprecationWarningEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev DeprecationWarningEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *registryClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -679,6 +918,14 @@ func (c *registryClient) StateToGenesis(ctx context.Context, height int64) (*Gen
 	return &rsp, nil
 }
 
+func (c *registryClient) ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error) {
+	var rsp ConsensusParameters
+	if err := c.conn.Invoke(ctx, methodConsensusParameters.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) GetEvents(ctx context.Context, height int64) ([]*Event, error) {
 	var rsp []*Event
 	if err := c.conn.Invoke(ctx, methodGetEvents.FullName(), height, &rsp); err != nil {
@@ -687,6 +934,14 @@ func (c *registryClient) GetEvents(ctx context.Context, height int64) ([]*Event,
 	return rsp, nil
 }
 
+func (c *registryClient) GetEventsRange(ctx context.Context, query *EventsRangeQuery) (*EventsRangePage, error) {
+	var rsp EventsRangePage
+	if err := c.conn.Invoke(ctx, methodGetEventsRange.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) Cleanup() {
 }
 
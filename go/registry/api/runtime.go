@@ -16,10 +16,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
 var (
@@ -92,6 +95,11 @@ type ExecutorParameters struct {
 
 	// RoundTimeout is the round timeout in consensus blocks.
 	RoundTimeout int64 `json:"round_timeout"`
+
+	// MinLiveCommitteePercent is the minimum percentage of the elected executor committee
+	// members that must be registered and not frozen at the start of a round for the round to
+	// accept commitments. Zero means that the check is disabled.
+	MinLiveCommitteePercent uint8 `json:"min_live_committee_percent,omitempty"`
 }
 
 // ValidateBasic performs basic executor parameter validity checks.
@@ -106,6 +114,9 @@ func (e *ExecutorParameters) ValidateBasic() error {
 	if e.RoundTimeout < 5 {
 		return fmt.Errorf("round timeout too small")
 	}
+	if e.MinLiveCommitteePercent > 100 {
+		return fmt.Errorf("minimum live committee percentage too large")
+	}
 	return nil
 }
 
@@ -221,10 +232,31 @@ type EntityWhitelistRuntimeAdmissionPolicy struct {
 	Entities map[signature.PublicKey]bool `json:"entities"`
 }
 
+// NodeWhitelistRuntimeAdmissionPolicy allows only whitelisted nodes to register.
+type NodeWhitelistRuntimeAdmissionPolicy struct {
+	// Nodes are the identifiers of the whitelisted nodes.
+	Nodes map[signature.PublicKey]bool `json:"nodes"`
+
+	// MaxNodes are the maximum numbers of nodes with a given role that can
+	// be registered for the runtime. Roles without a limit are not limited.
+	MaxNodes map[node.RolesMask]uint16 `json:"max_nodes,omitempty"`
+}
+
+// ValidateBasic performs basic policy validity checks.
+func (p *NodeWhitelistRuntimeAdmissionPolicy) ValidateBasic() error {
+	for role := range p.MaxNodes {
+		if !role.IsSingleRole() {
+			return fmt.Errorf("invalid role in max nodes limits: %d", role)
+		}
+	}
+	return nil
+}
+
 // RuntimeAdmissionPolicy is a specification of which nodes are allowed to register for a runtime.
 type RuntimeAdmissionPolicy struct {
 	AnyNode         *AnyNodeRuntimeAdmissionPolicy         `json:"any_node,omitempty"`
 	EntityWhitelist *EntityWhitelistRuntimeAdmissionPolicy `json:"entity_whitelist,omitempty"`
+	NodeWhitelist   *NodeWhitelistRuntimeAdmissionPolicy   `json:"node_whitelist,omitempty"`
 }
 
 // RuntimeStakingParameters are the stake-related parameters for a runtime.
@@ -446,6 +478,93 @@ type VersionInfo struct {
 type VersionInfoIntelSGX struct {
 	// Enclaves is the allowed MRENCLAVE/MRSIGNER pairs.
 	Enclaves []sgx.EnclaveIdentity `json:"enclaves"`
+
+	// Policy is the optional remote attestation policy that node attestations must satisfy in
+	// addition to having an allowed enclave identity.
+	Policy *AttestationPolicyIntelSGX `json:"policy,omitempty"`
+}
+
+// AttestationPolicyIntelSGX is the SGX remote attestation policy.
+type AttestationPolicyIntelSGX struct {
+	// MaxAttestationAge is the maximum age (in seconds) of the attestation verification report
+	// at the time the node (re-)registers. Zero means that attestations never expire.
+	MaxAttestationAge uint64 `json:"max_attestation_age,omitempty"`
+
+	// MinQESVN is the minimum security version number of the quoting enclave.
+	MinQESVN uint16 `json:"min_qe_svn,omitempty"`
+
+	// MinPCESVN is the minimum security version number of the provisioning certification
+	// enclave.
+	MinPCESVN uint16 `json:"min_pce_svn,omitempty"`
+
+	// AllowedQuoteStatuses are the allowed quote statuses (TCB levels) as reported by the
+	// attestation service. If empty, only quotes with an OK status are allowed.
+	AllowedQuoteStatuses []ias.ISVEnclaveQuoteStatus `json:"allowed_quote_statuses,omitempty"`
+}
+
+// ValidateBasic performs basic attestation policy validity checks.
+func (p *AttestationPolicyIntelSGX) ValidateBasic() error {
+	seen := make(map[ias.ISVEnclaveQuoteStatus]bool)
+	for _, status := range p.AllowedQuoteStatuses {
+		switch status {
+		case ias.QuoteOK,
+			ias.QuoteGroupOutOfDate,
+			ias.QuoteConfigurationNeeded,
+			ias.QuoteSwHardeningNeeded,
+			ias.QuoteConfigurationAndSwHardeningNeeded:
+		default:
+			// Invalid and revoked quotes can never be allowed.
+			return fmt.Errorf("quote status not allowed in policy: %d", int(status))
+		}
+		if seen[status] {
+			return fmt.Errorf("duplicate quote status in policy: %s", status)
+		}
+		seen[status] = true
+	}
+	return nil
+}
+
+// Verify verifies that the given attestation verification report satisfies the policy at the
+// provided timestamp.
+func (p *AttestationPolicyIntelSGX) Verify(avr *ias.AttestationVerificationReport, ts time.Time) error {
+	if p.MaxAttestationAge > 0 {
+		avrTs, err := time.Parse(ias.TimestampFormat, avr.Timestamp)
+		if err != nil {
+			return fmt.Errorf("malformed attestation timestamp: %w", err)
+		}
+		maxAge := time.Duration(p.MaxAttestationAge) * time.Second
+		if age := ts.Sub(avrTs); age > maxAge {
+			return fmt.Errorf("attestation too old (age: %s max: %s)", age, maxAge)
+		}
+	}
+
+	q, err := avr.Quote()
+	if err != nil {
+		return err
+	}
+	if q.Body.ISVSVNQuotingEnclave < p.MinQESVN {
+		return fmt.Errorf("quoting enclave SVN too low (min: %d got: %d)",
+			p.MinQESVN,
+			q.Body.ISVSVNQuotingEnclave,
+		)
+	}
+	if q.Body.ISVSVNProvisioningCertificationEnclave < p.MinPCESVN {
+		return fmt.Errorf("provisioning certification enclave SVN too low (min: %d got: %d)",
+			p.MinPCESVN,
+			q.Body.ISVSVNProvisioningCertificationEnclave,
+		)
+	}
+
+	allowed := p.AllowedQuoteStatuses
+	if len(allowed) == 0 {
+		allowed = []ias.ISVEnclaveQuoteStatus{ias.QuoteOK}
+	}
+	for _, status := range allowed {
+		if avr.ISVEnclaveQuoteStatus == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("quote status not allowed: %s", avr.ISVEnclaveQuoteStatus)
 }
 
 // RuntimeGenesis is the runtime genesis information that is used to
@@ -469,6 +588,55 @@ type RuntimeGenesis struct {
 
 	// Round is the runtime round in the genesis.
 	Round uint64 `json:"round"`
+
+	// StateCheckpoint is an optional reference to a storage checkpoint of
+	// the state identified by the StateRoot. If set, State must be empty and
+	// storage nodes fetch (and verify) the genesis state from the checkpoint
+	// chunks served by other storage nodes on first sync.
+	StateCheckpoint *RuntimeGenesisCheckpoint `json:"state_checkpoint,omitempty"`
+}
+
+// RuntimeGenesisCheckpoint is a reference to a storage checkpoint containing
+// the runtime genesis state.
+type RuntimeGenesisCheckpoint struct {
+	// Version is the checkpoint format version.
+	Version uint16 `json:"version"`
+
+	// Chunks are the hashes of the checkpoint chunks.
+	Chunks []hash.Hash `json:"chunks"`
+}
+
+// Equal compares vs another RuntimeGenesisCheckpoint for equality.
+func (gc *RuntimeGenesisCheckpoint) Equal(cmp *RuntimeGenesisCheckpoint) bool {
+	if gc == nil || cmp == nil {
+		return gc == cmp
+	}
+	if gc.Version != cmp.Version {
+		return false
+	}
+	if len(gc.Chunks) != len(cmp.Chunks) {
+		return false
+	}
+	for k, v := range gc.Chunks {
+		if !v.Equal(&cmp.Chunks[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Metadata returns the checkpoint metadata for the referenced checkpoint of
+// the given runtime genesis state root.
+func (gc *RuntimeGenesisCheckpoint) Metadata(id common.Namespace, round uint64, stateRoot hash.Hash) *checkpoint.Metadata {
+	return &checkpoint.Metadata{
+		Version: gc.Version,
+		Root: mkvsNode.Root{
+			Namespace: id,
+			Version:   round,
+			Hash:      stateRoot,
+		},
+		Chunks: gc.Chunks,
+	}
 }
 
 // Equal compares vs another RuntimeGenesis for equality.
@@ -490,18 +658,34 @@ func (rtg *RuntimeGenesis) Equal(cmp *RuntimeGenesis) bool {
 			return false
 		}
 	}
-	return true
+	return rtg.StateCheckpoint.Equal(cmp.StateCheckpoint)
 }
 
 // SanityCheck does basic sanity checking of RuntimeGenesis.
 // isGenesis is true, if it is called during consensus chain init.
 func (rtg *RuntimeGenesis) SanityCheck(isGenesis bool) error {
+	if rtg.StateCheckpoint != nil {
+		if len(rtg.State) != 0 {
+			return fmt.Errorf("runtimegenesis: sanity check failed: State must be empty when StateCheckpoint is set")
+		}
+		if rtg.StateRoot.IsEmpty() {
+			return fmt.Errorf("runtimegenesis: sanity check failed: StateRoot must be non-empty when StateCheckpoint is set")
+		}
+		if rtg.StateCheckpoint.Version != 1 {
+			return fmt.Errorf("runtimegenesis: sanity check failed: unsupported StateCheckpoint version %d", rtg.StateCheckpoint.Version)
+		}
+		if len(rtg.StateCheckpoint.Chunks) == 0 {
+			return fmt.Errorf("runtimegenesis: sanity check failed: StateCheckpoint must have at least one chunk")
+		}
+	}
+
 	if isGenesis {
 		return nil
 	}
 
-	// Require that either State is non-empty or Storage receipt being valid or StateRoot being non-empty.
-	if len(rtg.State) == 0 && !rtg.StateRoot.IsEmpty() {
+	// Require that either State is non-empty or Storage receipt being valid or StateRoot being non-empty
+	// or the state being referenced by a storage checkpoint.
+	if len(rtg.State) == 0 && !rtg.StateRoot.IsEmpty() && rtg.StateCheckpoint == nil {
 		// If State is empty and StateRoot is not, then all StorageReceipts must correctly verify StorageRoot.
 		if len(rtg.StorageReceipts) == 0 {
 			return fmt.Errorf("runtimegenesis: sanity check failed: when State is empty either StorageReceipts must be populated or StateRoot must be empty")
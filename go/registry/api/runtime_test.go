@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+func TestAttestationPolicyIntelSGX(t *testing.T) {
+	require := require.New(t)
+
+	q := ias.Quote{
+		Body: ias.Body{
+			Version:                                2,
+			SignatureType:                          ias.SignatureLinkable,
+			ISVSVNQuotingEnclave:                   5,
+			ISVSVNProvisioningCertificationEnclave: 7,
+		},
+	}
+	rawQuote, err := q.MarshalBinary()
+	require.NoError(err, "MarshalBinary")
+
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	avr := &ias.AttestationVerificationReport{
+		Timestamp:             now.Add(-10 * time.Minute).Format(ias.TimestampFormat),
+		ISVEnclaveQuoteStatus: ias.QuoteOK,
+		ISVEnclaveQuoteBody:   rawQuote,
+	}
+
+	var policy AttestationPolicyIntelSGX
+	require.NoError(policy.ValidateBasic(), "empty policy should be valid")
+	require.NoError(policy.Verify(avr, now), "empty policy should allow OK quotes")
+
+	// Freshness.
+	policy.MaxAttestationAge = 3600
+	require.NoError(policy.Verify(avr, now), "fresh attestation should be allowed")
+	require.Error(policy.Verify(avr, now.Add(time.Hour)), "stale attestation should be rejected")
+
+	// QE/PCE SVNs.
+	policy.MinQESVN = 5
+	policy.MinPCESVN = 7
+	require.NoError(policy.Verify(avr, now), "sufficient SVNs should be allowed")
+	policy.MinQESVN = 6
+	require.Error(policy.Verify(avr, now), "QE SVN below minimum should be rejected")
+	policy.MinQESVN = 5
+	policy.MinPCESVN = 8
+	require.Error(policy.Verify(avr, now), "PCE SVN below minimum should be rejected")
+	policy.MinPCESVN = 7
+
+	// TCB level.
+	avr.ISVEnclaveQuoteStatus = ias.QuoteSwHardeningNeeded
+	require.Error(policy.Verify(avr, now), "non-OK quote status should be rejected by default")
+	policy.AllowedQuoteStatuses = []ias.ISVEnclaveQuoteStatus{ias.QuoteOK, ias.QuoteSwHardeningNeeded}
+	require.NoError(policy.ValidateBasic(), "policy should be valid")
+	require.NoError(policy.Verify(avr, now), "allowed quote status should be allowed")
+
+	// Invalid policies.
+	policy.AllowedQuoteStatuses = []ias.ISVEnclaveQuoteStatus{ias.QuoteGroupRevoked}
+	require.Error(policy.ValidateBasic(), "revoked quote status should not be allowed in policy")
+	policy.AllowedQuoteStatuses = []ias.ISVEnclaveQuoteStatus{ias.QuoteOK, ias.QuoteOK}
+	require.Error(policy.ValidateBasic(), "duplicate quote status should not be allowed in policy")
+}
@@ -33,6 +33,14 @@ func (g *Genesis) SanityCheck(
 			return fmt.Errorf("registry: sanity check failed: maximum node expiration not specified")
 		}
 	}
+	if g.Parameters.MinHeartbeatInterval < 0 {
+		return fmt.Errorf("registry: sanity check failed: minimum heartbeat interval is negative")
+	}
+	for role := range g.Parameters.MaxNodesPerEntityRole {
+		if !role.IsSingleRole() {
+			return fmt.Errorf("registry: sanity check failed: invalid role in per-entity node limits: %d", role)
+		}
+	}
 
 	// Check entities.
 	seenEntities, err := SanityCheckEntities(logger, g.Entities)
@@ -52,6 +60,16 @@ func (g *Genesis) SanityCheck(
 		return err
 	}
 
+	// Check node statuses.
+	if err = SanityCheckNodeStatuses(g.NodeStatuses, nodeLookup); err != nil {
+		return err
+	}
+
+	// Check per-entity node quotas.
+	if err = SanityCheckEntityNodeQuotas(&g.Parameters, nodeLookup, baseEpoch); err != nil {
+		return err
+	}
+
 	// Check for blacklisted public keys.
 	entities := []*entity.Entity{}
 	for k, ent := range seenEntities {
@@ -87,6 +105,32 @@ func (g *Genesis) SanityCheck(
 	return nil
 }
 
+// SanityCheckEntityNodeQuotas verifies that no entity has more (non-expired) nodes registered than
+// allowed by the per-entity node quotas.
+func SanityCheckEntityNodeQuotas(params *ConsensusParameters, nodeLookup NodeLookup, epoch epochtime.EpochTime) error {
+	nodes, err := nodeLookup.Nodes(context.Background())
+	if err != nil {
+		return fmt.Errorf("registry: sanity check failed: could not obtain node list from nodeLookup: %w", err)
+	}
+
+	entityNodes := make(map[signature.PublicKey][]*node.Node)
+	for _, n := range nodes {
+		if n.IsExpired(uint64(epoch)) {
+			continue
+		}
+		entityNodes[n.EntityID] = append(entityNodes[n.EntityID], n)
+	}
+	for _, nodes := range entityNodes {
+		// Check each node against the ones before it so that any excess node is reported.
+		for i, n := range nodes {
+			if err = params.VerifyEntityNodeQuota(n, nodes[:i]); err != nil {
+				return fmt.Errorf("registry: sanity check failed: node '%s': %w", n.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
 // SanityCheckEntities examines the entities table.
 // Returns lookup of entity ID to the entity record for use in other checks.
 func SanityCheckEntities(logger *logging.Logger, entities []*entity.SignedEntity) (map[signature.PublicKey]*entity.Entity, error) {
@@ -207,6 +251,33 @@ func SanityCheckNodes(
 	return nodeLookup, nil
 }
 
+// SanityCheckNodeStatuses examines the node statuses table.
+// Pass lookup of nodes from SanityCheckNodes for cross referencing purposes.
+func SanityCheckNodeStatuses(
+	nodeStatuses map[signature.PublicKey]*NodeStatus,
+	nodeLookup NodeLookup,
+) error {
+	nodes, err := nodeLookup.Nodes(context.Background())
+	if err != nil {
+		return fmt.Errorf("registry: sanity check failed: could not obtain node list from nodeLookup: %w", err)
+	}
+	seenNodes := make(map[signature.PublicKey]bool)
+	for _, n := range nodes {
+		seenNodes[n.ID] = true
+	}
+
+	for id, status := range nodeStatuses {
+		if status == nil {
+			return fmt.Errorf("registry: node status sanity check failed: status of node %s is nil", id)
+		}
+		if !seenNodes[id] {
+			return fmt.Errorf("registry: node status sanity check failed: status references a missing node %s", id)
+		}
+	}
+
+	return nil
+}
+
 // SanityCheckStake ensures entities' stake accumulator claims are consistent
 // with general state and entities have enough stake for themselves and all
 // their registered nodes and runtimes.
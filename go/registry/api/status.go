@@ -22,6 +22,15 @@ type NodeStatus struct {
 	// After the specified epoch passes, this flag needs to be explicitly
 	// cleared (set to zero) in order for the node to become unfrozen.
 	FreezeEndTime epochtime.EpochTime `json:"freeze_end_time"`
+	// LastHeartbeatHeight is the height of the block that included the node's
+	// last heartbeat, or zero in case the node never submitted a heartbeat.
+	LastHeartbeatHeight int64 `json:"last_heartbeat_height,omitempty"`
+	// Suspended is a flag specifying whether the node has been suspended by
+	// its owning entity.
+	//
+	// Suspended nodes are not considered in scheduling decisions until they
+	// are explicitly resumed by the owning entity.
+	Suspended bool `json:"suspended,omitempty"`
 }
 
 // IsFrozen returns true if the node is currently frozen (prevented
@@ -30,6 +39,21 @@ func (ns NodeStatus) IsFrozen() bool {
 	return ns.FreezeEndTime > 0
 }
 
+// IsSuspended returns true if the node is currently suspended by its owning
+// entity (prevented from being considered in scheduling decisions).
+func (ns NodeStatus) IsSuspended() bool {
+	return ns.Suspended
+}
+
+// IsFresh returns true if the node has submitted a heartbeat within the
+// freshness window of the given size ending at the given height.
+func (ns NodeStatus) IsFresh(height, window int64) bool {
+	if ns.LastHeartbeatHeight == 0 {
+		return false
+	}
+	return height-ns.LastHeartbeatHeight < window
+}
+
 // Unfreeze makes the node unfrozen.
 func (ns *NodeStatus) Unfreeze() {
 	ns.FreezeEndTime = 0
@@ -39,3 +63,13 @@ func (ns *NodeStatus) Unfreeze() {
 type UnfreezeNode struct {
 	NodeID signature.PublicKey `json:"node_id"`
 }
+
+// SuspendNode is a request to temporarily suspend a node.
+type SuspendNode struct {
+	NodeID signature.PublicKey `json:"node_id"`
+}
+
+// ResumeNode is a request to resume a suspended node.
+type ResumeNode struct {
+	NodeID signature.PublicKey `json:"node_id"`
+}
@@ -47,6 +47,12 @@ var entityNodeSeed = []byte("testRegistryEntityNodes")
 func RegistryImplementationTests(t *testing.T, backend api.Backend, consensus consensusAPI.Backend) {
 	EnsureRegistryEmpty(t, backend)
 
+	t.Run("ConsensusParameters", func(t *testing.T) {
+		params, err := backend.ConsensusParameters(context.Background(), consensusAPI.HeightLatest)
+		require.NoError(t, err, "ConsensusParameters")
+		require.NotNil(t, params, "ConsensusParameters")
+	})
+
 	// We need a runtime ID as otherwise the registry will not allow us to
 	// register nodes for roles which require runtimes.
 	var runtimeID, runtimeEWID common.Namespace
@@ -84,6 +90,9 @@ func testRegistryEntityNodes( // nolint: gocyclo
 	t.Run("EntityRegistration", func(t *testing.T) {
 		require := require.New(t)
 
+		fromBlk, grr := consensus.GetBlock(ctx, consensusAPI.HeightLatest)
+		require.NoError(grr, "GetBlock")
+
 		for _, v := range entities {
 			// First try registering invalid cases and make sure they fail.
 			for _, inv := range v.invalidBefore {
@@ -119,6 +128,42 @@ func testRegistryEntityNodes( // nolint: gocyclo
 			}
 		}
 
+		// Make sure that GetEventsRange returns all registration events, one per page.
+		toBlk, grr := consensus.GetBlock(ctx, consensusAPI.HeightLatest)
+		require.NoError(grr, "GetBlock")
+		query := &api.EventsRangeQuery{
+			FromHeight: fromBlk.Height + 1,
+			ToHeight:   toBlk.Height,
+			Kinds:      api.EventKindEntity,
+			Limit:      1,
+		}
+		var rangeEvents []*api.Event
+		for {
+			page, qerr := backend.GetEventsRange(ctx, query)
+			require.NoError(qerr, "GetEventsRange")
+			require.True(len(page.Events) <= 1, "GetEventsRange should respect the limit")
+			rangeEvents = append(rangeEvents, page.Events...)
+			if page.Next == nil {
+				break
+			}
+			query.Cursor = page.Next
+		}
+		require.Len(rangeEvents, len(entities), "GetEventsRange should return all entity registration events")
+		for i, v := range entities {
+			require.NotNil(rangeEvents[i].EntityEvent, "GetEventsRange should only return entity events")
+			require.EqualValues(v.Entity.ID, rangeEvents[i].EntityEvent.Entity.ID, "GetEventsRange should return events in order")
+		}
+
+		// Filtering by entity should only return events for the given entity.
+		page, grr := backend.GetEventsRange(ctx, &api.EventsRangeQuery{
+			FromHeight: fromBlk.Height + 1,
+			ToHeight:   toBlk.Height,
+			EntityID:   &entities[0].Entity.ID,
+		})
+		require.NoError(grr, "GetEventsRange")
+		require.Len(page.Events, 1, "GetEventsRange should return events for the given entity")
+		require.Nil(page.Next, "GetEventsRange should not return a cursor for the last page")
+
 		for _, v := range entities {
 			var ent *entity.Entity
 			ent, err = backend.GetEntity(ctx, &api.IDQuery{ID: v.Entity.ID, Height: consensusAPI.HeightLatest})
@@ -188,6 +233,14 @@ func testRegistryEntityNodes( // nolint: gocyclo
 					require.Error(err, v.descr)
 				}
 
+				var validation *api.NodeValidationResult
+				validation, err = backend.ValidateNode(ctx, &api.ValidateNodeQuery{
+					Height: consensusAPI.HeightLatest,
+					Node:   tn.SignedRegistration,
+				})
+				require.NoError(err, "ValidateNode")
+				require.True(validation.IsValid(), "ValidateNode should not report violations for a valid node")
+
 				err = tn.Register(consensus, tn.SignedRegistration)
 				require.NoError(err, "RegisterNode")
 
@@ -234,7 +287,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 				for _, v := range tn.invalidAfter {
 					err = tn.Register(consensus, v.signed)
 					require.Error(err, v.descr)
-					require.Equal(err, api.ErrInvalidArgument)
+					require.True(errors.Is(err, api.ErrInvalidArgument), "error should be api.ErrInvalidArgument")
 				}
 
 				err = tn.Register(consensus, tn.SignedValidReRegistration)
@@ -326,7 +379,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		tx = api.NewUnfreezeNodeTx(0, nil, &unfreeze)
 		err = consensusAPI.SignAndSubmitTx(ctx, consensus, entity.Signer, tx)
 		require.Error(err, "UnfreezeNode (with invalid node)")
-		require.Equal(err, api.ErrNoSuchNode)
+		require.True(errors.Is(err, api.ErrNoSuchNode), "error should be api.ErrNoSuchNode")
 
 		// Try to unfreeze a node using the node signing key (should fail
 		// as unfreeze must be signed by entity signing key).
@@ -335,7 +388,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		})
 		err = consensusAPI.SignAndSubmitTx(ctx, consensus, node.Signer, tx)
 		require.Error(err, "UnfreezeNode (with invalid signer)")
-		require.Equal(err, api.ErrBadEntityForNode)
+		require.True(errors.Is(err, api.ErrBadEntityForNode), "error should be api.ErrBadEntityForNode")
 	})
 
 	t.Run("NodeExpiration", func(t *testing.T) {
@@ -396,7 +449,21 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		// Ensure that registering an expired node will fail.
 		err = expiredNode.Register(consensus, expiredNode.SignedRegistration)
 		require.Error(err, "RegisterNode with expired node")
-		require.Equal(err, api.ErrNodeExpired)
+		require.True(errors.Is(err, api.ErrNodeExpired), "error should be api.ErrNodeExpired")
+
+		var validation *api.NodeValidationResult
+		validation, err = backend.ValidateNode(ctx, &api.ValidateNodeQuery{
+			Height: consensusAPI.HeightLatest,
+			Node:   expiredNode.SignedRegistration,
+		})
+		require.NoError(err, "ValidateNode")
+		var gotExpired bool
+		for _, v := range validation.Violations {
+			if v.Check == api.NodeCheckExpiration {
+				gotExpired = true
+			}
+		}
+		require.True(gotExpired, "ValidateNode should report expired node")
 	})
 
 	t.Run("EntityDeregistration", func(t *testing.T) {
@@ -409,7 +476,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		for _, v := range entities {
 			err := v.Deregister(consensus)
 			require.Error(err, "DeregisterEntity")
-			require.Equal(err, api.ErrEntityHasNodes)
+			require.True(errors.Is(err, api.ErrEntityHasNodes), "error should be api.ErrEntityHasNodes")
 		}
 
 		// Advance the epoch to trigger 0th entity nodes to be removed.
@@ -447,7 +514,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		for _, v := range entities[1:] {
 			err := v.Deregister(consensus)
 			require.Error(err, "DeregisterEntity")
-			require.Equal(err, api.ErrEntityHasNodes)
+			require.True(errors.Is(err, api.ErrEntityHasNodes), "error should be api.ErrEntityHasNodes")
 		}
 
 		// Advance the epoch to trigger all nodes to expire and be removed.
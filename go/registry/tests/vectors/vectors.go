@@ -0,0 +1,156 @@
+// Package vectors implements the test vector generator for the registry
+// transactions.
+package vectors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+// Generator is the registry transaction test vector generator.
+var Generator = &testvectors.Generator{
+	Name:         registry.ModuleName,
+	ChainContext: "registry test vectors",
+	Generate:     Generate,
+}
+
+// Generate generates test vectors for the registry transactions.
+func Generate() []testvectors.TestVector {
+	var vectors []testvectors.TestVector
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("oasis-core registry test vectors: runtime"), 0)
+
+	// Generate different gas fees.
+	for _, fee := range testvectors.Fees {
+		// Generate different nonces.
+		for _, nonce := range testvectors.Nonces {
+			// Valid register entity transactions.
+			entitySigner := memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterEntity signer")
+			for _, numNodes := range []int{0, 1, 2, 5} {
+				ent := entity.Entity{
+					Versioned: cbor.NewVersioned(entity.LatestEntityDescriptorVersion),
+					ID:        entitySigner.Public(),
+				}
+				for i := 0; i < numNodes; i++ {
+					nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("oasis core registry test vectors: node signer %d", i))
+					ent.Nodes = append(ent.Nodes, nodeSigner.Public())
+				}
+				sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+				if err != nil {
+					panic(err)
+				}
+				tx := registry.NewRegisterEntityTx(nonce, fee, sigEnt)
+				vectors = append(vectors, testvectors.MakeTestVectorWithSigner("RegisterEntity", tx, entitySigner))
+			}
+
+			// Valid unfreeze node transactions.
+			nodeSigner := memorySigner.NewTestSigner("oasis-core registry test vectors: UnfreezeNode signer")
+			tx := registry.NewUnfreezeNodeTx(nonce, fee, &registry.UnfreezeNode{
+				NodeID: nodeSigner.Public(),
+			})
+			vectors = append(vectors, testvectors.MakeTestVector("UnfreezeNode", tx))
+
+			// Valid suspend and resume node transactions.
+			tx = registry.NewSuspendNodeTx(nonce, fee, &registry.SuspendNode{
+				NodeID: nodeSigner.Public(),
+			})
+			vectors = append(vectors, testvectors.MakeTestVector("SuspendNode", tx))
+			tx = registry.NewResumeNodeTx(nonce, fee, &registry.ResumeNode{
+				NodeID: nodeSigner.Public(),
+			})
+			vectors = append(vectors, testvectors.MakeTestVector("ResumeNode", tx))
+
+			// Valid register node transactions.
+			nodeIdentitySigners := []signature.Signer{
+				memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterNode signer"),
+				memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterNode P2P signer"),
+				memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterNode consensus signer"),
+				memorySigner.NewTestSigner("oasis-core registry test vectors: RegisterNode TLS signer"),
+			}
+			for _, roles := range []node.RolesMask{
+				node.RoleValidator,
+				node.RoleComputeWorker,
+				node.RoleStorageWorker,
+				node.RoleComputeWorker | node.RoleStorageWorker,
+			} {
+				for _, expiration := range []uint64{1, 10, 1000} {
+					n := node.Node{
+						Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+						ID:         nodeIdentitySigners[0].Public(),
+						EntityID:   entitySigner.Public(),
+						Expiration: expiration,
+						TLS: node.TLSInfo{
+							PubKey: nodeIdentitySigners[3].Public(),
+						},
+						P2P: node.P2PInfo{
+							ID: nodeIdentitySigners[1].Public(),
+						},
+						Consensus: node.ConsensusInfo{
+							ID: nodeIdentitySigners[2].Public(),
+						},
+						Roles: roles,
+					}
+					if roles != node.RoleValidator {
+						n.Runtimes = []*node.Runtime{
+							{ID: runtimeID},
+						}
+					}
+					sigNode, err := node.MultiSignNode(nodeIdentitySigners, registry.RegisterNodeSignatureContext, &n)
+					if err != nil {
+						panic(err)
+					}
+					tx := registry.NewRegisterNodeTx(nonce, fee, sigNode)
+					vectors = append(vectors, testvectors.MakeTestVectorWithSigner("RegisterNode", tx, nodeIdentitySigners[0]))
+				}
+			}
+
+			// Valid register runtime transactions.
+			for _, groupSize := range []uint64{1, 2, 5} {
+				rt := registry.Runtime{
+					Versioned: cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+					ID:        runtimeID,
+					EntityID:  entitySigner.Public(),
+					Kind:      registry.KindCompute,
+					Executor: registry.ExecutorParameters{
+						GroupSize:       groupSize,
+						GroupBackupSize: groupSize,
+						RoundTimeout:    20,
+					},
+					TxnScheduler: registry.TxnSchedulerParameters{
+						Algorithm:         registry.TxnSchedulerSimple,
+						MaxBatchSize:      1,
+						MaxBatchSizeBytes: 1024,
+						BatchFlushTimeout: 20 * time.Second,
+						ProposerTimeout:   20,
+					},
+					Storage: registry.StorageParameters{
+						GroupSize:               groupSize,
+						MinWriteReplication:     groupSize,
+						MaxApplyWriteLogEntries: 100_000,
+						MaxApplyOps:             2,
+					},
+					AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+						AnyNode: &registry.AnyNodeRuntimeAdmissionPolicy{},
+					},
+				}
+				sigRt, err := registry.SignRuntime(entitySigner, registry.RegisterRuntimeSignatureContext, &rt)
+				if err != nil {
+					panic(err)
+				}
+				tx := registry.NewRegisterRuntimeTx(nonce, fee, sigRt)
+				vectors = append(vectors, testvectors.MakeTestVectorWithSigner("RegisterRuntime", tx, entitySigner))
+			}
+		}
+	}
+
+	return vectors
+}
@@ -8,13 +8,17 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 const (
@@ -54,16 +58,49 @@ var (
 	// ErrProposerTimeoutNotAllowed is the error returned when proposer timeout is not allowed.
 	ErrProposerTimeoutNotAllowed = errors.New(ModuleName, 6, "roothash: proposer timeout not allowed")
 
+	// ErrTooManyCommitments is the error returned when accepting the submitted executor
+	// commitments would exceed the maximum number of outstanding commitments for a runtime.
+	ErrTooManyCommitments = errors.New(ModuleName, 7, "roothash: too many outstanding commitments")
+
+	// ErrCommitmentTooLarge is the error returned when a submitted executor commitment exceeds
+	// the maximum commitment size.
+	ErrCommitmentTooLarge = errors.New(ModuleName, 8, "roothash: commitment too large")
+
+	// ErrInvalidEvidence is the error returned when the submitted evidence is invalid.
+	ErrInvalidEvidence = errors.New(ModuleName, 9, "roothash: invalid evidence")
+
+	// ErrDuplicateEvidence is the error returned when the submitted evidence has already
+	// been processed.
+	ErrDuplicateEvidence = errors.New(ModuleName, 10, "roothash: duplicate evidence")
+
+	// ErrEvidenceExpired is the error returned when the submitted evidence is too old.
+	ErrEvidenceExpired = errors.New(ModuleName, 11, "roothash: evidence expired")
+
+	// ErrRuntimeResetNotAllowed is the error returned when a runtime reset is not allowed.
+	ErrRuntimeResetNotAllowed = errors.New(ModuleName, 12, "roothash: runtime reset not allowed")
+
+	// ErrRoundSuspended is the error returned when the current round is suspended due to not
+	// enough executor committee members being live.
+	ErrRoundSuspended = errors.New(ModuleName, 13, "roothash: round suspended due to insufficient committee liveness")
+
 	// MethodExecutorCommit is the method name for executor commit submission.
 	MethodExecutorCommit = transaction.NewMethodName(ModuleName, "ExecutorCommit", ExecutorCommit{})
 
 	// MethodExecutorProposerTimeout is the method name for executor.
 	MethodExecutorProposerTimeout = transaction.NewMethodName(ModuleName, "ExecutorProposerTimeout", ExecutorProposerTimeoutRequest{})
 
+	// MethodEvidence is the method name for submitting evidence of node misbehavior.
+	MethodEvidence = transaction.NewMethodName(ModuleName, "Evidence", Evidence{})
+
+	// MethodRuntimeReset is the method name for resetting a runtime's round state.
+	MethodRuntimeReset = transaction.NewMethodName(ModuleName, "RuntimeReset", RuntimeReset{})
+
 	// Methods is a list of all methods supported by the roothash backend.
 	Methods = []transaction.MethodName{
 		MethodExecutorCommit,
 		MethodExecutorProposerTimeout,
+		MethodEvidence,
+		MethodRuntimeReset,
 	}
 )
 
@@ -98,6 +135,27 @@ type Backend interface {
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]*Event, error)
 
+	// GetStragglerCounters returns the number of rounds in the given epoch in which each
+	// of the runtime's executor committee members failed to submit a commitment in time.
+	GetStragglerCounters(ctx context.Context, query *StragglerCountersQuery) (map[signature.PublicKey]uint64, error)
+
+	// GetLivenessSummary returns the runtime's liveness summary (number of finalized, failed,
+	// timed out and discrepancy rounds) for the given epoch.
+	GetLivenessSummary(ctx context.Context, query *LivenessSummaryQuery) (*LivenessSummary, error)
+
+	// GetExecutorParticipation returns the participation statistics (number of submitted
+	// commitments, proposed rounds and failures) of each of the runtime's executor nodes for
+	// the given epoch.
+	GetExecutorParticipation(
+		ctx context.Context,
+		query *ExecutorParticipationQuery,
+	) (map[signature.PublicKey]*ExecutorParticipation, error)
+
+	// GetStakingEventSubscription returns the runtime's subscription to staking events
+	// affecting its runtime account, including the range of consensus heights of events that
+	// should be delivered to the runtime when processing the round following the latest block.
+	GetStakingEventSubscription(ctx context.Context, request *RuntimeRequest) (*StakingEventSubscription, error)
+
 	// Cleanup cleans up the roothash backend.
 	Cleanup()
 }
@@ -130,6 +188,90 @@ func NewRequestProposerTimeoutTx(nonce uint64, fee *transaction.Fee, runtimeID c
 	})
 }
 
+// RuntimeReset is the argument set for the RuntimeReset method.
+//
+// A runtime reset archives the runtime's current round state and reinitializes it from a new
+// genesis block with the given state root. The new genesis block directly follows the runtime's
+// current block so that runtime rounds remain contiguous. It is only allowed on networks where the
+// DebugAllowRuntimeReset consensus parameter is set and must be submitted by the entity owning
+// the runtime.
+type RuntimeReset struct {
+	// ID is the runtime identifier.
+	ID common.Namespace `json:"id"`
+	// StateRoot is the state root of the new genesis block.
+	StateRoot hash.Hash `json:"state_root"`
+}
+
+// NewRuntimeResetTx creates a new runtime reset transaction.
+func NewRuntimeResetTx(nonce uint64, fee *transaction.Fee, reset *RuntimeReset) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodRuntimeReset, reset)
+}
+
+// StragglerCountersQuery is a straggler counters query.
+type StragglerCountersQuery struct {
+	RuntimeID common.Namespace    `json:"runtime_id"`
+	Epoch     epochtime.EpochTime `json:"epoch"`
+	Height    int64               `json:"height"`
+}
+
+// LivenessSummaryQuery is a runtime liveness summary query.
+type LivenessSummaryQuery struct {
+	RuntimeID common.Namespace    `json:"runtime_id"`
+	Epoch     epochtime.EpochTime `json:"epoch"`
+	Height    int64               `json:"height"`
+}
+
+// LivenessSummary is the per-epoch summary of a runtime's round processing.
+type LivenessSummary struct {
+	// FinalizedRounds is the number of rounds that were successfully finalized.
+	FinalizedRounds uint64 `json:"finalized_rounds,omitempty"`
+	// FailedRounds is the number of rounds that failed.
+	FailedRounds uint64 `json:"failed_rounds,omitempty"`
+	// Timeouts is the number of rounds in which the round timeout expired.
+	Timeouts uint64 `json:"timeouts,omitempty"`
+	// Discrepancies is the number of rounds in which an execution discrepancy was detected.
+	Discrepancies uint64 `json:"discrepancies,omitempty"`
+}
+
+// ExecutorParticipationQuery is an executor participation statistics query.
+type ExecutorParticipationQuery struct {
+	RuntimeID common.Namespace    `json:"runtime_id"`
+	Epoch     epochtime.EpochTime `json:"epoch"`
+	Height    int64               `json:"height"`
+}
+
+// ExecutorParticipation is the per-epoch participation of an executor node in a runtime's rounds.
+type ExecutorParticipation struct {
+	// Commits is the number of executor commitments submitted by the node.
+	Commits uint64 `json:"commits,omitempty"`
+	// Proposals is the number of finalized rounds in which the node was the transaction
+	// scheduler.
+	Proposals uint64 `json:"proposals,omitempty"`
+	// Failures is the number of rounds in which the node failed to submit a commitment in time.
+	Failures uint64 `json:"failures,omitempty"`
+}
+
+// RuntimeRequest is a generic roothash get request for a specific runtime.
+type RuntimeRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Height    int64            `json:"height"`
+}
+
+// StakingEventSubscription is a runtime's subscription to staking events affecting its
+// runtime account.
+type StakingEventSubscription struct {
+	// Subscribed is true iff the runtime is subscribed to staking events.
+	Subscribed bool `json:"subscribed"`
+	// Address is the runtime account address.
+	Address staking.Address `json:"address"`
+	// FromHeight is the (exclusive) consensus height from which on the staking events
+	// should be delivered.
+	FromHeight int64 `json:"from_height,omitempty"`
+	// ToHeight is the (inclusive) consensus height up to which the staking events should
+	// be delivered.
+	ToHeight int64 `json:"to_height,omitempty"`
+}
+
 // AnnotatedBlock is an annotated roothash block.
 type AnnotatedBlock struct {
 	// Height is the underlying roothash backend's block height that
@@ -152,6 +294,62 @@ type ExecutionDiscrepancyDetectedEvent struct {
 	Timeout bool `json:"timeout"`
 }
 
+// ExecutorStragglersEvent is an event emitted when a round times out before all of the
+// required executor committee members submitted their commitments.
+//
+// Only the public keys of the elected committee members that failed to submit a
+// commitment in time are included.
+type ExecutorStragglersEvent struct {
+	// Round is the round in which the stragglers were detected.
+	Round uint64 `json:"round"`
+	// Discrepancy signals whether the round was in discrepancy resolution mode in which
+	// case the stragglers are backup workers.
+	Discrepancy bool `json:"discrepancy,omitempty"`
+	// Nodes are the public keys of the committee members that failed to submit a
+	// commitment in time.
+	Nodes []signature.PublicKey `json:"nodes"`
+}
+
+// LivenessSummaryEvent is an event emitted at the end of each epoch with the runtime's
+// liveness summary for the epoch.
+type LivenessSummaryEvent struct {
+	// Epoch is the epoch the summary is for.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Summary is the runtime's liveness summary for the epoch.
+	Summary LivenessSummary `json:"summary"`
+}
+
+// ExecutorParticipationEvent is an event emitted at the end of each epoch with the
+// participation statistics of the runtime's executor nodes for the epoch.
+//
+// Only nodes that participated in (or failed to participate in) at least one round are
+// included.
+type ExecutorParticipationEvent struct {
+	// Epoch is the epoch the statistics are for.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Nodes are the participation statistics of the executor nodes.
+	Nodes map[signature.PublicKey]ExecutorParticipation `json:"nodes"`
+}
+
+// RuntimeResetEvent is an event emitted when a runtime's round state is reset.
+type RuntimeResetEvent struct {
+	// ArchivedRound is the round of the runtime's current block at the time of the reset.
+	ArchivedRound uint64 `json:"archived_round"`
+	// Round is the round of the new genesis block.
+	Round uint64 `json:"round"`
+}
+
+// RoundSuspendedEvent is an event emitted when a round is suspended because not enough
+// executor committee members are registered and not frozen at the start of the round.
+type RoundSuspendedEvent struct {
+	// Round is the suspended round.
+	Round uint64 `json:"round"`
+	// LiveMembers is the number of executor committee members that are registered and not frozen.
+	LiveMembers uint64 `json:"live_members"`
+	// CommitteeSize is the total number of executor committee members.
+	CommitteeSize uint64 `json:"committee_size"`
+}
+
 // FinalizedEvent is a finalized event.
 type FinalizedEvent struct {
 	Round uint64 `json:"round"`
@@ -159,13 +357,19 @@ type FinalizedEvent struct {
 
 // Event is a roothash event.
 type Event struct {
-	Height int64     `json:"height,omitempty"`
-	TxHash hash.Hash `json:"tx_hash,omitempty"`
+	Height int64        `json:"height,omitempty"`
+	TxHash hash.Hash    `json:"tx_hash,omitempty"`
+	Index  events.Index `json:"index"`
 
 	RuntimeID common.Namespace `json:"runtime_id"`
 
 	ExecutorCommitted            *ExecutorCommittedEvent            `json:"executor_committed,omitempty"`
 	ExecutionDiscrepancyDetected *ExecutionDiscrepancyDetectedEvent `json:"execution_discrepancy,omitempty"`
+	ExecutorStragglers           *ExecutorStragglersEvent           `json:"executor_stragglers,omitempty"`
+	LivenessSummary              *LivenessSummaryEvent              `json:"liveness_summary,omitempty"`
+	ExecutorParticipation        *ExecutorParticipationEvent        `json:"executor_participation,omitempty"`
+	RuntimeReset                 *RuntimeResetEvent                 `json:"runtime_reset,omitempty"`
+	RoundSuspended               *RoundSuspendedEvent               `json:"round_suspended,omitempty"`
 	FinalizedEvent               *FinalizedEvent                    `json:"finalized,omitempty"`
 }
 
@@ -193,6 +397,23 @@ type ConsensusParameters struct {
 	// GasCosts are the roothash transaction gas costs.
 	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
 
+	// MaxExecutorCommitments is the maximum number of executor commitments that can be
+	// outstanding in the executor pool of a runtime at any given time. Zero means no limit.
+	MaxExecutorCommitments uint64 `json:"max_executor_commitments,omitempty"`
+
+	// MaxExecutorCommitmentSize is the maximum size (in bytes) of a single serialized executor
+	// commitment. Zero means no limit.
+	MaxExecutorCommitmentSize uint64 `json:"max_executor_commitment_size,omitempty"`
+
+	// MaxEvidenceAge is the maximum age (in runtime rounds) of submitted evidence of node
+	// misbehavior. Zero means that evidence submission is disabled.
+	MaxEvidenceAge uint64 `json:"max_evidence_age,omitempty"`
+
+	// DebugAllowRuntimeReset is true iff runtime owners are allowed to reset the round state
+	// of their runtimes via the RuntimeReset method. This should only be enabled on test
+	// networks.
+	DebugAllowRuntimeReset bool `json:"debug_allow_runtime_reset,omitempty"`
+
 	// DebugDoNotSuspendRuntimes is true iff runtimes should not be suspended
 	// for lack of paying maintenance fees.
 	DebugDoNotSuspendRuntimes bool `json:"debug_do_not_suspend_runtimes,omitempty"`
@@ -208,6 +429,12 @@ const (
 
 	// GasOpProposerTimeout is the gas operation identifier for executor propose timeout cost.
 	GasOpProposerTimeout transaction.Op = "proposer_timeout"
+
+	// GasOpEvidence is the gas operation identifier for evidence submission transaction cost.
+	GasOpEvidence transaction.Op = "evidence"
+
+	// GasOpRuntimeReset is the gas operation identifier for runtime reset transaction cost.
+	GasOpRuntimeReset transaction.Op = "runtime_reset"
 )
 
 // XXX: Define reasonable default gas costs.
@@ -216,6 +443,8 @@ const (
 var DefaultGasCosts = transaction.Costs{
 	GasOpComputeCommit:   1000,
 	GasOpProposerTimeout: 1000,
+	GasOpEvidence:        1000,
+	GasOpRuntimeReset:    1000,
 }
 
 // SanityCheckBlocks examines the blocks table.
@@ -232,7 +461,7 @@ func SanityCheckBlocks(blocks map[common.Namespace]*block.Block) error {
 
 // SanityCheck does basic sanity checking on the genesis state.
 func (g *Genesis) SanityCheck() error {
-	unsafeFlags := g.Parameters.DebugDoNotSuspendRuntimes || g.Parameters.DebugBypassStake
+	unsafeFlags := g.Parameters.DebugDoNotSuspendRuntimes || g.Parameters.DebugBypassStake || g.Parameters.DebugAllowRuntimeReset
 	if unsafeFlags && !flags.DebugDontBlameOasis() {
 		return fmt.Errorf("roothash: sanity check failed: one or more unsafe debug flags set")
 	}
@@ -1,6 +1,32 @@
 package block
 
+import "fmt"
+
 // Message is a roothash message that can be sent by a runtime.
+//
+// Exactly one of the message fields must be set.
 type Message struct {
-	// No valid messages are currently defined.
+	// StakingSubscribe is a message that changes the runtime's subscription to
+	// staking events affecting its runtime account.
+	StakingSubscribe *StakingSubscribeMessage `json:"staking_subscribe,omitempty"`
+}
+
+// ValidateBasic performs basic validation of the runtime message.
+func (m *Message) ValidateBasic() error {
+	switch {
+	case m.StakingSubscribe != nil:
+		return nil
+	default:
+		return fmt.Errorf("runtime message has no fields set")
+	}
+}
+
+// StakingSubscribeMessage is a runtime message that subscribes the runtime to (or
+// unsubscribes it from) staking events affecting its runtime account.
+//
+// While subscribed, all staking events affecting the runtime account are delivered to the
+// runtime as part of the inputs for processing the next round.
+type StakingSubscribeMessage struct {
+	// Subscribe specifies whether the runtime should be subscribed or unsubscribed.
+	Subscribe bool `json:"subscribe"`
 }
@@ -0,0 +1,47 @@
+package block
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+func TestMessageValidateBasic(t *testing.T) {
+	require := require.New(t)
+
+	var msg Message
+	require.Error(msg.ValidateBasic(), "message without fields should be invalid")
+
+	msg.StakingSubscribe = &StakingSubscribeMessage{Subscribe: true}
+	require.NoError(msg.ValidateBasic(), "staking subscribe message should be valid")
+}
+
+func TestMessageEncoding(t *testing.T) {
+	require := require.New(t)
+
+	// NOTE: These encodings MUST be synced with runtime/src/common/roothash.rs.
+	for _, tc := range []struct {
+		msg      Message
+		expected string
+	}{
+		{
+			Message{StakingSubscribe: &StakingSubscribeMessage{Subscribe: true}},
+			"a1717374616b696e675f737562736372696265a169737562736372696265f5",
+		},
+		{
+			Message{StakingSubscribe: &StakingSubscribeMessage{Subscribe: false}},
+			"a1717374616b696e675f737562736372696265a169737562736372696265f4",
+		},
+	} {
+		enc := cbor.Marshal(tc.msg)
+		require.Equal(tc.expected, hex.EncodeToString(enc), "message should encode the same")
+
+		var dec Message
+		err := cbor.Unmarshal(enc, &dec)
+		require.NoError(err, "Unmarshal")
+		require.Equal(tc.msg, dec, "message should decode the same")
+	}
+}
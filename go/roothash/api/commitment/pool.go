@@ -2,6 +2,7 @@ package commitment
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -19,23 +20,25 @@ import (
 const moduleName = "roothash/commitment"
 
 var (
-	ErrNoRuntime              = errors.New(moduleName, 1, "roothash/commitment: no runtime configured")
-	ErrNoCommittee            = errors.New(moduleName, 2, "roothash/commitment: no committee configured")
-	ErrInvalidCommitteeKind   = errors.New(moduleName, 3, "roothash/commitment: invalid committee kind")
-	ErrRakSigInvalid          = errors.New(moduleName, 4, "roothash/commitment: batch RAK signature invalid")
-	ErrNotInCommittee         = errors.New(moduleName, 5, "roothash/commitment: node not part of committee")
-	ErrAlreadyCommitted       = errors.New(moduleName, 6, "roothash/commitment: node already sent commitment")
-	ErrNotBasedOnCorrectBlock = errors.New(moduleName, 7, "roothash/commitment: submitted commitment is not based on correct block")
-	ErrDiscrepancyDetected    = errors.New(moduleName, 8, "roothash/commitment: discrepancy detected")
-	ErrStillWaiting           = errors.New(moduleName, 9, "roothash/commitment: still waiting for commits")
-	ErrInsufficientVotes      = errors.New(moduleName, 10, "roothash/commitment: insufficient votes to finalize discrepancy resolution round")
-	ErrBadExecutorCommitment  = errors.New(moduleName, 11, "roothash/commitment: bad executor commitment")
-	ErrTxnSchedSigInvalid     = p2pError.Permanent(errors.New(moduleName, 12, "roothash/commitment: txn scheduler signature invalid"))
-	ErrInvalidMessages        = p2pError.Permanent(errors.New(moduleName, 13, "roothash/commitment: invalid messages"))
-	ErrBadStorageReceipts     = errors.New(moduleName, 14, "roothash/commitment: bad storage receipts")
-	ErrTimeoutNotCorrectRound = errors.New(moduleName, 15, "roothash/commitment: timeout not for correct round")
-	ErrNodeIsScheduler        = errors.New(moduleName, 16, "roothash/commitment: node is scheduler")
-	ErrMajorityFailure        = errors.New(moduleName, 17, "roothash/commitment: majority commitments indicated failure")
+	ErrNoRuntime                     = errors.New(moduleName, 1, "roothash/commitment: no runtime configured")
+	ErrNoCommittee                   = errors.New(moduleName, 2, "roothash/commitment: no committee configured")
+	ErrInvalidCommitteeKind          = errors.New(moduleName, 3, "roothash/commitment: invalid committee kind")
+	ErrRakSigInvalid                 = errors.New(moduleName, 4, "roothash/commitment: batch RAK signature invalid")
+	ErrNotInCommittee                = errors.New(moduleName, 5, "roothash/commitment: node not part of committee")
+	ErrAlreadyCommitted              = errors.New(moduleName, 6, "roothash/commitment: node already sent commitment")
+	ErrNotBasedOnCorrectBlock        = errors.New(moduleName, 7, "roothash/commitment: submitted commitment is not based on correct block")
+	ErrDiscrepancyDetected           = errors.New(moduleName, 8, "roothash/commitment: discrepancy detected")
+	ErrStillWaiting                  = errors.New(moduleName, 9, "roothash/commitment: still waiting for commits")
+	ErrInsufficientVotes             = errors.New(moduleName, 10, "roothash/commitment: insufficient votes to finalize discrepancy resolution round")
+	ErrBadExecutorCommitment         = errors.New(moduleName, 11, "roothash/commitment: bad executor commitment")
+	ErrTxnSchedSigInvalid            = p2pError.Permanent(errors.New(moduleName, 12, "roothash/commitment: txn scheduler signature invalid"))
+	ErrInvalidMessages               = p2pError.Permanent(errors.New(moduleName, 13, "roothash/commitment: invalid messages"))
+	ErrBadStorageReceipts            = errors.New(moduleName, 14, "roothash/commitment: bad storage receipts")
+	ErrTimeoutNotCorrectRound        = errors.New(moduleName, 15, "roothash/commitment: timeout not for correct round")
+	ErrNodeIsScheduler               = errors.New(moduleName, 16, "roothash/commitment: node is scheduler")
+	ErrMajorityFailure               = errors.New(moduleName, 17, "roothash/commitment: majority commitments indicated failure")
+	ErrBadStorageReceiptSigner       = errors.New(moduleName, 18, "roothash/commitment: storage receipt not signed by a current storage committee member")
+	ErrDuplicateStorageReceiptSigner = errors.New(moduleName, 19, "roothash/commitment: duplicate storage receipt signer")
 )
 
 const (
@@ -214,10 +217,18 @@ func (p *Pool) addOpenExecutorCommitment(
 		return ErrNoRuntime
 	}
 
-	// Make sure the commitment does not contain any messages as these are currently
-	// not supported and should be rejected.
-	if len(header.Messages) > 0 {
-		return ErrInvalidMessages
+	// Make sure that all of the messages in the commitment are well-formed.
+	for _, msg := range header.Messages {
+		if msg == nil {
+			return ErrInvalidMessages
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			logger.Debug("executor commitment contains invalid messages",
+				"node_id", id,
+				"err", err,
+			)
+			return ErrInvalidMessages
+		}
 	}
 
 	// Check if the block is based on the previous block.
@@ -238,6 +249,17 @@ func (p *Pool) addOpenExecutorCommitment(
 		return ErrBadExecutorCommitment
 	}
 
+	// Verify the input storage receipt signers. The quorum for input receipts is enforced by the
+	// executor nodes when accepting the batch, here we only make sure that the receipts were
+	// signed by distinct members of the current storage committee.
+	if err := verifyStorageReceiptSigners(sv, body.InputStorageSigs, 0); err != nil {
+		logger.Debug("executor commitment has bad input storage receipts",
+			"node_id", id,
+			"err", err,
+		)
+		return err
+	}
+
 	if err := sv.VerifyTxnSchedulerSignature(body.TxnSchedSig, blk.Header.Round); err != nil {
 		logger.Debug("executor commitment has bad transaction scheduler signer",
 			"node_id", id,
@@ -287,17 +309,11 @@ func (p *Pool) addOpenExecutorCommitment(
 		}
 
 		// Check if the header refers to merkle roots in storage.
-		if uint64(len(body.StorageSignatures)) < p.Runtime.Storage.MinWriteReplication {
-			logger.Debug("executor commitment doesn't have enough storage receipts",
+		if err := verifyStorageReceiptSigners(sv, body.StorageSignatures, p.Runtime.Storage.MinWriteReplication); err != nil {
+			logger.Debug("executor commitment has bad storage receipt signers",
 				"node_id", id,
 				"min_write_replication", p.Runtime.Storage.MinWriteReplication,
 				"num_receipts", len(body.StorageSignatures),
-			)
-			return ErrBadStorageReceipts
-		}
-		if err := sv.VerifyCommitteeSignatures(scheduler.KindStorage, body.StorageSignatures); err != nil {
-			logger.Debug("executor commitment has bad storage receipt signers",
-				"node_id", id,
 				"err", err,
 			)
 			return err
@@ -319,6 +335,25 @@ func (p *Pool) addOpenExecutorCommitment(
 	return nil
 }
 
+// verifyStorageReceiptSigners verifies that the given storage receipt signatures come from at
+// least minSigners distinct members of the current storage committee.
+func verifyStorageReceiptSigners(sv SignatureVerifier, sigs []signature.Signature, minSigners uint64) error {
+	signers := make(map[signature.PublicKey]bool, len(sigs))
+	for _, sig := range sigs {
+		if signers[sig.PublicKey] {
+			return ErrDuplicateStorageReceiptSigner
+		}
+		signers[sig.PublicKey] = true
+	}
+	if uint64(len(signers)) < minSigners {
+		return ErrBadStorageReceipts
+	}
+	if err := sv.VerifyCommitteeSignatures(scheduler.KindStorage, sigs); err != nil {
+		return fmt.Errorf("%w: %s", ErrBadStorageReceiptSigner, err)
+	}
+	return nil
+}
+
 // AddExecutorCommitment verifies and adds a new executor commitment to the pool.
 func (p *Pool) AddExecutorCommitment(
 	ctx context.Context,
@@ -381,6 +416,35 @@ func (p *Pool) CheckEnoughCommitments(didTimeout bool) error {
 	return nil
 }
 
+// GetStragglers returns the public keys of all committee members that are required to
+// submit a commitment in the current round but have not done so yet.
+//
+// In case a discrepancy has been detected, backup workers are considered, otherwise
+// only primary workers are considered.
+func (p *Pool) GetStragglers() []signature.PublicKey {
+	if p.Committee == nil {
+		return nil
+	}
+
+	var stragglers []signature.PublicKey
+	for _, n := range p.Committee.Members {
+		var check bool
+		if !p.Discrepancy {
+			check = n.Role == scheduler.RoleWorker
+		} else {
+			check = n.Role == scheduler.RoleBackupWorker
+		}
+		if !check {
+			continue
+		}
+
+		if _, ok := p.getCommitment(n.PublicKey); !ok {
+			stragglers = append(stragglers, n.PublicKey)
+		}
+	}
+	return stragglers
+}
+
 // CheckProposerTimeout verifies executor timeout request conditions.
 func (p *Pool) CheckProposerTimeout(
 	ctx context.Context,
@@ -64,6 +64,33 @@ func (n *staticSignatureVerifier) VerifyTxnSchedulerSignature(sig signature.Sign
 	return nil
 }
 
+// committeeSignatureVerifier is a signature verifier with a mutable storage committee.
+type committeeSignatureVerifier struct {
+	storageCommittee      map[signature.PublicKey]bool
+	txnSchedulerPublicKey signature.PublicKey
+}
+
+func (n *committeeSignatureVerifier) VerifyCommitteeSignatures(kind scheduler.CommitteeKind, sigs []signature.Signature) error {
+	if kind != scheduler.KindStorage {
+		return errors.New("unsupported committee kind")
+	}
+
+	for _, sig := range sigs {
+		if !n.storageCommittee[sig.PublicKey] {
+			return errors.New("unknown public key")
+		}
+	}
+	return nil
+}
+
+func (n *committeeSignatureVerifier) VerifyTxnSchedulerSignature(sig signature.Signature, round uint64) error {
+	if !sig.PublicKey.Equal(n.txnSchedulerPublicKey) {
+		return errors.New("unknown public key")
+	}
+
+	return nil
+}
+
 type staticNodeLookup struct {
 	runtime *node.Runtime
 }
@@ -176,6 +203,8 @@ func TestPoolSingleCommitment(t *testing.T) {
 		{"BlockBadRound", func(b *ComputeBody) { b.Header.Round-- }, ErrNotBasedOnCorrectBlock},
 		{"BlockBadPreviousHash", func(b *ComputeBody) { b.Header.PreviousHash.FromBytes([]byte("invalid")) }, ErrNotBasedOnCorrectBlock},
 		{"StorageSigs1", func(b *ComputeBody) { b.StorageSignatures = nil }, ErrBadStorageReceipts},
+		{"EmptyMessage", func(b *ComputeBody) { b.Header.Messages = []*block.Message{{}} }, ErrInvalidMessages},
+		{"NilMessage", func(b *ComputeBody) { b.Header.Messages = []*block.Message{nil} }, ErrInvalidMessages},
 		{"MissingIORootHash", func(b *ComputeBody) { b.Header.IORoot = nil }, ErrBadExecutorCommitment},
 		{"MissingStateRootHash", func(b *ComputeBody) { b.Header.StateRoot = nil }, ErrBadExecutorCommitment},
 		{"FailureIndicatingWithStorageSigs", func(b *ComputeBody) { b.Failure = FailureStorageUnavailable }, ErrBadExecutorCommitment},
@@ -266,6 +295,116 @@ func TestPoolSingleCommitment(t *testing.T) {
 	require.EqualValues(t, &body.Header, &header, "DD should return the same header")
 }
 
+func TestPoolStorageReceiptSigners(t *testing.T) {
+	genesisTestHelpers.SetTestChainContext()
+
+	// Generate a non-TEE runtime.
+	var rtID common.Namespace
+	_ = rtID.UnmarshalHex("0000000000000000000000000000000000000000000000000000000000000000")
+
+	rt := &registry.Runtime{
+		Versioned:   cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+		ID:          rtID,
+		Kind:        registry.KindCompute,
+		TEEHardware: node.TEEHardwareInvalid,
+		Storage: registry.StorageParameters{
+			GroupSize:           2,
+			MinWriteReplication: 2,
+		},
+	}
+
+	// Generate a commitment signing key.
+	sk, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(t, err, "NewSigner")
+
+	// Generate a committee.
+	committee := &scheduler.Committee{
+		Kind: scheduler.KindComputeExecutor,
+		Members: []*scheduler.CommitteeNode{
+			{
+				Role:      scheduler.RoleWorker,
+				PublicKey: sk.Public(),
+			},
+		},
+	}
+
+	// Create a pool.
+	pool := Pool{
+		Runtime:   rt,
+		Committee: committee,
+	}
+
+	// Generate a commitment with receipts from two storage nodes.
+	childBlk, parentBlk, body := generateComputeBody(t)
+	sig1 := body.StorageSignatures[0]
+	sig2 := generateStorageReceiptSignature(t, parentBlk, &body)
+	body.StorageSignatures = []signature.Signature{sig1, sig2}
+
+	sv := &committeeSignatureVerifier{
+		storageCommittee: map[signature.PublicKey]bool{
+			sig1.PublicKey: true,
+			sig2.PublicKey: true,
+		},
+		txnSchedulerPublicKey: body.TxnSchedSig.PublicKey,
+	}
+	nl := &staticNodeLookup{
+		runtime: &node.Runtime{
+			ID: rtID,
+		},
+	}
+
+	// Generate receipts from the next storage committee.
+	sig3 := generateStorageReceiptSignature(t, parentBlk, &body)
+	sig4 := generateStorageReceiptSignature(t, parentBlk, &body)
+
+	// Test invalid commitments.
+	for _, tc := range []struct {
+		name        string
+		fn          func(*ComputeBody)
+		expectedErr error
+	}{
+		{"DuplicateSigner", func(b *ComputeBody) { b.StorageSignatures = []signature.Signature{sig1, sig1} }, ErrDuplicateStorageReceiptSigner},
+		{"NotEnoughSigners", func(b *ComputeBody) { b.StorageSignatures = []signature.Signature{sig1} }, ErrBadStorageReceipts},
+		{"NonCommitteeSigner", func(b *ComputeBody) { b.StorageSignatures = []signature.Signature{sig1, sig3} }, ErrBadStorageReceiptSigner},
+		{"InputDuplicateSigner", func(b *ComputeBody) { b.InputStorageSigs = []signature.Signature{sig1, sig1} }, ErrDuplicateStorageReceiptSigner},
+		{"InputNonCommitteeSigner", func(b *ComputeBody) { b.InputStorageSigs = []signature.Signature{sig3} }, ErrBadStorageReceiptSigner},
+	} {
+		invalidBody := body
+		tc.fn(&invalidBody)
+
+		var commit *ExecutorCommitment
+		commit, err = SignExecutorCommitment(sk, &invalidBody)
+		require.NoError(t, err, "SignExecutorCommitment(%s)", tc.name)
+
+		err = pool.AddExecutorCommitment(context.Background(), childBlk, sv, nl, commit)
+		require.Error(t, err, "AddExecutorCommitment(%s)", tc.name)
+		require.True(t, errors.Is(err, tc.expectedErr), "AddExecutorCommitment(%s) should fail with %s (got %s)", tc.name, tc.expectedErr, err)
+	}
+
+	// Simulate a storage committee transition mid-round.
+	sv.storageCommittee = map[signature.PublicKey]bool{
+		sig3.PublicKey: true,
+		sig4.PublicKey: true,
+	}
+
+	// Receipts from the previous storage committee should no longer be accepted.
+	commit, err := SignExecutorCommitment(sk, &body)
+	require.NoError(t, err, "SignExecutorCommitment")
+	err = pool.AddExecutorCommitment(context.Background(), childBlk, sv, nl, commit)
+	require.Error(t, err, "AddExecutorCommitment")
+	require.True(t, errors.Is(err, ErrBadStorageReceiptSigner), "AddExecutorCommitment should fail with bad signer")
+
+	// Receipts from the current storage committee should be accepted.
+	body.StorageSignatures = []signature.Signature{sig3, sig4}
+	commit, err = SignExecutorCommitment(sk, &body)
+	require.NoError(t, err, "SignExecutorCommitment")
+	err = pool.AddExecutorCommitment(context.Background(), childBlk, sv, nl, commit)
+	require.NoError(t, err, "AddExecutorCommitment")
+
+	err = pool.CheckEnoughCommitments(false)
+	require.NoError(t, err, "CheckEnoughCommitments")
+}
+
 func TestPoolSingleCommitmentTEE(t *testing.T) {
 	genesisTestHelpers.SetTestChainContext()
 
@@ -813,6 +952,39 @@ func TestExecutorTimeoutRequest(t *testing.T) {
 	})
 }
 
+func TestGetStragglers(t *testing.T) {
+	genesisTestHelpers.SetTestChainContext()
+
+	require := require.New(t)
+	ctx := context.Background()
+
+	rt, sks, committee, nl := generateMockCommittee(t)
+	sk1 := sks[0]
+	sk2 := sks[1]
+	sk3 := sks[2]
+
+	// Create a pool.
+	pool := Pool{
+		Runtime: rt,
+	}
+	require.Empty(pool.GetStragglers(), "GetStragglers without committee")
+
+	pool.Committee = committee
+	require.EqualValues([]signature.PublicKey{sk1.Public(), sk2.Public()}, pool.GetStragglers(), "GetStragglers")
+
+	// Generate a commitment.
+	childBlk, _, body := generateComputeBody(t)
+	commit1, err := SignExecutorCommitment(sk1, &body)
+	require.NoError(err, "SignExecutorCommitment")
+	err = pool.AddExecutorCommitment(ctx, childBlk, nopSV, nl, commit1)
+	require.NoError(err, "AddExecutorCommitment")
+	require.EqualValues([]signature.PublicKey{sk2.Public()}, pool.GetStragglers(), "GetStragglers")
+
+	// In discrepancy resolution mode only backup workers should be considered.
+	pool.Discrepancy = true
+	require.EqualValues([]signature.PublicKey{sk3.Public()}, pool.GetStragglers(), "GetStragglers in discrepancy")
+}
+
 func generateMockCommittee(t *testing.T) (
 	rt *registry.Runtime,
 	sks []signature.Signer,
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+// Evidence is an evidence of node misbehavior.
+type Evidence struct {
+	// ID is the identifier of the runtime the evidence is for.
+	ID common.Namespace `json:"id"`
+
+	// EquivocationExecutor is the evidence of executor commitment equivocation.
+	EquivocationExecutor *EquivocationExecutorEvidence `json:"equivocation_executor,omitempty"`
+}
+
+// NewEvidenceTx creates a new evidence transaction.
+func NewEvidenceTx(nonce uint64, fee *transaction.Fee, evidence *Evidence) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodEvidence, evidence)
+}
+
+// ValidateBasic performs basic evidence validity checks.
+func (ev *Evidence) ValidateBasic() error {
+	switch {
+	case ev.EquivocationExecutor != nil:
+		return ev.EquivocationExecutor.ValidateBasic()
+	default:
+		return fmt.Errorf("roothash: evidence of unknown kind")
+	}
+}
+
+// EquivocationExecutorEvidence is evidence of a node signing two different
+// executor commitments for the same runtime round.
+type EquivocationExecutorEvidence struct {
+	CommitA commitment.ExecutorCommitment `json:"commit_a"`
+	CommitB commitment.ExecutorCommitment `json:"commit_b"`
+}
+
+// ValidateBasic performs stateless executor equivocation evidence validity
+// checks.
+//
+// Note that this does not check whether the signer was a member of the
+// runtime's executor committee nor whether the evidence is expired as that
+// requires access to state.
+func (ev *EquivocationExecutorEvidence) ValidateBasic() error {
+	if !ev.CommitA.Signature.PublicKey.Equal(ev.CommitB.Signature.PublicKey) {
+		return fmt.Errorf("roothash: equivocation evidence commitments not signed by the same node")
+	}
+
+	a, err := ev.CommitA.Open()
+	if err != nil {
+		return fmt.Errorf("roothash: equivocation evidence commitment A: %w", err)
+	}
+	b, err := ev.CommitB.Open()
+	if err != nil {
+		return fmt.Errorf("roothash: equivocation evidence commitment B: %w", err)
+	}
+	if err = a.Body.ValidateBasic(); err != nil {
+		return fmt.Errorf("roothash: equivocation evidence commitment A: %w", err)
+	}
+	if err = b.Body.ValidateBasic(); err != nil {
+		return fmt.Errorf("roothash: equivocation evidence commitment B: %w", err)
+	}
+
+	// Failure indicating commitments are not considered equivocation.
+	if a.IsIndicatingFailure() || b.IsIndicatingFailure() {
+		return fmt.Errorf("roothash: equivocation evidence includes failure indicating commitment")
+	}
+
+	if a.Body.Header.Round != b.Body.Header.Round {
+		return fmt.Errorf("roothash: equivocation evidence commitments not for the same round")
+	}
+	if !a.Body.Header.PreviousHash.Equal(&b.Body.Header.PreviousHash) {
+		return fmt.Errorf("roothash: equivocation evidence commitments not for the same previous block")
+	}
+	if a.MostlyEqual(*b) {
+		return fmt.Errorf("roothash: equivocation evidence commitments are not conflicting")
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+func TestEquivocationExecutorEvidence(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	signer := memorySigner.NewTestSigner("roothash/api: evidence signer")
+	otherSigner := memorySigner.NewTestSigner("roothash/api: evidence other signer")
+
+	var prevHash, rootA, rootB hash.Hash
+	prevHash.FromBytes([]byte("previous block"))
+	rootA.FromBytes([]byte("state root A"))
+	rootB.FromBytes([]byte("state root B"))
+
+	newBody := func(round uint64, stateRoot hash.Hash) *commitment.ComputeBody {
+		var ioRoot hash.Hash
+		ioRoot.Empty()
+		return &commitment.ComputeBody{
+			Header: commitment.ComputeResultsHeader{
+				Round:        round,
+				PreviousHash: prevHash,
+				IORoot:       &ioRoot,
+				StateRoot:    &stateRoot,
+			},
+		}
+	}
+	sign := func(signer signature.Signer, body *commitment.ComputeBody) commitment.ExecutorCommitment {
+		commit, err := commitment.SignExecutorCommitment(signer, body)
+		require.NoError(err, "SignExecutorCommitment")
+		return *commit
+	}
+
+	// Valid evidence.
+	ev := Evidence{
+		EquivocationExecutor: &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(signer, newBody(10, rootB)),
+		},
+	}
+	require.NoError(ev.ValidateBasic(), "valid evidence")
+
+	// Invalid evidence.
+	failureBody := newBody(10, rootB)
+	failureBody.SetFailure(commitment.FailureUnknown)
+	otherPrevBody := newBody(10, rootB)
+	otherPrevBody.Header.PreviousHash.Empty()
+	corrupted := sign(signer, newBody(10, rootB))
+	corrupted.Blob = append([]byte{}, corrupted.Blob...)
+	corrupted.Blob[0] ^= 0xff
+
+	for _, tc := range []struct {
+		msg      string
+		evidence *EquivocationExecutorEvidence
+	}{
+		{"different signers", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(otherSigner, newBody(10, rootB)),
+		}},
+		{"invalid signature", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: corrupted,
+		}},
+		{"failure indicating commitment", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(signer, failureBody),
+		}},
+		{"different rounds", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(signer, newBody(11, rootB)),
+		}},
+		{"different previous blocks", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(signer, otherPrevBody),
+		}},
+		{"same results", &EquivocationExecutorEvidence{
+			CommitA: sign(signer, newBody(10, rootA)),
+			CommitB: sign(signer, newBody(10, rootA)),
+		}},
+	} {
+		ev = Evidence{EquivocationExecutor: tc.evidence}
+		require.Error(ev.ValidateBasic(), tc.msg)
+	}
+
+	ev = Evidence{}
+	require.Error(ev.ValidateBasic(), "empty evidence")
+}
@@ -0,0 +1,18 @@
+// gen_structure_vectors generates test vectors for the roothash structures
+// that are also hashed or signed by the runtime.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	"github.com/oasisprotocol/oasis-core/go/roothash/tests/vectors"
+)
+
+func main() {
+	testvectors.SetChainContext(vectors.StructuresChainContext)
+
+	jsonOut, _ := json.MarshalIndent(vectors.GenerateStructures(), "", "  ")
+	fmt.Printf("%s", jsonOut)
+}
@@ -0,0 +1,11 @@
+// gen_vectors generates test vectors for the roothash transactions.
+package main
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	"github.com/oasisprotocol/oasis-core/go/roothash/tests/vectors"
+)
+
+func main() {
+	testvectors.Run(vectors.Generator)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/storage"
 )
@@ -146,6 +147,15 @@ func testGenesisBlock(t *testing.T, backend api.Backend, state *runtimeState) {
 	blk, err = backend.GetGenesisBlock(context.Background(), id, consensusAPI.HeightLatest)
 	require.NoError(err, "GetGenesisBlock")
 	require.EqualValues(genesisBlock, blk, "retrieved block is genesis block")
+
+	// Runtimes are not subscribed to staking events by default.
+	stakingSub, err := backend.GetStakingEventSubscription(context.Background(), &api.RuntimeRequest{
+		RuntimeID: id,
+		Height:    consensusAPI.HeightLatest,
+	})
+	require.NoError(err, "GetStakingEventSubscription")
+	require.False(stakingSub.Subscribed, "runtime should not be subscribed to staking events")
+	require.True(stakingSub.Address.Equal(staking.NewRuntimeAddress(id)), "subscription should use the runtime address")
 }
 
 func testEpochTransitionBlock(t *testing.T, backend api.Backend, consensus consensusAPI.Backend, states []*runtimeState) {
@@ -412,6 +422,30 @@ func (s *runtimeState) testSuccessfulRound(t *testing.T, backend api.Backend, co
 				}
 			}
 
+			// The finalized round should be included in the liveness summary.
+			epoch, err := consensus.EpochTime().GetEpoch(ctx, blk.Height)
+			require.NoError(err, "GetEpoch")
+			summary, err := backend.GetLivenessSummary(ctx, &api.LivenessSummaryQuery{
+				RuntimeID: s.rt.Runtime.ID,
+				Epoch:     epoch,
+				Height:    blk.Height,
+			})
+			require.NoError(err, "GetLivenessSummary")
+			require.True(summary.FinalizedRounds > 0, "liveness summary should include finalized round")
+
+			// The submitted commitments should be included in the executor participation.
+			participation, err := backend.GetExecutorParticipation(ctx, &api.ExecutorParticipationQuery{
+				RuntimeID: s.rt.Runtime.ID,
+				Epoch:     epoch,
+				Height:    blk.Height,
+			})
+			require.NoError(err, "GetExecutorParticipation")
+			for _, commit := range executorCommits {
+				p := participation[commit.Signature.PublicKey]
+				require.NotNil(p, "executor participation should include committing node")
+				require.True(p.Commits > 0, "executor participation should include commitment")
+			}
+
 			// Nothing more to do after the block was received.
 			return
 		case <-time.After(recvTimeout):
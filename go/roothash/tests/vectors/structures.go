@@ -0,0 +1,210 @@
+package vectors
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+// StructuresChainContext is the raw chain context used to derive the chain
+// domain separation context for all signatures in structure test vectors.
+const StructuresChainContext = "roothash structure test vectors"
+
+const (
+	// KindBlockHeader is the kind of runtime block header test vectors.
+	KindBlockHeader = "BlockHeader"
+	// KindComputeResultsHeader is the kind of compute results header test
+	// vectors, signed with the RAK.
+	KindComputeResultsHeader = "ComputeResultsHeader"
+	// KindExecutorCommitment is the kind of executor commitment test vectors.
+	KindExecutorCommitment = "ExecutorCommitment"
+)
+
+// StructureTestVector is a roothash structure test vector.
+//
+// NOTE: Keep the test vector format in sync with runtime/src/common/roothash.rs.
+type StructureTestVector struct {
+	// Kind is the kind of the structure.
+	Kind string `json:"kind"`
+	// Value is the structure.
+	Value interface{} `json:"value"`
+	// Encoded is the CBOR-encoded structure.
+	Encoded []byte `json:"encoded"`
+	// Hash is the hash of the CBOR-encoded structure.
+	Hash hash.Hash `json:"hash"`
+
+	// SignatureContext is the signature context used to sign the encoded
+	// structure. It is empty if the structure is not signed.
+	SignatureContext string `json:"signature_context,omitempty"`
+	// Signature is the signature over the encoded structure. It is nil if the
+	// structure is not signed.
+	Signature *signature.Signature `json:"signature,omitempty"`
+	// SignerPrivateKey is the private key of the signer. It is nil if the
+	// structure is not signed.
+	SignerPrivateKey []byte `json:"signer_private_key,omitempty"`
+}
+
+func makeStructureTestVector(kind string, value interface{}) StructureTestVector {
+	encoded := cbor.Marshal(value)
+	return StructureTestVector{
+		Kind:    kind,
+		Value:   value,
+		Encoded: encoded,
+		Hash:    hash.NewFromBytes(encoded),
+	}
+}
+
+func makeSignedStructureTestVector(
+	kind string,
+	value interface{},
+	signer signature.Signer,
+	context signature.Context,
+) StructureTestVector {
+	v := makeStructureTestVector(kind, value)
+
+	sigCtx, err := signature.PrepareSignerContext(context)
+	if err != nil {
+		panic(err)
+	}
+	sig, err := signature.Sign(signer, context, v.Encoded)
+	if err != nil {
+		panic(err)
+	}
+
+	v.SignatureContext = string(sigCtx)
+	v.Signature = sig
+	v.SignerPrivateKey = signer.(signature.UnsafeSigner).UnsafeBytes()
+	return v
+}
+
+// GenerateStructures generates test vectors for the roothash structures that
+// are also hashed or signed by the runtime.
+//
+// The chain context must be set to the one derived from StructuresChainContext.
+func GenerateStructures() []StructureTestVector {
+	var vectors []StructureTestVector
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("oasis-core roothash structure test vectors: runtime"), 0)
+	storageSigner := memorySigner.NewTestSigner("oasis-core roothash structure test vectors: storage signer")
+	rakSigner := memorySigner.NewTestSigner("oasis-core roothash structure test vectors: RAK signer")
+	nodeSigner := memorySigner.NewTestSigner("oasis-core roothash structure test vectors: node signer")
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	// Runtime block headers.
+	var emptyHeader block.Header
+	vectors = append(vectors, makeStructureTestVector(KindBlockHeader, emptyHeader))
+	for _, headerType := range []block.HeaderType{
+		block.Normal,
+		block.RoundFailed,
+		block.EpochTransition,
+		block.Suspended,
+	} {
+		for _, round := range []uint64{0, 1, 1000} {
+			var previousHash, ioRoot, stateRoot hash.Hash
+			previousHash.FromBytes([]byte(fmt.Sprintf("previous hash %d", round)))
+			ioRoot.FromBytes([]byte(fmt.Sprintf("io root %d", round)))
+			stateRoot.FromBytes([]byte(fmt.Sprintf("state root %d", round)))
+
+			hdr := block.Header{
+				Version:      42,
+				Namespace:    runtimeID,
+				Round:        round,
+				Timestamp:    1560257841 + round,
+				HeaderType:   headerType,
+				PreviousHash: previousHash,
+				IORoot:       ioRoot,
+				StateRoot:    stateRoot,
+			}
+			vectors = append(vectors, makeStructureTestVector(KindBlockHeader, hdr))
+
+			// Headers with storage receipt signatures.
+			receipt, err := storage.SignReceipt(storageSigner, runtimeID, round, hdr.RootsForStorageReceipt())
+			if err != nil {
+				panic(err)
+			}
+			hdr.StorageSignatures = []signature.Signature{receipt.Signature}
+			vectors = append(vectors, makeStructureTestVector(KindBlockHeader, hdr))
+		}
+	}
+
+	// Compute results headers.
+	var emptyComputeHeader commitment.ComputeResultsHeader
+	vectors = append(vectors, makeSignedStructureTestVector(
+		KindComputeResultsHeader,
+		emptyComputeHeader,
+		rakSigner,
+		commitment.ComputeResultsHeaderSignatureContext,
+	))
+	var computeHeaders []commitment.ComputeResultsHeader
+	for _, round := range []uint64{1, 2, 1000} {
+		var previousHash, ioRoot, stateRoot hash.Hash
+		previousHash.FromBytes([]byte(fmt.Sprintf("previous hash %d", round)))
+		ioRoot.FromBytes([]byte(fmt.Sprintf("io root %d", round)))
+		stateRoot.FromBytes([]byte(fmt.Sprintf("state root %d", round)))
+
+		for _, hdr := range []commitment.ComputeResultsHeader{
+			// Failure indication.
+			{
+				Round:        round,
+				PreviousHash: previousHash,
+			},
+			{
+				Round:        round,
+				PreviousHash: previousHash,
+				IORoot:       &ioRoot,
+				StateRoot:    &stateRoot,
+			},
+			{
+				Round:        round,
+				PreviousHash: previousHash,
+				IORoot:       &emptyRoot,
+				StateRoot:    &emptyRoot,
+			},
+		} {
+			computeHeaders = append(computeHeaders, hdr)
+			vectors = append(vectors, makeSignedStructureTestVector(
+				KindComputeResultsHeader,
+				hdr,
+				rakSigner,
+				commitment.ComputeResultsHeaderSignatureContext,
+			))
+		}
+	}
+
+	// Executor commitments.
+	for _, hdr := range computeHeaders {
+		var inputRoot hash.Hash
+		inputRoot.FromBytes([]byte(fmt.Sprintf("input root %d", hdr.Round)))
+
+		body := commitment.ComputeBody{
+			Header:    hdr,
+			InputRoot: inputRoot,
+		}
+		if hdr.IORoot == nil {
+			body.SetFailure(commitment.FailureStorageUnavailable)
+		} else {
+			rakSig, err := signature.Sign(rakSigner, commitment.ComputeResultsHeaderSignatureContext, cbor.Marshal(hdr))
+			if err != nil {
+				panic(err)
+			}
+			body.RakSig = &rakSig.Signature
+		}
+		vectors = append(vectors, makeSignedStructureTestVector(
+			KindExecutorCommitment,
+			body,
+			nodeSigner,
+			commitment.ExecutorSignatureContext,
+		))
+	}
+
+	return vectors
+}
@@ -0,0 +1,44 @@
+package vectors
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/oasisprotocol/ed25519"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+)
+
+func TestStructureVectors(t *testing.T) {
+	require := require.New(t)
+
+	testvectors.SetChainContext(StructuresChainContext)
+	defer signature.UnsafeResetChainContext()
+
+	generated, err := json.MarshalIndent(GenerateStructures(), "", "  ")
+	require.NoError(err, "MarshalIndent")
+
+	// NOTE: The test vectors are also used by runtime/src/common/roothash.rs. In case the
+	//       structures change, regenerate them using `go run ./roothash/gen_structure_vectors`.
+	expected, err := ioutil.ReadFile(filepath.Join("testdata", "structures.json"))
+	require.NoError(err, "ReadFile")
+	require.Equal(string(expected), string(generated), "test vectors should be up to date")
+
+	var vectors []StructureTestVector
+	err = json.Unmarshal(expected, &vectors)
+	require.NoError(err, "Unmarshal")
+	for _, v := range vectors {
+		require.Equal(hash.NewFromBytes(v.Encoded), v.Hash, "hash should match the encoded structure")
+		if v.Signature == nil {
+			continue
+		}
+		// Verify the signature using the prepared signature context, same as the runtime.
+		digest := hash.NewFromBytes([]byte(v.SignatureContext), v.Encoded)
+		require.True(ed25519.Verify(v.Signature.PublicKey[:], digest[:], v.Signature.Signature[:]), "signature should be valid")
+	}
+}
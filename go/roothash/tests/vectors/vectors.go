@@ -0,0 +1,76 @@
+// Package vectors implements the test vector generator for the roothash
+// transactions.
+package vectors
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+// Generator is the roothash transaction test vector generator.
+var Generator = &testvectors.Generator{
+	Name:         roothash.ModuleName,
+	ChainContext: "roothash test vectors",
+	Generate:     Generate,
+}
+
+// Generate generates test vectors for the roothash transactions.
+func Generate() []testvectors.TestVector {
+	var vectors []testvectors.TestVector
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("oasis-core roothash test vectors: runtime"), 0)
+
+	// Generate different gas fees.
+	for _, fee := range testvectors.Fees {
+		// Generate different nonces.
+		for _, nonce := range testvectors.Nonces {
+			// Valid executor commit transactions.
+			for _, numCommits := range []int{1, 2, 5} {
+				for _, round := range []uint64{0, 1, 1000} {
+					var commits []commitment.ExecutorCommitment
+					var txSigner signature.Signer
+					for i := 0; i < numCommits; i++ {
+						nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("oasis-core roothash test vectors: ExecutorCommit node signer %d", i))
+						if i == 0 {
+							// The first node submits the transaction.
+							txSigner = nodeSigner
+						}
+
+						var previousHash, ioRoot, stateRoot, inputRoot hash.Hash
+						previousHash.FromBytes([]byte(fmt.Sprintf("previous hash %d", round)))
+						ioRoot.FromBytes([]byte(fmt.Sprintf("io root %d", round)))
+						stateRoot.FromBytes([]byte(fmt.Sprintf("state root %d", round)))
+						inputRoot.FromBytes([]byte(fmt.Sprintf("input root %d", round)))
+
+						body := commitment.ComputeBody{
+							Header: commitment.ComputeResultsHeader{
+								Round:        round + 1,
+								PreviousHash: previousHash,
+								IORoot:       &ioRoot,
+								StateRoot:    &stateRoot,
+							},
+							InputRoot: inputRoot,
+						}
+						commit, err := commitment.SignExecutorCommitment(nodeSigner, &body)
+						if err != nil {
+							panic(err)
+						}
+						commits = append(commits, *commit)
+					}
+
+					tx := roothash.NewExecutorCommitTx(nonce, fee, runtimeID, commits)
+					vectors = append(vectors, testvectors.MakeTestVectorWithSigner("ExecutorCommit", tx, txSigner))
+				}
+			}
+		}
+	}
+
+	return vectors
+}
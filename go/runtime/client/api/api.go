@@ -66,6 +66,14 @@ type RuntimeClient interface {
 	// WaitBlockIndexed waits for a runtime block to be indexed by the indexer.
 	WaitBlockIndexed(ctx context.Context, request *WaitBlockIndexedRequest) error
 
+	// WaitRound waits for the given runtime round to be finalized and available in the local
+	// block history and returns its block. Use a context deadline to limit the wait.
+	//
+	// In case a progress callback is attached to the context via WithWaitRoundProgress, it is
+	// invoked for each runtime block finalized while waiting. Progress callbacks are not
+	// supported over gRPC.
+	WaitRound(ctx context.Context, request *WaitRoundRequest) (*block.Block, error)
+
 	// Cleanup cleans up the backend.
 	Cleanup()
 }
@@ -166,3 +174,30 @@ type WaitBlockIndexedRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Round     uint64           `json:"round"`
 }
+
+// WaitRoundRequest is a WaitRound request.
+type WaitRoundRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+}
+
+// WaitRoundProgressFunc is a WaitRound progress callback.
+type WaitRoundProgressFunc func(blk *block.Block)
+
+type waitRoundProgressKey struct{}
+
+// WithWaitRoundProgress returns a new context with the given WaitRound progress callback attached.
+func WithWaitRoundProgress(ctx context.Context, fn WaitRoundProgressFunc) context.Context {
+	return context.WithValue(ctx, waitRoundProgressKey{}, fn)
+}
+
+// WaitRoundProgressFromContext returns the WaitRound progress callback attached to the context.
+//
+// In case no callback is attached, a no-op callback is returned.
+func WaitRoundProgressFromContext(ctx context.Context) WaitRoundProgressFunc {
+	fn, ok := ctx.Value(waitRoundProgressKey{}).(WaitRoundProgressFunc)
+	if !ok || fn == nil {
+		return func(*block.Block) {}
+	}
+	return fn
+}
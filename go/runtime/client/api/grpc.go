@@ -40,6 +40,8 @@ var (
 	methodQueryTxs = serviceName.NewMethod("QueryTxs", QueryTxsRequest{})
 	// methodWaitBlockIndexed is the WaitBlockIndexed method.
 	methodWaitBlockIndexed = serviceName.NewMethod("WaitBlockIndexed", WaitBlockIndexedRequest{})
+	// methodWaitRound is the WaitRound method.
+	methodWaitRound = serviceName.NewMethod("WaitRound", WaitRoundRequest{})
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
@@ -89,6 +91,10 @@ var (
 				MethodName: methodWaitBlockIndexed.ShortName(),
 				Handler:    handlerWaitBlockIndexed,
 			},
+			{
+				MethodName: methodWaitRound.ShortName(),
+				Handler:    handlerWaitRound,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -369,6 +375,29 @@ func handlerWaitBlockIndexed( // nolint: golint
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerWaitRound( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var rq WaitRoundRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).WaitRound(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodWaitRound.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeClient).WaitRound(ctx, req.(*WaitRoundRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	var runtimeID common.Namespace
 	if err := stream.RecvMsg(&runtimeID); err != nil {
@@ -485,6 +514,14 @@ func (c *runtimeClient) WaitBlockIndexed(ctx context.Context, request *WaitBlock
 	return c.conn.Invoke(ctx, methodWaitBlockIndexed.FullName(), request, nil)
 }
 
+func (c *runtimeClient) WaitRound(ctx context.Context, request *WaitRoundRequest) (*block.Block, error) {
+	var rsp block.Block
+	if err := c.conn.Invoke(ctx, methodWaitRound.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *runtimeClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
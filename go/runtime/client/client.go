@@ -3,6 +3,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -373,6 +374,53 @@ func (c *runtimeClient) WaitBlockIndexed(ctx context.Context, request *api.WaitB
 	return tagIndexer.WaitBlockIndexed(ctx, request.Round)
 }
 
+// Implements api.RuntimeClient.
+func (c *runtimeClient) WaitRound(ctx context.Context, request *api.WaitRoundRequest) (*block.Block, error) {
+	rt, err := c.common.runtimeRegistry.GetRuntime(request.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+	history := rt.History()
+
+	// Subscribe before checking the history to not miss any blocks. Blocks are committed to the
+	// block history before they are broadcast to subscribers.
+	blkCh, blkSub, err := c.common.consensus.RootHash().WatchBlocks(request.RuntimeID)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to watch blocks: %w", err)
+	}
+	defer blkSub.Close()
+
+	progressFn := api.WaitRoundProgressFromContext(ctx)
+	for {
+		var latestBlk *block.Block
+		latestBlk, err = history.GetLatestBlock(ctx)
+		switch {
+		case err == nil:
+			if latestBlk.Header.Round >= request.Round {
+				return history.GetBlock(ctx, request.Round)
+			}
+		case errors.Is(err, roothash.ErrNotFound):
+			// No blocks in history yet.
+		default:
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			// The context we're working in was canceled, abort.
+			return nil, ctx.Err()
+		case <-c.common.ctx.Done():
+			// Client is shutting down.
+			return nil, fmt.Errorf("client: shutting down")
+		case annBlk, ok := <-blkCh:
+			if !ok {
+				return nil, fmt.Errorf("client: block channel closed unexpectedly")
+			}
+			progressFn(annBlk.Block)
+		}
+	}
+}
+
 // Implements enclaverpc.Transport.
 func (c *runtimeClient) CallEnclave(ctx context.Context, request *enclaverpc.CallEnclaveRequest) ([]byte, error) {
 	switch request.Endpoint {
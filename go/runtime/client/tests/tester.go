@@ -63,7 +63,11 @@ func testQuery(
 	c api.RuntimeClient,
 	input string,
 ) {
-	err := c.WaitBlockIndexed(ctx, &api.WaitBlockIndexedRequest{RuntimeID: runtimeID, Round: 3})
+	blk, err := c.WaitRound(ctx, &api.WaitRoundRequest{RuntimeID: runtimeID, Round: 3})
+	require.NoError(t, err, "WaitRound")
+	require.EqualValues(t, 3, blk.Header.Round, "WaitRound should return the block for the given round")
+
+	err = c.WaitBlockIndexed(ctx, &api.WaitBlockIndexedRequest{RuntimeID: runtimeID, Round: 3})
 	require.NoError(t, err, "WaitBlockIndexed")
 
 	// Fetch genesis block.
@@ -76,7 +80,7 @@ func testQuery(
 	testOutput := testInput
 
 	// Fetch blocks.
-	blk, err := c.GetBlock(ctx, &api.GetBlockRequest{RuntimeID: runtimeID, Round: 1})
+	blk, err = c.GetBlock(ctx, &api.GetBlockRequest{RuntimeID: runtimeID, Round: 1})
 	// Epoch transition from TestNode/ExecutorWorker/InitialEpochTransition
 	require.NoError(t, err, "GetBlock")
 	require.EqualValues(t, 1, blk.Header.Round)
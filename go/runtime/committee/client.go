@@ -9,11 +9,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/resolver/manual"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/mathrand"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
@@ -21,6 +24,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
 const (
@@ -32,6 +36,46 @@ const (
 	grpcMinConnectTimeout = 20 * time.Second
 )
 
+var (
+	clientConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_committee_client_connections",
+			Help: "Number of connections to committee nodes.",
+		},
+		[]string{"runtime", "kind"},
+	)
+	clientDials = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_committee_client_dials",
+			Help: "Number of new connections established to committee nodes.",
+		},
+		[]string{"runtime", "kind"},
+	)
+	clientReuses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_committee_client_reuses",
+			Help: "Number of existing connections reused after a committee change.",
+		},
+		[]string{"runtime", "kind"},
+	)
+	clientHealthCheckFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_committee_client_health_check_failures",
+			Help: "Number of failed connection health checks.",
+		},
+		[]string{"runtime", "kind"},
+	)
+
+	clientCollectors = []prometheus.Collector{
+		clientConnections,
+		clientDials,
+		clientReuses,
+		clientHealthCheckFailures,
+	}
+
+	metricsOnce sync.Once
+)
+
 // NodeSelectionFeedback is feedback to the node selection policy.
 type NodeSelectionFeedback struct {
 	// ID is the node identifier.
@@ -210,8 +254,12 @@ func (cs *clientConnState) Close() {
 type committeeClient struct {
 	sync.RWMutex
 
-	nw       NodeDescriptorLookup
-	conns    map[signature.PublicKey]*clientConnState
+	nw    NodeDescriptorLookup
+	conns map[signature.PublicKey]*clientConnState
+	// staleConns are connections to nodes from the previous committee which are kept around
+	// until the new committee is frozen so that they can be reused in case the nodes remain in
+	// the committee.
+	staleConns map[signature.PublicKey]*clientConnState
 	version  int64
 	notifier *pubsub.Broker
 	initCh   chan struct{}
@@ -219,6 +267,8 @@ type committeeClient struct {
 	clientIdentity      *identity.Identity
 	nodeSelectionPolicy NodeSelectionPolicy
 	closeDelay          time.Duration
+	healthCheckInterval time.Duration
+	metricsLabels       prometheus.Labels
 
 	logger *logging.Logger
 }
@@ -315,6 +365,17 @@ func (cc *committeeClient) Initialized() <-chan struct{} {
 }
 
 func (cc *committeeClient) updateConnectionLocked(n *node.Node) error {
+	// Reuse the connection from the previous committee if the node is still a member.
+	if cs := cc.staleConns[n.ID]; cs != nil {
+		cc.logger.Debug("reusing connection from previous committee",
+			"node", n.ID,
+		)
+
+		delete(cc.staleConns, n.ID)
+		cc.conns[n.ID] = cs
+		cc.incMetric(clientReuses)
+	}
+
 	// If the connection to given node already exists, only update its addresses/certificates.
 	var cs *clientConnState
 	if cs = cc.conns[n.ID]; cs != nil {
@@ -387,6 +448,7 @@ func (cc *committeeClient) updateConnectionLocked(n *node.Node) error {
 		cs.conn = conn
 
 		cc.conns[n.ID] = cs
+		cc.incMetric(clientDials)
 	}
 
 	return cs.Update(n)
@@ -402,6 +464,52 @@ func (cc *committeeClient) deleteConnectionLocked(id signature.PublicKey) {
 	delete(cc.conns, id)
 }
 
+func (cc *committeeClient) closeStaleConnectionsLocked() {
+	for id, cs := range cc.staleConns {
+		cs.DelayedClose(cc.closeDelay)
+		delete(cc.staleConns, id)
+	}
+}
+
+func (cc *committeeClient) checkConnectionsLocked() {
+	picked := cc.nodeSelectionPolicy.Pick()
+	for id, cs := range cc.conns {
+		if cs.conn == nil || cs.conn.GetState() != connectivity.TransientFailure {
+			continue
+		}
+
+		cc.logger.Warn("connection to committee node is unhealthy",
+			"node", id,
+		)
+		cc.incMetric(clientHealthCheckFailures)
+
+		// Skip the connection backoff and try to reconnect immediately.
+		cs.conn.ResetConnectBackoff()
+
+		// Make sure the node selection policy avoids the node while it is unavailable.
+		if id.Equal(picked) {
+			cc.nodeSelectionPolicy.UpdatePolicy(NodeSelectionFeedback{
+				ID:  id,
+				Bad: fmt.Errorf("connection health check failed"),
+			})
+		}
+	}
+}
+
+func (cc *committeeClient) incMetric(c *prometheus.CounterVec) {
+	if cc.metricsLabels == nil {
+		return
+	}
+	c.With(cc.metricsLabels).Inc()
+}
+
+func (cc *committeeClient) updateMetricsLocked() {
+	if cc.metricsLabels == nil {
+		return
+	}
+	clientConnections.With(cc.metricsLabels).Set(float64(len(cc.conns) + len(cc.staleConns)))
+}
+
 func (cc *committeeClient) refreshConnectionLocked(id signature.PublicKey) {
 	cs := cc.conns[id]
 	if cs == nil {
@@ -428,6 +536,14 @@ func (cc *committeeClient) worker(ctx context.Context, ch <-chan *NodeUpdate, su
 		defer rotSub.Close()
 	}
 
+	// Periodically check connection health if configured.
+	var healthCh <-chan time.Time
+	if cc.healthCheckInterval > 0 {
+		ticker := time.NewTicker(cc.healthCheckInterval)
+		defer ticker.Stop()
+		healthCh = ticker.C
+	}
+
 	var initialized bool
 	for {
 		select {
@@ -445,6 +561,13 @@ func (cc *committeeClient) worker(ctx context.Context, ch <-chan *NodeUpdate, su
 					cc.refreshConnectionLocked(id)
 				}
 			}()
+		case <-healthCh:
+			func() {
+				cc.Lock()
+				defer cc.Unlock()
+
+				cc.checkConnectionsLocked()
+			}()
 		case u := <-ch:
 			func() {
 				cc.Lock()
@@ -452,12 +575,17 @@ func (cc *committeeClient) worker(ctx context.Context, ch <-chan *NodeUpdate, su
 
 				switch {
 				case u.Reset:
-					// Committee has been reset.
-					for id := range cc.conns {
-						cc.deleteConnectionLocked(id)
+					// Committee has been reset. Keep existing connections around until the new
+					// committee is frozen as they may be reused.
+					for id, cs := range cc.conns {
+						cc.staleConns[id] = cs
 					}
+					cc.conns = make(map[signature.PublicKey]*clientConnState)
 				case u.Freeze != nil:
-					// Committee has been frozen.
+					// Committee has been frozen, close connections to nodes that are no longer
+					// committee members.
+					cc.closeStaleConnectionsLocked()
+
 					var nodes []signature.PublicKey
 					for id := range cc.conns {
 						nodes = append(nodes, id)
@@ -493,6 +621,8 @@ func (cc *committeeClient) worker(ctx context.Context, ch <-chan *NodeUpdate, su
 						"update", u,
 					)
 				}
+
+				cc.updateMetricsLocked()
 			}()
 		}
 	}
@@ -527,6 +657,29 @@ func WithCloseDelay(delay time.Duration) ClientOption {
 	}
 }
 
+// WithHealthCheckInterval is an option for configuring periodic connection health checks.
+//
+// Connections that are found to be unhealthy are reconnected immediately, skipping any connection
+// backoff, and the node selection policy is notified so that it avoids such nodes.
+//
+// If not configured, health checks are disabled.
+func WithHealthCheckInterval(interval time.Duration) ClientOption {
+	return func(cc *committeeClient) {
+		cc.healthCheckInterval = interval
+	}
+}
+
+// WithMetrics is an option for enabling connection metrics for the given runtime and committee
+// kind.
+func WithMetrics(runtimeID common.Namespace, kind scheduler.CommitteeKind) ClientOption {
+	return func(cc *committeeClient) {
+		cc.metricsLabels = prometheus.Labels{
+			"runtime": runtimeID.String(),
+			"kind":    kind.String(),
+		}
+	}
+}
+
 // NewClient creates a new committee client.
 func NewClient(ctx context.Context, nw NodeDescriptorLookup, options ...ClientOption) (Client, error) {
 	ch, sub, err := nw.WatchNodeUpdates()
@@ -537,6 +690,7 @@ func NewClient(ctx context.Context, nw NodeDescriptorLookup, options ...ClientOp
 	cc := &committeeClient{
 		nw:                  nw,
 		conns:               make(map[signature.PublicKey]*clientConnState),
+		staleConns:          make(map[signature.PublicKey]*clientConnState),
 		notifier:            pubsub.NewBroker(false),
 		initCh:              make(chan struct{}),
 		nodeSelectionPolicy: NewRoundRobinNodeSelectionPolicy(),
@@ -548,6 +702,12 @@ func NewClient(ctx context.Context, nw NodeDescriptorLookup, options ...ClientOp
 		o(cc)
 	}
 
+	if cc.metricsLabels != nil {
+		metricsOnce.Do(func() {
+			prometheus.MustRegister(clientCollectors...)
+		})
+	}
+
 	go cc.worker(ctx, ch, sub)
 
 	return cc, nil
@@ -258,11 +258,7 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 
 		if resp.Error != nil {
 			// Decode error.
-			err = errors.FromCode(resp.Error.Module, resp.Error.Code)
-			if err == nil {
-				err = fmt.Errorf("%s", resp.Error.Message)
-			}
-			return nil, err
+			return nil, errors.FromCodeWithMessage(resp.Error.Module, resp.Error.Code, resp.Error.Message)
 		}
 
 		return resp, nil
@@ -11,6 +11,7 @@ import (
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 )
 
@@ -211,6 +212,10 @@ type RuntimeExecuteTxBatchRequest struct {
 	Inputs transaction.RawBatch `json:"inputs"`
 	// Block on which the batch computation should be based.
 	Block roothash.Block `json:"block"`
+	// StakingEvents are the staking events affecting the runtime account that occurred since
+	// the last processed round. They are only included in case the runtime is subscribed to
+	// staking events.
+	StakingEvents []*staking.Event `json:"staking_events,omitempty"`
 }
 
 // RuntimeExecuteTxBatchResponse is a worker execute tx batch response message body.
@@ -217,6 +217,11 @@ type Backend interface {
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
+	// SimulateElection deterministically re-runs the committee election of the given
+	// kind for the given runtime and epoch, and returns all of the election inputs
+	// together with the step-by-step election result.
+	SimulateElection(ctx context.Context, request *SimulateElectionRequest) (*ElectionSimulation, error)
+
 	// Cleanup cleans up the scheduler backend.
 	Cleanup()
 }
@@ -227,6 +232,84 @@ type GetCommitteesRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 }
 
+// SimulateElectionRequest is a SimulateElection request.
+type SimulateElectionRequest struct {
+	RuntimeID common.Namespace    `json:"runtime_id"`
+	Kind      CommitteeKind       `json:"kind"`
+	Epoch     epochtime.EpochTime `json:"epoch"`
+}
+
+// ElectionCandidate is a node considered during a committee election.
+type ElectionCandidate struct {
+	// ID is the node identifier.
+	ID signature.PublicKey `json:"id"`
+
+	// EntityID is the identifier of the entity controlling the node.
+	EntityID signature.PublicKey `json:"entity_id"`
+
+	// EntityStake is the entity's active escrow balance at the time of the election.
+	EntityStake quantity.Quantity `json:"entity_stake"`
+
+	// Eligible is true iff the node was eligible for election.
+	Eligible bool `json:"eligible"`
+
+	// Reason is the reason why the node was not eligible for election.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ElectionStep is a single step of a deterministic committee election.
+type ElectionStep struct {
+	// Index is the index of the elected node in the list of eligible nodes as given by the
+	// election permutation.
+	Index int `json:"index"`
+
+	// Node is the committee member elected in this step.
+	Node CommitteeNode `json:"node"`
+}
+
+// ElectionSimulation is the result of a committee election simulation.
+type ElectionSimulation struct {
+	// Height is the consensus block height at which the election took place.
+	Height int64 `json:"height"`
+
+	// Epoch is the epoch for which the committee was elected.
+	Epoch epochtime.EpochTime `json:"epoch"`
+
+	// RuntimeID is the runtime identifier.
+	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// Kind is the kind of the elected committee.
+	Kind CommitteeKind `json:"kind"`
+
+	// Beacon is the beacon entropy used to seed the election.
+	Beacon []byte `json:"beacon"`
+
+	// WorkerSize is the number of workers that should be elected.
+	WorkerSize int `json:"worker_size"`
+
+	// BackupSize is the number of backup workers that should be elected.
+	BackupSize int `json:"backup_size"`
+
+	// Candidates are all of the nodes considered during the election.
+	Candidates []*ElectionCandidate `json:"candidates"`
+
+	// Eligible are the identifiers of the eligible nodes in the order in which they were
+	// passed to the election.
+	Eligible []signature.PublicKey `json:"eligible"`
+
+	// Permutation is the deterministic permutation of the eligible nodes.
+	Permutation []int `json:"permutation"`
+
+	// Steps are the individual election steps.
+	Steps []*ElectionStep `json:"steps"`
+
+	// Failure is the reason why a committee could not be elected (if any).
+	Failure string `json:"failure,omitempty"`
+
+	// Committee is the committee as stored in consensus state (if any).
+	Committee *Committee `json:"committee,omitempty"`
+}
+
 // Genesis is the committee scheduler genesis state.
 type Genesis struct {
 	// Parameters are the scheduler consensus parameters.
@@ -125,6 +125,26 @@ func SchedulerImplementationTests(t *testing.T, name string, backend api.Backend
 		1,
 	)
 
+	// Simulating the elections should yield the same committees.
+	for _, kind := range []api.CommitteeKind{api.KindComputeExecutor, api.KindStorage} {
+		var sim *api.ElectionSimulation
+		sim, err = backend.SimulateElection(context.Background(), &api.SimulateElectionRequest{
+			RuntimeID: rt.Runtime.ID,
+			Kind:      kind,
+			Epoch:     epoch,
+		})
+		require.NoError(err, "SimulateElection")
+		require.Empty(sim.Failure, "election simulation should succeed")
+		require.NotEmpty(sim.Beacon, "election simulation should include the beacon")
+		require.GreaterOrEqual(len(sim.Candidates), len(nodes), "all nodes should be considered")
+		require.NotNil(sim.Committee, "election simulation should include the elected committee")
+		require.Equal(epoch, sim.Committee.ValidFor, "elected committee should be for the given epoch")
+		require.Len(sim.Steps, len(sim.Committee.Members), "election simulation should elect all members")
+		for i, step := range sim.Steps {
+			require.EqualValues(*sim.Committee.Members[i], step.Node, "simulated member should match elected member")
+		}
+	}
+
 	// Cleanup the registry.
 	rt.Cleanup(t, consensus.Registry(), consensus)
 
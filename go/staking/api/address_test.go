@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 )
 
@@ -35,3 +36,21 @@ func TestReserved(t *testing.T) {
 	require.True(pk2.IsBlacklisted(), "public key for test address 2 should be blacklisted")
 	require.False(pk2.IsValid(), "public key for test address 2 should be invalid")
 }
+
+func TestRuntimeAddress(t *testing.T) {
+	require := require.New(t)
+
+	var rtID, rtID2 common.Namespace
+	_ = rtID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	_ = rtID2.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+
+	addr := NewRuntimeAddress(rtID)
+	require.True(addr.IsValid(), "runtime address should be valid")
+	require.True(addr.Equal(NewRuntimeAddress(rtID)), "runtime address should be deterministic")
+	require.False(addr.Equal(NewRuntimeAddress(rtID2)), "runtime addresses should differ between runtimes")
+
+	// Runtime addresses must not collide with addresses derived from public keys.
+	var pk signature.PublicKey
+	copy(pk[:], rtID[:])
+	require.False(addr.Equal(NewAddress(pk)), "runtime address should not collide with a public key address")
+}
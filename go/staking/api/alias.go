@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/address"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+const (
+	// MinAliasLength is the minimum length of an address alias.
+	MinAliasLength = 3
+	// MaxAliasLength is the maximum length of an address alias.
+	MaxAliasLength = 64
+)
+
+var (
+	// aliasRegexp is the regular expression an address alias must match.
+	aliasRegexp = regexp.MustCompile("^[a-z][a-z0-9-]*[a-z0-9]$")
+
+	_ prettyprint.PrettyPrinter = (*RegisterAlias)(nil)
+	_ prettyprint.PrettyPrinter = (*AliasInfo)(nil)
+)
+
+// ValidateAlias checks whether the given string is a well-formed address alias.
+//
+// Aliases consist of lower case letters, digits and dashes, must start with a
+// letter and must not end with a dash. To prevent aliases from being confused
+// with account addresses, aliases that look like Bech32-encoded addresses or
+// like hex-encoded addresses or public keys are rejected.
+func ValidateAlias(alias string) error {
+	if len(alias) < MinAliasLength || len(alias) > MaxAliasLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters",
+			ErrInvalidAlias, MinAliasLength, MaxAliasLength,
+		)
+	}
+	if !aliasRegexp.MatchString(alias) {
+		return fmt.Errorf("%w: must match '%s'", ErrInvalidAlias, aliasRegexp)
+	}
+	if strings.HasPrefix(alias, AddressBech32HRP.String()+"1") {
+		return fmt.Errorf("%w: collides with the account address format", ErrInvalidAlias)
+	}
+	if raw, err := hex.DecodeString(alias); err == nil {
+		switch len(raw) {
+		case address.Size, signature.PublicKeySize:
+			return fmt.Errorf("%w: collides with a hex-encoded address or public key", ErrInvalidAlias)
+		}
+	}
+	return nil
+}
+
+// AliasInfo is the registration record of an address alias.
+type AliasInfo struct {
+	// Address is the account address the alias resolves to.
+	Address Address `json:"address"`
+	// Expiration is the epoch at which the alias registration expires.
+	Expiration epochtime.EpochTime `json:"expiration"`
+}
+
+// PrettyPrint writes a pretty-printed representation of AliasInfo to the given
+// writer.
+func (ai AliasInfo) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sAddress:    %s\n", prefix, ai.Address)
+	fmt.Fprintf(w, "%sExpiration: %d\n", prefix, ai.Expiration)
+}
+
+// PrettyType returns a representation of AliasInfo that can be used for pretty
+// printing.
+func (ai AliasInfo) PrettyType() (interface{}, error) {
+	return ai, nil
+}
+
+// AliasQuery is an address alias query.
+type AliasQuery struct {
+	Height int64  `json:"height"`
+	Alias  string `json:"alias"`
+}
+
+// AliasEvent is the event emitted when an address alias is registered or
+// renewed.
+type AliasEvent struct {
+	Alias      string              `json:"alias"`
+	Owner      Address             `json:"owner"`
+	Expiration epochtime.EpochTime `json:"expiration"`
+}
+
+// RegisterAlias is an address alias registration (or renewal).
+type RegisterAlias struct {
+	// Alias is the alias to register for the transaction signer's account.
+	Alias string `json:"alias"`
+	// Epochs is the number of epochs to register (or extend) the alias for.
+	Epochs epochtime.EpochTime `json:"epochs"`
+}
+
+// PrettyPrint writes a pretty-printed representation of RegisterAlias to the
+// given writer.
+func (ra RegisterAlias) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sAlias:  %s\n", prefix, ra.Alias)
+	fmt.Fprintf(w, "%sEpochs: %d\n", prefix, ra.Epochs)
+}
+
+// PrettyType returns a representation of RegisterAlias that can be used for
+// pretty printing.
+func (ra RegisterAlias) PrettyType() (interface{}, error) {
+	return ra, nil
+}
+
+// NewRegisterAliasTx creates a new address alias registration transaction.
+func NewRegisterAliasTx(nonce uint64, fee *transaction.Fee, reg *RegisterAlias) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodRegisterAlias, reg)
+}
+
+// ResolveAddress resolves the given account address or address alias into an
+// account address.
+//
+// Strings that parse as Bech32-encoded account addresses are returned as is,
+// anything else is treated as an address alias and looked up at the given
+// block height.
+func ResolveAddress(ctx context.Context, backend Backend, height int64, addrOrAlias string) (Address, error) {
+	var addr Address
+	if err := addr.UnmarshalText([]byte(addrOrAlias)); err == nil {
+		return addr, nil
+	}
+	if err := ValidateAlias(addrOrAlias); err != nil {
+		return Address{}, fmt.Errorf("staking: '%s' is neither an address nor an alias: %w", addrOrAlias, err)
+	}
+
+	info, err := backend.Alias(ctx, &AliasQuery{Height: height, Alias: addrOrAlias})
+	if err != nil {
+		return Address{}, err
+	}
+	return info.Address, nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+func TestValidateAlias(t *testing.T) {
+	require := require.New(t)
+
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	for _, tc := range []struct {
+		alias string
+		valid bool
+	}{
+		{"exchange", true},
+		{"my-exchange-1", true},
+		{"abc", true},
+		{strings.Repeat("z", MaxAliasLength), true},
+		{"ab", false},
+		{strings.Repeat("z", MaxAliasLength+1), false},
+		{"Exchange", false},
+		{"1exchange", false},
+		{"exchange-", false},
+		{"my_exchange", false},
+		{"my.exchange", false},
+		{"oasis1exchange", false},
+		{addr.String(), false},
+		{hex.EncodeToString(addr[:]), false},
+		{"aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+	} {
+		err := ValidateAlias(tc.alias)
+		if tc.valid {
+			require.NoError(err, "alias '%s' should be valid", tc.alias)
+		} else {
+			require.Error(err, "alias '%s' should be invalid", tc.alias)
+			require.True(errors.Is(err, ErrInvalidAlias), "alias '%s' should fail with ErrInvalidAlias", tc.alias)
+		}
+	}
+}
+
+func TestSanityCheckAlias(t *testing.T) {
+	require := require.New(t)
+
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	err := SanityCheckAlias(10, "exchange", &AliasInfo{Address: addr, Expiration: 11})
+	require.NoError(err, "valid alias registration should pass the sanity check")
+
+	err = SanityCheckAlias(10, "exchange", &AliasInfo{Address: addr, Expiration: 10})
+	require.Error(err, "expired alias registration should fail the sanity check")
+
+	err = SanityCheckAlias(10, "exchange", &AliasInfo{Address: CommonPoolAddress, Expiration: 11})
+	require.Error(err, "alias registration with a reserved address should fail the sanity check")
+
+	err = SanityCheckAlias(10, addr.String(), &AliasInfo{Address: addr, Expiration: 11})
+	require.Error(err, "alias colliding with an address should fail the sanity check")
+
+	err = SanityCheckAlias(10, "exchange", nil)
+	require.Error(err, "nil alias registration should fail the sanity check")
+}
+
+type aliasBackend struct {
+	Backend
+
+	aliases map[string]*AliasInfo
+}
+
+func (b *aliasBackend) Alias(ctx context.Context, query *AliasQuery) (*AliasInfo, error) {
+	info, ok := b.aliases[query.Alias]
+	if !ok {
+		return nil, ErrAliasNotFound
+	}
+	return info, nil
+}
+
+func TestResolveAddress(t *testing.T) {
+	require := require.New(t)
+
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	backend := &aliasBackend{
+		aliases: map[string]*AliasInfo{
+			"exchange": {Address: addr2, Expiration: 100},
+		},
+	}
+	ctx := context.Background()
+
+	resolved, err := ResolveAddress(ctx, backend, 1, addr.String())
+	require.NoError(err, "resolving an address should work")
+	require.Equal(addr, resolved, "addresses should resolve to themselves")
+
+	resolved, err = ResolveAddress(ctx, backend, 1, "exchange")
+	require.NoError(err, "resolving a registered alias should work")
+	require.Equal(addr2, resolved, "aliases should resolve to the registered address")
+
+	_, err = ResolveAddress(ctx, backend, 1, "unknown")
+	require.Equal(ErrAliasNotFound, err, "resolving an unknown alias should fail")
+
+	_, err = ResolveAddress(ctx, backend, 1, "oasis1invalid")
+	require.True(errors.Is(err, ErrInvalidAlias), "resolving a malformed address should fail")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
@@ -66,6 +67,16 @@ var (
 	// exceed the maximum allowed number.
 	ErrTooManyAllowances = errors.New(ModuleName, 7, "staking: too many allowances")
 
+	// ErrInvalidAlias is the error returned when an address alias is malformed.
+	ErrInvalidAlias = errors.New(ModuleName, 8, "staking: invalid alias")
+
+	// ErrAliasNotFound is the error returned when an address alias is not registered.
+	ErrAliasNotFound = errors.New(ModuleName, 9, "staking: alias not found")
+
+	// ErrAliasTaken is the error returned when an address alias is already registered
+	// to a different account.
+	ErrAliasTaken = errors.New(ModuleName, 10, "staking: alias already taken")
+
 	// MethodTransfer is the method name for transfers.
 	MethodTransfer = transaction.NewMethodName(ModuleName, "Transfer", Transfer{})
 	// MethodBurn is the method name for burns.
@@ -80,6 +91,10 @@ var (
 	MethodAllow = transaction.NewMethodName(ModuleName, "Allow", Allow{})
 	// MethodWithdraw is the method name for
 	MethodWithdraw = transaction.NewMethodName(ModuleName, "Withdraw", Withdraw{})
+	// MethodMint is the method name for mints.
+	MethodMint = transaction.NewMethodName(ModuleName, "Mint", Mint{})
+	// MethodRegisterAlias is the method name for address alias registrations.
+	MethodRegisterAlias = transaction.NewMethodName(ModuleName, "RegisterAlias", RegisterAlias{})
 
 	// Methods is the list of all methods supported by the staking backend.
 	Methods = []transaction.MethodName{
@@ -90,6 +105,8 @@ var (
 		MethodAmendCommissionSchedule,
 		MethodAllow,
 		MethodWithdraw,
+		MethodMint,
+		MethodRegisterAlias,
 	}
 
 	_ prettyprint.PrettyPrinter = (*Transfer)(nil)
@@ -99,6 +116,7 @@ var (
 	_ prettyprint.PrettyPrinter = (*AmendCommissionSchedule)(nil)
 	_ prettyprint.PrettyPrinter = (*Allow)(nil)
 	_ prettyprint.PrettyPrinter = (*Withdraw)(nil)
+	_ prettyprint.PrettyPrinter = (*Mint)(nil)
 	_ prettyprint.PrettyPrinter = (*SharePool)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeThreshold)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeAccumulator)(nil)
@@ -139,6 +157,10 @@ type Backend interface {
 	// (delegator).
 	Delegations(ctx context.Context, query *OwnerQuery) (map[Address]*Delegation, error)
 
+	// DelegationsTo returns the list of delegations to the given owner
+	// (escrow account).
+	DelegationsTo(ctx context.Context, query *OwnerQuery) (map[Address]*Delegation, error)
+
 	// DebondingDelegations returns the list of debonding delegations for
 	// the given owner (delegator).
 	DebondingDelegations(ctx context.Context, query *OwnerQuery) (map[Address][]*DebondingDelegation, error)
@@ -146,6 +168,11 @@ type Backend interface {
 	// Allowance looks up the allowance for the given owner/beneficiary combination.
 	Allowance(ctx context.Context, query *AllowanceQuery) (*quantity.Quantity, error)
 
+	// Alias looks up the registration record of the given address alias.
+	//
+	// ErrAliasNotFound is returned if the alias is not registered.
+	Alias(ctx context.Context, query *AliasQuery) (*AliasInfo, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
@@ -158,6 +185,14 @@ type Backend interface {
 	// WatchEvents returns a channel that produces a stream of Events.
 	WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error)
 
+	// WatchFilteredEvents returns a channel that produces a stream of Events
+	// matching the given filter.
+	WatchFilteredEvents(ctx context.Context, filter *EventFilter) (<-chan *Event, pubsub.ClosableSubscription, error)
+
+	// GetRewardEvents returns the reward and slash events emitted in the
+	// given (inclusive) block height range, optionally filtered by account.
+	GetRewardEvents(ctx context.Context, query *RewardEventsQuery) ([]*Event, error)
+
 	// Cleanup cleans up the backend.
 	Cleanup()
 }
@@ -181,6 +216,20 @@ type AllowanceQuery struct {
 	Beneficiary Address `json:"beneficiary"`
 }
 
+// MaxRewardEventsQueryRange is the maximum number of blocks that can be
+// covered by a single reward events query.
+const MaxRewardEventsQueryRange = 1000
+
+// RewardEventsQuery is a reward events query.
+type RewardEventsQuery struct {
+	// FromHeight is the first block height (inclusive) to query.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the last block height (inclusive) to query.
+	ToHeight int64 `json:"to_height"`
+	// Account is an optional account to filter the events by.
+	Account *Address `json:"account,omitempty"`
+}
+
 // TransferEvent is the event emitted when stake is transferred, either by a
 // call to Transfer or Withdraw.
 type TransferEvent struct {
@@ -195,6 +244,13 @@ type BurnEvent struct {
 	Amount quantity.Quantity `json:"amount"`
 }
 
+// MintEvent is the event emitted when stake is created via a call to Mint.
+type MintEvent struct {
+	Minter Address           `json:"minter"`
+	To     Address           `json:"to"`
+	Amount quantity.Quantity `json:"amount"`
+}
+
 // EscrowEvent is an escrow event.
 type EscrowEvent struct {
 	Add     *AddEscrowEvent     `json:"add,omitempty"`
@@ -204,21 +260,231 @@ type EscrowEvent struct {
 
 // Event signifies a staking event, returned via GetEvents.
 type Event struct {
-	Height int64     `json:"height,omitempty"`
-	TxHash hash.Hash `json:"tx_hash,omitempty"`
+	Height int64        `json:"height,omitempty"`
+	TxHash hash.Hash    `json:"tx_hash,omitempty"`
+	Index  events.Index `json:"index"`
 
 	Transfer        *TransferEvent        `json:"transfer,omitempty"`
 	Burn            *BurnEvent            `json:"burn,omitempty"`
 	Escrow          *EscrowEvent          `json:"escrow,omitempty"`
 	AllowanceChange *AllowanceChangeEvent `json:"allowance_change,omitempty"`
+	Reward          *RewardEvent          `json:"reward,omitempty"`
+	Slash           *SlashEvent           `json:"slash,omitempty"`
+	Mint            *MintEvent            `json:"mint,omitempty"`
+	Alias           *AliasEvent           `json:"alias,omitempty"`
+}
+
+// AffectsAddress returns true iff the event affects the balance or allowances of the
+// account with the given address.
+func (e *Event) AffectsAddress(addr Address) bool {
+	switch {
+	case e.Transfer != nil:
+		return e.Transfer.From.Equal(addr) || e.Transfer.To.Equal(addr)
+	case e.Burn != nil:
+		return e.Burn.Owner.Equal(addr)
+	case e.Escrow != nil:
+		switch {
+		case e.Escrow.Add != nil:
+			return e.Escrow.Add.Owner.Equal(addr) || e.Escrow.Add.Escrow.Equal(addr)
+		case e.Escrow.Take != nil:
+			return e.Escrow.Take.Owner.Equal(addr)
+		case e.Escrow.Reclaim != nil:
+			return e.Escrow.Reclaim.Owner.Equal(addr) || e.Escrow.Reclaim.Escrow.Equal(addr)
+		}
+	case e.AllowanceChange != nil:
+		return e.AllowanceChange.Owner.Equal(addr) || e.AllowanceChange.Beneficiary.Equal(addr)
+	case e.Reward != nil:
+		return e.Reward.Account.Equal(addr)
+	case e.Slash != nil:
+		return e.Slash.Account.Equal(addr)
+	case e.Mint != nil:
+		return e.Mint.To.Equal(addr)
+	case e.Alias != nil:
+		return e.Alias.Owner.Equal(addr)
+	}
+	return false
+}
+
+// Kind returns the kind of the event.
+func (e *Event) Kind() EventKind {
+	switch {
+	case e.Transfer != nil:
+		return EventKindTransfer
+	case e.Burn != nil:
+		return EventKindBurn
+	case e.Escrow != nil:
+		return EventKindEscrow
+	case e.AllowanceChange != nil:
+		return EventKindAllowanceChange
+	case e.Reward != nil:
+		return EventKindReward
+	case e.Slash != nil:
+		return EventKindSlash
+	case e.Mint != nil:
+		return EventKindMint
+	case e.Alias != nil:
+		return EventKindAlias
+	default:
+		return EventKindInvalid
+	}
+}
+
+// EventKind is the kind of a staking event.
+type EventKind uint8
+
+const (
+	EventKindInvalid         EventKind = 0
+	EventKindTransfer        EventKind = 1
+	EventKindBurn            EventKind = 2
+	EventKindEscrow          EventKind = 3
+	EventKindAllowanceChange EventKind = 4
+	EventKindReward          EventKind = 5
+	EventKindSlash           EventKind = 6
+	EventKindMint            EventKind = 7
+	EventKindAlias           EventKind = 8
+
+	EventKindMax = EventKindAlias
+
+	EventKindTransferName        = "transfer"
+	EventKindBurnName            = "burn"
+	EventKindEscrowName          = "escrow"
+	EventKindAllowanceChangeName = "allowance_change"
+	EventKindRewardName          = "reward"
+	EventKindSlashName           = "slash"
+	EventKindMintName            = "mint"
+	EventKindAliasName           = "alias"
+)
+
+// String returns the string representation of an EventKind.
+func (k EventKind) String() string {
+	switch k {
+	case EventKindTransfer:
+		return EventKindTransferName
+	case EventKindBurn:
+		return EventKindBurnName
+	case EventKindEscrow:
+		return EventKindEscrowName
+	case EventKindAllowanceChange:
+		return EventKindAllowanceChangeName
+	case EventKindReward:
+		return EventKindRewardName
+	case EventKindSlash:
+		return EventKindSlashName
+	case EventKindMint:
+		return EventKindMintName
+	case EventKindAlias:
+		return EventKindAliasName
+	default:
+		return "[unknown event kind]"
+	}
+}
+
+// MarshalText encodes an EventKind into text form.
+func (k EventKind) MarshalText() ([]byte, error) {
+	if k == EventKindInvalid || k > EventKindMax {
+		return nil, fmt.Errorf("%w: invalid event kind: %d", ErrInvalidArgument, k)
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a text slice into an EventKind.
+func (k *EventKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case EventKindTransferName:
+		*k = EventKindTransfer
+	case EventKindBurnName:
+		*k = EventKindBurn
+	case EventKindEscrowName:
+		*k = EventKindEscrow
+	case EventKindAllowanceChangeName:
+		*k = EventKindAllowanceChange
+	case EventKindRewardName:
+		*k = EventKindReward
+	case EventKindSlashName:
+		*k = EventKindSlash
+	case EventKindMintName:
+		*k = EventKindMint
+	case EventKindAliasName:
+		*k = EventKindAlias
+	default:
+		return fmt.Errorf("%w: invalid event kind: %s", ErrInvalidArgument, string(text))
+	}
+	return nil
+}
+
+// EventFilter is a staking event filter.
+type EventFilter struct {
+	// Addresses is an optional set of accounts. If non-empty, only events
+	// affecting at least one of the given accounts match the filter.
+	Addresses []Address `json:"addresses,omitempty"`
+
+	// Kinds is an optional set of event kinds. If non-empty, only events of
+	// one of the given kinds match the filter.
+	Kinds []EventKind `json:"kinds,omitempty"`
+}
+
+// Matches returns true iff the event matches the filter.
+func (f *EventFilter) Matches(ev *Event) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.Kinds) > 0 {
+		kind := ev.Kind()
+		var found bool
+		for _, k := range f.Kinds {
+			if k == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Addresses) > 0 {
+		for _, addr := range f.Addresses {
+			if ev.AffectsAddress(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
 }
 
+const (
+	// EscrowEventVersionLegacy is the version of escrow events which only include token amounts.
+	EscrowEventVersionLegacy = 0
+	// EscrowEventVersionShares is the version of escrow events which also include share amounts
+	// and the state of the affected escrow pool after the operation.
+	EscrowEventVersionShares = 1
+)
+
 // AddEscrowEvent is the event emitted when stake is transferred into an escrow
 // account.
 type AddEscrowEvent struct {
+	// V is the event schema version. Fields other than Owner, Escrow and Amount are only
+	// present in events with version EscrowEventVersionShares or later.
+	V uint16 `json:"v,omitempty"`
+
 	Owner  Address           `json:"owner"`
 	Escrow Address           `json:"escrow"`
 	Amount quantity.Quantity `json:"amount"`
+
+	// NewShares is the amount of shares minted in the active escrow pool.
+	NewShares quantity.Quantity `json:"new_shares,omitempty"`
+	// ActiveBalance is the balance of the active escrow pool after the operation.
+	ActiveBalance quantity.Quantity `json:"active_balance,omitempty"`
+	// ActiveShares is the total amount of shares in the active escrow pool after the operation.
+	ActiveShares quantity.Quantity `json:"active_shares,omitempty"`
+}
+
+// HasShares returns true iff the event includes share amounts.
+func (e *AddEscrowEvent) HasShares() bool {
+	return e.V >= EscrowEventVersionShares
 }
 
 // TakeEscrowEvent is the event emitted when stake is taken from an escrow
@@ -231,9 +497,26 @@ type TakeEscrowEvent struct {
 // ReclaimEscrowEvent is the event emitted when stake is reclaimed from an
 // escrow account back into owner's general account.
 type ReclaimEscrowEvent struct {
+	// V is the event schema version. Fields other than Owner, Escrow and Amount are only
+	// present in events with version EscrowEventVersionShares or later.
+	V uint16 `json:"v,omitempty"`
+
 	Owner  Address           `json:"owner"`
 	Escrow Address           `json:"escrow"`
 	Amount quantity.Quantity `json:"amount"`
+
+	// Shares is the amount of shares burned in the debonding escrow pool.
+	Shares quantity.Quantity `json:"shares,omitempty"`
+	// DebondingBalance is the balance of the debonding escrow pool after the operation.
+	DebondingBalance quantity.Quantity `json:"debonding_balance,omitempty"`
+	// DebondingShares is the total amount of shares in the debonding escrow pool after the
+	// operation.
+	DebondingShares quantity.Quantity `json:"debonding_shares,omitempty"`
+}
+
+// HasShares returns true iff the event includes share amounts.
+func (e *ReclaimEscrowEvent) HasShares() bool {
+	return e.V >= EscrowEventVersionShares
 }
 
 // AllowanceChangeEvent is the event emitted when allowance is changed for a beneficiary.
@@ -245,6 +528,23 @@ type AllowanceChangeEvent struct { // nolint: maligned
 	AmountChange quantity.Quantity `json:"amount_change"`
 }
 
+// RewardEvent is the event emitted when a reward is disbursed to an escrow
+// account. The amount includes any commission.
+type RewardEvent struct {
+	Account Address             `json:"account"`
+	Epoch   epochtime.EpochTime `json:"epoch"`
+	Amount  quantity.Quantity   `json:"amount"`
+	Reason  RewardReason        `json:"reason"`
+}
+
+// SlashEvent is the event emitted when an escrow account is slashed.
+type SlashEvent struct {
+	Account Address             `json:"account"`
+	Epoch   epochtime.EpochTime `json:"epoch"`
+	Amount  quantity.Quantity   `json:"amount"`
+	Reason  SlashReason         `json:"reason"`
+}
+
 // Transfer is a stake transfer.
 type Transfer struct {
 	To     Address           `json:"to"`
@@ -296,6 +596,33 @@ func NewBurnTx(nonce uint64, fee *transaction.Fee, burn *Burn) *transaction.Tran
 	return transaction.NewTransaction(nonce, fee, MethodBurn, burn)
 }
 
+// Mint is a stake mint (creation).
+type Mint struct {
+	To     Address           `json:"to"`
+	Amount quantity.Quantity `json:"amount"`
+}
+
+// PrettyPrint writes a pretty-printed representation of Mint to the given
+// writer.
+func (m Mint) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sTo:     %s\n", prefix, m.To)
+
+	fmt.Fprintf(w, "%sAmount: ", prefix)
+	token.PrettyPrintAmount(ctx, m.Amount, w)
+	fmt.Fprintln(w)
+}
+
+// PrettyType returns a representation of Mint that can be used for pretty
+// printing.
+func (m Mint) PrettyType() (interface{}, error) {
+	return m, nil
+}
+
+// NewMintTx creates a new mint transaction.
+func NewMintTx(nonce uint64, fee *transaction.Fee, mint *Mint) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodMint, mint)
+}
+
 // Escrow is a stake escrow.
 type Escrow struct {
 	Account Address           `json:"account"`
@@ -327,14 +654,31 @@ func NewAddEscrowTx(nonce uint64, fee *transaction.Fee, escrow *Escrow) *transac
 type ReclaimEscrow struct {
 	Account Address           `json:"account"`
 	Shares  quantity.Quantity `json:"shares"`
+
+	// Tranches is the number of equal tranches the reclaimed stake is split
+	// into, each debonding one epoch after the previous one. Zero and one
+	// both mean that all of the stake debonds at once.
+	Tranches uint16 `json:"tranches,omitempty"`
+}
+
+// NumTranches returns the number of debonding tranches of the reclamation.
+func (re *ReclaimEscrow) NumTranches() uint16 {
+	if re.Tranches == 0 {
+		return 1
+	}
+	return re.Tranches
 }
 
 // PrettyPrint writes a pretty-printed representation of ReclaimEscrow to the
 // given writer.
 func (re ReclaimEscrow) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
-	fmt.Fprintf(w, "%sAccount: %s\n", prefix, re.Account)
+	fmt.Fprintf(w, "%sAccount:  %s\n", prefix, re.Account)
 
-	fmt.Fprintf(w, "%sShares:  %s\n", prefix, re.Shares)
+	fmt.Fprintf(w, "%sShares:   %s\n", prefix, re.Shares)
+
+	if re.Tranches > 1 {
+		fmt.Fprintf(w, "%sTranches: %d\n", prefix, re.Tranches)
+	}
 }
 
 // PrettyType returns a representation of Transfer that can be used for pretty
@@ -444,8 +788,8 @@ func (p SharePool) PrettyType() (interface{}, error) {
 	return p, nil
 }
 
-// sharesForStake computes the amount of shares for the given amount of base units.
-func (p *SharePool) sharesForStake(amount *quantity.Quantity) (*quantity.Quantity, error) {
+// SharesForStake computes the amount of shares for the given amount of base units.
+func (p *SharePool) SharesForStake(amount *quantity.Quantity) (*quantity.Quantity, error) {
 	if p.TotalShares.IsZero() {
 		// No existing shares, exchange rate is 1:1.
 		return amount.Clone(), nil
@@ -476,7 +820,7 @@ func (p *SharePool) sharesForStake(amount *quantity.Quantity) (*quantity.Quantit
 // Deposit moves stake into the combined balance, raising the shares.
 // If an error occurs, the pool and affected accounts are left in an invalid state.
 func (p *SharePool) Deposit(shareDst, stakeSrc, baseUnitsAmount *quantity.Quantity) error {
-	shares, err := p.sharesForStake(baseUnitsAmount)
+	shares, err := p.SharesForStake(baseUnitsAmount)
 	if err != nil {
 		return err
 	}
@@ -496,8 +840,8 @@ func (p *SharePool) Deposit(shareDst, stakeSrc, baseUnitsAmount *quantity.Quanti
 	return nil
 }
 
-// stakeForShares computes the amount of base units for the given amount of shares.
-func (p *SharePool) stakeForShares(amount *quantity.Quantity) (*quantity.Quantity, error) {
+// StakeForShares computes the amount of base units for the given amount of shares.
+func (p *SharePool) StakeForShares(amount *quantity.Quantity) (*quantity.Quantity, error) {
 	if amount.IsZero() || p.Balance.IsZero() || p.TotalShares.IsZero() {
 		// No existing shares or no balance means no base units.
 		return quantity.NewQuantity(), nil
@@ -522,7 +866,7 @@ func (p *SharePool) stakeForShares(amount *quantity.Quantity) (*quantity.Quantit
 // Withdraw moves stake out of the combined balance, reducing the shares.
 // If an error occurs, the pool and affected accounts are left in an invalid state.
 func (p *SharePool) Withdraw(stakeDst, shareSrc, shareAmount *quantity.Quantity) error {
-	baseUnits, err := p.stakeForShares(shareAmount)
+	baseUnits, err := p.StakeForShares(shareAmount)
 	if err != nil {
 		return err
 	}
@@ -957,6 +1301,17 @@ type Genesis struct {
 	// DebondingDelegations is a nested map of staking delegations of the form:
 	// DEBONDING-DELEGATEE-ACCOUNT-ADDRESS: DEBONDING-DELEGATOR-ACCOUNT-ADDRESS: list of DEBONDING-DELEGATIONs.
 	DebondingDelegations map[Address]map[Address][]*DebondingDelegation `json:"debonding_delegations,omitempty"`
+
+	// Aliases is a map of registered address aliases.
+	Aliases map[string]*AliasInfo `json:"aliases,omitempty"`
+}
+
+// Denomination returns the denomination of the token described by the genesis state.
+func (g *Genesis) Denomination() *token.Denomination {
+	return &token.Denomination{
+		Symbol:   g.TokenSymbol,
+		Exponent: g.TokenValueExponent,
+	}
 }
 
 // ConsensusParameters are the staking consensus parameters.
@@ -969,7 +1324,13 @@ type ConsensusParameters struct { // nolint: maligned
 	CommissionScheduleRules           CommissionScheduleRules             `json:"commission_schedule_rules,omitempty"`
 	Slashing                          map[SlashReason]Slash               `json:"slashing,omitempty"`
 	GasCosts                          transaction.Costs                   `json:"gas_costs,omitempty"`
-	MinDelegationAmount               quantity.Quantity                   `json:"min_delegation"`
+
+	// MinDelegationAmount is the minimum amount of stake that can be escrowed in a single
+	// delegation.
+	MinDelegationAmount quantity.Quantity `json:"min_delegation"`
+	// MinTransferAmount is the minimum amount of stake that can be transferred in a single
+	// transfer.
+	MinTransferAmount quantity.Quantity `json:"min_transfer,omitempty"`
 
 	DisableTransfers       bool             `json:"disable_transfers,omitempty"`
 	DisableDelegation      bool             `json:"disable_delegation,omitempty"`
@@ -978,6 +1339,11 @@ type ConsensusParameters struct { // nolint: maligned
 	// MaxAllowances is the maximum number of allowances an account can have. Zero means disabled.
 	MaxAllowances uint32 `json:"max_allowances,omitempty"`
 
+	// MintAllowlist is the set of addresses that are allowed to mint new stake. Minting is disabled
+	// if the allowlist is empty. This should only be configured on test networks (e.g., to fund
+	// faucets).
+	MintAllowlist map[Address]bool `json:"mint_allowlist,omitempty"`
+
 	// FeeSplitWeightPropose is the proportion of block fee portions that go to the proposer.
 	FeeSplitWeightPropose quantity.Quantity `json:"fee_split_weight_propose"`
 	// FeeSplitWeightVote is the proportion of block fee portions that go to the validator that votes.
@@ -991,6 +1357,21 @@ type ConsensusParameters struct { // nolint: maligned
 	// RewardFactorBlockProposed is the factor for a reward distributed per block
 	// to the entity that proposed the block.
 	RewardFactorBlockProposed quantity.Quantity `json:"reward_factor_block_proposed"`
+
+	// AliasRegistrationFee is the per-epoch fee (in base units) charged for registering or
+	// renewing an address alias. Fees are transferred into the common pool.
+	AliasRegistrationFee quantity.Quantity `json:"alias_registration_fee,omitempty"`
+	// MaxAliasRegistrationEpochs is the maximum number of epochs an address alias can be
+	// registered for in advance. Zero means that alias registration is disabled.
+	MaxAliasRegistrationEpochs epochtime.EpochTime `json:"max_alias_registration_epochs,omitempty"`
+
+	// MaxReclaimEscrowTranches is the maximum number of debonding tranches a single escrow
+	// reclamation can be split into. Zero means that splitting reclamations is disabled.
+	MaxReclaimEscrowTranches uint16 `json:"max_reclaim_escrow_tranches,omitempty"`
+
+	// MaxSponsoredFee is the maximum fee amount (in base units) that a sponsor can pay on behalf
+	// of the signer of a single transaction. Zero means that fee sponsorship is disabled.
+	MaxSponsoredFee quantity.Quantity `json:"max_sponsored_fee,omitempty"`
 }
 
 const (
@@ -1008,4 +1389,8 @@ const (
 	GasOpAllow transaction.Op = "allow"
 	// GasOpWithdraw is the gas operation identifier for withdraw.
 	GasOpWithdraw transaction.Op = "withdraw"
+	// GasOpMint is the gas operation identifier for mint.
+	GasOpMint transaction.Op = "mint"
+	// GasOpRegisterAlias is the gas operation identifier for register alias.
+	GasOpRegisterAlias transaction.Op = "register_alias"
 )
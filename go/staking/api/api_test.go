@@ -3,9 +3,14 @@ package api
 import (
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 )
 
 func TestConsensusParameters(t *testing.T) {
@@ -41,6 +46,17 @@ func TestConsensusParameters(t *testing.T) {
 		FeeSplitWeightNextPropose: mustInitQuantity(t, 0),
 	}
 	require.Error(degenerateFeeSplit.SanityCheck(), "consensus parameters with degenerate fee split should be invalid")
+
+	// Mint allowlist.
+	mintAllowlistParams := validThresholdsParams
+	mintAllowlistParams.MintAllowlist = map[Address]bool{
+		NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")): true,
+	}
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, false)
+	require.Error(mintAllowlistParams.SanityCheck(), "consensus parameters with a mint allowlist should be invalid on production networks")
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, true)
+	require.NoError(mintAllowlistParams.SanityCheck(), "consensus parameters with a mint allowlist should be valid on test networks")
+	viper.Set(cmdFlags.CfgDebugDontBlameOasis, false)
 }
 
 func TestThresholdKind(t *testing.T) {
@@ -209,3 +225,150 @@ func TestStakeAccumulator(t *testing.T) {
 	require.Error(err, "escrow account should no longer check out")
 	require.Equal(err, ErrInsufficientStake)
 }
+
+func TestEventAffectsAddress(t *testing.T) {
+	require := require.New(t)
+
+	var rtID common.Namespace
+	_ = rtID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	rtAddr := NewRuntimeAddress(rtID)
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	for _, tc := range []struct {
+		name     string
+		ev       *Event
+		expected bool
+	}{
+		{"Deposit", &Event{Transfer: &TransferEvent{From: addr, To: rtAddr}}, true},
+		{"Withdrawal", &Event{Transfer: &TransferEvent{From: rtAddr, To: addr}}, true},
+		{"OtherTransfer", &Event{Transfer: &TransferEvent{From: addr, To: addr2}}, false},
+		{"Burn", &Event{Burn: &BurnEvent{Owner: rtAddr}}, true},
+		{"OtherBurn", &Event{Burn: &BurnEvent{Owner: addr}}, false},
+		{"AddEscrow", &Event{Escrow: &EscrowEvent{Add: &AddEscrowEvent{Owner: rtAddr, Escrow: addr}}}, true},
+		{"TakeEscrow", &Event{Escrow: &EscrowEvent{Take: &TakeEscrowEvent{Owner: addr}}}, false},
+		{"ReclaimEscrow", &Event{Escrow: &EscrowEvent{Reclaim: &ReclaimEscrowEvent{Owner: addr, Escrow: rtAddr}}}, true},
+		{"AllowanceChange", &Event{AllowanceChange: &AllowanceChangeEvent{Owner: addr, Beneficiary: rtAddr}}, true},
+		{"Mint", &Event{Mint: &MintEvent{Minter: addr, To: rtAddr}}, true},
+		{"OtherMint", &Event{Mint: &MintEvent{Minter: rtAddr, To: addr}}, false},
+		{"Alias", &Event{Alias: &AliasEvent{Alias: "runtime", Owner: rtAddr}}, true},
+		{"OtherAlias", &Event{Alias: &AliasEvent{Alias: "other", Owner: addr}}, false},
+		{"Empty", &Event{}, false},
+	} {
+		require.Equal(tc.expected, tc.ev.AffectsAddress(rtAddr), tc.name)
+	}
+}
+
+func TestEventKind(t *testing.T) {
+	require := require.New(t)
+
+	for k := EventKindTransfer; k <= EventKindMax; k++ {
+		enc, err := k.MarshalText()
+		require.NoError(err, "MarshalText")
+
+		var d EventKind
+		err = d.UnmarshalText(enc)
+		require.NoError(err, "UnmarshalText")
+		require.Equal(k, d, "event kind should round-trip")
+	}
+
+	_, err := EventKindInvalid.MarshalText()
+	require.Error(err, "MarshalText should fail for an invalid event kind")
+
+	var d EventKind
+	err = d.UnmarshalText([]byte("invalid"))
+	require.Error(err, "UnmarshalText should fail for an unknown event kind")
+}
+
+func TestEventFilter(t *testing.T) {
+	require := require.New(t)
+
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr3 := NewAddress(signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	transfer := &Event{Transfer: &TransferEvent{From: addr, To: addr2}}
+	escrow := &Event{Escrow: &EscrowEvent{Add: &AddEscrowEvent{Owner: addr2, Escrow: addr3}}}
+	burn := &Event{Burn: &BurnEvent{Owner: addr3}}
+
+	require.Equal(EventKindTransfer, transfer.Kind())
+	require.Equal(EventKindEscrow, escrow.Kind())
+	require.Equal(EventKindBurn, burn.Kind())
+	require.Equal(EventKindInvalid, (&Event{}).Kind())
+
+	for _, tc := range []struct {
+		name     string
+		filter   *EventFilter
+		ev       *Event
+		expected bool
+	}{
+		{"Nil", nil, transfer, true},
+		{"Empty", &EventFilter{}, burn, true},
+		{"Address", &EventFilter{Addresses: []Address{addr}}, transfer, true},
+		{"OtherAddress", &EventFilter{Addresses: []Address{addr}}, escrow, false},
+		{"AddressSet", &EventFilter{Addresses: []Address{addr, addr3}}, escrow, true},
+		{"Kind", &EventFilter{Kinds: []EventKind{EventKindEscrow}}, escrow, true},
+		{"OtherKind", &EventFilter{Kinds: []EventKind{EventKindEscrow}}, transfer, false},
+		{"KindAndAddress", &EventFilter{Addresses: []Address{addr2}, Kinds: []EventKind{EventKindEscrow, EventKindBurn}}, escrow, true},
+		{"KindAndOtherAddress", &EventFilter{Addresses: []Address{addr2}, Kinds: []EventKind{EventKindEscrow, EventKindBurn}}, burn, false},
+	} {
+		require.Equal(tc.expected, tc.filter.Matches(tc.ev), tc.name)
+	}
+}
+
+func TestEscrowEventVersioning(t *testing.T) {
+	require := require.New(t)
+
+	type legacyAddEscrowEvent struct {
+		Owner  Address           `json:"owner"`
+		Escrow Address           `json:"escrow"`
+		Amount quantity.Quantity `json:"amount"`
+	}
+
+	addr := NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	// Legacy events should be encoded the same as before.
+	legacy := legacyAddEscrowEvent{Owner: addr, Escrow: addr2, Amount: *quantity.NewFromUint64(100)}
+	ev := AddEscrowEvent{Owner: addr, Escrow: addr2, Amount: *quantity.NewFromUint64(100)}
+	require.Equal(cbor.Marshal(legacy), cbor.Marshal(ev), "legacy event encoding should not change")
+
+	var decEv AddEscrowEvent
+	err := cbor.Unmarshal(cbor.Marshal(legacy), &decEv)
+	require.NoError(err, "Unmarshal legacy event")
+	require.False(decEv.HasShares(), "legacy event should not include shares")
+
+	// Events with shares should round-trip.
+	ev = AddEscrowEvent{
+		V:             EscrowEventVersionShares,
+		Owner:         addr,
+		Escrow:        addr2,
+		Amount:        *quantity.NewFromUint64(100),
+		NewShares:     *quantity.NewFromUint64(50),
+		ActiveBalance: *quantity.NewFromUint64(300),
+		ActiveShares:  *quantity.NewFromUint64(150),
+	}
+	decEv = AddEscrowEvent{}
+	err = cbor.Unmarshal(cbor.Marshal(ev), &decEv)
+	require.NoError(err, "Unmarshal event")
+	require.True(decEv.HasShares(), "event should include shares")
+	require.Zero(ev.NewShares.Cmp(&decEv.NewShares), "new shares should round-trip")
+	require.Zero(ev.ActiveBalance.Cmp(&decEv.ActiveBalance), "active balance should round-trip")
+	require.Zero(ev.ActiveShares.Cmp(&decEv.ActiveShares), "active shares should round-trip")
+
+	reclaimEv := ReclaimEscrowEvent{
+		V:                EscrowEventVersionShares,
+		Owner:            addr,
+		Escrow:           addr2,
+		Amount:           *quantity.NewFromUint64(100),
+		Shares:           *quantity.NewFromUint64(50),
+		DebondingBalance: *quantity.NewFromUint64(0),
+		DebondingShares:  *quantity.NewFromUint64(0),
+	}
+	var decReclaimEv ReclaimEscrowEvent
+	err = cbor.Unmarshal(cbor.Marshal(reclaimEv), &decReclaimEv)
+	require.NoError(err, "Unmarshal reclaim event")
+	require.True(decReclaimEv.HasShares(), "reclaim event should include shares")
+	require.Zero(reclaimEv.Shares.Cmp(&decReclaimEv.Shares), "shares should round-trip")
+	require.True(decReclaimEv.DebondingBalance.IsZero(), "debonding balance should round-trip")
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+// CommissionRateTemplateStep is a commission rate step with a start epoch
+// relative to the anchor epoch of the amendment.
+type CommissionRateTemplateStep struct {
+	// Number of epochs after the anchor epoch when the commission rate will
+	// go in effect.
+	Offset epochtime.EpochTime `json:"offset,omitempty"`
+	// Commission rate numerator. The rate is this value divided by CommissionRateDenominator.
+	Rate quantity.Quantity `json:"rate,omitempty"`
+}
+
+// CommissionRateBoundTemplateStep is a commission rate bound step with a start
+// epoch relative to the anchor epoch of the amendment.
+type CommissionRateBoundTemplateStep struct {
+	// Number of epochs after the anchor epoch when the commission rate bound
+	// will go in effect.
+	Offset epochtime.EpochTime `json:"offset,omitempty"`
+	// Minimum commission rate numerator. The minimum rate is this value divided by CommissionRateDenominator.
+	RateMin quantity.Quantity `json:"rate_min,omitempty"`
+	// Maximum commission rate numerator. The maximum rate is this value divided by CommissionRateDenominator.
+	RateMax quantity.Quantity `json:"rate_max,omitempty"`
+}
+
+// CommissionScheduleTemplate is a commission schedule with step start epochs
+// specified relative to an anchor epoch. It can be used to build identical
+// commission schedule amendments for multiple accounts.
+type CommissionScheduleTemplate struct {
+	// List of commission rates and their starting offsets.
+	Rates []CommissionRateTemplateStep `json:"rates,omitempty"`
+	// List of commission rate bounds and their starting offsets.
+	Bounds []CommissionRateBoundTemplateStep `json:"bounds,omitempty"`
+}
+
+// AnchorEpoch returns the earliest epoch after now that is aligned with the
+// commission rate change interval and at which an amendment built from the
+// template would be accepted.
+func (t *CommissionScheduleTemplate) AnchorEpoch(rules *CommissionScheduleRules, now epochtime.EpochTime) epochtime.EpochTime {
+	anchor := now + 1
+	if len(t.Bounds) > 0 {
+		anchor += rules.RateBoundLead
+	}
+
+	interval := rules.RateChangeInterval
+	if interval == 0 {
+		interval = 1
+	}
+	if rem := anchor % interval; rem != 0 {
+		anchor += interval - rem
+	}
+	return anchor
+}
+
+// Build builds a commission schedule amendment from the template, with all
+// steps anchored at the given epoch.
+func (t *CommissionScheduleTemplate) Build(rules *CommissionScheduleRules, anchor epochtime.EpochTime) (*AmendCommissionSchedule, error) {
+	if rules.RateChangeInterval == 0 {
+		return nil, fmt.Errorf("commission rate change interval not configured")
+	}
+
+	var amendment AmendCommissionSchedule
+	for _, step := range t.Rates {
+		amendment.Amendment.Rates = append(amendment.Amendment.Rates, CommissionRateStep{
+			Start: anchor + step.Offset,
+			Rate:  *step.Rate.Clone(),
+		})
+	}
+	for _, step := range t.Bounds {
+		amendment.Amendment.Bounds = append(amendment.Amendment.Bounds, CommissionRateBoundStep{
+			Start:   anchor + step.Offset,
+			RateMin: *step.RateMin.Clone(),
+			RateMax: *step.RateMax.Clone(),
+		})
+	}
+
+	if err := amendment.Amendment.validateComplexity(rules); err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	if err := amendment.Amendment.validateNondegenerate(rules); err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	return &amendment, nil
+}
+
+// BuildNext builds a commission schedule amendment from the template, with all
+// steps anchored at the earliest acceptable epoch after now (see AnchorEpoch).
+func (t *CommissionScheduleTemplate) BuildNext(rules *CommissionScheduleRules, now epochtime.EpochTime) (*AmendCommissionSchedule, error) {
+	amendment, err := t.Build(rules, t.AnchorEpoch(rules, now))
+	if err != nil {
+		return nil, err
+	}
+	if err = amendment.Amendment.validateAmendmentAcceptable(rules, now); err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	return amendment, nil
+}
+
+// TransactionSubmitter is the interface used for signing and submitting
+// transactions (e.g., the consensus backend's submission manager).
+type TransactionSubmitter interface {
+	// SignAndSubmitTx populates the nonce and fee fields in the transaction,
+	// signs the transaction with the passed signer and submits it.
+	SignAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error
+}
+
+// SubmitCommissionScheduleBulk signs and submits the given commission schedule
+// amendment for the accounts of all of the given signers. Nonces and fees are
+// determined separately for each account by the submitter.
+//
+// Submission continues in case some amendments fail to be submitted and the
+// returned error contains the failures for all affected accounts.
+func SubmitCommissionScheduleBulk(
+	ctx context.Context,
+	submitter TransactionSubmitter,
+	signers []signature.Signer,
+	amendment *AmendCommissionSchedule,
+) error {
+	var errs *multierror.Error
+	for _, signer := range signers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		addr := NewAddress(signer.Public())
+		tx := NewAmendCommissionScheduleTx(0, nil, amendment)
+		if err := submitter.SignAndSubmitTx(ctx, signer, tx); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("account %s: %w", addr, err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+func TestCommissionScheduleTemplate(t *testing.T) {
+	require := require.New(t)
+
+	rules := CommissionScheduleRules{
+		RateChangeInterval: 10,
+		RateBoundLead:      30,
+		MaxRateSteps:       4,
+		MaxBoundSteps:      4,
+	}
+
+	ratesOnly := CommissionScheduleTemplate{
+		Rates: []CommissionRateTemplateStep{
+			{Offset: 0, Rate: mustInitQuantity(t, 10_000)},
+			{Offset: 20, Rate: mustInitQuantity(t, 20_000)},
+		},
+	}
+	require.EqualValues(10, ratesOnly.AnchorEpoch(&rules, 0), "anchor should be the next aligned epoch")
+	require.EqualValues(20, ratesOnly.AnchorEpoch(&rules, 10), "anchor should be after the current epoch")
+	require.EqualValues(20, ratesOnly.AnchorEpoch(&rules, 15), "anchor should be aligned")
+
+	amendment, err := ratesOnly.Build(&rules, 40)
+	require.NoError(err, "Build")
+	require.Len(amendment.Amendment.Rates, 2)
+	require.EqualValues(40, amendment.Amendment.Rates[0].Start)
+	require.EqualValues(60, amendment.Amendment.Rates[1].Start)
+	require.Equal(mustInitQuantity(t, 20_000), amendment.Amendment.Rates[1].Rate)
+	require.Empty(amendment.Amendment.Bounds)
+
+	_, err = ratesOnly.Build(&rules, 45)
+	requireErrorShowDiagnostic(t, err, "unaligned anchor")
+
+	withBounds := CommissionScheduleTemplate{
+		Rates: []CommissionRateTemplateStep{
+			{Offset: 0, Rate: mustInitQuantity(t, 10_000)},
+		},
+		Bounds: []CommissionRateBoundTemplateStep{
+			{Offset: 0, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 50_000)},
+		},
+	}
+	require.EqualValues(40, withBounds.AnchorEpoch(&rules, 5), "anchor should respect the rate bound lead")
+	require.EqualValues(40, withBounds.AnchorEpoch(&rules, 9), "anchor should respect the rate bound lead")
+	require.EqualValues(50, withBounds.AnchorEpoch(&rules, 10), "anchor should respect the rate bound lead")
+
+	amendment, err = withBounds.BuildNext(&rules, 10)
+	require.NoError(err, "BuildNext")
+	require.EqualValues(50, amendment.Amendment.Rates[0].Start)
+	require.EqualValues(50, amendment.Amendment.Bounds[0].Start)
+
+	// Amendments built from a template should be acceptable for an existing schedule.
+	cs := CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 0, Rate: mustInitQuantity(t, 20_000)},
+		},
+		Bounds: []CommissionRateBoundStep{
+			{Start: 0, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 100_000)},
+		},
+	}
+	require.NoError(cs.AmendAndPruneAndValidate(&amendment.Amendment, &rules, 10), "AmendAndPruneAndValidate")
+
+	_, err = withBounds.Build(&rules, 20)
+	require.NoError(err, "Build should not check amendment acceptability")
+
+	tooComplex := CommissionScheduleTemplate{
+		Rates: []CommissionRateTemplateStep{
+			{Offset: 0}, {Offset: 10}, {Offset: 20}, {Offset: 30}, {Offset: 40},
+		},
+	}
+	_, err = tooComplex.BuildNext(&rules, 0)
+	requireErrorShowDiagnostic(t, err, "too many rate steps")
+
+	overUnity := CommissionScheduleTemplate{
+		Rates: []CommissionRateTemplateStep{
+			{Offset: 0, Rate: *CommissionRateDenominator.Clone()},
+		},
+	}
+	_ = overUnity.Rates[0].Rate.Add(mustInitQuantityP(t, 1))
+	_, err = overUnity.BuildNext(&rules, 0)
+	requireErrorShowDiagnostic(t, err, "rate over unity")
+
+	_, err = ratesOnly.Build(&CommissionScheduleRules{}, 10)
+	requireErrorShowDiagnostic(t, err, "rate change interval not configured")
+}
+
+type testSubmitter struct {
+	failFor map[signature.PublicKey]bool
+	txs     map[signature.PublicKey]*transaction.Transaction
+}
+
+func (s *testSubmitter) SignAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error {
+	if s.failFor[signer.Public()] {
+		return fmt.Errorf("submission failed")
+	}
+	s.txs[signer.Public()] = tx
+	return nil
+}
+
+func TestSubmitCommissionScheduleBulk(t *testing.T) {
+	require := require.New(t)
+
+	signers := []signature.Signer{
+		memorySigner.NewTestSigner("staking/api: commission bulk signer 1"),
+		memorySigner.NewTestSigner("staking/api: commission bulk signer 2"),
+		memorySigner.NewTestSigner("staking/api: commission bulk signer 3"),
+	}
+	amendment := &AmendCommissionSchedule{
+		Amendment: CommissionSchedule{
+			Rates: []CommissionRateStep{
+				{Start: 10, Rate: mustInitQuantity(t, 10_000)},
+			},
+		},
+	}
+
+	submitter := &testSubmitter{
+		txs: make(map[signature.PublicKey]*transaction.Transaction),
+	}
+	err := SubmitCommissionScheduleBulk(context.Background(), submitter, signers, amendment)
+	require.NoError(err, "SubmitCommissionScheduleBulk")
+	require.Len(submitter.txs, len(signers), "amendments should be submitted for all accounts")
+	for _, signer := range signers {
+		tx := submitter.txs[signer.Public()]
+		require.NotNil(tx, "amendment should be submitted")
+		require.Equal(MethodAmendCommissionSchedule, tx.Method)
+		require.Nil(tx.Fee, "fee should be left to the submitter")
+
+		var decoded AmendCommissionSchedule
+		require.NoError(cbor.Unmarshal(tx.Body, &decoded), "transaction body should decode")
+		require.Equal(*amendment, decoded)
+	}
+
+	// Failures for some accounts should not prevent submission for others.
+	submitter = &testSubmitter{
+		failFor: map[signature.PublicKey]bool{
+			signers[1].Public(): true,
+		},
+		txs: make(map[signature.PublicKey]*transaction.Transaction),
+	}
+	err = SubmitCommissionScheduleBulk(context.Background(), submitter, signers, amendment)
+	require.Error(err, "SubmitCommissionScheduleBulk should report failures")
+	require.Contains(err.Error(), NewAddress(signers[1].Public()).String(), "error should contain failed account")
+	require.Len(submitter.txs, 2, "amendments should be submitted for other accounts")
+
+	// Canceled context should abort submission.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	submitter = &testSubmitter{
+		txs: make(map[signature.PublicKey]*transaction.Transaction),
+	}
+	err = SubmitCommissionScheduleBulk(ctx, submitter, signers, amendment)
+	require.Equal(context.Canceled, err, "SubmitCommissionScheduleBulk should fail with canceled context")
+	require.Empty(submitter.txs, "no amendments should be submitted")
+}
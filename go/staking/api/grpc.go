@@ -32,19 +32,27 @@ var (
 	methodAccount = serviceName.NewMethod("Account", OwnerQuery{})
 	// methodDelegations is the Delegations method.
 	methodDelegations = serviceName.NewMethod("Delegations", OwnerQuery{})
+	// methodDelegationsTo is the DelegationsTo method.
+	methodDelegationsTo = serviceName.NewMethod("DelegationsTo", OwnerQuery{})
 	// methodDebondingDelegations is the DebondingDelegations method.
 	methodDebondingDelegations = serviceName.NewMethod("DebondingDelegations", OwnerQuery{})
 	// methodAllowance is the Allowance method.
 	methodAllowance = serviceName.NewMethod("Allowance", AllowanceQuery{})
+	// methodAlias is the Alias method.
+	methodAlias = serviceName.NewMethod("Alias", AliasQuery{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodConsensusParameters is the ConsensusParameters method.
 	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	// methodGetRewardEvents is the GetRewardEvents method.
+	methodGetRewardEvents = serviceName.NewMethod("GetRewardEvents", RewardEventsQuery{})
 
 	// methodWatchEvents is the WatchEvents method.
 	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil)
+	// methodWatchFilteredEvents is the WatchFilteredEvents method.
+	methodWatchFilteredEvents = serviceName.NewMethod("WatchFilteredEvents", EventFilter{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -87,6 +95,10 @@ var (
 				MethodName: methodDelegations.ShortName(),
 				Handler:    handlerDelegations,
 			},
+			{
+				MethodName: methodDelegationsTo.ShortName(),
+				Handler:    handlerDelegationsTo,
+			},
 			{
 				MethodName: methodDebondingDelegations.ShortName(),
 				Handler:    handlerDebondingDelegations,
@@ -95,6 +107,10 @@ var (
 				MethodName: methodAllowance.ShortName(),
 				Handler:    handlerAllowance,
 			},
+			{
+				MethodName: methodAlias.ShortName(),
+				Handler:    handlerAlias,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -107,6 +123,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetRewardEvents.ShortName(),
+				Handler:    handlerGetRewardEvents,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -114,6 +134,11 @@ var (
 				Handler:       handlerWatchEvents,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchFilteredEvents.ShortName(),
+				Handler:       handlerWatchFilteredEvents,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -317,6 +342,29 @@ func handlerDelegations( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerDelegationsTo( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query OwnerQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).DelegationsTo(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDelegationsTo.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).DelegationsTo(ctx, req.(*OwnerQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerDebondingDelegations( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -363,6 +411,29 @@ func handlerAllowance( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerAlias( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query AliasQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).Alias(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodAlias.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).Alias(ctx, req.(*AliasQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -432,6 +503,29 @@ func handlerGetEvents( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetRewardEvents( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query RewardEventsQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetRewardEvents(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRewardEvents.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetRewardEvents(ctx, req.(*RewardEventsQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerWatchEvents(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -460,6 +554,35 @@ func handlerWatchEvents(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchFilteredEvents(srv interface{}, stream grpc.ServerStream) error {
+	var filter EventFilter
+	if err := stream.RecvMsg(&filter); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchFilteredEvents(ctx, &filter)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new staking backend service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -541,6 +664,14 @@ func (c *stakingClient) Delegations(ctx context.Context, query *OwnerQuery) (map
 	return rsp, nil
 }
 
+func (c *stakingClient) DelegationsTo(ctx context.Context, query *OwnerQuery) (map[Address]*Delegation, error) {
+	var rsp map[Address]*Delegation
+	if err := c.conn.Invoke(ctx, methodDelegationsTo.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *stakingClient) DebondingDelegations(ctx context.Context, query *OwnerQuery) (map[Address][]*DebondingDelegation, error) {
 	var rsp map[Address][]*DebondingDelegation
 	if err := c.conn.Invoke(ctx, methodDebondingDelegations.FullName(), query, &rsp); err != nil {
@@ -557,6 +688,14 @@ func (c *stakingClient) Allowance(ctx context.Context, query *AllowanceQuery) (*
 	return &rsp, nil
 }
 
+func (c *stakingClient) Alias(ctx context.Context, query *AliasQuery) (*AliasInfo, error) {
+	var rsp AliasInfo
+	if err := c.conn.Invoke(ctx, methodAlias.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *stakingClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -581,6 +720,14 @@ func (c *stakingClient) GetEvents(ctx context.Context, height int64) ([]*Event,
 	return rsp, nil
 }
 
+func (c *stakingClient) GetRewardEvents(ctx context.Context, query *RewardEventsQuery) ([]*Event, error) {
+	var rsp []*Event
+	if err := c.conn.Invoke(ctx, methodGetRewardEvents.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *stakingClient) WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -616,6 +763,41 @@ func (c *stakingClient) WatchEvents(ctx context.Context) (<-chan *Event, pubsub.
 	return ch, sub, nil
 }
 
+func (c *stakingClient) WatchFilteredEvents(ctx context.Context, filter *EventFilter) (<-chan *Event, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchFilteredEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(filter); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *Event)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev Event
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *stakingClient) Cleanup() {
 }
 
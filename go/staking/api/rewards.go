@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -16,6 +17,70 @@ type RewardStep struct {
 	Scale quantity.Quantity   `json:"scale"`
 }
 
+// RewardReason is the reason why an account was rewarded.
+type RewardReason int
+
+const (
+	// RewardEpochElection is the reward for being elected into a committee
+	// in an epoch.
+	RewardEpochElection RewardReason = 0
+	// RewardEpochSigned is the reward for signing at least a threshold
+	// fraction of the blocks in an epoch.
+	RewardEpochSigned RewardReason = 1
+	// RewardBlockProposed is the reward for proposing a block.
+	RewardBlockProposed RewardReason = 2
+
+	// RewardEpochElectionName is the string representation of RewardEpochElection.
+	RewardEpochElectionName = "epoch-election"
+	// RewardEpochSignedName is the string representation of RewardEpochSigned.
+	RewardEpochSignedName = "epoch-signed"
+	// RewardBlockProposedName is the string representation of RewardBlockProposed.
+	RewardBlockProposedName = "block-proposed"
+)
+
+// String returns a string representation of a RewardReason.
+func (r RewardReason) String() string {
+	switch r {
+	case RewardEpochElection:
+		return RewardEpochElectionName
+	case RewardEpochSigned:
+		return RewardEpochSignedName
+	case RewardBlockProposed:
+		return RewardBlockProposedName
+	default:
+		return "[unknown reward reason]"
+	}
+}
+
+// MarshalText encodes a RewardReason into text form.
+func (r RewardReason) MarshalText() ([]byte, error) {
+	switch r {
+	case RewardEpochElection:
+		return []byte(RewardEpochElectionName), nil
+	case RewardEpochSigned:
+		return []byte(RewardEpochSignedName), nil
+	case RewardBlockProposed:
+		return []byte(RewardBlockProposedName), nil
+	default:
+		return nil, fmt.Errorf("invalid reward reason: %d", r)
+	}
+}
+
+// UnmarshalText decodes a text slice into a RewardReason.
+func (r *RewardReason) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case RewardEpochElectionName:
+		*r = RewardEpochElection
+	case RewardEpochSignedName:
+		*r = RewardEpochSigned
+	case RewardBlockProposedName:
+		*r = RewardBlockProposed
+	default:
+		return fmt.Errorf("invalid reward reason: %s", string(text))
+	}
+	return nil
+}
+
 func init() {
 	// Denominated in one millionth of a percent.
 	RewardAmountDenominator = quantity.NewQuantity()
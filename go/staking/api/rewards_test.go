@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardReason(t *testing.T) {
+	require := require.New(t)
+
+	// Test valid RewardReasons.
+	for _, k := range []RewardReason{
+		RewardEpochElection,
+		RewardEpochSigned,
+		RewardBlockProposed,
+	} {
+		enc, err := k.MarshalText()
+		require.NoError(err, "MarshalText")
+
+		var r RewardReason
+		err = r.UnmarshalText(enc)
+		require.NoError(err, "UnmarshalText")
+
+		require.Equal(k, r, "reward reason should round-trip")
+	}
+
+	// Test invalid RewardReasons.
+	rr := RewardReason(-1)
+	require.Equal("[unknown reward reason]", rr.String())
+	enc, err := rr.MarshalText()
+	require.Nil(enc, "MarshalText on invalid reward reason should be nil")
+	require.Error(err, "MarshalText on invalid reward reason should error")
+
+	err = rr.UnmarshalText([]byte("invalid reward reason"))
+	require.Error(err, "UnmarshalText on invalid reward reason should error")
+}
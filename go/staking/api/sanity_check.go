@@ -7,6 +7,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
 )
 
@@ -23,6 +24,14 @@ func (p *ConsensusParameters) SanityCheck() error {
 		}
 	}
 
+	// Minimum amounts.
+	if !p.MinDelegationAmount.IsValid() {
+		return fmt.Errorf("minimum delegation amount has invalid value")
+	}
+	if !p.MinTransferAmount.IsValid() {
+		return fmt.Errorf("minimum transfer amount has invalid value")
+	}
+
 	// Fee splits.
 	if !p.FeeSplitWeightPropose.IsValid() {
 		return fmt.Errorf("fee split weight propose has invalid value")
@@ -37,6 +46,26 @@ func (p *ConsensusParameters) SanityCheck() error {
 		return fmt.Errorf("fee split proportions are all zero")
 	}
 
+	// Mint allowlist.
+	if len(p.MintAllowlist) > 0 && !flags.DebugDontBlameOasis() {
+		return fmt.Errorf("mint allowlist is set but minting is only allowed on test networks (UNSAFE)")
+	}
+	for addr := range p.MintAllowlist {
+		if !addr.IsValid() {
+			return fmt.Errorf("mint allowlist contains invalid address: %s", addr)
+		}
+	}
+
+	// Address aliases.
+	if !p.AliasRegistrationFee.IsValid() {
+		return fmt.Errorf("alias registration fee has invalid value")
+	}
+
+	// Fee sponsorship.
+	if !p.MaxSponsoredFee.IsValid() {
+		return fmt.Errorf("maximum sponsored fee has invalid value")
+	}
+
 	return nil
 }
 
@@ -233,6 +262,23 @@ func SanityCheckAccountShares(
 	return nil
 }
 
+// SanityCheckAlias examines an address alias registration.
+func SanityCheckAlias(now epochtime.EpochTime, alias string, info *AliasInfo) error {
+	if err := ValidateAlias(alias); err != nil {
+		return fmt.Errorf("staking: sanity check failed: alias '%s': %w", alias, err)
+	}
+	if info == nil {
+		return fmt.Errorf("staking: sanity check failed: alias '%s': registration is nil", alias)
+	}
+	if !info.Address.IsValid() || info.Address.IsReserved() {
+		return fmt.Errorf("staking: sanity check failed: alias '%s': address %s is invalid", alias, info.Address)
+	}
+	if info.Expiration <= now {
+		return fmt.Errorf("staking: sanity check failed: alias '%s': registration expired at epoch %d", alias, info.Expiration)
+	}
+	return nil
+}
+
 // SanityCheck does basic sanity checking on the genesis state.
 func (g *Genesis) SanityCheck(now epochtime.EpochTime) error { // nolint: gocyclo
 	if err := g.Parameters.SanityCheck(); err != nil {
@@ -326,5 +372,12 @@ func (g *Genesis) SanityCheck(now epochtime.EpochTime) error { // nolint: gocycl
 		}
 	}
 
+	// Address aliases must be well-formed and must not have expired.
+	for alias, info := range g.Aliases {
+		if err := SanityCheckAlias(now, alias, info); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -13,9 +13,14 @@ type SlashReason int
 const (
 	// SlashDoubleSigning is slashing due to double signing.
 	SlashDoubleSigning SlashReason = 0
+	// SlashRuntimeEquivocation is slashing due to signing two different
+	// executor commitments for the same runtime round.
+	SlashRuntimeEquivocation SlashReason = 1
 
 	// SlashDoubleSigningName is the string representation of SlashDoubleSigning.
 	SlashDoubleSigningName = "double-signing"
+	// SlashRuntimeEquivocationName is the string representation of SlashRuntimeEquivocation.
+	SlashRuntimeEquivocationName = "runtime-equivocation"
 )
 
 // String returns a string representation of a SlashReason.
@@ -23,6 +28,8 @@ func (s SlashReason) String() string {
 	switch s {
 	case SlashDoubleSigning:
 		return SlashDoubleSigningName
+	case SlashRuntimeEquivocation:
+		return SlashRuntimeEquivocationName
 	default:
 		return "[unknown slash reason]"
 	}
@@ -33,6 +40,8 @@ func (s SlashReason) MarshalText() ([]byte, error) {
 	switch s {
 	case SlashDoubleSigning:
 		return []byte(SlashDoubleSigningName), nil
+	case SlashRuntimeEquivocation:
+		return []byte(SlashRuntimeEquivocationName), nil
 	default:
 		return nil, fmt.Errorf("invalid slash reason: %d", s)
 	}
@@ -43,6 +52,8 @@ func (s *SlashReason) UnmarshalText(text []byte) error {
 	switch string(text) {
 	case SlashDoubleSigningName:
 		*s = SlashDoubleSigning
+	case SlashRuntimeEquivocationName:
+		*s = SlashRuntimeEquivocation
 	default:
 		return fmt.Errorf("invalid slash reason: %s", string(text))
 	}
@@ -0,0 +1,208 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+var tokenSymbolRegexp = regexp.MustCompile(TokenSymbolRegexp)
+
+// Denomination is a token denomination.
+type Denomination struct {
+	// Symbol is the token's ticker symbol.
+	Symbol string `json:"symbol"`
+	// Exponent is the token's value base-10 exponent, i.e. one token equals
+	// 10^Exponent base units.
+	Exponent uint8 `json:"exponent"`
+	// DisplayName is the optional human readable name of the token.
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ValidateBasic performs basic denomination validity checks.
+func (d *Denomination) ValidateBasic() error {
+	if len(d.Symbol) == 0 || len(d.Symbol) > TokenSymbolMaxLength || !tokenSymbolRegexp.MatchString(d.Symbol) {
+		return ErrInvalidTokenSymbol
+	}
+	if d.Exponent > TokenValueExponentMaxValue {
+		return ErrInvalidTokenValueExponent
+	}
+	return nil
+}
+
+// String returns a string representation of the denomination.
+func (d *Denomination) String() string {
+	if d.DisplayName != "" {
+		return fmt.Sprintf("%s (%s)", d.Symbol, d.DisplayName)
+	}
+	return d.Symbol
+}
+
+// FormatAmount returns the given amount in base units formatted as a token
+// amount prefixed with the token's ticker symbol.
+func (d *Denomination) FormatAmount(amount quantity.Quantity) (string, error) {
+	tokenAmount, err := ConvertToTokenAmount(amount, d.Exponent)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s", d.Symbol, tokenAmount), nil
+}
+
+// ParseAmount parses the given token amount (e.g., 1.5) and returns the
+// corresponding amount in base units. The amount may optionally be prefixed
+// with the token's ticker symbol, as returned by FormatAmount.
+func (d *Denomination) ParseAmount(raw string) (*quantity.Quantity, error) {
+	if d.Exponent > TokenValueExponentMaxValue {
+		return nil, ErrInvalidTokenValueExponent
+	}
+
+	raw = strings.TrimSpace(raw)
+	if fields := strings.Fields(raw); len(fields) == 2 {
+		if fields[0] != d.Symbol {
+			return nil, fmt.Errorf("%w: unexpected symbol '%s'", ErrInvalidTokenAmount, fields[0])
+		}
+		raw = fields[1]
+	}
+
+	intPart, fracPart := raw, ""
+	if idx := strings.IndexByte(raw, '.'); idx >= 0 {
+		intPart, fracPart = raw[:idx], raw[idx+1:]
+	}
+	fracPart = strings.TrimRight(fracPart, "0")
+	switch {
+	case intPart == "":
+		return nil, fmt.Errorf("%w: missing integer part", ErrInvalidTokenAmount)
+	case len(fracPart) > int(d.Exponent):
+		return nil, fmt.Errorf("%w: too many decimals", ErrInvalidTokenAmount)
+	case strings.TrimLeft(intPart+fracPart, "0123456789") != "":
+		return nil, fmt.Errorf("%w: malformed amount '%s'", ErrInvalidTokenAmount, raw)
+	}
+
+	// Pad the fractional part to the token's value exponent so that the
+	// concatenation is the amount in base units.
+	digits := intPart + fracPart + strings.Repeat("0", int(d.Exponent)-len(fracPart))
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed amount '%s'", ErrInvalidTokenAmount, raw)
+	}
+	var q quantity.Quantity
+	if err := q.FromBigInt(n); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenAmount, err)
+	}
+	return &q, nil
+}
+
+// ContextWithDenomination returns a new context carrying the token's ticker
+// symbol and value base-10 exponent of the given denomination, as used when
+// pretty printing amounts.
+func ContextWithDenomination(ctx context.Context, d *Denomination) context.Context {
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, d.Symbol)
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, d.Exponent)
+	return ctx
+}
+
+// DenominationFromContext returns the denomination carried by the given
+// context, if any.
+func DenominationFromContext(ctx context.Context) (*Denomination, bool) {
+	symbol, ok := ctx.Value(prettyprint.ContextKeyTokenSymbol).(string)
+	if !ok {
+		return nil, false
+	}
+	exp, ok := ctx.Value(prettyprint.ContextKeyTokenValueExponent).(uint8)
+	if !ok {
+		return nil, false
+	}
+	return &Denomination{Symbol: symbol, Exponent: exp}, true
+}
+
+// DenominationRegistry is a registry of token denominations, keyed by the
+// token's ticker symbol.
+type DenominationRegistry struct {
+	sync.RWMutex
+
+	denominations map[string]Denomination
+}
+
+// Register registers a new denomination.
+func (r *DenominationRegistry) Register(d Denomination) error {
+	if err := d.ValidateBasic(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.denominations[d.Symbol]; exists {
+		return ErrDenominationExists
+	}
+	r.denominations[d.Symbol] = d
+	return nil
+}
+
+// Lookup looks up a denomination by the token's ticker symbol.
+func (r *DenominationRegistry) Lookup(symbol string) (*Denomination, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	d, ok := r.denominations[symbol]
+	if !ok {
+		return nil, ErrNoSuchDenomination
+	}
+	return &d, nil
+}
+
+// Denominations returns all registered denominations sorted by the token's
+// ticker symbol.
+func (r *DenominationRegistry) Denominations() []Denomination {
+	r.RLock()
+	defer r.RUnlock()
+
+	denominations := make([]Denomination, 0, len(r.denominations))
+	for _, d := range r.denominations {
+		denominations = append(denominations, d)
+	}
+	sort.Slice(denominations, func(i, j int) bool {
+		return denominations[i].Symbol < denominations[j].Symbol
+	})
+	return denominations
+}
+
+// ParseAmount parses the given amount prefixed with a registered token's
+// ticker symbol (e.g., CORE 1.5) and returns its denomination and the
+// corresponding amount in base units.
+func (r *DenominationRegistry) ParseAmount(raw string) (*Denomination, *quantity.Quantity, error) {
+	fields := strings.Fields(raw)
+	if len(fields) != 2 {
+		return nil, nil, fmt.Errorf("%w: missing symbol", ErrInvalidTokenAmount)
+	}
+	d, err := r.Lookup(fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	amount, err := d.ParseAmount(fields[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, amount, nil
+}
+
+// NewDenominationRegistry creates a new denomination registry with the given
+// denominations.
+func NewDenominationRegistry(denominations ...Denomination) (*DenominationRegistry, error) {
+	r := &DenominationRegistry{
+		denominations: make(map[string]Denomination),
+	}
+	for _, d := range denominations {
+		if err := r.Register(d); err != nil {
+			return nil, fmt.Errorf("staking/token: failed to register denomination %s: %w", d.Symbol, err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,145 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+func TestDenominationValidateBasic(t *testing.T) {
+	require := require.New(t)
+
+	for _, t := range []struct {
+		denomination Denomination
+		valid        bool
+	}{
+		{Denomination{Symbol: "CORE", Exponent: 9}, true},
+		{Denomination{Symbol: "CORE", Exponent: 9, DisplayName: "Oasis Core"}, true},
+		{Denomination{Symbol: "BIG", Exponent: TokenValueExponentMaxValue}, true},
+		{Denomination{Symbol: "", Exponent: 9}, false},
+		{Denomination{Symbol: "SOMETHINGLONG", Exponent: 9}, false},
+		{Denomination{Symbol: "core", Exponent: 9}, false},
+		{Denomination{Symbol: "TOOBIG", Exponent: TokenValueExponentMaxValue + 1}, false},
+	} {
+		err := t.denomination.ValidateBasic()
+		switch t.valid {
+		case true:
+			require.NoError(err, "ValidateBasic(%s)", t.denomination.Symbol)
+		case false:
+			require.Error(err, "ValidateBasic(%s)", t.denomination.Symbol)
+		}
+	}
+}
+
+func TestDenominationAmounts(t *testing.T) {
+	require := require.New(t)
+
+	d := &Denomination{Symbol: "CORE", Exponent: 9}
+	formatted, err := d.FormatAmount(*quantity.NewFromUint64(7999217230119682890))
+	require.NoError(err, "FormatAmount")
+	require.Equal("CORE 7999217230.11968289", formatted)
+
+	for _, t := range []struct {
+		raw    string
+		amount *quantity.Quantity
+	}{
+		{"7999217230.11968289", quantity.NewFromUint64(7999217230119682890)},
+		{"CORE 7999217230.11968289", quantity.NewFromUint64(7999217230119682890)},
+		{"100", quantity.NewFromUint64(100000000000)},
+		{"100.0", quantity.NewFromUint64(100000000000)},
+		{"0.000000001", quantity.NewFromUint64(1)},
+		{"0.0000000010", quantity.NewFromUint64(1)},
+		{"0", quantity.NewFromUint64(0)},
+		{"OTHER 1.0", nil},
+		{"0.0000000001", nil},
+		{"-1.0", nil},
+		{".5", nil},
+		{"1.2.3", nil},
+		{"1e9", nil},
+		{"", nil},
+	} {
+		amount, err := d.ParseAmount(t.raw)
+		if t.amount == nil {
+			require.Error(err, "ParseAmount(%s) should fail", t.raw)
+			require.True(errors.Is(err, ErrInvalidTokenAmount), "ParseAmount(%s) should fail with an invalid amount", t.raw)
+			continue
+		}
+		require.NoError(err, "ParseAmount(%s)", t.raw)
+		require.Equal(t.amount.String(), amount.String(), "ParseAmount(%s)", t.raw)
+	}
+
+	// Parsing should be the inverse of formatting.
+	amount := quantity.NewFromUint64(10000000000000000001)
+	formatted, err = d.FormatAmount(*amount)
+	require.NoError(err, "FormatAmount")
+	parsed, err := d.ParseAmount(formatted)
+	require.NoError(err, "ParseAmount")
+	require.Equal(amount.String(), parsed.String(), "ParseAmount(FormatAmount(x)) should equal x")
+
+	// Denominations without decimals.
+	d = &Denomination{Symbol: "WHOLE", Exponent: 0}
+	amount, err = d.ParseAmount("42")
+	require.NoError(err, "ParseAmount")
+	require.Equal("42", amount.String())
+	_, err = d.ParseAmount("42.5")
+	require.Error(err, "ParseAmount should fail for decimals")
+}
+
+func TestDenominationContext(t *testing.T) {
+	require := require.New(t)
+
+	_, ok := DenominationFromContext(context.Background())
+	require.False(ok, "DenominationFromContext should fail without a denomination")
+
+	d := &Denomination{Symbol: "CORE", Exponent: 9}
+	ctx := ContextWithDenomination(context.Background(), d)
+	cd, ok := DenominationFromContext(ctx)
+	require.True(ok, "DenominationFromContext")
+	require.EqualValues(d, cd)
+}
+
+func TestDenominationRegistry(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewDenominationRegistry(Denomination{Symbol: "core", Exponent: 9})
+	require.Error(err, "NewDenominationRegistry should fail with an invalid denomination")
+	_, err = NewDenominationRegistry(Denomination{Symbol: "CORE", Exponent: 9}, Denomination{Symbol: "CORE", Exponent: 6})
+	require.True(errors.Is(err, ErrDenominationExists), "NewDenominationRegistry should fail with duplicate denominations")
+
+	r, err := NewDenominationRegistry(
+		Denomination{Symbol: "CORE", Exponent: 9, DisplayName: "Oasis Core"},
+		Denomination{Symbol: "ALT", Exponent: 2},
+	)
+	require.NoError(err, "NewDenominationRegistry")
+
+	err = r.Register(Denomination{Symbol: "ALT", Exponent: 3})
+	require.Equal(ErrDenominationExists, err, "Register should fail for an existing denomination")
+	err = r.Register(Denomination{Symbol: "NEW", Exponent: 0})
+	require.NoError(err, "Register")
+
+	d, err := r.Lookup("CORE")
+	require.NoError(err, "Lookup")
+	require.EqualValues(9, d.Exponent)
+	require.Equal("CORE (Oasis Core)", d.String())
+	_, err = r.Lookup("MISSING")
+	require.Equal(ErrNoSuchDenomination, err, "Lookup should fail for a missing denomination")
+
+	var symbols []string
+	for _, d := range r.Denominations() {
+		symbols = append(symbols, d.Symbol)
+	}
+	require.Equal([]string{"ALT", "CORE", "NEW"}, symbols, "Denominations should be sorted by symbol")
+
+	d, amount, err := r.ParseAmount("ALT 1.5")
+	require.NoError(err, "ParseAmount")
+	require.Equal("ALT", d.Symbol)
+	require.Equal("150", amount.String())
+	_, _, err = r.ParseAmount("1.5")
+	require.True(errors.Is(err, ErrInvalidTokenAmount), "ParseAmount should fail without a symbol")
+	_, _, err = r.ParseAmount("MISSING 1.5")
+	require.Equal(ErrNoSuchDenomination, err, "ParseAmount should fail for a missing denomination")
+}
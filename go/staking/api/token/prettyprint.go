@@ -27,29 +27,12 @@ func ConvertToTokenAmount(amount quantity.Quantity, tokenValueExponent uint8) (s
 // token's value base-10 exponent, then the amount is printed in tokens instead
 // of base units.
 func PrettyPrintAmount(ctx context.Context, amount quantity.Quantity, w io.Writer) {
-	useBaseUnits := false
-
-	symbol, ok := ctx.Value(prettyprint.ContextKeyTokenSymbol).(string)
-	if !ok || symbol == "" || len(symbol) > TokenSymbolMaxLength {
-		useBaseUnits = true
-	}
-	exp, ok := ctx.Value(prettyprint.ContextKeyTokenValueExponent).(uint8)
-	if !ok {
-		useBaseUnits = true
-	}
-
-	var tokenAmount string
-	var err error
-	if !useBaseUnits {
-		tokenAmount, err = ConvertToTokenAmount(amount, exp)
-		if err != nil {
-			useBaseUnits = true
+	if d, ok := DenominationFromContext(ctx); ok && d.Symbol != "" && len(d.Symbol) <= TokenSymbolMaxLength {
+		if tokenAmount, err := d.FormatAmount(amount); err == nil {
+			fmt.Fprint(w, tokenAmount)
+			return
 		}
 	}
 
-	if useBaseUnits {
-		fmt.Fprintf(w, "%s base units", amount)
-	} else {
-		fmt.Fprintf(w, "%s %s", symbol, tokenAmount)
-	}
+	fmt.Fprintf(w, "%s base units", amount)
 }
@@ -15,6 +15,20 @@ const (
 	TokenValueExponentMaxValue = 20
 )
 
-// ErrInvalidTokenValueExponent is the error returned when an invalid token's
-// value base-10 exponent is specified.
-var ErrInvalidTokenValueExponent = errors.New(ModuleName, 1, "staking/token: invalid token's value exponent")
+var (
+	// ErrInvalidTokenValueExponent is the error returned when an invalid token's
+	// value base-10 exponent is specified.
+	ErrInvalidTokenValueExponent = errors.New(ModuleName, 1, "staking/token: invalid token's value exponent")
+	// ErrInvalidTokenSymbol is the error returned when an invalid token's
+	// ticker symbol is specified.
+	ErrInvalidTokenSymbol = errors.New(ModuleName, 2, "staking/token: invalid token's symbol")
+	// ErrInvalidTokenAmount is the error returned when an invalid token amount
+	// is specified.
+	ErrInvalidTokenAmount = errors.New(ModuleName, 3, "staking/token: invalid token amount")
+	// ErrDenominationExists is the error returned when a denomination with the
+	// same ticker symbol is already registered.
+	ErrDenominationExists = errors.New(ModuleName, 4, "staking/token: denomination already exists")
+	// ErrNoSuchDenomination is the error returned when a denomination with the
+	// given ticker symbol is not registered.
+	ErrNoSuchDenomination = errors.New(ModuleName, 5, "staking/token: no such denomination")
+)
@@ -174,6 +174,13 @@ func testTransfer(t *testing.T, state *stakingTestsState, backend api.Backend, c
 	require.NoError(err, "WatchEvents")
 	defer sub.Close()
 
+	filteredCh, filteredSub, err := backend.WatchFilteredEvents(context.Background(), &api.EventFilter{
+		Addresses: []api.Address{DestAddr},
+		Kinds:     []api.EventKind{api.EventKindTransfer},
+	})
+	require.NoError(err, "WatchFilteredEvents")
+	defer filteredSub.Close()
+
 	xfer := &api.Transfer{
 		To:     DestAddr,
 		Amount: *quantity.NewFromUint64(math.MaxUint8),
@@ -230,6 +237,16 @@ TransferWaitLoop:
 		}
 	}
 
+	select {
+	case ev := <-filteredCh:
+		require.NotNil(ev.Transfer, "filtered event should be a transfer event")
+		require.Equal(SrcAddr, ev.Transfer.From, "filtered event: from")
+		require.Equal(DestAddr, ev.Transfer.To, "filtered event: to")
+		require.Equal(xfer.Amount, ev.Transfer.Amount, "filtered event: amount")
+	case <-time.After(recvTimeout):
+		t.Fatalf("failed to receive filtered transfer event")
+	}
+
 	_ = srcAcc.General.Balance.Sub(&xfer.Amount)
 	newSrcAcc, err := backend.Account(context.Background(), &api.OwnerQuery{Owner: SrcAddr, Height: consensusAPI.HeightLatest})
 	require.NoError(err, "src: Account - after")
@@ -351,10 +368,17 @@ func testBurn(t *testing.T, state *stakingTestsState, backend api.Backend, conse
 		// Make sure that GetEvents also returns the burn event.
 		evts, grr := backend.GetEvents(context.Background(), consensusAPI.HeightLatest)
 		require.NoError(grr, "GetEvents")
+		for i, evt := range evts {
+			require.True(evt.Index.IsValid(), "GetEvents should return valid event indices")
+			if i > 0 {
+				require.False(evt.Index.Less(evts[i-1].Index), "GetEvents should return events in emission order")
+			}
+		}
 		var gotIt bool
 		for _, evt := range evts {
 			if evt.Burn != nil {
 				if evt.Burn.Owner.Equal(be.Owner) && evt.Burn.Amount.Cmp(&be.Amount) == 0 {
+					require.Equal(ev.Index, evt.Index, "GetEvents should return the same event index as WatchEvents")
 					gotIt = true
 					break
 				}
@@ -440,6 +464,9 @@ func testEscrowEx( // nolint: gocyclo
 		require.Equal(srcAddr, ev.Owner, "Event: owner")
 		require.Equal(dstAddr, ev.Escrow, "Event: escrow")
 		require.Equal(escrow.Amount, ev.Amount, "Event: amount")
+		require.True(ev.HasShares(), "Event: should include shares")
+		require.False(ev.NewShares.IsZero(), "Event: new shares")
+		require.Zero(totalEscrowed.Cmp(&ev.ActiveBalance), "Event: active balance")
 
 		// Make sure that GetEvents also returns the add escrow event.
 		evts, grr := backend.GetEvents(context.Background(), consensusAPI.HeightLatest)
@@ -553,6 +580,15 @@ func testEscrowEx( // nolint: gocyclo
 	newSrcAcc = nil
 	newDstAcc = nil
 
+	// Query delegations in both directions.
+	delsFrom, err := backend.Delegations(context.Background(), &api.OwnerQuery{Owner: srcAddr, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "Delegations")
+	require.Contains(delsFrom, dstAddr, "Delegations should include the escrow account")
+	delsTo, err := backend.DelegationsTo(context.Background(), &api.OwnerQuery{Owner: dstAddr, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "DelegationsTo")
+	require.Contains(delsTo, srcAddr, "DelegationsTo should include the delegator")
+	require.Equal(delsFrom[dstAddr], delsTo[srcAddr], "Delegations and DelegationsTo should match")
+
 	// Reclaim escrow (subject to debonding).
 	debs, err := backend.DebondingDelegations(context.Background(), &api.OwnerQuery{Owner: srcAddr, Height: consensusAPI.HeightLatest})
 	require.NoError(err, "DebondingDelegations - before")
@@ -588,6 +624,8 @@ func testEscrowEx( // nolint: gocyclo
 		require.Equal(srcAddr, ev.Owner, "Event: owner")
 		require.Equal(dstAddr, ev.Escrow, "Event: escrow")
 		require.Equal(totalEscrowed, &ev.Amount, "Event: amount")
+		require.True(ev.HasShares(), "Event: should include shares")
+		require.False(ev.Shares.IsZero(), "Event: shares")
 
 		// Make sure that GetEvents also returns the reclaim escrow event.
 		evts, grr := backend.GetEvents(context.Background(), consensusAPI.HeightLatest)
@@ -0,0 +1,110 @@
+// Package vectors implements the test vector generator for the staking
+// transactions.
+package vectors
+
+import (
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/testvectors"
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Generator is the staking transaction test vector generator.
+var Generator = &testvectors.Generator{
+	Name:         staking.ModuleName,
+	ChainContext: "staking test vectors",
+	Generate:     Generate,
+}
+
+// Generate generates test vectors for the staking transactions.
+func Generate() []testvectors.TestVector {
+	var vectors []testvectors.TestVector
+
+	// Generate different gas fees.
+	for _, fee := range testvectors.Fees {
+		// Generate different nonces.
+		for _, nonce := range testvectors.Nonces {
+			// Valid transfer transactions.
+			transferDst := memorySigner.NewTestSigner("oasis-core staking test vectors: Transfer dst")
+			transferDstAddr := staking.NewAddress(transferDst.Public())
+			for _, amt := range []uint64{0, 1000, 10_000_000} {
+				for _, tx := range []*transaction.Transaction{
+					staking.NewTransferTx(nonce, fee, &staking.Transfer{
+						To:     transferDstAddr,
+						Amount: *quantity.NewFromUint64(amt),
+					}),
+				} {
+					vectors = append(vectors, testvectors.MakeTestVector("Transfer", tx))
+				}
+			}
+
+			// Valid burn transactions.
+			for _, amt := range []uint64{0, 1000, 10_000_000} {
+				for _, tx := range []*transaction.Transaction{
+					staking.NewBurnTx(nonce, fee, &staking.Burn{
+						Amount: *quantity.NewFromUint64(amt),
+					}),
+				} {
+					vectors = append(vectors, testvectors.MakeTestVector("Burn", tx))
+				}
+			}
+
+			// Valid escrow transactions.
+			escrowDst := memorySigner.NewTestSigner("oasis-core staking test vectors: Escrow dst")
+			escrowDstAddr := staking.NewAddress(escrowDst.Public())
+			for _, amt := range []uint64{0, 1000, 10_000_000} {
+				for _, tx := range []*transaction.Transaction{
+					staking.NewAddEscrowTx(nonce, fee, &staking.Escrow{
+						Account: escrowDstAddr,
+						Amount:  *quantity.NewFromUint64(amt),
+					}),
+				} {
+					vectors = append(vectors, testvectors.MakeTestVector("Escrow", tx))
+				}
+			}
+
+			// Valid reclaim escrow transactions.
+			escrowSrc := memorySigner.NewTestSigner("oasis-core staking test vectors: ReclaimEscrow src")
+			escrowSrcAddr := staking.NewAddress(escrowSrc.Public())
+			for _, amt := range []uint64{0, 1000, 10_000_000} {
+				for _, tx := range []*transaction.Transaction{
+					staking.NewReclaimEscrowTx(nonce, fee, &staking.ReclaimEscrow{
+						Account: escrowSrcAddr,
+						Shares:  *quantity.NewFromUint64(amt),
+					}),
+				} {
+					vectors = append(vectors, testvectors.MakeTestVector("ReclaimEscrow", tx))
+				}
+			}
+
+			// Valid amend commission schedule transactions.
+			for _, steps := range []int{0, 1, 2, 5} {
+				for _, startEpoch := range []uint64{0, 10, 1000, 1_000_000} {
+					for _, rate := range []uint64{0, 10, 1000, 10_000, 50_000, 100_000} {
+						var cs staking.CommissionSchedule
+						for i := 0; i < steps; i++ {
+							cs.Rates = append(cs.Rates, staking.CommissionRateStep{
+								Start: epochtime.EpochTime(startEpoch),
+								Rate:  *quantity.NewFromUint64(rate),
+							})
+							cs.Bounds = append(cs.Bounds, staking.CommissionRateBoundStep{
+								Start:   epochtime.EpochTime(startEpoch),
+								RateMin: *quantity.NewFromUint64(rate),
+								RateMax: *quantity.NewFromUint64(rate),
+							})
+						}
+
+						tx := staking.NewAmendCommissionScheduleTx(nonce, fee, &staking.AmendCommissionSchedule{
+							Amendment: cs,
+						})
+						vectors = append(vectors, testvectors.MakeTestVector("AmendCommissionSchedule", tx))
+					}
+				}
+			}
+		}
+	}
+
+	return vectors
+}
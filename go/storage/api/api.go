@@ -40,6 +40,12 @@ var (
 	ErrUnsupported = errors.New(ModuleName, 4, "storage: method not supported by backend")
 	// ErrLimitReached means that a configured limit has been reached.
 	ErrLimitReached = errors.New(ModuleName, 5, "storage: limit reached")
+	// ErrInvalidContinuationToken is the error returned when the GetDiff continuation token is
+	// malformed or does not match the request.
+	ErrInvalidContinuationToken = errors.New(ModuleName, 6, "storage: invalid continuation token")
+	// ErrDiffVerificationFailed is the error returned when a fetched write log does not transform
+	// the start root into the expected end root.
+	ErrDiffVerificationFailed = errors.New(ModuleName, 7, "storage: diff verification failed")
 
 	// The following errors are reimports from NodeDB.
 
@@ -102,11 +108,36 @@ type Config struct { // nolint: maligned
 	// NoFsync will disable fsync() where possible.
 	NoFsync bool
 
+	// FsyncBatch configures batching of fsync() calls. It is ignored when NoFsync is set.
+	FsyncBatch nodedb.FsyncBatchPolicy
+
 	// MemoryOnly will make the storage memory-only (if the backend supports it).
 	MemoryOnly bool
 
 	// ReadOnly will make the storage read-only.
 	ReadOnly bool
+
+	// KeyFilterCapacity is the expected number of keys in the key existence filter. Zero
+	// disables the filter.
+	KeyFilterCapacity uint64
+
+	// KeyFilterFalsePositiveRate is the target key existence filter false positive rate.
+	KeyFilterFalsePositiveRate float64
+
+	// SharedNodeDB is the path to a node database shared by multiple namespaces. If set, nodes
+	// are deduplicated across all namespaces using the same shared node database.
+	SharedNodeDB string
+
+	// WriteLogBufferSize is the maximum number of write log entries buffered while streaming
+	// write logs from the database. Zero selects the default buffer size.
+	WriteLogBufferSize int
+
+	// TreeCacheSize is the memory budget (in bytes) shared by the in-memory caches of all trees
+	// backed by the node database. Zero selects the default per-tree cache capacities.
+	TreeCacheSize uint64
+
+	// GC configures garbage collection of the underlying database storage.
+	GC nodedb.GCPolicy
 }
 
 // ToNodeDB converts from a Config to a node DB Config.
@@ -116,9 +147,19 @@ func (cfg *Config) ToNodeDB() *nodedb.Config {
 		Namespace:        cfg.Namespace,
 		MaxCacheSize:     cfg.MaxCacheSize,
 		NoFsync:          cfg.NoFsync,
+		FsyncBatch:       cfg.FsyncBatch,
 		MemoryOnly:       cfg.MemoryOnly,
 		ReadOnly:         cfg.ReadOnly,
 		DiscardWriteLogs: cfg.DiscardWriteLogs,
+
+		KeyFilterCapacity:          cfg.KeyFilterCapacity,
+		KeyFilterFalsePositiveRate: cfg.KeyFilterFalsePositiveRate,
+
+		SharedNodeDB: cfg.SharedNodeDB,
+
+		WriteLogBufferSize: cfg.WriteLogBufferSize,
+
+		GC: cfg.GC,
 	}
 }
 
@@ -144,6 +185,26 @@ type ReceiptBody struct {
 	// Roots are the merkle roots of the merklized data structure that the
 	// storage node is certifying to store.
 	Roots []hash.Hash `json:"roots"`
+	// Attestation is an optional attestation of the data that the storage
+	// node has written while applying the root(s).
+	Attestation *ReceiptAttestation `json:"attestation,omitempty"`
+}
+
+// ReceiptAttestation is an attestation of the data written by a storage node
+// during an apply operation.
+type ReceiptAttestation struct {
+	// NodesWritten is the number of tree nodes that have been persisted by the
+	// storage node while applying the write log(s). It is zero in case all of
+	// the resulting roots were already present in the node's database.
+	NodesWritten uint64 `json:"nodes_written"`
+	// WriteLogHash is a commitment to the write log(s) that have been applied,
+	// as computed by WriteLogsHash.
+	WriteLogHash hash.Hash `json:"write_log_hash"`
+}
+
+// WriteLogsHash computes a commitment to an ordered list of write logs.
+func WriteLogsHash(writeLogs []WriteLog) hash.Hash {
+	return hash.NewFrom(writeLogs)
 }
 
 // Receipt is a signed ReceiptBody.
@@ -158,6 +219,18 @@ func (s *Receipt) Open(receipt *ReceiptBody) error {
 
 // SignReceipt signs a storage receipt for the given roots.
 func SignReceipt(signer signature.Signer, ns common.Namespace, round uint64, roots []hash.Hash) (*Receipt, error) {
+	return SignAttestedReceipt(signer, ns, round, roots, nil)
+}
+
+// SignAttestedReceipt signs a storage receipt for the given roots, including
+// an optional attestation of the written data.
+func SignAttestedReceipt(
+	signer signature.Signer,
+	ns common.Namespace,
+	round uint64,
+	roots []hash.Hash,
+	attestation *ReceiptAttestation,
+) (*Receipt, error) {
 	if signer == nil {
 		return nil, ErrCantProve
 	}
@@ -165,10 +238,11 @@ func SignReceipt(signer signature.Signer, ns common.Namespace, round uint64, roo
 		return nil, ErrNoRoots
 	}
 	receipt := ReceiptBody{
-		Version:   1,
-		Namespace: ns,
-		Round:     round,
-		Roots:     roots,
+		Version:     1,
+		Namespace:   ns,
+		Round:       round,
+		Roots:       roots,
+		Attestation: attestation,
 	}
 	signed, err := signature.SignSigned(signer, ReceiptSignatureContext, &receipt)
 	if err != nil {
@@ -245,6 +319,20 @@ type ApplyRequest struct {
 	DstRound  uint64           `json:"dst_round"`
 	DstRoot   hash.Hash        `json:"dst_root"`
 	WriteLog  WriteLog         `json:"writelog"`
+
+	// Attest requests that the storage node returns an additional receipt
+	// which includes an attestation of the written data.
+	Attest bool `json:"attest,omitempty"`
+}
+
+// ApplyIteratorRequest is an ApplyIterator request.
+type ApplyIteratorRequest struct {
+	Namespace common.Namespace
+	SrcRound  uint64
+	SrcRoot   hash.Hash
+	DstRound  uint64
+	DstRoot   hash.Hash
+	WriteLog  WriteLogIterator
 }
 
 // ApplyBatchRequest is an ApplyBatch request.
@@ -252,18 +340,31 @@ type ApplyBatchRequest struct {
 	Namespace common.Namespace `json:"namespace"`
 	DstRound  uint64           `json:"dst_round"`
 	Ops       []ApplyOp        `json:"ops"`
+
+	// Attest requests that the storage node returns an additional receipt
+	// which includes an attestation of the written data.
+	Attest bool `json:"attest,omitempty"`
 }
 
 // SyncOptions are the sync options.
 type SyncOptions struct {
 	OffsetKey []byte `json:"offset_key"`
 	Limit     uint64 `json:"limit"`
+
+	// ContinuationToken is an optional opaque token obtained from a previous GetDiff response
+	// for the same roots. When set, streaming resumes after the last entry covered by the token
+	// and OffsetKey is ignored.
+	ContinuationToken []byte `json:"continuation_token,omitempty"`
 }
 
 // SyncChunk is a chunk of write log entries sent during GetDiff operation.
 type SyncChunk struct {
 	Final    bool     `json:"final"`
 	WriteLog WriteLog `json:"writelog"`
+
+	// ContinuationToken is an opaque token that can be used to resume streaming after the
+	// entries of this chunk. It is not set for the final chunk.
+	ContinuationToken []byte `json:"continuation_token,omitempty"`
 }
 
 // GetDiffRequest is a GetDiff request.
@@ -283,6 +384,9 @@ type Backend interface {
 	// The expected new root is used to check if the new root after all the
 	// operations are applied already exists in the local DB.  If it does, the
 	// Apply is ignored.
+	//
+	// In case the request asks for an attestation, a second receipt which
+	// also includes a ReceiptAttestation is returned after the regular one.
 	Apply(ctx context.Context, request *ApplyRequest) ([]*Receipt, error)
 
 	// ApplyBatch applies multiple sets of operations against the MKVS and
@@ -312,6 +416,11 @@ type LocalBackend interface {
 
 	// NodeDB returns the underlying node database.
 	NodeDB() nodedb.NodeDB
+
+	// ApplyIterator applies the write log entries yielded by the given iterator to the local
+	// storage. Entries are consumed as they are applied so the caller does not need to hold the
+	// whole write log in memory. No receipts are generated.
+	ApplyIterator(ctx context.Context, request *ApplyIteratorRequest) error
 }
 
 // ClientBackend is a storage client backend implementation.
@@ -324,4 +433,8 @@ type ClientBackend interface {
 	// EnsureCommitteeVersion waits for the storage committee client to be fully synced to the
 	// given version.
 	EnsureCommitteeVersion(ctx context.Context, version int64) error
+
+	// GetCheckpointAvailability returns the availability of checkpoints for the given runtime
+	// as advertised by the currently connected storage nodes.
+	GetCheckpointAvailability(namespace common.Namespace) *CheckpointAvailability
 }
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+// CheckpointSource is a storage node advertising the availability of a checkpoint.
+type CheckpointSource struct {
+	// Node is the identifier of the node advertising the checkpoint.
+	Node signature.PublicKey `json:"node"`
+	// StoreURL is the base URL of the external checkpoint store advertised by the node, if any.
+	StoreURL string `json:"store_url,omitempty"`
+}
+
+// AvailableCheckpoint is a checkpoint advertised by one or more storage nodes.
+type AvailableCheckpoint struct {
+	node.StorageCheckpoint
+
+	// Sources are the storage nodes advertising the checkpoint.
+	Sources []CheckpointSource `json:"sources"`
+}
+
+// HasSource returns true iff the given node advertises the checkpoint.
+func (ac *AvailableCheckpoint) HasSource(id signature.PublicKey) bool {
+	for _, src := range ac.Sources {
+		if src.Node.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckpointAvailability is the availability of checkpoints for a runtime aggregated from the
+// advertisements in the descriptors of storage nodes.
+type CheckpointAvailability struct {
+	checkpoints map[hash.Hash]*AvailableCheckpoint
+}
+
+// Get returns the availability of the checkpoint with the given metadata hash or nil in case no
+// node advertises it.
+func (ca *CheckpointAvailability) Get(metadataHash hash.Hash) *AvailableCheckpoint {
+	return ca.checkpoints[metadataHash]
+}
+
+// Checkpoints returns all advertised checkpoints, ordered from the most recent round backwards.
+// Checkpoints for the same round are ordered by the number of nodes advertising them.
+func (ca *CheckpointAvailability) Checkpoints() []*AvailableCheckpoint {
+	cps := make([]*AvailableCheckpoint, 0, len(ca.checkpoints))
+	for _, cp := range ca.checkpoints {
+		cps = append(cps, cp)
+	}
+	sort.Slice(cps, func(i, j int) bool {
+		if cps[i].Round != cps[j].Round {
+			return cps[i].Round > cps[j].Round
+		}
+		if len(cps[i].Sources) != len(cps[j].Sources) {
+			return len(cps[i].Sources) > len(cps[j].Sources)
+		}
+		return bytes.Compare(cps[i].Hash[:], cps[j].Hash[:]) < 0
+	})
+	return cps
+}
+
+// Sources returns the number of nodes advertising the checkpoint with the given metadata hash.
+func (ca *CheckpointAvailability) Sources(metadataHash hash.Hash) int {
+	if cp := ca.checkpoints[metadataHash]; cp != nil {
+		return len(cp.Sources)
+	}
+	return 0
+}
+
+// IsEmpty returns true iff no checkpoints are advertised.
+func (ca *CheckpointAvailability) IsEmpty() bool {
+	return len(ca.checkpoints) == 0
+}
+
+// NewCheckpointAvailability aggregates the checkpoints for the given runtime advertised by the
+// given storage nodes.
+func NewCheckpointAvailability(runtimeID common.Namespace, nodes []*node.Node) *CheckpointAvailability {
+	ca := &CheckpointAvailability{
+		checkpoints: make(map[hash.Hash]*AvailableCheckpoint),
+	}
+	for _, n := range nodes {
+		rt := n.GetRuntime(runtimeID)
+		if rt == nil || rt.Capabilities.Storage == nil {
+			continue
+		}
+		for _, cp := range rt.Capabilities.Storage.Checkpoints {
+			ac := ca.checkpoints[cp.Hash]
+			if ac == nil {
+				ac = &AvailableCheckpoint{StorageCheckpoint: cp}
+				ca.checkpoints[cp.Hash] = ac
+			}
+			if ac.HasSource(n.ID) {
+				// Ignore duplicate advertisements.
+				continue
+			}
+			ac.Sources = append(ac.Sources, CheckpointSource{
+				Node:     n.ID,
+				StoreURL: rt.Capabilities.Storage.CheckpointStoreURL,
+			})
+		}
+	}
+	return ca
+}
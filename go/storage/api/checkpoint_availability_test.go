@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+func TestCheckpointAvailability(t *testing.T) {
+	require := require.New(t)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("checkpoint availability test ns"), 0)
+	otherRuntimeID := common.NewTestNamespaceFromSeed([]byte("checkpoint availability test ns 2"), 0)
+
+	newCheckpoint := func(round uint64, seed string) node.StorageCheckpoint {
+		var h hash.Hash
+		h.FromBytes([]byte(seed))
+		return node.StorageCheckpoint{Version: 1, Round: round, Chunks: 1, Hash: h}
+	}
+	cp1 := newCheckpoint(10, "cp1")
+	cp2 := newCheckpoint(20, "cp2")
+	cp3 := newCheckpoint(20, "cp3")
+	cpOther := newCheckpoint(30, "other")
+
+	newNode := func(seed string, storeURL string, cps ...node.StorageCheckpoint) *node.Node {
+		n := &node.Node{ID: signature.NewPublicKey(hash.NewFromBytes([]byte(seed)).String())}
+		rt := n.AddOrUpdateRuntime(runtimeID)
+		rt.Capabilities.Storage = &node.CapabilityStorage{
+			Checkpoints:        cps,
+			CheckpointStoreURL: storeURL,
+		}
+		other := n.AddOrUpdateRuntime(otherRuntimeID)
+		other.Capabilities.Storage = &node.CapabilityStorage{
+			Checkpoints: []node.StorageCheckpoint{cpOther},
+		}
+		return n
+	}
+	n1 := newNode("node 1", "https://example.com/store", cp1, cp2, cp2)
+	n2 := newNode("node 2", "", cp2, cp3)
+	n3 := newNode("node 3", "", cp3)
+	n4 := newNode("node 4", "", cp3)
+	n5 := &node.Node{ID: signature.NewPublicKey(hash.NewFromBytes([]byte("node 5")).String())}
+
+	ca := NewCheckpointAvailability(runtimeID, []*node.Node{n1, n2, n3, n4, n5})
+	require.False(ca.IsEmpty(), "availability should not be empty")
+	require.Nil(ca.Get(cpOther.Hash), "checkpoints for other runtimes should be ignored")
+	require.Equal(0, ca.Sources(cpOther.Hash), "checkpoints for other runtimes should have no sources")
+	require.Equal(1, ca.Sources(cp1.Hash), "number of sources should be correct")
+	require.Equal(2, ca.Sources(cp2.Hash), "duplicate advertisements should be ignored")
+	require.Equal(3, ca.Sources(cp3.Hash), "number of sources should be correct")
+
+	available := ca.Get(cp1.Hash)
+	require.NotNil(available, "advertised checkpoint should be available")
+	require.Equal(cp1, available.StorageCheckpoint, "checkpoint advertisement should be correct")
+	require.True(available.HasSource(n1.ID), "advertising node should be a source")
+	require.False(available.HasSource(n2.ID), "other nodes should not be sources")
+	require.Equal("https://example.com/store", available.Sources[0].StoreURL, "store URL should be recorded")
+
+	cps := ca.Checkpoints()
+	require.Len(cps, 3, "all advertised checkpoints should be returned")
+	require.Equal(cp3.Hash, cps[0].Hash, "most recent and most widely available checkpoint should come first")
+	require.Equal(cp2.Hash, cps[1].Hash, "checkpoints should be ordered by availability")
+	require.Equal(cp1.Hash, cps[2].Hash, "checkpoints should be ordered by round")
+
+	require.True(NewCheckpointAvailability(runtimeID, nil).IsEmpty(), "availability without nodes should be empty")
+}
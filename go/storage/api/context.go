@@ -8,7 +8,17 @@ import (
 
 type contextKey string
 
-const contextKeyNodePriorityHint = contextKey("storage/node-priority-key")
+const (
+	contextKeyNodePriorityHint = contextKey("storage/node-priority-key")
+	contextKeyDiffVerifier     = contextKey("storage/diff-verifier")
+	contextKeyNodeAvoidHint    = contextKey("storage/node-avoid-hint")
+	contextKeySourceRecorder   = contextKey("storage/source-recorder")
+)
+
+// DiffVerifier is a function that verifies a write log fetched via GetDiff before it is returned
+// to the caller. It should return an error in case the write log does not transform the request's
+// start root into its end root.
+type DiffVerifier func(ctx context.Context, request *GetDiffRequest, writeLog WriteLog) error
 
 // WithNodePriorityHint sets a storage node priority hint for any storage read requests using this
 // context. Only storage nodes that overlap with the configured committee will be used.
@@ -32,3 +42,44 @@ func NodePriorityHintFromContext(ctx context.Context) []signature.PublicKey {
 	nodes, _ := ctx.Value(contextKeyNodePriorityHint).([]signature.PublicKey)
 	return nodes
 }
+
+// SourceRecorder is a function that is called with the identifier of the storage node that served
+// a successful storage read request.
+type SourceRecorder func(node signature.PublicKey)
+
+// WithNodeAvoidHint sets a storage node avoidance hint for any storage read requests using this
+// context. The given storage nodes will only be used in case all other nodes fail.
+func WithNodeAvoidHint(ctx context.Context, nodes []signature.PublicKey) context.Context {
+	return context.WithValue(ctx, contextKeyNodeAvoidHint, nodes)
+}
+
+// NodeAvoidHintFromContext returns the storage node avoidance hint or nil if none is set.
+func NodeAvoidHintFromContext(ctx context.Context) []signature.PublicKey {
+	nodes, _ := ctx.Value(contextKeyNodeAvoidHint).([]signature.PublicKey)
+	return nodes
+}
+
+// WithSourceRecorder sets a source recorder for any storage read requests using this context.
+// Storage clients that support it will report which storage node served the response.
+func WithSourceRecorder(ctx context.Context, recorder SourceRecorder) context.Context {
+	return context.WithValue(ctx, contextKeySourceRecorder, recorder)
+}
+
+// SourceRecorderFromContext returns the source recorder or nil if none is set.
+func SourceRecorderFromContext(ctx context.Context) SourceRecorder {
+	recorder, _ := ctx.Value(contextKeySourceRecorder).(SourceRecorder)
+	return recorder
+}
+
+// WithDiffVerifier sets a diff verifier for any GetDiff requests using this context. Storage
+// clients that support it will fetch the complete write log and verify it before returning it,
+// falling back to other storage nodes in case verification fails.
+func WithDiffVerifier(ctx context.Context, verifier DiffVerifier) context.Context {
+	return context.WithValue(ctx, contextKeyDiffVerifier, verifier)
+}
+
+// DiffVerifierFromContext returns the diff verifier or nil if none is set.
+func DiffVerifierFromContext(ctx context.Context) DiffVerifier {
+	verifier, _ := ctx.Value(contextKeyDiffVerifier).(DiffVerifier)
+	return verifier
+}
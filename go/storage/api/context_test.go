@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,3 +35,53 @@ func TestNodePriorityHint(t *testing.T) {
 	require.Len(nodes, 3, "all node ids must be there")
 	require.EqualValues([]signature.PublicKey{pk1, pk2, pk3}, nodes, "all node ids must be the same")
 }
+
+func TestDiffVerifier(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	require.Nil(DiffVerifierFromContext(ctx), "must return nil when diff verifier is not present")
+
+	var called bool
+	ctx = WithDiffVerifier(ctx, func(ctx context.Context, request *GetDiffRequest, writeLog WriteLog) error {
+		called = true
+		return fmt.Errorf("verification failed")
+	})
+	verifier := DiffVerifierFromContext(ctx)
+	require.NotNil(verifier, "diff verifier must be there")
+	err := verifier(ctx, &GetDiffRequest{}, nil)
+	require.Error(err, "diff verifier must be the configured one")
+	require.True(called, "diff verifier must be the configured one")
+}
+
+func TestNodeAvoidHint(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	require.Nil(NodeAvoidHintFromContext(ctx), "must return nil when node avoidance hint is not present")
+
+	var pk1, pk2 signature.PublicKey
+	_ = pk1.UnmarshalHex("0000000000000000000000000000000000000000000000000000000000000000")
+	_ = pk2.UnmarshalHex("0000000000000000000000000000000000000000000000000000000000000001")
+	ctx = WithNodeAvoidHint(ctx, []signature.PublicKey{pk1, pk2})
+	require.EqualValues([]signature.PublicKey{pk1, pk2}, NodeAvoidHintFromContext(ctx), "all node ids must be the same")
+}
+
+func TestSourceRecorder(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	require.Nil(SourceRecorderFromContext(ctx), "must return nil when source recorder is not present")
+
+	var recorded signature.PublicKey
+	ctx = WithSourceRecorder(ctx, func(node signature.PublicKey) {
+		recorded = node
+	})
+	recorder := SourceRecorderFromContext(ctx)
+	require.NotNil(recorder, "source recorder must be there")
+
+	var pk signature.PublicKey
+	_ = pk.UnmarshalHex("0000000000000000000000000000000000000000000000000000000000000001")
+	recorder(pk)
+	require.EqualValues(pk, recorded, "source recorder must be the configured one")
+}
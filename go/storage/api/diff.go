@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+var _ ResumableWriteLogIterator = (*resumableIterator)(nil)
+
+// ResumableWriteLogIterator is a write log iterator returned by GetDiff which supports resuming an
+// interrupted stream of write log entries.
+type ResumableWriteLogIterator interface {
+	WriteLogIterator
+
+	// ContinuationToken returns an opaque token which can be passed in SyncOptions of a subsequent
+	// GetDiff request for the same roots in order to resume streaming after the last entry that
+	// was returned by the iterator.
+	//
+	// In case no entries have been received yet, it returns the token the request has been made
+	// with (if any).
+	ContinuationToken() []byte
+}
+
+// diffContinuationToken is the (opaque) GetDiff continuation token.
+type diffContinuationToken struct {
+	// StartRoot is the start root of the diff.
+	StartRoot Root `json:"start_root"`
+	// EndRoot is the end root of the diff.
+	EndRoot Root `json:"end_root"`
+	// Offset is the number of write log entries that have already been streamed.
+	Offset uint64 `json:"offset"`
+	// LastKey is the key of the last write log entry that has already been streamed.
+	LastKey []byte `json:"last_key"`
+}
+
+func (t *diffContinuationToken) verify(request *GetDiffRequest) error {
+	if !t.StartRoot.Equal(&request.StartRoot) || !t.EndRoot.Equal(&request.EndRoot) {
+		return ErrInvalidContinuationToken
+	}
+	if t.Offset == 0 {
+		return ErrInvalidContinuationToken
+	}
+	return nil
+}
+
+func decodeDiffContinuationToken(raw []byte, request *GetDiffRequest) (*diffContinuationToken, error) {
+	var token diffContinuationToken
+	if err := cbor.Unmarshal(raw, &token); err != nil {
+		return nil, ErrInvalidContinuationToken
+	}
+	if err := token.verify(request); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// resumableIterator is a queue-backed write log iterator that keeps track of the continuation
+// token of the last chunk that has been fully returned.
+type resumableIterator struct {
+	queue  chan interface{}
+	cached *LogEntry
+	token  []byte
+	ctx    context.Context
+}
+
+// continuationToken is a queue marker carrying a chunk continuation token. It is queued after all
+// of the chunk's entries.
+type continuationToken []byte
+
+func (i *resumableIterator) Next() (bool, error) {
+	for {
+		select {
+		case ret, ok := <-i.queue:
+			if !ok {
+				i.cached = nil
+				return false, nil
+			}
+			switch obj := ret.(type) {
+			case error:
+				i.cached = nil
+				return false, obj
+			case continuationToken:
+				i.token = obj
+				continue
+			case *LogEntry:
+				i.cached = obj
+			}
+			return true, nil
+		case <-i.ctx.Done():
+			return false, i.ctx.Err()
+		}
+	}
+}
+
+func (i *resumableIterator) Value() (LogEntry, error) {
+	if i.cached == nil {
+		return LogEntry{}, writelog.ErrIteratorInvalid
+	}
+	return *i.cached, nil
+}
+
+func (i *resumableIterator) ContinuationToken() []byte {
+	return i.token
+}
+
+func (i *resumableIterator) put(v interface{}) error {
+	select {
+	case i.queue <- v:
+		return nil
+	case <-i.ctx.Done():
+		return i.ctx.Err()
+	}
+}
+
+func newResumableIterator(ctx context.Context, token []byte) *resumableIterator {
+	return &resumableIterator{
+		queue: make(chan interface{}, WriteLogIteratorChunkSize*10),
+		token: token,
+		ctx:   ctx,
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+
+	chunks []*SyncChunk
+}
+
+func (s *testServerStream) SendMsg(m interface{}) error {
+	s.chunks = append(s.chunks, m.(*SyncChunk))
+	return nil
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+
+	chunks []*SyncChunk
+	err    error
+}
+
+func (s *testClientStream) RecvMsg(m interface{}) error {
+	if len(s.chunks) == 0 {
+		if s.err != nil {
+			return s.err
+		}
+		return io.EOF
+	}
+	*m.(*SyncChunk) = *s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return nil
+}
+
+func testDiffRequest(token []byte) *GetDiffRequest {
+	var ns common.Namespace
+	req := &GetDiffRequest{
+		StartRoot: Root{Namespace: ns, Version: 1},
+		EndRoot:   Root{Namespace: ns, Version: 2, Hash: hash.NewFromBytes([]byte("end"))},
+	}
+	req.StartRoot.Hash.Empty()
+	req.Options.ContinuationToken = token
+	return req
+}
+
+func TestGetDiffContinuationToken(t *testing.T) {
+	require := require.New(t)
+
+	var wl WriteLog
+	for i := 0; i < 2*WriteLogIteratorChunkSize+5; i++ {
+		wl = append(wl, LogEntry{Key: []byte(fmt.Sprintf("key %d", i)), Value: []byte("value")})
+	}
+
+	// Full stream.
+	var stream testServerStream
+	err := sendWriteLogIterator(writelog.NewStaticIterator(wl), testDiffRequest(nil), &stream)
+	require.NoError(err, "sendWriteLogIterator")
+	require.Len(stream.chunks, 3)
+	require.NotEmpty(stream.chunks[0].ContinuationToken, "non-final chunks should have a continuation token")
+	require.NotEmpty(stream.chunks[1].ContinuationToken, "non-final chunks should have a continuation token")
+	require.Empty(stream.chunks[2].ContinuationToken, "the final chunk should not have a continuation token")
+
+	// Resume after the first chunk.
+	var resumed testServerStream
+	err = sendWriteLogIterator(writelog.NewStaticIterator(wl), testDiffRequest(stream.chunks[0].ContinuationToken), &resumed)
+	require.NoError(err, "sendWriteLogIterator(resumed)")
+	var resumedWl WriteLog
+	for _, chunk := range resumed.chunks {
+		resumedWl = append(resumedWl, chunk.WriteLog...)
+	}
+	require.Equal(wl[WriteLogIteratorChunkSize:], resumedWl, "resumed stream should contain the remaining entries")
+
+	// Tokens are bound to the request roots.
+	req := testDiffRequest(stream.chunks[0].ContinuationToken)
+	req.EndRoot.Version = 3
+	err = sendWriteLogIterator(writelog.NewStaticIterator(wl), req, &testServerStream{})
+	require.True(errors.Is(err, ErrInvalidContinuationToken), "token for different roots should be rejected")
+
+	// Tokens are bound to the write log.
+	err = sendWriteLogIterator(writelog.NewStaticIterator(wl[1:]), testDiffRequest(stream.chunks[0].ContinuationToken), &testServerStream{})
+	require.True(errors.Is(err, ErrInvalidContinuationToken), "token for a different write log should be rejected")
+
+	err = sendWriteLogIterator(writelog.NewStaticIterator(wl), testDiffRequest([]byte("invalid")), &testServerStream{})
+	require.True(errors.Is(err, ErrInvalidContinuationToken), "malformed token should be rejected")
+
+	// Receiving side should expose the token of the last fully consumed chunk on failure.
+	streamErr := fmt.Errorf("stream failed")
+	it := receiveWriteLogIterator(context.Background(), &testClientStream{
+		chunks: stream.chunks[:2],
+		err:    streamErr,
+	}, nil)
+	var received WriteLog
+	for {
+		more, err := it.Next()
+		if err != nil {
+			require.Equal(streamErr, err)
+			break
+		}
+		require.True(more, "iterator should not finish before the error")
+		entry, err := it.Value()
+		require.NoError(err, "Value")
+		received = append(received, entry)
+	}
+	require.Equal(wl[:2*WriteLogIteratorChunkSize], received)
+	require.Equal(stream.chunks[1].ContinuationToken, it.(ResumableWriteLogIterator).ContinuationToken())
+}
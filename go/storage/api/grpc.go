@@ -9,9 +9,9 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
-	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 var (
@@ -21,11 +21,44 @@ var (
 	ServiceName = cmnGrpc.NewServiceName("Storage")
 
 	// MethodSyncGet is the SyncGet method.
-	MethodSyncGet = ServiceName.NewMethod("SyncGet", GetRequest{})
+	MethodSyncGet = ServiceName.NewMethod("SyncGet", GetRequest{}).
+			WithNamespaceExtractor(func(ctx context.Context, req interface{}) (common.Namespace, error) {
+			r, ok := req.(*GetRequest)
+			if !ok {
+				return common.Namespace{}, errInvalidRequestType
+			}
+			return r.Tree.Root.Namespace, nil
+		}).
+		WithAccessControl(func(ctx context.Context, req interface{}) (bool, error) {
+			return true, nil
+		})
+
 	// MethodSyncGetPrefixes is the SyncGetPrefixes method.
-	MethodSyncGetPrefixes = ServiceName.NewMethod("SyncGetPrefixes", GetPrefixesRequest{})
+	MethodSyncGetPrefixes = ServiceName.NewMethod("SyncGetPrefixes", GetPrefixesRequest{}).
+				WithNamespaceExtractor(func(ctx context.Context, req interface{}) (common.Namespace, error) {
+			r, ok := req.(*GetPrefixesRequest)
+			if !ok {
+				return common.Namespace{}, errInvalidRequestType
+			}
+			return r.Tree.Root.Namespace, nil
+		}).
+		WithAccessControl(func(ctx context.Context, req interface{}) (bool, error) {
+			return true, nil
+		})
+
 	// MethodSyncIterate is the SyncIterate method.
-	MethodSyncIterate = ServiceName.NewMethod("SyncIterate", IterateRequest{})
+	MethodSyncIterate = ServiceName.NewMethod("SyncIterate", IterateRequest{}).
+				WithNamespaceExtractor(func(ctx context.Context, req interface{}) (common.Namespace, error) {
+			r, ok := req.(*IterateRequest)
+			if !ok {
+				return common.Namespace{}, errInvalidRequestType
+			}
+			return r.Tree.Root.Namespace, nil
+		}).
+		WithAccessControl(func(ctx context.Context, req interface{}) (bool, error) {
+			return true, nil
+		})
+
 	// MethodApply is the Apply method.
 	MethodApply = ServiceName.NewMethod("Apply", ApplyRequest{}).
 			WithNamespaceExtractor(func(ctx context.Context, req interface{}) (common.Namespace, error) {
@@ -274,7 +307,8 @@ func handlerGetCheckpoints( // nolint: golint
 	return interceptor(ctx, &req, info, handler)
 }
 
-func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.ServerStream) error {
+func sendWriteLogIterator(it WriteLogIterator, req *GetDiffRequest, stream grpc.ServerStream) error {
+	opts := &req.Options
 	var totalSent uint64
 	skipping := true
 	final := false
@@ -285,6 +319,40 @@ func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.Se
 		skipping = false
 	}
 
+	// Offset is the number of entries consumed from the iterator so far and is used to generate
+	// continuation tokens.
+	var (
+		offset  uint64
+		lastKey []byte
+	)
+	if len(opts.ContinuationToken) > 0 {
+		token, err := decodeDiffContinuationToken(opts.ContinuationToken, req)
+		if err != nil {
+			return err
+		}
+		skipping = false
+
+		for offset < token.Offset {
+			more, err := it.Next()
+			if err != nil {
+				return err
+			}
+			if !more {
+				return ErrInvalidContinuationToken
+			}
+
+			entry, err := it.Value()
+			if err != nil {
+				return err
+			}
+			offset++
+			lastKey = entry.Key
+		}
+		if !bytes.Equal(lastKey, token.LastKey) {
+			return ErrInvalidContinuationToken
+		}
+	}
+
 	for {
 		var entryArray []LogEntry
 		for {
@@ -301,6 +369,8 @@ func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.Se
 			if err != nil {
 				return err
 			}
+			offset++
+			lastKey = entry.Key
 
 			if skipping {
 				if bytes.Equal(entry.Key, opts.OffsetKey) {
@@ -323,6 +393,14 @@ func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.Se
 			Final:    final,
 			WriteLog: entryArray,
 		}
+		if !final && offset > 0 {
+			chunk.ContinuationToken = cbor.Marshal(&diffContinuationToken{
+				StartRoot: req.StartRoot,
+				EndRoot:   req.EndRoot,
+				Offset:    offset,
+				LastKey:   lastKey,
+			})
+		}
 
 		if err := stream.SendMsg(chunk); err != nil {
 			return err
@@ -348,7 +426,7 @@ func handlerGetDiff(srv interface{}, stream grpc.ServerStream) error {
 		return err
 	}
 
-	return sendWriteLogIterator(it, &req.Options, stream)
+	return sendWriteLogIterator(it, &req, stream)
 }
 
 func handlerGetCheckpointChunk(srv interface{}, stream grpc.ServerStream) error {
@@ -417,11 +495,11 @@ func (c *storageClient) GetCheckpoints(ctx context.Context, request *checkpoint.
 	return rsp, nil
 }
 
-func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream) WriteLogIterator {
-	pipe := writelog.NewPipeIterator(ctx)
+func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream, token []byte) WriteLogIterator {
+	it := newResumableIterator(ctx, token)
 
 	go func() {
-		defer pipe.Close()
+		defer close(it.queue)
 
 		for {
 			var chunk SyncChunk
@@ -430,13 +508,20 @@ func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream) Writ
 				break
 			}
 			if err != nil {
-				_ = pipe.PutError(err)
-				continue
+				_ = it.put(err)
+				return
 			}
 
 			for i := range chunk.WriteLog {
-				if err := pipe.Put(&chunk.WriteLog[i]); err != nil {
-					_ = pipe.PutError(err)
+				if err = it.put(&chunk.WriteLog[i]); err != nil {
+					return
+				}
+			}
+			// Make the continuation token available only after all of the chunk's entries have
+			// been consumed.
+			if len(chunk.ContinuationToken) > 0 {
+				if err = it.put(continuationToken(chunk.ContinuationToken)); err != nil {
+					return
 				}
 			}
 
@@ -446,7 +531,7 @@ func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream) Writ
 		}
 	}()
 
-	return &pipe
+	return it
 }
 
 func (c *storageClient) GetDiff(ctx context.Context, request *GetDiffRequest) (WriteLogIterator, error) {
@@ -461,7 +546,7 @@ func (c *storageClient) GetDiff(ctx context.Context, request *GetDiffRequest) (W
 		return nil, err
 	}
 
-	return receiveWriteLogIterator(ctx, stream), nil
+	return receiveWriteLogIterator(ctx, stream, request.Options.ContinuationToken), nil
 }
 
 func (c *storageClient) GetCheckpointChunk(ctx context.Context, chunk *checkpoint.ChunkMetadata, w io.Writer) error {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 )
@@ -50,6 +51,7 @@ var (
 
 	labelApply           = prometheus.Labels{"call": "apply"}
 	labelApplyBatch      = prometheus.Labels{"call": "apply_batch"}
+	labelApplyIterator   = prometheus.Labels{"call": "apply_iterator"}
 	labelSyncGet         = prometheus.Labels{"call": "sync_get"}
 	labelSyncGetPrefixes = prometheus.Labels{"call": "sync_get_prefixes"}
 	labelSyncIterate     = prometheus.Labels{"call": "sync_iterate"}
@@ -78,6 +80,13 @@ func (w *metricsWrapper) EnsureCommitteeVersion(ctx context.Context, version int
 	return ErrUnsupported
 }
 
+func (w *metricsWrapper) GetCheckpointAvailability(namespace common.Namespace) *CheckpointAvailability {
+	if clientBackend, ok := w.Backend.(ClientBackend); ok {
+		return clientBackend.GetCheckpointAvailability(namespace)
+	}
+	return NewCheckpointAvailability(namespace, nil)
+}
+
 func (w *metricsWrapper) Apply(ctx context.Context, request *ApplyRequest) ([]*Receipt, error) {
 	start := time.Now()
 	receipts, err := w.Backend.Apply(ctx, request)
@@ -118,6 +127,44 @@ func (w *metricsWrapper) ApplyBatch(ctx context.Context, request *ApplyBatchRequ
 	return receipts, err
 }
 
+// sizeCountingIterator is a write log iterator that counts the size of the consumed entries.
+type sizeCountingIterator struct {
+	WriteLogIterator
+
+	size int
+}
+
+func (it *sizeCountingIterator) Value() (LogEntry, error) {
+	entry, err := it.WriteLogIterator.Value()
+	if err == nil {
+		it.size += len(entry.Key) + len(entry.Value)
+	}
+	return entry, err
+}
+
+func (w *metricsWrapper) ApplyIterator(ctx context.Context, request *ApplyIteratorRequest) error {
+	localBackend, ok := w.Backend.(LocalBackend)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	it := &sizeCountingIterator{WriteLogIterator: request.WriteLog}
+	rq := *request
+	rq.WriteLog = it
+
+	start := time.Now()
+	err := localBackend.ApplyIterator(ctx, &rq)
+	storageLatency.With(labelApplyIterator).Observe(time.Since(start).Seconds())
+	storageValueSize.With(labelApplyIterator).Observe(float64(it.size))
+	if err != nil {
+		storageFailures.With(labelApplyIterator).Inc()
+		return err
+	}
+
+	storageCalls.With(labelApplyIterator).Inc()
+	return nil
+}
+
 func (w *metricsWrapper) SyncGet(ctx context.Context, request *GetRequest) (*ProofResponse, error) {
 	start := time.Now()
 	res, err := w.Backend.SyncGet(ctx, request)
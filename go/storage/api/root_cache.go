@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
@@ -24,17 +25,20 @@ type RootCache struct {
 	applyLocks      *lru.Cache
 	applyLocksGuard sync.Mutex
 
-	persistEverything mkvs.Option
+	treeOptions []mkvs.Option
 }
 
 // GetTree gets a tree entry from the cache by the root iff present, or creates
 // a new tree with the specified root in the node database.
 func (rc *RootCache) GetTree(ctx context.Context, root Root) (mkvs.Tree, error) {
-	return mkvs.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything), nil
+	return mkvs.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.treeOptions...), nil
 }
 
 // Apply applies the write log, bypassing the apply operation iff the new root
 // already is in the node database.
+//
+// Besides the new root it also returns the number of nodes that have been
+// persisted in the node database.
 func (rc *RootCache) Apply(
 	ctx context.Context,
 	ns common.Namespace,
@@ -43,7 +47,23 @@ func (rc *RootCache) Apply(
 	dstVersion uint64,
 	dstRoot hash.Hash,
 	writeLog WriteLog,
-) (*hash.Hash, error) {
+) (*hash.Hash, uint64, error) {
+	return rc.ApplyIterator(ctx, ns, srcVersion, srcRoot, dstVersion, dstRoot, writelog.NewStaticIterator(writeLog))
+}
+
+// ApplyIterator applies the write log entries yielded by the given iterator, bypassing the apply
+// operation (without consuming the iterator) iff the new root already is in the node database.
+//
+// Besides the new root it also returns the number of nodes that have been written.
+func (rc *RootCache) ApplyIterator(
+	ctx context.Context,
+	ns common.Namespace,
+	srcVersion uint64,
+	srcRoot hash.Hash,
+	dstVersion uint64,
+	dstRoot hash.Hash,
+	it WriteLogIterator,
+) (*hash.Hash, uint64, error) {
 	root := Root{
 		Namespace: ns,
 		Version:   srcVersion,
@@ -57,14 +77,17 @@ func (rc *RootCache) Apply(
 
 	// Sanity check the expected new root.
 	if !expectedNewRoot.Follows(&root) {
-		return nil, ErrRootMustFollowOld
+		return nil, 0, ErrRootMustFollowOld
 	}
 
 	mu := rc.getApplyLock(root, expectedNewRoot)
 	mu.Lock()
 	defer mu.Unlock()
 
-	var r hash.Hash
+	var (
+		r            hash.Hash
+		nodesWritten uint64
+	)
 
 	// Check if we already have the expected new root in our local DB.
 	if rc.localDB.HasRoot(expectedNewRoot) {
@@ -72,11 +95,12 @@ func (rc *RootCache) Apply(
 		r = dstRoot
 	} else {
 		// We don't, apply operations.
-		tree := mkvs.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything)
+		db := &countingNodeDB{NodeDB: rc.localDB, count: &nodesWritten}
+		tree := mkvs.NewWithRoot(rc.remoteSyncer, db, root, rc.treeOptions...)
 		defer tree.Close()
 
-		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
-			return nil, err
+		if err := tree.ApplyWriteLog(ctx, it); err != nil {
+			return nil, 0, err
 		}
 
 		var err error
@@ -92,13 +116,13 @@ func (rc *RootCache) Apply(
 		case nil:
 			r = dstRoot
 		case mkvs.ErrKnownRootMismatch:
-			return nil, ErrExpectedRootMismatch
+			return nil, 0, ErrExpectedRootMismatch
 		default:
-			return nil, err
+			return nil, 0, err
 		}
 	}
 
-	return &r, nil
+	return &r, nodesWritten, nil
 }
 
 func (rc *RootCache) getApplyLock(root, expectedNewRoot Root) *sync.Mutex {
@@ -124,11 +148,16 @@ func (rc *RootCache) HasRoot(root Root) bool {
 	return rc.localDB.HasRoot(root)
 }
 
+// NewRootCache creates a new root cache.
+//
+// In case a tree cache budget is given, the in-memory caches of all trees created by the root
+// cache share it.
 func NewRootCache(
 	localDB nodedb.NodeDB,
 	remoteSyncer syncer.ReadSyncer,
 	applyLockLRUSlots uint64,
 	insecureSkipChecks bool,
+	treeCacheBudget *mkvs.CacheBudget,
 ) (*RootCache, error) {
 	applyLocks, err := lru.New(lru.Capacity(applyLockLRUSlots, false))
 	if err != nil {
@@ -139,12 +168,69 @@ func NewRootCache(
 	// everything that we obtain from the remote syncer in our local
 	// database.
 	persistEverything := mkvs.PersistEverythingFromSyncer(remoteSyncer != nil)
+	treeOptions := []mkvs.Option{persistEverything}
+	if remoteSyncer != nil {
+		// Reuse evicted nodes when syncing to reduce GC pressure.
+		treeOptions = append(treeOptions, mkvs.WithNodeArena())
+	}
+	if treeCacheBudget != nil {
+		treeOptions = append(treeOptions, mkvs.WithCacheBudget(treeCacheBudget))
+	}
 
 	return &RootCache{
 		localDB:            localDB,
 		remoteSyncer:       remoteSyncer,
 		insecureSkipChecks: insecureSkipChecks,
 		applyLocks:         applyLocks,
-		persistEverything:  persistEverything,
+		treeOptions:        treeOptions,
 	}, nil
 }
+
+// countingNodeDB is a node database wrapper that counts the number of nodes
+// persisted via its batches.
+type countingNodeDB struct {
+	nodedb.NodeDB
+
+	count *uint64
+}
+
+func (d *countingNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) (nodedb.Batch, error) {
+	batch, err := d.NodeDB.NewBatch(oldRoot, version, chunk)
+	if err != nil {
+		return nil, err
+	}
+	return &countingBatch{Batch: batch, count: d.count}, nil
+}
+
+type countingBatch struct {
+	nodedb.Batch
+
+	count *uint64
+}
+
+func (b *countingBatch) MaybeStartSubtree(subtree nodedb.Subtree, depth node.Depth, subtreeRoot *node.Pointer) nodedb.Subtree {
+	var inner nodedb.Subtree
+	if cs, ok := subtree.(*countingSubtree); ok {
+		inner = cs.Subtree
+	}
+
+	newSubtree := b.Batch.MaybeStartSubtree(inner, depth, subtreeRoot)
+	if subtree != nil && newSubtree == inner {
+		return subtree
+	}
+	return &countingSubtree{Subtree: newSubtree, count: b.count}
+}
+
+type countingSubtree struct {
+	nodedb.Subtree
+
+	count *uint64
+}
+
+func (s *countingSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
+	if err := s.Subtree.PutNode(depth, ptr); err != nil {
+		return err
+	}
+	*s.count++
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -18,12 +19,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/mathrand"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/committee"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 var (
@@ -37,6 +40,10 @@ var ErrStorageNotAvailable = errors.New("storage/client: storage not available")
 const (
 	retryInterval = 1 * time.Second
 	maxRetries    = 15
+
+	// nodeAvoidanceInterval is the amount of time a storage node that served data which failed
+	// verification is deprioritized for reads.
+	nodeAvoidanceInterval = 5 * time.Minute
 )
 
 // storageClientBackend contains all information about the client storage API
@@ -49,6 +56,36 @@ type storageClientBackend struct {
 
 	committeeClient committee.Client
 	runtime         registry.RuntimeDescriptorProvider
+
+	avoidedNodesLock sync.Mutex
+	avoidedNodes     map[signature.PublicKey]time.Time
+}
+
+// avoidNode deprioritizes the given storage node for reads.
+func (b *storageClientBackend) avoidNode(id signature.PublicKey) {
+	b.avoidedNodesLock.Lock()
+	defer b.avoidedNodesLock.Unlock()
+
+	if b.avoidedNodes == nil {
+		b.avoidedNodes = make(map[signature.PublicKey]time.Time)
+	}
+	b.avoidedNodes[id] = time.Now().Add(nodeAvoidanceInterval)
+}
+
+// isNodeAvoided returns true iff the given storage node is currently deprioritized for reads.
+func (b *storageClientBackend) isNodeAvoided(id signature.PublicKey) bool {
+	b.avoidedNodesLock.Lock()
+	defer b.avoidedNodesLock.Unlock()
+
+	until, ok := b.avoidedNodes[id]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(b.avoidedNodes, id)
+		return false
+	}
+	return true
 }
 
 // Implements api.StorageClient.
@@ -65,6 +102,11 @@ func (b *storageClientBackend) EnsureCommitteeVersion(ctx context.Context, versi
 	return b.committeeClient.EnsureVersion(ctx, version)
 }
 
+// Implements api.StorageClient.
+func (b *storageClientBackend) GetCheckpointAvailability(namespace common.Namespace) *api.CheckpointAvailability {
+	return api.NewCheckpointAvailability(namespace, b.GetConnectedNodes())
+}
+
 type grpcResponse struct {
 	resp interface{}
 	err  error
@@ -78,6 +120,7 @@ func (b *storageClientBackend) writeWithClient(
 	round uint64,
 	fn func(context.Context, api.Backend, *node.Node) (interface{}, error),
 	expectedNewRoots []hash.Hash,
+	expectedWriteLogHash *hash.Hash,
 ) ([]*api.Receipt, error) {
 	conns := b.committeeClient.GetConnectionsWithMeta()
 	n := len(conns)
@@ -169,11 +212,16 @@ func (b *storageClientBackend) writeWithClient(
 		// NOTE: All storage backend implementations of apply operations return
 		// a list of storage receipts. However, a concrete storage backend,
 		// e.g. storage/database, actually returns a single storage receipt
-		// in a list.
-		if len(receiptList) != 1 {
-			b.logger.Error("got more than one receipt from a storage node",
+		// in a list (followed by an attested receipt if requested).
+		expectedReceipts := 1
+		if expectedWriteLogHash != nil {
+			expectedReceipts = 2
+		}
+		if len(receiptList) != expectedReceipts {
+			b.logger.Error("got unexpected number of receipts from a storage node",
 				"node", response.node,
 				"num_receipts", len(receiptList),
+				"expected_receipts", expectedReceipts,
 			)
 			continue
 		}
@@ -226,6 +274,16 @@ func (b *storageClientBackend) writeWithClient(
 			continue
 		}
 
+		if expectedWriteLogHash != nil {
+			if err := verifyAttestedReceipt(receipt, &receiptBody, receiptList[1], expectedWriteLogHash); err != nil {
+				b.logger.Error("invalid attested receipt from a storage node",
+					"node", response.node,
+					"err", err,
+				)
+				continue
+			}
+		}
+
 		receipts = append(receipts, receipt)
 		if len(receipts) >= minWriteReplication {
 			break
@@ -254,7 +312,53 @@ func (b *storageClientBackend) writeWithClient(
 	}
 }
 
+// verifyAttestedReceipt verifies that the attested receipt has been signed by
+// the same storage node as the regular receipt, that it covers the same roots
+// and that it commits to the expected write log(s).
+func verifyAttestedReceipt(
+	receipt *api.Receipt,
+	receiptBody *api.ReceiptBody,
+	attestedReceipt *api.Receipt,
+	expectedWriteLogHash *hash.Hash,
+) error {
+	if !attestedReceipt.Signature.PublicKey.Equal(receipt.Signature.PublicKey) {
+		return fmt.Errorf("attested receipt signed by a different signer")
+	}
+
+	var attestedBody api.ReceiptBody
+	if err := attestedReceipt.Open(&attestedBody); err != nil {
+		return fmt.Errorf("failed to open attested receipt: %w", err)
+	}
+	if attestedBody.Attestation == nil {
+		return fmt.Errorf("missing attestation")
+	}
+	if !attestedBody.Namespace.Equal(&receiptBody.Namespace) || attestedBody.Round != receiptBody.Round {
+		return fmt.Errorf("attested receipt does not match regular receipt")
+	}
+	if len(attestedBody.Roots) != len(receiptBody.Roots) {
+		return fmt.Errorf("attested receipt has unexpected number of roots")
+	}
+	for i := range attestedBody.Roots {
+		if !attestedBody.Roots[i].Equal(&receiptBody.Roots[i]) {
+			return fmt.Errorf("attested receipt does not match regular receipt")
+		}
+	}
+	if !attestedBody.Attestation.WriteLogHash.Equal(expectedWriteLogHash) {
+		return fmt.Errorf("attested write log hash mismatch (expected: %s got: %s)",
+			expectedWriteLogHash,
+			attestedBody.Attestation.WriteLogHash,
+		)
+	}
+	return nil
+}
+
 func (b *storageClientBackend) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
+	var expectedWriteLogHash *hash.Hash
+	if request.Attest {
+		h := api.WriteLogsHash([]api.WriteLog{request.WriteLog})
+		expectedWriteLogHash = &h
+	}
+
 	return b.writeWithClient(
 		ctx,
 		request.Namespace,
@@ -263,13 +367,22 @@ func (b *storageClientBackend) Apply(ctx context.Context, request *api.ApplyRequ
 			return c.Apply(ctx, request)
 		},
 		[]hash.Hash{request.DstRoot},
+		expectedWriteLogHash,
 	)
 }
 
 func (b *storageClientBackend) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
 	expectedNewRoots := make([]hash.Hash, 0, len(request.Ops))
+	writeLogs := make([]api.WriteLog, 0, len(request.Ops))
 	for _, op := range request.Ops {
 		expectedNewRoots = append(expectedNewRoots, op.DstRoot)
+		writeLogs = append(writeLogs, op.WriteLog)
+	}
+
+	var expectedWriteLogHash *hash.Hash
+	if request.Attest {
+		h := api.WriteLogsHash(writeLogs)
+		expectedWriteLogHash = &h
 	}
 
 	return b.writeWithClient(
@@ -280,6 +393,7 @@ func (b *storageClientBackend) ApplyBatch(ctx context.Context, request *api.Appl
 			return c.ApplyBatch(ctx, request)
 		},
 		expectedNewRoots,
+		expectedWriteLogHash,
 	)
 }
 
@@ -321,6 +435,22 @@ func (b *storageClientBackend) readWithClient(
 		rng.Shuffle(len(ordinaryNodes), func(i, j int) {
 			ordinaryNodes[i], ordinaryNodes[j] = ordinaryNodes[j], ordinaryNodes[i]
 		})
+		// Finally move any nodes that recently served invalid data or that the caller asked to
+		// avoid to the end.
+		avoidHint := make(map[signature.PublicKey]bool)
+		for _, nodeID := range api.NodeAvoidHintFromContext(ctx) {
+			avoidHint[nodeID] = true
+		}
+		var avoidedNodes []*committee.ClientConnWithMeta
+		preferredNodes := nodes[:0]
+		for _, c := range nodes {
+			if avoidHint[c.Node.ID] || b.isNodeAvoided(c.Node.ID) {
+				avoidedNodes = append(avoidedNodes, c)
+				continue
+			}
+			preferredNodes = append(preferredNodes, c)
+		}
+		nodes = append(preferredNodes, avoidedNodes...)
 
 		var err error
 		for _, conn := range nodes {
@@ -334,8 +464,14 @@ func (b *storageClientBackend) readWithClient(
 					"err", err,
 					"runtime_id", ns,
 				)
+				if errors.Is(err, api.ErrDiffVerificationFailed) {
+					b.avoidNode(conn.Node.ID)
+				}
 				continue
 			}
+			if recorder := api.SourceRecorderFromContext(ctx); recorder != nil {
+				recorder(conn.Node.ID)
+			}
 			return nil
 		}
 		return err
@@ -389,11 +525,39 @@ func (b *storageClientBackend) SyncIterate(ctx context.Context, request *api.Ite
 }
 
 func (b *storageClientBackend) GetDiff(ctx context.Context, request *api.GetDiffRequest) (api.WriteLogIterator, error) {
+	verifier := api.DiffVerifierFromContext(ctx)
 	rsp, err := b.readWithClient(
 		ctx,
 		request.StartRoot.Namespace,
 		func(ctx context.Context, c api.Backend) (interface{}, error) {
-			return c.GetDiff(ctx, request)
+			it, err := c.GetDiff(ctx, request)
+			if err != nil || verifier == nil {
+				return it, err
+			}
+
+			// In case a verifier is configured, fetch the complete write log so that it can be
+			// verified before it is handed out and other nodes can be tried on failure.
+			var (
+				writeLog api.WriteLog
+				more     bool
+				entry    api.LogEntry
+			)
+			for {
+				if more, err = it.Next(); err != nil {
+					return nil, err
+				}
+				if !more {
+					break
+				}
+				if entry, err = it.Value(); err != nil {
+					return nil, err
+				}
+				writeLog = append(writeLog, entry)
+			}
+			if err = verifier(ctx, request, writeLog); err != nil {
+				return nil, fmt.Errorf("%w: %s", api.ErrDiffVerificationFailed, err)
+			}
+			return writelog.NewStaticIterator(writeLog), nil
 		},
 	)
 	if err != nil {
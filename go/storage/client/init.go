@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -14,8 +15,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 )
 
-// BackendName is the name of this implementation.
-const BackendName = "client"
+const (
+	// BackendName is the name of this implementation.
+	BackendName = "client"
+
+	// healthCheckInterval is the interval between storage node connection health checks.
+	healthCheckInterval = 15 * time.Second
+)
 
 // NewForCommittee creates a new storage client that tracks the specified committee.
 func NewForCommittee(
@@ -25,7 +31,13 @@ func NewForCommittee(
 	nodes committee.NodeDescriptorLookup,
 	runtime registry.RuntimeDescriptorProvider,
 ) (api.Backend, error) {
-	committeeClient, err := committee.NewClient(ctx, nodes, committee.WithClientAuthentication(ident))
+	committeeClient, err := committee.NewClient(
+		ctx,
+		nodes,
+		committee.WithClientAuthentication(ident),
+		committee.WithHealthCheckInterval(healthCheckInterval),
+		committee.WithMetrics(namespace, scheduler.KindStorage),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("storage/client: failed to create committee client: %w", err)
 	}
@@ -8,9 +8,11 @@ import (
 	"io"
 	"path/filepath"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
@@ -66,7 +68,12 @@ func New(cfg *api.Config) (api.Backend, error) {
 		return nil, fmt.Errorf("storage/database: failed to create node database: %w", err)
 	}
 
-	rootCache, err := api.NewRootCache(ndb, nil, cfg.ApplyLockLRUSlots, cfg.InsecureSkipChecks)
+	var treeCacheBudget *mkvs.CacheBudget
+	if cfg.TreeCacheSize > 0 {
+		treeCacheBudget = mkvs.NewCacheBudget(cfg.TreeCacheSize)
+	}
+
+	rootCache, err := api.NewRootCache(ndb, nil, cfg.ApplyLockLRUSlots, cfg.InsecureSkipChecks, treeCacheBudget)
 	if err != nil {
 		ndb.Close()
 		return nil, fmt.Errorf("storage/database: failed to create root cache: %w", err)
@@ -103,7 +110,7 @@ func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest)
 		return nil, fmt.Errorf("storage/database: failed to Apply: %w", api.ErrReadOnly)
 	}
 
-	newRoot, err := ba.rootCache.Apply(
+	newRoot, nodesWritten, err := ba.rootCache.Apply(
 		ctx,
 		request.Namespace,
 		request.SrcRound,
@@ -116,8 +123,34 @@ func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest)
 		return nil, fmt.Errorf("storage/database: failed to Apply: %w", err)
 	}
 
-	receipt, err := api.SignReceipt(ba.signer, request.Namespace, request.DstRound, []hash.Hash{*newRoot})
-	return []*api.Receipt{receipt}, err
+	var attestation *api.ReceiptAttestation
+	if request.Attest {
+		attestation = &api.ReceiptAttestation{
+			NodesWritten: nodesWritten,
+			WriteLogHash: api.WriteLogsHash([]api.WriteLog{request.WriteLog}),
+		}
+	}
+	return ba.signReceipts(request.Namespace, request.DstRound, []hash.Hash{*newRoot}, attestation)
+}
+
+func (ba *databaseBackend) ApplyIterator(ctx context.Context, request *api.ApplyIteratorRequest) error {
+	if ba.readOnly {
+		return fmt.Errorf("storage/database: failed to ApplyIterator: %w", api.ErrReadOnly)
+	}
+
+	_, _, err := ba.rootCache.ApplyIterator(
+		ctx,
+		request.Namespace,
+		request.SrcRound,
+		request.SrcRoot,
+		request.DstRound,
+		request.DstRoot,
+		request.WriteLog,
+	)
+	if err != nil {
+		return fmt.Errorf("storage/database: failed to ApplyIterator: %w", err)
+	}
+	return nil
 }
 
 func (ba *databaseBackend) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
@@ -125,17 +158,48 @@ func (ba *databaseBackend) ApplyBatch(ctx context.Context, request *api.ApplyBat
 		return nil, fmt.Errorf("storage/database: failed to ApplyBatch: %w", api.ErrReadOnly)
 	}
 
+	var totalNodesWritten uint64
 	newRoots := make([]hash.Hash, 0, len(request.Ops))
+	writeLogs := make([]api.WriteLog, 0, len(request.Ops))
 	for _, op := range request.Ops {
-		newRoot, err := ba.rootCache.Apply(ctx, request.Namespace, op.SrcRound, op.SrcRoot, request.DstRound, op.DstRoot, op.WriteLog)
+		newRoot, nodesWritten, err := ba.rootCache.Apply(ctx, request.Namespace, op.SrcRound, op.SrcRoot, request.DstRound, op.DstRoot, op.WriteLog)
 		if err != nil {
 			return nil, fmt.Errorf("storage/database: failed to Apply, op: %w", err)
 		}
 		newRoots = append(newRoots, *newRoot)
+		writeLogs = append(writeLogs, op.WriteLog)
+		totalNodesWritten += nodesWritten
 	}
 
-	receipt, err := api.SignReceipt(ba.signer, request.Namespace, request.DstRound, newRoots)
-	return []*api.Receipt{receipt}, err
+	var attestation *api.ReceiptAttestation
+	if request.Attest {
+		attestation = &api.ReceiptAttestation{
+			NodesWritten: totalNodesWritten,
+			WriteLogHash: api.WriteLogsHash(writeLogs),
+		}
+	}
+	return ba.signReceipts(request.Namespace, request.DstRound, newRoots, attestation)
+}
+
+func (ba *databaseBackend) signReceipts(
+	ns common.Namespace,
+	round uint64,
+	roots []hash.Hash,
+	attestation *api.ReceiptAttestation,
+) ([]*api.Receipt, error) {
+	receipt, err := api.SignReceipt(ba.signer, ns, round, roots)
+	if err != nil {
+		return []*api.Receipt{receipt}, err
+	}
+	if attestation == nil {
+		return []*api.Receipt{receipt}, nil
+	}
+
+	attestedReceipt, err := api.SignAttestedReceipt(ba.signer, ns, round, roots, attestation)
+	if err != nil {
+		return nil, err
+	}
+	return []*api.Receipt{receipt, attestedReceipt}, nil
 }
 
 func (ba *databaseBackend) Cleanup() {
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"crypto/rand"
 	"io/ioutil"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/tests"
@@ -20,12 +22,16 @@ func TestStorageDatabase(t *testing.T) {
 		BackendNameBadgerDB,
 	} {
 		t.Run(v, func(t *testing.T) {
-			doTestImpl(t, v)
+			doTestImpl(t, v, 0)
+		})
+		// Use a small tree cache budget to make sure that syncing works with frequent evictions.
+		t.Run(v+"/TreeCacheBudget", func(t *testing.T) {
+			doTestImpl(t, v, 4*1024)
 		})
 	}
 }
 
-func doTestImpl(t *testing.T, backend string) {
+func doTestImpl(t *testing.T, backend string, treeCacheSize uint64) {
 	require := require.New(t)
 
 	testNs := common.NewTestNamespaceFromSeed([]byte("database backend test ns"), 0)
@@ -37,6 +43,7 @@ func doTestImpl(t *testing.T, backend string) {
 			Namespace:         testNs,
 			MaxCacheSize:      16 * 1024 * 1024,
 			NoFsync:           true,
+			TreeCacheSize:     treeCacheSize,
 		}
 		err error
 	)
@@ -55,4 +62,55 @@ func doTestImpl(t *testing.T, backend string) {
 	localBackend := impl.(api.LocalBackend)
 
 	tests.StorageImplementationTests(t, localBackend, impl, testNs, 0)
+
+	t.Run("Attestation", func(t *testing.T) {
+		testAttestation(t, impl, testNs)
+	})
+}
+
+func testAttestation(t *testing.T, backend api.Backend, namespace common.Namespace) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	wl := api.WriteLog{
+		{Key: []byte("attested key 1"), Value: []byte("attested value 1")},
+		{Key: []byte("attested key 2"), Value: []byte("attested value 2")},
+	}
+	expectedNewRoot := tests.CalculateExpectedNewRoot(t, wl, namespace, 0)
+	expectedWriteLogHash := api.WriteLogsHash([]api.WriteLog{wl})
+
+	apply := func() *api.ReceiptAttestation {
+		receipts, err := backend.Apply(ctx, &api.ApplyRequest{
+			Namespace: namespace,
+			SrcRound:  0,
+			SrcRoot:   emptyRoot,
+			DstRound:  0,
+			DstRoot:   expectedNewRoot,
+			WriteLog:  wl,
+			Attest:    true,
+		})
+		require.NoError(err, "Apply")
+		require.Len(receipts, 2, "Apply should return a regular and an attested receipt")
+
+		var body, attestedBody api.ReceiptBody
+		err = receipts[0].Open(&body)
+		require.NoError(err, "Open")
+		require.Nil(body.Attestation, "regular receipt should not include an attestation")
+		err = receipts[1].Open(&attestedBody)
+		require.NoError(err, "Open")
+		require.NotNil(attestedBody.Attestation, "attested receipt should include an attestation")
+		require.EqualValues(body.Roots, attestedBody.Roots, "attested receipt should cover the same roots")
+		require.EqualValues(expectedWriteLogHash, attestedBody.Attestation.WriteLogHash, "attested write log hash should be correct")
+		return attestedBody.Attestation
+	}
+
+	attestation := apply()
+	require.NotZero(attestation.NodesWritten, "nodes should be written on first apply")
+
+	// Applying the same write log again should not write any nodes.
+	attestation = apply()
+	require.Zero(attestation.NodesWritten, "no nodes should be written on repeated apply")
 }
@@ -0,0 +1,258 @@
+package mkvs
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// batchEntry is a write log entry that is applied as part of a batch.
+type batchEntry struct {
+	key   node.Key
+	value []byte
+
+	result insertResult
+}
+
+// Implements Tree.
+//
+// Instead of applying entries one by one, the write log is sorted by key so that removals and
+// insertions of keys sharing a common prefix only traverse the shared part of the tree once and
+// the nodes on the shared path stay hot in the cache.
+func (t *tree) ApplyWriteLog(ctx context.Context, wl writelog.Iterator) error {
+	var entries []*batchEntry
+	for {
+		// Fetch next entry from write log iterator.
+		more, err := wl.Next()
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+		entry, err := wl.Value()
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, &batchEntry{key: entry.Key, value: entry.Value})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Sort entries by key. In case the same key is updated multiple times, only the last update
+	// is retained which is equivalent to applying the updates in order.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	var (
+		inserts []*batchEntry
+		removes []*batchEntry
+	)
+	for i, entry := range entries {
+		if i+1 < len(entries) && bytes.Equal(entry.key, entries[i+1].key) {
+			continue
+		}
+		if entry.value == nil {
+			removes = append(removes, entry)
+		} else {
+			inserts = append(inserts, entry)
+		}
+	}
+
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	if t.cache.isClosed() {
+		return ErrClosed
+	}
+
+	// Remember where the path from root to target nodes ends (will end).
+	t.cache.markPosition()
+
+	// As updates to distinct keys are commutative, all removals can be applied first.
+	for _, entry := range removes {
+		if err := t.applyBatchRemove(ctx, entry.key); err != nil {
+			return err
+		}
+	}
+
+	if len(inserts) > 0 {
+		newRoot, err := t.doBatchInsert(ctx, t.cache.pendingRoot, 0, inserts, 0)
+		if err != nil {
+			return err
+		}
+		t.cache.setPendingRoot(newRoot)
+
+		// Update the pending write log.
+		if !t.withoutWriteLog {
+			for _, entry := range inserts {
+				pending := t.pendingWriteLog[node.ToMapKey(entry.key)]
+				if pending == nil {
+					t.pendingWriteLog[node.ToMapKey(entry.key)] = &pendingEntry{
+						key:          entry.key,
+						value:        entry.value,
+						existed:      entry.result.existed,
+						insertedLeaf: entry.result.insertedLeaf,
+					}
+				} else {
+					pending.value = entry.value
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyBatchRemove removes a single key as part of a batch.
+//
+// The cache lock must be held.
+func (t *tree) applyBatchRemove(ctx context.Context, key node.Key) error {
+	// If the key has already been removed locally, don't try to remove it again.
+	var entry *pendingEntry
+	if !t.withoutWriteLog {
+		if entry = t.pendingWriteLog[node.ToMapKey(key)]; entry != nil && entry.value == nil {
+			return nil
+		}
+	}
+
+	newRoot, changed, _, err := t.doRemove(ctx, t.cache.pendingRoot, 0, key, 0)
+	if err != nil {
+		return err
+	}
+
+	// Update the pending write log.
+	if !t.withoutWriteLog {
+		if entry == nil {
+			t.pendingWriteLog[node.ToMapKey(key)] = &pendingEntry{key, nil, changed, nil}
+		} else {
+			entry.value = nil
+		}
+	}
+
+	t.cache.setPendingRoot(newRoot)
+	return nil
+}
+
+// doBatchInsert inserts a batch of entries sorted by key into the subtree rooted at the given
+// pointer. All entries must share the same key prefix up to the given bit depth.
+//
+// Entries that follow the label of an existing internal node are partitioned among its children
+// so that the shared part of the path is only traversed once. Remaining entries are inserted one
+// by one.
+func (t *tree) doBatchInsert(
+	ctx context.Context,
+	ptr *node.Pointer,
+	bitDepth node.Depth,
+	entries []*batchEntry,
+	depth node.Depth,
+) (*node.Pointer, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if len(entries) > 1 {
+		// Dereference the node, possibly making a remote request.
+		nd, err := t.cache.derefNodePtr(ctx, ptr, t.newFetcherSyncGet(entries[0].key, false))
+		if err != nil {
+			return nil, err
+		}
+
+		if n, ok := nd.(*node.InternalNode); ok {
+			// Entries that diverge from the node's label require the edge to be split and are
+			// inserted one by one after the rest of the batch.
+			var following, diverging []*batchEntry
+			for _, entry := range entries {
+				if followsLabel(n, bitDepth, entry.key) {
+					following = append(following, entry)
+				} else {
+					diverging = append(diverging, entry)
+				}
+			}
+			if len(following) > 0 {
+				if err = t.doBatchInsertInternal(ctx, ptr, n, bitDepth, following, depth); err != nil {
+					return nil, err
+				}
+			}
+			entries = diverging
+		}
+	}
+
+	// Insert remaining entries one by one.
+	for _, entry := range entries {
+		result, err := t.doInsert(ctx, ptr, bitDepth, entry.key, entry.value, depth)
+		if err != nil {
+			return nil, err
+		}
+		entry.result = result
+		ptr = result.newRoot
+	}
+	return ptr, nil
+}
+
+// doBatchInsertInternal inserts a batch of entries sorted by key that all fully match the label
+// of the given internal node into the node's subtrees.
+func (t *tree) doBatchInsertInternal(
+	ctx context.Context,
+	ptr *node.Pointer,
+	n *node.InternalNode,
+	bitDepth node.Depth,
+	entries []*batchEntry,
+	depth node.Depth,
+) (err error) {
+	bitLength := bitDepth + n.LabelBitLength
+
+	// Partition entries based on where they continue. As entries are sorted, the entry ending
+	// exactly at this node (if any) comes first, followed by the left and then the right subtree
+	// entries.
+	var leafEntries []*batchEntry
+	rest := entries
+	if rest[0].key.BitLength() == bitLength {
+		leafEntries, rest = rest[:1], rest[1:]
+	}
+	split := sort.Search(len(rest), func(i int) bool {
+		return rest[i].key.GetBit(bitLength)
+	})
+	leftEntries, rightEntries := rest[:split], rest[split:]
+
+	if len(leafEntries) > 0 {
+		if n.LeafNode, err = t.doBatchInsert(ctx, n.LeafNode, bitLength, leafEntries, depth); err != nil {
+			return err
+		}
+	}
+	if len(leftEntries) > 0 {
+		if n.Left, err = t.doBatchInsert(ctx, n.Left, bitLength, leftEntries, depth+1); err != nil {
+			return err
+		}
+	}
+	if len(rightEntries) > 0 {
+		if n.Right, err = t.doBatchInsert(ctx, n.Right, bitLength, rightEntries, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if !n.LeafNode.IsClean() || !n.Left.IsClean() || !n.Right.IsClean() {
+		if n.Clean {
+			// Node was clean so old node is eligible for removal.
+			t.pendingRemovedNodes = append(t.pendingRemovedNodes, n.ExtractUnchecked())
+		}
+
+		n.Clean = false
+		ptr.Clean = false
+		// No longer eligible for eviction as it is dirty.
+		t.cache.rollbackNode(ptr)
+	}
+	return nil
+}
+
+// followsLabel checks whether the given key fully matches the label of the given internal node.
+func followsLabel(n *node.InternalNode, bitDepth node.Depth, key node.Key) bool {
+	_, keyRemainder := key.Split(bitDepth, key.BitLength())
+	cpLength := n.Label.CommonPrefixLen(n.LabelBitLength, keyRemainder, key.BitLength()-bitDepth)
+	return cpLength == n.LabelBitLength
+}
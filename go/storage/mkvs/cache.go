@@ -40,8 +40,16 @@ type cache struct {
 	nodeCapacity uint64
 	// Maximum capacity of leaf values.
 	valueCapacity uint64
+	// Optional memory budget shared with other caches. If set, it is used instead of the node
+	// and value capacities.
+	budget *CacheBudget
+	// Amount of the shared memory budget used by this cache.
+	budgetSize uint64
 	// Persist all the nodes and values we obtain from the remote syncer?
 	persistEverythingFromSyncer bool
+	// Optional arena used to allocate nodes obtained from the remote syncer. Nodes evicted from
+	// the cache are released back into the arena.
+	arena *node.Arena
 
 	lruInternal    *list.List
 	lruInternalPos *list.Element
@@ -84,6 +92,12 @@ func (c *cache) close() {
 	// Reset statistics.
 	c.valueSize = 0
 	c.internalNodeCount = 0
+
+	// Release any used memory budget.
+	if c.budget != nil {
+		c.budget.release(c.budgetSize)
+		c.budgetSize = 0
+	}
 }
 
 func (c *cache) isClosed() bool {
@@ -150,9 +164,13 @@ func (c *cache) useNode(ptr *node.Pointer) {
 //
 // This makes it possible to keep the path from the root to the derefed
 // node in the cache instead of evicting it.
+//
+// As this is called before any nodes are visited, nodes evicted during previous
+// operations are no longer referenced and can be reused by the node arena.
 func (c *cache) markPosition() {
 	c.lruInternalPos = c.lruInternal.Front()
 	c.lruLeafPos = c.lruLeaf.Front()
+	c.arena.Reclaim()
 }
 
 func (c *cache) tryCommitNode(ptr, lockedPtr *node.Pointer) error {
@@ -168,9 +186,18 @@ func (c *cache) tryCommitNode(ptr, lockedPtr *node.Pointer) error {
 	}
 
 	// Evict nodes till there is enough capacity.
+	if c.budget != nil {
+		size := cachedNodeSize(ptr.Node)
+		if err := c.tryEvictBudget(size, lockedPtr); err != nil {
+			return err
+		}
+		c.budget.use(size)
+		c.budgetSize += size
+	}
+
 	switch n := ptr.Node.(type) {
 	case *node.InternalNode:
-		if c.nodeCapacity > 0 && c.internalNodeCount+1 > c.nodeCapacity {
+		if c.budget == nil && c.nodeCapacity > 0 && c.internalNodeCount+1 > c.nodeCapacity {
 			if err := c.tryEvictInternal(1, lockedPtr); err != nil {
 				return err
 			}
@@ -185,7 +212,7 @@ func (c *cache) tryCommitNode(ptr, lockedPtr *node.Pointer) error {
 	case *node.LeafNode:
 		valueSize := n.Size()
 
-		if c.valueCapacity > 0 && c.valueSize+valueSize > c.valueCapacity {
+		if c.budget == nil && c.valueCapacity > 0 && c.valueSize+valueSize > c.valueCapacity {
 			if err := c.tryEvictLeaf(valueSize, lockedPtr); err != nil {
 				return err
 			}
@@ -228,11 +255,27 @@ func (c *cache) rollbackNode(ptr *node.Pointer) {
 		c.lruLeaf.Remove(ptr.LRU)
 		c.valueSize -= n.Size()
 	}
+	c.releaseBudget(ptr.Node)
 
 	ptr.LRU = nil
 }
 
-func (c *cache) tryRemoveNode(ptr, lockedPtr *node.Pointer) error {
+// releaseBudget releases the shared memory budget used by the given node.
+func (c *cache) releaseBudget(n node.Node) {
+	if c.budget == nil {
+		return
+	}
+
+	size := cachedNodeSize(n)
+	c.budget.release(size)
+	c.budgetSize -= size
+}
+
+// tryRemoveNode removes a tree node and its subtree from the cache.
+//
+// In case release is true, the removed nodes are no longer referenced (e.g., on eviction) and are
+// released back into the node arena.
+func (c *cache) tryRemoveNode(ptr, lockedPtr *node.Pointer, release bool) error {
 	if lockedPtr != nil && lockedPtr == ptr {
 		return errRemoveLocked
 	}
@@ -245,19 +288,19 @@ func (c *cache) tryRemoveNode(ptr, lockedPtr *node.Pointer) error {
 	case *node.InternalNode:
 		// Remove leaf node and subtrees first.
 		if n.LeafNode != nil && n.LeafNode.Node != nil {
-			if err := c.tryRemoveNode(n.LeafNode, lockedPtr); err != nil {
+			if err := c.tryRemoveNode(n.LeafNode, lockedPtr, release); err != nil {
 				return err
 			}
 			n.LeafNode = nil
 		}
 		if n.Left != nil && n.Left.Node != nil {
-			if err := c.tryRemoveNode(n.Left, lockedPtr); err != nil {
+			if err := c.tryRemoveNode(n.Left, lockedPtr, release); err != nil {
 				return err
 			}
 			n.Left = nil
 		}
 		if n.Right != nil && n.Right.Node != nil {
-			if err := c.tryRemoveNode(n.Right, lockedPtr); err != nil {
+			if err := c.tryRemoveNode(n.Right, lockedPtr, release); err != nil {
 				return err
 			}
 			n.Right = nil
@@ -275,6 +318,10 @@ func (c *cache) tryRemoveNode(ptr, lockedPtr *node.Pointer) error {
 		c.lruLeaf.Remove(ptr.LRU)
 		c.valueSize -= n.Size()
 	}
+	c.releaseBudget(ptr.Node)
+	if release {
+		c.arena.Release(ptr.Node)
+	}
 
 	ptr.Node = nil
 	ptr.LRU = nil
@@ -283,7 +330,7 @@ func (c *cache) tryRemoveNode(ptr, lockedPtr *node.Pointer) error {
 
 // removeNode removes a tree node.
 func (c *cache) removeNode(ptr *node.Pointer) {
-	_ = c.tryRemoveNode(ptr, nil)
+	_ = c.tryRemoveNode(ptr, nil, false)
 }
 
 // tryEvictLeaf tries to evict leaf nodes from the cache.
@@ -294,7 +341,7 @@ func (c *cache) tryEvictLeaf(targetCapacity uint64, lockedPtr *node.Pointer) err
 		if !n.Clean {
 			panic(fmt.Errorf("mkvs: tried to evict dirty node %v", n))
 		}
-		if err := c.tryRemoveNode(n, lockedPtr); err != nil {
+		if err := c.tryRemoveNode(n, lockedPtr, true); err != nil {
 			return err
 		}
 	}
@@ -309,9 +356,38 @@ func (c *cache) tryEvictInternal(targetCapacity uint64, lockedPtr *node.Pointer)
 		if !n.Clean {
 			panic(fmt.Errorf("mkvs: tried to evict dirty node %v", n))
 		}
-		if err := c.tryRemoveNode(n, lockedPtr); err != nil {
+		if err := c.tryRemoveNode(n, lockedPtr, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tryEvictBudget tries to evict nodes from the cache until a node of the given size fits into the
+// shared memory budget. Leaf nodes are evicted first as they hold the values.
+//
+// As only nodes from this cache can be evicted, the budget may still be exceeded in case other
+// caches are using most of it.
+func (c *cache) tryEvictBudget(targetSize uint64, lockedPtr *node.Pointer) error {
+	for c.budget.exceeded(targetSize) {
+		var elem *list.Element
+		switch {
+		case c.lruLeaf.Len() > 0:
+			elem = c.lruLeaf.Back()
+		case c.lruInternal.Len() > 0:
+			elem = c.lruInternal.Back()
+		default:
+			return nil
+		}
+
+		n := elem.Value.(*node.Pointer)
+		if !n.Clean {
+			panic(fmt.Errorf("mkvs: tried to evict dirty node %v", n))
+		}
+		if err := c.tryRemoveNode(n, lockedPtr, true); err != nil {
 			return err
 		}
+		c.budget.recordEviction()
 	}
 	return nil
 }
@@ -348,6 +424,9 @@ func (c *cache) derefNodePtr(
 		}
 
 		if !refetch {
+			if c.budget != nil {
+				c.budget.recordHit()
+			}
 			return ptr.Node, nil
 		}
 	}
@@ -355,6 +434,9 @@ func (c *cache) derefNodePtr(
 	if !ptr.Clean || ptr.Hash.IsEmpty() {
 		return nil, nil
 	}
+	if c.budget != nil {
+		c.budget.recordMiss()
+	}
 
 	// First, attempt to fetch from the local node database.
 	n, err := c.db.GetNode(c.syncRoot, ptr)
@@ -0,0 +1,141 @@
+package mkvs
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+var (
+	cacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_cache_hits",
+			Help: "Number of MKVS in-memory tree cache hits.",
+		},
+	)
+	cacheMisses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_cache_misses",
+			Help: "Number of MKVS in-memory tree cache misses.",
+		},
+	)
+	cacheEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_cache_evictions",
+			Help: "Number of nodes evicted from MKVS in-memory tree caches.",
+		},
+	)
+	cacheSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_storage_mkvs_cache_size_bytes",
+			Help: "Total size of MKVS in-memory tree caches using a shared memory budget (bytes).",
+		},
+	)
+
+	cacheCollectors = []prometheus.Collector{
+		cacheHits,
+		cacheMisses,
+		cacheEvictions,
+		cacheSize,
+	}
+
+	cacheMetricsOnce sync.Once
+)
+
+// CacheStats are the in-memory tree cache statistics.
+type CacheStats struct {
+	// Capacity is the capacity of the memory budget in bytes.
+	Capacity uint64 `json:"capacity"`
+	// Size is the amount of the memory budget currently in use in bytes.
+	Size uint64 `json:"size"`
+	// Hits is the number of node lookups that were served from memory.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of node lookups that required fetching the node from the node
+	// database or the remote syncer.
+	Misses uint64 `json:"misses"`
+	// Evictions is the number of nodes that have been evicted to stay within the budget.
+	Evictions uint64 `json:"evictions"`
+}
+
+// CacheBudget is a memory budget that is shared by the in-memory caches of all trees created
+// with it (e.g., all trees backed by the same node database).
+//
+// Each cache can only evict its own nodes, so in case other caches are using the whole budget,
+// a cache may temporarily exceed it until the other caches release their nodes.
+type CacheBudget struct {
+	// NOTE: Fields accessed atomically must come first to ensure alignment.
+	size      uint64
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	capacity uint64
+}
+
+// Stats returns the current cache statistics.
+func (b *CacheBudget) Stats() CacheStats {
+	return CacheStats{
+		Capacity:  b.capacity,
+		Size:      atomic.LoadUint64(&b.size),
+		Hits:      atomic.LoadUint64(&b.hits),
+		Misses:    atomic.LoadUint64(&b.misses),
+		Evictions: atomic.LoadUint64(&b.evictions),
+	}
+}
+
+func (b *CacheBudget) exceeded(size uint64) bool {
+	return atomic.LoadUint64(&b.size)+size > b.capacity
+}
+
+func (b *CacheBudget) use(size uint64) {
+	atomic.AddUint64(&b.size, size)
+	cacheSize.Add(float64(size))
+}
+
+func (b *CacheBudget) release(size uint64) {
+	atomic.AddUint64(&b.size, ^(size - 1))
+	cacheSize.Sub(float64(size))
+}
+
+func (b *CacheBudget) recordHit() {
+	atomic.AddUint64(&b.hits, 1)
+	cacheHits.Inc()
+}
+
+func (b *CacheBudget) recordMiss() {
+	atomic.AddUint64(&b.misses, 1)
+	cacheMisses.Inc()
+}
+
+func (b *CacheBudget) recordEviction() {
+	atomic.AddUint64(&b.evictions, 1)
+	cacheEvictions.Inc()
+}
+
+// NewCacheBudget creates a new in-memory tree cache memory budget with the given capacity in
+// bytes.
+func NewCacheBudget(capacityBytes uint64) *CacheBudget {
+	cacheMetricsOnce.Do(func() {
+		prometheus.MustRegister(cacheCollectors...)
+	})
+
+	return &CacheBudget{
+		capacity: capacityBytes,
+	}
+}
+
+// cachedNodeSize returns the amount of memory accounted for a node kept in the cache. As opposed
+// to the node's Size method it does not include the size of any child nodes.
+func cachedNodeSize(n node.Node) uint64 {
+	switch n := n.(type) {
+	case *node.InternalNode:
+		return node.PointerSize + node.InternalNodeSize + uint64(len(n.Label))
+	case *node.LeafNode:
+		return node.PointerSize + n.Size()
+	default:
+		return 0
+	}
+}
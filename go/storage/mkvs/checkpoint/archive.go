@@ -0,0 +1,267 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	archiveManifestFile = "manifest.json"
+	archiveVersion      = 1
+)
+
+// ArchiveManifest is the manifest of a storage archive.
+//
+// A storage archive is a portable copy of all finalized roots of a single version of a node
+// database. Next to the manifest, it contains a checkpoint for each of the roots, laid out in the
+// same way as checkpoints created by the file checkpoint creator.
+type ArchiveManifest struct {
+	// Version is the archive format version.
+	Version uint16 `json:"version"`
+	// Namespace is the namespace of the archived roots.
+	Namespace common.Namespace `json:"namespace"`
+	// RootVersion is the version of the archived roots.
+	RootVersion uint64 `json:"root_version"`
+	// Checkpoints are the checkpoints of the archived roots.
+	Checkpoints []*Metadata `json:"checkpoints"`
+}
+
+// Roots returns the archived root hashes.
+func (m *ArchiveManifest) Roots() []hash.Hash {
+	roots := make([]hash.Hash, 0, len(m.Checkpoints))
+	for _, cp := range m.Checkpoints {
+		roots = append(roots, cp.Root.Hash)
+	}
+	return roots
+}
+
+// Validate performs basic sanity checks on the archive manifest.
+func (m *ArchiveManifest) Validate() error {
+	if m.Version != archiveVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrArchiveInvalid, m.Version)
+	}
+	if len(m.Checkpoints) == 0 {
+		return fmt.Errorf("%w: no checkpoints", ErrArchiveInvalid)
+	}
+	for _, cp := range m.Checkpoints {
+		if cp.Version != checkpointVersion {
+			return fmt.Errorf("%w: unsupported checkpoint version %d", ErrArchiveInvalid, cp.Version)
+		}
+		if !cp.Root.Namespace.Equal(&m.Namespace) || cp.Root.Version != m.RootVersion {
+			return fmt.Errorf("%w: checkpoint for unexpected root %s", ErrArchiveInvalid, cp.Root)
+		}
+		if len(cp.Chunks) == 0 {
+			return fmt.Errorf("%w: checkpoint for root %s has no chunks", ErrArchiveInvalid, cp.Root)
+		}
+	}
+	return nil
+}
+
+// ExportArchive writes a storage archive of all roots of the given finalized version into the
+// given directory.
+//
+// Checkpoints that already exist in the directory (e.g., from an interrupted export) are reused.
+func ExportArchive(
+	ctx context.Context,
+	ndb db.NodeDB,
+	namespace common.Namespace,
+	version uint64,
+	chunkSize uint64,
+	dir string,
+) (*ArchiveManifest, error) {
+	latestVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to get latest version: %w", err)
+	}
+	if version > latestVersion {
+		return nil, fmt.Errorf("checkpoint: version %d: %w", version, db.ErrNotFinalized)
+	}
+
+	// Pin the version for the whole export so that all roots are exported from the same state.
+	snap, err := ndb.Snapshot(version)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to snapshot version %d: %w", version, err)
+	}
+	defer snap.Close()
+
+	rootHashes, err := ndb.GetRootsForVersion(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to get roots for version %d: %w", version, err)
+	}
+	if len(rootHashes) == 0 {
+		return nil, fmt.Errorf("checkpoint: no roots for version %d", version)
+	}
+	sort.Slice(rootHashes, func(i, j int) bool {
+		return bytes.Compare(rootHashes[i][:], rootHashes[j][:]) < 0
+	})
+
+	if err = common.Mkdir(dir); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to create archive directory: %w", err)
+	}
+
+	fc := &fileCreator{
+		dataDir: dir,
+		ndb:     ndb,
+	}
+	manifest := &ArchiveManifest{
+		Version:     archiveVersion,
+		Namespace:   namespace,
+		RootVersion: version,
+	}
+	for _, rootHash := range rootHashes {
+		root := node.Root{
+			Namespace: namespace,
+			Version:   version,
+			Hash:      rootHash,
+		}
+		var cp *Metadata
+		if cp, err = fc.CreateCheckpoint(ctx, root, chunkSize); err != nil {
+			return nil, err
+		}
+		manifest.Checkpoints = append(manifest.Checkpoints, cp)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to marshal archive manifest: %w", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, archiveManifestFile), data, 0o600); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to write archive manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadArchiveManifest reads and validates the manifest of the storage archive in the given
+// directory.
+func ReadArchiveManifest(dir string) (*ArchiveManifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, archiveManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to read archive manifest: %w", err)
+	}
+
+	var manifest ArchiveManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: malformed manifest: %s", ErrArchiveInvalid, err)
+	}
+	if err = manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// VerifyArchive verifies that all chunks of the storage archive in the given directory are
+// present and match the digests recorded in the manifest.
+func VerifyArchive(ctx context.Context, dir string, manifest *ArchiveManifest) error {
+	fc := &fileCreator{dataDir: dir}
+	for _, cp := range manifest.Checkpoints {
+		for idx := range cp.Chunks {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			chunk, err := cp.GetChunkMetadata(uint64(idx))
+			if err != nil {
+				return err
+			}
+
+			hb := hash.NewBuilder()
+			if err = fc.GetCheckpointChunk(ctx, chunk, hb); err != nil {
+				return fmt.Errorf("checkpoint: root %s chunk %d: %w", cp.Root, idx, err)
+			}
+			if h := hb.Build(); !chunk.Digest.Equal(&h) {
+				return fmt.Errorf("%w: root %s chunk %d digest incorrect (expected: %s got: %s)",
+					ErrChunkCorrupted,
+					cp.Root,
+					idx,
+					chunk.Digest,
+					h,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// ImportArchive restores the storage archive in the given directory into the node database and
+// finalizes the archived version.
+//
+// All chunks are verified before anything is written into the node database so an incomplete or
+// corrupted archive can be fixed and the import retried. Importing an archive whose roots are
+// already present and finalized is a no-op.
+func ImportArchive(ctx context.Context, ndb db.NodeDB, dir string) (*ArchiveManifest, error) {
+	manifest, err := ReadArchiveManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the import in case it has already been completed.
+	latestVersion, err := ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to get latest version: %w", err)
+	}
+	if latestVersion >= manifest.RootVersion {
+		imported := true
+		for _, cp := range manifest.Checkpoints {
+			if !ndb.HasRoot(cp.Root) {
+				imported = false
+				break
+			}
+		}
+		if imported {
+			return manifest, nil
+		}
+	}
+
+	if err = VerifyArchive(ctx, dir, manifest); err != nil {
+		return nil, err
+	}
+
+	rs, err := NewRestorer(ndb)
+	if err != nil {
+		return nil, err
+	}
+	if err = importArchive(ctx, ndb, rs, dir, manifest); err != nil {
+		_ = rs.AbortRestore(ctx)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func importArchive(ctx context.Context, ndb db.NodeDB, rs Restorer, dir string, manifest *ArchiveManifest) error {
+	fc := &fileCreator{dataDir: dir}
+	for _, cp := range manifest.Checkpoints {
+		if err := rs.StartRestore(ctx, cp); err != nil {
+			return fmt.Errorf("checkpoint: failed to start restore of root %s: %w", cp.Root, err)
+		}
+
+		for idx := range cp.Chunks {
+			chunk, err := cp.GetChunkMetadata(uint64(idx))
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			if err = fc.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+				return fmt.Errorf("checkpoint: root %s chunk %d: %w", cp.Root, idx, err)
+			}
+			if _, err = rs.RestoreChunk(ctx, uint64(idx), &buf); err != nil {
+				return fmt.Errorf("checkpoint: failed to restore root %s chunk %d: %w", cp.Root, idx, err)
+			}
+		}
+	}
+
+	if err := ndb.Finalize(ctx, manifest.RootVersion, manifest.Roots()); err != nil {
+		return fmt.Errorf("checkpoint: failed to finalize version %d: %w", manifest.RootVersion, err)
+	}
+	return nil
+}
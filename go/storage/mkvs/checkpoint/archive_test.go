@@ -0,0 +1,147 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpoint.archive")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:        filepath.Join(dir, "db"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	// Generate two versions with two roots each.
+	ctx := context.Background()
+	root := node.Root{
+		Namespace: testNs,
+	}
+	root.Hash.Empty()
+	var roots []node.Root
+	for v := uint64(0); v < 2; v++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		for i := 0; i < 1000; i++ {
+			err = tree.Insert(ctx, []byte(strconv.Itoa(int(v)*1000+i)), []byte(strconv.Itoa(i)))
+			require.NoError(err, "Insert")
+		}
+		var stateRoot hash.Hash
+		_, stateRoot, err = tree.Commit(ctx, testNs, v)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		ioTree := mkvs.New(nil, ndb)
+		err = ioTree.Insert(ctx, []byte("io"), []byte(strconv.Itoa(int(v))))
+		require.NoError(err, "Insert")
+		var ioRoot hash.Hash
+		_, ioRoot, err = ioTree.Commit(ctx, testNs, v)
+		require.NoError(err, "Commit")
+		ioTree.Close()
+
+		err = ndb.Finalize(ctx, v, []hash.Hash{stateRoot, ioRoot})
+		require.NoError(err, "Finalize")
+
+		root.Version = v
+		root.Hash = stateRoot
+		roots = []node.Root{root, {Namespace: testNs, Version: v, Hash: ioRoot}}
+	}
+
+	archiveDir := filepath.Join(dir, "archive")
+	_, err = ExportArchive(ctx, ndb, testNs, 2, 16*1024, archiveDir)
+	require.True(errors.Is(err, db.ErrNotFinalized), "ExportArchive should fail for a non-finalized version")
+
+	manifest, err := ExportArchive(ctx, ndb, testNs, 1, 16*1024, archiveDir)
+	require.NoError(err, "ExportArchive")
+	require.EqualValues(1, manifest.RootVersion)
+	require.Len(manifest.Checkpoints, 2, "all roots of the version should be archived")
+	require.ElementsMatch([]hash.Hash{roots[0].Hash, roots[1].Hash}, manifest.Roots())
+
+	readManifest, err := ReadArchiveManifest(archiveDir)
+	require.NoError(err, "ReadArchiveManifest")
+	require.EqualValues(manifest, readManifest, "manifest should round-trip")
+
+	// Exporting again should reuse the existing checkpoints.
+	manifest2, err := ExportArchive(ctx, ndb, testNs, 1, 16*1024, archiveDir)
+	require.NoError(err, "ExportArchive")
+	require.EqualValues(manifest, manifest2)
+
+	ndb2, err := badgerDb.New(&db.Config{
+		DB:        filepath.Join(dir, "db2"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+	defer ndb2.Close()
+
+	// Corrupt a chunk, import should fail before anything is written.
+	chunkFn := filepath.Join(
+		archiveDir,
+		"1",
+		roots[0].Hash.String(),
+		chunksDir,
+		"0",
+	)
+	chunkData, err := ioutil.ReadFile(chunkFn)
+	require.NoError(err, "ReadFile")
+	err = ioutil.WriteFile(chunkFn, chunkData[:len(chunkData)/2], 0o600)
+	require.NoError(err, "WriteFile")
+
+	_, err = ImportArchive(ctx, ndb2, archiveDir)
+	require.True(errors.Is(err, ErrChunkCorrupted), "ImportArchive should fail with a corrupted chunk")
+	require.False(ndb2.HasRoot(roots[1]), "nothing should be imported from a corrupted archive")
+
+	// Fix the chunk and retry the import.
+	err = ioutil.WriteFile(chunkFn, chunkData, 0o600)
+	require.NoError(err, "WriteFile")
+
+	_, err = ImportArchive(ctx, ndb2, archiveDir)
+	require.NoError(err, "ImportArchive")
+
+	latestVersion, err := ndb2.GetLatestVersion(ctx)
+	require.NoError(err, "GetLatestVersion")
+	require.EqualValues(1, latestVersion, "archived version should be finalized")
+
+	for _, r := range roots {
+		require.True(ndb2.HasRoot(r), "archived root should be imported")
+
+		tree1 := mkvs.NewWithRoot(nil, ndb, r)
+		tree2 := mkvs.NewWithRoot(nil, ndb2, r)
+		it1 := tree1.NewIterator(ctx)
+		it2 := tree2.NewIterator(ctx)
+		it2.Rewind()
+		for it1.Rewind(); it1.Valid(); it1.Next() {
+			require.True(it2.Valid(), "imported tree should contain all keys")
+			require.EqualValues(it1.Key(), it2.Key())
+			require.EqualValues(it1.Value(), it2.Value())
+			it2.Next()
+		}
+		require.False(it2.Valid(), "imported tree should not contain extra keys")
+		it1.Close()
+		it2.Close()
+		tree1.Close()
+		tree2.Close()
+	}
+
+	// Importing again should be a no-op.
+	_, err = ImportArchive(ctx, ndb2, archiveDir)
+	require.NoError(err, "ImportArchive on an already imported archive should work")
+}
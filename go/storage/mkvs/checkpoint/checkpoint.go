@@ -3,6 +3,7 @@ package checkpoint
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -35,6 +36,26 @@ var (
 
 	// ErrChunkCorrupted is the error when a chunk is corrupted.
 	ErrChunkCorrupted = errors.New(moduleName, 7, "chunk: corrupted chunk")
+
+	// ErrChainContextMismatch is the error when a checkpoint has been created for a different chain.
+	ErrChainContextMismatch = errors.New(moduleName, 8, "checkpoint: chain context mismatch")
+
+	// ErrNamespaceMismatch is the error when a checkpoint has been created for a different namespace.
+	ErrNamespaceMismatch = errors.New(moduleName, 9, "checkpoint: namespace mismatch")
+
+	// ErrParametersMismatch is the error when a checkpoint has been created with different creation
+	// parameters.
+	ErrParametersMismatch = errors.New(moduleName, 10, "checkpoint: creation parameters mismatch")
+
+	// ErrCheckpointingDisabled is the error when checkpoints cannot be created because
+	// checkpointing is disabled.
+	ErrCheckpointingDisabled = errors.New(moduleName, 11, "checkpoint: checkpointing disabled")
+
+	// ErrObjectNotFound is the error when an object is not found in an external object store.
+	ErrObjectNotFound = errors.New(moduleName, 12, "checkpoint: object not found")
+
+	// ErrArchiveInvalid is the error when a storage archive is malformed or inconsistent.
+	ErrArchiveInvalid = errors.New(moduleName, 13, "checkpoint: invalid archive")
 )
 
 // ChunkProvider is a chunk provider.
@@ -61,7 +82,7 @@ type Creator interface {
 	ChunkProvider
 
 	// CreateCheckpoint creates a new checkpoint at the given root.
-	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error)
+	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64, opts ...CreateOption) (*Metadata, error)
 
 	// GetCheckpoint retrieves checkpoint metadata for a specific checkpoint.
 	GetCheckpoint(ctx context.Context, version uint16, root node.Root) (*Metadata, error)
@@ -70,6 +91,37 @@ type Creator interface {
 	DeleteCheckpoint(ctx context.Context, version uint16, root node.Root) error
 }
 
+// CreateOption is a checkpoint creation option.
+type CreateOption func(*Metadata)
+
+// WithChainContext records the chain domain separation context of the network the checkpoint is
+// being created on in the checkpoint metadata.
+func WithChainContext(chainContext string) CreateOption {
+	return func(m *Metadata) {
+		m.ChainContext = chainContext
+	}
+}
+
+// WithCreationParameters records the checkpoint creation parameters in the checkpoint metadata.
+func WithCreationParameters(params *CreationParameters) CreateOption {
+	return func(m *Metadata) {
+		if params == nil {
+			m.Parameters = nil
+			return
+		}
+		p := *params
+		m.Parameters = &p
+	}
+}
+
+// WithOnDemand marks the checkpoint as created on demand. Such checkpoints are ignored by the
+// checkpointer when scheduling and garbage collecting regular checkpoints.
+func WithOnDemand() CreateOption {
+	return func(m *Metadata) {
+		m.OnDemand = true
+	}
+}
+
 // Restorer is a checkpoint restorer.
 type Restorer interface {
 	// StartRestore starts a checkpoint restoration process.
@@ -124,6 +176,41 @@ type Metadata struct {
 	Version uint16      `json:"version"`
 	Root    node.Root   `json:"root"`
 	Chunks  []hash.Hash `json:"chunks"`
+
+	// ChainContext is the chain domain separation context of the network the checkpoint has been
+	// created on. It is empty for checkpoints that do not record it (e.g., genesis checkpoints).
+	ChainContext string `json:"chain_context,omitempty"`
+	// Parameters are the parameters the checkpoint has been created with. They are nil for
+	// checkpoints that do not record them (e.g., genesis checkpoints).
+	Parameters *CreationParameters `json:"parameters,omitempty"`
+	// OnDemand is true for checkpoints that have been explicitly requested instead of being
+	// created by the checkpointer on its regular interval.
+	OnDemand bool `json:"on_demand,omitempty"`
+}
+
+// VerifyOrigin verifies that the checkpoint has been created for the given chain context and
+// namespace with the given creation parameters and can thus be restored.
+//
+// Only the properties recorded in the checkpoint metadata are checked. The creation parameters
+// are not checked in case params is nil.
+func (m *Metadata) VerifyOrigin(chainContext string, namespace common.Namespace, params *CreationParameters) error {
+	if !m.Root.Namespace.Equal(&namespace) {
+		return fmt.Errorf("%w: expected %s got %s", ErrNamespaceMismatch, namespace, m.Root.Namespace)
+	}
+	if m.ChainContext != "" && m.ChainContext != chainContext {
+		return fmt.Errorf("%w: expected %s got %s", ErrChainContextMismatch, chainContext, m.ChainContext)
+	}
+	if m.Parameters != nil && params != nil {
+		// The number of kept checkpoints is a local retention policy that does not affect the
+		// checkpoint itself so it is not checked.
+		if m.Parameters.Interval != params.Interval {
+			return fmt.Errorf("%w: expected interval %d got %d", ErrParametersMismatch, params.Interval, m.Parameters.Interval)
+		}
+		if m.Parameters.ChunkSize != params.ChunkSize {
+			return fmt.Errorf("%w: expected chunk size %d got %d", ErrParametersMismatch, params.ChunkSize, m.Parameters.ChunkSize)
+		}
+	}
+	return nil
 }
 
 // EncodedHash returns the encoded cryptographic hash of the checkpoint metadata.
@@ -403,3 +403,151 @@ func TestPruneGapAfterCheckpointRestore(t *testing.T) {
 	err = ndb2.Prune(ctx, checkpointRootVersion)
 	require.NoError(err, "Prune(%d)", checkpointRootVersion)
 }
+
+func TestConcurrentRestore(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpoint")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	ctx := context.Background()
+	tree := mkvs.New(nil, ndb)
+	for i := 0; i < 1000; i++ {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(err, "Insert")
+	}
+
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit")
+	root := node.Root{
+		Namespace: testNs,
+		Version:   1,
+		Hash:      rootHash,
+	}
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+	cp, err := fc.CreateCheckpoint(ctx, root, 1024)
+	require.NoError(err, "CreateCheckpoint")
+	require.True(len(cp.Chunks) > 1, "there should be multiple chunks")
+
+	// Fetch all chunks.
+	chunks := make([][]byte, len(cp.Chunks))
+	for i := range cp.Chunks {
+		var cm *ChunkMetadata
+		cm, err = cp.GetChunkMetadata(uint64(i))
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		chunks[i] = buf.Bytes()
+	}
+
+	// Restore all chunks concurrently, restoring each chunk twice.
+	ndb2, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db2"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+	err = rs.StartRestore(ctx, cp)
+	require.NoError(err, "StartRestore")
+
+	type result struct {
+		done bool
+		err  error
+	}
+	resultCh := make(chan *result, 2*len(chunks))
+	for i := 0; i < 2*len(chunks); i++ {
+		go func(idx int) {
+			done, rErr := rs.RestoreChunk(ctx, uint64(idx), bytes.NewReader(chunks[idx]))
+			resultCh <- &result{done, rErr}
+		}(i % len(chunks))
+	}
+
+	var numDone, numRestored int
+	for i := 0; i < 2*len(chunks); i++ {
+		r := <-resultCh
+		switch r.err {
+		case nil:
+			numRestored++
+			if r.done {
+				numDone++
+			}
+		default:
+			require.True(errors.Is(r.err, ErrChunkAlreadyRestored) || errors.Is(r.err, ErrNoRestoreInProgress),
+				"RestoreChunk should only fail as the chunk was already restored")
+		}
+	}
+	require.Equal(len(chunks), numRestored, "each chunk should be restored exactly once")
+	require.Equal(1, numDone, "restoration should be completed exactly once")
+	require.Nil(rs.GetCurrentCheckpoint(), "restore should no longer be in progress")
+
+	err = ndb2.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+	require.NoError(err, "Finalize")
+
+	// Verify that everything has been restored.
+	tree = mkvs.NewWithRoot(nil, ndb2, root)
+	for i := 0; i < 1000; i++ {
+		var value []byte
+		value, err = tree.Get(ctx, []byte(strconv.Itoa(i)))
+		require.NoError(err, "Get")
+		require.Equal([]byte(strconv.Itoa(i)), value)
+	}
+}
+
+func TestMetadataVerifyOrigin(t *testing.T) {
+	require := require.New(t)
+
+	otherNs := common.NewTestNamespaceFromSeed([]byte("oasis mkvs checkpoint test other ns"), 0)
+	params := &CreationParameters{
+		Interval:  10,
+		NumKept:   2,
+		ChunkSize: 16 * 1024,
+	}
+
+	// Metadata without origin information should only be checked for the namespace.
+	cp := &Metadata{
+		Version: 1,
+		Root:    node.Root{Namespace: testNs, Version: 10},
+	}
+	err := cp.VerifyOrigin("chain context", testNs, params)
+	require.NoError(err, "VerifyOrigin")
+	err = cp.VerifyOrigin("chain context", otherNs, params)
+	require.True(errors.Is(err, ErrNamespaceMismatch), "VerifyOrigin should fail with wrong namespace")
+
+	// Metadata with origin information.
+	WithChainContext("chain context")(cp)
+	WithCreationParameters(params)(cp)
+	require.NotSame(params, cp.Parameters, "WithCreationParameters should store a copy")
+
+	err = cp.VerifyOrigin("chain context", testNs, params)
+	require.NoError(err, "VerifyOrigin")
+	err = cp.VerifyOrigin("chain context", testNs, nil)
+	require.NoError(err, "VerifyOrigin should not check parameters when none are given")
+	err = cp.VerifyOrigin("other chain context", testNs, params)
+	require.True(errors.Is(err, ErrChainContextMismatch), "VerifyOrigin should fail with wrong chain context")
+
+	otherParams := *params
+	otherParams.NumKept = 5
+	err = cp.VerifyOrigin("chain context", testNs, &otherParams)
+	require.NoError(err, "VerifyOrigin should ignore the number of kept checkpoints")
+	otherParams.Interval = 20
+	err = cp.VerifyOrigin("chain context", testNs, &otherParams)
+	require.True(errors.Is(err, ErrParametersMismatch), "VerifyOrigin should fail with wrong interval")
+	otherParams = *params
+	otherParams.ChunkSize = 32 * 1024
+	err = cp.VerifyOrigin("chain context", testNs, &otherParams)
+	require.True(errors.Is(err, ErrParametersMismatch), "VerifyOrigin should fail with wrong chunk size")
+}
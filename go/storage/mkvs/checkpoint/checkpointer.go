@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/eapache/channels"
@@ -29,6 +30,10 @@ type CheckpointerConfig struct {
 	// RootsPerVersion is the number of roots per version.
 	RootsPerVersion int
 
+	// ChainContext is the chain domain separation context recorded in created checkpoints. If
+	// empty, no chain context is recorded.
+	ChainContext string
+
 	// Parameters are the checkpoint creation parameters.
 	Parameters *CreationParameters
 	// GetParameters can be used instead of specifying Parameters to dynamically fetch the current
@@ -40,29 +45,51 @@ type CheckpointerConfig struct {
 	//
 	// This must return exactly RootsPerVersion roots.
 	GetRoots func(context.Context, uint64) ([]hash.Hash, error)
+
+	// CheckpointsUpdated can be used to get notified about the list of all current checkpoints
+	// after each successful checkpointing pass (e.g., in order to advertise them).
+	CheckpointsUpdated func(context.Context, []*Metadata)
+
+	// Store is an optional external object store that all created checkpoints are uploaded to.
+	Store ObjectStore
 }
 
 // CreationParameters are the checkpoint creation parameters used by the checkpointer.
 type CreationParameters struct {
 	// Interval is the expected runtime state checkpoint interval (in rounds).
-	Interval uint64
+	Interval uint64 `json:"interval"`
 
 	// NumKept is the expected minimum number of checkpoints to keep.
-	NumKept uint64
+	NumKept uint64 `json:"num_kept"`
 
 	// ChunkSize is the chunk size parameter for checkpoint creation.
-	ChunkSize uint64
+	ChunkSize uint64 `json:"chunk_size"`
 }
 
 // Checkpointer is a checkpointer.
 type Checkpointer interface {
 	// NotifyNewVersion notifies the checkpointer that a new version has been finalized.
 	NotifyNewVersion(version uint64)
+
+	// ForceCheckpoint creates checkpoints for all roots of the given finalized version, even if
+	// the version is not on the regular checkpoint interval. The version must still be present
+	// in the node database.
+	//
+	// Checkpoints created this way are not subject to garbage collection and are kept until they
+	// are explicitly removed via DeleteCheckpoints.
+	ForceCheckpoint(ctx context.Context, version uint64) ([]*Metadata, error)
+
+	// DeleteCheckpoints removes all checkpoints for the given version that have been created via
+	// ForceCheckpoint.
+	DeleteCheckpoints(ctx context.Context, version uint64) error
 }
 
 type checkpointer struct {
 	cfg CheckpointerConfig
 
+	// lock serializes checkpoint creation and garbage collection.
+	lock sync.Mutex
+
 	ndb      db.NodeDB
 	creator  Creator
 	notifyCh *channels.RingChannel
@@ -76,7 +103,119 @@ func (c *checkpointer) NotifyNewVersion(version uint64) {
 	c.notifyCh.In() <- version
 }
 
-func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *CreationParameters) (err error) {
+// Implements Checkpointer.
+func (c *checkpointer) ForceCheckpoint(ctx context.Context, version uint64) ([]*Metadata, error) {
+	params, err := c.getParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.ChunkSize == 0 {
+		return nil, ErrCheckpointingDisabled
+	}
+
+	earliest, err := c.ndb.GetEarliestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpointer: failed to get earliest version: %w", err)
+	}
+	latest, err := c.ndb.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpointer: failed to get latest version: %w", err)
+	}
+	if version < earliest || version > latest {
+		return nil, fmt.Errorf("%w: version %d not in range [%d, %d]", db.ErrVersionNotFound, version, earliest, latest)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.logger.Info("checkpointing version on demand",
+		"version", version,
+	)
+
+	cps, err := c.checkpoint(ctx, version, params, WithOnDemand())
+	if err != nil {
+		return nil, err
+	}
+	c.checkpointsUpdated(ctx)
+
+	return cps, nil
+}
+
+// Implements Checkpointer.
+func (c *checkpointer) DeleteCheckpoints(ctx context.Context, version uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:     checkpointVersion,
+		Namespace:   c.cfg.Namespace,
+		RootVersion: &version,
+	})
+	if err != nil {
+		return fmt.Errorf("checkpointer: failed to get existing checkpoints: %w", err)
+	}
+
+	var found bool
+	for _, cp := range cps {
+		if !cp.OnDemand {
+			continue
+		}
+		found = true
+
+		c.logger.Info("deleting on-demand checkpoint",
+			"root", cp.Root,
+		)
+		if err = c.creator.DeleteCheckpoint(ctx, checkpointVersion, cp.Root); err != nil {
+			return fmt.Errorf("checkpointer: failed to delete checkpoint: %w", err)
+		}
+	}
+	if !found {
+		return ErrCheckpointNotFound
+	}
+	c.checkpointsUpdated(ctx)
+
+	return nil
+}
+
+func (c *checkpointer) getParameters(ctx context.Context) (*CreationParameters, error) {
+	params := c.cfg.Parameters
+	if params == nil && c.cfg.GetParameters != nil {
+		var err error
+		params, err = c.cfg.GetParameters(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checkpointer: failed to get checkpoint parameters: %w", err)
+		}
+	}
+	if params == nil {
+		return nil, fmt.Errorf("checkpointer: no checkpoint parameters")
+	}
+	return params, nil
+}
+
+func (c *checkpointer) checkpointsUpdated(ctx context.Context) {
+	if c.cfg.CheckpointsUpdated == nil {
+		return
+	}
+
+	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   checkpointVersion,
+		Namespace: c.cfg.Namespace,
+	})
+	if err != nil {
+		c.logger.Error("failed to get checkpoints",
+			"err", err,
+		)
+		return
+	}
+	c.cfg.CheckpointsUpdated(ctx, cps)
+}
+
+func (c *checkpointer) checkpoint(
+	ctx context.Context,
+	version uint64,
+	params *CreationParameters,
+	opts ...CreateOption,
+) (cps []*Metadata, err error) {
 	var rootHashes []hash.Hash
 	if c.cfg.GetRoots == nil {
 		rootHashes, err = c.ndb.GetRootsForVersion(ctx, version)
@@ -84,10 +223,10 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 		rootHashes, err = c.cfg.GetRoots(ctx, version)
 	}
 	if err != nil {
-		return fmt.Errorf("checkpointer: failed to get storage roots: %w", err)
+		return nil, fmt.Errorf("checkpointer: failed to get storage roots: %w", err)
 	}
 	if len(rootHashes) != c.cfg.RootsPerVersion {
-		return fmt.Errorf("checkpointer: unexpected number of roots for version (expected: %d got: %d)",
+		return nil, fmt.Errorf("checkpointer: unexpected number of roots for version (expected: %d got: %d)",
 			c.cfg.RootsPerVersion,
 			len(rootHashes),
 		)
@@ -119,19 +258,29 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			"chunk_size", params.ChunkSize,
 		)
 
-		_, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize)
+		var cp *Metadata
+		cp, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize,
+			append([]CreateOption{
+				WithChainContext(c.cfg.ChainContext),
+				WithCreationParameters(params),
+			}, opts...)...,
+		)
 		if err != nil {
 			c.logger.Error("failed to create checkpoint",
 				"root", root,
 				"err", err,
 			)
-			return fmt.Errorf("checkpointer: failed to create checkpoint: %w", err)
+			return nil, fmt.Errorf("checkpointer: failed to create checkpoint: %w", err)
 		}
+		cps = append(cps, cp)
 	}
-	return nil
+	return cps, nil
 }
 
 func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, params *CreationParameters) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	// Get a list of all current checkpoints.
 	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
 		Version:   checkpointVersion,
@@ -146,6 +295,11 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 	var cpVersions []uint64
 	cpsByVersion := make(map[uint64][]node.Root)
 	for _, cp := range cps {
+		// Checkpoints created on demand are neither part of the regular schedule nor subject to
+		// garbage collection.
+		if cp.OnDemand {
+			continue
+		}
 		if cpsByVersion[cp.Root.Version] == nil {
 			cpVersions = append(cpVersions, cp.Root.Version)
 		}
@@ -163,7 +317,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 			"version", cpVersion,
 		)
 
-		if err = c.checkpoint(ctx, cpVersion, params); err != nil {
+		if _, err = c.checkpoint(ctx, cpVersion, params); err != nil {
 			c.logger.Error("failed to checkpoint version",
 				"version", cpVersion,
 				"err", err,
@@ -221,20 +375,12 @@ func (c *checkpointer) worker(ctx context.Context) {
 			}
 
 			// Fetch current checkpoint parameters.
-			params := c.cfg.Parameters
-			if params == nil && c.cfg.GetParameters != nil {
-				var err error
-				params, err = c.cfg.GetParameters(ctx)
-				if err != nil {
-					c.logger.Error("failed to get checkpoint parameters",
-						"err", err,
-						"version", version,
-					)
-					continue
-				}
-			}
-			if params == nil {
-				c.logger.Error("no checkpoint parameters")
+			params, err := c.getParameters(ctx)
+			if err != nil {
+				c.logger.Error("failed to get checkpoint parameters",
+					"err", err,
+					"version", version,
+				)
 				continue
 			}
 
@@ -243,7 +389,7 @@ func (c *checkpointer) worker(ctx context.Context) {
 				continue
 			}
 
-			if err := c.maybeCheckpoint(ctx, version, params); err != nil {
+			if err = c.maybeCheckpoint(ctx, version, params); err != nil {
 				c.logger.Error("failed to checkpoint",
 					"version", version,
 					"err", err,
@@ -251,6 +397,8 @@ func (c *checkpointer) worker(ctx context.Context) {
 				continue
 			}
 
+			c.checkpointsUpdated(ctx)
+
 			// Emit status update if someone is listening. This is only used in tests.
 			select {
 			case c.statusCh <- struct{}{}:
@@ -268,6 +416,10 @@ func NewCheckpointer(
 	creator Creator,
 	cfg CheckpointerConfig,
 ) (Checkpointer, error) {
+	if cfg.Store != nil {
+		creator = NewObjectStoreCreator(creator, cfg.Store)
+	}
+
 	c := &checkpointer{
 		cfg:      cfg,
 		ndb:      ndb,
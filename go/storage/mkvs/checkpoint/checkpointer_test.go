@@ -2,6 +2,7 @@ package checkpoint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -49,6 +50,7 @@ func TestCheckpointer(t *testing.T) {
 		Namespace:       testNs,
 		CheckInterval:   testCheckInterval,
 		RootsPerVersion: 1,
+		ChainContext:    "test chain context",
 		Parameters: &CreationParameters{
 			Interval:  1,
 			NumKept:   testNumKept,
@@ -91,6 +93,115 @@ func TestCheckpointer(t *testing.T) {
 			})
 			require.NoError(err, "GetCheckpoints")
 			require.Len(cps, testNumKept+1, "incorrect number of live checkpoints")
+			for _, m := range cps {
+				require.Equal("test chain context", m.ChainContext, "checkpoint should record the chain context")
+				require.EqualValues(16*1024, m.Parameters.ChunkSize, "checkpoint should record the creation parameters")
+			}
 		}
 	}
 }
+
+func TestCheckpointerForceCheckpoint(t *testing.T) {
+	require := require.New(t)
+
+	// Initialize a database.
+	dir, err := ioutil.TempDir("", "mkvs.checkpointer")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+
+	// Create a file-based checkpoint creator.
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	// Create a checkpointer.
+	ctx := context.Background()
+	cp, err := NewCheckpointer(ctx, ndb, fc, CheckpointerConfig{
+		Name:            "test",
+		Namespace:       testNs,
+		CheckInterval:   testCheckInterval,
+		RootsPerVersion: 1,
+		Parameters: &CreationParameters{
+			Interval:  4,
+			NumKept:   2,
+			ChunkSize: 16 * 1024,
+		},
+	})
+	require.NoError(err, "NewCheckpointer")
+
+	getCheckpoints := func() (regular, onDemand []uint64) {
+		cps, cerr := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+			Version:   checkpointVersion,
+			Namespace: testNs,
+		})
+		require.NoError(cerr, "GetCheckpoints")
+		for _, m := range cps {
+			if m.OnDemand {
+				onDemand = append(onDemand, m.Root.Version)
+			} else {
+				regular = append(regular, m.Root.Version)
+			}
+		}
+		return
+	}
+
+	// Finalize a few rounds.
+	var root node.Root
+	root.Empty()
+	root.Namespace = testNs
+
+	for round := uint64(0); round < 12; round++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("round %d", round)), []byte(fmt.Sprintf("value %d", round)))
+		require.NoError(err, "Insert")
+
+		_, rootHash, err := tree.Commit(ctx, testNs, round)
+		require.NoError(err, "Commit")
+
+		root.Version = round
+		root.Hash = rootHash
+
+		err = ndb.Finalize(ctx, root.Version, []hash.Hash{root.Hash})
+		require.NoError(err, "Finalize")
+
+		if round == 3 {
+			// Force a checkpoint of a version that is not on the checkpoint interval.
+			cps, err := cp.ForceCheckpoint(ctx, 1)
+			require.NoError(err, "ForceCheckpoint")
+			require.Len(cps, 1, "ForceCheckpoint should create a checkpoint for each root")
+			require.EqualValues(1, cps[0].Root.Version)
+			require.True(cps[0].OnDemand, "forced checkpoint should be marked as on demand")
+		}
+
+		cp.NotifyNewVersion(round)
+
+		select {
+		case <-cp.(*checkpointer).statusCh:
+		case <-time.After(2 * testCheckInterval):
+			t.Fatalf("failed to wait for checkpointer to checkpoint")
+		}
+	}
+
+	// On-demand checkpoints should neither affect the schedule nor be garbage collected.
+	regular, onDemand := getCheckpoints()
+	require.EqualValues([]uint64{4, 8}, regular, "regular checkpoints should follow the interval")
+	require.EqualValues([]uint64{1}, onDemand, "on-demand checkpoint should be kept")
+
+	// Versions that are not in the database cannot be checkpointed.
+	_, err = cp.ForceCheckpoint(ctx, 100)
+	require.True(errors.Is(err, db.ErrVersionNotFound), "ForceCheckpoint should fail for missing versions")
+
+	// Only on-demand checkpoints can be deleted.
+	err = cp.DeleteCheckpoints(ctx, 4)
+	require.True(errors.Is(err, ErrCheckpointNotFound), "DeleteCheckpoints should not delete regular checkpoints")
+	err = cp.DeleteCheckpoints(ctx, 1)
+	require.NoError(err, "DeleteCheckpoints")
+	_, onDemand = getCheckpoints()
+	require.Empty(onDemand, "on-demand checkpoint should be deleted")
+}
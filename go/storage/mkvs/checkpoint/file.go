@@ -28,8 +28,21 @@ type fileCreator struct {
 	ndb     db.NodeDB
 }
 
-func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (meta *Metadata, err error) {
-	tree := mkvs.NewWithRoot(nil, fc.ndb, root)
+func (fc *fileCreator) CreateCheckpoint(
+	ctx context.Context,
+	root node.Root,
+	chunkSize uint64,
+	opts ...CreateOption,
+) (meta *Metadata, err error) {
+	// Pin the checkpointed version so that it cannot be pruned while the checkpoint is being
+	// created, which may take a long time.
+	snap, err := fc.ndb.Snapshot(root.Version)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to snapshot version %d: %w", root.Version, err)
+	}
+	defer snap.Close()
+
+	tree := mkvs.NewWithRoot(nil, db.NewVersionPinnedNodeDB(fc.ndb, snap), root)
 	defer tree.Close()
 
 	// Create checkpoint directory.
@@ -97,6 +110,9 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 		Root:    root,
 		Chunks:  chunks,
 	}
+	for _, opt := range opts {
+		opt(meta)
+	}
 
 	if err = ioutil.WriteFile(filepath.Join(checkpointDir, checkpointMetadataFile), cbor.Marshal(meta), 0o600); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint metadata: %w", err)
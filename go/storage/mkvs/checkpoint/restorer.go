@@ -20,6 +20,8 @@ type restorer struct {
 	currentCheckpoint *Metadata
 	// pendingChunks is a set of pending chunks.
 	pendingChunks map[uint64]bool
+	// restoringChunks is a set of chunks that are currently being restored.
+	restoringChunks map[uint64]bool
 }
 
 // Implements Restorer.
@@ -36,6 +38,7 @@ func (rs *restorer) StartRestore(ctx context.Context, checkpoint *Metadata) erro
 	}
 
 	rs.currentCheckpoint = checkpoint
+	rs.restoringChunks = make(map[uint64]bool)
 	rs.pendingChunks = make(map[uint64]bool)
 	for idx := range checkpoint.Chunks {
 		rs.pendingChunks[uint64(idx)] = true
@@ -49,6 +52,7 @@ func (rs *restorer) AbortRestore(ctx context.Context) error {
 	defer rs.Unlock()
 
 	rs.pendingChunks = nil
+	rs.restoringChunks = nil
 	rs.currentCheckpoint = nil
 
 	return rs.ndb.AbortMultipartInsert()
@@ -67,47 +71,66 @@ func (rs *restorer) GetCurrentCheckpoint() *Metadata {
 }
 
 // Implements Restorer.
+//
+// Different chunks of the same checkpoint may be restored concurrently.
 func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (bool, error) {
-	chunk, err := func() (*ChunkMetadata, error) {
+	checkpoint, chunk, err := func() (*Metadata, *ChunkMetadata, error) {
 		rs.Lock()
 		defer rs.Unlock()
 
 		if rs.currentCheckpoint == nil {
-			return nil, ErrNoRestoreInProgress
+			return nil, nil, ErrNoRestoreInProgress
 		}
 
 		// Check if the given chunk is still pending.
-		if !rs.pendingChunks[idx] {
-			return nil, ErrChunkAlreadyRestored
+		if !rs.pendingChunks[idx] || rs.restoringChunks[idx] {
+			return nil, nil, ErrChunkAlreadyRestored
 		}
 
-		return rs.currentCheckpoint.GetChunkMetadata(idx)
+		chunk, err := rs.currentCheckpoint.GetChunkMetadata(idx)
+		if err != nil {
+			return nil, nil, err
+		}
+		rs.restoringChunks[idx] = true
+
+		return rs.currentCheckpoint, chunk, nil
 	}()
 	if err != nil {
 		return false, err
 	}
 
 	err = restoreChunk(ctx, rs.ndb, chunk, r)
+
+	rs.Lock()
+	defer rs.Unlock()
+
+	// Make sure the restore has not been aborted in the meantime.
+	if rs.currentCheckpoint != checkpoint {
+		return false, ErrNoRestoreInProgress
+	}
+	delete(rs.restoringChunks, idx)
+
 	switch {
 	case err == nil:
 	case errors.Is(err, ErrChunkProofVerificationFailed):
 		// Chunk was as specified in the manifest but did not match the reported root. In this case
 		// we need to abort processing the given checkpoint.
-		_ = rs.AbortRestore(ctx)
+		rs.pendingChunks = nil
+		rs.restoringChunks = nil
+		rs.currentCheckpoint = nil
+		_ = rs.ndb.AbortMultipartInsert()
 		return false, err
 	default:
 		return false, err
 	}
 
-	rs.Lock()
-	defer rs.Unlock()
-
 	// Mark the given chunk as restored.
 	delete(rs.pendingChunks, idx)
 
 	// If there are no more pending chunks, restore is done.
 	if len(rs.pendingChunks) == 0 {
 		rs.pendingChunks = nil
+		rs.restoringChunks = nil
 		rs.currentCheckpoint = nil
 		return true, nil
 	}
@@ -0,0 +1,176 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// ObjectStore is an external object store (e.g., an S3-compatible bucket) that created
+// checkpoints can be uploaded to so that they can be served without consuming the bandwidth of
+// the node that created them.
+type ObjectStore interface {
+	// PutObject stores the given object under the given key, overwriting any existing object.
+	PutObject(ctx context.Context, key string, data []byte) error
+
+	// GetObject retrieves the object stored under the given key.
+	GetObject(ctx context.Context, key string, w io.Writer) error
+
+	// DeleteObject removes the object stored under the given key.
+	//
+	// It is not an error to delete an object that does not exist.
+	DeleteObject(ctx context.Context, key string) error
+
+	// URL returns the base URL from which the stored objects can be publicly retrieved via HTTP
+	// GET requests by appending their keys.
+	URL() string
+}
+
+// MetadataObjectKey returns the object store key of the metadata of the checkpoint at the given
+// root.
+func MetadataObjectKey(version uint16, root node.Root) string {
+	return strings.Join([]string{
+		strconv.FormatUint(uint64(version), 10),
+		root.Namespace.String(),
+		strconv.FormatUint(root.Version, 10),
+		root.Hash.String(),
+		checkpointMetadataFile,
+	}, "/")
+}
+
+// ChunkObjectKey returns the object store key of the given checkpoint chunk.
+func ChunkObjectKey(chunk *ChunkMetadata) string {
+	return strings.Join([]string{
+		strconv.FormatUint(uint64(chunk.Version), 10),
+		chunk.Root.Namespace.String(),
+		strconv.FormatUint(chunk.Root.Version, 10),
+		chunk.Root.Hash.String(),
+		chunksDir,
+		strconv.FormatUint(chunk.Index, 10),
+	}, "/")
+}
+
+type storeCreator struct {
+	Creator
+
+	store ObjectStore
+}
+
+func (sc *storeCreator) CreateCheckpoint(
+	ctx context.Context,
+	root node.Root,
+	chunkSize uint64,
+	opts ...CreateOption,
+) (*Metadata, error) {
+	meta, err := sc.Creator.CreateCheckpoint(ctx, root, chunkSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upload all chunks before the metadata so that the presence of the metadata object means
+	// that the checkpoint is complete.
+	for idx := range meta.Chunks {
+		var chunk *ChunkMetadata
+		if chunk, err = meta.GetChunkMetadata(uint64(idx)); err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err = sc.Creator.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to read chunk %d: %w", idx, err)
+		}
+		if err = sc.store.PutObject(ctx, ChunkObjectKey(chunk), buf.Bytes()); err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to upload chunk %d: %w", idx, err)
+		}
+	}
+	if err = sc.store.PutObject(ctx, MetadataObjectKey(meta.Version, meta.Root), cbor.Marshal(meta)); err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to upload checkpoint metadata: %w", err)
+	}
+	return meta, nil
+}
+
+func (sc *storeCreator) DeleteCheckpoint(ctx context.Context, version uint16, root node.Root) error {
+	meta, err := sc.Creator.GetCheckpoint(ctx, version, root)
+	if err != nil {
+		return err
+	}
+
+	// Remove the metadata first so that the checkpoint is no longer considered complete in case
+	// removing any of the chunks fails.
+	if err = sc.store.DeleteObject(ctx, MetadataObjectKey(version, root)); err != nil {
+		return fmt.Errorf("checkpoint: failed to remove uploaded checkpoint metadata: %w", err)
+	}
+	for idx := range meta.Chunks {
+		var chunk *ChunkMetadata
+		if chunk, err = meta.GetChunkMetadata(uint64(idx)); err != nil {
+			return err
+		}
+		if err = sc.store.DeleteObject(ctx, ChunkObjectKey(chunk)); err != nil {
+			return fmt.Errorf("checkpoint: failed to remove uploaded chunk %d: %w", idx, err)
+		}
+	}
+
+	return sc.Creator.DeleteCheckpoint(ctx, version, root)
+}
+
+// NewObjectStoreCreator wraps the given checkpoint creator so that all created checkpoints are
+// also uploaded to the given object store and removed from it when deleted.
+func NewObjectStoreCreator(creator Creator, store ObjectStore) Creator {
+	return &storeCreator{
+		Creator: creator,
+		store:   store,
+	}
+}
+
+// GetChunkFromURL fetches a checkpoint chunk from an object store that is publicly available at
+// the given base URL.
+func GetChunkFromURL(ctx context.Context, client *http.Client, baseURL string, chunk *ChunkMetadata, w io.Writer) error {
+	err := httpGetObject(ctx, client, strings.TrimSuffix(baseURL, "/")+"/"+ChunkObjectKey(chunk), w)
+	if errors.Is(err, ErrObjectNotFound) {
+		return ErrChunkNotFound
+	}
+	return err
+}
+
+func httpGetObject(ctx context.Context, client *http.Client, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("checkpoint: failed to create request: %w", err)
+	}
+	return doObjectRequest(client, req, w)
+}
+
+func doObjectRequest(client *http.Client, req *http.Request, w io.Writer) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("checkpoint: object store request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrObjectNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("checkpoint: object store request failed with status %d", resp.StatusCode)
+	}
+
+	if w == nil {
+		w = ioutil.Discard
+	}
+	if _, err = io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("checkpoint: failed to read object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3ServiceName      = "s3"
+)
+
+// S3StoreConfig is the configuration of an S3-compatible object store.
+//
+// Any store implementing the S3 API with AWS Signature Version 4 authentication can be used
+// (e.g., Amazon S3, MinIO or Google Cloud Storage with HMAC keys).
+type S3StoreConfig struct {
+	// Endpoint is the URL of the object store API endpoint (e.g., https://s3.amazonaws.com).
+	Endpoint string
+	// Region is the region used for request signing.
+	Region string
+	// Bucket is the name of the bucket. Path-style addressing is used.
+	Bucket string
+	// Prefix is an optional key prefix for all stored objects.
+	Prefix string
+
+	// AccessKeyID is the access key identifier. If empty, requests are not signed.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+
+	// PublicURL is the base URL from which stored objects can be publicly retrieved (e.g., in case
+	// the bucket is served via a CDN). If empty, the bucket URL at the API endpoint is used.
+	PublicURL string
+
+	// Client is the HTTP client used to issue requests. If nil, the default client is used.
+	Client *http.Client
+}
+
+type s3Store struct {
+	cfg S3StoreConfig
+
+	baseURL   *url.URL
+	publicURL string
+}
+
+func (s *s3Store) objectURL(key string) *url.URL {
+	u := *s.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	return &u
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, data []byte, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("checkpoint: failed to create request: %w", err)
+	}
+	req.ContentLength = int64(len(data))
+	if s.cfg.AccessKeyID != "" {
+		s.sign(req, data, time.Now())
+	}
+	return doObjectRequest(s.cfg.Client, req, w)
+}
+
+// sign signs the request using AWS Signature Version 4.
+func (s *s3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.cfg.Region, s3ServiceName, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := s3SigningKey(s.cfg.SecretAccessKey, date, s.cfg.Region, s3ServiceName)
+	signature := hex.EncodeToString(hmacSHA256(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm,
+		s.cfg.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func (s *s3Store) PutObject(ctx context.Context, key string, data []byte) error {
+	return s.do(ctx, http.MethodPut, key, data, nil)
+}
+
+func (s *s3Store) GetObject(ctx context.Context, key string, w io.Writer) error {
+	return s.do(ctx, http.MethodGet, key, nil, w)
+}
+
+func (s *s3Store) DeleteObject(ctx context.Context, key string) error {
+	err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *s3Store) URL() string {
+	return s.publicURL
+}
+
+// NewS3Store creates a new S3-compatible object store.
+func NewS3Store(cfg *S3StoreConfig) (ObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("checkpoint: missing object store bucket")
+	}
+	if cfg.AccessKeyID != "" && cfg.Region == "" {
+		return nil, fmt.Errorf("checkpoint: missing object store region")
+	}
+	baseURL, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: malformed object store endpoint: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("checkpoint: unsupported object store endpoint scheme: '%s'", baseURL.Scheme)
+	}
+
+	pathParts := []string{strings.TrimSuffix(baseURL.Path, "/"), cfg.Bucket}
+	if prefix := strings.Trim(cfg.Prefix, "/"); prefix != "" {
+		pathParts = append(pathParts, prefix)
+	}
+	baseURL.Path = strings.Join(pathParts, "/")
+
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = baseURL.String()
+	}
+
+	return &s3Store{
+		cfg:       *cfg,
+		baseURL:   baseURL,
+		publicURL: publicURL,
+	}, nil
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func s3SigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(service))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
@@ -0,0 +1,201 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// testS3Server is a minimal in-memory S3-compatible object store.
+type testS3Server struct {
+	sync.Mutex
+
+	t           *testing.T
+	accessKeyID string
+	objects     map[string][]byte
+}
+
+func (s *testS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.accessKeyID != "" && r.Method != http.MethodGet {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+s.accessKeyID+"/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		require.NoError(s.t, err, "ReadAll")
+		if s.accessKeyID != "" {
+			h := sha256.Sum256(data)
+			require.Equal(s.t, hex.EncodeToString(h[:]), r.Header.Get("X-Amz-Content-Sha256"), "payload hash should be correct")
+		}
+		s.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3SigningKey(t *testing.T) {
+	require := require.New(t)
+
+	// Test vector from the AWS Signature Version 4 documentation.
+	key := s3SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20150830", "us-east-1", "iam")
+	require.Equal("c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9", hex.EncodeToString(key))
+}
+
+func TestS3Store(t *testing.T) {
+	require := require.New(t)
+
+	srv := &testS3Server{t: t, accessKeyID: "test-key", objects: make(map[string][]byte)}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	_, err := NewS3Store(&S3StoreConfig{Endpoint: server.URL})
+	require.Error(err, "NewS3Store should fail without a bucket")
+	_, err = NewS3Store(&S3StoreConfig{Endpoint: "ftp://example.com", Bucket: "bucket"})
+	require.Error(err, "NewS3Store should fail with an unsupported scheme")
+
+	store, err := NewS3Store(&S3StoreConfig{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		Prefix:          "/checkpoints/",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+	require.NoError(err, "NewS3Store")
+	require.Equal(server.URL+"/bucket/checkpoints", store.URL(), "public URL should default to the bucket URL")
+
+	ctx := context.Background()
+	err = store.PutObject(ctx, "foo/bar", []byte("hello"))
+	require.NoError(err, "PutObject")
+	require.Contains(srv.objects, "/bucket/checkpoints/foo/bar", "object should be stored under the prefix")
+
+	var buf bytes.Buffer
+	err = store.GetObject(ctx, "foo/bar", &buf)
+	require.NoError(err, "GetObject")
+	require.Equal([]byte("hello"), buf.Bytes(), "object should be correct")
+
+	err = store.DeleteObject(ctx, "foo/bar")
+	require.NoError(err, "DeleteObject")
+	err = store.GetObject(ctx, "foo/bar", &buf)
+	require.True(errors.Is(err, ErrObjectNotFound), "GetObject should fail for a removed object")
+
+	// Requests with invalid credentials should fail.
+	badStore, err := NewS3Store(&S3StoreConfig{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		AccessKeyID:     "bad-key",
+		SecretAccessKey: "test-secret",
+	})
+	require.NoError(err, "NewS3Store")
+	err = badStore.PutObject(ctx, "foo/bar", []byte("hello"))
+	require.Error(err, "PutObject should fail with invalid credentials")
+}
+
+func TestObjectStoreCreator(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "mkvs.checkpoint.store")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	ctx := context.Background()
+	tree := mkvs.New(nil, ndb)
+	for i := 0; i < 1000; i++ {
+		err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit")
+	root := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Hash:      rootHash,
+	}
+
+	srv := &testS3Server{t: t, objects: make(map[string][]byte)}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	store, err := NewS3Store(&S3StoreConfig{Endpoint: server.URL, Bucket: "bucket"})
+	require.NoError(err, "NewS3Store")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+	sc := NewObjectStoreCreator(fc, store)
+
+	// Created checkpoints should be uploaded.
+	cp, err := sc.CreateCheckpoint(ctx, root, 16*1024)
+	require.NoError(err, "CreateCheckpoint")
+	require.Len(srv.objects, len(cp.Chunks)+1, "all chunks and the metadata should be uploaded")
+	require.Contains(srv.objects, "/bucket/"+MetadataObjectKey(cp.Version, cp.Root), "metadata should be uploaded")
+
+	// Chunks should be retrievable from the public URL and match the local chunks.
+	for idx := range cp.Chunks {
+		chunk, cerr := cp.GetChunkMetadata(uint64(idx))
+		require.NoError(cerr, "GetChunkMetadata")
+
+		var local, remote bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, chunk, &local)
+		require.NoError(err, "GetCheckpointChunk")
+		err = GetChunkFromURL(ctx, nil, store.URL(), chunk, &remote)
+		require.NoError(err, "GetChunkFromURL")
+		require.Equal(local.Bytes(), remote.Bytes(), "uploaded chunk should match the local chunk")
+	}
+
+	// Deleted checkpoints should be removed from the store.
+	err = sc.DeleteCheckpoint(ctx, cp.Version, cp.Root)
+	require.NoError(err, "DeleteCheckpoint")
+	require.Len(srv.objects, 0, "all uploaded objects should be removed")
+	_, err = fc.GetCheckpoint(ctx, cp.Version, cp.Root)
+	require.True(errors.Is(err, ErrCheckpointNotFound), "local checkpoint should be removed")
+
+	chunk, err := cp.GetChunkMetadata(0)
+	require.NoError(err, "GetChunkMetadata")
+	err = GetChunkFromURL(ctx, nil, store.URL(), chunk, ioutil.Discard)
+	require.True(errors.Is(err, ErrChunkNotFound), "GetChunkFromURL should fail for a removed chunk")
+}
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -11,8 +12,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
-// ModuleName is the module name.
-const ModuleName = "storage/mkvs/db"
+const (
+	// ModuleName is the module name.
+	ModuleName = "storage/mkvs/db"
+
+	// MaxRootAnnotationSize is the maximum size of a root annotation in bytes.
+	MaxRootAnnotationSize = 1024
+)
 
 var (
 	// ErrNodeNotFound indicates that a node with the specified hash couldn't be found
@@ -51,6 +57,18 @@ var (
 	// ErrInvalidMultipartVersion indicates that a Finalize, NewBatch or Commit was called with a version
 	// that doesn't match the current multipart restore as set with StartMultipartRestore.
 	ErrInvalidMultipartVersion = errors.New(ModuleName, 14, "mkvs: operation called with different version than current multipart version")
+	// ErrVersionPruned indicates that the given version has been pruned, either before a version
+	// handle could be opened or while the handle was open.
+	ErrVersionPruned = errors.New(ModuleName, 15, "mkvs: version has been pruned")
+	// ErrVersionHandleClosed indicates that an operation was attempted on a closed version handle.
+	ErrVersionHandleClosed = errors.New(ModuleName, 16, "mkvs: version handle closed")
+	// ErrVersionPinned indicates that the given version cannot be pruned as it is pinned by
+	// an open snapshot.
+	ErrVersionPinned = errors.New(ModuleName, 17, "mkvs: version is pinned by a snapshot")
+	// ErrAnnotationTooLarge indicates that the given root annotation exceeds the maximum size.
+	ErrAnnotationTooLarge = errors.New(ModuleName, 18, "mkvs: root annotation too large")
+	// ErrAnnotationNotFound indicates that the given root has no annotation.
+	ErrAnnotationNotFound = errors.New(ModuleName, 19, "mkvs: root annotation not found")
 )
 
 // Config is the node database backend configuration.
@@ -61,6 +79,10 @@ type Config struct { // nolint: maligned
 	// NoFsync will disable fsync() where possible.
 	NoFsync bool
 
+	// FsyncBatch configures batching of fsync() calls (if the backend supports it). It is ignored
+	// when NoFsync is set.
+	FsyncBatch FsyncBatchPolicy
+
 	// MemoryOnly will make the storage memory-only (if the backend supports it).
 	MemoryOnly bool
 
@@ -75,14 +97,158 @@ type Config struct { // nolint: maligned
 
 	// DiscardWriteLogs will cause all write logs to be discarded.
 	DiscardWriteLogs bool
+
+	// KeyFilterCapacity is the expected number of keys in the key existence filter maintained
+	// for finalized versions (if the backend supports it). Zero disables the filter.
+	KeyFilterCapacity uint64
+
+	// KeyFilterFalsePositiveRate is the target false positive rate of the key existence filter
+	// when it holds KeyFilterCapacity keys.
+	KeyFilterFalsePositiveRate float64
+
+	// SharedNodeDB is the path to a node database shared by multiple namespaces (if the backend
+	// supports it). If set, nodes are stored in the shared database, where identical nodes of
+	// different namespaces are only stored once, while all per-namespace metadata remains in DB.
+	SharedNodeDB string
+
+	// WriteLogBufferSize is the maximum number of write log entries buffered while streaming
+	// write logs from the database. Zero selects the default buffer size.
+	WriteLogBufferSize int
+
+	// GC configures garbage collection of the underlying storage (if the backend supports it).
+	GC GCPolicy
+}
+
+// GCPolicy is the policy for garbage collecting the underlying storage of the node database
+// (e.g., the value log of a log-structured database).
+type GCPolicy struct {
+	// Interval is the interval between periodic garbage collection runs. Zero selects the backend
+	// default while a negative interval disables periodic garbage collection.
+	Interval time.Duration
+
+	// DiscardRatio is the fraction of a log file that must be discardable in order for the file
+	// to be rewritten. Values outside of (0, 1) select the backend default.
+	DiscardRatio float64
+
+	// MaxRewrites is the maximum number of log files rewritten in a single run, bounding the
+	// amount of IO a run can perform. Zero means that the number of rewrites is not limited.
+	MaxRewrites uint64
+}
+
+// FsyncBatchPolicy is the policy for batching fsync() calls.
+//
+// When batching is enabled, commits are no longer synced to disk individually. Instead, the
+// database is synced once the configured number of commits has accumulated or once the
+// configured interval has passed since the first unsynced commit, whichever comes first.
+//
+// A crash of the node process itself does not lose any commits. In case of an operating system
+// crash or power loss, the commits since the last sync may be lost. As the underlying log is
+// verified on recovery and truncated at the first incomplete entry, only the most recent commits
+// are lost and the database remains consistent, but it may go back to an earlier version. Any
+// consumers that track progress outside of the node database must therefore reconcile it with
+// the last finalized version on startup.
+type FsyncBatchPolicy struct {
+	// Commits is the number of commits after which the database is synced. Zero means that the
+	// number of commits is not limited.
+	Commits uint64
+
+	// Interval is the maximum amount of time that a commit can remain unsynced. Zero means that
+	// the amount of time is not limited.
+	Interval time.Duration
+}
+
+// IsEnabled returns true iff fsync batching is enabled.
+func (p *FsyncBatchPolicy) IsEnabled() bool {
+	return p.Commits > 0 || p.Interval > 0
+}
+
+// NodeGetter is an interface for looking up nodes.
+type NodeGetter interface {
+	// GetNode looks up a node in the database.
+	GetNode(root node.Root, ptr *node.Pointer) (node.Node, error)
+}
+
+// VersionHandle is a handle that pins a read-only view of a single version of the node database.
+//
+// A version handle remains valid until it is either closed or the version it refers to is pruned.
+// Pruning waits for any in-progress reads through the handle to complete before removing any
+// data, and all subsequent reads fail with ErrVersionPruned. This guarantees that a reader using
+// a handle either observes a consistent view of the version or gets an explicit error, instead of
+// racing with Prune and observing a partially removed version.
+type VersionHandle interface {
+	NodeGetter
+
+	// Version returns the version that this handle refers to.
+	Version() uint64
+
+	// Close releases the version handle.
+	//
+	// It is not an error to call this method more than once.
+	Close()
+}
+
+// Snapshot is a read-only view of a single version of the node database that is pinned against
+// pruning for as long as it remains open.
+//
+// Different from a version handle, which gets invalidated when its version is pruned, a snapshot
+// causes any attempt to prune its version to fail with ErrVersionPinned. This makes snapshots
+// suitable for long-running consumers (e.g., checkpoint creation) that need a stable view.
+type Snapshot interface {
+	VersionHandle
+}
+
+// KeyFilter is an optional interface implemented by node databases that maintain a probabilistic
+// key existence filter for the roots of finalized versions.
+type KeyFilter interface {
+	// MayContainKey returns false in case the given key is definitely not present in the given
+	// root. Otherwise the key may be present and a regular lookup is required.
+	MayContainKey(root node.Root, key []byte) bool
+
+	// ReportKeyFilterFalsePositive reports that a key for which MayContainKey returned true was
+	// not present in the given root.
+	ReportKeyFilterFalsePositive(root node.Root)
+}
+
+// GarbageCollector is an optional interface implemented by node databases that support
+// triggering garbage collection of their underlying storage on demand.
+type GarbageCollector interface {
+	// TriggerGC runs garbage collection immediately, waits for it to complete and returns the
+	// number of log files that have been rewritten.
+	TriggerGC(ctx context.Context) (uint64, error)
 }
 
 // NodeDB is the persistence layer used for persisting the in-memory tree.
 type NodeDB interface {
 	// GetNode looks up a node in the database.
+	//
+	// Lookups are not synchronized with Prune and Finalize. Readers that need a consistent view
+	// of a version should use a version handle obtained via OpenVersion instead.
 	GetNode(root node.Root, ptr *node.Pointer) (node.Node, error)
 
+	// GetNodes looks up multiple nodes in the database using a single batched lookup.
+	//
+	// The returned slice has the same length as ptrs and contains nil for any nodes that were not
+	// found. Lookups are not synchronized with Prune and Finalize, same as for GetNode.
+	GetNodes(root node.Root, ptrs []*node.Pointer) ([]node.Node, error)
+
+	// OpenVersion opens a handle for consistent reads of the given version. The caller must
+	// close the handle after use.
+	//
+	// In case the version has already been pruned (or is currently being pruned), this method
+	// returns ErrVersionPruned.
+	OpenVersion(version uint64) (VersionHandle, error)
+
+	// Snapshot opens a read-only snapshot of the given version which pins the version against
+	// pruning until the snapshot is closed. The caller must close the snapshot after use.
+	//
+	// In case the version has already been pruned (or is currently being pruned), this method
+	// returns ErrVersionPruned.
+	Snapshot(version uint64) (Snapshot, error)
+
 	// GetWriteLog retrieves a write log between two storage instances from the database.
+	//
+	// The write log is streamed from the database, entries are only decoded as they are
+	// consumed from the returned iterator.
 	GetWriteLog(ctx context.Context, startRoot, endRoot node.Root) (writelog.Iterator, error)
 
 	// GetLatestVersion returns the most recent version in the node database.
@@ -94,6 +260,25 @@ type NodeDB interface {
 	// GetRootsForVersion returns a list of roots stored under the given version.
 	GetRootsForVersion(ctx context.Context, version uint64) ([]hash.Hash, error)
 
+	// SetRootAnnotation attaches an opaque annotation (e.g., the hash of the block that produced
+	// the root) to the given root, replacing any existing annotation. Passing an empty annotation
+	// removes the existing annotation.
+	//
+	// Only roots in finalized versions can be annotated and annotations are removed together with
+	// the rest of the version when it is pruned. The annotation must not be larger than
+	// MaxRootAnnotationSize bytes.
+	SetRootAnnotation(ctx context.Context, root node.Root, annotation []byte) error
+
+	// GetRootAnnotation returns the annotation attached to the given root.
+	//
+	// In case the root exists but has not been annotated, this method returns
+	// ErrAnnotationNotFound.
+	GetRootAnnotation(ctx context.Context, root node.Root) ([]byte, error)
+
+	// GetRootAnnotationsForVersion returns the annotations of all annotated roots stored under
+	// the given version.
+	GetRootAnnotationsForVersion(ctx context.Context, version uint64) (map[hash.Hash][]byte, error)
+
 	// StartMultipartInsert prepares the database for a batch insert job from multiple chunks.
 	// Batches from this call onwards will keep track of inserted nodes so that they can be
 	// deleted if the job fails for any reason.
@@ -122,9 +307,17 @@ type NodeDB interface {
 	// can be discarded.
 	Finalize(ctx context.Context, version uint64, roots []hash.Hash) error
 
+	// DiscardUnfinalized removes all roots in versions after the last finalized version together
+	// with any nodes and write logs added for them, returning the discarded versions.
+	//
+	// This makes it possible to recover from partially written or corrupted versions by
+	// applying them again.
+	DiscardUnfinalized(ctx context.Context) ([]uint64, error)
+
 	// Prune removes all roots recorded under the given version.
 	//
 	// Only the earliest version can be pruned, passing any other version will result in an error.
+	// In case the version is pinned by an open snapshot, this method returns ErrVersionPinned.
 	Prune(ctx context.Context, version uint64) error
 
 	// Size returns the size of the database in bytes.
@@ -211,6 +404,18 @@ func (d *nopNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error
 	return nil, ErrNodeNotFound
 }
 
+func (d *nopNodeDB) GetNodes(root node.Root, ptrs []*node.Pointer) ([]node.Node, error) {
+	return make([]node.Node, len(ptrs)), nil
+}
+
+func (d *nopNodeDB) OpenVersion(version uint64) (VersionHandle, error) {
+	return &nopVersionHandle{version: version}, nil
+}
+
+func (d *nopNodeDB) Snapshot(version uint64) (Snapshot, error) {
+	return &nopVersionHandle{version: version}, nil
+}
+
 func (d *nopNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.Root) (writelog.Iterator, error) {
 	return nil, ErrWriteLogNotFound
 }
@@ -227,6 +432,18 @@ func (d *nopNodeDB) GetRootsForVersion(ctx context.Context, version uint64) ([]h
 	return nil, nil
 }
 
+func (d *nopNodeDB) SetRootAnnotation(ctx context.Context, root node.Root, annotation []byte) error {
+	return nil
+}
+
+func (d *nopNodeDB) GetRootAnnotation(ctx context.Context, root node.Root) ([]byte, error) {
+	return nil, ErrRootNotFound
+}
+
+func (d *nopNodeDB) GetRootAnnotationsForVersion(ctx context.Context, version uint64) (map[hash.Hash][]byte, error) {
+	return nil, nil
+}
+
 func (d *nopNodeDB) HasRoot(root node.Root) bool {
 	return false
 }
@@ -243,6 +460,10 @@ func (d *nopNodeDB) Finalize(ctx context.Context, version uint64, roots []hash.H
 	return nil
 }
 
+func (d *nopNodeDB) DiscardUnfinalized(ctx context.Context) ([]uint64, error) {
+	return nil, nil
+}
+
 func (d *nopNodeDB) Prune(ctx context.Context, version uint64) error {
 	return nil
 }
@@ -258,6 +479,22 @@ func (d *nopNodeDB) Sync() error {
 func (d *nopNodeDB) Close() {
 }
 
+// nopVersionHandle is a no-op version handle.
+type nopVersionHandle struct {
+	version uint64
+}
+
+func (h *nopVersionHandle) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	return nil, ErrNodeNotFound
+}
+
+func (h *nopVersionHandle) Version() uint64 {
+	return h.version
+}
+
+func (h *nopVersionHandle) Close() {
+}
+
 // nopBatch is a no-op batch.
 type nopBatch struct {
 	BaseBatch
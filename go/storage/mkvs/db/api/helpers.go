@@ -1,13 +1,22 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
+const (
+	cborMajorTypeArray = 4
+	cborNull           = 0xf6
+)
+
 // HashedDBWriteLog is a write log helper for database backends that can reference nodes by hash.
 type HashedDBWriteLog []HashedDBLogEntry
 
@@ -33,23 +42,105 @@ func MakeHashedDBWriteLog(writeLog writelog.WriteLog, annotations writelog.Annot
 	return log
 }
 
-// ReviveHashedDBWriteLogs is a helper for hashed database backends that converts
-// a HashedDBWriteLog into a WriteLog.
+// HashedDBWriteLogDecoder lazily decodes the entries of a serialized HashedDBWriteLog so that
+// the whole write log never needs to be decoded into memory at once.
+type HashedDBWriteLogDecoder struct {
+	remaining uint64
+	dec       interface {
+		Decode(v interface{}) error
+	}
+
+	// static is set when the decoder wraps an already decoded write log.
+	static HashedDBWriteLog
+}
+
+// NewHashedDBWriteLogDecoder creates a new decoder for the given CBOR-serialized HashedDBWriteLog.
+func NewHashedDBWriteLogDecoder(data []byte) (*HashedDBWriteLogDecoder, error) {
+	n, rest, err := decodeArrayHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	return &HashedDBWriteLogDecoder{
+		remaining: n,
+		dec:       cbor.NewDecoder(bytes.NewReader(rest)),
+	}, nil
+}
+
+// Next decodes the next write log entry. It returns io.EOF after all entries have been decoded.
+func (d *HashedDBWriteLogDecoder) Next() (*HashedDBLogEntry, error) {
+	if d.static != nil {
+		if len(d.static) == 0 {
+			return nil, io.EOF
+		}
+		entry := &d.static[0]
+		d.static = d.static[1:]
+		return entry, nil
+	}
+
+	if d.remaining == 0 {
+		return nil, io.EOF
+	}
+	var entry HashedDBLogEntry
+	if err := d.dec.Decode(&entry); err != nil {
+		return nil, fmt.Errorf("mkvs: failed to decode write log entry: %w", err)
+	}
+	d.remaining--
+	return &entry, nil
+}
+
+// decodeArrayHeader decodes the header of a definite-length CBOR array and returns the number of
+// elements and the encoded elements that follow the header.
+func decodeArrayHeader(data []byte) (uint64, []byte, error) {
+	if len(data) == 0 {
+		return 0, nil, fmt.Errorf("mkvs: malformed write log: empty")
+	}
+	if data[0] == cborNull {
+		return 0, nil, nil
+	}
+	if data[0]>>5 != cborMajorTypeArray {
+		return 0, nil, fmt.Errorf("mkvs: malformed write log: not an array")
+	}
+
+	info := data[0] & 0x1f
+	data = data[1:]
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < size {
+			return 0, nil, fmt.Errorf("mkvs: malformed write log: truncated array header")
+		}
+		var n uint64
+		for _, b := range data[:size] {
+			n = n<<8 | uint64(b)
+		}
+		return n, data[size:], nil
+	default:
+		return 0, nil, fmt.Errorf("mkvs: malformed write log: unsupported array length")
+	}
+}
+
+// StreamHashedDBWriteLogs is a helper for hashed database backends that lazily converts
+// serialized HashedDBWriteLogs into a write log iterator.
 //
-// The provided logGetter will be called first to fetch the next write log to
-// convert. If it returns a nil write log, iteration terminates.
+// The provided logGetter will be called first to fetch a decoder for the next write log to
+// convert. If it returns a nil decoder, iteration terminates.
 //
-// Then the provided valueGetter will be called for each log entry to fetch each
-// of the values in the write log.
+// Then the provided valueGetter will be called for each log entry to fetch each of the values in
+// the write log. Entries are only decoded and their values fetched as buffer space becomes
+// available, so at most bufferSize revived entries are held in memory at any time. A bufferSize
+// of zero selects the default buffer size.
 //
 // After iteration has finished, closer will be called.
-func ReviveHashedDBWriteLogs(
+func StreamHashedDBWriteLogs(
 	ctx context.Context,
-	logGetter func() (node.Root, HashedDBWriteLog, error),
+	logGetter func() (node.Root, *HashedDBWriteLogDecoder, error),
 	valueGetter func(node.Root, hash.Hash) (*node.LeafNode, error),
 	closer func(),
+	bufferSize int,
 ) (writelog.Iterator, error) {
-	pipe := writelog.NewPipeIterator(ctx)
+	pipe := writelog.NewPipeIteratorWithBufferSize(ctx, bufferSize)
 	go func() {
 		defer pipe.Close()
 		defer closer()
@@ -70,7 +161,16 @@ func ReviveHashedDBWriteLogs(
 				return
 			}
 
-			for _, entry := range log {
+			for {
+				entry, err := log.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					_ = pipe.PutError(err)
+					return
+				}
+
 				var newEntry *writelog.LogEntry
 				if entry.InsertedHash == nil {
 					newEntry = &writelog.LogEntry{
@@ -98,6 +198,36 @@ func ReviveHashedDBWriteLogs(
 	return &pipe, nil
 }
 
+// ReviveHashedDBWriteLogs is a helper for hashed database backends that converts
+// a HashedDBWriteLog into a WriteLog.
+//
+// The provided logGetter will be called first to fetch the next write log to
+// convert. If it returns a nil write log, iteration terminates.
+//
+// Then the provided valueGetter will be called for each log entry to fetch each
+// of the values in the write log.
+//
+// After iteration has finished, closer will be called.
+func ReviveHashedDBWriteLogs(
+	ctx context.Context,
+	logGetter func() (node.Root, HashedDBWriteLog, error),
+	valueGetter func(node.Root, hash.Hash) (*node.LeafNode, error),
+	closer func(),
+) (writelog.Iterator, error) {
+	return StreamHashedDBWriteLogs(ctx,
+		func() (node.Root, *HashedDBWriteLogDecoder, error) {
+			root, log, err := logGetter()
+			if err != nil || log == nil {
+				return root, nil, err
+			}
+			return root, &HashedDBWriteLogDecoder{static: log}, nil
+		},
+		valueGetter,
+		closer,
+		0,
+	)
+}
+
 // NodeVisitor is a function that visits a given node and returns true to continue
 // traversal of child nodes or false to stop.
 type NodeVisitor func(context.Context, node.Node) bool
@@ -106,9 +236,10 @@ type NodeVisitor func(context.Context, node.Node) bool
 // a pre-order DFS where the node is visited first, then its leaf (if any) and then
 // its children (first left then right).
 //
-// Different to the Visit method in the MKVS tree, this uses the NodeDB API directly
-// to traverse the tree to avoid the overhead of keeping the cache.
-func Visit(ctx context.Context, ndb NodeDB, root node.Root, visitor NodeVisitor) error {
+// Different to the Visit method in the MKVS tree, this uses the NodeDB API (or a
+// version handle) directly to traverse the tree to avoid the overhead of keeping
+// the cache.
+func Visit(ctx context.Context, ndb NodeGetter, root node.Root, visitor NodeVisitor) error {
 	ptr := &node.Pointer{
 		Clean: true,
 		Hash:  root.Hash,
@@ -116,7 +247,7 @@ func Visit(ctx context.Context, ndb NodeDB, root node.Root, visitor NodeVisitor)
 	return doVisit(ctx, ndb, root, visitor, ptr)
 }
 
-func doVisit(ctx context.Context, ndb NodeDB, root node.Root, visitor NodeVisitor, ptr *node.Pointer) error {
+func doVisit(ctx context.Context, ndb NodeGetter, root node.Root, visitor NodeVisitor, ptr *node.Pointer) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -155,3 +286,38 @@ func doVisit(ctx context.Context, ndb NodeDB, root node.Root, visitor NodeVisito
 
 	return nil
 }
+
+type versionPinnedNodeDB struct {
+	NodeDB
+
+	vh VersionHandle
+}
+
+func (d *versionPinnedNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	if root.Version == d.vh.Version() {
+		return d.vh.GetNode(root, ptr)
+	}
+	return d.NodeDB.GetNode(root, ptr)
+}
+
+func (d *versionPinnedNodeDB) MayContainKey(root node.Root, key []byte) bool {
+	if kf, ok := d.NodeDB.(KeyFilter); ok {
+		return kf.MayContainKey(root, key)
+	}
+	return true
+}
+
+func (d *versionPinnedNodeDB) ReportKeyFilterFalsePositive(root node.Root) {
+	if kf, ok := d.NodeDB.(KeyFilter); ok {
+		kf.ReportKeyFilterFalsePositive(root)
+	}
+}
+
+// NewVersionPinnedNodeDB wraps the given node database so that all node lookups for roots at the
+// version of the given handle go through the handle.
+//
+// This makes it possible to use a version handle with consumers that require a NodeDB (e.g., the
+// MKVS tree). The caller remains responsible for closing the handle.
+func NewVersionPinnedNodeDB(ndb NodeDB, vh VersionHandle) NodeDB {
+	return &versionPinnedNodeDB{NodeDB: ndb, vh: vh}
+}
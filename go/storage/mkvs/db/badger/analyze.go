@@ -0,0 +1,316 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// AnalyzeConfig is the node database space usage analysis configuration.
+type AnalyzeConfig struct {
+	// DB is the path to the node database.
+	DB string
+
+	// KeyPrefixLen is the length (in bytes) of the leaf key prefixes used to aggregate space
+	// usage by key prefix.
+	KeyPrefixLen int
+
+	// NumTopPrefixes is the number of largest key prefixes to include in the report.
+	NumTopPrefixes int
+}
+
+// KeyTypeStats is the space usage of a given type of database entries.
+type KeyTypeStats struct {
+	// Name is the name of the entry type.
+	Name string `json:"name"`
+	// Entries is the number of live entries.
+	Entries uint64 `json:"entries"`
+	// Bytes is the estimated size of live entries.
+	Bytes uint64 `json:"bytes"`
+}
+
+// VersionStats is the space usage of a given version.
+type VersionStats struct {
+	// Version is the version.
+	Version uint64 `json:"version"`
+
+	// Nodes is the number of live nodes written in this version.
+	Nodes uint64 `json:"nodes"`
+	// NodeBytes is the estimated size of live nodes written in this version.
+	NodeBytes uint64 `json:"node_bytes"`
+
+	// WriteLogs is the number of write logs stored for this version.
+	WriteLogs uint64 `json:"write_logs"`
+	// WriteLogBytes is the estimated size of write logs stored for this version.
+	WriteLogBytes uint64 `json:"write_log_bytes"`
+
+	// GarbageBytes is the estimated size of deleted or superseded entries written in this
+	// version that have not yet been removed by compaction.
+	GarbageBytes uint64 `json:"garbage_bytes"`
+}
+
+// PrefixStats is the space usage of leaf nodes with a given key prefix.
+type PrefixStats struct {
+	// Prefix is the key prefix.
+	Prefix []byte `json:"prefix"`
+	// Leaves is the number of live leaf nodes.
+	Leaves uint64 `json:"leaves"`
+	// Bytes is the estimated size of live leaf nodes.
+	Bytes uint64 `json:"bytes"`
+}
+
+// AnalyzeReport is the node database space usage report.
+type AnalyzeReport struct {
+	// Namespace is the namespace the database is for.
+	Namespace common.Namespace `json:"namespace"`
+	// EarliestVersion is the earliest version.
+	EarliestVersion uint64 `json:"earliest_version"`
+	// LastFinalizedVersion is the last finalized version.
+	LastFinalizedVersion *uint64 `json:"last_finalized_version,omitempty"`
+
+	// LSMSize is the on-disk size of the LSM tree.
+	LSMSize int64 `json:"lsm_size"`
+	// ValueLogSize is the on-disk size of the value log.
+	ValueLogSize int64 `json:"value_log_size"`
+
+	// LiveBytes is the estimated size of all live entries.
+	LiveBytes uint64 `json:"live_bytes"`
+	// GarbageEntries is the number of deleted or superseded entries that have not yet been
+	// removed by compaction.
+	GarbageEntries uint64 `json:"garbage_entries"`
+	// GarbageBytes is the estimated size of deleted or superseded entries that have not yet been
+	// removed by compaction.
+	GarbageBytes uint64 `json:"garbage_bytes"`
+
+	// KeyTypes is the space usage by database entry type.
+	KeyTypes []*KeyTypeStats `json:"key_types"`
+	// Versions is the space usage by version.
+	Versions []*VersionStats `json:"versions"`
+	// TopPrefixes are the leaf key prefixes using the most space, in descending order.
+	TopPrefixes []*PrefixStats `json:"top_prefixes"`
+}
+
+// SpaceAmplification returns the ratio between the on-disk size of the database and the
+// estimated size of all live entries.
+func (r *AnalyzeReport) SpaceAmplification() float64 {
+	if r.LiveBytes == 0 {
+		return 0
+	}
+	return float64(r.LSMSize+r.ValueLogSize) / float64(r.LiveBytes)
+}
+
+var (
+	nodeKeyPrefix     = nodeKeyFmt.Encode()[0]
+	writeLogKeyPrefix = writeLogKeyFmt.Encode()[0]
+
+	// keyTypeNames are the names of the database entry types, indexed by key format prefix.
+	keyTypeNames = map[byte]string{
+		nodeKeyPrefix:                             "nodes",
+		writeLogKeyPrefix:                         "write_logs",
+		rootsMetadataKeyFmt.Encode()[0]:           "roots_metadata",
+		rootUpdatedNodesKeyFmt.Encode()[0]:        "root_updated_nodes",
+		metadataKeyFmt.Encode()[0]:                "metadata",
+		multipartRestoreNodeLogKeyFmt.Encode()[0]: "multipart_restore_log",
+		obsoleteNodeKeyFmt.Encode()[0]:            "obsolete_nodes",
+	}
+)
+
+// tsToVersion converts a badger timestamp to a MKVS version.
+func tsToVersion(ts uint64) (uint64, bool) {
+	if ts <= tsMetadata {
+		return 0, false
+	}
+	return ts - tsMetadata - 1, true
+}
+
+// Analyze opens the Badger node database at the configured path in read-only mode and reports
+// its space usage.
+//
+// The database must not be in use by any other process.
+func Analyze(ctx context.Context, cfg *AnalyzeConfig) (*AnalyzeReport, error) {
+	logger := logging.GetLogger("mkvs/db/badger/analyze")
+
+	opts := badger.DefaultOptions(cfg.DB)
+	opts = opts.WithLogger(cmnBadger.NewLogAdapter(logger))
+	opts = opts.WithCompression(options.Snappy)
+	opts = opts.WithReadOnly(true)
+
+	db, err := badger.OpenManaged(opts)
+	if err != nil {
+		return nil, fmt.Errorf("mkvs/badger: failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	var report AnalyzeReport
+	report.LSMSize, report.ValueLogSize = db.Size()
+
+	tx := db.NewTransactionAt(math.MaxUint64, false)
+	defer tx.Discard()
+
+	// Load metadata.
+	var meta serializedMetadata
+	item, err := tx.Get(metadataKeyFmt.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("mkvs/badger: failed to load metadata: %w", err)
+	}
+	if err = item.Value(func(data []byte) error {
+		return cbor.UnmarshalTrusted(data, &meta)
+	}); err != nil {
+		return nil, fmt.Errorf("mkvs/badger: failed to load metadata: %w", err)
+	}
+	if meta.Version != dbVersion {
+		return nil, fmt.Errorf("mkvs/badger: incompatible database version (expected: %d got: %d)",
+			dbVersion,
+			meta.Version,
+		)
+	}
+	report.Namespace = meta.Namespace
+	report.EarliestVersion = meta.EarliestVersion
+	report.LastFinalizedVersion = meta.LastFinalizedVersion
+
+	keyTypes := make(map[byte]*KeyTypeStats)
+	versions := make(map[uint64]*VersionStats)
+	prefixes := make(map[string]*PrefixStats)
+	getVersion := func(version uint64) *VersionStats {
+		vs := versions[version]
+		if vs == nil {
+			vs = &VersionStats{Version: version}
+			versions[version] = vs
+		}
+		return vs
+	}
+
+	itOpts := badger.DefaultIteratorOptions
+	itOpts.AllVersions = true
+	itOpts.PrefetchValues = false
+	it := tx.NewIterator(itOpts)
+	defer it.Close()
+
+	// Entries are iterated in key order with all versions of a key ordered from newest to oldest.
+	// Only the newest version of each key is live (unless it is a deletion marker), all the rest
+	// is garbage waiting to be removed by compaction.
+	var lastKey []byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		item = it.Item()
+		key := item.Key()
+		size := uint64(item.EstimatedSize())
+		version, hasVersion := tsToVersion(item.Version())
+
+		isNewest := lastKey == nil || string(key) != string(lastKey)
+		if isNewest {
+			lastKey = item.KeyCopy(lastKey)
+		}
+
+		if !isNewest || item.IsDeletedOrExpired() {
+			report.GarbageEntries++
+			report.GarbageBytes += size
+			if hasVersion {
+				getVersion(version).GarbageBytes += size
+			}
+			continue
+		}
+		report.LiveBytes += size
+
+		kt := keyTypes[key[0]]
+		if kt == nil {
+			name, ok := keyTypeNames[key[0]]
+			if !ok {
+				name = fmt.Sprintf("unknown_%02x", key[0])
+			}
+			kt = &KeyTypeStats{Name: name}
+			keyTypes[key[0]] = kt
+		}
+		kt.Entries++
+		kt.Bytes += size
+
+		switch key[0] {
+		case nodeKeyPrefix:
+			if hasVersion {
+				vs := getVersion(version)
+				vs.Nodes++
+				vs.NodeBytes += size
+			}
+
+			if cfg.KeyPrefixLen <= 0 || cfg.NumTopPrefixes <= 0 {
+				continue
+			}
+
+			var n node.Node
+			if err = item.Value(func(data []byte) error {
+				var vErr error
+				n, vErr = node.UnmarshalBinary(data)
+				return vErr
+			}); err != nil {
+				return nil, fmt.Errorf("mkvs/badger: failed to unmarshal node: %w", err)
+			}
+			leaf, ok := n.(*node.LeafNode)
+			if !ok {
+				continue
+			}
+
+			prefix := leaf.Key
+			if len(prefix) > cfg.KeyPrefixLen {
+				prefix = prefix[:cfg.KeyPrefixLen]
+			}
+			ps := prefixes[string(prefix)]
+			if ps == nil {
+				ps = &PrefixStats{Prefix: append([]byte{}, prefix...)}
+				prefixes[string(prefix)] = ps
+			}
+			ps.Leaves++
+			ps.Bytes += size
+		case writeLogKeyPrefix:
+			var wlVersion uint64
+			if !writeLogKeyFmt.Decode(key, &wlVersion) {
+				continue
+			}
+			vs := getVersion(wlVersion)
+			vs.WriteLogs++
+			vs.WriteLogBytes += size
+		}
+	}
+
+	for _, kt := range keyTypes {
+		report.KeyTypes = append(report.KeyTypes, kt)
+	}
+	sort.Slice(report.KeyTypes, func(i, j int) bool {
+		return report.KeyTypes[i].Name < report.KeyTypes[j].Name
+	})
+
+	for _, vs := range versions {
+		report.Versions = append(report.Versions, vs)
+	}
+	sort.Slice(report.Versions, func(i, j int) bool {
+		return report.Versions[i].Version < report.Versions[j].Version
+	})
+
+	for _, ps := range prefixes {
+		report.TopPrefixes = append(report.TopPrefixes, ps)
+	}
+	sort.Slice(report.TopPrefixes, func(i, j int) bool {
+		a, b := report.TopPrefixes[i], report.TopPrefixes[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return string(a.Prefix) < string(b.Prefix)
+	})
+	if len(report.TopPrefixes) > cfg.NumTopPrefixes {
+		report.TopPrefixes = report.TopPrefixes[:cfg.NumTopPrefixes]
+	}
+
+	return &report, nil
+}
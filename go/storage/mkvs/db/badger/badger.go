@@ -2,13 +2,15 @@
 package badger
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/dgraph-io/badger/v2/options"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
@@ -60,6 +62,17 @@ var (
 	//
 	// Value is empty.
 	multipartRestoreNodeLogKeyFmt = keyformat.New(0x05, &hash.Hash{})
+	// keyFilterKeyFmt is the key format for key existence filters. The key format is (version).
+	//
+	// Value is CBOR-serialized keyFilter.
+	keyFilterKeyFmt = keyformat.New(0x06, uint64(0))
+	// obsoleteNodeKeyFmt is the key format for nodes removed by an update when using a shared
+	// node database. Such nodes are only removed from the shared node database once all versions
+	// before the version in which they were removed are pruned. The key format is (version, node
+	// hash).
+	//
+	// Value is empty.
+	obsoleteNodeKeyFmt = keyformat.New(0x07, uint64(0), &hash.Hash{})
 )
 
 // New creates a new BadgerDB-backed node database.
@@ -69,11 +82,27 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		namespace:        cfg.Namespace,
 		readOnly:         cfg.ReadOnly,
 		discardWriteLogs: cfg.DiscardWriteLogs,
+		writeLogBufSize:  cfg.WriteLogBufferSize,
+		versionHandles:   make(map[uint64]map[*versionHandle]struct{}),
+	}
+	if cfg.KeyFilterCapacity > 0 {
+		db.keyFilterCapacity = cfg.KeyFilterCapacity
+		db.keyFilterFalsePositiveRate = cfg.KeyFilterFalsePositiveRate
+		if db.keyFilterFalsePositiveRate <= 0 || db.keyFilterFalsePositiveRate >= 1 {
+			db.keyFilterFalsePositiveRate = defaultKeyFilterFalsePositiveRate
+		}
+		db.keyFilters = make(map[uint64]*keyFilter)
+
+		keyFilterMetricsOnce.Do(func() {
+			prometheus.MustRegister(keyFilterCollectors...)
+		})
 	}
 
 	opts := badger.DefaultOptions(cfg.DB)
 	opts = opts.WithLogger(cmnBadger.NewLogAdapter(db.logger))
-	opts = opts.WithSyncWrites(!cfg.NoFsync)
+	// With fsync batching, writes are synced explicitly according to the policy.
+	fsyncBatch := !cfg.NoFsync && cfg.FsyncBatch.IsEnabled()
+	opts = opts.WithSyncWrites(!cfg.NoFsync && !fsyncBatch)
 	// Allow value log truncation if required (this is needed to recover the
 	// value log file which can get corrupted in crashes).
 	opts = opts.WithTruncate(true)
@@ -95,19 +124,44 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 	// Make sure that we can discard any deleted/invalid metadata.
 	db.db.SetDiscardTs(tsMetadata)
 
+	// Open the node store.
+	switch cfg.SharedNodeDB {
+	case "":
+		db.nodes = &localNodeStore{db: db.db}
+	default:
+		var shared *sharedNodeDB
+		if shared, err = openSharedNodeDB(cfg); err != nil {
+			_ = db.db.Close()
+			return nil, err
+		}
+		db.nodes = shared.newStore(db.namespace, db.db)
+	}
+
 	// Load database metadata.
 	if err = db.load(); err != nil {
-		_ = db.db.Close()
+		db.closeOnError()
 		return nil, fmt.Errorf("mkvs/badger: failed to load metadata: %w", err)
 	}
 
+	// Load key filters.
+	if db.keyFilterCapacity > 0 {
+		if err = db.loadKeyFilters(); err != nil {
+			db.closeOnError()
+			return nil, fmt.Errorf("mkvs/badger: failed to load key filters: %w", err)
+		}
+	}
+
 	// Cleanup any multipart restore remnants, since they can't be used anymore.
 	if err = db.cleanMultipartLocked(true); err != nil {
-		_ = db.db.Close()
+		db.closeOnError()
 		return nil, fmt.Errorf("mkvs/badger: failed to clean leftovers from multipart restore: %w", err)
 	}
 
-	db.gc = cmnBadger.NewGCWorker(db.logger, db.db)
+	if fsyncBatch {
+		db.fsync = newFsyncBatcher(db.logger, cfg.FsyncBatch, db.syncAll)
+	}
+
+	db.gc = cmnBadger.NewGCWorkerWithConfig(db.logger, db.db, gcConfig(&cfg.GC))
 
 	return db, nil
 }
@@ -119,18 +173,41 @@ type badgerNodeDB struct { // nolint: maligned
 
 	readOnly         bool
 	discardWriteLogs bool
+	writeLogBufSize  int
 
 	multipartVersion uint64
 
 	db *badger.DB
 	gc *cmnBadger.GCWorker
 
+	// nodes is the store holding the nodes, either the database itself or a node database
+	// shared with other namespaces.
+	nodes nodeStore
+
 	// metaUpdateLock must be held at any point where data at tsMetadata is read and updated. This
 	// is required because all metadata updates happen at the same timestamp and as such conflicts
 	// cannot be detected.
 	metaUpdateLock sync.Mutex
 	meta           metadata
 
+	// versionHandlesLock protects the set of open version handles and the version that is
+	// currently being pruned.
+	versionHandlesLock sync.Mutex
+	versionHandles     map[uint64]map[*versionHandle]struct{}
+	pruningVersion     *uint64
+
+	// keyFilterCapacity is the expected number of keys in a key filter or zero if key filters
+	// are disabled.
+	keyFilterCapacity          uint64
+	keyFilterFalsePositiveRate float64
+
+	// keyFiltersLock protects the key filters of the most recent finalized versions.
+	keyFiltersLock sync.RWMutex
+	keyFilters     map[uint64]*keyFilter
+
+	// fsync is the fsync batcher in case fsync batching is enabled.
+	fsync *fsyncBatcher
+
 	closeOnce sync.Once
 }
 
@@ -210,8 +287,8 @@ func (d *badgerNodeDB) cleanMultipartLocked(removeNodes bool) error {
 
 	metaBatch := d.db.NewWriteBatchAt(tsMetadata)
 	defer metaBatch.Cancel()
-	nodeBatch := d.db.NewWriteBatchAt(versionToTs(version))
-	defer nodeBatch.Cancel()
+	nodeBatch := d.nodes.newWriter(version)
+	defer nodeBatch.cancel()
 
 	var logged bool
 	for it.Rewind(); it.Valid(); it.Next() {
@@ -225,7 +302,7 @@ func (d *badgerNodeDB) cleanMultipartLocked(removeNodes bool) error {
 			if !multipartRestoreNodeLogKeyFmt.Decode(key, &hash) {
 				panic("mkvs/badger: bad iterator")
 			}
-			if err := nodeBatch.Delete(nodeKeyFmt.Encode(&hash)); err != nil {
+			if err := nodeBatch.remove(&hash); err != nil {
 				return err
 			}
 		}
@@ -236,7 +313,7 @@ func (d *badgerNodeDB) cleanMultipartLocked(removeNodes bool) error {
 
 	// Flush both batches first. If anything fails, having corrupt
 	// multipart info in d.meta shouldn't hurt us next run.
-	if err := nodeBatch.Flush(); err != nil {
+	if err := nodeBatch.flush(); err != nil {
 		return err
 	}
 	if err := metaBatch.Flush(); err != nil {
@@ -269,9 +346,64 @@ func (d *badgerNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, er
 		return nil, api.ErrNodeNotFound
 	}
 
-	tx := d.db.NewTransactionAt(versionToTs(root.Version), false)
-	defer tx.Discard()
-	item, err := tx.Get(nodeKeyFmt.Encode(&ptr.Hash))
+	return d.getNode(root.Version, ptr)
+}
+
+func (d *badgerNodeDB) GetNodes(root node.Root, ptrs []*node.Pointer) ([]node.Node, error) {
+	for _, ptr := range ptrs {
+		if ptr == nil || !ptr.IsClean() {
+			panic("mkvs/badger: attempted to get invalid pointer from node database")
+		}
+	}
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]node.Node, len(ptrs))
+	// If the version is earlier than the earliest version, we don't have the nodes (they were
+	// pruned). Note that the keys can still be present in the database until they get compacted.
+	if root.Version < d.meta.getEarliestVersion() {
+		return nodes, nil
+	}
+
+	// Perform lookups in key order as that results in better locality in the underlying store.
+	order := make([]int, len(ptrs))
+	for i := range ptrs {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(ptrs[order[i]].Hash[:], ptrs[order[j]].Hash[:]) < 0
+	})
+
+	rd := d.nodes.newReader(root.Version)
+	defer rd.discard()
+
+	for _, i := range order {
+		item, err := rd.get(&ptrs[i].Hash)
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			continue
+		default:
+			d.logger.Error("failed to Get node from backing store",
+				"err", err,
+			)
+			return nil, fmt.Errorf("mkvs/badger: failed to Get node from backing store: %w", err)
+		}
+
+		if nodes[i], err = d.decodeNode(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
+
+// getNode looks up a node at the given version without performing any checks.
+func (d *badgerNodeDB) getNode(version uint64, ptr *node.Pointer) (node.Node, error) {
+	rd := d.nodes.newReader(version)
+	defer rd.discard()
+	item, err := rd.get(&ptr.Hash)
 	switch err {
 	case nil:
 	case badger.ErrKeyNotFound:
@@ -283,8 +415,13 @@ func (d *badgerNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, er
 		return nil, fmt.Errorf("mkvs/badger: failed to Get node from backing store: %w", err)
 	}
 
+	return d.decodeNode(item)
+}
+
+// decodeNode decodes the node stored in the given item.
+func (d *badgerNodeDB) decodeNode(item *badger.Item) (node.Node, error) {
 	var n node.Node
-	if err = item.Value(func(val []byte) error {
+	if err := item.Value(func(val []byte) error {
 		var vErr error
 		n, vErr = node.UnmarshalBinary(val)
 		return vErr
@@ -308,9 +445,16 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 	if err := d.sanityCheckNamespace(startRoot.Namespace); err != nil {
 		return nil, err
 	}
-	// If the version is earlier than the earliest version, we don't have the roots.
-	if endRoot.Version < d.meta.getEarliestVersion() {
+	// Open a handle for the end version so that the write log and the referenced leaf nodes
+	// are not pruned while we are reading them. If the version is earlier than the earliest
+	// version, we don't have the roots.
+	vh, err := d.openVersion(endRoot.Version)
+	switch err {
+	case nil:
+	case api.ErrVersionPruned:
 		return nil, api.ErrWriteLogNotFound
+	default:
+		return nil, err
 	}
 
 	tx := d.db.NewTransactionAt(versionToTs(endRoot.Version), false)
@@ -318,6 +462,7 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 	defer func() {
 		if discardTx {
 			tx.Discard()
+			vh.Close()
 		}
 	}()
 
@@ -383,10 +528,10 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 					// Path has been found, deserialize and stream write logs.
 					var index int
 					discardTx = false
-					// Close iterator now as ReviveHashedDBWriteLogs can close the txn immediately.
+					// Close iterator now as StreamHashedDBWriteLogs can close the txn immediately.
 					it.Close()
-					return api.ReviveHashedDBWriteLogs(ctx,
-						func() (node.Root, api.HashedDBWriteLog, error) {
+					return api.StreamHashedDBWriteLogs(ctx,
+						func() (node.Root, *api.HashedDBWriteLogDecoder, error) {
 							if index >= len(nextItem.logKeys) {
 								return node.Root{}, nil, nil
 							}
@@ -403,10 +548,13 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 								return node.Root{}, nil, err
 							}
 
-							var log api.HashedDBWriteLog
-							err = item.Value(func(data []byte) error {
-								return cbor.UnmarshalTrusted(data, &log)
-							})
+							// Only the serialized write log is kept in memory, entries are decoded
+							// and their values fetched lazily while the write log is consumed.
+							data, err := item.ValueCopy(nil)
+							if err != nil {
+								return node.Root{}, nil, err
+							}
+							log, err := api.NewHashedDBWriteLogDecoder(data)
 							if err != nil {
 								return node.Root{}, nil, err
 							}
@@ -415,7 +563,7 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 							return root, log, nil
 						},
 						func(root node.Root, h hash.Hash) (*node.LeafNode, error) {
-							leaf, err := d.GetNode(root, &node.Pointer{Hash: h, Clean: true})
+							leaf, err := vh.GetNode(root, &node.Pointer{Hash: h, Clean: true})
 							if err != nil {
 								return nil, err
 							}
@@ -423,7 +571,9 @@ func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.
 						},
 						func() {
 							tx.Discard()
+							vh.Close()
 						},
+						d.writeLogBufSize,
 					)
 				}
 
@@ -471,6 +621,103 @@ func (d *badgerNodeDB) GetRootsForVersion(ctx context.Context, version uint64) (
 	return
 }
 
+func (d *badgerNodeDB) SetRootAnnotation(ctx context.Context, root node.Root, annotation []byte) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return err
+	}
+	if len(annotation) > api.MaxRootAnnotationSize {
+		return api.ErrAnnotationTooLarge
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	// Only roots in finalized versions can be annotated as any other roots may still be discarded.
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if !exists || lastFinalizedVersion < root.Version {
+		return api.ErrNotFinalized
+	}
+	if root.Version < d.meta.getEarliestVersion() {
+		return api.ErrVersionNotFound
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	rootsMeta, err := loadRootsMetadata(tx, root.Version)
+	if err != nil {
+		return err
+	}
+	if rootsMeta.Roots[root.Hash] == nil {
+		return api.ErrRootNotFound
+	}
+
+	switch len(annotation) {
+	case 0:
+		delete(rootsMeta.Annotations, root.Hash)
+	default:
+		if rootsMeta.Annotations == nil {
+			rootsMeta.Annotations = make(map[hash.Hash][]byte)
+		}
+		rootsMeta.Annotations[root.Hash] = append([]byte{}, annotation...)
+	}
+
+	if err = rootsMeta.save(tx); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save roots metadata: %w", err)
+	}
+	if err = tx.CommitAt(tsMetadata, nil); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to commit metadata: %w", err)
+	}
+	return d.syncCommit()
+}
+
+func (d *badgerNodeDB) GetRootAnnotation(ctx context.Context, root node.Root) ([]byte, error) {
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return nil, err
+	}
+
+	// If the version is earlier than the earliest version, we don't have the root.
+	if root.Version < d.meta.getEarliestVersion() {
+		return nil, api.ErrRootNotFound
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	rootsMeta, err := loadRootsMetadata(tx, root.Version)
+	if err != nil {
+		return nil, err
+	}
+	if rootsMeta.Roots[root.Hash] == nil {
+		return nil, api.ErrRootNotFound
+	}
+
+	annotation, ok := rootsMeta.Annotations[root.Hash]
+	if !ok {
+		return nil, api.ErrAnnotationNotFound
+	}
+	return annotation, nil
+}
+
+func (d *badgerNodeDB) GetRootAnnotationsForVersion(ctx context.Context, version uint64) (map[hash.Hash][]byte, error) {
+	// If the version is earlier than the earliest version, we don't have the roots.
+	if version < d.meta.getEarliestVersion() {
+		return nil, nil
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	rootsMeta, err := loadRootsMetadata(tx, version)
+	if err != nil {
+		return nil, err
+	}
+	return rootsMeta.Annotations, nil
+}
+
 func (d *badgerNodeDB) HasRoot(root node.Root) bool {
 	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
 		return false
@@ -558,7 +805,8 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 		}
 	}
 
-	// Go through all roots and prune them based on whether they are finalized or not.
+	// Go through all roots and prune them based on whether they are finalized or not. The lone
+	// node flag is true iff the node was removed by a finalized root (and is thus obsolete).
 	maybeLoneNodes := make(map[hash.Hash]bool)
 	notLoneNodes := make(map[hash.Hash]bool)
 
@@ -595,8 +843,8 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 			// node hash as long as we make sure that these nodes are not shared
 			// with any finalized roots added in the same version.
 			for _, n := range updatedNodes {
-				if !n.Removed {
-					maybeLoneNodes[n.Hash] = true
+				if _, ok := maybeLoneNodes[n.Hash]; !ok && !n.Removed {
+					maybeLoneNodes[n.Hash] = false
 				}
 			}
 
@@ -629,22 +877,39 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 	}
 
 	// Clean any lone nodes.
-	for h := range maybeLoneNodes {
+	nodeBatch := d.nodes.newWriter(version)
+	defer nodeBatch.cancel()
+	for h, obsolete := range maybeLoneNodes {
 		if notLoneNodes[h] {
 			continue
 		}
 
-		key := nodeKeyFmt.Encode(&h)
-		if err := versionBatch.Delete(key); err != nil {
+		nodeHash := h
+		if obsolete {
+			err = nodeBatch.removeObsolete(&nodeHash)
+		} else {
+			err = nodeBatch.remove(&nodeHash)
+		}
+		if err != nil {
 			return err
 		}
 	}
 
-	// Commit batch.
+	// Commit batches.
+	if err := nodeBatch.flush(); err != nil {
+		return err
+	}
 	if err := versionBatch.Flush(); err != nil {
 		return err
 	}
 
+	// Build the key filter from the finalized roots.
+	if d.keyFilterCapacity > 0 {
+		if err = d.updateKeyFilterLocked(ctx, tx, version, rootsMeta); err != nil {
+			return err
+		}
+	}
+
 	// Save roots metadata if changed.
 	if rootsChanged {
 		if err := rootsMeta.save(tx); err != nil {
@@ -667,6 +932,142 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, version uint64, roots []has
 			return err
 		}
 	}
+	return d.syncCommit()
+}
+
+func (d *badgerNodeDB) DiscardUnfinalized(ctx context.Context) ([]uint64, error) {
+	if d.readOnly {
+		return nil, api.ErrReadOnly
+	}
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.multipartVersion != multipartVersionNone {
+		return nil, api.ErrMultipartInProgress
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	// Determine the set of unfinalized versions.
+	var firstVersion uint64
+	if lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion(); exists {
+		firstVersion = lastFinalizedVersion + 1
+	}
+	var versions []uint64
+	func() {
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: rootsMetadataKeyFmt.Encode()})
+		defer it.Close()
+
+		for it.Seek(rootsMetadataKeyFmt.Encode(firstVersion)); it.Valid(); it.Next() {
+			var version uint64
+			if !rootsMetadataKeyFmt.Decode(it.Item().Key(), &version) {
+				break
+			}
+			versions = append(versions, version)
+		}
+	}()
+
+	for _, version := range versions {
+		if err := d.discardVersionLocked(tx, version); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.CommitAt(tsMetadata, nil); err != nil {
+		return nil, fmt.Errorf("mkvs/badger: failed to commit metadata: %w", err)
+	}
+	if err := d.syncCommit(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// discardVersionLocked removes all roots in the given unfinalized version together with any nodes
+// and write logs added for them. Metadata updates are performed in the passed transaction.
+//
+// Assumes metaUpdateLock is held when called.
+func (d *badgerNodeDB) discardVersionLocked(tx *badger.Txn, version uint64) error {
+	versionBatch := d.db.NewWriteBatchAt(versionToTs(version))
+	defer versionBatch.Cancel()
+	nodeBatch := d.nodes.newWriter(version)
+	defer nodeBatch.cancel()
+
+	rootsMeta, err := loadRootsMetadata(tx, version)
+	if err != nil {
+		return err
+	}
+
+	for rootHash := range rootsMeta.Roots {
+		rootUpdatedNodesKey := rootUpdatedNodesKeyFmt.Encode(version, &rootHash)
+
+		// Remove nodes added during this version for this root. As the version being discarded
+		// may be corrupted, failing to load the index only leaves the nodes in place.
+		var updatedNodes []updatedNode
+		item, err := tx.Get(rootUpdatedNodesKey)
+		if err == nil {
+			err = item.Value(func(data []byte) error {
+				return cbor.UnmarshalTrusted(data, &updatedNodes)
+			})
+		}
+		if err != nil {
+			d.logger.Warn("failed to load root updated nodes index, not removing nodes",
+				"err", err,
+				"version", version,
+				"root", rootHash,
+			)
+		}
+		for _, n := range updatedNodes {
+			if n.Removed {
+				continue
+			}
+			nodeHash := n.Hash
+			if err = nodeBatch.remove(&nodeHash); err != nil {
+				return err
+			}
+		}
+		if err = tx.Delete(rootUpdatedNodesKey); err != nil {
+			return err
+		}
+	}
+
+	// Remove write logs in version.
+	if !d.discardWriteLogs {
+		if err = func() error {
+			wtx := d.db.NewTransactionAt(versionToTs(version), false)
+			defer wtx.Discard()
+
+			it := wtx.NewIterator(badger.IteratorOptions{Prefix: writeLogKeyFmt.Encode(version)})
+			defer it.Close()
+
+			for it.Rewind(); it.Valid(); it.Next() {
+				if err = versionBatch.Delete(it.Item().KeyCopy(nil)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+
+	if err = nodeBatch.flush(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flush node batch: %w", err)
+	}
+	if err = versionBatch.Flush(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flush batch: %w", err)
+	}
+
+	// Delete roots metadata.
+	if err = tx.Delete(rootsMetadataKeyFmt.Encode(version)); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to remove roots metadata: %w", err)
+	}
+
+	d.logger.Info("discarded unfinalized version",
+		"version", version,
+		"roots", len(rootsMeta.Roots),
+	)
 	return nil
 }
 
@@ -692,9 +1093,19 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		return api.ErrNotEarliest
 	}
 
+	// Invalidate any open handles for the version and prevent new ones from being opened. This
+	// waits for any in-progress reads through the handles to complete and fails in case the
+	// version is pinned by a snapshot.
+	if err := d.invalidateVersionHandles(version); err != nil {
+		return err
+	}
+	defer d.clearPruningVersion()
+
 	// Remove all roots in version.
 	batch := d.db.NewWriteBatchAt(versionToTs(version))
 	defer batch.Cancel()
+	nodeBatch := d.nodes.newWriter(version)
+	defer nodeBatch.cancel()
 	tx := d.db.NewTransactionAt(versionToTs(version), true)
 	defer tx.Discard()
 
@@ -723,10 +1134,10 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		// Traverse the root and prune all items created in this version.
 		root := node.Root{Namespace: d.namespace, Version: version, Hash: rootHash}
 		var innerErr error
-		err := api.Visit(ctx, d, root, func(ctx context.Context, n node.Node) bool {
+		err := api.Visit(ctx, d.newPruneVersionHandle(version), root, func(ctx context.Context, n node.Node) bool {
 			if n.GetCreatedVersion() == version {
 				h := n.GetHash()
-				if innerErr = batch.Delete(nodeKeyFmt.Encode(&h)); innerErr != nil {
+				if innerErr = nodeBatch.remove(&h); innerErr != nil {
 					return false
 				}
 			}
@@ -745,6 +1156,11 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		return fmt.Errorf("mkvs/badger: failed to remove roots metadata: %w", err)
 	}
 
+	// Delete key filter.
+	if err := d.removeKeyFilterLocked(tx, version); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to remove key filter: %w", err)
+	}
+
 	// Prune all write logs in version.
 	if !d.discardWriteLogs {
 		wtx := d.db.NewTransactionAt(versionToTs(version), false)
@@ -761,7 +1177,10 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		}
 	}
 
-	// Commit batch.
+	// Commit batches.
+	if err := nodeBatch.flush(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flush node batch: %w", err)
+	}
 	if err := batch.Flush(); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to flush batch: %w", err)
 	}
@@ -774,10 +1193,15 @@ func (d *badgerNodeDB) Prune(ctx context.Context, version uint64) error {
 		return fmt.Errorf("mkvs/badger: failed to commit: %w", err)
 	}
 
+	// Remove nodes that became obsolete after the pruned version.
+	if err := d.nodes.prune(version); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to prune obsolete nodes: %w", err)
+	}
+
 	// Discard everything invalidated at or below given version.
 	d.db.SetDiscardTs(versionToTs(version + 1))
 
-	return nil
+	return d.syncCommit()
 }
 
 func (d *badgerNodeDB) StartMultipartInsert(version uint64) error {
@@ -841,19 +1265,20 @@ func (d *badgerNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) (
 	}
 
 	var logBatch *badger.WriteBatch
-	var readTxn *badger.Txn
+	var nodeReader nodeReader
 	if d.multipartVersion != multipartVersionNone {
 		// The node log is at a different version than the nodes themselves,
 		// which is awkward.
 		logBatch = d.db.NewWriteBatchAt(tsMetadata)
-		readTxn = d.db.NewTransactionAt(versionToTs(version), false)
+		nodeReader = d.nodes.newReader(version)
 	}
 
 	return &badgerBatch{
 		db:             d,
 		bat:            d.db.NewWriteBatchAt(versionToTs(version)),
+		nodes:          d.nodes.newWriter(version),
 		multipartNodes: logBatch,
-		readTxn:        readTxn,
+		nodeReader:     nodeReader,
 		oldRoot:        oldRoot,
 		chunk:          chunk,
 	}, nil
@@ -865,11 +1290,40 @@ func (d *badgerNodeDB) Size() (int64, error) {
 }
 
 func (d *badgerNodeDB) Sync() error {
+	if d.fsync != nil {
+		return d.fsync.sync()
+	}
+	return d.syncAll()
+}
+
+// syncAll syncs both the database and the node store.
+func (d *badgerNodeDB) syncAll() error {
+	if err := d.nodes.sync(); err != nil {
+		return err
+	}
 	return d.db.Sync()
 }
 
+// syncCommit syncs the database after a commit in case required by the fsync batching policy.
+func (d *badgerNodeDB) syncCommit() error {
+	if d.fsync == nil {
+		return nil
+	}
+	if err := d.fsync.commit(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to sync: %w", err)
+	}
+	return nil
+}
+
 func (d *badgerNodeDB) Close() {
 	d.closeOnce.Do(func() {
+		if d.fsync != nil {
+			if err := d.fsync.close(); err != nil {
+				d.logger.Error("failed to sync database on close",
+					"err", err,
+				)
+			}
+		}
 		d.gc.Close()
 
 		if err := d.db.Close(); err != nil {
@@ -877,19 +1331,27 @@ func (d *badgerNodeDB) Close() {
 				"err", err,
 			)
 		}
+		d.nodes.close()
 	})
 }
 
+// closeOnError closes the database and releases the node store when opening fails.
+func (d *badgerNodeDB) closeOnError() {
+	_ = d.db.Close()
+	d.nodes.close()
+}
+
 type badgerBatch struct {
 	api.BaseBatch
 
 	db             *badgerNodeDB
 	bat            *badger.WriteBatch
+	nodes          nodeWriter
 	multipartNodes *badger.WriteBatch
 
-	// readTx is the read transaction used to check for node existence during
+	// nodeReader is the node reader used to check for node existence during
 	// a multipart restore.
-	readTxn *badger.Txn
+	nodeReader nodeReader
 
 	oldRoot node.Root
 	chunk   bool
@@ -1033,6 +1495,9 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 			return fmt.Errorf("mkvs/badger: failed to flush node log batch: %w", err)
 		}
 	}
+	if err = ba.nodes.flush(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flush node batch: %w", err)
+	}
 	if err = ba.bat.Flush(); err != nil {
 		return fmt.Errorf("mkvs/badger: failed to flush batch: %w", err)
 	}
@@ -1046,14 +1511,18 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 	ba.annotations = nil
 	ba.updatedNodes = nil
 
+	if err = ba.db.syncCommit(); err != nil {
+		return err
+	}
 	return ba.BaseBatch.Commit(root)
 }
 
 func (ba *badgerBatch) Reset() {
 	ba.bat.Cancel()
+	ba.nodes.cancel()
 	if ba.multipartNodes != nil {
 		ba.multipartNodes.Cancel()
-		ba.readTxn.Discard()
+		ba.nodeReader.discard()
 	}
 	ba.writeLog = nil
 	ba.annotations = nil
@@ -1072,15 +1541,15 @@ func (s *badgerSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
 
 	h := ptr.Node.GetHash()
 	s.batch.updatedNodes = append(s.batch.updatedNodes, updatedNode{Hash: h})
-	nodeKey := nodeKeyFmt.Encode(&h)
 	if s.batch.multipartNodes != nil {
-		if _, err = s.batch.readTxn.Get(nodeKey); err != nil && errors.Is(err, badger.ErrKeyNotFound) {
+		var exists bool
+		if exists, err = s.batch.nodeReader.has(&h); err == nil && !exists {
 			if err = s.batch.multipartNodes.Set(multipartRestoreNodeLogKeyFmt.Encode(&h), []byte{}); err != nil {
 				return err
 			}
 		}
 	}
-	if err = s.batch.bat.Set(nodeKey, data); err != nil {
+	if err = s.batch.nodes.put(&h, data); err != nil {
 		return err
 	}
 	return nil
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
@@ -325,3 +326,441 @@ func TestReadOnlyBatch(t *testing.T) {
 	_, err = badgerdb.NewBatch(node.Root{}, 13, false)
 	require.Error(err, "NewBatch()")
 }
+
+func TestVersionHandles(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	var emptyRoot node.Root
+	emptyRoot.Namespace = testNs
+	emptyRoot.Hash.Empty()
+
+	tree := mkvs.NewWithRoot(nil, ndb, emptyRoot)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[0])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit()")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+	ptr := &node.Pointer{Clean: true, Hash: rootHash}
+
+	vh, err := ndb.OpenVersion(0)
+	require.NoError(err, "OpenVersion()")
+	require.EqualValues(0, vh.Version(), "Version()")
+	_, err = vh.GetNode(root, ptr)
+	require.NoError(err, "GetNode() through version handle")
+
+	closedVh, err := ndb.OpenVersion(0)
+	require.NoError(err, "OpenVersion()")
+	closedVh.Close()
+	closedVh.Close()
+	_, err = closedVh.GetNode(root, ptr)
+	require.Equal(api.ErrVersionHandleClosed, err, "GetNode() through closed version handle")
+
+	// Pruning the version should invalidate the handle.
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+	_, err = vh.GetNode(root, ptr)
+	require.Equal(api.ErrVersionPruned, err, "GetNode() through invalidated version handle")
+	vh.Close()
+
+	_, err = ndb.OpenVersion(0)
+	require.Equal(api.ErrVersionPruned, err, "OpenVersion() for a pruned version")
+	require.Empty(ndb.(*badgerNodeDB).versionHandles, "no version handles should be tracked")
+}
+
+func TestSnapshots(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	var emptyRoot node.Root
+	emptyRoot.Namespace = testNs
+	emptyRoot.Hash.Empty()
+
+	tree := mkvs.NewWithRoot(nil, ndb, emptyRoot)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[0])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit()")
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+	ptr := &node.Pointer{Clean: true, Hash: rootHash}
+
+	snap1, err := ndb.Snapshot(0)
+	require.NoError(err, "Snapshot()")
+	require.EqualValues(0, snap1.Version(), "Version()")
+	snap2, err := ndb.Snapshot(0)
+	require.NoError(err, "Snapshot()")
+
+	// Pruning a pinned version should fail and leave the snapshots intact.
+	err = ndb.Prune(ctx, 0)
+	require.Equal(api.ErrVersionPinned, err, "Prune() of a pinned version")
+	_, err = snap1.GetNode(root, ptr)
+	require.NoError(err, "GetNode() through snapshot")
+
+	snap1.Close()
+	snap1.Close()
+	_, err = snap1.GetNode(root, ptr)
+	require.Equal(api.ErrVersionHandleClosed, err, "GetNode() through closed snapshot")
+
+	// The version should remain pinned while any snapshot is open.
+	err = ndb.Prune(ctx, 0)
+	require.Equal(api.ErrVersionPinned, err, "Prune() of a pinned version")
+	_, err = snap2.GetNode(root, ptr)
+	require.NoError(err, "GetNode() through snapshot")
+	snap2.Close()
+
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+
+	_, err = ndb.Snapshot(0)
+	require.Equal(api.ErrVersionPruned, err, "Snapshot() for a pruned version")
+	require.Empty(ndb.(*badgerNodeDB).meta.snapshots, "no versions should be pinned")
+}
+
+func TestGetNodes(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	root := fillDB(ctx, require, testValues, 1, ndb)
+
+	rootNode, err := ndb.GetNode(root, &node.Pointer{Clean: true, Hash: root.Hash})
+	require.NoError(err, "GetNode(root)")
+	rootInternal, ok := rootNode.(*node.InternalNode)
+	require.True(ok, "root node should be an internal node")
+
+	ptrs := []*node.Pointer{
+		{Clean: true, Hash: rootInternal.Right.Hash},
+		{Clean: true, Hash: hash.NewFromBytes([]byte("missing node"))},
+		{Clean: true, Hash: root.Hash},
+		{Clean: true, Hash: rootInternal.Left.Hash},
+	}
+	nodes, err := ndb.GetNodes(root, ptrs)
+	require.NoError(err, "GetNodes()")
+	require.Len(nodes, len(ptrs), "GetNodes() should return a node for each pointer")
+	require.Nil(nodes[1], "GetNodes() should return nil for missing nodes")
+	for _, i := range []int{0, 2, 3} {
+		require.NotNil(nodes[i], "GetNodes() should return existing nodes")
+		require.EqualValues(ptrs[i].Hash, nodes[i].GetHash(), "GetNodes() should return nodes in order")
+	}
+
+	_, err = ndb.GetNodes(node.Root{Namespace: common.NewTestNamespaceFromSeed([]byte("other ns"), 0)}, ptrs)
+	require.Error(err, "GetNodes() with a bad namespace should fail")
+}
+
+func TestRootAnnotations(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	var emptyRoot node.Root
+	emptyRoot.Namespace = testNs
+	emptyRoot.Hash.Empty()
+
+	tree := mkvs.NewWithRoot(nil, ndb, emptyRoot)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[0])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit()")
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+
+	// Roots in non-finalized versions cannot be annotated.
+	err = ndb.SetRootAnnotation(ctx, root, []byte("block 0"))
+	require.Equal(api.ErrNotFinalized, err, "SetRootAnnotation() of a non-finalized root")
+
+	err = ndb.Finalize(ctx, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	_, err = ndb.GetRootAnnotation(ctx, root)
+	require.Equal(api.ErrAnnotationNotFound, err, "GetRootAnnotation() of a root without annotation")
+
+	err = ndb.SetRootAnnotation(ctx, root, []byte("block 0"))
+	require.NoError(err, "SetRootAnnotation()")
+	annotation, err := ndb.GetRootAnnotation(ctx, root)
+	require.NoError(err, "GetRootAnnotation()")
+	require.EqualValues([]byte("block 0"), annotation, "GetRootAnnotation() should return the annotation")
+	annotations, err := ndb.GetRootAnnotationsForVersion(ctx, 0)
+	require.NoError(err, "GetRootAnnotationsForVersion()")
+	require.EqualValues(map[hash.Hash][]byte{rootHash: []byte("block 0")}, annotations)
+
+	// Roots themselves should be unaffected by annotations.
+	roots, err := ndb.GetRootsForVersion(ctx, 0)
+	require.NoError(err, "GetRootsForVersion()")
+	require.EqualValues([]hash.Hash{rootHash}, roots)
+
+	// Invalid annotations.
+	err = ndb.SetRootAnnotation(ctx, root, make([]byte, api.MaxRootAnnotationSize+1))
+	require.Equal(api.ErrAnnotationTooLarge, err, "SetRootAnnotation() with a too large annotation")
+	missingRoot := node.Root{Namespace: testNs, Version: 0, Hash: hash.NewFromBytes([]byte("missing root"))}
+	err = ndb.SetRootAnnotation(ctx, missingRoot, []byte("block 0"))
+	require.Equal(api.ErrRootNotFound, err, "SetRootAnnotation() of a missing root")
+	_, err = ndb.GetRootAnnotation(ctx, missingRoot)
+	require.Equal(api.ErrRootNotFound, err, "GetRootAnnotation() of a missing root")
+
+	// Removing the annotation.
+	err = ndb.SetRootAnnotation(ctx, root, nil)
+	require.NoError(err, "SetRootAnnotation(nil)")
+	_, err = ndb.GetRootAnnotation(ctx, root)
+	require.Equal(api.ErrAnnotationNotFound, err, "GetRootAnnotation() of a removed annotation")
+
+	// Annotations are pruned together with the version.
+	err = ndb.SetRootAnnotation(ctx, root, []byte("block 0"))
+	require.NoError(err, "SetRootAnnotation()")
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+	_, err = ndb.GetRootAnnotation(ctx, root)
+	require.Equal(api.ErrRootNotFound, err, "GetRootAnnotation() of a pruned root")
+	annotations, err = ndb.GetRootAnnotationsForVersion(ctx, 0)
+	require.NoError(err, "GetRootAnnotationsForVersion()")
+	require.Empty(annotations, "GetRootAnnotationsForVersion() of a pruned version")
+}
+
+func TestDiscardUnfinalized(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	root := node.Root{Namespace: testNs}
+	root.Hash.Empty()
+	var roots []node.Root
+	for version := uint64(0); version < 3; version++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte("key"), testValues[version])
+		require.NoError(err, "Insert()")
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit()")
+		tree.Close()
+
+		root = node.Root{Namespace: testNs, Version: version, Hash: rootHash}
+		roots = append(roots, root)
+	}
+	err = ndb.Finalize(ctx, 0, []hash.Hash{roots[0].Hash})
+	require.NoError(err, "Finalize()")
+
+	versions, err := ndb.DiscardUnfinalized(ctx)
+	require.NoError(err, "DiscardUnfinalized()")
+	require.EqualValues([]uint64{1, 2}, versions, "DiscardUnfinalized() should discard all unfinalized versions")
+
+	require.True(ndb.HasRoot(roots[0]), "finalized root should be kept")
+	for _, r := range roots[1:] {
+		require.False(ndb.HasRoot(r), "unfinalized root should be discarded")
+		_, err = ndb.GetNode(r, &node.Pointer{Clean: true, Hash: r.Hash})
+		require.Equal(api.ErrNodeNotFound, err, "GetNode() of a discarded root")
+	}
+	_, err = ndb.GetWriteLog(ctx, roots[0], roots[1])
+	require.Equal(api.ErrWriteLogNotFound, err, "GetWriteLog() of a discarded root")
+
+	// Discarded versions can be applied and finalized again.
+	tree := mkvs.NewWithRoot(nil, ndb, roots[0])
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("key"), testValues[1])
+	require.NoError(err, "Insert()")
+	_, rootHash, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit()")
+	require.EqualValues(roots[1].Hash, rootHash, "root should be the same as before")
+	err = ndb.Finalize(ctx, 1, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	versions, err = ndb.DiscardUnfinalized(ctx)
+	require.NoError(err, "DiscardUnfinalized()")
+	require.Empty(versions, "DiscardUnfinalized() without unfinalized versions")
+}
+
+func TestLegacyRootsMetadata(t *testing.T) {
+	require := require.New(t)
+
+	rootHash := hash.NewFromBytes([]byte("root"))
+	legacy := legacyRootsMetadata{
+		Roots: map[hash.Hash][]hash.Hash{rootHash: {}},
+	}
+
+	rootsMeta := &rootsMetadata{version: 42}
+	err := cbor.Unmarshal(cbor.Marshal(legacy), &rootsMeta)
+	require.NoError(err, "legacy roots metadata should decode")
+	require.EqualValues(legacy.Roots, rootsMeta.Roots)
+	require.Nil(rootsMeta.Annotations)
+	require.EqualValues(42, rootsMeta.version, "version should be retained")
+
+	rootsMeta.Annotations = map[hash.Hash][]byte{rootHash: []byte("annotation")}
+	var decRootsMeta rootsMetadata
+	err = cbor.Unmarshal(cbor.Marshal(rootsMeta), &decRootsMeta)
+	require.NoError(err, "roots metadata should decode")
+	require.EqualValues(rootsMeta.Roots, decRootsMeta.Roots)
+	require.EqualValues(rootsMeta.Annotations, decRootsMeta.Annotations)
+}
+
+func TestAnalyze(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	cfg := *dbCfg
+	cfg.MemoryOnly = false
+	cfg.DB = dir
+	ndb, err := New(&cfg)
+	require.NoError(err, "New()")
+	_ = fillDB(ctx, require, testValues, 1, ndb)
+	ndb.Close()
+
+	report, err := Analyze(ctx, &AnalyzeConfig{
+		DB:             dir,
+		KeyPrefixLen:   1,
+		NumTopPrefixes: 2,
+	})
+	require.NoError(err, "Analyze()")
+	require.EqualValues(testNs, report.Namespace, "namespace should be reported")
+	require.True(report.LiveBytes > 0, "live bytes should be reported")
+
+	var nodes uint64
+	for _, vs := range report.Versions {
+		nodes += vs.Nodes
+	}
+	// Three leaves and two internal nodes.
+	require.EqualValues(5, nodes, "all nodes should be reported")
+	require.NotEmpty(report.KeyTypes, "key types should be reported")
+
+	// All test keys start with a digit, so the top prefixes should be limited to two.
+	require.Len(report.TopPrefixes, 2, "top prefixes should be limited")
+	for _, ps := range report.TopPrefixes {
+		require.EqualValues(1, ps.Leaves, "each prefix should contain a single leaf")
+		require.Len(ps.Prefix, 1, "prefix length should be respected")
+	}
+}
+
+func TestKeyFilter(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cfg := *dbCfg
+	cfg.KeyFilterCapacity = 150
+	ndb, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+	badgerdb := ndb.(*badgerNodeDB)
+
+	root := node.Root{Namespace: testNs}
+	root.Hash.Empty()
+	commit := func(version uint64, insert []string, remove []string) node.Root {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		defer tree.Close()
+		for _, key := range insert {
+			err = tree.Insert(ctx, []byte(key), []byte("value of "+key))
+			require.NoError(err, "Insert()")
+		}
+		for _, key := range remove {
+			err = tree.Remove(ctx, []byte(key))
+			require.NoError(err, "Remove()")
+		}
+		_, rootHash, cerr := tree.Commit(ctx, testNs, version)
+		require.NoError(cerr, "Commit()")
+		err = ndb.Finalize(ctx, version, []hash.Hash{rootHash})
+		require.NoError(err, "Finalize()")
+		return node.Root{Namespace: testNs, Version: version, Hash: rootHash}
+	}
+	keys := func(prefix string, n int) (keys []string) {
+		for i := 0; i < n; i++ {
+			keys = append(keys, fmt.Sprintf("%s %d", prefix, i))
+		}
+		return
+	}
+
+	// Version 0 builds the filter from scratch.
+	root = commit(0, keys("key", 100), nil)
+	require.EqualValues(100, badgerdb.keyFilters[0].Count, "filter should contain all keys")
+	for _, key := range keys("key", 100) {
+		require.True(badgerdb.MayContainKey(root, []byte(key)), "filter should contain inserted keys")
+	}
+	var falsePositives int
+	for _, key := range keys("missing", 1000) {
+		if badgerdb.MayContainKey(root, []byte(key)) {
+			falsePositives++
+		}
+	}
+	require.Less(falsePositives, 50, "filter should reject most missing keys")
+	require.True(badgerdb.MayContainKey(node.Root{Namespace: testNs, Version: 5, Hash: root.Hash}, []byte("missing 0")),
+		"roots without a filter may contain any key")
+
+	// Version 1 extends the previous filter with the new keys only.
+	root = commit(1, keys("more", 60), []string{"key 0"})
+	require.EqualValues(160, badgerdb.keyFilters[1].Count, "filter should be derived from the previous version")
+	for _, key := range append(keys("key", 100), keys("more", 60)...) {
+		require.True(badgerdb.MayContainKey(root, []byte(key)), "filter should contain inserted keys")
+	}
+
+	tree := mkvs.NewWithRoot(nil, ndb, root)
+	defer tree.Close()
+	value, err := tree.Get(ctx, []byte("more 5"))
+	require.NoError(err, "Get()")
+	require.EqualValues([]byte("value of more 5"), value, "Get() should return existing values")
+	for _, key := range keys("missing", 100) {
+		value, err = tree.Get(ctx, []byte(key))
+		require.NoError(err, "Get()")
+		require.Nil(value, "Get() should not return values for missing keys")
+	}
+
+	// Version 2 rebuilds the filter as the previous one exceeds its capacity.
+	root = commit(2, []string{"last"}, nil)
+	require.EqualValues(160, badgerdb.keyFilters[2].Count, "filter should be rebuilt from scratch")
+	require.True(badgerdb.MayContainKey(root, []byte("last")), "filter should contain inserted keys")
+
+	// Pruning removes the filter.
+	err = ndb.Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+	require.Nil(badgerdb.keyFilters[0], "pruning should remove the filter")
+}
+
+func TestTriggerGC(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "oasis-storage-database-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(dir)
+
+	cfg := *dbCfg
+	cfg.MemoryOnly = false
+	cfg.DB = dir + "/db"
+	cfg.SharedNodeDB = dir + "/shared"
+	cfg.GC = api.GCPolicy{
+		Interval:    -1,
+		MaxRewrites: 1,
+	}
+	ndb, err := New(&cfg)
+	require.NoError(err, "New()")
+
+	_ = fillDB(ctx, require, testValues, 1, ndb)
+
+	gc, ok := ndb.(api.GarbageCollector)
+	require.True(ok, "badger node database should support triggering GC")
+	rewrites, err := gc.TriggerGC(ctx)
+	require.NoError(err, "TriggerGC()")
+	require.EqualValues(0, rewrites, "nothing should be rewritten in a fresh database")
+
+	ndb.Close()
+	_, err = gc.TriggerGC(ctx)
+	require.Error(err, "TriggerGC() should fail after the database is closed")
+}
@@ -0,0 +1,103 @@
+package badger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+// fsyncBatcher syncs the database according to a fsync batching policy.
+type fsyncBatcher struct {
+	sync.Mutex
+
+	logger *logging.Logger
+	policy api.FsyncBatchPolicy
+	syncFn func() error
+
+	pending uint64
+	timer   *time.Timer
+	closed  bool
+}
+
+func newFsyncBatcher(logger *logging.Logger, policy api.FsyncBatchPolicy, syncFn func() error) *fsyncBatcher {
+	return &fsyncBatcher{
+		logger: logger,
+		policy: policy,
+		syncFn: syncFn,
+	}
+}
+
+// commit records a successful commit and syncs the database in case the number of unsynced
+// commits reached the limit.
+func (b *fsyncBatcher) commit() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.pending++
+	if b.policy.Commits > 0 && b.pending >= b.policy.Commits {
+		return b.syncLocked()
+	}
+	if b.policy.Interval > 0 && b.timer == nil {
+		b.timer = time.AfterFunc(b.policy.Interval, b.onTimer)
+	}
+	return nil
+}
+
+func (b *fsyncBatcher) onTimer() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed || b.timer == nil {
+		return
+	}
+	b.timer = nil
+
+	if err := b.syncLocked(); err != nil {
+		b.logger.Error("failed to sync database",
+			"err", err,
+		)
+	}
+}
+
+// sync syncs the database, resetting the batch.
+func (b *fsyncBatcher) sync() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	return b.syncLocked()
+}
+
+func (b *fsyncBatcher) syncLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.pending = 0
+
+	return b.syncFn()
+}
+
+// close syncs any unsynced commits and stops the batcher.
+func (b *fsyncBatcher) close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	if b.pending == 0 {
+		return nil
+	}
+	return b.syncLocked()
+}
@@ -0,0 +1,95 @@
+package badger
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestFsyncBatcher(t *testing.T) {
+	require := require.New(t)
+	logger := logging.GetLogger("mkvs/db/badger/test")
+
+	var syncs uint64
+	syncFn := func() error {
+		atomic.AddUint64(&syncs, 1)
+		return nil
+	}
+
+	// Count-based batching.
+	b := newFsyncBatcher(logger, api.FsyncBatchPolicy{Commits: 3}, syncFn)
+	for i := 0; i < 7; i++ {
+		require.NoError(b.commit(), "commit")
+	}
+	require.EqualValues(2, atomic.LoadUint64(&syncs), "database should be synced every 3 commits")
+	require.NoError(b.sync(), "sync")
+	require.EqualValues(3, atomic.LoadUint64(&syncs), "explicit sync should sync the database")
+	require.NoError(b.commit(), "commit")
+	require.NoError(b.close(), "close")
+	require.EqualValues(4, atomic.LoadUint64(&syncs), "close should sync pending commits")
+	require.NoError(b.close(), "close")
+	require.NoError(b.commit(), "commit")
+	require.EqualValues(4, atomic.LoadUint64(&syncs), "closed batcher should not sync")
+
+	// Interval-based batching.
+	atomic.StoreUint64(&syncs, 0)
+	b = newFsyncBatcher(logger, api.FsyncBatchPolicy{Interval: 50 * time.Millisecond}, syncFn)
+	for i := 0; i < 10; i++ {
+		require.NoError(b.commit(), "commit")
+	}
+	require.EqualValues(0, atomic.LoadUint64(&syncs), "database should not be synced before the interval")
+	require.Eventually(func() bool {
+		return atomic.LoadUint64(&syncs) == 1
+	}, time.Second, 10*time.Millisecond, "database should be synced after the interval")
+	require.NoError(b.close(), "close")
+	require.EqualValues(1, atomic.LoadUint64(&syncs), "close should not sync without pending commits")
+}
+
+func TestFsyncBatch(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	cfg := *dbCfg
+	cfg.DB = dir
+	cfg.MemoryOnly = false
+	cfg.NoFsync = false
+	cfg.FsyncBatch = api.FsyncBatchPolicy{Commits: 2}
+	ndb, err := New(&cfg)
+	require.NoError(err, "New()")
+
+	root := node.Root{Namespace: testNs}
+	root.Hash.Empty()
+	for version := uint64(0); version < 5; version++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte("key"), []byte{byte(version)})
+		require.NoError(err, "Insert()")
+		_, rootHash, cerr := tree.Commit(ctx, testNs, version)
+		require.NoError(cerr, "Commit()")
+		err = ndb.Finalize(ctx, version, []hash.Hash{rootHash})
+		require.NoError(err, "Finalize()")
+		tree.Close()
+
+		root = node.Root{Namespace: testNs, Version: version, Hash: rootHash}
+	}
+	ndb.Close()
+
+	// Everything should be persisted after the database is closed.
+	ndb, err = New(&cfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+
+	latest, err := ndb.GetLatestVersion(ctx)
+	require.NoError(err, "GetLatestVersion()")
+	require.EqualValues(4, latest, "all versions should be persisted")
+	require.True(ndb.HasRoot(root), "latest root should be persisted")
+}
@@ -0,0 +1,40 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+var _ api.GarbageCollector = (*badgerNodeDB)(nil)
+
+// gcConfig converts the node database GC policy into a value log GC worker configuration.
+func gcConfig(policy *api.GCPolicy) cmnBadger.GCConfig {
+	return cmnBadger.GCConfig{
+		Interval:     policy.Interval,
+		DiscardRatio: policy.DiscardRatio,
+		MaxRewrites:  policy.MaxRewrites,
+	}
+}
+
+// Implements api.GarbageCollector.
+func (d *badgerNodeDB) TriggerGC(ctx context.Context) (uint64, error) {
+	rewrites, err := d.gc.Trigger(ctx)
+	if err != nil {
+		return rewrites, fmt.Errorf("mkvs/badger: failed to GC value log: %w", err)
+	}
+
+	// Also collect the shared node database in case it is used.
+	if store, ok := d.nodes.(*sharedNodeStore); ok {
+		var sharedRewrites uint64
+		sharedRewrites, err = store.shared.gc.Trigger(ctx)
+		rewrites += sharedRewrites
+		if err != nil {
+			return rewrites, fmt.Errorf("mkvs/badger: failed to GC shared node database value log: %w", err)
+		}
+	}
+
+	return rewrites, nil
+}
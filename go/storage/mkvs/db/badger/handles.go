@@ -0,0 +1,189 @@
+package badger
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// versionHandle is a handle for consistent reads of a single version.
+type versionHandle struct {
+	db      *badgerNodeDB
+	version uint64
+
+	// registered is true iff the handle is tracked by the database and is subject to
+	// invalidation on prune.
+	registered bool
+	// pinned is true iff the handle is a snapshot that pins its version against pruning.
+	pinned bool
+
+	// l is held for reading during each lookup and for writing when the handle is closed or
+	// invalidated, so that invalidation waits for any in-progress lookups.
+	l           sync.RWMutex
+	closed      bool
+	invalidated bool
+}
+
+func (h *versionHandle) Version() uint64 {
+	return h.version
+}
+
+func (h *versionHandle) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	if ptr == nil || !ptr.IsClean() {
+		panic("mkvs/badger: attempted to get invalid pointer from node database")
+	}
+	if err := h.db.sanityCheckNamespace(root.Namespace); err != nil {
+		return nil, err
+	}
+	if root.Version != h.version {
+		return nil, fmt.Errorf("mkvs/badger: root version does not match handle (expected: %d got: %d)",
+			h.version,
+			root.Version,
+		)
+	}
+
+	h.l.RLock()
+	defer h.l.RUnlock()
+
+	switch {
+	case h.closed:
+		return nil, api.ErrVersionHandleClosed
+	case h.invalidated:
+		return nil, api.ErrVersionPruned
+	default:
+	}
+
+	return h.db.getNode(h.version, ptr)
+}
+
+func (h *versionHandle) Close() {
+	h.l.Lock()
+	if h.closed {
+		h.l.Unlock()
+		return
+	}
+	h.closed = true
+	h.l.Unlock()
+
+	if h.registered {
+		h.db.unregisterVersionHandle(h)
+	}
+	if h.pinned {
+		h.db.meta.unpinVersion(h.version)
+	}
+}
+
+func (h *versionHandle) invalidate() {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	h.invalidated = true
+}
+
+func (d *badgerNodeDB) OpenVersion(version uint64) (api.VersionHandle, error) {
+	return d.openVersion(version)
+}
+
+func (d *badgerNodeDB) openVersion(version uint64) (*versionHandle, error) {
+	d.versionHandlesLock.Lock()
+	defer d.versionHandlesLock.Unlock()
+
+	if version < d.meta.getEarliestVersion() {
+		return nil, api.ErrVersionPruned
+	}
+	if d.pruningVersion != nil && version <= *d.pruningVersion {
+		return nil, api.ErrVersionPruned
+	}
+
+	h := &versionHandle{
+		db:         d,
+		version:    version,
+		registered: true,
+	}
+	handles := d.versionHandles[version]
+	if handles == nil {
+		handles = make(map[*versionHandle]struct{})
+		d.versionHandles[version] = handles
+	}
+	handles[h] = struct{}{}
+
+	return h, nil
+}
+
+func (d *badgerNodeDB) Snapshot(version uint64) (api.Snapshot, error) {
+	d.versionHandlesLock.Lock()
+	defer d.versionHandlesLock.Unlock()
+
+	if version < d.meta.getEarliestVersion() {
+		return nil, api.ErrVersionPruned
+	}
+	if d.pruningVersion != nil && version <= *d.pruningVersion {
+		return nil, api.ErrVersionPruned
+	}
+
+	// Snapshots are not tracked as they are never invalidated. Instead the version is pinned so
+	// that any attempt to prune it fails for as long as the snapshot is open.
+	d.meta.pinVersion(version)
+
+	return &versionHandle{
+		db:      d,
+		version: version,
+		pinned:  true,
+	}, nil
+}
+
+func (d *badgerNodeDB) unregisterVersionHandle(h *versionHandle) {
+	d.versionHandlesLock.Lock()
+	defer d.versionHandlesLock.Unlock()
+
+	handles := d.versionHandles[h.version]
+	delete(handles, h)
+	if len(handles) == 0 {
+		delete(d.versionHandles, h.version)
+	}
+}
+
+// invalidateVersionHandles marks the given version as being pruned, which prevents any new handles
+// from being opened, and invalidates all open handles for the version.
+//
+// In case the version is pinned by an open snapshot, nothing is changed and ErrVersionPinned is
+// returned.
+//
+// Assumes metaUpdateLock is held when called.
+func (d *badgerNodeDB) invalidateVersionHandles(version uint64) error {
+	d.versionHandlesLock.Lock()
+	defer d.versionHandlesLock.Unlock()
+
+	if d.meta.isVersionPinned(version) {
+		return api.ErrVersionPinned
+	}
+
+	d.pruningVersion = &version
+	for h := range d.versionHandles[version] {
+		h.invalidate()
+	}
+	delete(d.versionHandles, version)
+
+	return nil
+}
+
+// clearPruningVersion clears the version being pruned after pruning has finished.
+//
+// Assumes metaUpdateLock is held when called.
+func (d *badgerNodeDB) clearPruningVersion() {
+	d.versionHandlesLock.Lock()
+	defer d.versionHandlesLock.Unlock()
+
+	d.pruningVersion = nil
+}
+
+// newPruneVersionHandle creates a version handle for use by Prune itself. Such a handle is not
+// tracked and is therefore not invalidated by the prune operation that is using it.
+func (d *badgerNodeDB) newPruneVersionHandle(version uint64) *versionHandle {
+	return &versionHandle{
+		db:      d,
+		version: version,
+	}
+}
@@ -0,0 +1,347 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// keyFilterNumVersions is the number of most recent finalized versions for which key filters
+	// are retained.
+	keyFilterNumVersions = 4
+
+	// defaultKeyFilterFalsePositiveRate is the false positive rate used in case none is configured.
+	defaultKeyFilterFalsePositiveRate = 0.01
+)
+
+var (
+	keyFilterQueries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_key_filter_queries",
+			Help: "Number of MKVS key existence filter queries.",
+		},
+		[]string{"result"},
+	)
+	keyFilterFalsePositives = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_key_filter_false_positives",
+			Help: "Number of MKVS key existence filter queries that were false positives.",
+		},
+	)
+	keyFilterRebuilds = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_storage_mkvs_key_filter_rebuilds",
+			Help: "Number of MKVS key existence filters rebuilt from scratch.",
+		},
+	)
+
+	keyFilterCollectors = []prometheus.Collector{
+		keyFilterQueries,
+		keyFilterFalsePositives,
+		keyFilterRebuilds,
+	}
+
+	labelKeyFilterNegative = prometheus.Labels{"result": "negative"}
+	labelKeyFilterPositive = prometheus.Labels{"result": "positive"}
+
+	keyFilterMetricsOnce sync.Once
+
+	_ api.KeyFilter = (*badgerNodeDB)(nil)
+)
+
+// keyFilter is a Bloom filter over the keys of all finalized roots in a version.
+//
+// NOTE: Public fields of this structure are part of the on-disk format.
+type keyFilter struct {
+	_ struct{} `cbor:",toarray"` // nolint
+
+	// Roots are the finalized roots whose keys have been added to the filter.
+	Roots []hash.Hash
+	// Count is the number of keys added since the filter has been built from scratch. As filters
+	// for later versions are derived from earlier ones, keys that have since been updated or
+	// removed are also counted.
+	Count uint64
+	// NumHashes is the number of hash functions.
+	NumHashes uint64
+	// Bits is the filter bit set.
+	Bits []byte
+}
+
+func newKeyFilter(capacity uint64, fpRate float64) *keyFilter {
+	n := float64(capacity)
+	numBits := uint64(math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if numBits < 64 {
+		numBits = 64
+	}
+	numHashes := uint64(math.Round(float64(numBits) / n * math.Ln2))
+	if numHashes < 1 {
+		numHashes = 1
+	}
+
+	return &keyFilter{
+		NumHashes: numHashes,
+		Bits:      make([]byte, (numBits+7)/8),
+	}
+}
+
+// derive returns a copy of the filter that can be extended with the keys of the next version.
+func (f *keyFilter) derive() *keyFilter {
+	return &keyFilter{
+		Count:     f.Count,
+		NumHashes: f.NumHashes,
+		Bits:      append([]byte{}, f.Bits...),
+	}
+}
+
+// isCompatible checks whether the filter has the same parameters as the other filter.
+func (f *keyFilter) isCompatible(other *keyFilter) bool {
+	return f.NumHashes == other.NumHashes && len(f.Bits) == len(other.Bits)
+}
+
+func (f *keyFilter) hasRoot(rootHash hash.Hash) bool {
+	for _, h := range f.Roots {
+		if h.Equal(&rootHash) {
+			return true
+		}
+	}
+	return false
+}
+
+// bitIndices calls fn with the index of each bit corresponding to the given key, stopping early in
+// case fn returns false.
+func (f *keyFilter) bitIndices(key []byte, fn func(idx uint64) bool) {
+	h := fnv.New128a()
+	_, _ = h.Write(key)
+	sum := h.Sum(nil)
+
+	// Use double hashing to derive all of the hash functions.
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[8+i])
+	}
+	numBits := uint64(len(f.Bits)) * 8
+	for i := uint64(0); i < f.NumHashes; i++ {
+		if !fn((h1 + i*h2) % numBits) {
+			return
+		}
+	}
+}
+
+func (f *keyFilter) add(key []byte) {
+	f.bitIndices(key, func(idx uint64) bool {
+		f.Bits[idx/8] |= 1 << (idx % 8)
+		return true
+	})
+	f.Count++
+}
+
+func (f *keyFilter) mayContain(key []byte) bool {
+	contains := true
+	f.bitIndices(key, func(idx uint64) bool {
+		contains = f.Bits[idx/8]&(1<<(idx%8)) != 0
+		return contains
+	})
+	return contains
+}
+
+// loadKeyFilters loads the key filters of the most recent finalized versions.
+func (d *badgerNodeDB) loadKeyFilters() error {
+	lastFinalizedVersion, exists := d.meta.getLastFinalizedVersion()
+	if !exists {
+		return nil
+	}
+
+	tx := d.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+
+	for i := uint64(0); i < keyFilterNumVersions && i <= lastFinalizedVersion; i++ {
+		version := lastFinalizedVersion - i
+		item, err := tx.Get(keyFilterKeyFmt.Encode(version))
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			continue
+		default:
+			return err
+		}
+
+		var f keyFilter
+		if err = item.Value(func(data []byte) error {
+			return cbor.UnmarshalTrusted(data, &f)
+		}); err != nil {
+			return fmt.Errorf("mkvs/badger: corrupted key filter: %w", err)
+		}
+		d.keyFilters[version] = &f
+	}
+	return nil
+}
+
+// updateKeyFilterLocked builds the key filter for a version that is being finalized. The passed
+// roots metadata must only contain finalized roots.
+//
+// Assumes metaUpdateLock is held when called.
+func (d *badgerNodeDB) updateKeyFilterLocked(ctx context.Context, tx *badger.Txn, version uint64, rootsMeta *rootsMetadata) error {
+	d.keyFiltersLock.RLock()
+	prevFilter := d.keyFilters[version-1]
+	d.keyFiltersLock.RUnlock()
+
+	f := newKeyFilter(d.keyFilterCapacity, d.keyFilterFalsePositiveRate)
+
+	// Roots derived from finalized roots of the previous version only need to have their nodes
+	// created in this version visited, as all other keys are already in the previous filter. The
+	// previous filter is not reused when it exceeds its capacity so that keys that have since
+	// been updated or removed do not accumulate indefinitely.
+	derivedRoots := make(map[hash.Hash]bool)
+	if version > 0 && prevFilter != nil && prevFilter.isCompatible(f) && prevFilter.Count < d.keyFilterCapacity {
+		prevRootsMeta, err := loadRootsMetadata(tx, version-1)
+		if err != nil {
+			return err
+		}
+		for rootHash, nextRoots := range prevRootsMeta.Roots {
+			if !prevFilter.hasRoot(rootHash) {
+				continue
+			}
+			for _, nextRoot := range nextRoots {
+				derivedRoots[nextRoot] = true
+			}
+		}
+		// Roots derived from other derived roots in the same version also qualify.
+		for updated := true; updated; {
+			updated = false
+			for rootHash, nextRoots := range rootsMeta.Roots {
+				if !derivedRoots[rootHash] {
+					continue
+				}
+				for _, nextRoot := range nextRoots {
+					if !derivedRoots[nextRoot] {
+						derivedRoots[nextRoot] = true
+						updated = true
+					}
+				}
+			}
+		}
+	}
+
+	rebuilt := true
+	for rootHash := range rootsMeta.Roots {
+		if derivedRoots[rootHash] {
+			f = prevFilter.derive()
+			rebuilt = false
+			break
+		}
+	}
+
+	for rootHash := range rootsMeta.Roots {
+		f.Roots = append(f.Roots, rootHash)
+		if rootHash.IsEmpty() {
+			continue
+		}
+
+		derived := derivedRoots[rootHash]
+		root := node.Root{Namespace: d.namespace, Version: version, Hash: rootHash}
+		err := api.Visit(ctx, d, root, func(ctx context.Context, n node.Node) bool {
+			if derived && n.GetCreatedVersion() < version {
+				return false
+			}
+			if leaf, ok := n.(*node.LeafNode); ok {
+				f.add(leaf.Key)
+			}
+			return true
+		})
+		if err != nil {
+			// Failing to build the filter should not prevent finalization as lookups can always
+			// fall back to traversing the tree.
+			d.logger.Warn("failed to build key filter, lookups will not use it",
+				"err", err,
+				"version", version,
+				"root", rootHash,
+			)
+			return nil
+		}
+	}
+	if rebuilt {
+		keyFilterRebuilds.Inc()
+	}
+
+	if err := tx.Set(keyFilterKeyFmt.Encode(version), cbor.Marshal(f)); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to save key filter: %w", err)
+	}
+	var staleVersion *uint64
+	if version >= keyFilterNumVersions {
+		v := version - keyFilterNumVersions
+		staleVersion = &v
+		if err := tx.Delete(keyFilterKeyFmt.Encode(v)); err != nil {
+			return fmt.Errorf("mkvs/badger: failed to remove stale key filter: %w", err)
+		}
+	}
+
+	// NOTE: The in-memory filters are updated before the transaction is committed. In case the
+	//       commit fails, the version remains unfinalized and the filter is overwritten once it
+	//       is finalized again.
+	d.keyFiltersLock.Lock()
+	d.keyFilters[version] = f
+	if staleVersion != nil {
+		delete(d.keyFilters, *staleVersion)
+	}
+	d.keyFiltersLock.Unlock()
+
+	return nil
+}
+
+// removeKeyFilterLocked removes the key filter for a pruned version.
+//
+// Assumes metaUpdateLock is held when called.
+func (d *badgerNodeDB) removeKeyFilterLocked(tx *badger.Txn, version uint64) error {
+	d.keyFiltersLock.Lock()
+	delete(d.keyFilters, version)
+	d.keyFiltersLock.Unlock()
+
+	return tx.Delete(keyFilterKeyFmt.Encode(version))
+}
+
+// Implements api.KeyFilter.
+func (d *badgerNodeDB) MayContainKey(root node.Root, key []byte) bool {
+	if d.keyFilterCapacity == 0 || !root.Namespace.Equal(&d.namespace) {
+		return true
+	}
+
+	d.keyFiltersLock.RLock()
+	defer d.keyFiltersLock.RUnlock()
+
+	f := d.keyFilters[root.Version]
+	if f == nil || !f.hasRoot(root.Hash) {
+		return true
+	}
+	if !f.mayContain(key) {
+		keyFilterQueries.With(labelKeyFilterNegative).Inc()
+		return false
+	}
+	keyFilterQueries.With(labelKeyFilterPositive).Inc()
+	return true
+}
+
+// Implements api.KeyFilter.
+func (d *badgerNodeDB) ReportKeyFilterFalsePositive(root node.Root) {
+	if d.keyFilterCapacity == 0 {
+		return
+	}
+
+	d.keyFiltersLock.RLock()
+	defer d.keyFiltersLock.RUnlock()
+
+	if f := d.keyFilters[root.Version]; f != nil && f.hasRoot(root.Hash) {
+		keyFilterFalsePositives.Inc()
+	}
+}
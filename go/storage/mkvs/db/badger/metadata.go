@@ -31,6 +31,10 @@ type metadata struct {
 	sync.RWMutex
 
 	value serializedMetadata
+
+	// snapshots is the number of open snapshots for each pinned version. It is not persisted as
+	// snapshots do not survive a restart.
+	snapshots map[uint64]uint64
 }
 
 func (m *metadata) getEarliestVersion() uint64 {
@@ -94,6 +98,37 @@ func (m *metadata) setMultipartVersion(tx *badger.Txn, version uint64) error {
 	return m.save(tx)
 }
 
+func (m *metadata) pinVersion(version uint64) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.snapshots == nil {
+		m.snapshots = make(map[uint64]uint64)
+	}
+	m.snapshots[version]++
+}
+
+func (m *metadata) unpinVersion(version uint64) {
+	m.Lock()
+	defer m.Unlock()
+
+	switch m.snapshots[version] {
+	case 0:
+		panic(fmt.Sprintf("mkvs/badger: unpinning version %d which is not pinned", version))
+	case 1:
+		delete(m.snapshots, version)
+	default:
+		m.snapshots[version]--
+	}
+}
+
+func (m *metadata) isVersionPinned(version uint64) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.snapshots[version] > 0
+}
+
 func (m *metadata) save(tx *badger.Txn) error {
 	return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(m.value))
 }
@@ -116,11 +151,36 @@ type rootsMetadata struct {
 
 	// Roots is the map of a root created in a version to any derived roots (in this or later versions).
 	Roots map[hash.Hash][]hash.Hash
+	// Annotations is the map of finalized roots in a version to their annotations.
+	Annotations map[hash.Hash][]byte
 
 	// version is the version this metadata is for.
 	version uint64
 }
 
+// legacyRootsMetadata is the roots metadata format used before root annotations were added.
+type legacyRootsMetadata struct {
+	_ struct{} `cbor:",toarray"`
+
+	Roots map[hash.Hash][]hash.Hash
+}
+
+// UnmarshalCBOR decodes a CBOR marshalled roots metadata, also accepting the legacy format.
+func (rm *rootsMetadata) UnmarshalCBOR(data []byte) error {
+	type rmRaw rootsMetadata
+	if err := cbor.Unmarshal(data, (*rmRaw)(rm)); err == nil {
+		return nil
+	}
+
+	var legacy legacyRootsMetadata
+	if err := cbor.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	rm.Roots = legacy.Roots
+	rm.Annotations = nil
+	return nil
+}
+
 // loadRootsMetadata loads the roots metadata for the given version from the database.
 func loadRootsMetadata(tx *badger.Txn, version uint64) (*rootsMetadata, error) {
 	rootsMeta := &rootsMetadata{version: version}
@@ -0,0 +1,141 @@
+package badger
+
+import (
+	"errors"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// nodeStore is the store holding the nodes of a node database.
+type nodeStore interface {
+	// newReader creates a new reader for looking up nodes at the given version.
+	newReader(version uint64) nodeReader
+
+	// newWriter creates a new writer for updating nodes at the given version.
+	newWriter(version uint64) nodeWriter
+
+	// prune removes any nodes that became obsolete in versions up to and including the version
+	// following the given pruned version.
+	prune(version uint64) error
+
+	// sync syncs the node store to disk in case it is separate from the node database.
+	sync() error
+
+	// close releases the node store.
+	close()
+}
+
+// nodeReader looks up nodes in a node store.
+type nodeReader interface {
+	// get returns the item holding the serialized node with the given hash. In case the node
+	// does not exist, badger.ErrKeyNotFound is returned.
+	get(h *hash.Hash) (*badger.Item, error)
+
+	// has returns true iff the node with the given hash has been stored by the node database.
+	has(h *hash.Hash) (bool, error)
+
+	// discard releases the reader.
+	discard()
+}
+
+// nodeWriter performs batched updates of nodes in a node store.
+type nodeWriter interface {
+	// put stores the given serialized node.
+	put(h *hash.Hash, data []byte) error
+
+	// remove removes the node with the given hash.
+	remove(h *hash.Hash) error
+
+	// removeObsolete removes the node with the given hash, which was removed by an update in
+	// the writer's version. The node must remain available in earlier versions until they are
+	// pruned.
+	removeObsolete(h *hash.Hash) error
+
+	// flush writes all pending updates.
+	flush() error
+
+	// cancel discards all pending updates.
+	cancel()
+}
+
+// localNodeStore is a node store that keeps nodes in the node database itself, at the timestamp
+// of the version in which they were updated.
+type localNodeStore struct {
+	db *badger.DB
+}
+
+func (s *localNodeStore) newReader(version uint64) nodeReader {
+	return &localNodeReader{
+		tx: s.db.NewTransactionAt(versionToTs(version), false),
+	}
+}
+
+func (s *localNodeStore) newWriter(version uint64) nodeWriter {
+	return &localNodeWriter{
+		bat: s.db.NewWriteBatchAt(versionToTs(version)),
+	}
+}
+
+func (s *localNodeStore) prune(version uint64) error {
+	// Obsolete nodes are removed at the timestamp of the version in which they became obsolete
+	// so they are discarded together with the pruned versions.
+	return nil
+}
+
+func (s *localNodeStore) sync() error {
+	// Nodes are synced together with the node database.
+	return nil
+}
+
+func (s *localNodeStore) close() {
+}
+
+type localNodeReader struct {
+	tx *badger.Txn
+}
+
+func (r *localNodeReader) get(h *hash.Hash) (*badger.Item, error) {
+	return r.tx.Get(nodeKeyFmt.Encode(h))
+}
+
+func (r *localNodeReader) has(h *hash.Hash) (bool, error) {
+	_, err := r.get(h)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, badger.ErrKeyNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *localNodeReader) discard() {
+	r.tx.Discard()
+}
+
+type localNodeWriter struct {
+	bat *badger.WriteBatch
+}
+
+func (w *localNodeWriter) put(h *hash.Hash, data []byte) error {
+	return w.bat.Set(nodeKeyFmt.Encode(h), data)
+}
+
+func (w *localNodeWriter) remove(h *hash.Hash) error {
+	return w.bat.Delete(nodeKeyFmt.Encode(h))
+}
+
+func (w *localNodeWriter) removeObsolete(h *hash.Hash) error {
+	return w.remove(h)
+}
+
+func (w *localNodeWriter) flush() error {
+	return w.bat.Flush()
+}
+
+func (w *localNodeWriter) cancel() {
+	w.bat.Cancel()
+}
@@ -0,0 +1,413 @@
+package badger
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+const sharedDBVersion = 1
+
+var (
+	// sharedNodeKeyFmt is the key format for nodes in a shared node database (node hash).
+	//
+	// Value is serialized node.
+	sharedNodeKeyFmt = keyformat.New(0x00, &hash.Hash{})
+	// sharedNodeRefKeyFmt is the key format for node references in a shared node database. The
+	// key format is (node hash, namespace) and a node is kept for as long as any namespace
+	// references it.
+	//
+	// Value is empty.
+	sharedNodeRefKeyFmt = keyformat.New(0x01, &hash.Hash{}, &common.Namespace{})
+	// sharedMetadataKeyFmt is the key format for shared node database metadata.
+	//
+	// Value is CBOR-serialized sharedMetadata.
+	sharedMetadataKeyFmt = keyformat.New(0x02)
+)
+
+var (
+	sharedNodeDBsLock sync.Mutex
+	sharedNodeDBs     = make(map[string]*sharedNodeDB)
+)
+
+// sharedMetadata is the on-disk serialized shared node database metadata.
+type sharedMetadata struct {
+	// Version is the database schema version.
+	Version uint64 `json:"version"`
+}
+
+// sharedNodeDB is a content-addressed node database shared by the node databases of multiple
+// namespaces. As nodes are addressed by their hash, identical nodes are only stored once.
+//
+// All data is stored at the metadata timestamp as the versions of different namespaces are
+// unrelated. A node is removed once no namespace references it anymore.
+type sharedNodeDB struct {
+	// Lock serializes reference updates.
+	sync.Mutex
+
+	logger *logging.Logger
+
+	path       string
+	readOnly   bool
+	memoryOnly bool
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+
+	// refs is the number of open node databases using the shared node database. Guarded by
+	// sharedNodeDBsLock.
+	refs int
+}
+
+// openSharedNodeDB opens the shared node database configured in the given node database
+// configuration. In case the shared node database is already open, it is reused.
+func openSharedNodeDB(cfg *api.Config) (*sharedNodeDB, error) {
+	sharedNodeDBsLock.Lock()
+	defer sharedNodeDBsLock.Unlock()
+
+	if s := sharedNodeDBs[cfg.SharedNodeDB]; s != nil {
+		if s.readOnly != cfg.ReadOnly || s.memoryOnly != cfg.MemoryOnly {
+			return nil, fmt.Errorf("mkvs/badger: shared node database already open with incompatible configuration")
+		}
+		s.refs++
+		return s, nil
+	}
+
+	s := &sharedNodeDB{
+		logger:     logging.GetLogger("mkvs/db/badger/shared"),
+		path:       cfg.SharedNodeDB,
+		readOnly:   cfg.ReadOnly,
+		memoryOnly: cfg.MemoryOnly,
+		refs:       1,
+	}
+
+	opts := badger.DefaultOptions(cfg.SharedNodeDB)
+	opts = opts.WithLogger(cmnBadger.NewLogAdapter(s.logger))
+	// With fsync batching, writes are synced explicitly by the node databases.
+	opts = opts.WithSyncWrites(!cfg.NoFsync && !cfg.FsyncBatch.IsEnabled())
+	opts = opts.WithTruncate(true)
+	opts = opts.WithCompression(options.Snappy)
+	opts = opts.WithBlockCacheSize(cfg.MaxCacheSize)
+	opts = opts.WithReadOnly(cfg.ReadOnly)
+	opts = opts.WithDetectConflicts(false)
+
+	if cfg.MemoryOnly {
+		s.logger.Warn("using memory-only mode, data will not be persisted")
+		opts = opts.WithInMemory(true).WithDir("").WithValueDir("")
+	}
+
+	var err error
+	if s.db, err = badger.OpenManaged(opts); err != nil {
+		return nil, fmt.Errorf("mkvs/badger: failed to open shared node database: %w", err)
+	}
+
+	// Make sure that we can discard any deleted nodes and references.
+	s.db.SetDiscardTs(tsMetadata)
+
+	if err = s.load(); err != nil {
+		_ = s.db.Close()
+		return nil, fmt.Errorf("mkvs/badger: failed to load shared node database metadata: %w", err)
+	}
+
+	s.gc = cmnBadger.NewGCWorkerWithConfig(s.logger, s.db, gcConfig(&cfg.GC))
+	sharedNodeDBs[s.path] = s
+
+	return s, nil
+}
+
+func (s *sharedNodeDB) load() error {
+	tx := s.db.NewTransactionAt(tsMetadata, true)
+	defer tx.Discard()
+
+	var meta sharedMetadata
+	item, err := tx.Get(sharedMetadataKeyFmt.Encode())
+	switch err {
+	case nil:
+		if err = item.Value(func(data []byte) error {
+			return cbor.UnmarshalTrusted(data, &meta)
+		}); err != nil {
+			return err
+		}
+		if meta.Version != sharedDBVersion {
+			return fmt.Errorf("incompatible database version (expected: %d got: %d)",
+				sharedDBVersion,
+				meta.Version,
+			)
+		}
+		return nil
+	case badger.ErrKeyNotFound:
+	default:
+		return err
+	}
+
+	if s.readOnly {
+		return nil
+	}
+
+	// No metadata exists, create some.
+	meta.Version = sharedDBVersion
+	if err = tx.Set(sharedMetadataKeyFmt.Encode(), cbor.Marshal(meta)); err != nil {
+		return err
+	}
+	return tx.CommitAt(tsMetadata, nil)
+}
+
+// release releases a reference to the shared node database and closes it once it is no longer
+// used by any node database.
+func (s *sharedNodeDB) release() {
+	sharedNodeDBsLock.Lock()
+	defer sharedNodeDBsLock.Unlock()
+
+	s.refs--
+	if s.refs > 0 {
+		return
+	}
+	delete(sharedNodeDBs, s.path)
+
+	s.gc.Close()
+	if err := s.db.Close(); err != nil {
+		s.logger.Error("close returned error",
+			"err", err,
+		)
+	}
+}
+
+// hasReferences returns true iff the node with the given hash is referenced by any namespace.
+func (s *sharedNodeDB) hasReferences(tx *badger.Txn, h *hash.Hash) bool {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = sharedNodeRefKeyFmt.Encode(h)
+	opts.PrefetchValues = false
+	it := tx.NewIterator(opts)
+	defer it.Close()
+
+	it.Rewind()
+	return it.Valid()
+}
+
+// newStore creates a node store for the given namespace, backed by the shared node database. The
+// given node database of the namespace is used to track obsolete nodes.
+func (s *sharedNodeDB) newStore(namespace common.Namespace, db *badger.DB) nodeStore {
+	return &sharedNodeStore{
+		shared:    s,
+		namespace: namespace,
+		db:        db,
+	}
+}
+
+// sharedNodeStore is a node store for a single namespace, backed by a shared node database.
+type sharedNodeStore struct {
+	shared    *sharedNodeDB
+	namespace common.Namespace
+
+	// db is the node database of the namespace.
+	db *badger.DB
+}
+
+func (s *sharedNodeStore) newReader(version uint64) nodeReader {
+	return &sharedNodeReader{
+		store: s,
+		tx:    s.shared.db.NewTransactionAt(tsMetadata, false),
+	}
+}
+
+func (s *sharedNodeStore) newWriter(version uint64) nodeWriter {
+	return &sharedNodeWriter{
+		store:   s,
+		version: version,
+	}
+}
+
+func (s *sharedNodeStore) prune(version uint64) error {
+	tx := s.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+	metaBatch := s.db.NewWriteBatchAt(tsMetadata)
+	defer metaBatch.Cancel()
+
+	// Nodes that became obsolete in any version up to and including the version following the
+	// pruned version are no longer needed by this namespace.
+	w := s.newWriter(version + 1)
+	defer w.cancel()
+
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: obsoleteNodeKeyFmt.Encode()})
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		var (
+			obsoleteVersion uint64
+			h               hash.Hash
+		)
+		if !obsoleteNodeKeyFmt.Decode(it.Item().Key(), &obsoleteVersion, &h) {
+			panic("mkvs/badger: bad iterator")
+		}
+		if obsoleteVersion > version+1 {
+			break
+		}
+
+		if err := w.remove(&h); err != nil {
+			return err
+		}
+		if err := metaBatch.Delete(it.Item().KeyCopy(nil)); err != nil {
+			return err
+		}
+	}
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return metaBatch.Flush()
+}
+
+func (s *sharedNodeStore) sync() error {
+	return s.shared.db.Sync()
+}
+
+func (s *sharedNodeStore) close() {
+	s.shared.release()
+}
+
+type sharedNodeReader struct {
+	store *sharedNodeStore
+	tx    *badger.Txn
+}
+
+func (r *sharedNodeReader) get(h *hash.Hash) (*badger.Item, error) {
+	return r.tx.Get(sharedNodeKeyFmt.Encode(h))
+}
+
+func (r *sharedNodeReader) has(h *hash.Hash) (bool, error) {
+	// A node has only been stored by this namespace if it is referenced by it.
+	_, err := r.tx.Get(sharedNodeRefKeyFmt.Encode(h, &r.store.namespace))
+	switch err {
+	case nil:
+		return true, nil
+	case badger.ErrKeyNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *sharedNodeReader) discard() {
+	r.tx.Discard()
+}
+
+type sharedNodeOp struct {
+	hash    hash.Hash
+	data    []byte
+	removed bool
+}
+
+// sharedNodeWriter buffers node updates and applies them to the shared node database on flush,
+// so that reference updates of different namespaces are serialized.
+type sharedNodeWriter struct {
+	store   *sharedNodeStore
+	version uint64
+
+	ops      []sharedNodeOp
+	obsolete []hash.Hash
+}
+
+func (w *sharedNodeWriter) put(h *hash.Hash, data []byte) error {
+	w.ops = append(w.ops, sharedNodeOp{hash: *h, data: data})
+	return nil
+}
+
+func (w *sharedNodeWriter) remove(h *hash.Hash) error {
+	w.ops = append(w.ops, sharedNodeOp{hash: *h, removed: true})
+	return nil
+}
+
+func (w *sharedNodeWriter) removeObsolete(h *hash.Hash) error {
+	// Obsolete nodes are still referenced by earlier versions, so only record them in the node
+	// database of the namespace until the earlier versions are pruned.
+	w.obsolete = append(w.obsolete, *h)
+	return nil
+}
+
+func (w *sharedNodeWriter) flush() error {
+	if len(w.obsolete) > 0 {
+		metaBatch := w.store.db.NewWriteBatchAt(tsMetadata)
+		defer metaBatch.Cancel()
+
+		for i := range w.obsolete {
+			if err := metaBatch.Set(obsoleteNodeKeyFmt.Encode(w.version, &w.obsolete[i]), []byte{}); err != nil {
+				return err
+			}
+		}
+		if err := metaBatch.Flush(); err != nil {
+			return err
+		}
+		w.obsolete = nil
+	}
+	if len(w.ops) == 0 {
+		return nil
+	}
+
+	s := w.store.shared
+	s.Lock()
+	defer s.Unlock()
+
+	// Update the node references of this namespace.
+	batch := s.db.NewWriteBatchAt(tsMetadata)
+	defer batch.Cancel()
+
+	removed := make(map[hash.Hash]bool)
+	for _, op := range w.ops {
+		refKey := sharedNodeRefKeyFmt.Encode(&op.hash, &w.store.namespace)
+		if op.removed {
+			if err := batch.Delete(refKey); err != nil {
+				return err
+			}
+			removed[op.hash] = true
+			continue
+		}
+
+		if err := batch.Set(sharedNodeKeyFmt.Encode(&op.hash), op.data); err != nil {
+			return err
+		}
+		if err := batch.Set(refKey, []byte{}); err != nil {
+			return err
+		}
+		delete(removed, op.hash)
+	}
+	if err := batch.Flush(); err != nil {
+		return err
+	}
+	w.ops = nil
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// Remove any nodes that are no longer referenced by any namespace.
+	tx := s.db.NewTransactionAt(tsMetadata, false)
+	defer tx.Discard()
+	nodeBatch := s.db.NewWriteBatchAt(tsMetadata)
+	defer nodeBatch.Cancel()
+
+	for h := range removed {
+		nodeHash := h
+		if s.hasReferences(tx, &nodeHash) {
+			continue
+		}
+		if err := nodeBatch.Delete(sharedNodeKeyFmt.Encode(&nodeHash)); err != nil {
+			return err
+		}
+	}
+	return nodeBatch.Flush()
+}
+
+func (w *sharedNodeWriter) cancel() {
+	w.ops = nil
+	w.obsolete = nil
+}
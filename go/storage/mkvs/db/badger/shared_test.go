@@ -0,0 +1,127 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func countSharedKeys(require *require.Assertions, shared *sharedNodeDB, prefix []byte) int {
+	var count int
+	err := shared.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+		}
+		return nil
+	})
+	require.NoError(err, "View()")
+	return count
+}
+
+func countTreeNodes(ctx context.Context, require *require.Assertions, ndb api.NodeDB, root node.Root) int {
+	var count int
+	err := api.Visit(ctx, ndb, root, func(ctx context.Context, n node.Node) bool {
+		count++
+		return true
+	})
+	require.NoError(err, "Visit()")
+	return count
+}
+
+func commitVersion(ctx context.Context, require *require.Assertions, ndb api.NodeDB, root node.Root, version uint64, value []byte) node.Root {
+	tree := mkvs.NewWithRoot(nil, ndb, root)
+	defer tree.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		err := tree.Insert(ctx, []byte(key), []byte(key))
+		require.NoError(err, "Insert()")
+	}
+	err := tree.Insert(ctx, []byte("value"), value)
+	require.NoError(err, "Insert()")
+
+	_, rootHash, err := tree.Commit(ctx, root.Namespace, version)
+	require.NoError(err, "Commit()")
+	err = ndb.Finalize(ctx, version, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize()")
+
+	return node.Root{Namespace: root.Namespace, Version: version, Hash: rootHash}
+}
+
+func TestSharedNodeDB(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	namespaces := []common.Namespace{
+		common.NewTestNamespaceFromSeed([]byte("badger shared node db test ns"), 0),
+		common.NewTestNamespaceFromSeed([]byte("badger shared node db test ns 2"), 0),
+	}
+
+	var ndbs []api.NodeDB
+	for _, ns := range namespaces {
+		cfg := *dbCfg
+		cfg.Namespace = ns
+		cfg.SharedNodeDB = "shared node db test"
+		ndb, err := New(&cfg)
+		require.NoError(err, "New()")
+		defer ndb.Close()
+
+		ndbs = append(ndbs, ndb)
+	}
+	shared := ndbs[0].(*badgerNodeDB).nodes.(*sharedNodeStore).shared
+	require.Equal(shared, ndbs[1].(*badgerNodeDB).nodes.(*sharedNodeStore).shared, "shared node database should be reused")
+
+	// Commit identical state in both namespaces.
+	var roots0, roots1 []node.Root
+	for i, ndb := range ndbs {
+		root := node.Root{Namespace: namespaces[i]}
+		root.Hash.Empty()
+		roots0 = append(roots0, commitVersion(ctx, require, ndb, root, 0, testValues[0]))
+	}
+	require.EqualValues(roots0[0].Hash, roots0[1].Hash, "roots should be identical")
+
+	numNodes := countTreeNodes(ctx, require, ndbs[0], roots0[0])
+	require.Equal(numNodes, countSharedKeys(require, shared, sharedNodeKeyFmt.Encode()), "identical nodes should only be stored once")
+	require.Equal(2*numNodes, countSharedKeys(require, shared, sharedNodeRefKeyFmt.Encode()), "nodes should be referenced by both namespaces")
+
+	// Update the state in both namespaces and prune the first version in the first namespace.
+	for i, ndb := range ndbs {
+		roots1 = append(roots1, commitVersion(ctx, require, ndb, roots0[i], 1, testValues[1]))
+	}
+	require.Equal(numNodes, countTreeNodes(ctx, require, ndbs[0], roots0[0]), "earlier version should be available until pruned")
+	err := ndbs[0].Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+
+	// The pruned version should still be available in the second namespace.
+	require.Equal(numNodes, countTreeNodes(ctx, require, ndbs[1], roots0[1]), "version should not be pruned in other namespaces")
+
+	// Pruning in the second namespace should remove any nodes that are no longer referenced.
+	err = ndbs[1].Prune(ctx, 0)
+	require.NoError(err, "Prune()")
+
+	numNodes = countTreeNodes(ctx, require, ndbs[0], roots1[0])
+	require.Equal(numNodes, countSharedKeys(require, shared, sharedNodeKeyFmt.Encode()), "unreferenced nodes should be removed")
+	require.Equal(2*numNodes, countSharedKeys(require, shared, sharedNodeRefKeyFmt.Encode()), "nodes should be referenced by both namespaces")
+	require.Equal(numNodes, countTreeNodes(ctx, require, ndbs[1], roots1[1]), "latest version should be available")
+
+	// Nodes should not be stored in the per-namespace node databases.
+	err = ndbs[0].(*badgerNodeDB).db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			require.False(bytes.HasPrefix(it.Item().Key(), nodePrefix), "per-namespace node database should not contain nodes")
+		}
+		return nil
+	})
+	require.NoError(err, "View()")
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
@@ -85,3 +86,81 @@ func TestHashedWriteLog(t *testing.T) {
 	}
 	require.Equal(t, i, len(wl))
 }
+
+func TestHashedWriteLogStream(t *testing.T) {
+	wl := makeWriteLog()
+	wla := make(writelog.Annotations, len(wl))
+	hashes := make(map[hash.Hash]*node.Pointer)
+	for i := 0; i < len(wl); i++ {
+		// Make every other entry a removal.
+		if i%2 == 1 {
+			wl[i].Value = nil
+			continue
+		}
+
+		h := hash.NewFromBytes(wl[i].Value)
+		ptr := &node.Pointer{
+			Clean: true,
+			Hash:  h,
+			Node: &node.LeafNode{
+				Clean: true,
+				Hash:  h,
+				Key:   wl[i].Key,
+				Value: wl[i].Value,
+			},
+		}
+		wla[i] = writelog.LogEntryAnnotation{
+			InsertedNode: ptr,
+		}
+		hashes[ptr.Hash] = ptr
+	}
+
+	// Split the write log into multiple serialized logs, including an empty one.
+	encoded := [][]byte{
+		cbor.Marshal(api.MakeHashedDBWriteLog(wl[:30], wla[:30])),
+		cbor.Marshal(api.MakeHashedDBWriteLog(nil, nil)),
+		cbor.Marshal(api.MakeHashedDBWriteLog(wl[30:], wla[30:])),
+	}
+
+	var (
+		index  int
+		closed bool
+	)
+	it, err := api.StreamHashedDBWriteLogs(context.Background(),
+		func() (node.Root, *api.HashedDBWriteLogDecoder, error) {
+			if index >= len(encoded) {
+				return node.Root{}, nil, nil
+			}
+			dec, derr := api.NewHashedDBWriteLogDecoder(encoded[index])
+			index++
+			return node.Root{}, dec, derr
+		},
+		func(root node.Root, h hash.Hash) (*node.LeafNode, error) {
+			return hashes[h].Node.(*node.LeafNode), nil
+		},
+		func() { closed = true },
+		1,
+	)
+	require.NoError(t, err, "StreamHashedDBWriteLogs")
+
+	i := 0
+	for {
+		more, err := it.Next()
+		require.NoError(t, err, "it.Next()")
+		if !more {
+			break
+		}
+		entry, err := it.Value()
+		require.NoError(t, err, "it.Value()")
+		require.Equal(t, wl[i], entry)
+		i++
+	}
+	require.Equal(t, len(wl), i)
+	require.True(t, closed, "closer should be called after iteration")
+
+	// Malformed write logs should be rejected.
+	_, err = api.NewHashedDBWriteLogDecoder([]byte{0xa0})
+	require.Error(t, err, "NewHashedDBWriteLogDecoder should fail for non-arrays")
+	_, err = api.NewHashedDBWriteLogDecoder([]byte{0x98})
+	require.Error(t, err, "NewHashedDBWriteLogDecoder should fail for truncated headers")
+}
@@ -47,7 +47,7 @@ func (t *tree) SyncIterate(ctx context.Context, request *syncer.IterateRequest)
 	}
 
 	// Retrieve the proof for the items iterated over.
-	proof, err := it.GetProof()
+	proof, err := it.GetProofBuilder().BuildVersion(ctx, request.ProofVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +64,9 @@ func (t *tree) newFetcherSyncIterate(key node.Key, prefetch uint16) readSyncFetc
 				Root:     t.cache.syncRoot,
 				Position: ptr.Hash,
 			},
-			Key:      key,
-			Prefetch: prefetch,
+			Key:          key,
+			Prefetch:     prefetch,
+			ProofVersion: syncer.ProofVersionLatest,
 		})
 		if err != nil {
 			return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
@@ -27,7 +28,20 @@ func (t *tree) Get(ctx context.Context, key []byte) ([]byte, error) {
 	// Remember where the path from root to target node ends (will end).
 	t.cache.markPosition()
 
-	return t.doGet(ctx, t.cache.pendingRoot, 0, key, doGetOptions{}, false)
+	// If the tree has no local modifications and the node database maintains a key filter, use
+	// it to avoid traversing the tree for keys that are definitely not there.
+	kf, ok := t.cache.db.(db.KeyFilter)
+	if !ok || !t.cache.pendingRoot.IsClean() {
+		return t.doGet(ctx, t.cache.pendingRoot, 0, key, doGetOptions{}, false)
+	}
+	if !kf.MayContainKey(t.cache.syncRoot, key) {
+		return nil, nil
+	}
+	value, err := t.doGet(ctx, t.cache.pendingRoot, 0, key, doGetOptions{}, false)
+	if err == nil && value == nil {
+		kf.ReportKeyFilterFalsePositive(t.cache.syncRoot)
+	}
+	return value, err
 }
 
 // Implements syncer.ReadSyncer.
@@ -56,7 +70,7 @@ func (t *tree) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer
 	if _, err := t.doGet(ctx, t.cache.pendingRoot, 0, request.Key, opts, false); err != nil {
 		return nil, err
 	}
-	proof, err := pb.Build(ctx)
+	proof, err := pb.BuildVersion(ctx, request.ProofVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +89,7 @@ func (t *tree) newFetcherSyncGet(key node.Key, includeSiblings bool) readSyncFet
 			},
 			Key:             key,
 			IncludeSiblings: includeSiblings,
+			ProofVersion:    syncer.ProofVersionLatest,
 		})
 		if err != nil {
 			return nil, err
@@ -0,0 +1,187 @@
+package node
+
+// arenaChunkSize is the number of structures allocated at once when an arena
+// has no released structures available for reuse.
+const arenaChunkSize = 256
+
+// ArenaStats are the node arena allocation statistics.
+type ArenaStats struct {
+	// Allocated is the number of nodes and pointers handed out by the arena.
+	Allocated uint64 `json:"allocated"`
+	// Reused is the number of allocated nodes that reused a released node.
+	Reused uint64 `json:"reused"`
+	// Released is the number of nodes released back into the arena.
+	Released uint64 `json:"released"`
+}
+
+// Arena is an allocator for deserialized nodes and pointers.
+//
+// Nodes and pointers are allocated in chunks to reduce the number of heap
+// allocations (and thus GC pressure) when deserializing many nodes, e.g.,
+// during sync. Nodes released back into the arena (e.g., on cache eviction)
+// are reused for subsequent allocations. Released nodes only become available
+// for reuse after Reclaim has been called, which must only happen when no
+// references to any released nodes remain.
+//
+// Pointers are never reused as they may be referenced from outside the tree
+// (e.g., by iterators) even after the node they point to has been released.
+//
+// A nil arena is valid and allocates everything directly on the heap.
+//
+// The arena is not safe for concurrent use.
+type Arena struct {
+	internalNodes []InternalNode
+	leafNodes     []LeafNode
+	pointers      []Pointer
+
+	freeInternalNodes    []*InternalNode
+	freeLeafNodes        []*LeafNode
+	pendingInternalNodes []*InternalNode
+	pendingLeafNodes     []*LeafNode
+
+	stats ArenaStats
+}
+
+// NewArena creates a new node arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// Stats returns the arena allocation statistics.
+func (a *Arena) Stats() ArenaStats {
+	if a == nil {
+		return ArenaStats{}
+	}
+	return a.stats
+}
+
+// NewInternalNode allocates a new empty internal node.
+func (a *Arena) NewInternalNode() *InternalNode {
+	if a == nil {
+		return &InternalNode{}
+	}
+
+	a.stats.Allocated++
+	if l := len(a.freeInternalNodes); l > 0 {
+		n := a.freeInternalNodes[l-1]
+		a.freeInternalNodes[l-1] = nil
+		a.freeInternalNodes = a.freeInternalNodes[:l-1]
+		a.stats.Reused++
+		return n
+	}
+	if len(a.internalNodes) == 0 {
+		a.internalNodes = make([]InternalNode, arenaChunkSize)
+	}
+	n := &a.internalNodes[0]
+	a.internalNodes = a.internalNodes[1:]
+	return n
+}
+
+// NewLeafNode allocates a new empty leaf node.
+func (a *Arena) NewLeafNode() *LeafNode {
+	if a == nil {
+		return &LeafNode{}
+	}
+
+	a.stats.Allocated++
+	if l := len(a.freeLeafNodes); l > 0 {
+		n := a.freeLeafNodes[l-1]
+		a.freeLeafNodes[l-1] = nil
+		a.freeLeafNodes = a.freeLeafNodes[:l-1]
+		a.stats.Reused++
+		return n
+	}
+	if len(a.leafNodes) == 0 {
+		a.leafNodes = make([]LeafNode, arenaChunkSize)
+	}
+	n := &a.leafNodes[0]
+	a.leafNodes = a.leafNodes[1:]
+	return n
+}
+
+// NewPointer allocates a new empty pointer.
+func (a *Arena) NewPointer() *Pointer {
+	if a == nil {
+		return &Pointer{}
+	}
+
+	a.stats.Allocated++
+	if len(a.pointers) == 0 {
+		a.pointers = make([]Pointer, arenaChunkSize)
+	}
+	p := &a.pointers[0]
+	a.pointers = a.pointers[1:]
+	return p
+}
+
+// Release releases the given node back into the arena. The node is left intact
+// until the next call to Reclaim, after which it is reset and may be reused.
+//
+// The caller must ensure that the node is not released more than once.
+func (a *Arena) Release(n Node) {
+	if a == nil {
+		return
+	}
+
+	switch n := n.(type) {
+	case *InternalNode:
+		a.pendingInternalNodes = append(a.pendingInternalNodes, n)
+	case *LeafNode:
+		a.pendingLeafNodes = append(a.pendingLeafNodes, n)
+	default:
+		return
+	}
+	a.stats.Released++
+}
+
+// Reclaim resets all previously released nodes and makes them available for
+// reuse.
+//
+// The caller must ensure that no references to released nodes remain.
+func (a *Arena) Reclaim() {
+	if a == nil {
+		return
+	}
+
+	for i, n := range a.pendingInternalNodes {
+		*n = InternalNode{}
+		a.freeInternalNodes = append(a.freeInternalNodes, n)
+		a.pendingInternalNodes[i] = nil
+	}
+	for i, n := range a.pendingLeafNodes {
+		*n = LeafNode{}
+		a.freeLeafNodes = append(a.freeLeafNodes, n)
+		a.pendingLeafNodes[i] = nil
+	}
+	a.pendingInternalNodes = a.pendingInternalNodes[:0]
+	a.pendingLeafNodes = a.pendingLeafNodes[:0]
+}
+
+// UnmarshalBinary unmarshals a node of arbitrary type, allocating it from the
+// arena.
+func (a *Arena) UnmarshalBinary(data []byte) (Node, error) {
+	if len(data) <= 1 {
+		return nil, ErrMalformedNode
+	}
+
+	// Nodes can be either Internal or Leaf nodes.
+	// Check the first byte and deserialize appropriately.
+	switch data[0] {
+	case PrefixLeafNode:
+		leaf := a.NewLeafNode()
+		if _, err := leaf.SizedUnmarshalBinary(data); err != nil {
+			a.Release(leaf)
+			return nil, err
+		}
+		return leaf, nil
+	case PrefixInternalNode:
+		inode := a.NewInternalNode()
+		if _, err := inode.sizedUnmarshalBinary(a, data); err != nil {
+			a.Release(inode)
+			return nil, err
+		}
+		return inode, nil
+	default:
+		return nil, ErrMalformedNode
+	}
+}
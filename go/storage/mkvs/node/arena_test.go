@@ -0,0 +1,59 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+func TestArena(t *testing.T) {
+	require := require.New(t)
+
+	leafNode := &LeafNode{
+		Key:   []byte("a golden key"),
+		Value: []byte("value"),
+	}
+	leafNode.UpdateHash()
+	intNode := &InternalNode{
+		Version:        0xDEADBEEF,
+		Label:          Key("abc"),
+		LabelBitLength: Depth(24),
+		LeafNode:       &Pointer{Clean: true, Node: leafNode, Hash: leafNode.Hash},
+		Left:           &Pointer{Clean: true, Hash: hash.NewFromBytes([]byte("left"))},
+		Right:          &Pointer{Clean: true, Hash: hash.NewFromBytes([]byte("right"))},
+	}
+	intNode.UpdateHash()
+	rawIntNode, err := intNode.MarshalBinary()
+	require.NoError(err, "MarshalBinary")
+
+	a := NewArena()
+	n, err := a.UnmarshalBinary(rawIntNode)
+	require.NoError(err, "UnmarshalBinary")
+	require.True(intNode.Equal(n), "node allocated from arena should be equal")
+	require.EqualValues(5, a.Stats().Allocated, "internal node, leaf node and three pointers should be allocated")
+
+	// Released nodes should only be reused after they have been reclaimed.
+	a.Release(n)
+	require.True(intNode.Equal(n), "released node should remain intact until reclaimed")
+	require.NotSame(n, a.NewInternalNode(), "released node should not be reused before reclaim")
+
+	a.Reclaim()
+	reused := a.NewInternalNode()
+	require.Same(n, reused, "released node should be reused after reclaim")
+	require.Equal(&InternalNode{}, reused, "reused node should be reset")
+	require.EqualValues(1, a.Stats().Reused)
+	require.EqualValues(1, a.Stats().Released)
+
+	_, err = a.UnmarshalBinary([]byte{PrefixInternalNode, 0x01})
+	require.Error(err, "UnmarshalBinary should fail on malformed nodes")
+
+	// A nil arena should allocate directly on the heap.
+	var nilArena *Arena
+	n, err = nilArena.UnmarshalBinary(rawIntNode)
+	require.NoError(err, "UnmarshalBinary")
+	require.True(intNode.Equal(n), "node allocated without an arena should be equal")
+	nilArena.Release(n)
+	nilArena.Reclaim()
+}
@@ -417,6 +417,10 @@ func (n *InternalNode) UnmarshalBinary(data []byte) error {
 
 // SizedUnmarshalBinary decodes a binary marshaled internal node.
 func (n *InternalNode) SizedUnmarshalBinary(data []byte) (int, error) {
+	return n.sizedUnmarshalBinary(nil, data)
+}
+
+func (n *InternalNode) sizedUnmarshalBinary(a *Arena, data []byte) (int, error) {
 	if len(data) < 1+VersionSize+DepthSize+1 {
 		return 0, ErrMalformedNode
 	}
@@ -450,13 +454,17 @@ func (n *InternalNode) SizedUnmarshalBinary(data []byte) (int, error) {
 		n.LeafNode = nil
 		pos++
 	} else {
-		leafNode := LeafNode{}
+		leafNode := a.NewLeafNode()
 		var leafNodeBinarySize int
 		var err error
 		if leafNodeBinarySize, err = leafNode.SizedUnmarshalBinary(data[pos:]); err != nil {
+			a.Release(leafNode)
 			return 0, fmt.Errorf("mkvs: failed to unmarshal leaf node: %w", err)
 		}
-		n.LeafNode = &Pointer{Clean: true, Hash: leafNode.Hash, Node: &leafNode}
+		n.LeafNode = a.NewPointer()
+		n.LeafNode.Clean = true
+		n.LeafNode.Hash = leafNode.Hash
+		n.LeafNode.Node = leafNode
 		pos += leafNodeBinarySize
 	}
 
@@ -476,13 +484,17 @@ func (n *InternalNode) SizedUnmarshalBinary(data []byte) (int, error) {
 		if leftHash.IsEmpty() {
 			n.Left = nil
 		} else {
-			n.Left = &Pointer{Clean: true, Hash: leftHash}
+			n.Left = a.NewPointer()
+			n.Left.Clean = true
+			n.Left.Hash = leftHash
 		}
 
 		if rightHash.IsEmpty() {
 			n.Right = nil
 		} else {
-			n.Right = &Pointer{Clean: true, Hash: rightHash}
+			n.Right = a.NewPointer()
+			n.Right.Clean = true
+			n.Right.Hash = rightHash
 		}
 
 		n.UpdateHash()
@@ -670,28 +682,6 @@ func (n *LeafNode) Equal(other Node) bool {
 
 // UnmarshalBinary unmarshals a node of arbitrary type.
 func UnmarshalBinary(bytes []byte) (Node, error) {
-	// Nodes can be either Internal or Leaf nodes.
-	// Check the first byte and deserialize appropriately.
-	var node Node
-	if len(bytes) > 1 {
-		switch bytes[0] {
-		case PrefixLeafNode:
-			var leaf LeafNode
-			if err := leaf.UnmarshalBinary(bytes); err != nil {
-				return nil, err
-			}
-			node = Node(&leaf)
-		case PrefixInternalNode:
-			var inode InternalNode
-			if err := inode.UnmarshalBinary(bytes); err != nil {
-				return nil, err
-			}
-			node = Node(&inode)
-		default:
-			return nil, ErrMalformedNode
-		}
-	} else {
-		return nil, ErrMalformedNode
-	}
-	return node, nil
+	var a *Arena
+	return a.UnmarshalBinary(bytes)
 }
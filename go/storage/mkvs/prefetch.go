@@ -3,6 +3,7 @@ package mkvs
 import (
 	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
@@ -16,16 +17,20 @@ func (t *tree) PrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit ui
 	if t.cache.isClosed() {
 		return ErrClosed
 	}
-	if t.cache.rs == syncer.NopReadSyncer {
-		// If there is no remote syncer, we just do nothing.
-		return nil
-	}
 
 	return t.doPrefetchPrefixes(ctx, prefixes, limit)
 }
 
 func (t *tree) doPrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit uint16) error {
-	// TODO: Can we avoid fetching items that we already have?
+	// First, attempt to prefetch from the local node database as that avoids any remote round
+	// trips in case all of the nodes are available locally.
+	complete, err := t.doPrefetchPrefixesLocal(ctx, prefixes, limit)
+	if err != nil {
+		return err
+	}
+	if complete || t.cache.rs == syncer.NopReadSyncer {
+		return nil
+	}
 
 	return t.cache.remoteSync(
 		ctx,
@@ -36,8 +41,9 @@ func (t *tree) doPrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit
 					Root:     t.cache.syncRoot,
 					Position: t.cache.syncRoot.Hash,
 				},
-				Prefixes: prefixes,
-				Limit:    limit,
+				Prefixes:     prefixes,
+				Limit:        limit,
+				ProofVersion: syncer.ProofVersionLatest,
 			})
 			if err != nil {
 				return nil, err
@@ -47,6 +53,108 @@ func (t *tree) doPrefetchPrefixes(ctx context.Context, prefixes [][]byte, limit
 	)
 }
 
+// prefetchItem is a pointer that is pending to be prefetched together with the key path leading
+// to it.
+type prefetchItem struct {
+	ptr   *node.Pointer
+	path  node.Key
+	depth node.Depth
+}
+
+// doPrefetchPrefixesLocal prefetches the subtrees under the given prefixes from the local node
+// database. The tree is traversed one level at a time and all nodes of a level that are not yet
+// cached are fetched using a single batched lookup.
+//
+// Returns true in case all of the traversed nodes were available locally.
+func (t *tree) doPrefetchPrefixesLocal(ctx context.Context, prefixes [][]byte, limit uint16) (bool, error) {
+	var leaves int
+	frontier := []prefetchItem{{ptr: t.cache.pendingRoot}}
+	for len(frontier) > 0 && leaves < int(limit) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		// Fetch all nodes of the current level that are not yet cached.
+		var fetch []*node.Pointer
+		for _, item := range frontier {
+			if item.ptr.Node == nil && item.ptr.Clean && !item.ptr.Hash.IsEmpty() {
+				fetch = append(fetch, item.ptr)
+			}
+		}
+		if len(fetch) > 0 {
+			nodes, err := t.cache.db.GetNodes(t.cache.syncRoot, fetch)
+			if err != nil {
+				return false, err
+			}
+			for i, nd := range nodes {
+				if nd == nil {
+					// Node is not available locally.
+					return false, nil
+				}
+				fetch[i].Node = nd
+				t.cache.commitNode(fetch[i])
+			}
+		}
+
+		// Determine the nodes of the next level that are under the requested prefixes.
+		var next []prefetchItem
+		for _, item := range frontier {
+			switch n := item.ptr.Node.(type) {
+			case nil:
+			case *node.LeafNode:
+				leaves++
+			case *node.InternalNode:
+				path := item.path.Merge(item.depth, n.Label, n.LabelBitLength)
+				depth := item.depth + n.LabelBitLength
+
+				var leaf, left, right bool
+				for _, prefix := range prefixes {
+					prefixKey := node.Key(prefix)
+					prefixLen := prefixKey.BitLength()
+
+					commonLen := depth
+					if prefixLen < commonLen {
+						commonLen = prefixLen
+					}
+					if path.CommonPrefixLen(depth, prefixKey, prefixLen) < commonLen {
+						// Subtree is not under this prefix.
+						continue
+					}
+
+					if depth >= prefixLen {
+						// The whole subtree is under this prefix.
+						leaf, left, right = true, true, true
+						break
+					}
+					if prefixKey.GetBit(depth) {
+						right = true
+					} else {
+						left = true
+					}
+				}
+
+				for _, child := range []struct {
+					ptr     *node.Pointer
+					include bool
+				}{
+					{n.LeafNode, leaf},
+					{n.Left, left},
+					{n.Right, right},
+				} {
+					if !child.include || child.ptr == nil {
+						continue
+					}
+					next = append(next, prefetchItem{ptr: child.ptr, path: path, depth: depth})
+				}
+			default:
+				panic(fmt.Sprintf("mkvs: unknown node type: %+v", n))
+			}
+		}
+		frontier = next
+	}
+	return true, nil
+}
+
 // Implements syncer.ReadSyncer.
 func (t *tree) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesRequest) (*syncer.ProofResponse, error) {
 	t.cache.Lock()
@@ -62,14 +170,11 @@ func (t *tree) SyncGetPrefixes(ctx context.Context, request *syncer.GetPrefixesR
 		return nil, syncer.ErrDirtyRoot
 	}
 
-	// First, trigger same prefetching locally if a remote read syncer
-	// is available. This is needed to ensure that the same optimization
-	// carries on to the next layer.
-	if t.cache.rs != syncer.NopReadSyncer {
-		err := t.doPrefetchPrefixes(ctx, request.Prefixes, request.Limit)
-		if err != nil {
-			return nil, err
-		}
+	// First, trigger same prefetching locally. This fetches the nodes from the
+	// local node database in batches and, if needed, from the remote read
+	// syncer so that the same optimization carries on to the next layer.
+	if err := t.doPrefetchPrefixes(ctx, request.Prefixes, request.Limit); err != nil {
+		return nil, err
 	}
 
 	it := t.NewIterator(ctx, WithProof(request.Tree.Root.Hash))
@@ -96,7 +201,7 @@ prefixLoop:
 		}
 	}
 
-	proof, err := it.GetProof()
+	proof, err := it.GetProofBuilder().BuildVersion(ctx, request.ProofVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package syncer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,20 +11,63 @@ import (
 )
 
 const (
+	// ProofVersionV1 is the original proof format where each entry is encoded separately.
+	ProofVersionV1 uint16 = 1
+	// ProofVersionV2 is the compact proof format where all entries are encoded as a single
+	// stream and repeated subtree hashes are replaced by references.
+	ProofVersionV2 uint16 = 2
+
+	// ProofVersionLatest is the latest supported proof version.
+	ProofVersionLatest = ProofVersionV2
+)
+
+const (
+	// proofEntryEmpty is the proof entry type for empty subtrees (v2 only).
+	proofEntryEmpty byte = 0x00
 	// proofEntryFull is the proof entry type for full nodes.
 	proofEntryFull byte = 0x01
 	// proofEntryHash is the proof entry type for subtree hashes.
 	proofEntryHash byte = 0x02
+	// proofEntryHashRef is the proof entry type for references to previously
+	// seen subtree hashes (v2 only).
+	proofEntryHashRef byte = 0x03
 )
 
+// NegotiateProofVersion returns the proof version that should be used to serve a request
+// for the given proof version.
+func NegotiateProofVersion(requested uint16) uint16 {
+	switch {
+	case requested == 0:
+		// Requests not specifying a version predate proof versioning.
+		return ProofVersionV1
+	case requested > ProofVersionLatest:
+		return ProofVersionLatest
+	default:
+		return requested
+	}
+}
+
 // Proof is a Merkle proof for a subtree.
 type Proof struct {
 	// UntrustedRoot is the root hash this proof is for. This should only be
 	// used as a quick sanity check and proof verification MUST use an
 	// independently obtained root hash as the prover can provide any root.
 	UntrustedRoot hash.Hash `json:"untrusted_root"`
-	// Entries are the proof entries in pre-order traversal.
+	// Entries are the proof entries in pre-order traversal (v1 only).
 	Entries [][]byte `json:"entries"`
+
+	// V is the proof version. Zero means the proof is a v1 proof.
+	V uint16 `json:"v,omitempty"`
+	// Data is the encoded stream of proof entries in pre-order traversal (v2 only).
+	Data []byte `json:"data,omitempty"`
+}
+
+// Version returns the proof version.
+func (p *Proof) Version() uint16 {
+	if p.V == 0 {
+		return ProofVersionV1
+	}
+	return p.V
 }
 
 type proofNode struct {
@@ -109,7 +153,22 @@ func (b *ProofBuilder) Size() uint64 {
 	return b.size
 }
 
+// BuildVersion tries to build the proof using the given proof version.
+//
+// In case the requested version is not supported, the latest supported version is
+// used instead.
+func (b *ProofBuilder) BuildVersion(ctx context.Context, version uint16) (*Proof, error) {
+	switch NegotiateProofVersion(version) {
+	case ProofVersionV2:
+		return b.buildV2(ctx)
+	default:
+		return b.Build(ctx)
+	}
+}
+
 // Build tries to build the proof.
+//
+// The proof is built using the v1 proof format.
 func (b *ProofBuilder) Build(ctx context.Context) (*Proof, error) {
 	proof := Proof{
 		UntrustedRoot: b.root,
@@ -156,6 +215,8 @@ func (b *ProofBuilder) build(ctx context.Context, proof *Proof, h hash.Hash) err
 
 // ProofVerifier enables verifying proofs returned by the ReadSyncer API.
 type ProofVerifier struct {
+	// Arena is an optional arena used to allocate the deserialized nodes.
+	Arena *node.Arena
 }
 
 // VerifyProof verifies a proof and generates an in-memory subtree representing
@@ -169,14 +230,40 @@ func (pv *ProofVerifier) VerifyProof(ctx context.Context, root hash.Hash, proof
 			proof.UntrustedRoot,
 		)
 	}
-	if len(proof.Entries) == 0 {
-		return nil, errors.New("verifier: empty proof")
-	}
 
-	_, rootNode, err := pv.verifyProof(ctx, proof, 0)
+	var (
+		rootNode *node.Pointer
+		err      error
+	)
+	switch proof.Version() {
+	case ProofVersionV1:
+		if len(proof.Entries) == 0 {
+			return nil, errors.New("verifier: empty proof")
+		}
+
+		_, rootNode, err = pv.verifyProof(ctx, proof, 0)
+	case ProofVersionV2:
+		if len(proof.Data) == 0 {
+			return nil, errors.New("verifier: empty proof")
+		}
+
+		// The whole proof is already in memory, so entries can be at most as large as the proof.
+		sv := StreamingProofVerifier{
+			MaxEntrySize: uint64(len(proof.Data)),
+			Arena:        pv.Arena,
+		}
+		rootNode, err = sv.decode(ctx, bytes.NewReader(proof.Data))
+	default:
+		return nil, fmt.Errorf("verifier: unsupported proof version (%d)", proof.V)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return checkProofRoot(root, rootNode)
+}
+
+// checkProofRoot checks that the decoded proof matches the expected root.
+func checkProofRoot(root hash.Hash, rootNode *node.Pointer) (*node.Pointer, error) {
 	rootNodeHash := rootNode.GetHash()
 	if rootNodeHash.IsEmpty() {
 		// Make sure that in case the root node is empty we always return nil
@@ -212,7 +299,7 @@ func (pv *ProofVerifier) verifyProof(ctx context.Context, proof *Proof, idx int)
 	switch entry[0] {
 	case proofEntryFull:
 		// Full node.
-		n, err := node.UnmarshalBinary(entry[1:])
+		n, err := pv.Arena.UnmarshalBinary(entry[1:])
 		if err != nil {
 			return -1, nil, err
 		}
@@ -235,7 +322,11 @@ func (pv *ProofVerifier) verifyProof(ctx context.Context, proof *Proof, idx int)
 			nd.UpdateHash()
 		}
 
-		return pos, &node.Pointer{Clean: true, Hash: n.GetHash(), Node: n}, nil
+		ptr := pv.Arena.NewPointer()
+		ptr.Clean = true
+		ptr.Hash = n.GetHash()
+		ptr.Node = n
+		return pos, ptr, nil
 	case proofEntryHash:
 		// Hash of a node.
 		var h hash.Hash
@@ -243,7 +334,10 @@ func (pv *ProofVerifier) verifyProof(ctx context.Context, proof *Proof, idx int)
 			return -1, nil, err
 		}
 
-		return idx + 1, &node.Pointer{Clean: true, Hash: h}, nil
+		ptr := pv.Arena.NewPointer()
+		ptr.Clean = true
+		ptr.Hash = h
+		return idx + 1, ptr, nil
 	default:
 		return -1, nil, fmt.Errorf("verifier: unexpected entry in proof (%x)", entry[0])
 	}
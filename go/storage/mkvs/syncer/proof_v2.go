@@ -0,0 +1,289 @@
+package syncer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// DefaultMaxProofEntrySize is the default maximum size of a single encoded node in a
+// streamed proof.
+const DefaultMaxProofEntrySize = 16 * 1024 * 1024
+
+// The v2 proof format encodes all entries in pre-order traversal as a single stream where
+// each entry starts with its type:
+//
+//   - proofEntryEmpty: An empty subtree, no payload.
+//   - proofEntryFull: A full node, followed by the uvarint-encoded size of the compact node
+//     encoding and the encoding itself. Hashes of full nodes are never encoded as they
+//     can be computed by the verifier.
+//   - proofEntryHash: A subtree hash, followed by the hash. Each such hash is appended to
+//     the hash palette which is implicitly maintained by both the encoder and the decoder.
+//   - proofEntryHashRef: A subtree hash that is already in the palette, followed by the
+//     uvarint-encoded offset of the hash in the palette.
+
+func (b *ProofBuilder) buildV2(ctx context.Context) (*Proof, error) {
+	enc := proofEncoderV2{
+		b:       b,
+		palette: make(map[hash.Hash]uint64),
+	}
+	if err := enc.encode(ctx, b.root); err != nil {
+		return nil, err
+	}
+
+	return &Proof{
+		UntrustedRoot: b.root,
+		V:             ProofVersionV2,
+		Data:          enc.buf.Bytes(),
+	}, nil
+}
+
+type proofEncoderV2 struct {
+	b *ProofBuilder
+
+	buf     bytes.Buffer
+	palette map[hash.Hash]uint64
+}
+
+func (e *proofEncoderV2) writeUvarint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	_, _ = e.buf.Write(scratch[:n])
+}
+
+func (e *proofEncoderV2) encode(ctx context.Context, h hash.Hash) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if h.IsEmpty() {
+		_ = e.buf.WriteByte(proofEntryEmpty)
+		return nil
+	}
+	n := e.b.included[h]
+	if n == nil {
+		// Node is not included in this proof, just add hash of subtree or a reference to
+		// it in case the same hash has already been included.
+		if offset, ok := e.palette[h]; ok {
+			_ = e.buf.WriteByte(proofEntryHashRef)
+			e.writeUvarint(offset)
+			return nil
+		}
+		e.palette[h] = uint64(len(e.palette))
+
+		data, err := h.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_ = e.buf.WriteByte(proofEntryHash)
+		_, _ = e.buf.Write(data)
+		return nil
+	}
+
+	// Pre-order traversal, add visited node.
+	_ = e.buf.WriteByte(proofEntryFull)
+	e.writeUvarint(uint64(len(n.serialized)))
+	_, _ = e.buf.Write(n.serialized)
+
+	// And then add any children.
+	for _, childHash := range n.children {
+		if err := e.encode(ctx, childHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StreamingProofVerifier verifies v2 proofs read from a stream without first reading the
+// whole proof into memory.
+//
+// The amount of memory used during verification is bounded by the configured limits.
+type StreamingProofVerifier struct {
+	// MaxEntrySize is the maximum size of a single encoded node. If zero, the default
+	// limit of DefaultMaxProofEntrySize is used.
+	MaxEntrySize uint64
+	// MaxNodes is the maximum number of nodes (including subtree hashes) in the proof.
+	// If zero, the number of nodes is not limited.
+	MaxNodes uint64
+	// MaxDepth is the maximum depth of the proof. If zero, the depth is not limited.
+	MaxDepth uint64
+	// Arena is an optional arena used to allocate the deserialized nodes.
+	Arena *node.Arena
+}
+
+// VerifyProofStream verifies a v2 proof read from the given reader and generates an
+// in-memory subtree representing the nodes which are included in the proof.
+//
+// The reader must only contain the encoded proof entries (e.g., Proof.Data).
+func (sv *StreamingProofVerifier) VerifyProofStream(ctx context.Context, root hash.Hash, r io.Reader) (*node.Pointer, error) {
+	rootNode, err := sv.decode(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return checkProofRoot(root, rootNode)
+}
+
+func (sv *StreamingProofVerifier) decode(ctx context.Context, r io.Reader) (*node.Pointer, error) {
+	br, ok := r.(proofReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	dec := proofDecoderV2{
+		sv:           sv,
+		r:            br,
+		maxEntrySize: sv.MaxEntrySize,
+	}
+	if dec.maxEntrySize == 0 {
+		dec.maxEntrySize = DefaultMaxProofEntrySize
+	}
+
+	rootNode, err := dec.decode(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure there is no trailing data.
+	switch _, err = br.ReadByte(); err {
+	case io.EOF:
+	case nil:
+		return nil, errors.New("verifier: malformed proof: trailing data")
+	default:
+		return nil, err
+	}
+
+	return rootNode, nil
+}
+
+type proofReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+type proofDecoderV2 struct {
+	sv *StreamingProofVerifier
+	r  proofReader
+
+	maxEntrySize uint64
+	nodes        uint64
+	palette      []hash.Hash
+}
+
+func (d *proofDecoderV2) readUvarint() (uint64, error) {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, fmt.Errorf("verifier: malformed proof: %w", err)
+	}
+	return v, nil
+}
+
+func (d *proofDecoderV2) countNode() error {
+	d.nodes++
+	if d.sv.MaxNodes > 0 && d.nodes > d.sv.MaxNodes {
+		return errors.New("verifier: proof exceeds maximum number of nodes")
+	}
+	return nil
+}
+
+func (d *proofDecoderV2) newPointer(h hash.Hash, n node.Node) *node.Pointer {
+	ptr := d.sv.Arena.NewPointer()
+	ptr.Clean = true
+	ptr.Hash = h
+	ptr.Node = n
+	return ptr
+}
+
+func (d *proofDecoderV2) decode(ctx context.Context, depth uint64) (*node.Pointer, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if d.sv.MaxDepth > 0 && depth > d.sv.MaxDepth {
+		return nil, errors.New("verifier: proof exceeds maximum depth")
+	}
+
+	entryType, err := d.r.ReadByte()
+	if err != nil {
+		return nil, errors.New("verifier: malformed proof")
+	}
+
+	switch entryType {
+	case proofEntryEmpty:
+		return nil, nil
+	case proofEntryFull:
+		// Full node.
+		if err = d.countNode(); err != nil {
+			return nil, err
+		}
+		var size uint64
+		if size, err = d.readUvarint(); err != nil {
+			return nil, err
+		}
+		if size > d.maxEntrySize {
+			return nil, fmt.Errorf("verifier: proof entry too large (%d bytes)", size)
+		}
+		data := make([]byte, size)
+		if _, err = io.ReadFull(d.r, data); err != nil {
+			return nil, fmt.Errorf("verifier: malformed proof: %w", err)
+		}
+
+		var n node.Node
+		if n, err = d.sv.Arena.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+
+		// For internal nodes, also decode children.
+		if nd, ok := n.(*node.InternalNode); ok {
+			if nd.Left, err = d.decode(ctx, depth+1); err != nil {
+				return nil, err
+			}
+			if nd.Right, err = d.decode(ctx, depth+1); err != nil {
+				return nil, err
+			}
+
+			// Recompute hash as hashes were not recomputed for compact encoding.
+			nd.UpdateHash()
+		}
+
+		return d.newPointer(n.GetHash(), n), nil
+	case proofEntryHash:
+		// Hash of a node.
+		if err = d.countNode(); err != nil {
+			return nil, err
+		}
+		var rawHash [hash.Size]byte
+		if _, err = io.ReadFull(d.r, rawHash[:]); err != nil {
+			return nil, fmt.Errorf("verifier: malformed proof: %w", err)
+		}
+		var h hash.Hash
+		if err = h.UnmarshalBinary(rawHash[:]); err != nil {
+			return nil, err
+		}
+		d.palette = append(d.palette, h)
+
+		return d.newPointer(h, nil), nil
+	case proofEntryHashRef:
+		// Reference to a previously seen hash of a node.
+		if err = d.countNode(); err != nil {
+			return nil, err
+		}
+		var offset uint64
+		if offset, err = d.readUvarint(); err != nil {
+			return nil, err
+		}
+		if offset >= uint64(len(d.palette)) {
+			return nil, errors.New("verifier: malformed proof: bad hash reference")
+		}
+
+		return d.newPointer(d.palette[offset], nil), nil
+	default:
+		return nil, fmt.Errorf("verifier: unexpected entry in proof (%x)", entryType)
+	}
+}
@@ -34,6 +34,10 @@ type GetRequest struct {
 	Tree            TreeID `json:"tree"`
 	Key             []byte `json:"key"`
 	IncludeSiblings bool   `json:"include_siblings,omitempty"`
+
+	// ProofVersion is the highest proof version supported by the caller. If not set,
+	// a v1 proof is returned.
+	ProofVersion uint16 `json:"proof_version,omitempty"`
 }
 
 // GetPrefixesRequest is a request for the SyncGetPrefixes operation.
@@ -41,6 +45,10 @@ type GetPrefixesRequest struct {
 	Tree     TreeID   `json:"tree"`
 	Prefixes [][]byte `json:"prefixes"`
 	Limit    uint16   `json:"limit"`
+
+	// ProofVersion is the highest proof version supported by the caller. If not set,
+	// a v1 proof is returned.
+	ProofVersion uint16 `json:"proof_version,omitempty"`
 }
 
 // IterateRequest is a request for the SyncIterate operation.
@@ -48,6 +56,10 @@ type IterateRequest struct {
 	Tree     TreeID `json:"tree"`
 	Key      []byte `json:"key"`
 	Prefetch uint16 `json:"prefetch"`
+
+	// ProofVersion is the highest proof version supported by the caller. If not set,
+	// a v1 proof is returned.
+	ProofVersion uint16 `json:"proof_version,omitempty"`
 }
 
 // ProofResponse is a response for requests that produce proofs.
@@ -1,6 +1,7 @@
 package mkvs
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"testing"
@@ -169,3 +170,100 @@ func copyProof(p *syncer.Proof) *syncer.Proof {
 	}
 	return &result
 }
+
+func TestProofV2(t *testing.T) {
+	require := require.New(t)
+
+	// Build a simple in-memory Merkle tree.
+	ctx := context.Background()
+	keys, values := generateKeyValuePairsEx("", 100)
+	var ns common.Namespace
+
+	tree := New(nil, nil).(*tree)
+	for i, key := range keys {
+		err := tree.Insert(ctx, key, values[i])
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+
+	// Request a proof for a single key, using each of the proof versions.
+	rsp, err := tree.SyncGet(ctx, &syncer.GetRequest{
+		Tree: syncer.TreeID{
+			Root:     node.Root{Namespace: ns, Version: 0, Hash: rootHash},
+			Position: rootHash,
+		},
+		Key:             keys[42],
+		IncludeSiblings: true,
+	})
+	require.NoError(err, "SyncGet (v1)")
+	proofV1 := rsp.Proof
+	require.EqualValues(syncer.ProofVersionV1, proofV1.Version(), "requests without version should get v1 proofs")
+
+	rsp, err = tree.SyncGet(ctx, &syncer.GetRequest{
+		Tree: syncer.TreeID{
+			Root:     node.Root{Namespace: ns, Version: 0, Hash: rootHash},
+			Position: rootHash,
+		},
+		Key:             keys[42],
+		IncludeSiblings: true,
+		ProofVersion:    syncer.ProofVersionLatest + 1,
+	})
+	require.NoError(err, "SyncGet (v2)")
+	proofV2 := rsp.Proof
+	require.EqualValues(syncer.ProofVersionLatest, proofV2.Version(), "unsupported versions should be negotiated down")
+	require.Empty(proofV2.Entries, "v2 proofs should not contain v1 entries")
+	require.True(len(cbor.Marshal(proofV2)) < len(cbor.Marshal(proofV1)), "v2 proofs should be smaller")
+
+	// Both proofs should verify to the same subtree.
+	var pv syncer.ProofVerifier
+	ptrV1, err := pv.VerifyProof(ctx, rootHash, &proofV1)
+	require.NoError(err, "VerifyProof (v1)")
+	ptrV2, err := pv.VerifyProof(ctx, rootHash, &proofV2)
+	require.NoError(err, "VerifyProof (v2)")
+	require.EqualValues(ptrV1.Hash, ptrV2.Hash, "verified root hashes should match")
+
+	// Streaming verification.
+	var sv syncer.StreamingProofVerifier
+	_, err = sv.VerifyProofStream(ctx, rootHash, bytes.NewReader(proofV2.Data))
+	require.NoError(err, "VerifyProofStream")
+
+	// Streaming verification with limits.
+	sv = syncer.StreamingProofVerifier{MaxNodes: 2}
+	_, err = sv.VerifyProofStream(ctx, rootHash, bytes.NewReader(proofV2.Data))
+	require.Error(err, "VerifyProofStream should fail when exceeding the node limit")
+	sv = syncer.StreamingProofVerifier{MaxDepth: 1}
+	_, err = sv.VerifyProofStream(ctx, rootHash, bytes.NewReader(proofV2.Data))
+	require.Error(err, "VerifyProofStream should fail when exceeding the depth limit")
+	sv = syncer.StreamingProofVerifier{MaxEntrySize: 1}
+	_, err = sv.VerifyProofStream(ctx, rootHash, bytes.NewReader(proofV2.Data))
+	require.Error(err, "VerifyProofStream should fail when exceeding the entry size limit")
+
+	// Corrupted proofs.
+	corrupted := proofV2
+	corrupted.Data = append([]byte{}, proofV2.Data...)
+	corrupted.Data[len(corrupted.Data)-1] ^= 0xff
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with invalid proof")
+
+	corrupted.Data = proofV2.Data[:len(proofV2.Data)-1]
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with truncated proof")
+
+	corrupted.Data = append(append([]byte{}, proofV2.Data...), 0x00)
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with trailing data")
+
+	corrupted.Data = append([]byte{0xaa}, proofV2.Data[1:]...)
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with invalid proof element type")
+
+	corrupted.Data = nil
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with empty proof")
+
+	corrupted = proofV2
+	corrupted.V = syncer.ProofVersionLatest + 1
+	_, err = pv.VerifyProof(ctx, rootHash, &corrupted)
+	require.Error(err, "VerifyProof should fail with unsupported proof version")
+}
@@ -6,7 +6,6 @@ import (
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
-	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
 
 var _ Tree = (*tree)(nil)
@@ -48,6 +47,24 @@ func Capacity(nodeCapacity, valueCapacityBytes uint64) Option {
 	}
 }
 
+// WithCacheBudget makes the in-memory cache use the given memory budget which may be shared with
+// other trees. In this case the node and value capacities are ignored.
+func WithCacheBudget(budget *CacheBudget) Option {
+	return func(t *tree) {
+		t.cache.budget = budget
+	}
+}
+
+// WithNodeArena makes the in-memory cache allocate nodes obtained from the remote syncer from a
+// node arena and reuse nodes evicted from the cache for subsequent allocations. This reduces GC
+// pressure when many nodes are being synced.
+func WithNodeArena() Option {
+	return func(t *tree) {
+		t.cache.arena = node.NewArena()
+		t.cache.ProofVerifier.Arena = t.cache.arena
+	}
+}
+
 // PersistEverythingFromSyncer sets whether to persist all the nodes and
 // values obtained from the remote syncer to local database.
 //
@@ -110,35 +127,6 @@ func (t *tree) NewIterator(ctx context.Context, options ...IteratorOption) Itera
 	return newTreeIterator(ctx, t, options...)
 }
 
-// Implements Tree.
-func (t *tree) ApplyWriteLog(ctx context.Context, wl writelog.Iterator) error {
-	for {
-		// Fetch next entry from write log iterator.
-		more, err := wl.Next()
-		if err != nil {
-			return err
-		}
-		if !more {
-			break
-		}
-		entry, err := wl.Value()
-		if err != nil {
-			return err
-		}
-
-		// Apply operation.
-		if entry.Value == nil {
-			err = t.Remove(ctx, entry.Key)
-		} else {
-			err = t.Insert(ctx, entry.Key, entry.Value)
-		}
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Implements Tree.
 func (t *tree) Close() {
 	t.cache.Lock()
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
@@ -812,6 +813,30 @@ func testSyncerPrefetchPrefixes(t *testing.T, ndb db.NodeDB, factory NodeDBFacto
 	require.EqualValues(t, 0, stats.SyncIterateCount, "SyncIterate should not be called")
 }
 
+func testSyncerPrefetchPrefixesLocal(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	keys, values, root, tree := generatePopulatedTree(t, ndb)
+
+	stats := syncer.NewStatsCollector(tree)
+	localTree := NewWithRoot(stats, ndb, root, Capacity(0, 0))
+
+	// Prefetch keys starting with prefix "key". As all nodes are available in the local node
+	// database, the remote syncer should not be used.
+	err := localTree.PrefetchPrefixes(ctx, [][]byte{[]byte("key")}, 1000)
+	require.NoError(t, err, "PrefetchPrefixes")
+	require.EqualValues(t, 0, stats.SyncGetPrefixesCount, "SyncGetPrefixes should not be called")
+
+	// Ensure that everything is now cached.
+	for i, key := range keys {
+		v, err := localTree.Get(ctx, key)
+		require.NoError(t, err, "Get")
+		require.EqualValues(t, values[i], v)
+	}
+	require.EqualValues(t, 0, stats.SyncGetCount, "SyncGet should not be called")
+	require.EqualValues(t, 0, stats.SyncGetPrefixesCount, "SyncGetPrefixes should not be called")
+	require.EqualValues(t, 0, stats.SyncIterateCount, "SyncIterate should not be called")
+}
+
 func testValueEviction(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	ctx := context.Background()
 	tree := New(nil, ndb, Capacity(0, 512)).(*tree)
@@ -857,6 +882,78 @@ func testNodeEviction(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	require.EqualValues(t, 15904, tree.cache.valueSize, "Cache.LeafValueSize")
 }
 
+func testNodeArena(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	keys, values, root, localTree := generatePopulatedTree(t, ndb)
+
+	// Use a small cache so that synced nodes get evicted and reused.
+	remoteTree := NewWithRoot(localTree, nil, root, Capacity(16, 1024), WithNodeArena()).(*tree)
+	expected := make(map[string][]byte)
+	for i := 0; i < len(keys); i++ {
+		expected[string(keys[i])] = values[i]
+	}
+	for round := 0; round < 2; round++ {
+		for i := 0; i < len(keys); i++ {
+			value, err := remoteTree.Get(ctx, keys[i])
+			require.NoError(t, err, "Get")
+			require.Equal(t, values[i], value)
+		}
+
+		it := remoteTree.NewIterator(ctx)
+		var count int
+		for it.Rewind(); it.Valid(); it.Next() {
+			require.Equal(t, expected[string(it.Key())], it.Value(), "iterator should return correct values")
+			count++
+		}
+		require.NoError(t, it.Err(), "iterator should not fail")
+		require.Equal(t, len(keys), count, "iterator should visit all keys")
+		it.Close()
+	}
+
+	stats := remoteTree.cache.arena.Stats()
+	require.NotZero(t, stats.Released, "evicted nodes should be released into the arena")
+	require.NotZero(t, stats.Reused, "released nodes should be reused")
+}
+
+func testCacheBudget(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+	budget := NewCacheBudget(16 * 1024)
+
+	keys, values := generateKeyValuePairs()
+	tree := New(nil, ndb, WithCacheBudget(budget))
+	for i := 0; i < len(keys); i++ {
+		err := tree.Insert(ctx, keys[i], values[i])
+		require.NoError(t, err, "Insert")
+	}
+
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(t, err, "Commit")
+
+	stats := budget.Stats()
+	require.EqualValues(t, 16*1024, stats.Capacity, "CacheStats.Capacity")
+	require.True(t, stats.Size <= stats.Capacity, "cache should stay within the budget")
+	require.True(t, stats.Evictions > 0, "nodes should be evicted to stay within the budget")
+
+	// A second tree sharing the same budget should fetch evicted nodes from the node database.
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+	tree2 := NewWithRoot(nil, ndb, root, WithCacheBudget(budget))
+	for i := 0; i < len(keys); i++ {
+		var value []byte
+		value, err = tree2.Get(ctx, keys[i])
+		require.NoError(t, err, "Get")
+		require.Equal(t, values[i], value, "Get should return the correct value")
+	}
+
+	stats = budget.Stats()
+	require.True(t, stats.Hits > 0, "lookups should be served from memory")
+	require.True(t, stats.Misses > 0, "lookups should fetch evicted nodes")
+
+	// Closing the trees should release the whole budget.
+	tree.Close()
+	tree2.Close()
+	require.EqualValues(t, 0, budget.Stats().Size, "CacheStats.Size")
+}
+
 func testDoubleInsertWithEviction(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	ctx := context.Background()
 	tree := New(nil, ndb, Capacity(128, 0))
@@ -935,6 +1032,91 @@ func testApplyWriteLog(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	require.True(t, rootHash.IsEmpty(), "root hash must be empty after removal of all items")
 }
 
+func testApplyWriteLogBatch(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
+	ctx := context.Background()
+
+	// Populate the tree with regular keys and keys that are prefixes of each other.
+	keys, values := generateKeyValuePairsEx("", 200)
+	longKeys, longValues := generateLongKeyValuePairs()
+	keys = append(keys, longKeys...)
+	values = append(values, longValues...)
+
+	tree := New(nil, ndb)
+	for i := range keys {
+		err := tree.Insert(ctx, keys[i], values[i])
+		require.NoError(t, err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(t, err, "Commit")
+	tree.Close()
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+
+	// Generate a write log containing updates, new keys, removals and repeated keys, in random
+	// order.
+	rng := rand.New(rand.NewSource(42)) // nolint: gosec
+	var writeLog writelog.WriteLog
+	for i := range keys {
+		switch rng.Intn(3) {
+		case 0:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i]})
+		case 1:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i], Value: []byte(fmt.Sprintf("updated %d", i))})
+		default:
+		}
+	}
+	newKeys, newValues := generateKeyValuePairsEx("new ", 200)
+	for i := range newKeys {
+		writeLog = append(writeLog, writelog.LogEntry{Key: newKeys[i], Value: newValues[i]})
+	}
+	writeLog = append(writeLog,
+		writelog.LogEntry{Key: []byte{}, Value: []byte("empty key")},
+		writelog.LogEntry{Key: newKeys[0]},
+		writelog.LogEntry{Key: keys[1], Value: []byte("updated twice")},
+	)
+	rng.Shuffle(len(writeLog), func(i, j int) {
+		writeLog[i], writeLog[j] = writeLog[j], writeLog[i]
+	})
+	// Make sure repeated keys are applied in order.
+	writeLog = append(writeLog,
+		writelog.LogEntry{Key: newKeys[1]},
+		writelog.LogEntry{Key: newKeys[1], Value: []byte("reinserted")},
+	)
+
+	// Apply the write log one entry at a time.
+	seqTree := NewWithRoot(nil, ndb, root)
+	defer seqTree.Close()
+	expected := make(map[string][]byte)
+	for _, entry := range writeLog {
+		if entry.Value == nil {
+			err = seqTree.Remove(ctx, entry.Key)
+		} else {
+			err = seqTree.Insert(ctx, entry.Key, entry.Value)
+		}
+		require.NoError(t, err, "Insert/Remove")
+		expected[string(entry.Key)] = entry.Value
+	}
+	seqWriteLog, seqRootHash, err := seqTree.Commit(ctx, testNs, 1, NoPersist())
+	require.NoError(t, err, "Commit")
+
+	// Apply the write log as a batch, with a small cache to exercise eviction.
+	batchTree := NewWithRoot(nil, ndb, root, Capacity(50, 16*1024))
+	defer batchTree.Close()
+	err = batchTree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog))
+	require.NoError(t, err, "ApplyWriteLog")
+	batchWriteLog, batchRootHash, err := batchTree.Commit(ctx, testNs, 1, NoPersist())
+	require.NoError(t, err, "Commit")
+
+	require.EqualValues(t, seqRootHash, batchRootHash, "batched application should result in the same root")
+	require.ElementsMatch(t, seqWriteLog, batchWriteLog, "batched application should result in the same write log")
+
+	for key, value := range expected {
+		var v []byte
+		v, err = batchTree.Get(ctx, []byte(key))
+		require.NoError(t, err, "Get")
+		require.EqualValues(t, value, v, "value must be correct")
+	}
+}
+
 func testOnCommitHooks(t *testing.T, ndb db.NodeDB, factory NodeDBFactory) {
 	var emptyRoot hash.Hash
 	emptyRoot.Empty()
@@ -2043,15 +2225,19 @@ func testBackend(
 		{"InsertCommitEach", testInsertCommitEach},
 		{"Remove", testRemove},
 		{"ApplyWriteLog", testApplyWriteLog},
+		{"ApplyWriteLogBatch", testApplyWriteLogBatch},
 		{"SyncerBasic", testSyncerBasic},
 		{"SyncerRootEmptyLabelNeedsDeref", testSyncerRootEmptyLabelNeedsDeref},
 		{"SyncerRemove", testSyncerRemove},
 		{"SyncerInsert", testSyncerInsert},
 		{"SyncerNilNodes", testSyncerNilNodes},
 		{"SyncerPrefetchPrefixes", testSyncerPrefetchPrefixes},
+		{"SyncerPrefetchPrefixesLocal", testSyncerPrefetchPrefixesLocal},
 		{"ValueEviction", testValueEviction},
 		{"NodeEviction", testNodeEviction},
 		{"DoubleInsertWithEviction", testDoubleInsertWithEviction},
+		{"CacheBudget", testCacheBudget},
+		{"NodeArena", testNodeArena},
 		{"DebugDump", testDebugDumpLocal},
 		{"OnCommitHooks", testOnCommitHooks},
 		{"CommitNoPersist", testCommitNoPersist},
@@ -2119,6 +2305,56 @@ func TestBadgerBackend(t *testing.T) {
 	}, nil)
 }
 
+func TestBadgerBackendKeyFilter(t *testing.T) {
+	testBackend(t, func(t *testing.T) (NodeDBFactory, func()) {
+		// Create a new random temporary directory under /tmp.
+		dir, err := ioutil.TempDir("", "mkvs.test.badger")
+		require.NoError(t, err, "TempDir")
+
+		// Create a Badger-backed Node DB factory with a small key filter to also exercise rebuilds.
+		factory := func(ns common.Namespace) (db.NodeDB, error) {
+			return badgerDb.New(&db.Config{
+				DB:                dir,
+				NoFsync:           true,
+				Namespace:         ns,
+				MaxCacheSize:      16 * 1024 * 1024,
+				KeyFilterCapacity: 64,
+			})
+		}
+
+		cleanup := func() {
+			os.RemoveAll(dir)
+		}
+
+		return factory, cleanup
+	}, nil)
+}
+
+func TestBadgerBackendSharedNodes(t *testing.T) {
+	testBackend(t, func(t *testing.T) (NodeDBFactory, func()) {
+		// Create a new random temporary directory under /tmp.
+		dir, err := ioutil.TempDir("", "mkvs.test.badger")
+		require.NoError(t, err, "TempDir")
+
+		// Create a Badger-backed Node DB factory storing nodes in a shared node database.
+		factory := func(ns common.Namespace) (db.NodeDB, error) {
+			return badgerDb.New(&db.Config{
+				DB:           filepath.Join(dir, "ndb"),
+				NoFsync:      true,
+				Namespace:    ns,
+				MaxCacheSize: 16 * 1024 * 1024,
+				SharedNodeDB: filepath.Join(dir, "shared"),
+			})
+		}
+
+		cleanup := func() {
+			os.RemoveAll(dir)
+		}
+
+		return factory, cleanup
+	}, nil)
+}
+
 func BenchmarkInsertCommitBatch1(b *testing.B) {
 	benchmarkInsertBatch(b, 1, true)
 }
@@ -2179,6 +2415,149 @@ func benchmarkInsertBatch(b *testing.B, numValues int, commit bool) {
 	}
 }
 
+func BenchmarkApplyWriteLog1000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 1000, true)
+}
+
+func BenchmarkApplyWriteLog10000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 10000, true)
+}
+
+func BenchmarkApplyWriteLog100000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 100000, true)
+}
+
+func BenchmarkApplyWriteLogSequential1000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 1000, false)
+}
+
+func BenchmarkApplyWriteLogSequential10000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 10000, false)
+}
+
+func BenchmarkApplyWriteLogSequential100000(b *testing.B) {
+	benchmarkApplyWriteLog(b, 100000, false)
+}
+
+func BenchmarkRemoteSync(b *testing.B) {
+	benchmarkRemoteSync(b, false)
+}
+
+func BenchmarkRemoteSyncNodeArena(b *testing.B) {
+	benchmarkRemoteSync(b, true)
+}
+
+// benchmarkRemoteSync benchmarks fetching all keys of a tree via a remote syncer using a small
+// cache, similar to what nodes that sync state from remote storage nodes do.
+func benchmarkRemoteSync(b *testing.B, arena bool) {
+	ctx := context.Background()
+
+	tree := New(nil, nil, Capacity(0, 0))
+	keys, values := generateKeyValuePairsEx("", 10000)
+	for i := 0; i < len(keys); i++ {
+		err := tree.Insert(ctx, keys[i], values[i])
+		require.NoError(b, err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(b, err, "Commit")
+	root := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Hash:      rootHash,
+	}
+
+	options := []Option{Capacity(1000, 64*1024)}
+	if arena {
+		options = append(options, WithNodeArena())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		remoteTree := NewWithRoot(tree, nil, root, options...)
+		for i := 0; i < len(keys); i++ {
+			if _, err = remoteTree.Get(ctx, keys[i]); err != nil {
+				b.Fatalf("Get: %s", err)
+			}
+		}
+		remoteTree.Close()
+	}
+}
+
+// benchmarkApplyWriteLog benchmarks applying a large write log on top of an existing populated
+// tree, similar to what the storage worker does during sync.
+func benchmarkApplyWriteLog(b *testing.B, numValues int, batch bool) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.bench.badgerdb")
+	require.NoError(b, err, "TempDir")
+	defer os.RemoveAll(dir)
+	ndb, err := badgerDb.New(&db.Config{
+		DB:           dir,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(b, err, "New")
+	defer ndb.Close()
+
+	// Populate the tree with existing state.
+	keys, values := generateKeyValuePairsEx("", numValues)
+	tree := New(nil, ndb)
+	for i := range keys {
+		err = tree.Insert(ctx, keys[i], values[i])
+		require.NoError(b, err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(b, err, "Commit")
+	tree.Close()
+	root := node.Root{Namespace: testNs, Version: 0, Hash: rootHash}
+
+	// Generate a write log updating half of the existing keys, removing a quarter of them and
+	// inserting new keys, in random order.
+	rng := rand.New(rand.NewSource(42)) // nolint: gosec
+	var writeLog writelog.WriteLog
+	for i := range keys {
+		switch i % 4 {
+		case 0, 1:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i], Value: []byte(fmt.Sprintf("updated %d", i))})
+		case 2:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i]})
+		default:
+		}
+	}
+	newKeys, newValues := generateKeyValuePairsEx("new ", numValues/4)
+	for i := range newKeys {
+		writeLog = append(writeLog, writelog.LogEntry{Key: newKeys[i], Value: newValues[i]})
+	}
+	rng.Shuffle(len(writeLog), func(i, j int) {
+		writeLog[i], writeLog[j] = writeLog[j], writeLog[i]
+	})
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree = NewWithRoot(nil, ndb, root)
+		switch batch {
+		case true:
+			err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog))
+		case false:
+			for _, entry := range writeLog {
+				if entry.Value == nil {
+					err = tree.Remove(ctx, entry.Key)
+				} else {
+					err = tree.Insert(ctx, entry.Key, entry.Value)
+				}
+				if err != nil {
+					break
+				}
+			}
+		}
+		require.NoError(b, err, "apply write log")
+		_, _, err = tree.Commit(ctx, testNs, 1, NoPersist())
+		require.NoError(b, err, "Commit")
+		tree.Close()
+	}
+}
+
 func generateKeyValuePairsEx(prefix string, count int) ([][]byte, [][]byte) {
 	keys := make([][]byte, count)
 	values := make([][]byte, count)
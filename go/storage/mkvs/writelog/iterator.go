@@ -115,8 +115,17 @@ func (i *PipeIterator) Close() {
 
 // NewPipeIterator returns a new PipeIterator.
 func NewPipeIterator(ctx context.Context) PipeIterator {
+	return NewPipeIteratorWithBufferSize(ctx, 0)
+}
+
+// NewPipeIteratorWithBufferSize returns a new PipeIterator that can take at most the given number
+// of elements before starting to block. A size of zero selects the default buffer size.
+func NewPipeIteratorWithBufferSize(ctx context.Context, size int) PipeIterator {
+	if size <= 0 {
+		size = pipeIteratorQueueSize
+	}
 	return PipeIterator{
-		queue: make(chan interface{}, pipeIteratorQueueSize),
+		queue: make(chan interface{}, size),
 		ctx:   ctx,
 	}
 }
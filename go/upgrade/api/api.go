@@ -41,7 +41,7 @@ const (
 var (
 	// ErrStopForUpgrade is the error returned by the consensus upgrade function when it detects that
 	// the consensus layer has reached the scheduled shutdown epoch and should be interrupted.
-	ErrStopForUpgrade = errors.New(ModuleName, 1, "upgrade: reached upgrade epoch")
+	ErrStopForUpgrade = errors.New(ModuleName, 1, "upgrade: reached upgrade point")
 
 	// ErrUpgradePending is the error returned when there is a pending upgrade and the node detects that it is
 	// not the one performing it.
@@ -60,6 +60,10 @@ var (
 
 	// ErrUpgradeInProgress is the error returned from CancelUpgrade when the upgrade being cancelled is already in progress.
 	ErrUpgradeInProgress = errors.New(ModuleName, 6, "upgrade: can not cancel upgrade in progress")
+
+	// ErrNoUpgradeHandler is the error returned when the node binary that should perform the pending
+	// upgrade does not have a handler registered for it.
+	ErrNoUpgradeHandler = errors.New(ModuleName, 7, "upgrade: no handler registered for pending upgrade")
 )
 
 // Descriptor describes an upgrade.
@@ -72,7 +76,13 @@ type Descriptor struct {
 	// Upgrade methods other than "internal" may have differently formatted identifiers.
 	Identifier string `json:"identifier"`
 	// Epoch is the epoch at which the upgrade should happen.
-	Epoch epochtime.EpochTime `json:"epoch"`
+	//
+	// Exactly one of Epoch and Height must be set.
+	Epoch epochtime.EpochTime `json:"epoch,omitempty"`
+	// Height is the consensus height at which the upgrade should happen.
+	//
+	// Exactly one of Epoch and Height must be set.
+	Height int64 `json:"height,omitempty"`
 }
 
 // IsValid checks if the upgrade descriptor is valid.
@@ -80,12 +90,24 @@ func (d Descriptor) IsValid() bool {
 	if d.Method != UpgradeMethInternal {
 		return false
 	}
-	if d.Epoch < 1 {
+	if d.Height < 0 {
+		return false
+	}
+	// Exactly one of the epoch or the height must be set.
+	if (d.Epoch >= 1) == (d.Height >= 1) {
 		return false
 	}
 	return true
 }
 
+// IsDue checks if the upgrade should happen at the given epoch and consensus height.
+func (d Descriptor) IsDue(epoch epochtime.EpochTime, height int64) bool {
+	if d.Height > 0 {
+		return height >= d.Height
+	}
+	return epoch >= d.Epoch
+}
+
 // PendingUpgrade describes a currently pending upgrade and includes the
 // submitted upgrade descriptor.
 type PendingUpgrade struct {
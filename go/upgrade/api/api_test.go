@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
+)
+
+func TestDescriptorIsValid(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		msg   string
+		desc  Descriptor
+		valid bool
+	}{
+		{"epoch should be valid", Descriptor{Method: UpgradeMethInternal, Epoch: 10}, true},
+		{"height should be valid", Descriptor{Method: UpgradeMethInternal, Height: 100}, true},
+		{"invalid method should be invalid", Descriptor{Method: "external", Epoch: 10}, false},
+		{"missing epoch and height should be invalid", Descriptor{Method: UpgradeMethInternal}, false},
+		{"both epoch and height should be invalid", Descriptor{Method: UpgradeMethInternal, Epoch: 10, Height: 100}, false},
+		{"negative height should be invalid", Descriptor{Method: UpgradeMethInternal, Epoch: 10, Height: -1}, false},
+	} {
+		require.Equal(tc.valid, tc.desc.IsValid(), tc.msg)
+	}
+}
+
+func TestDescriptorIsDue(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		msg    string
+		desc   Descriptor
+		epoch  epochtime.EpochTime
+		height int64
+		due    bool
+	}{
+		{"epoch upgrade before the epoch", Descriptor{Epoch: 10}, 9, 1000, false},
+		{"epoch upgrade at the epoch", Descriptor{Epoch: 10}, 10, 1, true},
+		{"epoch upgrade after the epoch", Descriptor{Epoch: 10}, 11, 1, true},
+		{"height upgrade before the height", Descriptor{Height: 100}, 1000, 99, false},
+		{"height upgrade at the height", Descriptor{Height: 100}, 0, 100, true},
+		{"height upgrade after the height", Descriptor{Height: 100}, 0, 101, true},
+	} {
+		require.Equal(tc.due, tc.desc.IsDue(tc.epoch, tc.height), tc.msg)
+	}
+}
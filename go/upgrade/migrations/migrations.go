@@ -2,6 +2,8 @@
 package migrations
 
 import (
+	"fmt"
+
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -11,8 +13,14 @@ const (
 	ModuleName = "upgrade-migrations"
 )
 
-var registeredHandlers = map[string]Handler{
-	DummyUpgradeName: &dummyMigrationHandler{},
+// handlerKey is the key under which migration handlers are registered.
+type handlerKey struct {
+	name   string
+	height int64
+}
+
+var registeredHandlers = map[handlerKey]Handler{
+	{name: DummyUpgradeName}: &dummyMigrationHandler{},
 }
 
 // Handler is the interface used by migration handlers.
@@ -25,6 +33,10 @@ type Handler interface {
 	// the consensus portion of the upgrade. The interface argument is
 	// a private structure passed to Backend.ConsensusUpgrade by the
 	// consensus backend.
+	//
+	// The handler is invoked within a state checkpoint, so any state
+	// changes are discarded if it fails. As checkpoints cannot be nested,
+	// the handler must not start a checkpoint of its own.
 	ConsensusUpgrade(*Context, interface{}) error
 }
 
@@ -40,8 +52,22 @@ type Context struct {
 }
 
 // Register registers a new migration handler, by upgrade name.
+//
+// The handler will be used for the named upgrade regardless of when it is scheduled.
 func Register(name string, handler Handler) {
-	registeredHandlers[name] = handler
+	registeredHandlers[handlerKey{name: name}] = handler
+}
+
+// RegisterAtHeight registers a new migration handler, by upgrade name and consensus height.
+//
+// The handler will only be used for the named upgrade in case it is scheduled to happen at the
+// given consensus height, which allows binaries to be built for a specific coordinated upgrade.
+// A handler registered for a specific height takes precedence over one registered via Register.
+func RegisterAtHeight(name string, height int64, handler Handler) {
+	if height < 1 {
+		panic("upgrade/migrations: invalid upgrade height")
+	}
+	registeredHandlers[handlerKey{name: name, height: height}] = handler
 }
 
 // NewContext returns a new upgrade migration context.
@@ -54,14 +80,18 @@ func NewContext(upgrade *upgradeApi.PendingUpgrade, dataDir string) *Context {
 }
 
 // GetHandler returns the handler associated with the upgrade described in the context.
-// If the handler does not exist, this is considered a severe programmer error and will result in a panic.
-func GetHandler(ctx *Context) Handler {
-	handler, ok := registeredHandlers[ctx.Upgrade.Descriptor.Name]
-	if !ok {
-		// If we got here, that means the upgrade descriptor checked out, including the upgrader hash.
-		// Nothing left to do but bite the dust.
-		panic("unknown upgrade name, no way forward")
+//
+// In case this binary does not have a handler for the upgrade, upgradeApi.ErrNoUpgradeHandler
+// is returned.
+func GetHandler(ctx *Context) (Handler, error) {
+	desc := ctx.Upgrade.Descriptor
+	if desc.Height > 0 {
+		if handler, ok := registeredHandlers[handlerKey{name: desc.Name, height: desc.Height}]; ok {
+			return handler, nil
+		}
 	}
-
-	return handler
+	if handler, ok := registeredHandlers[handlerKey{name: desc.Name}]; ok {
+		return handler, nil
+	}
+	return nil, fmt.Errorf("%w: %s", upgradeApi.ErrNoUpgradeHandler, desc.Name)
 }
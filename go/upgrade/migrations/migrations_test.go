@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+type testMigrationHandler struct {
+	dummyMigrationHandler
+
+	id string
+}
+
+func TestGetHandler(t *testing.T) {
+	require := require.New(t)
+
+	const name = "__test-get-handler"
+	byName := &testMigrationHandler{id: "name"}
+	byHeight := &testMigrationHandler{id: "height"}
+	Register(name, byName)
+	RegisterAtHeight(name, 100, byHeight)
+	defer func() {
+		delete(registeredHandlers, handlerKey{name: name})
+		delete(registeredHandlers, handlerKey{name: name, height: 100})
+	}()
+
+	newContext := func(desc *upgradeApi.Descriptor) *Context {
+		return NewContext(&upgradeApi.PendingUpgrade{Descriptor: desc}, "")
+	}
+
+	handler, err := GetHandler(newContext(&upgradeApi.Descriptor{Name: name, Height: 100}))
+	require.NoError(err, "GetHandler")
+	require.Equal(byHeight, handler, "handler registered at height should take precedence")
+
+	handler, err = GetHandler(newContext(&upgradeApi.Descriptor{Name: name, Height: 101}))
+	require.NoError(err, "GetHandler")
+	require.Equal(byName, handler, "handler registered by name should be used for other heights")
+
+	handler, err = GetHandler(newContext(&upgradeApi.Descriptor{Name: name, Epoch: 10}))
+	require.NoError(err, "GetHandler")
+	require.Equal(byName, handler, "handler registered by name should be used for epoch upgrades")
+
+	_, err = GetHandler(newContext(&upgradeApi.Descriptor{Name: "__test-unknown", Height: 100}))
+	require.Error(err, "GetHandler should fail for unknown upgrades")
+	require.True(errors.Is(err, upgradeApi.ErrNoUpgradeHandler), "GetHandler should return ErrNoUpgradeHandler")
+
+	// Height-only registrations should not apply to other heights.
+	const heightOnlyName = "__test-get-handler-height-only"
+	RegisterAtHeight(heightOnlyName, 100, byHeight)
+	defer delete(registeredHandlers, handlerKey{name: heightOnlyName, height: 100})
+
+	_, err = GetHandler(newContext(&upgradeApi.Descriptor{Name: heightOnlyName, Height: 101}))
+	require.True(errors.Is(err, upgradeApi.ErrNoUpgradeHandler), "handler registered at height should not apply elsewhere")
+
+	require.Panics(func() { RegisterAtHeight(name, 0, byHeight) }, "registering at an invalid height should panic")
+}
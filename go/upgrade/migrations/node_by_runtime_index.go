@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"fmt"
+
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+)
+
+const (
+	// NodeByRuntimeIndexUpgradeName is the name of the upgrade that builds the registry node by
+	// runtime index for nodes registered before the index was introduced.
+	NodeByRuntimeIndexUpgradeName = "registry-node-by-runtime-index"
+)
+
+var _ Handler = (*nodeByRuntimeIndexHandler)(nil)
+
+type nodeByRuntimeIndexHandler struct{}
+
+func (h *nodeByRuntimeIndexHandler) StartupUpgrade(ctx *Context) error {
+	return nil
+}
+
+func (h *nodeByRuntimeIndexHandler) ConsensusUpgrade(ctx *Context, privateCtx interface{}) error {
+	abciCtx := privateCtx.(*abciAPI.Context)
+	regState := registryState.NewMutableState(abciCtx.State())
+
+	if err := regState.RebuildNodeByRuntimeIndex(abciCtx); err != nil {
+		return fmt.Errorf("failed to rebuild node by runtime index: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	Register(NodeByRuntimeIndexUpgradeName, &nodeByRuntimeIndexHandler{})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/tendermint/apps/registry/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+func TestNodeByRuntimeIndexHandler(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1580461674, 0)
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock, now)
+	defer ctx.Close()
+
+	regState := registryState.NewMutableState(ctx.State())
+
+	nodeSigner := memorySigner.NewTestSigner("upgrade/migrations: node signer")
+	rt := common.NewTestNamespaceFromSeed([]byte("upgrade/migrations: runtime"), 0)
+	n := node.Node{
+		Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:        nodeSigner.Public(),
+		P2P: node.P2PInfo{
+			ID: memorySigner.NewTestSigner("upgrade/migrations: p2p signer").Public(),
+		},
+		Consensus: node.ConsensusInfo{
+			ID: memorySigner.NewTestSigner("upgrade/migrations: consensus signer").Public(),
+		},
+		TLS: node.TLSInfo{
+			PubKey: memorySigner.NewTestSigner("upgrade/migrations: tls signer").Public(),
+		},
+		Runtimes: []*node.Runtime{
+			{ID: rt},
+		},
+	}
+	sigNode, err := node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, &n)
+	require.NoError(err, "MultiSignNode")
+	err = regState.SetNode(ctx, nil, &n, sigNode)
+	require.NoError(err, "SetNode")
+
+	upgradeCtx := NewContext(&upgradeApi.PendingUpgrade{
+		Descriptor: &upgradeApi.Descriptor{Name: NodeByRuntimeIndexUpgradeName, Height: 100},
+	}, "")
+	handler, err := GetHandler(upgradeCtx)
+	require.NoError(err, "GetHandler")
+	err = handler.ConsensusUpgrade(upgradeCtx, ctx)
+	require.NoError(err, "ConsensusUpgrade")
+
+	nodes, err := regState.RuntimeNodes(ctx, rt)
+	require.NoError(err, "RuntimeNodes")
+	require.Len(nodes, 1, "node should be indexed by runtime")
+	require.EqualValues(n, *nodes[0], "returned node should be correct")
+}
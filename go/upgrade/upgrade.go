@@ -76,6 +76,7 @@ func (u *upgradeManager) SubmitDescriptor(ctx context.Context, descriptor *api.D
 	u.logger.Info("received upgrade descriptor, scheduling shutdown",
 		"name", u.pending.Descriptor.Name,
 		"epoch", u.pending.Descriptor.Epoch,
+		"height", u.pending.Descriptor.Height,
 	)
 
 	return u.flushDescriptor()
@@ -221,16 +222,21 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch e
 		return nil
 	}
 
-	// If we haven't reached the upgrade epoch yet, we run normally;
+	// If we haven't reached the upgrade point yet, we run normally;
 	// startup made sure we're an appropriate binary for that.
 	if u.pending.UpgradeHeight == api.InvalidUpgradeHeight {
-		if currentEpoch < u.pending.Descriptor.Epoch {
+		if !u.pending.Descriptor.IsDue(currentEpoch, currentHeight) {
 			return nil
 		}
 		u.pending.UpgradeHeight = currentHeight
 		if err := u.flushDescriptor(); err != nil {
 			return err
 		}
+		u.logger.Warn("reached upgrade point, stopping for upgrade",
+			"name", u.pending.Descriptor.Name,
+			"epoch", currentEpoch,
+			"height", currentHeight,
+		)
 		return api.ErrStopForUpgrade
 	}
 
@@ -241,7 +247,7 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx interface{}, currentEpoch e
 	}
 
 	if u.pending.UpgradeHeight > currentHeight {
-		panic("consensus upgrade: UpgradeHeight is in the future but upgrade epoch seen already")
+		panic("consensus upgrade: UpgradeHeight is in the future but upgrade point seen already")
 	}
 
 	if !u.pending.HasStage(api.UpgradeStageConsensus) {
@@ -274,13 +280,26 @@ func New(store *persistent.CommonStore, dataDir string) (api.Backend, error) {
 		logger: logging.GetLogger(api.ModuleName),
 	}
 
-	if err := upgrader.checkStatus(); err != nil {
+	if err = upgrader.checkStatus(); err != nil {
 		return nil, err
 	}
 
 	if upgrader.pending != nil {
 		upgrader.ctx = migrations.NewContext(upgrader.pending, dataDir)
-		upgrader.handler = migrations.GetHandler(upgrader.ctx)
+		upgrader.handler, err = migrations.GetHandler(upgrader.ctx)
+		switch {
+		case err == nil:
+		case upgrader.pending.UpgradeHeight == api.InvalidUpgradeHeight:
+			// The upgrade point has not been reached yet so this binary will not be performing
+			// the upgrade and does not need a handler.
+		default:
+			// This binary is supposed to perform the upgrade but it doesn't know how to.
+			upgrader.logger.Error("this binary is missing a handler for the pending upgrade, refusing to start",
+				"name", upgrader.pending.Descriptor.Name,
+				"upgrade_height", upgrader.pending.UpgradeHeight,
+			)
+			return nil, err
+		}
 	}
 
 	return upgrader, nil
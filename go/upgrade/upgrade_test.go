@@ -0,0 +1,79 @@
+package upgrade
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+func TestNewPendingUpgradeHandler(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-core-unittests")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	store, err := persistent.NewCommonStore(dir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	svcStore, err := store.GetServiceStore(api.ModuleName)
+	require.NoError(err, "GetServiceStore")
+
+	thisHash, err := hashSelf()
+	require.NoError(err, "hashSelf")
+
+	setPending := func(pending *api.PendingUpgrade) {
+		err = svcStore.PutCBOR(metadataStoreKey, &pending)
+		require.NoError(err, "PutCBOR")
+	}
+
+	// Before the upgrade point is reached, the old binary does not need a handler.
+	setPending(&api.PendingUpgrade{
+		Descriptor: &api.Descriptor{
+			Name:       "__test-unknown",
+			Method:     api.UpgradeMethInternal,
+			Identifier: thisHash.String(),
+			Height:     100,
+		},
+		SubmittingVersion: thisVersion,
+	})
+	_, err = New(store, dir)
+	require.NoError(err, "New should succeed before the upgrade point")
+
+	// Once the upgrade point is reached, the upgrading binary must have a handler.
+	setPending(&api.PendingUpgrade{
+		Descriptor: &api.Descriptor{
+			Name:       "__test-unknown",
+			Method:     api.UpgradeMethInternal,
+			Identifier: thisHash.String(),
+			Height:     100,
+		},
+		SubmittingVersion: thisVersion,
+		UpgradeHeight:     100,
+	})
+	_, err = New(store, dir)
+	require.Error(err, "New should fail without an upgrade handler")
+	require.True(errors.Is(err, api.ErrNoUpgradeHandler), "New should return ErrNoUpgradeHandler")
+
+	// With a handler, the upgrading binary should start.
+	setPending(&api.PendingUpgrade{
+		Descriptor: &api.Descriptor{
+			Name:       migrations.DummyUpgradeName,
+			Method:     api.UpgradeMethInternal,
+			Identifier: thisHash.String(),
+			Height:     100,
+		},
+		SubmittingVersion: thisVersion,
+		UpgradeHeight:     100,
+	})
+	_, err = New(store, dir)
+	require.NoError(err, "New should succeed with an upgrade handler")
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	epochtime "github.com/oasisprotocol/oasis-core/go/epochtime/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -25,3 +26,27 @@ type Status struct {
 	// Peers is the list of peers in the runtime P2P network.
 	Peers []string `json:"peers"`
 }
+
+// EpochSnapshot is the runtime committee epoch snapshot as seen by the committee node.
+type EpochSnapshot struct {
+	// Epoch is the sequential number of the epoch.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// GroupVersion is the consensus layer height of the last processed committee election. Peer
+	// messages and committee access policies are versioned by it.
+	GroupVersion int64 `json:"group_version"`
+	// RuntimeDescriptorVersion is the version of the runtime descriptor in effect for the epoch.
+	RuntimeDescriptorVersion uint16 `json:"runtime_descriptor_version"`
+
+	// ExecutorCommittee is the executor committee for the epoch, if any.
+	ExecutorCommittee *CommitteeSnapshot `json:"executor_committee,omitempty"`
+	// StorageCommittee is the storage committee for the epoch, if any.
+	StorageCommittee *CommitteeSnapshot `json:"storage_committee,omitempty"`
+}
+
+// CommitteeSnapshot is a committee as seen by the committee node.
+type CommitteeSnapshot struct {
+	// Role is the role of the local node in the committee.
+	Role scheduler.Role `json:"role"`
+	// Committee is the elected committee, including all of its members.
+	Committee *scheduler.Committee `json:"committee"`
+}
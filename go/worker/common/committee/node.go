@@ -177,6 +177,33 @@ func (n *Node) GetStatus(ctx context.Context) (*api.Status, error) {
 	return &status, nil
 }
 
+// GetEpochSnapshot returns the current epoch snapshot of the common committee node.
+func (n *Node) GetEpochSnapshot(ctx context.Context) (*api.EpochSnapshot, error) {
+	epoch := n.Group.GetEpochSnapshot()
+
+	snapshot := api.EpochSnapshot{
+		Epoch:        epoch.GetEpochNumber(),
+		GroupVersion: epoch.GetGroupVersion(),
+	}
+	if rt := epoch.GetRuntime(); rt != nil {
+		snapshot.RuntimeDescriptorVersion = rt.Versioned.V
+	}
+	snapshot.ExecutorCommittee = committeeSnapshot(epoch.GetExecutorCommittee())
+	snapshot.StorageCommittee = committeeSnapshot(epoch.GetStorageCommittee())
+
+	return &snapshot, nil
+}
+
+func committeeSnapshot(ci *CommitteeInfo) *api.CommitteeSnapshot {
+	if ci == nil {
+		return nil
+	}
+	return &api.CommitteeSnapshot{
+		Role:      ci.Role,
+		Committee: ci.Committee,
+	}
+}
+
 func (n *Node) getMetricLabels() prometheus.Labels {
 	return prometheus.Labels{
 		"runtime": n.Runtime.ID().String(),
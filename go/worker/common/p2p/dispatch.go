@@ -9,9 +9,12 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	core "github.com/libp2p/go-libp2p-core"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	p2pError "github.com/oasisprotocol/oasis-core/go/worker/common/p2p/error"
@@ -22,6 +25,22 @@ const (
 	redispatchMaxRetries = 5
 )
 
+var (
+	droppedMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_p2p_dropped_message_count",
+			Help: "Number of duplicate or replayed P2P messages dropped.",
+		},
+		[]string{"runtime", "reason"},
+	)
+
+	p2pCollectors = []prometheus.Collector{
+		droppedMessageCount,
+	}
+
+	metricsOnce sync.Once
+)
+
 // Handler is a handler for P2P messages.
 type Handler interface {
 	// AuthenticatePeer handles authenticating a peer that send an
@@ -60,10 +79,13 @@ type topicHandler struct {
 
 	p2p *P2P
 
+	runtimeID   common.Namespace
 	topic       *pubsub.Topic
 	cancelRelay pubsub.RelayCancelFunc
 	handlers    []Handler
 
+	filter *messageFilter
+
 	numWorkers uint64
 
 	logger *logging.Logger
@@ -94,6 +116,22 @@ func (h *topicHandler) topicMessageValidator(ctx context.Context, unused core.Pe
 		return false
 	}
 
+	// Drop any duplicate or replayed messages from other peers.
+	if peerID != h.p2p.host.ID() {
+		msgHash := hash.NewFromBytes(envelope.GetData())
+		if ok, reason := h.filter.check(peerID, envelope.GetSeqno(), msgHash); !ok {
+			h.logger.Debug("dropping message from peer",
+				"peer_id", peerID,
+				"reason", reason,
+			)
+			droppedMessageCount.With(prometheus.Labels{
+				"runtime": h.runtimeID.String(),
+				"reason":  reason,
+			}).Inc()
+			return false
+		}
+	}
+
 	var msg Message
 	if err = cbor.Unmarshal(envelope.GetData(), &msg); err != nil {
 		h.logger.Error("error while parsing message from peer",
@@ -231,11 +269,13 @@ func newTopicHandler(p *P2P, runtimeID common.Namespace, handlers []Handler) (st
 	}
 
 	h := &topicHandler{
-		ctx:      p.ctx, // TODO: Should this support individual cancelation?
-		p2p:      p,
-		topic:    topic,
-		handlers: handlers,
-		logger:   logging.GetLogger("worker/common/p2p/" + topicID),
+		ctx:       p.ctx, // TODO: Should this support individual cancelation?
+		p2p:       p,
+		runtimeID: runtimeID,
+		topic:     topic,
+		handlers:  handlers,
+		filter:    newMessageFilter(viper.GetUint64(CfgP2PDedupCacheSize), viper.GetDuration(CfgP2PDedupTTL)),
+		logger:    logging.GetLogger("worker/common/p2p/" + topicID),
 	}
 	if h.cancelRelay, err = h.topic.Relay(); err != nil {
 		// Well, ok, fine.  This should NEVER happen, but try to back out
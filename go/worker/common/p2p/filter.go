@@ -0,0 +1,145 @@
+package p2p
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// replayWindowSize is the number of most recent sequence numbers tracked per peer.
+	replayWindowSize = 64
+
+	// maxFilterPeers is the maximum number of peers for which per-peer state is tracked.
+	maxFilterPeers = 256
+	// maxFilterPeerMessages is the maximum number of message hashes tracked per peer.
+	maxFilterPeerMessages = 256
+
+	dropReasonDuplicate     = "duplicate"
+	dropReasonPeerDuplicate = "peer_duplicate"
+	dropReasonReplay        = "replay"
+)
+
+// peerFilter is the per-peer message filter state.
+type peerFilter struct {
+	// seen are the recently seen message hashes originating from the peer.
+	seen *lru.Cache
+
+	// hasSeqno is true iff any sequence numbers have been recorded.
+	hasSeqno bool
+	// maxSeqno is the largest recorded sequence number.
+	maxSeqno uint64
+	// window is a bitmap of recorded sequence numbers, where bit i corresponds to maxSeqno-i.
+	window uint64
+}
+
+func (pf *peerFilter) checkSeqno(seqno uint64) bool {
+	switch {
+	case !pf.hasSeqno || seqno > pf.maxSeqno:
+		return true
+	case pf.maxSeqno-seqno >= replayWindowSize:
+		// Too old to be tracked, treat it as a replay.
+		return false
+	default:
+		return pf.window&(1<<(pf.maxSeqno-seqno)) == 0
+	}
+}
+
+func (pf *peerFilter) recordSeqno(seqno uint64) {
+	switch {
+	case !pf.hasSeqno:
+		pf.hasSeqno = true
+		pf.maxSeqno = seqno
+		pf.window = 1
+	case seqno > pf.maxSeqno:
+		if shift := seqno - pf.maxSeqno; shift < replayWindowSize {
+			pf.window <<= shift
+		} else {
+			pf.window = 0
+		}
+		pf.window |= 1
+		pf.maxSeqno = seqno
+	default:
+		pf.window |= 1 << (pf.maxSeqno - seqno)
+	}
+}
+
+// messageFilter performs message-level deduplication and replay protection.
+//
+// Messages are deduplicated based on their hashes both globally and per originating peer, where
+// the per-peer state makes sure that a peer's own replays are detected even when the global state
+// is being churned by other peers. Seen message hashes expire after a configured TTL. Additionally
+// the pubsub sequence numbers of each peer are tracked in a sliding window so that any replayed
+// messages are rejected.
+//
+// All state is kept in bounded LRU caches.
+type messageFilter struct {
+	sync.Mutex
+
+	ttl time.Duration
+
+	global *lru.Cache
+	peers  *lru.Cache
+}
+
+func (f *messageFilter) isSeen(cache *lru.Cache, h hash.Hash, now time.Time) bool {
+	v, ok := cache.Peek(h)
+	if !ok {
+		return false
+	}
+	return now.Sub(v.(time.Time)) < f.ttl
+}
+
+// check checks whether the given message from the given originating peer should be accepted and
+// records it in case it should be. In case the message should be dropped, the reason is returned.
+func (f *messageFilter) check(peerID core.PeerID, rawSeqno []byte, h hash.Hash) (bool, string) {
+	if len(rawSeqno) != 8 {
+		// Messages without valid sequence numbers cannot be checked for replays.
+		return false, dropReasonReplay
+	}
+	seqno := binary.BigEndian.Uint64(rawSeqno)
+	now := time.Now()
+
+	f.Lock()
+	defer f.Unlock()
+
+	var pf *peerFilter
+	if v, ok := f.peers.Get(peerID); ok {
+		pf = v.(*peerFilter)
+	} else {
+		seen, _ := lru.New(lru.Capacity(maxFilterPeerMessages, false))
+		pf = &peerFilter{seen: seen}
+		_ = f.peers.Put(peerID, pf)
+	}
+
+	switch {
+	case !pf.checkSeqno(seqno):
+		return false, dropReasonReplay
+	case f.isSeen(pf.seen, h, now):
+		return false, dropReasonPeerDuplicate
+	case f.isSeen(f.global, h, now):
+		return false, dropReasonDuplicate
+	}
+
+	pf.recordSeqno(seqno)
+	_ = pf.seen.Put(h, now)
+	_ = f.global.Put(h, now)
+
+	return true, ""
+}
+
+func newMessageFilter(cacheSize uint64, ttl time.Duration) *messageFilter {
+	global, _ := lru.New(lru.Capacity(cacheSize, false))
+	peers, _ := lru.New(lru.Capacity(maxFilterPeers, false))
+
+	return &messageFilter{
+		ttl:    ttl,
+		global: global,
+		peers:  peers,
+	}
+}
@@ -0,0 +1,71 @@
+package p2p
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	core "github.com/libp2p/go-libp2p-core"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+func encodeSeqno(seqno uint64) []byte {
+	var raw [8]byte
+	binary.BigEndian.PutUint64(raw[:], seqno)
+	return raw[:]
+}
+
+func TestMessageFilter(t *testing.T) {
+	require := require.New(t)
+
+	f := newMessageFilter(16, time.Hour)
+	peerA := core.PeerID("peer A")
+	peerB := core.PeerID("peer B")
+	msgHash := func(n int) hash.Hash {
+		return hash.NewFromBytes([]byte{byte(n)})
+	}
+
+	ok, _ := f.check(peerA, encodeSeqno(100), msgHash(1))
+	require.True(ok, "new message should be accepted")
+
+	ok, reason := f.check(peerA, encodeSeqno(100), msgHash(2))
+	require.False(ok, "replayed sequence number should be rejected")
+	require.Equal(dropReasonReplay, reason)
+
+	ok, reason = f.check(peerA, encodeSeqno(101), msgHash(1))
+	require.False(ok, "duplicate message from the same peer should be rejected")
+	require.Equal(dropReasonPeerDuplicate, reason)
+
+	ok, reason = f.check(peerB, encodeSeqno(1), msgHash(1))
+	require.False(ok, "duplicate message from a different peer should be rejected")
+	require.Equal(dropReasonDuplicate, reason)
+
+	// Out-of-order sequence numbers within the window should be accepted once.
+	ok, _ = f.check(peerA, encodeSeqno(110), msgHash(3))
+	require.True(ok, "new message should be accepted")
+	ok, _ = f.check(peerA, encodeSeqno(105), msgHash(4))
+	require.True(ok, "out-of-order message within the window should be accepted")
+	ok, reason = f.check(peerA, encodeSeqno(105), msgHash(5))
+	require.False(ok, "replayed out-of-order sequence number should be rejected")
+	require.Equal(dropReasonReplay, reason)
+
+	// Sequence numbers that fall outside the window should be rejected.
+	ok, _ = f.check(peerA, encodeSeqno(110+replayWindowSize), msgHash(6))
+	require.True(ok, "new message should be accepted")
+	ok, reason = f.check(peerA, encodeSeqno(110), msgHash(7))
+	require.False(ok, "sequence number outside the window should be rejected")
+	require.Equal(dropReasonReplay, reason)
+
+	ok, reason = f.check(peerA, []byte{1, 2, 3}, msgHash(8))
+	require.False(ok, "invalid sequence number should be rejected")
+	require.Equal(dropReasonReplay, reason)
+
+	// Seen message hashes should expire.
+	f = newMessageFilter(16, 0)
+	ok, _ = f.check(peerA, encodeSeqno(1), msgHash(1))
+	require.True(ok, "new message should be accepted")
+	ok, _ = f.check(peerA, encodeSeqno(2), msgHash(1))
+	require.True(ok, "message should be accepted after the seen hash expired")
+}
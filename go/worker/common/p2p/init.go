@@ -1,6 +1,8 @@
 package p2p
 
 import (
+	"time"
+
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -18,6 +20,11 @@ const (
 	CfgP2PPeerOutboundQueueSize = "worker.p2p.peer_outbound_queue_size"
 	// CfgP2PValidateQueueSize sets the libp2p gossipsub buffer size of the validate queue.
 	CfgP2PValidateQueueSize = "worker.p2p.validate_queue_size"
+
+	// CfgP2PDedupCacheSize sets the maximum number of message hashes tracked for deduplication.
+	CfgP2PDedupCacheSize = "worker.p2p.dedup_cache_size"
+	// CfgP2PDedupTTL sets the time after which a seen message hash expires.
+	CfgP2PDedupTTL = "worker.p2p.dedup_ttl"
 )
 
 // Enabled reads our enabled flag from viper.
@@ -34,6 +41,8 @@ func init() {
 	Flags.StringSlice(cfgP2pAddresses, []string{}, "Address/port(s) to use for P2P connections when registering this node (if not set, all non-loopback local interfaces will be used)")
 	Flags.Int64(CfgP2PPeerOutboundQueueSize, 32, "Set libp2p gossipsub buffer size for outbound messages")
 	Flags.Int64(CfgP2PValidateQueueSize, 32, "Set libp2p gossipsub buffer size of the validate queue")
+	Flags.Uint64(CfgP2PDedupCacheSize, 16384, "Maximum number of message hashes tracked for deduplication")
+	Flags.Duration(CfgP2PDedupTTL, 10*time.Minute, "Time after which a seen message hash expires")
 
 	_ = viper.BindPFlags(Flags)
 }
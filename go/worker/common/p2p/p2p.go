@@ -16,6 +16,7 @@ import (
 	pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -239,6 +240,10 @@ func New(ctx context.Context, identity *identity.Identity, consensus consensus.B
 	}
 	p.host.Network().SetConnHandler(p.handleConnection)
 
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(p2pCollectors...)
+	})
+
 	p.logger.Info("p2p host initialized",
 		"address", fmt.Sprintf("%+v", host.Addrs()),
 	)
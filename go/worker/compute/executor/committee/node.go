@@ -32,6 +32,7 @@ import (
 	schedulingAPI "github.com/oasisprotocol/oasis-core/go/runtime/scheduling/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
@@ -833,6 +834,7 @@ func (n *Node) Dispatch(batch transaction.RawBatch) error {
 		DstRound:  lastHeader.Round + 1,
 		DstRoot:   ioRoot,
 		WriteLog:  ioWriteLog,
+		Attest:    true,
 	})
 	if err != nil {
 		spanInsert.Finish()
@@ -945,6 +947,36 @@ func (n *Node) maybeStartProcessingBatchLocked(batch *unresolvedBatch) {
 	}
 }
 
+// getStakingEvents returns the staking events affecting the runtime account that should be
+// delivered to the runtime when processing a batch on top of the block finalized at the given
+// consensus height. In case the runtime is not subscribed to staking events, nil is returned.
+func (n *Node) getStakingEvents(ctx context.Context, height int64) ([]*staking.Event, error) {
+	sub, err := n.commonNode.Consensus.RootHash().GetStakingEventSubscription(ctx, &roothash.RuntimeRequest{
+		RuntimeID: n.commonNode.Runtime.ID(),
+		Height:    height,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staking event subscription: %w", err)
+	}
+	if !sub.Subscribed {
+		return nil, nil
+	}
+
+	var events []*staking.Event
+	for h := sub.FromHeight + 1; h <= sub.ToHeight; h++ {
+		var evs []*staking.Event
+		if evs, err = n.commonNode.Consensus.Staking().GetEvents(ctx, h); err != nil {
+			return nil, fmt.Errorf("failed to query staking events at height %d: %w", h, err)
+		}
+		for _, ev := range evs {
+			if ev.AffectsAddress(sub.Address) {
+				events = append(events, ev)
+			}
+		}
+	}
+	return events, nil
+}
+
 // Guarded by n.commonNode.CrossNode.
 func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 	if n.commonNode.CurrentBlock == nil {
@@ -974,6 +1006,7 @@ func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 	// Request the worker host to process a batch. This is done in a separate
 	// goroutine so that the committee node can continue processing blocks.
 	blk := n.commonNode.CurrentBlock
+	height := n.commonNode.CurrentBlockHeight
 	go func() {
 		defer close(done)
 
@@ -987,11 +1020,20 @@ func (n *Node) startProcessingBatchLocked(batch *unresolvedBatch) {
 			)
 			return
 		}
+		stakingEvents, err := n.getStakingEvents(ctx, height)
+		if err != nil {
+			n.logger.Error("failed to fetch staking events",
+				"err", err,
+				"height", height,
+			)
+			return
+		}
 		rq := &protocol.Body{
 			RuntimeExecuteTxBatchRequest: &protocol.RuntimeExecuteTxBatchRequest{
-				IORoot: batch.ioRoot.Hash,
-				Inputs: resolvedBatch,
-				Block:  *blk,
+				IORoot:        batch.ioRoot.Hash,
+				Inputs:        resolvedBatch,
+				Block:         *blk,
+				StakingEvents: stakingEvents,
 			},
 		}
 		batchReadTime.With(n.getMetricLabels()).Observe(time.Since(readStartTime).Seconds())
@@ -1130,6 +1172,7 @@ func (n *Node) proposeBatchLocked(processedBatch *processedBatch) {
 			Namespace: lastHeader.Namespace,
 			DstRound:  lastHeader.Round + 1,
 			Ops:       applyOps,
+			Attest:    true,
 		})
 		if err != nil {
 			n.logger.Error("failed to apply to storage",
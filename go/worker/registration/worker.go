@@ -2,7 +2,10 @@ package registration
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -46,11 +49,25 @@ const (
 	// CfgRegistrationRotateCerts sets the number of epochs that a node's TLS
 	// certificate should be valid for.
 	CfgRegistrationRotateCerts = "worker.registration.rotate_certs"
+	// CfgRegistrationHeartbeatInterval sets the number of blocks between two
+	// node heartbeats.
+	CfgRegistrationHeartbeatInterval = "worker.registration.heartbeat_interval"
+	// CfgRegistrationAdditionalEntities configures additional entities on
+	// behalf of which the node should also register.
+	CfgRegistrationAdditionalEntities = "worker.registration.additional_entities"
+
+	// additionalEntitiesDir is the directory (relative to the node data
+	// directory) where node identities of additional entities are stored.
+	additionalEntitiesDir = "registration-entities"
 )
 
 var (
 	deregistrationRequestStoreKey = []byte("deregistration requested")
 
+	// ErrNotRunning is the error returned when the node registration is not running (e.g., because
+	// the node has no entity or deregistration has been requested).
+	ErrNotRunning = errors.New("worker/registration: node registration not running")
+
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
@@ -63,8 +80,17 @@ var (
 		},
 	)
 
+	workerNodeDeprecationWarnings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_node_deprecation_warning_count",
+			Help: "Number of deprecation warnings reported by the registry for the node's registrations.",
+		},
+		[]string{"field"},
+	)
+
 	nodeCollectors = []prometheus.Collector{
 		workerNodeRegistered,
+		workerNodeDeprecationWarnings,
 	}
 
 	metricsOnce sync.Once
@@ -136,6 +162,17 @@ func (rp *roleProvider) SetUnavailable() {
 	rp.SetAvailable(nil)
 }
 
+// additionalEntity is an additional entity on behalf of which the node registers.
+//
+// As the registry requires all node identity keys to be unique, a separate node identity is used
+// for each additional entity.
+type additionalEntity struct {
+	entityID signature.PublicKey
+	identity *identity.Identity
+
+	status control.EntityRegistrationStatus
+}
+
 // Worker is a service handling worker node registration.
 type Worker struct { // nolint: maligned
 	sync.RWMutex
@@ -150,6 +187,8 @@ type Worker struct { // nolint: maligned
 	entityID           signature.PublicKey
 	registrationSigner signature.Signer
 
+	additionalEntities []*additionalEntity
+
 	sentryAddresses []node.TLSAddress
 
 	runtimeRegistry runtimeRegistry.Registry
@@ -166,6 +205,10 @@ type Worker struct { // nolint: maligned
 	initialRegCh chan struct{} // closed after initial registration
 	stopRegCh    chan struct{} // closed internally to trigger clean registration lapse
 
+	runLock   sync.Mutex
+	runCh     chan struct{} // closed internally to restart the registration loops
+	restartCh chan struct{} // closed after the registration loops have been restarted
+
 	logger    *logging.Logger
 	consensus consensus.Backend
 
@@ -180,7 +223,7 @@ func DebugForceAllowUnroutableAddresses() {
 	allowUnroutableAddresses = true
 }
 
-func (w *Worker) registrationLoop() { // nolint: gocyclo
+func (w *Worker) registrationLoop(runCh <-chan struct{}) { // nolint: gocyclo
 	// If we have any sentry nodes, let them know about our TLS certs.
 	if len(w.sentryAddresses) > 0 {
 		pubKeys := w.identity.GetTLSPubKeys()
@@ -216,6 +259,8 @@ func (w *Worker) registrationLoop() { // nolint: gocyclo
 		select {
 		case <-w.stopCh:
 			return
+		case <-runCh:
+			return
 		case <-w.consensus.Synced():
 		}
 	}
@@ -251,6 +296,8 @@ func (w *Worker) registrationLoop() { // nolint: gocyclo
 			select {
 			case <-w.stopCh:
 				return context.Canceled
+			case <-runCh:
+				return context.Canceled
 			case epoch, ok = <-ch:
 				if !ok {
 					return context.Canceled
@@ -285,6 +332,8 @@ Loop:
 		case <-w.stopRegCh:
 			w.logger.Info("node deregistration and eventual shutdown requested")
 			return
+		case <-runCh:
+			return
 		case epoch = <-ch:
 			// Epoch updated, check if we can submit a registration.
 
@@ -404,10 +453,20 @@ Loop:
 			continue
 		}
 		if first {
-			close(w.initialRegCh)
+			// The initial registration channel may already be closed in case the registration
+			// loop has been restarted.
+			select {
+			case <-w.initialRegCh:
+			default:
+				close(w.initialRegCh)
+			}
 			first = false
 		}
 
+		// Register on behalf of any additional entities. Failures are not fatal as they should
+		// not affect the node's own registration.
+		w.registerAdditionalEntities(epoch, hook)
+
 		// Call any registration callbacks.
 		func() {
 			w.RLock()
@@ -439,12 +498,137 @@ Loop:
 	}
 }
 
+func (w *Worker) heartbeatLoop(runCh <-chan struct{}) {
+	// Wait for the initial registration.
+	select {
+	case <-w.stopCh:
+		return
+	case <-w.stopRegCh:
+		return
+	case <-runCh:
+		return
+	case <-w.initialRegCh:
+	}
+
+	doc, err := w.consensus.GetGenesisDocument(w.ctx)
+	if err != nil {
+		w.logger.Error("failed to get genesis document, not submitting heartbeats",
+			"err", err,
+		)
+		return
+	}
+	minInterval := doc.Registry.Parameters.MinHeartbeatInterval
+	if minInterval == 0 {
+		w.logger.Warn("node heartbeats are disabled, not submitting heartbeats")
+		return
+	}
+	interval := int64(viper.GetUint64(CfgRegistrationHeartbeatInterval))
+	if interval < minInterval {
+		interval = minInterval
+	}
+
+	blkCh, blkSub, err := w.consensus.WatchBlocks(w.ctx)
+	if err != nil {
+		w.logger.Error("failed to watch consensus blocks, not submitting heartbeats",
+			"err", err,
+		)
+		return
+	}
+	defer blkSub.Close()
+
+	nodeID := w.identity.NodeSigner.Public()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-w.stopRegCh:
+			return
+		case <-runCh:
+			return
+		case blk := <-blkCh:
+			status, serr := w.registry.GetNodeStatus(w.ctx, &registry.IDQuery{
+				Height: blk.Height,
+				ID:     nodeID,
+			})
+			if serr != nil {
+				w.logger.Error("failed to query node status",
+					"err", serr,
+				)
+				continue
+			}
+			// Include a margin of one block as the heartbeat will be included
+			// in one of the following blocks.
+			if status.LastHeartbeatHeight > 0 && blk.Height+1-status.LastHeartbeatHeight < interval {
+				continue
+			}
+
+			tx := registry.NewHeartbeatNodeTx(0, nil)
+			if err = consensus.SignAndSubmitTx(w.ctx, w.consensus, w.identity.NodeSigner, tx); err != nil {
+				w.logger.Error("failed to submit node heartbeat",
+					"err", err,
+				)
+				continue
+			}
+			w.logger.Debug("submitted node heartbeat",
+				"height", blk.Height,
+			)
+		}
+	}
+}
+
+func (w *Worker) deprecationWarningsLoop(runCh <-chan struct{}) {
+	ch, sub, err := w.registry.WatchDeprecationWarnings(w.ctx)
+	if err != nil {
+		w.logger.Error("failed to watch deprecation warnings",
+			"err", err,
+		)
+		return
+	}
+	defer sub.Close()
+
+	// Collect the identifiers of all nodes registered by this worker.
+	nodeIDs := map[signature.PublicKey]bool{
+		w.identity.NodeSigner.Public(): true,
+	}
+	for _, ae := range w.additionalEntities {
+		nodeIDs[ae.identity.NodeSigner.Public()] = true
+	}
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-runCh:
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			if ev.NodeID == nil || !nodeIDs[*ev.NodeID] {
+				continue
+			}
+
+			for _, warning := range ev.Warnings {
+				w.logger.Warn("node descriptor uses deprecated features, upgrade before they are rejected",
+					"entity_id", ev.EntityID,
+					"node_id", *ev.NodeID,
+					"field", warning.Field,
+					"warning", warning.Message,
+				)
+				workerNodeDeprecationWarnings.WithLabelValues(warning.Field).Inc()
+			}
+		}
+	}
+}
+
 func (w *Worker) doNodeRegistration() {
 	defer close(w.quitCh)
 	defer workerNodeRegistered.Set(0.0)
 
 	if !w.storedDeregister {
-		w.registrationLoop()
+		for w.runRegistration() {
+			w.logger.Info("node registration restarted")
+		}
 	}
 
 	// Loop broken; shutdown requested.
@@ -490,6 +674,79 @@ func (w *Worker) doNodeRegistration() {
 	}
 }
 
+// runRegistration runs the registration loops until they are either stopped, in which case it
+// returns false, or a restart is requested, in which case it returns true after all of the loops
+// have terminated.
+func (w *Worker) runRegistration() bool {
+	w.runLock.Lock()
+	runCh := make(chan struct{})
+	w.runCh = runCh
+	if w.restartCh != nil {
+		close(w.restartCh)
+		w.restartCh = nil
+	}
+	w.runLock.Unlock()
+
+	var wg sync.WaitGroup
+	if w.consensus != nil && viper.GetUint64(CfgRegistrationHeartbeatInterval) != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.heartbeatLoop(runCh)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.deprecationWarningsLoop(runCh)
+	}()
+	w.registrationLoop(runCh)
+
+	w.runLock.Lock()
+	w.runCh = nil
+	w.runLock.Unlock()
+
+	select {
+	case <-runCh:
+	default:
+		// Registration loop terminated due to a stop or deregistration request.
+		return false
+	}
+
+	// Make sure none of the loops from this run are left behind.
+	wg.Wait()
+	return true
+}
+
+// Restart restarts the node registration loops without affecting the rest of the node.
+//
+// The node registration status and role providers are preserved and the node is re-registered
+// as soon as all role providers are available.
+func (w *Worker) Restart(ctx context.Context) error {
+	w.runLock.Lock()
+	runCh := w.runCh
+	if runCh == nil {
+		w.runLock.Unlock()
+		return ErrNotRunning
+	}
+	restartCh := make(chan struct{})
+	w.runCh = nil
+	w.restartCh = restartCh
+	close(runCh)
+	w.runLock.Unlock()
+
+	w.logger.Info("restarting node registration")
+
+	select {
+	case <-restartCh:
+		return nil
+	case <-w.quitCh:
+		return ErrNotRunning
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (w *Worker) registrationStopped() {
 	if w.delegate != nil {
 		w.delegate.RegistrationStopped()
@@ -503,6 +760,13 @@ func (w *Worker) GetRegistrationStatus(ctx context.Context) (*control.Registrati
 
 	status := new(control.RegistrationStatus)
 	*status = w.status
+	if len(w.additionalEntities) > 0 {
+		status.AdditionalEntities = make(map[signature.PublicKey]*control.EntityRegistrationStatus)
+		for _, ae := range w.additionalEntities {
+			aeStatus := ae.status
+			status.AdditionalEntities[ae.entityID] = &aeStatus
+		}
+	}
 	return status, nil
 }
 
@@ -649,37 +913,41 @@ func (w *Worker) gatherTLSAddresses(sentryTLSAddrs []node.TLSAddress) ([]node.TL
 	return validatedAddrs, nil
 }
 
-func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook) error {
-	identityPublic := w.identity.NodeSigner.Public()
-	w.logger.Info("performing node (re-)registration",
-		"epoch", epoch,
-		"node_id", identityPublic.String(),
-	)
-
+// newNodeDescriptor creates a new node descriptor for the given identity and owning entity and
+// applies the given hook to it.
+func (w *Worker) newNodeDescriptor(
+	epoch epochtime.EpochTime,
+	ident *identity.Identity,
+	entityID signature.PublicKey,
+	hook RegisterNodeHook,
+) (*node.Node, error) {
 	var nextPubKey signature.PublicKey
-	if s := w.identity.GetNextTLSSigner(); s != nil {
+	if s := ident.GetNextTLSSigner(); s != nil {
 		nextPubKey = s.Public()
 	}
 
 	nodeDesc := node.Node{
 		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
-		ID:         identityPublic,
-		EntityID:   w.entityID,
+		ID:         ident.NodeSigner.Public(),
+		EntityID:   entityID,
 		Expiration: uint64(epoch) + 2,
 		TLS: node.TLSInfo{
-			PubKey:     w.identity.GetTLSSigner().Public(),
+			PubKey:     ident.GetTLSSigner().Public(),
 			NextPubKey: nextPubKey,
 		},
 		P2P: node.P2PInfo{
-			ID: w.identity.P2PSigner.Public(),
+			ID: ident.P2PSigner.Public(),
 		},
 		Consensus: node.ConsensusInfo{
-			ID: w.identity.ConsensusSigner.Public(),
+			ID: ident.ConsensusSigner.Public(),
+		},
+		VRF: &node.VRFInfo{
+			ID: ident.VRFKey.Public(),
 		},
 	}
 
 	if err := hook(&nodeDesc); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Sanity check to prevent an invalid registration when no role provider added any runtimes but
@@ -688,7 +956,123 @@ func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook)
 		w.logger.Error("not registering: no runtimes provided while runtimes are required",
 			"node_descriptor", nodeDesc,
 		)
-		return fmt.Errorf("registration: no runtimes provided while runtimes are required")
+		return nil, fmt.Errorf("registration: no runtimes provided while runtimes are required")
+	}
+
+	return &nodeDesc, nil
+}
+
+// registerAdditionalEntities performs node (re-)registration on behalf of all additional entities.
+func (w *Worker) registerAdditionalEntities(epoch epochtime.EpochTime, hook RegisterNodeHook) {
+	for _, ae := range w.additionalEntities {
+		nodeDesc, err := w.registerAdditionalEntity(epoch, ae, hook)
+
+		w.Lock()
+		switch err {
+		case nil:
+			ae.status.LastRegistration = time.Now()
+			ae.status.LastError = ""
+			ae.status.Descriptor = nodeDesc
+		default:
+			ae.status.LastError = err.Error()
+		}
+		w.Unlock()
+
+		if err != nil {
+			w.logger.Error("failed to register node on behalf of additional entity",
+				"err", err,
+				"entity_id", ae.entityID,
+				"node_id", ae.identity.NodeSigner.Public(),
+			)
+		}
+	}
+}
+
+func (w *Worker) registerAdditionalEntity(
+	epoch epochtime.EpochTime,
+	ae *additionalEntity,
+	hook RegisterNodeHook,
+) (*node.Node, error) {
+	w.logger.Info("performing node (re-)registration on behalf of additional entity",
+		"epoch", epoch,
+		"entity_id", ae.entityID,
+		"node_id", ae.identity.NodeSigner.Public(),
+	)
+
+	// Check if the entity under which we are registering actually exists.
+	if _, err := w.registry.GetEntity(w.ctx, &registry.IDQuery{
+		Height: consensus.HeightLatest,
+		ID:     ae.entityID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query owning entity: %w", err)
+	}
+
+	nodeDesc, err := w.newNodeDescriptor(epoch, ae.identity, ae.entityID, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the node's own registration can be a validator as the consensus identity is bound to
+	// the node's own identity.
+	nodeDesc.Roles &^= node.RoleValidator
+	if nodeDesc.Roles == 0 {
+		return nil, fmt.Errorf("registration: no roles that are allowed for additional entities")
+	}
+
+	// Addresses are shared with the node's own registration.
+	w.RLock()
+	ownDesc := w.status.Descriptor
+	w.RUnlock()
+	if ownDesc != nil {
+		if nodeDesc.HasRoles(registry.TLSAddressRequiredRoles) {
+			for _, addr := range ownDesc.TLS.Addresses {
+				if !addr.PubKey.Equal(w.identity.GetTLSSigner().Public()) {
+					continue
+				}
+				nodeDesc.TLS.Addresses = append(nodeDesc.TLS.Addresses, node.TLSAddress{
+					PubKey:  ae.identity.GetTLSSigner().Public(),
+					Address: addr.Address,
+				})
+			}
+		}
+		if nodeDesc.HasRoles(registry.P2PAddressRequiredRoles) {
+			nodeDesc.P2P.Addresses = ownDesc.P2P.Addresses
+		}
+	}
+
+	// Registrations on behalf of additional entities are always node-signed.
+	nodeSigners := []signature.Signer{
+		ae.identity.NodeSigner,
+		ae.identity.P2PSigner,
+		ae.identity.ConsensusSigner,
+		ae.identity.GetTLSSigner(),
+	}
+	sigNode, err := node.MultiSignNode(nodeSigners, registry.RegisterNodeSignatureContext, nodeDesc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign node descriptor: %w", err)
+	}
+
+	tx := registry.NewRegisterNodeTx(0, nil, sigNode)
+	if err = consensus.SignAndSubmitTx(w.ctx, w.consensus, ae.identity.NodeSigner, tx); err != nil {
+		return nil, err
+	}
+
+	w.logger.Info("node registered with the registry on behalf of additional entity",
+		"entity_id", ae.entityID,
+	)
+	return nodeDesc, nil
+}
+
+func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook) error {
+	identityPublic := w.identity.NodeSigner.Public()
+	w.logger.Info("performing node (re-)registration",
+		"epoch", epoch,
+		"node_id", identityPublic.String(),
+	)
+
+	nodeDesc, err := w.newNodeDescriptor(epoch, w.identity, w.entityID, hook)
+	if err != nil {
+		return err
 	}
 
 	var sentryConsensusAddrs []node.ConsensusAddress
@@ -733,7 +1117,7 @@ func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook)
 		nodeSigners = append([]signature.Signer{w.identity.NodeSigner}, nodeSigners...)
 	}
 
-	sigNode, err := node.MultiSignNode(nodeSigners, registry.RegisterNodeSignatureContext, &nodeDesc)
+	sigNode, err := node.MultiSignNode(nodeSigners, registry.RegisterNodeSignatureContext, nodeDesc)
 	if err != nil {
 		w.logger.Error("failed to register node: unable to sign node descriptor",
 			"err", err,
@@ -742,7 +1126,7 @@ func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook)
 	}
 
 	tx := registry.NewRegisterNodeTx(0, nil, sigNode)
-	if err := consensus.SignAndSubmitTx(w.ctx, w.consensus, w.registrationSigner, tx); err != nil {
+	if err = consensus.SignAndSubmitTx(w.ctx, w.consensus, w.registrationSigner, tx); err != nil {
 		w.logger.Error("failed to register node",
 			"err", err,
 		)
@@ -752,7 +1136,7 @@ func (w *Worker) registerNode(epoch epochtime.EpochTime, hook RegisterNodeHook)
 	// Update the registration status on successful registration.
 	w.RLock()
 	w.status.LastRegistration = time.Now()
-	w.status.Descriptor = &nodeDesc
+	w.status.Descriptor = nodeDesc
 	w.RUnlock()
 
 	w.logger.Info("node registered with the registry")
@@ -912,6 +1296,65 @@ func GetRegistrationSigner(logger *logging.Logger, dataDir string, identity *ide
 	return entity.ID, entitySigner, nil
 }
 
+// loadAdditionalEntities loads the additional entities on behalf of which the node should also
+// register, generating a separate node identity for each of them as needed.
+func loadAdditionalEntities(
+	logger *logging.Logger,
+	dataDir string,
+	primaryEntityID signature.PublicKey,
+) ([]*additionalEntity, error) {
+	var additionalEntities []*additionalEntity
+	seen := make(map[signature.PublicKey]bool)
+	for _, f := range viper.GetStringSlice(CfgRegistrationAdditionalEntities) {
+		ent, err := entity.LoadDescriptor(f)
+		if err != nil {
+			return nil, fmt.Errorf("worker/registration: failed to load additional entity descriptor: %w", err)
+		}
+		if ent.ID.Equal(primaryEntityID) || seen[ent.ID] {
+			return nil, fmt.Errorf("worker/registration: duplicate entity in registration config: %s", ent.ID)
+		}
+		seen[ent.ID] = true
+
+		identityDir := filepath.Join(dataDir, additionalEntitiesDir, hex.EncodeToString(ent.ID[:]))
+		if err = common.Mkdir(identityDir); err != nil {
+			return nil, fmt.Errorf("worker/registration: failed to create additional entity directory: %w", err)
+		}
+		signerFactory, err := fileSigner.NewFactory(identityDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
+		if err != nil {
+			return nil, fmt.Errorf("worker/registration: failed to create additional entity signer factory: %w", err)
+		}
+		ident, err := identity.LoadOrGenerate(identityDir, signerFactory, false)
+		if err != nil {
+			return nil, fmt.Errorf("worker/registration: failed to load additional entity node identity: %w", err)
+		}
+
+		nodeID := ident.NodeSigner.Public()
+		var allowed bool
+		for _, v := range ent.Nodes {
+			if v.Equal(nodeID) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			// The local copy of the entity descriptor may just be stale, so this is not fatal.
+			logger.Warn("node identity for additional entity is not in the entity's list of nodes",
+				"entity_id", ent.ID,
+				"node_id", nodeID,
+			)
+		}
+
+		additionalEntities = append(additionalEntities, &additionalEntity{
+			entityID: ent.ID,
+			identity: ident,
+			status: control.EntityRegistrationStatus{
+				NodeID: nodeID,
+			},
+		})
+	}
+	return additionalEntities, nil
+}
+
 // New constructs a new worker node registration service.
 func New(
 	dataDir string,
@@ -940,6 +1383,11 @@ func New(
 		return nil, err
 	}
 
+	additionalEntities, err := loadAdditionalEntities(logger, dataDir, entityID)
+	if err != nil {
+		return nil, err
+	}
+
 	storedDeregister := false
 	err = serviceStore.GetCBOR(deregistrationRequestStoreKey, &storedDeregister)
 	if err != nil && err != persistent.ErrNotFound {
@@ -966,6 +1414,7 @@ func New(
 		entityID:           entityID,
 		sentryAddresses:    workerCommonCfg.SentryAddresses,
 		registrationSigner: registrationSigner,
+		additionalEntities: additionalEntities,
 		runtimeRegistry:    runtimeRegistry,
 		epochtime:          epochtime,
 		registry:           registry,
@@ -1045,6 +1494,8 @@ func init() {
 	Flags.String(CfgDebugRegistrationPrivateKey, "", "private key to use to sign node registrations")
 	Flags.Bool(CfgRegistrationForceRegister, false, "override a previously saved deregistration request")
 	Flags.Uint64(CfgRegistrationRotateCerts, 0, "rotate node TLS certificates every N epochs (0 to disable)")
+	Flags.Uint64(CfgRegistrationHeartbeatInterval, 0, "submit a node heartbeat every N blocks (0 to disable)")
+	Flags.StringSlice(CfgRegistrationAdditionalEntities, nil, "additional entities on behalf of which to also register the node")
 	_ = Flags.MarkHidden(CfgDebugRegistrationPrivateKey)
 
 	_ = viper.BindPFlags(Flags)
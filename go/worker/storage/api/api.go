@@ -6,13 +6,19 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 // ModuleName is the storage worker module name.
 const ModuleName = "worker/storage"
 
-// ErrRuntimeNotFound is the error returned when the called references an unknown runtime.
-var ErrRuntimeNotFound = errors.New(ModuleName, 1, "worker/storage: runtime not found")
+var (
+	// ErrRuntimeNotFound is the error returned when the called references an unknown runtime.
+	ErrRuntimeNotFound = errors.New(ModuleName, 1, "worker/storage: runtime not found")
+	// ErrGCNotSupported is the error returned when the storage backend of a runtime does not
+	// support triggering garbage collection on demand.
+	ErrGCNotSupported = errors.New(ModuleName, 2, "worker/storage: garbage collection not supported")
+)
 
 // StorageWorker is the storage worker control API interface.
 type StorageWorker interface {
@@ -21,6 +27,19 @@ type StorageWorker interface {
 
 	// ForceFinalize forces finalization of a specific round.
 	ForceFinalize(ctx context.Context, request *ForceFinalizeRequest) error
+
+	// CreateCheckpoint creates checkpoints for a specific finalized round on demand.
+	//
+	// Such checkpoints are not garbage collected and are kept until they are removed via
+	// DeleteCheckpoint.
+	CreateCheckpoint(ctx context.Context, request *CheckpointRequest) ([]*checkpoint.Metadata, error)
+
+	// DeleteCheckpoint removes checkpoints for a specific round that have been created on demand.
+	DeleteCheckpoint(ctx context.Context, request *CheckpointRequest) error
+
+	// TriggerGC runs garbage collection of the storage database of a specific runtime immediately
+	// (e.g., during off-peak hours) and waits for it to complete.
+	TriggerGC(ctx context.Context, request *TriggerGCRequest) (*TriggerGCResponse, error)
 }
 
 // GetLastSyncedRoundRequest is a GetLastSyncedRound request.
@@ -41,6 +60,23 @@ type ForceFinalizeRequest struct {
 	Round     uint64           `json:"round"`
 }
 
+// CheckpointRequest is a CreateCheckpoint or DeleteCheckpoint request.
+type CheckpointRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+}
+
+// TriggerGCRequest is a TriggerGC request.
+type TriggerGCRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+}
+
+// TriggerGCResponse is a TriggerGC response.
+type TriggerGCResponse struct {
+	// Rewrites is the number of database log files that have been rewritten.
+	Rewrites uint64 `json:"rewrites"`
+}
+
 // Status is the storage worker status.
 type Status struct {
 	// LastFinalizedRound is the last synced and finalized round.
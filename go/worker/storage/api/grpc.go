@@ -6,6 +6,7 @@ import (
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 )
 
 var (
@@ -16,6 +17,12 @@ var (
 	methodGetLastSyncedRound = serviceName.NewMethod("GetLastSyncedRound", &GetLastSyncedRoundRequest{})
 	// methodForceFinalize is the ForceFinalize method.
 	methodForceFinalize = serviceName.NewMethod("ForceFinalize", &ForceFinalizeRequest{})
+	// methodCreateCheckpoint is the CreateCheckpoint method.
+	methodCreateCheckpoint = serviceName.NewMethod("CreateCheckpoint", &CheckpointRequest{})
+	// methodDeleteCheckpoint is the DeleteCheckpoint method.
+	methodDeleteCheckpoint = serviceName.NewMethod("DeleteCheckpoint", &CheckpointRequest{})
+	// methodTriggerGC is the TriggerGC method.
+	methodTriggerGC = serviceName.NewMethod("TriggerGC", &TriggerGCRequest{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -30,6 +37,18 @@ var (
 				MethodName: methodForceFinalize.ShortName(),
 				Handler:    handlerForceFinalize,
 			},
+			{
+				MethodName: methodCreateCheckpoint.ShortName(),
+				Handler:    handlerCreateCheckpoint,
+			},
+			{
+				MethodName: methodDeleteCheckpoint.ShortName(),
+				Handler:    handlerDeleteCheckpoint,
+			},
+			{
+				MethodName: methodTriggerGC.ShortName(),
+				Handler:    handlerTriggerGC,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -81,6 +100,75 @@ func handlerForceFinalize( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerCreateCheckpoint( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(CheckpointRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageWorker).CreateCheckpoint(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCreateCheckpoint.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageWorker).CreateCheckpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerDeleteCheckpoint( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(CheckpointRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(StorageWorker).DeleteCheckpoint(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDeleteCheckpoint.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(StorageWorker).DeleteCheckpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerTriggerGC( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(TriggerGCRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageWorker).TriggerGC(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodTriggerGC.FullName(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageWorker).TriggerGC(ctx, req.(*TriggerGCRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 // RegisterService registers a new storage worker service with the given gRPC server.
 func RegisterService(server *grpc.Server, service StorageWorker) {
 	server.RegisterService(&serviceDesc, service)
@@ -102,6 +190,26 @@ func (c *storageWorkerClient) ForceFinalize(ctx context.Context, req *ForceFinal
 	return c.conn.Invoke(ctx, methodForceFinalize.FullName(), req, nil)
 }
 
+func (c *storageWorkerClient) CreateCheckpoint(ctx context.Context, req *CheckpointRequest) ([]*checkpoint.Metadata, error) {
+	var rsp []*checkpoint.Metadata
+	if err := c.conn.Invoke(ctx, methodCreateCheckpoint.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *storageWorkerClient) DeleteCheckpoint(ctx context.Context, req *CheckpointRequest) error {
+	return c.conn.Invoke(ctx, methodDeleteCheckpoint.FullName(), req, nil)
+}
+
+func (c *storageWorkerClient) TriggerGC(ctx context.Context, req *TriggerGCRequest) (*TriggerGCResponse, error) {
+	var rsp TriggerGCResponse
+	if err := c.conn.Invoke(ctx, methodTriggerGC.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 // NewStorageWorkerClient creates a new gRPC transaction scheduler
 // client service.
 func NewStorageWorkerClient(c *grpc.ClientConn) StorageWorker {
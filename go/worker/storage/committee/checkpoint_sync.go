@@ -6,7 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"net/http"
 	"sort"
 	"sync"
 	"time"
@@ -14,6 +14,9 @@ import (
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	registryApi "github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/committee"
 	schedulerApi "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
@@ -29,6 +32,10 @@ const (
 	checkpointStatusNext = 1
 	checkpointStatusBail = 2
 
+	// checkpointStoreTimeout is the timeout for fetching a single chunk from an external
+	// checkpoint store.
+	checkpointStoreTimeout = 1 * time.Minute
+
 	// LogEventCheckpointSyncSuccess is a log event value that signals that checkpoint sync was successful.
 	LogEventCheckpointSyncSuccess = "worker/storage/checkpoint-sync-success"
 )
@@ -36,9 +43,43 @@ const (
 // ErrNoUsableCheckpoints is the error returned when none of the checkpoints could be synced.
 var ErrNoUsableCheckpoints = errors.New("storage: no checkpoint could be synced")
 
+var checkpointStoreClient = &http.Client{Timeout: checkpointStoreTimeout}
+
+// CheckpointSyncConfig is the checkpoint sync configuration.
+type CheckpointSyncConfig struct {
+	// Disabled specifies whether checkpoint sync should be disabled. In this case the node will
+	// only sync by applying all diffs from genesis.
+	Disabled bool
+
+	// ChunkFetchersPerNode is the number of chunks that are fetched concurrently from each
+	// storage committee node.
+	ChunkFetchersPerNode uint
+
+	// RestoreWorkers is the number of chunks that are restored into the local node database
+	// concurrently.
+	RestoreWorkers uint
+
+	// MemoryBudget is the maximum amount of memory (in bytes) used for buffering fetched chunks
+	// that are waiting to be restored.
+	MemoryBudget uint64
+
+	// ExternalStoresDisabled specifies whether fetching chunks directly from external checkpoint
+	// stores advertised by storage committee nodes should be disabled.
+	ExternalStoresDisabled bool
+}
+
+// fetchedChunk is a checkpoint chunk that has been fetched but not yet restored.
+type fetchedChunk struct {
+	chunk *checkpoint.ChunkMetadata
+	node  signature.PublicKey
+	data  []byte
+}
+
 type restoreResult struct {
-	done bool
-	err  error
+	chunk *checkpoint.ChunkMetadata
+	node  signature.PublicKey
+	done  bool
+	err   error
 }
 
 type chunkHeap struct {
@@ -62,9 +103,15 @@ func (h *chunkHeap) Pop() interface{} {
 	return ret
 }
 
-// goWithCommittee runs the given operation with all the connections to the storage committee.
+// goWithCommittee runs the given operation with all the connections to the storage committee,
+// using the given number of workers per connection.
+//
+// In case prefer is non-nil, only the connections for which it returns true are used, unless
+// there are no such connections in which case all connections are used.
 func (n *Node) goWithCommittee(
 	committeeClient committee.Client,
+	workersPerConn uint,
+	prefer func(*committee.ClientConnWithMeta) bool,
 	fn func(context.Context, *committee.ClientConnWithMeta) error,
 ) (
 	context.CancelFunc,
@@ -77,6 +124,17 @@ func (n *Node) goWithCommittee(
 		if len(conns) == 0 {
 			return storageClient.ErrStorageNotAvailable
 		}
+		if prefer != nil {
+			var preferred []*committee.ClientConnWithMeta
+			for _, conn := range conns {
+				if prefer(conn) {
+					preferred = append(preferred, conn)
+				}
+			}
+			if len(preferred) > 0 {
+				conns = preferred
+			}
+		}
 		connCh <- conns
 		return nil
 	}
@@ -89,21 +147,26 @@ func (n *Node) goWithCommittee(
 	if !ok || len(conns) == 0 {
 		return nil, nil, storageClient.ErrStorageNotAvailable
 	}
+	if workersPerConn == 0 {
+		workersPerConn = 1
+	}
 
 	workerCtx, workerCancel := context.WithCancel(n.ctx)
 	var workerGroup sync.WaitGroup
 	doneCh := make(chan interface{})
 
 	for _, conn := range conns {
-		workerGroup.Add(1)
-		go func(conn *committee.ClientConnWithMeta) {
-			defer workerGroup.Done()
-			op := func() error {
-				return fn(workerCtx, conn)
-			}
-			sched := backoff.WithMaxRetries(backoff.NewConstantBackOff(retryInterval), maxRetries)
-			_ = backoff.Retry(op, backoff.WithContext(sched, workerCtx))
-		}(conn)
+		for i := uint(0); i < workersPerConn; i++ {
+			workerGroup.Add(1)
+			go func(conn *committee.ClientConnWithMeta) {
+				defer workerGroup.Done()
+				op := func() error {
+					return fn(workerCtx, conn)
+				}
+				sched := backoff.WithMaxRetries(backoff.NewConstantBackOff(retryInterval), maxRetries)
+				_ = backoff.Retry(op, backoff.WithContext(sched, workerCtx))
+			}(conn)
+		}
 	}
 	go func() {
 		defer close(doneCh)
@@ -113,95 +176,181 @@ func (n *Node) goWithCommittee(
 	return workerCancel, doneCh, nil
 }
 
-func (n *Node) nodeWorker(
+// chunkFetcher fetches chunks dispatched by the checkpoint sync driver from the given storage
+// committee node and forwards them to the restore workers.
+//
+// In case the node advertises an external checkpoint store for the checkpoint (storeURL is
+// non-empty), chunks are first fetched from the store, falling back to the node itself.
+//
+// Before fetching a chunk, the fetcher must acquire a slot in the given memory budget semaphore.
+// The slot is released by the restore worker once the chunk has been restored, or by the fetcher
+// itself in case the chunk could not be fetched.
+func (n *Node) chunkFetcher(
 	ctx context.Context,
 	conn *committee.ClientConnWithMeta,
-	chunkDispatchCh chan *checkpoint.ChunkMetadata,
-	chunkReturnCh chan *checkpoint.ChunkMetadata,
-	errorCh chan int,
+	storeURL string,
+	chunkDispatchCh <-chan *checkpoint.ChunkMetadata,
+	chunkReturnCh chan<- *checkpoint.ChunkMetadata,
+	fetchedCh chan<- *fetchedChunk,
+	slots chan struct{},
 ) error {
 	api := storageApi.NewStorageClient(conn.ClientConn)
 	for {
+		// Wait for buffer space to become available before taking a chunk so that we don't
+		// hold on to chunks that could be fetched by other workers.
+		select {
+		case <-ctx.Done():
+			return backoff.Permanent(ctx.Err())
+		case slots <- struct{}{}:
+		}
+
 		var chunk *checkpoint.ChunkMetadata
 		var ok bool
 		select {
 		case <-ctx.Done():
+			<-slots
 			return backoff.Permanent(ctx.Err())
 		case chunk, ok = <-chunkDispatchCh:
 			if !ok {
+				<-slots
 				return nil
 			}
 		}
 
-		restoreCh := make(chan *restoreResult)
-		rd, wr := io.Pipe()
-		go func() {
-			done, err := n.localStorage.Checkpointer().RestoreChunk(ctx, chunk.Index, rd)
-			restoreCh <- &restoreResult{
-				done: done,
-				err:  err,
+		var buf bytes.Buffer
+		if storeURL != "" {
+			if err := checkpoint.GetChunkFromURL(ctx, checkpointStoreClient, storeURL, chunk, &buf); err != nil {
+				n.logger.Warn("can't fetch chunk from external checkpoint store, falling back to node",
+					"node", conn.Node.ID,
+					"store_url", storeURL,
+					"chunk", chunk.Index,
+					"err", err,
+				)
+				buf.Reset()
+			} else {
+				select {
+				case <-ctx.Done():
+					<-slots
+					return backoff.Permanent(ctx.Err())
+				case fetchedCh <- &fetchedChunk{chunk: chunk, node: conn.Node.ID, data: buf.Bytes()}:
+				}
+				continue
 			}
-		}()
-		err := api.GetCheckpointChunk(ctx, chunk, wr)
-		wr.Close()
-		result := <-restoreCh
-
-		// GetCheckpointChunk errors.
-		// The chunk probably always needs to be returned here
-		// (otherwise there's a deadlock risk with one worker's backoff just aborting
-		// and another worker then blocking on its chunk).
-		switch {
-		case err == nil:
-			// Fall out of the switch.
-		case err != nil:
-			n.logger.Error("can't fetch chunk from storage node", "node", conn.Node.ID, "chunk", chunk.Index, "err", err)
-			chunkReturnCh <- chunk
-			fallthrough
-		case errors.Is(err, checkpoint.ErrChunkNotFound):
-			return backoff.Permanent(err)
-		default:
-			return err
 		}
-
-		// RestoreChunk errors.
-		switch {
-		case result.done:
-			// Signal to the toplevel handler that we're done.
-			chunkReturnCh <- nil
-			return nil
-		case result.err != nil:
-			n.logger.Error("chunk restoration failed",
+		if err := api.GetCheckpointChunk(ctx, chunk, &buf); err != nil {
+			<-slots
+			n.logger.Error("can't fetch chunk from storage node",
 				"node", conn.Node.ID,
 				"chunk", chunk.Index,
-				"root", chunk.Root,
-				"err", result.err,
+				"err", err,
 			)
-			fallthrough
-		case errors.Is(result.err, checkpoint.ErrChunkCorrupted):
-			chunkReturnCh <- chunk
-			return result.err
-		case errors.Is(result.err, checkpoint.ErrChunkProofVerificationFailed):
-			errorCh <- checkpointStatusNext
-			return backoff.Permanent(result.err)
-		case result.err != nil:
-			errorCh <- checkpointStatusBail
-			return backoff.Permanent(result.err)
+
+			// The chunk always needs to be returned here, otherwise it would never be retried.
+			select {
+			case <-ctx.Done():
+				return backoff.Permanent(ctx.Err())
+			case chunkReturnCh <- chunk:
+			}
+
+			if errors.Is(err, checkpoint.ErrChunkNotFound) {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			<-slots
+			return backoff.Permanent(ctx.Err())
+		case fetchedCh <- &fetchedChunk{chunk: chunk, node: conn.Node.ID, data: buf.Bytes()}:
 		}
 	}
 }
 
-func (n *Node) handleCheckpoint(check *checkpoint.Metadata, committeeClient committee.Client, groupSize uint64) (int, error) {
+// chunkRestorer restores fetched chunks into the local node database. Multiple restorers may run
+// concurrently, each restoring its chunk in a separate multipart batch.
+func (n *Node) chunkRestorer(
+	ctx context.Context,
+	fetchedCh <-chan *fetchedChunk,
+	resultCh chan<- *restoreResult,
+	slots chan struct{},
+) {
+	for {
+		var fc *fetchedChunk
+		select {
+		case <-ctx.Done():
+			return
+		case fc = <-fetchedCh:
+		}
+
+		done, err := n.localStorage.Checkpointer().RestoreChunk(ctx, fc.chunk.Index, bytes.NewReader(fc.data))
+		// Release the buffer space held by the chunk.
+		fc.data = nil
+		<-slots
+
+		select {
+		case <-ctx.Done():
+			return
+		case resultCh <- &restoreResult{chunk: fc.chunk, node: fc.node, done: done, err: err}:
+		}
+	}
+}
+
+func (n *Node) handleCheckpoint(
+	check *checkpoint.Metadata,
+	committeeClient committee.Client,
+	rt *registryApi.Runtime,
+) (int, error) {
+	// Make sure the checkpoint is meant for this network and runtime before starting to fetch any
+	// chunks.
+	chainContext, err := n.getChainContext(n.ctx)
+	if err != nil {
+		return checkpointStatusBail, err
+	}
+	cpParams := checkpointParameters(rt)
+	if err = check.VerifyOrigin(chainContext, rt.ID, &cpParams); err != nil {
+		return checkpointStatusNext, err
+	}
+
+	params := &rt.Storage
+	cfg := n.checkpointSyncCfg
+	restoreWorkers := cfg.RestoreWorkers
+	if restoreWorkers == 0 {
+		restoreWorkers = 1
+	}
+	// Limit the number of chunks buffered in memory based on the configured memory budget.
+	maxBufferedChunks := uint64(1)
+	if params.CheckpointChunkSize > 0 && cfg.MemoryBudget/params.CheckpointChunkSize > 1 {
+		maxBufferedChunks = cfg.MemoryBudget / params.CheckpointChunkSize
+	}
+
 	chunkDispatchCh := make(chan *checkpoint.ChunkMetadata)
 	defer close(chunkDispatchCh)
 
-	chunkReturnCh := make(chan *checkpoint.ChunkMetadata, groupSize)
-	errorCh := make(chan int, groupSize)
+	chunkReturnCh := make(chan *checkpoint.ChunkMetadata, params.GroupSize)
+	fetchedCh := make(chan *fetchedChunk)
+	resultCh := make(chan *restoreResult)
+	slots := make(chan struct{}, maxBufferedChunks)
+
+	// Plan the source set up front: prefer the committee nodes that advertise the checkpoint and
+	// only fall back to all nodes in case none of them do.
+	available := n.getCheckpointAvailability(committeeClient).Get(check.EncodedHash())
+	var prefer func(*committee.ClientConnWithMeta) bool
+	if available != nil {
+		prefer = func(conn *committee.ClientConnWithMeta) bool {
+			return available.HasSource(conn.Node.ID)
+		}
+		n.logger.Debug("checkpoint advertised by committee nodes",
+			"checkpoint_root", check.Root,
+			"num_sources", len(available.Sources),
+		)
+	}
 
-	worker := func(ctx context.Context, conn *committee.ClientConnWithMeta) error {
-		return n.nodeWorker(ctx, conn, chunkDispatchCh, chunkReturnCh, errorCh)
+	fetcher := func(ctx context.Context, conn *committee.ClientConnWithMeta) error {
+		return n.chunkFetcher(ctx, conn, n.getCheckpointStoreURL(conn, available), chunkDispatchCh, chunkReturnCh, fetchedCh, slots)
 	}
 
-	cancel, doneCh, err := n.goWithCommittee(committeeClient, worker)
+	cancel, doneCh, err := n.goWithCommittee(committeeClient, cfg.ChunkFetchersPerNode, prefer, fetcher)
 	if err != nil {
 		return checkpointStatusBail, fmt.Errorf("can't fetch chunks from committee nodes: %w", err)
 	}
@@ -214,6 +363,22 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, committeeClient comm
 		return checkpointStatusBail, fmt.Errorf("can't start checkpoint restore: %w", err)
 	}
 
+	// Start the restore workers. Make sure they have all terminated before returning so that no
+	// chunks are being restored when the caller decides to abort the restore.
+	restoreCtx, restoreCancel := context.WithCancel(n.ctx)
+	var restoreGroup sync.WaitGroup
+	defer func() {
+		restoreCancel()
+		restoreGroup.Wait()
+	}()
+	for i := uint(0); i < restoreWorkers; i++ {
+		restoreGroup.Add(1)
+		go func() {
+			defer restoreGroup.Done()
+			n.chunkRestorer(restoreCtx, fetchedCh, resultCh, slots)
+		}()
+	}
+
 	// Prepare the heap of chunks.
 	chunks := &chunkHeap{
 		array:  make([]*checkpoint.ChunkMetadata, len(check.Chunks)),
@@ -232,6 +397,8 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, committeeClient comm
 	n.logger.Debug("checkpoint chunks prepared for dispatch",
 		"chunks", len(check.Chunks),
 		"checkpoint_root", check.Root,
+		"restore_workers", restoreWorkers,
+		"max_buffered_chunks", maxBufferedChunks,
 	)
 
 	// Feed the workers with chunks.
@@ -252,14 +419,32 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, committeeClient comm
 			return checkpointStatusBail, n.ctx.Err()
 
 		case returned := <-chunkReturnCh:
-			if returned == nil {
+			heap.Push(chunks, returned)
+
+		case result := <-resultCh:
+			switch {
+			case result.done:
 				// Restoration completed, no more chunks.
 				return checkpointStatusDone, nil
+			case result.err == nil:
+			case errors.Is(result.err, checkpoint.ErrChunkAlreadyRestored):
+				// Chunk was restored by another worker in the meantime.
+			case errors.Is(result.err, checkpoint.ErrChunkCorrupted):
+				n.logger.Error("chunk restoration failed",
+					"node", result.node,
+					"chunk", result.chunk.Index,
+					"root", result.chunk.Root,
+					"err", result.err,
+				)
+				heap.Push(chunks, result.chunk)
+			case errors.Is(result.err, checkpoint.ErrChunkProofVerificationFailed),
+				errors.Is(result.err, checkpoint.ErrNoRestoreInProgress):
+				// The restore has been aborted as the checkpoint is invalid, move on to the next
+				// checkpoint.
+				return checkpointStatusNext, result.err
+			default:
+				return checkpointStatusBail, result.err
 			}
-			heap.Push(chunks, returned)
-
-		case status := <-errorCh:
-			return status, nil
 
 		// If there's no chunk to send, outChan will be nil here, blocking forever. We still need to wait
 		// for other events even if there's no chunk to dispatch, since they may simply all be in processing.
@@ -278,7 +463,22 @@ func (n *Node) handleCheckpoint(check *checkpoint.Metadata, committeeClient comm
 }
 
 func (n *Node) getCheckpointList(committeeClient committee.Client) ([]*checkpoint.Metadata, error) {
-	// Get checkpoint list from all current committee members.
+	// Get checkpoint list from all current committee members that advertise any checkpoints, or
+	// from all members in case none do.
+	availability := n.getCheckpointAvailability(committeeClient)
+	var prefer func(*committee.ClientConnWithMeta) bool
+	if !availability.IsEmpty() {
+		advertisers := make(map[signature.PublicKey]bool)
+		for _, cp := range availability.Checkpoints() {
+			for _, src := range cp.Sources {
+				advertisers[src.Node] = true
+			}
+		}
+		prefer = func(conn *committee.ClientConnWithMeta) bool {
+			return advertisers[conn.Node.ID]
+		}
+	}
+
 	listCh := make(chan []*checkpoint.Metadata)
 	req := &checkpoint.GetCheckpointsRequest{
 		Version:   1,
@@ -303,7 +503,7 @@ func (n *Node) getCheckpointList(committeeClient committee.Client) ([]*checkpoin
 		return nil
 	}
 
-	cancel, doneCh, err := n.goWithCommittee(committeeClient, getter)
+	cancel, doneCh, err := n.goWithCommittee(committeeClient, 1, prefer, getter)
 	if err != nil {
 		return nil, err
 	}
@@ -322,10 +522,16 @@ resultLoop:
 		}
 	}
 
-	// Prepare the list: sort and deduplicate.
-	sort.Slice(list, func(i, j int) bool {
+	// Prepare the list: sort and deduplicate. Checkpoints for the same version are ranked by the
+	// number of committee nodes advertising them so that the most widely available ones are tried
+	// first.
+	sort.SliceStable(list, func(i, j int) bool {
 		// Descending!
 		if list[j].Root.Version == list[i].Root.Version {
+			ai, aj := availability.Sources(list[i].EncodedHash()), availability.Sources(list[j].EncodedHash())
+			if ai != aj {
+				return ai > aj
+			}
 			return bytes.Compare(list[j].Root.Hash[:], list[i].Root.Hash[:]) < 0
 		}
 		return list[j].Root.Version < list[i].Root.Version
@@ -343,6 +549,30 @@ resultLoop:
 	return retList[:cursor], nil
 }
 
+// getCheckpointAvailability returns the availability of checkpoints advertised by the storage
+// committee nodes in their node descriptors.
+func (n *Node) getCheckpointAvailability(committeeClient committee.Client) *storageApi.CheckpointAvailability {
+	var nodes []*node.Node
+	for _, conn := range committeeClient.GetConnectionsWithMeta() {
+		nodes = append(nodes, conn.Node)
+	}
+	return storageApi.NewCheckpointAvailability(n.commonNode.Runtime.ID(), nodes)
+}
+
+// getCheckpointStoreURL returns the base URL of the external checkpoint store advertised by the
+// given storage committee node in case the node also advertises the given checkpoint.
+func (n *Node) getCheckpointStoreURL(conn *committee.ClientConnWithMeta, available *storageApi.AvailableCheckpoint) string {
+	if n.checkpointSyncCfg.ExternalStoresDisabled || available == nil {
+		return ""
+	}
+	for _, src := range available.Sources {
+		if src.Node.Equal(conn.Node.ID) {
+			return src.StoreURL
+		}
+	}
+	return ""
+}
+
 func (n *Node) checkCheckpointUsable(cp *checkpoint.Metadata, remainingMask outstandingMask) outstandingMask {
 	namespace := n.commonNode.Runtime.ID()
 	if !namespace.Equal(&cp.Root.Namespace) {
@@ -375,12 +605,7 @@ func (n *Node) checkCheckpointUsable(cp *checkpoint.Metadata, remainingMask outs
 	return maskNone
 }
 
-func (n *Node) syncCheckpoints() (*blockSummary, error) {
-	// Store roots and round info for checkpoints that finished syncing.
-	// Round and namespace info will get overwritten as rounds are skipped
-	// for errors, driven by remainingRoots.
-	var syncState blockSummary
-
+func (n *Node) newCommitteeClient() (committee.Client, error) {
 	// Start following the storage committee.
 	committeeWatcher, err := committee.NewWatcher(
 		n.ctx,
@@ -398,6 +623,69 @@ func (n *Node) syncCheckpoints() (*blockSummary, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't create committee client: %w", err)
 	}
+	return committeeClient, nil
+}
+
+// syncGenesisCheckpoint restores the runtime genesis state from the storage checkpoint referenced
+// by the runtime genesis. The checkpoint chunks are fetched from the storage committee and each
+// chunk is verified against the checkpoint metadata and the genesis state root.
+func (n *Node) syncGenesisCheckpoint(rt *registryApi.Runtime) (*blockSummary, error) {
+	check := rt.Genesis.StateCheckpoint.Metadata(rt.ID, rt.Genesis.Round, rt.Genesis.StateRoot)
+
+	committeeClient, err := n.newCommitteeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Kill any previous restores that might be active.
+	if err = n.localStorage.Checkpointer().AbortRestore(n.ctx); err != nil {
+		return nil, fmt.Errorf("error aborting previous restore for genesis checkpoint sync: %w", err)
+	}
+
+	status, err := n.handleCheckpoint(check, committeeClient, rt)
+	if status != checkpointStatusDone {
+		if err == nil {
+			err = ErrNoUsableCheckpoints
+		}
+		if abortErr := n.localStorage.Checkpointer().AbortRestore(n.ctx); abortErr != nil {
+			n.logger.Error("can't abort genesis checkpoint restore",
+				"err", abortErr,
+			)
+		}
+		return nil, fmt.Errorf("error restoring genesis checkpoint: %w", err)
+	}
+
+	if err = n.localStorage.NodeDB().Finalize(n.ctx, check.Root.Version, []hash.Hash{check.Root.Hash}); err != nil {
+		if abortErr := n.localStorage.NodeDB().AbortMultipartInsert(); abortErr != nil {
+			n.logger.Error("can't abort multipart insert after finalization failure",
+				"err", abortErr,
+			)
+		}
+		return nil, fmt.Errorf("can't finalize version after genesis checkpoint restored: %w", err)
+	}
+
+	syncState := &blockSummary{
+		Namespace: rt.ID,
+		Round:     rt.Genesis.Round,
+		StateRoot: check.Root,
+	}
+	syncState.IORoot.Namespace = rt.ID
+	syncState.IORoot.Version = rt.Genesis.Round
+	syncState.IORoot.Hash.Empty()
+
+	return syncState, nil
+}
+
+func (n *Node) syncCheckpoints() (*blockSummary, error) {
+	// Store roots and round info for checkpoints that finished syncing.
+	// Round and namespace info will get overwritten as rounds are skipped
+	// for errors, driven by remainingRoots.
+	var syncState blockSummary
+
+	committeeClient, err := n.newCommitteeClient()
+	if err != nil {
+		return nil, err
+	}
 
 	descriptor, err := n.commonNode.Runtime.RegistryDescriptor(n.ctx)
 	if err != nil {
@@ -432,7 +720,7 @@ func (n *Node) syncCheckpoints() (*blockSummary, error) {
 			doneRoots = []hash.Hash{}
 		}
 
-		status, err := n.handleCheckpoint(check, committeeClient, descriptor.Storage.GroupSize)
+		status, err := n.handleCheckpoint(check, committeeClient, descriptor)
 		switch status {
 		case checkpointStatusDone:
 			n.logger.Info("successfully restored from checkpoint", "root", check.Root, "mask", mask)
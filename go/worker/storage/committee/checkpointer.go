@@ -0,0 +1,160 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	registryApi "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	dbApi "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+)
+
+// checkpointParameters returns the checkpoint creation parameters configured in the given runtime
+// descriptor.
+func checkpointParameters(rt *registryApi.Runtime) checkpoint.CreationParameters {
+	return checkpoint.CreationParameters{
+		Interval:  rt.Storage.CheckpointInterval,
+		NumKept:   rt.Storage.CheckpointNumKept,
+		ChunkSize: rt.Storage.CheckpointChunkSize,
+	}
+}
+
+// getCheckpointParameters returns the current checkpoint creation parameters.
+func (n *Node) getCheckpointParameters(ctx context.Context) (*checkpoint.CreationParameters, error) {
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
+	params := n.checkpointerParams
+	return &params, nil
+}
+
+// getChainContext returns the chain domain separation context of the network.
+func (n *Node) getChainContext(ctx context.Context) (string, error) {
+	doc, err := n.commonNode.Consensus.GetGenesisDocument(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't get genesis document: %w", err)
+	}
+	return doc.ChainContext(), nil
+}
+
+// notifyCheckpointer notifies the checkpointer (if running) that there is a new finalized round.
+func (n *Node) notifyCheckpointer(round uint64) {
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
+	if n.checkpointer != nil {
+		n.checkpointer.NotifyNewVersion(round)
+	}
+}
+
+// updateCheckpointer starts, stops or reconfigures the checkpointer based on the checkpoint
+// parameters in the given runtime descriptor.
+func (n *Node) updateCheckpointer(rt *registryApi.Runtime) error {
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
+	params := checkpointParameters(rt)
+	running, enabled := n.checkpointer != nil, params.Interval > 0
+	if params == n.checkpointerParams && running == enabled {
+		return nil
+	}
+	n.checkpointerParams = params
+
+	switch {
+	case !enabled && running:
+		// Checkpointing has been disabled, stop the checkpointer.
+		n.logger.Info("stopping checkpointer as checkpointing has been disabled")
+
+		n.checkpointerCancel()
+		n.checkpointer = nil
+		n.checkpointerCancel = nil
+	case enabled && !running:
+		// Checkpointing has been enabled, start the checkpointer.
+		n.logger.Info("starting checkpointer",
+			"interval", params.Interval,
+			"num_kept", params.NumKept,
+			"chunk_size", params.ChunkSize,
+		)
+
+		chainContext, err := n.getChainContext(n.ctx)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(n.ctx)
+		cp, err := checkpoint.NewCheckpointer(ctx, n.localStorage.NodeDB(), n.localStorage.Checkpointer(), checkpoint.CheckpointerConfig{
+			Name:            "runtime",
+			Namespace:       n.commonNode.Runtime.ID(),
+			CheckInterval:   n.checkpointerCfg.CheckInterval,
+			RootsPerVersion: 2, // State root and I/O root.
+			ChainContext:    chainContext,
+			GetParameters:   n.getCheckpointParameters,
+			GetRoots: func(ctx context.Context, version uint64) ([]hash.Hash, error) {
+				blk, berr := n.commonNode.Runtime.History().GetBlock(ctx, version)
+				if berr != nil {
+					return nil, berr
+				}
+
+				return []hash.Hash{
+					blk.Header.IORoot,
+					blk.Header.StateRoot,
+				}, nil
+			},
+			CheckpointsUpdated: n.advertiseCheckpoints,
+			Store:              n.checkpointerCfg.Store,
+		})
+		if err != nil {
+			cancel()
+			return err
+		}
+		n.checkpointer = cp
+		n.checkpointerCancel = cancel
+
+		// Make sure the new checkpointer knows about the last finalized round.
+		if round, _, _ := n.GetLastSynced(); round != defaultUndefinedRound && round != n.undefinedRound {
+			n.checkpointer.NotifyNewVersion(round)
+		}
+	default:
+		// The running checkpointer fetches the updated parameters on its next pass.
+		n.logger.Info("updated checkpoint parameters",
+			"interval", params.Interval,
+			"num_kept", params.NumKept,
+			"chunk_size", params.ChunkSize,
+		)
+	}
+	return nil
+}
+
+// getCheckpointer returns the running checkpointer.
+func (n *Node) getCheckpointer() (checkpoint.Checkpointer, error) {
+	n.checkpointerLock.Lock()
+	defer n.checkpointerLock.Unlock()
+
+	if n.checkpointer == nil {
+		return nil, checkpoint.ErrCheckpointingDisabled
+	}
+	return n.checkpointer, nil
+}
+
+// ForceCheckpoint creates checkpoints for the given finalized round on demand.
+func (n *Node) ForceCheckpoint(ctx context.Context, round uint64) ([]*checkpoint.Metadata, error) {
+	cp, err := n.getCheckpointer()
+	if err != nil {
+		return nil, err
+	}
+
+	if lastFinalized, _, _ := n.GetLastSynced(); lastFinalized == defaultUndefinedRound || round > lastFinalized {
+		return nil, fmt.Errorf("%w: round %d", dbApi.ErrNotFinalized, round)
+	}
+	return cp.ForceCheckpoint(ctx, round)
+}
+
+// DeleteCheckpoints removes the on-demand checkpoints for the given round.
+func (n *Node) DeleteCheckpoints(ctx context.Context, round uint64) error {
+	cp, err := n.getCheckpointer()
+	if err != nil {
+		return err
+	}
+	return cp.DeleteCheckpoints(ctx, round)
+}
@@ -0,0 +1,102 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// DiffSyncConfig is the storage worker diff sync configuration.
+type DiffSyncConfig struct {
+	// VerifyDiffs specifies whether fetched diffs should be verified against the expected roots
+	// before they are applied to local storage. This makes it possible to reject invalid data
+	// early and to avoid the storage nodes that served it.
+	VerifyDiffs bool
+
+	// ValidateApply specifies whether a fetched diff that does not result in the expected root
+	// when applied to local storage should be re-fetched from a different storage node instead
+	// of proceeding to finalization of the round.
+	ValidateApply bool
+}
+
+// newDiffVerifier creates a new diff verifier that applies fetched write logs to a throwaway
+// in-memory tree on top of the locally stored start root and checks that the resulting root
+// matches the expected end root.
+//
+// The prefix write log contains any entries that have already been fetched in previous
+// attempts when the diff fetch is being resumed.
+func newDiffVerifier(ndb mkvsDB.NodeDB, prefix storageApi.WriteLog) storageApi.DiffVerifier {
+	return func(ctx context.Context, request *storageApi.GetDiffRequest, writeLog storageApi.WriteLog) error {
+		var tree mkvs.Tree
+		switch {
+		case request.StartRoot.Hash.IsEmpty():
+			tree = mkvs.New(nil, nil)
+		case ndb.HasRoot(request.StartRoot):
+			tree = mkvs.NewWithRoot(nil, ndb, request.StartRoot)
+		default:
+			// The start root has not been applied yet so we cannot verify the diff before it
+			// is applied. In this case the root check on apply will catch any corruption.
+			return nil
+		}
+		defer tree.Close()
+
+		if len(prefix) > 0 {
+			writeLog = append(append(storageApi.WriteLog{}, prefix...), writeLog...)
+		}
+		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
+			return fmt.Errorf("failed to apply write log: %w", err)
+		}
+		_, rootHash, err := tree.Commit(ctx, request.EndRoot.Namespace, request.EndRoot.Version, mkvs.NoPersist())
+		if err != nil {
+			return fmt.Errorf("failed to compute root: %w", err)
+		}
+		if !rootHash.Equal(&request.EndRoot.Hash) {
+			return fmt.Errorf("root mismatch (expected: %s got: %s)", request.EndRoot.Hash, rootHash)
+		}
+		return nil
+	}
+}
+
+// rootTypeName returns the name of the root type a fetch mask refers to.
+func rootTypeName(mask outstandingMask) string {
+	switch mask {
+	case maskIO:
+		return "io"
+	case maskState:
+		return "state"
+	default:
+		return mask.String()
+	}
+}
+
+// handleApplyRootMismatch handles a fetched diff that did not result in the expected root when
+// applied to local storage. The storage node that served the diff is recorded so that it is
+// avoided when the diff is re-fetched on the next retry.
+func (n *Node) handleApplyRootMismatch(syncing *inFlight, diff *fetchedDiff) {
+	rootType := rootTypeName(diff.fetchMask)
+
+	labels := n.getMetricLabels()
+	labels["root_type"] = rootType
+	storageWorkerApplyRootMismatches.With(labels).Inc()
+
+	var source interface{} = "unknown"
+	if diff.source != nil {
+		source = *diff.source
+		syncing.avoid[diff.fetchMask] = append(syncing.avoid[diff.fetchMask], *diff.source)
+	}
+	n.logger.Error("applied diff does not result in the expected root, will re-fetch",
+		"round", diff.round,
+		"root_type", rootType,
+		"old_root", diff.prevRoot,
+		"expected_root", diff.thisRoot,
+		"source_node", source,
+		"avoided_nodes", len(syncing.avoid[diff.fetchMask]),
+	)
+
+	syncing.outstanding &= ^diff.fetchMask
+	syncing.awaitingRetry |= diff.fetchMask
+}
@@ -0,0 +1,88 @@
+package committee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestDiffVerifier(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage worker diff verifier test ns"), 0)
+	ndb, err := badgerDb.New(&mkvsDB.Config{
+		Namespace:    ns,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+		MemoryOnly:   true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	// Create and persist the start root.
+	tree := mkvs.New(nil, ndb)
+	defer tree.Close()
+	err = tree.Insert(ctx, []byte("foo"), []byte("bar"))
+	require.NoError(err, "Insert")
+	_, startHash, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit")
+	startRoot := mkvsNode.Root{Namespace: ns, Version: 1, Hash: startHash}
+
+	// Compute the end root without persisting it.
+	writeLog := storageApi.WriteLog{
+		{Key: []byte("moo"), Value: []byte("goo")},
+		{Key: []byte("foo"), Value: []byte("baz")},
+	}
+	endTree := mkvs.NewWithRoot(nil, ndb, startRoot)
+	defer endTree.Close()
+	for _, entry := range writeLog {
+		err = endTree.Insert(ctx, entry.Key, entry.Value)
+		require.NoError(err, "Insert")
+	}
+	_, endHash, err := endTree.Commit(ctx, ns, 2, mkvs.NoPersist())
+	require.NoError(err, "Commit")
+	endRoot := mkvsNode.Root{Namespace: ns, Version: 2, Hash: endHash}
+
+	request := &storageApi.GetDiffRequest{StartRoot: startRoot, EndRoot: endRoot}
+	verifier := newDiffVerifier(ndb, nil)
+	err = verifier(ctx, request, writeLog)
+	require.NoError(err, "verification should succeed for a valid write log")
+	err = verifier(ctx, request, writeLog[:1])
+	require.Error(err, "verification should fail for a truncated write log")
+	err = verifier(ctx, request, storageApi.WriteLog{
+		{Key: []byte("moo"), Value: []byte("goo")},
+		{Key: []byte("foo"), Value: []byte("corrupted")},
+	})
+	require.Error(err, "verification should fail for a corrupted write log")
+
+	// Resumed fetches should include the previously fetched entries.
+	verifier = newDiffVerifier(ndb, writeLog[:1])
+	err = verifier(ctx, request, writeLog[1:])
+	require.NoError(err, "verification should succeed for a resumed write log")
+
+	// Diffs from an empty root should be verified.
+	var emptyRoot mkvsNode.Root
+	emptyRoot.Namespace = ns
+	emptyRoot.Version = 1
+	emptyRoot.Hash.Empty()
+	request = &storageApi.GetDiffRequest{StartRoot: emptyRoot, EndRoot: startRoot}
+	verifier = newDiffVerifier(ndb, nil)
+	err = verifier(ctx, request, storageApi.WriteLog{{Key: []byte("foo"), Value: []byte("bar")}})
+	require.NoError(err, "verification should succeed for a valid write log from an empty root")
+	err = verifier(ctx, request, nil)
+	require.Error(err, "verification should fail for an empty write log")
+
+	// Diffs from unknown roots cannot be verified and should be skipped.
+	request = &storageApi.GetDiffRequest{StartRoot: endRoot, EndRoot: startRoot}
+	err = verifier(ctx, request, nil)
+	require.NoError(err, "verification should be skipped for unknown start roots")
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc/policy"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -30,6 +32,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p"
@@ -67,10 +70,19 @@ var (
 		[]string{"runtime"},
 	)
 
+	storageWorkerApplyRootMismatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_apply_root_mismatches",
+			Help: "Number of fetched diffs that did not result in the expected root when applied.",
+		},
+		[]string{"runtime", "root_type"},
+	)
+
 	storageWorkerCollectors = []prometheus.Collector{
 		storageWorkerLastFullRound,
 		storageWorkerLastSyncedRound,
 		storageWorkerLastPendingRound,
+		storageWorkerApplyRootMismatches,
 	}
 
 	prometheusOnce sync.Once
@@ -139,6 +151,18 @@ type fetchedDiff struct {
 	prevRoot  mkvsNode.Root
 	thisRoot  mkvsNode.Root
 	writeLog  storageApi.WriteLog
+
+	// source is the storage node that served the diff (if any).
+	source *signature.PublicKey
+
+	// progress is set in case fetching failed mid-stream and can be resumed on retry.
+	progress *diffProgress
+}
+
+// diffProgress is the progress of a partially fetched diff.
+type diffProgress struct {
+	writeLog storageApi.WriteLog
+	token    []byte
 }
 
 func (d *fetchedDiff) GetRound() uint64 {
@@ -183,6 +207,7 @@ type watcherState struct {
 type Node struct {
 	commonNode *committee.Node
 
+	// roleProvider is nil in case the node is running as a read-only replica.
 	roleProvider registration.RoleProvider
 
 	logger *logging.Logger
@@ -190,6 +215,7 @@ type Node struct {
 	localStorage   storageApi.LocalBackend
 	storageClient  storageApi.ClientBackend
 	grpcPolicy     *policy.DynamicRuntimePolicyChecker
+	accessPolicies *accessPolicies
 	undefinedRound uint64
 
 	fetchPool *workerpool.Pool
@@ -198,8 +224,22 @@ type Node struct {
 
 	workerCommonCfg workerCommon.Config
 
-	checkpointer           checkpoint.Checkpointer
-	checkpointSyncDisabled bool
+	// checkpointerCfg is nil in case the checkpointer is disabled in the node configuration.
+	checkpointerCfg   *checkpoint.CheckpointerConfig
+	checkpointSyncCfg *CheckpointSyncConfig
+	diffSyncCfg       *DiffSyncConfig
+	recoveryCfg       *RecoveryConfig
+
+	checkpointerLock   sync.Mutex
+	checkpointer       checkpoint.Checkpointer
+	checkpointerCancel context.CancelFunc
+	checkpointerParams checkpoint.CreationParameters
+
+	// Guarded by CrossNode.
+	storageParams *registryApi.StorageParameters
+
+	checkpointsLock       sync.RWMutex
+	advertisedCheckpoints []node.StorageCheckpoint
 
 	syncedLock  sync.RWMutex
 	syncedState watcherState
@@ -208,11 +248,17 @@ type Node struct {
 	diffCh     chan *fetchedDiff
 	finalizeCh chan *blockSummary
 
+	// runLock serializes worker restarts and guards the per-run context.
+	runLock   sync.Mutex
 	ctx       context.Context
 	ctxCancel context.CancelFunc
+	restartCh chan chan error
 
-	quitCh chan struct{}
-	initCh chan struct{}
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	quitCh   chan struct{}
+	initOnce sync.Once
+	initCh   chan struct{}
 }
 
 func NewNode(
@@ -224,8 +270,21 @@ func NewNode(
 	workerCommonCfg workerCommon.Config,
 	localStorage storageApi.LocalBackend,
 	checkpointerCfg *checkpoint.CheckpointerConfig,
-	checkpointSyncDisabled bool,
+	checkpointSyncCfg *CheckpointSyncConfig,
+	diffSyncCfg *DiffSyncConfig,
+	recoveryCfg *RecoveryConfig,
+	policyCfg *PolicyConfig,
 ) (*Node, error) {
+	if diffSyncCfg == nil {
+		diffSyncCfg = &DiffSyncConfig{}
+	}
+	if recoveryCfg == nil {
+		recoveryCfg = &RecoveryConfig{}
+	}
+	if policyCfg == nil {
+		policyCfg = DefaultPolicyConfig()
+	}
+
 	node := &Node{
 		commonNode: commonNode,
 
@@ -235,82 +294,31 @@ func NewNode(
 
 		workerCommonCfg: workerCommonCfg,
 
-		localStorage: localStorage,
-		grpcPolicy:   grpcPolicy,
+		localStorage:   localStorage,
+		grpcPolicy:     grpcPolicy,
+		accessPolicies: newAccessPolicies(policyCfg),
 
 		fetchPool: fetchPool,
 
 		stateStore: store,
 
-		checkpointSyncDisabled: checkpointSyncDisabled,
+		checkpointerCfg:   checkpointerCfg,
+		checkpointSyncCfg: checkpointSyncCfg,
+		diffSyncCfg:       diffSyncCfg,
+		recoveryCfg:       recoveryCfg,
 
 		blockCh:    channels.NewInfiniteChannel(),
 		diffCh:     make(chan *fetchedDiff),
 		finalizeCh: make(chan *blockSummary),
 
-		quitCh: make(chan struct{}),
-		initCh: make(chan struct{}),
+		restartCh: make(chan chan error),
+		stopCh:    make(chan struct{}),
+		quitCh:    make(chan struct{}),
+		initCh:    make(chan struct{}),
 	}
 
-	node.syncedState.LastBlock.Round = defaultUndefinedRound
-	rtID := commonNode.Runtime.ID()
-	err := store.GetCBOR(rtID[:], &node.syncedState)
-	if err != nil && err != persistent.ErrNotFound {
-		return nil, fmt.Errorf("storage worker: failed to restore sync state: %w", err)
-	}
-
-	node.ctx, node.ctxCancel = context.WithCancel(context.Background())
-
-	// Create a new storage client that will be used for remote sync.
-	scl, err := client.New(
-		node.ctx,
-		commonNode.Runtime.ID(),
-		node.commonNode.Identity,
-		node.commonNode.Consensus.Scheduler(),
-		node.commonNode.Consensus.Registry(),
-		nil,
-		runtimeCommittee.WithFilter(runtimeCommittee.IgnoreNodeFilter(commonNode.Identity.NodeSigner.Public())),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("storage worker: failed to create client: %w", err)
-	}
-	node.storageClient = scl.(storageApi.ClientBackend)
-
-	// Create a new checkpointer if enabled.
-	if checkpointerCfg != nil {
-		checkpointerCfg = &checkpoint.CheckpointerConfig{
-			Name:            "runtime",
-			Namespace:       commonNode.Runtime.ID(),
-			CheckInterval:   checkpointerCfg.CheckInterval,
-			RootsPerVersion: 2, // State root and I/O root.
-			GetParameters: func(ctx context.Context) (*checkpoint.CreationParameters, error) {
-				rt, rerr := commonNode.Runtime.RegistryDescriptor(ctx)
-				if rerr != nil {
-					return nil, rerr
-				}
-
-				return &checkpoint.CreationParameters{
-					Interval:  rt.Storage.CheckpointInterval,
-					NumKept:   rt.Storage.CheckpointNumKept,
-					ChunkSize: rt.Storage.CheckpointChunkSize,
-				}, nil
-			},
-			GetRoots: func(ctx context.Context, version uint64) ([]hash.Hash, error) {
-				blk, berr := commonNode.Runtime.History().GetBlock(ctx, version)
-				if berr != nil {
-					return nil, berr
-				}
-
-				return []hash.Hash{
-					blk.Header.IORoot,
-					blk.Header.StateRoot,
-				}, nil
-			},
-		}
-		node.checkpointer, err = checkpoint.NewCheckpointer(node.ctx, localStorage.NodeDB(), localStorage.Checkpointer(), *checkpointerCfg)
-		if err != nil {
-			return nil, fmt.Errorf("storage worker: failed to create checkpointer: %w", err)
-		}
+	if err := node.initRun(); err != nil {
+		return nil, err
 	}
 
 	// Register prune handler.
@@ -335,13 +343,133 @@ func (n *Node) Name() string {
 
 // Start causes the worker to start responding to tendermint new block events.
 func (n *Node) Start() error {
-	go n.worker()
+	go n.supervisor()
 	return nil
 }
 
 // Stop causes the worker to stop watching and shut down.
 func (n *Node) Stop() {
+	n.stopOnce.Do(func() {
+		close(n.stopCh)
+	})
+
+	n.runLock.Lock()
+	defer n.runLock.Unlock()
+	n.ctxCancel()
+}
+
+// Restart stops the worker, tears down all of its per-run state (including the checkpointer and
+// the remote storage client) and starts it again from the persisted sync state.
+//
+// The method returns once the worker has been restarted, it does not wait for the worker to
+// finish initialization.
+func (n *Node) Restart(ctx context.Context) error {
+	n.logger.Info("restarting storage worker")
+
+	n.runLock.Lock()
 	n.ctxCancel()
+	n.runLock.Unlock()
+
+	errCh := make(chan error, 1)
+	select {
+	case n.restartCh <- errCh:
+	case <-n.stopCh:
+		return context.Canceled
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// supervisor runs the worker until the node is stopped, restarting it on request.
+func (n *Node) supervisor() {
+	defer close(n.quitCh)
+
+	for {
+		n.worker()
+
+		select {
+		case <-n.stopCh:
+			return
+		case errCh := <-n.restartCh:
+			err := n.resetRun()
+			errCh <- err
+			if err != nil {
+				n.logger.Error("failed to restart storage worker",
+					"err", err,
+				)
+				continue
+			}
+
+			n.logger.Info("storage worker restarted")
+		}
+	}
+}
+
+// initRun initializes the per-run worker state.
+func (n *Node) initRun() error {
+	n.syncedLock.Lock()
+	n.syncedState = watcherState{}
+	n.syncedState.LastBlock.Round = defaultUndefinedRound
+	rtID := n.commonNode.Runtime.ID()
+	err := n.stateStore.GetCBOR(rtID[:], &n.syncedState)
+	n.syncedLock.Unlock()
+	if err != nil && err != persistent.ErrNotFound {
+		return fmt.Errorf("storage worker: failed to restore sync state: %w", err)
+	}
+
+	n.runLock.Lock()
+	defer n.runLock.Unlock()
+
+	n.ctx, n.ctxCancel = context.WithCancel(context.Background())
+	n.diffCh = make(chan *fetchedDiff)
+	n.finalizeCh = make(chan *blockSummary)
+
+	// Create a new storage client that will be used for remote sync.
+	scl, err := client.New(
+		n.ctx,
+		rtID,
+		n.commonNode.Identity,
+		n.commonNode.Consensus.Scheduler(),
+		n.commonNode.Consensus.Registry(),
+		nil,
+		runtimeCommittee.WithFilter(runtimeCommittee.IgnoreNodeFilter(n.commonNode.Identity.NodeSigner.Public())),
+	)
+	if err != nil {
+		n.ctxCancel()
+		return fmt.Errorf("storage worker: failed to create client: %w", err)
+	}
+	n.storageClient = scl.(storageApi.ClientBackend)
+
+	return nil
+}
+
+// resetRun tears down the state of the previous (terminated) worker run and initializes the
+// state for a new run.
+func (n *Node) resetRun() error {
+	// Make sure the node is not advertised while the worker is being re-initialized. The worker
+	// will mark the role provider as available again once it is ready.
+	if n.roleProvider != nil {
+		n.roleProvider.SetUnavailable()
+	}
+
+	// The checkpointer context is derived from the worker context so it is already stopped.
+	n.checkpointerLock.Lock()
+	if n.checkpointerCancel != nil {
+		n.checkpointerCancel()
+	}
+	n.checkpointer = nil
+	n.checkpointerCancel = nil
+	n.checkpointerParams = checkpoint.CreationParameters{}
+	n.checkpointerLock.Unlock()
+
+	return n.initRun()
 }
 
 // Quit returns a channel that will be closed when the worker stops.
@@ -378,16 +506,26 @@ func (n *Node) getMetricLabels() prometheus.Labels {
 // NodeHooks implementation.
 
 func (n *Node) updateExternalServicePolicyLocked(snapshot *committee.EpochSnapshot) {
+	// Read-only replicas do not expose an external storage gRPC interface.
+	if n.grpcPolicy == nil {
+		return
+	}
+
 	// Create new storage gRPC access policy for the current runtime.
 	policy := accessctl.NewPolicy()
 
+	// Add policy for all clients.
+	for _, action := range n.accessPolicies.public.Actions {
+		policy.Allow(accessctl.AnySubject, action)
+	}
+
 	// Add policy for configured sentry nodes.
 	for _, addr := range n.workerCommonCfg.SentryAddresses {
-		sentryNodesPolicy.AddPublicKeyPolicy(&policy, addr.PubKey)
+		n.accessPolicies.sentry.AddPublicKeyPolicy(&policy, addr.PubKey)
 	}
 
 	if xc := snapshot.GetExecutorCommittee(); xc != nil {
-		executorCommitteePolicy.AddRulesForCommittee(&policy, xc, snapshot.Nodes())
+		n.accessPolicies.executor.AddRulesForCommittee(&policy, xc, snapshot.Nodes())
 	}
 	// TODO: Query registry only for storage nodes after
 	// https://github.com/oasisprotocol/oasis-core/issues/1923 is implemented.
@@ -404,7 +542,7 @@ func (n *Node) updateExternalServicePolicyLocked(snapshot *committee.EpochSnapsh
 			}
 		}
 
-		storageNodesPolicy.AddRulesForNodeRoles(&policy, rtNodes, node.RoleStorageWorker)
+		n.accessPolicies.storage.AddRulesForNodeRoles(&policy, rtNodes, node.RoleStorageWorker)
 	}
 
 	// Update storage gRPC access policy for the current runtime.
@@ -420,6 +558,7 @@ func (n *Node) HandlePeerMessage(context.Context, *p2p.Message, bool) (bool, err
 // Guarded by CrossNode.
 func (n *Node) HandleEpochTransitionLocked(snapshot *committee.EpochSnapshot) {
 	n.updateExternalServicePolicyLocked(snapshot)
+	n.checkReplicationLocked(snapshot)
 }
 
 // Guarded by CrossNode.
@@ -453,6 +592,35 @@ func (n *Node) GetLastSynced() (uint64, mkvsNode.Root, mkvsNode.Root) {
 	return n.syncedState.LastBlock.Round, n.syncedState.LastBlock.IORoot, n.syncedState.LastBlock.StateRoot
 }
 
+// SyncStorage makes sure that all local storage state is persisted to disk.
+func (n *Node) SyncStorage() error {
+	return n.localStorage.NodeDB().Sync()
+}
+
+// TriggerGC runs garbage collection of the local storage database immediately and returns the
+// number of database log files that have been rewritten.
+func (n *Node) TriggerGC(ctx context.Context) (uint64, error) {
+	gc, ok := n.localStorage.NodeDB().(mkvsDB.GarbageCollector)
+	if !ok {
+		return 0, api.ErrGCNotSupported
+	}
+
+	n.logger.Info("triggering storage garbage collection")
+
+	rewrites, err := gc.TriggerGC(ctx)
+	if err != nil {
+		n.logger.Error("failed to garbage collect storage",
+			"err", err,
+		)
+		return 0, err
+	}
+
+	n.logger.Info("storage garbage collection completed",
+		"rewrites", rewrites,
+	)
+	return rewrites, nil
+}
+
 // ForceFinalize forces a storage finalization for the given round.
 func (n *Node) ForceFinalize(ctx context.Context, round uint64) error {
 	n.logger.Debug("forcing round finalization",
@@ -477,7 +645,15 @@ func (n *Node) ForceFinalize(ctx context.Context, round uint64) error {
 	})
 }
 
-func (n *Node) fetchDiff(round uint64, prevRoot, thisRoot *mkvsNode.Root, fetchMask outstandingMask) {
+func (n *Node) fetchDiff(
+	ctx context.Context,
+	round uint64,
+	prevRoot *mkvsNode.Root,
+	thisRoot *mkvsNode.Root,
+	fetchMask outstandingMask,
+	progress *diffProgress,
+	avoid []signature.PublicKey,
+) {
 	result := &fetchedDiff{
 		fetchMask: fetchMask,
 		fetched:   false,
@@ -486,7 +662,10 @@ func (n *Node) fetchDiff(round uint64, prevRoot, thisRoot *mkvsNode.Root, fetchM
 		thisRoot:  *thisRoot,
 	}
 	defer func() {
-		n.diffCh <- result
+		select {
+		case n.diffCh <- result:
+		case <-ctx.Done():
+		}
 	}()
 	// Check if the new root doesn't already exist.
 	if !n.localStorage.NodeDB().HasRoot(*thisRoot) {
@@ -500,20 +679,60 @@ func (n *Node) fetchDiff(round uint64, prevRoot, thisRoot *mkvsNode.Root, fetchM
 		} else {
 			// New root does not yet exist in storage and we need to fetch it from a
 			// remote node.
+			request := &storageApi.GetDiffRequest{StartRoot: *prevRoot, EndRoot: *thisRoot}
+			if progress != nil {
+				// Resume from where the previous attempt failed.
+				request.Options.ContinuationToken = progress.token
+				result.writeLog = append(storageApi.WriteLog{}, progress.writeLog...)
+			}
+			if n.diffSyncCfg.VerifyDiffs {
+				ctx = storageApi.WithDiffVerifier(ctx, newDiffVerifier(n.localStorage.NodeDB(), result.writeLog))
+			}
+			if n.diffSyncCfg.ValidateApply {
+				// Record which node served the diff so that it can be avoided in case the diff does
+				// not result in the expected root when applied.
+				ctx = storageApi.WithSourceRecorder(ctx, func(node signature.PublicKey) {
+					result.source = &node
+				})
+				if len(avoid) > 0 {
+					ctx = storageApi.WithNodeAvoidHint(ctx, avoid)
+				}
+			}
+
 			n.logger.Debug("calling GetDiff",
 				"old_root", prevRoot,
 				"new_root", thisRoot,
 				"fetch_mask", fetchMask,
+				"resumed_entries", len(result.writeLog),
+				"avoided_nodes", len(avoid),
 			)
 
-			it, err := n.storageClient.GetDiff(n.ctx, &storageApi.GetDiffRequest{StartRoot: *prevRoot, EndRoot: *thisRoot})
+			it, err := n.storageClient.GetDiff(ctx, request)
 			if err != nil {
+				// In case the continuation token has been rejected (e.g., because the remote
+				// node has a different write log) or the diff failed verification (in which
+				// case the previously fetched entries may be the invalid ones), the next retry
+				// will start from scratch.
+				if !errors.Is(err, storageApi.ErrInvalidContinuationToken) && !errors.Is(err, storageApi.ErrDiffVerificationFailed) {
+					result.progress = progress
+				}
 				result.err = err
 				return
 			}
 			for {
 				more, err := it.Next()
 				if err != nil {
+					if errors.Is(err, storageApi.ErrInvalidContinuationToken) {
+						result.err = err
+						return
+					}
+					// Record progress so that the retry can resume streaming.
+					if rit, ok := it.(storageApi.ResumableWriteLogIterator); ok && rit.ContinuationToken() != nil {
+						result.progress = &diffProgress{
+							writeLog: result.writeLog,
+							token:    rit.ContinuationToken(),
+						}
+					}
 					result.err = err
 					return
 				}
@@ -532,6 +751,32 @@ func (n *Node) fetchDiff(round uint64, prevRoot, thisRoot *mkvsNode.Root, fetchM
 	}
 }
 
+// submitFetchDiff submits a diff fetch to the fetch pool. The fetch is abandoned in case the node
+// or the fetch pool are shutting down.
+func (n *Node) submitFetchDiff(
+	fetcherGroup *sync.WaitGroup,
+	round uint64,
+	prevRoot *mkvsNode.Root,
+	thisRoot *mkvsNode.Root,
+	fetchMask outstandingMask,
+	progress *diffProgress,
+	avoid []signature.PublicKey,
+) {
+	fetcherGroup.Add(1)
+	_, err := n.fetchPool.SubmitContext(n.ctx, func(ctx context.Context) {
+		defer fetcherGroup.Done()
+		n.fetchDiff(ctx, round, prevRoot, thisRoot, fetchMask, progress, avoid)
+	})
+	if err != nil {
+		fetcherGroup.Done()
+		n.logger.Debug("not fetching diff, shutting down",
+			"err", err,
+			"round", round,
+			"fetch_mask", fetchMask,
+		)
+	}
+}
+
 func (n *Node) finalize(summary *blockSummary) {
 	err := n.localStorage.NodeDB().Finalize(n.ctx, summary.Round, []hash.Hash{
 		summary.IORoot.Hash,
@@ -555,15 +800,26 @@ func (n *Node) finalize(summary *blockSummary) {
 		)
 	}
 
-	n.finalizeCh <- summary
+	select {
+	case n.finalizeCh <- summary:
+	case <-n.ctx.Done():
+	}
 }
 
 type inFlight struct {
 	outstanding   outstandingMask
 	awaitingRetry outstandingMask
+
+	// progress is the progress of partially fetched diffs awaiting retry.
+	progress map[outstandingMask]*diffProgress
+	// avoid are the storage nodes that served diffs which did not result in the expected root
+	// when applied and should be avoided when retrying.
+	avoid map[outstandingMask][]signature.PublicKey
 }
 
-func (n *Node) initGenesis(rt *registryApi.Runtime) error {
+// initGenesis initializes local storage at genesis. It returns true in case the genesis state needs
+// to be restored from the storage checkpoint referenced by the runtime genesis.
+func (n *Node) initGenesis(rt *registryApi.Runtime) (bool, error) {
 	n.logger.Info("initializing storage at genesis")
 
 	if rt.Genesis.State != nil {
@@ -583,7 +839,7 @@ func (n *Node) initGenesis(rt *registryApi.Runtime) error {
 			WriteLog:  rt.Genesis.State,
 		})
 		if err != nil {
-			return err
+			return false, err
 		}
 	} else if !rt.Genesis.StateRoot.IsEmpty() {
 		// Non-empty state root and nil state. This is only allowed in case the storage node already
@@ -593,15 +849,96 @@ func (n *Node) initGenesis(rt *registryApi.Runtime) error {
 			Version:   rt.Genesis.Round,
 			Hash:      rt.Genesis.StateRoot,
 		}) {
+			if rt.Genesis.StateCheckpoint != nil {
+				n.logger.Info("genesis state will be restored from checkpoint",
+					"state_root", rt.Genesis.StateRoot,
+					"chunks", len(rt.Genesis.StateCheckpoint.Chunks),
+				)
+				return true, nil
+			}
+
 			n.logger.Warn("non-empty state root but no state specified, assuming replication",
 				"state_root", rt.Genesis.StateRoot,
 			)
 		}
 	}
 
+	return false, nil
+}
+
+func (n *Node) registerNodeHook(nd *node.Node) error {
+	rt := nd.AddOrUpdateRuntime(n.commonNode.Runtime.ID())
+
+	n.checkpointsLock.RLock()
+	defer n.checkpointsLock.RUnlock()
+
+	switch len(n.advertisedCheckpoints) {
+	case 0:
+		rt.Capabilities.Storage = nil
+	default:
+		rt.Capabilities.Storage = &node.CapabilityStorage{
+			Checkpoints: n.advertisedCheckpoints,
+		}
+		if n.checkpointerCfg != nil && n.checkpointerCfg.Store != nil {
+			rt.Capabilities.Storage.CheckpointStoreURL = n.checkpointerCfg.Store.URL()
+		}
+	}
 	return nil
 }
 
+// advertiseCheckpoints updates the list of storage checkpoints advertised in the node descriptor.
+func (n *Node) advertiseCheckpoints(ctx context.Context, cps []*checkpoint.Metadata) {
+	// Advertise the most recent checkpoints first.
+	sort.Slice(cps, func(i, j int) bool {
+		return cps[i].Root.Version > cps[j].Root.Version
+	})
+	if len(cps) > node.MaxStorageCheckpoints {
+		cps = cps[:node.MaxStorageCheckpoints]
+	}
+
+	advertised := make([]node.StorageCheckpoint, 0, len(cps))
+	for _, cp := range cps {
+		advertised = append(advertised, node.StorageCheckpoint{
+			Version: cp.Version,
+			Round:   cp.Root.Version,
+			Root:    cp.Root.Hash,
+			Chunks:  uint64(len(cp.Chunks)),
+			Hash:    cp.EncodedHash(),
+		})
+	}
+
+	n.checkpointsLock.Lock()
+	changed := len(advertised) != len(n.advertisedCheckpoints)
+	for i := 0; !changed && i < len(advertised); i++ {
+		changed = advertised[i] != n.advertisedCheckpoints[i]
+	}
+	n.advertisedCheckpoints = advertised
+	n.checkpointsLock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	// Only trigger re-registration once the node has been initialized as otherwise the initial
+	// registration callback could be overwritten. The updated list will be picked up by the
+	// initial registration in that case.
+	select {
+	case <-n.initCh:
+	default:
+		return
+	}
+
+	// Read-only replicas do not register so there is nothing to advertise.
+	if n.roleProvider == nil {
+		return
+	}
+
+	n.logger.Debug("advertising updated storage checkpoints",
+		"num_checkpoints", len(advertised),
+	)
+	n.roleProvider.SetAvailable(n.registerNodeHook)
+}
+
 func (n *Node) flushSyncedState(summary *blockSummary) uint64 {
 	n.syncedLock.Lock()
 	defer n.syncedLock.Unlock()
@@ -615,15 +952,20 @@ func (n *Node) flushSyncedState(summary *blockSummary) uint64 {
 	return n.syncedState.LastBlock.Round
 }
 
+func (n *Node) markInitialized() {
+	n.initOnce.Do(func() {
+		close(n.initCh)
+	})
+}
+
 func (n *Node) worker() { // nolint: gocyclo
-	defer close(n.quitCh)
 	defer close(n.diffCh)
 
 	// Wait for the common node to be initialized.
 	select {
 	case <-n.commonNode.Initialized():
 	case <-n.ctx.Done():
-		close(n.initCh)
+		n.markInitialized()
 		return
 	}
 
@@ -645,7 +987,54 @@ func (n *Node) worker() { // nolint: gocyclo
 		cachedLastRound = n.undefinedRound
 	}
 
+	// The node database may be behind the synced state in case fsync batching is enabled and the
+	// node crashed, so make sure to resume from the last round that was actually finalized.
+	if cachedLastRound != n.undefinedRound {
+		var latestVersion uint64
+		if latestVersion, err = n.localStorage.NodeDB().GetLatestVersion(n.ctx); err != nil {
+			n.logger.Error("failed to get latest storage version",
+				"err", err,
+			)
+			return
+		}
+		if latestVersion < cachedLastRound {
+			n.logger.Warn("storage database is behind the synced state, rolling back",
+				"last_synced", cachedLastRound,
+				"latest_version", latestVersion,
+			)
+
+			cachedLastRound = n.undefinedRound
+			if latestVersion >= genesisBlock.Header.Round {
+				var blk *block.Block
+				if blk, err = n.commonNode.Runtime.History().GetBlock(n.ctx, latestVersion); err != nil {
+					n.logger.Error("failed to get block for the latest storage version",
+						"err", err,
+						"round", latestVersion,
+					)
+					return
+				}
+				// An empty node database also reports version zero, so check for the roots.
+				summary := summaryFromBlock(blk)
+				if n.localStorage.NodeDB().HasRoot(summary.StateRoot) {
+					cachedLastRound = n.flushSyncedState(summary)
+				}
+			}
+		}
+	}
+
+	// Check the node database for unreadable roots and recover if enabled.
+	var needsRecovery bool
+	if n.recoveryCfg.AutoRecover {
+		if needsRecovery, err = n.recoverStorage(cachedLastRound); err != nil {
+			n.logger.Error("failed to recover storage",
+				"err", err,
+			)
+			return
+		}
+	}
+
 	// Initialize genesis from the runtime descriptor.
+	var genesisCheckpointRt *registryApi.Runtime
 	if cachedLastRound == n.undefinedRound {
 		var rt *registryApi.Runtime
 		rt, err = n.commonNode.Runtime.RegistryDescriptor(n.ctx)
@@ -655,12 +1044,16 @@ func (n *Node) worker() { // nolint: gocyclo
 			)
 			return
 		}
-		if err = n.initGenesis(rt); err != nil {
+		var needsCheckpoint bool
+		if needsCheckpoint, err = n.initGenesis(rt); err != nil {
 			n.logger.Error("failed to initialize storage at genesis",
 				"err", err,
 			)
 			return
 		}
+		if needsCheckpoint {
+			genesisCheckpointRt = rt
+		}
 	}
 
 	n.logger.Info("worker initialized",
@@ -668,6 +1061,14 @@ func (n *Node) worker() { // nolint: gocyclo
 		"last_synced", cachedLastRound,
 	)
 
+	// Apply storage parameters from the runtime descriptor (including starting the checkpointer if
+	// enabled) and watch for changes.
+	fetcherGroup.Add(1)
+	go func() {
+		defer fetcherGroup.Done()
+		n.watchStorageParameters()
+	}()
+
 	outOfOrderDiffs := &outOfOrderRoundQueue{}
 	outOfOrderApplieds := &outOfOrderRoundQueue{}
 	syncingRounds := make(map[uint64]*inFlight)
@@ -676,27 +1077,45 @@ func (n *Node) worker() { // nolint: gocyclo
 
 	heap.Init(outOfOrderDiffs)
 
-	// We are now ready to service requests.
-	registeredCh := make(chan interface{})
-	n.roleProvider.SetAvailableWithCallback(func(nd *node.Node) error {
-		nd.AddOrUpdateRuntime(n.commonNode.Runtime.ID())
-		return nil
-	}, func(ctx context.Context) error {
-		close(registeredCh)
-		return nil
-	})
+	// We are now ready to service requests. Read-only replicas do not register.
+	if n.roleProvider != nil {
+		registeredCh := make(chan interface{})
+		n.roleProvider.SetAvailableWithCallback(n.registerNodeHook, func(ctx context.Context) error {
+			close(registeredCh)
+			return nil
+		})
 
-	// Wait for the registration to finish, because we'll need to ask
-	// questions immediately.
-	n.logger.Debug("waiting for node registration to finish")
-	select {
-	case <-registeredCh:
-	case <-n.ctx.Done():
-		return
+		// Wait for the registration to finish, because we'll need to ask
+		// questions immediately.
+		n.logger.Debug("waiting for node registration to finish")
+		select {
+		case <-registeredCh:
+		case <-n.ctx.Done():
+			return
+		}
+	}
+
+	// Restore the genesis state from the referenced checkpoint if needed.
+	if genesisCheckpointRt != nil {
+		var summary *blockSummary
+		summary, err = n.syncGenesisCheckpoint(genesisCheckpointRt)
+		if err != nil {
+			n.logger.Error("genesis checkpoint sync failed, assuming replication",
+				"err", err,
+			)
+		} else {
+			cachedLastRound = n.flushSyncedState(summary)
+			lastFullyAppliedRound = cachedLastRound
+			n.logger.Info("genesis checkpoint sync succeeded",
+				"round", summary.Round,
+				"state_root", summary.StateRoot,
+			)
+		}
 	}
 
-	// Try to perform initial sync from state and io checkpoints.
-	if !n.checkpointSyncDisabled {
+	// Try to perform initial sync from state and io checkpoints. In case the last synced roots are
+	// unreadable, this is the only way to recover so it is done even if checkpoint sync is disabled.
+	if !n.checkpointSyncCfg.Disabled || needsRecovery {
 		var summary *blockSummary
 		summary, err = n.syncCheckpoints()
 		if err != nil {
@@ -706,9 +1125,15 @@ func (n *Node) worker() { // nolint: gocyclo
 			n.logger.Info("first checkpoint sync failed, trying once more", "err", err)
 			summary, err = n.syncCheckpoints()
 		}
-		if err != nil {
+		switch {
+		case err != nil && needsRecovery:
+			n.logger.Error("failed to recover storage from checkpoints",
+				"err", err,
+			)
+			return
+		case err != nil:
 			n.logger.Info("checkpoint sync failed", "err", err)
-		} else {
+		default:
 			cachedLastRound = n.flushSyncedState(summary)
 			lastFullyAppliedRound = cachedLastRound
 			n.logger.Info("checkpoint sync succeeded",
@@ -716,7 +1141,7 @@ func (n *Node) worker() { // nolint: gocyclo
 			)
 		}
 	}
-	close(n.initCh)
+	n.markInitialized()
 
 	// Main processing loop. When a new block comes in, its state and io roots are inspected and their
 	// writelogs fetched from remote storage nodes in case we don't have them locally yet. Fetches are
@@ -737,15 +1162,24 @@ mainLoop:
 			lastDiff := heap.Pop(outOfOrderDiffs).(*fetchedDiff)
 			// Apply the write log if one exists.
 			if lastDiff.fetched {
-				_, err = n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
+				err = n.localStorage.ApplyIterator(n.ctx, &storageApi.ApplyIteratorRequest{
 					Namespace: lastDiff.thisRoot.Namespace,
 					SrcRound:  lastDiff.prevRoot.Version,
 					SrcRoot:   lastDiff.prevRoot.Hash,
 					DstRound:  lastDiff.thisRoot.Version,
 					DstRoot:   lastDiff.thisRoot.Hash,
-					WriteLog:  lastDiff.writeLog,
+					WriteLog:  writelog.NewStaticIterator(lastDiff.writeLog),
 				})
-				if err != nil {
+				// Release the fetched write log as soon as possible as it can be large.
+				lastDiff.writeLog = nil
+				switch {
+				case err == nil:
+				case n.diffSyncCfg.ValidateApply && errors.Is(err, storageApi.ErrExpectedRootMismatch):
+					// The diff did not result in the expected root. Instead of proceeding to
+					// finalization (which would fail), re-fetch the diff from a different node.
+					n.handleApplyRootMismatch(syncingRounds[lastDiff.round], lastDiff)
+					continue
+				default:
 					n.logger.Error("can't apply write log",
 						"err", err,
 						"old_root", lastDiff.prevRoot,
@@ -848,6 +1282,8 @@ mainLoop:
 					syncing = &inFlight{
 						outstanding:   maskNone,
 						awaitingRetry: maskAll,
+						progress:      make(map[outstandingMask]*diffProgress),
+						avoid:         make(map[outstandingMask][]signature.PublicKey),
 					}
 					syncingRounds[i] = syncing
 
@@ -872,20 +1308,18 @@ mainLoop:
 				if (syncing.outstanding&maskIO) == 0 && (syncing.awaitingRetry&maskIO) != 0 {
 					syncing.outstanding |= maskIO
 					syncing.awaitingRetry &= ^maskIO
-					fetcherGroup.Add(1)
-					n.fetchPool.Submit(func() {
-						defer fetcherGroup.Done()
-						n.fetchDiff(this.Round, &prevIORoot, &this.IORoot, maskIO)
-					})
+					progress := syncing.progress[maskIO]
+					delete(syncing.progress, maskIO)
+					avoid := syncing.avoid[maskIO]
+					n.submitFetchDiff(&fetcherGroup, this.Round, &prevIORoot, &this.IORoot, maskIO, progress, avoid)
 				}
 				if (syncing.outstanding&maskState) == 0 && (syncing.awaitingRetry&maskState) != 0 {
 					syncing.outstanding |= maskState
 					syncing.awaitingRetry &= ^maskState
-					fetcherGroup.Add(1)
-					n.fetchPool.Submit(func() {
-						defer fetcherGroup.Done()
-						n.fetchDiff(this.Round, &prev.StateRoot, &this.StateRoot, maskState)
-					})
+					progress := syncing.progress[maskState]
+					delete(syncing.progress, maskState)
+					avoid := syncing.avoid[maskState]
+					n.submitFetchDiff(&fetcherGroup, this.Round, &prev.StateRoot, &this.StateRoot, maskState, progress, avoid)
 				}
 			}
 
@@ -897,9 +1331,13 @@ mainLoop:
 					"old_root", item.prevRoot,
 					"new_root", item.thisRoot,
 					"fetch_mask", item.fetchMask,
+					"resumable", item.progress != nil,
 				)
 				syncingRounds[item.round].outstanding &= ^item.fetchMask
 				syncingRounds[item.round].awaitingRetry |= item.fetchMask
+				if item.progress != nil {
+					syncingRounds[item.round].progress[item.fetchMask] = item.progress
+				}
 			} else {
 				heap.Push(outOfOrderDiffs, item)
 			}
@@ -911,9 +1349,7 @@ mainLoop:
 			storageWorkerLastFullRound.With(n.getMetricLabels()).Set(float64(finalized.Round))
 
 			// Notify the checkpointer that there is a new finalized round.
-			if n.checkpointer != nil {
-				n.checkpointer.NotifyNewVersion(finalized.Round)
-			}
+			n.notifyCheckpointer(finalized.Round)
 
 		case <-n.ctx.Done():
 			break mainLoop
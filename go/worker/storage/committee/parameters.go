@@ -0,0 +1,96 @@
+package committee
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	registryApi "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
+)
+
+// LogEventStorageParametersUpdated is a log event value that signals that the storage parameters in
+// the runtime descriptor have been updated.
+const LogEventStorageParametersUpdated = "worker/storage/parameters-updated"
+
+// updateStorageParameters applies the storage parameters from the given runtime descriptor.
+func (n *Node) updateStorageParameters(rt *registryApi.Runtime) {
+	params := rt.Storage
+
+	n.commonNode.CrossNode.Lock()
+	old := n.storageParams
+	changed := old == nil || *old != params
+	if changed {
+		n.storageParams = &params
+
+		if old != nil {
+			n.logger.Info("runtime storage parameters updated",
+				"group_size", params.GroupSize,
+				"min_write_replication", params.MinWriteReplication,
+				"max_apply_write_log_entries", params.MaxApplyWriteLogEntries,
+				"max_apply_ops", params.MaxApplyOps,
+				"checkpoint_interval", params.CheckpointInterval,
+				"checkpoint_num_kept", params.CheckpointNumKept,
+				"checkpoint_chunk_size", params.CheckpointChunkSize,
+				"old_params", old,
+				logging.LogEvent, LogEventStorageParametersUpdated,
+			)
+
+			// Refresh the access policy for the updated runtime.
+			n.updateExternalServicePolicyLocked(n.commonNode.Group.GetEpochSnapshot())
+		}
+		// Re-check the replication factor against the current storage committee.
+		n.checkReplicationLocked(n.commonNode.Group.GetEpochSnapshot())
+	}
+	n.commonNode.CrossNode.Unlock()
+
+	if !changed || n.checkpointerCfg == nil {
+		return
+	}
+	if err := n.updateCheckpointer(rt); err != nil {
+		n.logger.Error("failed to update checkpointer",
+			"err", err,
+		)
+	}
+}
+
+// checkReplicationLocked checks whether the storage committee is large enough to satisfy the
+// minimum write replication factor and logs a warning otherwise.
+//
+// Guarded by CrossNode.
+func (n *Node) checkReplicationLocked(snapshot *committee.EpochSnapshot) {
+	if n.storageParams == nil {
+		return
+	}
+	sc := snapshot.GetStorageCommittee()
+	if sc == nil || sc.Committee == nil {
+		return
+	}
+
+	if size := uint64(len(sc.Committee.Members)); size < n.storageParams.MinWriteReplication {
+		n.logger.Warn("storage committee is smaller than the minimum write replication factor, writes will fail",
+			"committee_size", size,
+			"min_write_replication", n.storageParams.MinWriteReplication,
+			"epoch", snapshot.GetEpochNumber(),
+		)
+	}
+}
+
+// watchStorageParameters watches runtime descriptor updates and applies any changes to the storage
+// parameters.
+func (n *Node) watchStorageParameters() {
+	rtCh, rtSub, err := n.commonNode.Runtime.WatchRegistryDescriptor()
+	if err != nil {
+		n.logger.Error("failed to watch runtime descriptor updates",
+			"err", err,
+		)
+		return
+	}
+	defer rtSub.Close()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case rt := <-rtCh:
+			n.updateStorageParameters(rt)
+		}
+	}
+}
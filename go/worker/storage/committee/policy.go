@@ -1,37 +1,131 @@
 package committee
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 )
 
-// Define storage access policies for all the relevant committees and node
-// groups.
+// Grant is a storage gRPC access grant, specifying a group of clients that is allowed to use a
+// given group of storage RPC methods.
+type Grant string
+
+const (
+	// GrantExecutor grants access to members of the runtime's current executor committee.
+	GrantExecutor Grant = "executor"
+	// GrantStorage grants access to all storage nodes registered for the runtime.
+	GrantStorage Grant = "storage"
+	// GrantSentry grants access to the configured sentry nodes.
+	GrantSentry Grant = "sentry"
+	// GrantPublic grants access to any client.
+	GrantPublic Grant = "public"
+)
+
+// PolicyConfig is the storage gRPC access policy configuration, specifying which clients are
+// allowed to use each group of storage RPC methods.
+type PolicyConfig struct {
+	// Apply are the grants for the Apply and ApplyBatch methods.
+	Apply []Grant
+	// Diff are the grants for the GetDiff method.
+	Diff []Grant
+	// Checkpoint are the grants for the GetCheckpoints and GetCheckpointChunk methods.
+	Checkpoint []Grant
+	// Read are the grants for the SyncGet, SyncGetPrefixes and SyncIterate methods.
+	Read []Grant
+}
+
+// DefaultPolicyConfig returns the default storage gRPC access policy configuration.
+//
+// NOTE: GetDiff/GetCheckpoint* need to be accessible to all storage nodes, not just the ones in
+// the current storage committee so that new nodes can sync-up.
+func DefaultPolicyConfig() *PolicyConfig {
+	return &PolicyConfig{
+		Apply:      []Grant{GrantExecutor, GrantSentry},
+		Diff:       []Grant{GrantStorage, GrantSentry},
+		Checkpoint: []Grant{GrantStorage, GrantSentry},
+		Read:       []Grant{GrantPublic},
+	}
+}
+
+// ParseGrants parses a list of access grants.
+func ParseGrants(raw []string) ([]Grant, error) {
+	grants := make([]Grant, 0, len(raw))
+	for _, r := range raw {
+		g := Grant(strings.ToLower(strings.TrimSpace(r)))
+		switch g {
+		case GrantExecutor, GrantStorage, GrantSentry, GrantPublic:
+		default:
+			return nil, fmt.Errorf("invalid storage access grant: %s", r)
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+// Define the storage RPC method groups that share an access policy.
 var (
-	executorCommitteePolicy = &committee.AccessPolicy{
-		Actions: []accessctl.Action{
-			accessctl.Action(api.MethodApply.FullName()),
-			accessctl.Action(api.MethodApplyBatch.FullName()),
-		},
-	}
-	// NOTE: GetDiff/GetCheckpoint* need to be accessible to all storage nodes,
-	// not just the ones in the current storage committee so that new nodes can
-	// sync-up.
-	storageNodesPolicy = &committee.AccessPolicy{
-		Actions: []accessctl.Action{
-			accessctl.Action(api.MethodGetDiff.FullName()),
-			accessctl.Action(api.MethodGetCheckpoints.FullName()),
-			accessctl.Action(api.MethodGetCheckpointChunk.FullName()),
-		},
-	}
-	sentryNodesPolicy = &committee.AccessPolicy{
-		Actions: []accessctl.Action{
-			accessctl.Action(api.MethodGetDiff.FullName()),
-			accessctl.Action(api.MethodGetCheckpoints.FullName()),
-			accessctl.Action(api.MethodGetCheckpointChunk.FullName()),
-			accessctl.Action(api.MethodApply.FullName()),
-			accessctl.Action(api.MethodApplyBatch.FullName()),
-		},
+	applyActions = []accessctl.Action{
+		accessctl.Action(api.MethodApply.FullName()),
+		accessctl.Action(api.MethodApplyBatch.FullName()),
+	}
+	diffActions = []accessctl.Action{
+		accessctl.Action(api.MethodGetDiff.FullName()),
+	}
+	checkpointActions = []accessctl.Action{
+		accessctl.Action(api.MethodGetCheckpoints.FullName()),
+		accessctl.Action(api.MethodGetCheckpointChunk.FullName()),
+	}
+	readActions = []accessctl.Action{
+		accessctl.Action(api.MethodSyncGet.FullName()),
+		accessctl.Action(api.MethodSyncGetPrefixes.FullName()),
+		accessctl.Action(api.MethodSyncIterate.FullName()),
 	}
 )
+
+// accessPolicies are the access policies for each of the access grants.
+type accessPolicies struct {
+	executor *committee.AccessPolicy
+	storage  *committee.AccessPolicy
+	sentry   *committee.AccessPolicy
+	public   *committee.AccessPolicy
+}
+
+// newAccessPolicies builds per-grant access policies from the given configuration.
+func newAccessPolicies(cfg *PolicyConfig) *accessPolicies {
+	aps := &accessPolicies{
+		executor: &committee.AccessPolicy{},
+		storage:  &committee.AccessPolicy{},
+		sentry:   &committee.AccessPolicy{},
+		public:   &committee.AccessPolicy{},
+	}
+	for _, group := range []struct {
+		grants  []Grant
+		actions []accessctl.Action
+	}{
+		{cfg.Apply, applyActions},
+		{cfg.Diff, diffActions},
+		{cfg.Checkpoint, checkpointActions},
+		{cfg.Read, readActions},
+	} {
+		for _, grant := range group.grants {
+			var ap *committee.AccessPolicy
+			switch grant {
+			case GrantExecutor:
+				ap = aps.executor
+			case GrantStorage:
+				ap = aps.storage
+			case GrantSentry:
+				ap = aps.sentry
+			case GrantPublic:
+				ap = aps.public
+			default:
+				continue
+			}
+			ap.Actions = append(ap.Actions, group.actions...)
+		}
+	}
+	return aps
+}
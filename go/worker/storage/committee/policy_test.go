@@ -0,0 +1,48 @@
+package committee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+func TestParseGrants(t *testing.T) {
+	require := require.New(t)
+
+	grants, err := ParseGrants([]string{"executor", " Storage", "SENTRY", "public"})
+	require.NoError(err, "ParseGrants")
+	require.Equal([]Grant{GrantExecutor, GrantStorage, GrantSentry, GrantPublic}, grants)
+
+	grants, err = ParseGrants(nil)
+	require.NoError(err, "ParseGrants(empty)")
+	require.Empty(grants)
+
+	_, err = ParseGrants([]string{"storage", "everyone"})
+	require.Error(err, "ParseGrants should fail for unknown grants")
+}
+
+func TestAccessPolicies(t *testing.T) {
+	require := require.New(t)
+
+	// Default policy should match the historic storage access policy.
+	aps := newAccessPolicies(DefaultPolicyConfig())
+	require.ElementsMatch(applyActions, aps.executor.Actions)
+	require.ElementsMatch(append(append([]accessctl.Action{}, diffActions...), checkpointActions...), aps.storage.Actions)
+	require.ElementsMatch(append(append(append([]accessctl.Action{}, applyActions...), diffActions...), checkpointActions...), aps.sentry.Actions)
+	require.ElementsMatch(readActions, aps.public.Actions)
+
+	// Public checkpoint serving with committee-only applies.
+	aps = newAccessPolicies(&PolicyConfig{
+		Apply:      []Grant{GrantExecutor},
+		Diff:       []Grant{GrantStorage},
+		Checkpoint: []Grant{GrantPublic},
+	})
+	require.ElementsMatch(applyActions, aps.executor.Actions)
+	require.ElementsMatch(diffActions, aps.storage.Actions)
+	require.Empty(aps.sentry.Actions)
+	require.ElementsMatch(checkpointActions, aps.public.Actions)
+	require.NotContains(aps.public.Actions, accessctl.Action(api.MethodApply.FullName()))
+}
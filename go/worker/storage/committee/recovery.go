@@ -0,0 +1,121 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// RecoveryConfig is the storage worker recovery configuration.
+type RecoveryConfig struct {
+	// AutoRecover specifies whether the worker should automatically recover from a corrupted
+	// local node database on startup. Unfinalized versions with unreadable roots are discarded
+	// and fetched again from remote storage nodes, while unreadable finalized roots are restored
+	// from checkpoints.
+	AutoRecover bool
+}
+
+// checkRootReadable checks whether the root node of the given root can be read from the node
+// database.
+func checkRootReadable(ndb mkvsDB.NodeDB, root mkvsNode.Root) error {
+	if root.Hash.IsEmpty() {
+		return nil
+	}
+	if !ndb.HasRoot(root) {
+		return mkvsDB.ErrRootNotFound
+	}
+
+	n, err := ndb.GetNode(root, &mkvsNode.Pointer{Clean: true, Hash: root.Hash})
+	if err != nil {
+		return err
+	}
+	if h := n.GetHash(); !h.Equal(&root.Hash) {
+		return fmt.Errorf("root node hash mismatch (expected: %s got: %s)", root.Hash, h)
+	}
+	return nil
+}
+
+// findUnreadableRoots returns the roots in versions starting with the given version which cannot
+// be read from the node database. The versions are checked in order until the first version
+// without any roots.
+func findUnreadableRoots(
+	ctx context.Context,
+	ndb mkvsDB.NodeDB,
+	namespace common.Namespace,
+	version uint64,
+) ([]mkvsNode.Root, error) {
+	var unreadable []mkvsNode.Root
+	for ; ; version++ {
+		roots, err := ndb.GetRootsForVersion(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		if len(roots) == 0 {
+			return unreadable, nil
+		}
+
+		for _, rootHash := range roots {
+			root := mkvsNode.Root{Namespace: namespace, Version: version, Hash: rootHash}
+			if checkRootReadable(ndb, root) != nil {
+				unreadable = append(unreadable, root)
+			}
+		}
+	}
+}
+
+// recoverStorage checks the local node database for unreadable roots and recovers from any
+// corruption that can be repaired locally. Unfinalized versions are discarded in case any of
+// their roots are unreadable, so that they are fetched again.
+//
+// It returns true in case the roots of the last synced round are unreadable, in which case the
+// worker needs to restore the state from checkpoints.
+func (n *Node) recoverStorage(lastRound uint64) (bool, error) {
+	ndb := n.localStorage.NodeDB()
+
+	firstUnfinalized := n.undefinedRound + 1
+	if lastRound != n.undefinedRound {
+		latestVersion, err := ndb.GetLatestVersion(n.ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get latest storage version: %w", err)
+		}
+		firstUnfinalized = latestVersion + 1
+	}
+
+	unreadable, err := findUnreadableRoots(n.ctx, ndb, n.commonNode.Runtime.ID(), firstUnfinalized)
+	if err != nil {
+		return false, fmt.Errorf("failed to check unfinalized roots: %w", err)
+	}
+	if len(unreadable) > 0 {
+		n.logger.Warn("unreadable roots in unfinalized versions, discarding",
+			"roots", unreadable,
+		)
+
+		var discarded []uint64
+		if discarded, err = ndb.DiscardUnfinalized(n.ctx); err != nil {
+			return false, fmt.Errorf("failed to discard unfinalized versions: %w", err)
+		}
+		n.logger.Info("discarded unfinalized versions",
+			"versions", discarded,
+		)
+	}
+
+	if lastRound == n.undefinedRound {
+		return false, nil
+	}
+
+	_, lastIORoot, lastStateRoot := n.GetLastSynced()
+	for _, root := range []mkvsNode.Root{lastIORoot, lastStateRoot} {
+		if err = checkRootReadable(ndb, root); err != nil {
+			n.logger.Error("last synced root is unreadable, will restore from checkpoints",
+				"err", err,
+				"round", lastRound,
+				"root", root,
+			)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,94 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerDb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+var errTestCorrupted = errors.New("corrupted node")
+
+// corruptedNodeDB is a node database where some nodes cannot be read.
+type corruptedNodeDB struct {
+	mkvsDB.NodeDB
+
+	corrupted map[hash.Hash]bool
+}
+
+func (d *corruptedNodeDB) GetNode(root mkvsNode.Root, ptr *mkvsNode.Pointer) (mkvsNode.Node, error) {
+	if d.corrupted[ptr.Hash] {
+		return nil, errTestCorrupted
+	}
+	return d.NodeDB.GetNode(root, ptr)
+}
+
+func TestFindUnreadableRoots(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage worker recovery test ns"), 0)
+	ndb, err := badgerDb.New(&mkvsDB.Config{
+		Namespace:    ns,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+		MemoryOnly:   true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	root := mkvsNode.Root{Namespace: ns}
+	root.Hash.Empty()
+	var roots []mkvsNode.Root
+	for version := uint64(0); version < 3; version++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		err = tree.Insert(ctx, []byte("foo"), []byte{byte(version)})
+		require.NoError(err, "Insert")
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, ns, version)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		root = mkvsNode.Root{Namespace: ns, Version: version, Hash: rootHash}
+		roots = append(roots, root)
+	}
+	err = ndb.Finalize(ctx, 0, []hash.Hash{roots[0].Hash})
+	require.NoError(err, "Finalize")
+
+	cdb := &corruptedNodeDB{NodeDB: ndb, corrupted: make(map[hash.Hash]bool)}
+	for _, r := range roots {
+		require.NoError(checkRootReadable(cdb, r), "root should be readable")
+	}
+	var emptyRoot mkvsNode.Root
+	emptyRoot.Namespace = ns
+	emptyRoot.Hash.Empty()
+	require.NoError(checkRootReadable(cdb, emptyRoot), "empty root should be readable")
+	missingRoot := mkvsNode.Root{Namespace: ns, Version: 1, Hash: hash.NewFromBytes([]byte("missing"))}
+	require.True(errors.Is(checkRootReadable(cdb, missingRoot), mkvsDB.ErrRootNotFound), "missing root should not be readable")
+
+	unreadable, err := findUnreadableRoots(ctx, cdb, ns, 1)
+	require.NoError(err, "findUnreadableRoots")
+	require.Empty(unreadable, "there should be no unreadable roots")
+
+	cdb.corrupted[roots[2].Hash] = true
+	require.True(errors.Is(checkRootReadable(cdb, roots[2]), errTestCorrupted), "corrupted root should not be readable")
+	unreadable, err = findUnreadableRoots(ctx, cdb, ns, 1)
+	require.NoError(err, "findUnreadableRoots")
+	require.EqualValues([]mkvsNode.Root{roots[2]}, unreadable, "corrupted root should be reported")
+
+	// Discarding unfinalized versions should remove the corrupted root.
+	_, err = cdb.DiscardUnfinalized(ctx)
+	require.NoError(err, "DiscardUnfinalized")
+	unreadable, err = findUnreadableRoots(ctx, cdb, ns, 1)
+	require.NoError(err, "findUnreadableRoots")
+	require.Empty(unreadable, "there should be no unreadable roots after discarding")
+	require.NoError(checkRootReadable(cdb, roots[0]), "finalized root should be readable")
+}
@@ -3,6 +3,7 @@ package storage
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"time"
@@ -16,6 +17,9 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/committee"
 )
 
 const (
@@ -28,8 +32,60 @@ const (
 	// CfgWorkerCheckpointCheckInterval configures the checkpointer check interval.
 	CfgWorkerCheckpointCheckInterval = "worker.storage.checkpointer.check_interval"
 
+	// CfgWorkerCheckpointStoreEndpoint configures the API endpoint of an S3-compatible object
+	// store that created checkpoints are uploaded to.
+	CfgWorkerCheckpointStoreEndpoint = "worker.storage.checkpointer.store.endpoint"
+	// CfgWorkerCheckpointStoreRegion configures the checkpoint object store region.
+	CfgWorkerCheckpointStoreRegion = "worker.storage.checkpointer.store.region"
+	// CfgWorkerCheckpointStoreBucket configures the checkpoint object store bucket.
+	CfgWorkerCheckpointStoreBucket = "worker.storage.checkpointer.store.bucket"
+	// CfgWorkerCheckpointStorePrefix configures the checkpoint object store key prefix.
+	CfgWorkerCheckpointStorePrefix = "worker.storage.checkpointer.store.prefix"
+	// CfgWorkerCheckpointStoreAccessKeyID configures the checkpoint object store access key ID.
+	CfgWorkerCheckpointStoreAccessKeyID = "worker.storage.checkpointer.store.access_key_id"
+	// CfgWorkerCheckpointStoreSecretAccessKeyFile configures the path to the file containing the
+	// checkpoint object store secret access key.
+	CfgWorkerCheckpointStoreSecretAccessKeyFile = "worker.storage.checkpointer.store.secret_access_key_file"
+	// CfgWorkerCheckpointStorePublicURL configures the base URL from which uploaded checkpoints
+	// can be publicly fetched and which is advertised in the node descriptor.
+	CfgWorkerCheckpointStorePublicURL = "worker.storage.checkpointer.store.public_url"
+
 	// CfgCheckpointSyncDisabled disables syncing from checkpoints on worker startup.
 	CfgWorkerCheckpointSyncDisabled = "worker.storage.checkpoint_sync.disabled"
+	// CfgWorkerCheckpointSyncChunkFetchersPerNode configures the number of chunks fetched
+	// concurrently from each storage node during checkpoint sync.
+	CfgWorkerCheckpointSyncChunkFetchersPerNode = "worker.storage.checkpoint_sync.chunk_fetchers_per_node"
+	// CfgWorkerCheckpointSyncRestoreWorkers configures the number of chunks restored concurrently
+	// during checkpoint sync.
+	CfgWorkerCheckpointSyncRestoreWorkers = "worker.storage.checkpoint_sync.restore_workers"
+	// CfgWorkerCheckpointSyncMemoryBudget configures the maximum amount of memory used for
+	// buffering fetched chunks during checkpoint sync.
+	CfgWorkerCheckpointSyncMemoryBudget = "worker.storage.checkpoint_sync.memory_budget"
+	// CfgWorkerCheckpointSyncExternalStoresDisabled disables fetching checkpoint chunks from
+	// external checkpoint stores advertised by storage nodes.
+	CfgWorkerCheckpointSyncExternalStoresDisabled = "worker.storage.checkpoint_sync.external_stores.disabled"
+
+	// CfgWorkerDiffSyncVerify enables verification of fetched diffs before they are applied.
+	CfgWorkerDiffSyncVerify = "worker.storage.diff_sync.verify"
+	// CfgWorkerDiffSyncValidateApply enables re-fetching of diffs that do not result in the
+	// expected root when applied.
+	CfgWorkerDiffSyncValidateApply = "worker.storage.diff_sync.validate_apply"
+
+	// CfgWorkerAutoRecover enables automatic recovery from a corrupted local storage database.
+	CfgWorkerAutoRecover = "worker.storage.auto_recover"
+
+	// CfgWorkerPolicyApply configures the clients allowed to apply updates.
+	CfgWorkerPolicyApply = "worker.storage.policy.apply"
+	// CfgWorkerPolicyDiff configures the clients allowed to fetch diffs.
+	CfgWorkerPolicyDiff = "worker.storage.policy.diff"
+	// CfgWorkerPolicyCheckpoint configures the clients allowed to fetch checkpoints.
+	CfgWorkerPolicyCheckpoint = "worker.storage.policy.checkpoint"
+	// CfgWorkerPolicyRead configures the clients allowed to perform read queries.
+	CfgWorkerPolicyRead = "worker.storage.policy.read"
+
+	// CfgWorkerReadOnlyReplica configures the storage worker to run as a read-only replica
+	// that follows the runtime state without registering or joining storage committees.
+	CfgWorkerReadOnlyReplica = "worker.storage.read_only_replica"
 
 	// CfgWorkerDebugIgnoreApply is a debug option that makes the worker ignore
 	// all apply operations.
@@ -44,6 +100,39 @@ const (
 	// CfgMaxCacheSize configures the maximum in-memory cache size.
 	CfgMaxCacheSize = "worker.storage.max_cache_size"
 
+	// CfgTreeCacheSize configures the memory budget shared by the in-memory caches of all trees
+	// backed by the storage database.
+	CfgTreeCacheSize = "worker.storage.tree_cache_size"
+
+	// CfgFsyncBatchCommits configures the number of commits after which the storage database is
+	// synced to disk.
+	CfgFsyncBatchCommits = "worker.storage.fsync_batch.commits"
+	// CfgFsyncBatchInterval configures the maximum time a commit to the storage database can
+	// remain unsynced.
+	CfgFsyncBatchInterval = "worker.storage.fsync_batch.interval"
+
+	// CfgKeyFilterCapacity configures the expected number of keys in the key existence filter.
+	CfgKeyFilterCapacity = "worker.storage.key_filter.capacity"
+	// CfgKeyFilterFalsePositiveRate configures the key existence filter false positive rate.
+	CfgKeyFilterFalsePositiveRate = "worker.storage.key_filter.false_positive_rate"
+
+	// CfgGCInterval configures the interval between periodic storage database garbage collection
+	// runs.
+	CfgGCInterval = "worker.storage.gc.interval"
+	// CfgGCDiscardRatio configures the fraction of a database log file that must be discardable
+	// for the file to be rewritten during garbage collection.
+	CfgGCDiscardRatio = "worker.storage.gc.discard_ratio"
+	// CfgGCMaxRewrites configures the maximum number of database log files rewritten in a single
+	// garbage collection run.
+	CfgGCMaxRewrites = "worker.storage.gc.max_rewrites"
+
+	// CfgSharedNodeDB configures the path to a node database shared by all runtimes.
+	CfgSharedNodeDB = "worker.storage.shared_node_db"
+
+	// CfgWriteLogBufferSize configures the number of write log entries buffered while streaming
+	// write logs from the storage database.
+	CfgWriteLogBufferSize = "worker.storage.write_log_buffer_size"
+
 	cfgCrashEnabled       = "worker.storage.crash.enabled"
 	cfgInsecureSkipChecks = "worker.storage.debug.insecure_skip_checks"
 )
@@ -51,6 +140,63 @@ const (
 // Flags has the configuration flags.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+func grantsToStrings(grants []committee.Grant) []string {
+	s := make([]string, 0, len(grants))
+	for _, g := range grants {
+		s = append(s, string(g))
+	}
+	return s
+}
+
+// policyConfig constructs the storage gRPC access policy configuration based on the
+// configuration flags.
+func policyConfig() (*committee.PolicyConfig, error) {
+	var (
+		cfg committee.PolicyConfig
+		err error
+	)
+	for _, v := range []struct {
+		flag   string
+		grants *[]committee.Grant
+	}{
+		{CfgWorkerPolicyApply, &cfg.Apply},
+		{CfgWorkerPolicyDiff, &cfg.Diff},
+		{CfgWorkerPolicyCheckpoint, &cfg.Checkpoint},
+		{CfgWorkerPolicyRead, &cfg.Read},
+	} {
+		if *v.grants, err = committee.ParseGrants(viper.GetStringSlice(v.flag)); err != nil {
+			return nil, fmt.Errorf("%s: %w", v.flag, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// checkpointStore constructs the external checkpoint object store based on the configuration
+// flags. It returns nil in case no object store is configured.
+func checkpointStore() (checkpoint.ObjectStore, error) {
+	endpoint := viper.GetString(CfgWorkerCheckpointStoreEndpoint)
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	cfg := &checkpoint.S3StoreConfig{
+		Endpoint:    endpoint,
+		Region:      viper.GetString(CfgWorkerCheckpointStoreRegion),
+		Bucket:      viper.GetString(CfgWorkerCheckpointStoreBucket),
+		Prefix:      viper.GetString(CfgWorkerCheckpointStorePrefix),
+		AccessKeyID: viper.GetString(CfgWorkerCheckpointStoreAccessKeyID),
+		PublicURL:   viper.GetString(CfgWorkerCheckpointStorePublicURL),
+	}
+	if secretFile := viper.GetString(CfgWorkerCheckpointStoreSecretAccessKeyFile); secretFile != "" {
+		secret, err := ioutil.ReadFile(secretFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", CfgWorkerCheckpointStoreSecretAccessKeyFile, err)
+		}
+		cfg.SecretAccessKey = strings.TrimSpace(string(secret))
+	}
+	return checkpoint.NewS3Store(cfg)
+}
+
 // NewLocalBackend constructs a new Backend based on the configuration flags.
 func NewLocalBackend(
 	dataDir string,
@@ -65,6 +211,25 @@ func NewLocalBackend(
 		InsecureSkipChecks: viper.GetBool(cfgInsecureSkipChecks) && cmdFlags.DebugDontBlameOasis(),
 		Namespace:          namespace,
 		MaxCacheSize:       int64(viper.GetSizeInBytes(CfgMaxCacheSize)),
+		TreeCacheSize:      uint64(viper.GetSizeInBytes(CfgTreeCacheSize)),
+
+		FsyncBatch: nodedb.FsyncBatchPolicy{
+			Commits:  viper.GetUint64(CfgFsyncBatchCommits),
+			Interval: viper.GetDuration(CfgFsyncBatchInterval),
+		},
+
+		KeyFilterCapacity:          viper.GetUint64(CfgKeyFilterCapacity),
+		KeyFilterFalsePositiveRate: viper.GetFloat64(CfgKeyFilterFalsePositiveRate),
+
+		SharedNodeDB: viper.GetString(CfgSharedNodeDB),
+
+		WriteLogBufferSize: viper.GetInt(CfgWriteLogBufferSize),
+
+		GC: nodedb.GCPolicy{
+			Interval:     viper.GetDuration(CfgGCInterval),
+			DiscardRatio: viper.GetFloat64(CfgGCDiscardRatio),
+			MaxRewrites:  viper.GetUint64(CfgGCMaxRewrites),
+		},
 	}
 
 	var (
@@ -95,7 +260,27 @@ func init() {
 	Flags.Uint(cfgWorkerFetcherCount, 4, "Number of concurrent storage diff fetchers")
 	Flags.Bool(CfgWorkerCheckpointerDisabled, false, "Disable the storage checkpointer")
 	Flags.Duration(CfgWorkerCheckpointCheckInterval, 1*time.Minute, "Storage checkpointer check interval")
+	Flags.String(CfgWorkerCheckpointStoreEndpoint, "", "API endpoint of an S3-compatible object store that created checkpoints are uploaded to (empty disables)")
+	Flags.String(CfgWorkerCheckpointStoreRegion, "us-east-1", "Checkpoint object store region")
+	Flags.String(CfgWorkerCheckpointStoreBucket, "", "Checkpoint object store bucket")
+	Flags.String(CfgWorkerCheckpointStorePrefix, "", "Checkpoint object store key prefix")
+	Flags.String(CfgWorkerCheckpointStoreAccessKeyID, "", "Checkpoint object store access key ID")
+	Flags.String(CfgWorkerCheckpointStoreSecretAccessKeyFile, "", "Path to the file containing the checkpoint object store secret access key")
+	Flags.String(CfgWorkerCheckpointStorePublicURL, "", "Base URL from which uploaded checkpoints can be publicly fetched (defaults to the bucket URL)")
 	Flags.Bool(CfgWorkerCheckpointSyncDisabled, false, "Disable initial storage sync from checkpoints")
+	Flags.Uint(CfgWorkerCheckpointSyncChunkFetchersPerNode, 2, "Number of concurrent checkpoint chunk fetchers per storage node")
+	Flags.Uint(CfgWorkerCheckpointSyncRestoreWorkers, 4, "Number of concurrent checkpoint chunk restore workers")
+	Flags.String(CfgWorkerCheckpointSyncMemoryBudget, "256mb", "Maximum memory used for buffering fetched checkpoint chunks")
+	Flags.Bool(CfgWorkerCheckpointSyncExternalStoresDisabled, false, "Disable fetching checkpoint chunks from external checkpoint stores advertised by storage nodes")
+	Flags.Bool(CfgWorkerDiffSyncVerify, false, "Verify fetched storage diffs against expected roots before applying them")
+	Flags.Bool(CfgWorkerDiffSyncValidateApply, false, "Re-fetch storage diffs from a different node when applying them does not result in the expected root")
+	Flags.Bool(CfgWorkerAutoRecover, false, "Automatically recover from a corrupted storage database by syncing affected versions again")
+	defaultPolicy := committee.DefaultPolicyConfig()
+	Flags.StringSlice(CfgWorkerPolicyApply, grantsToStrings(defaultPolicy.Apply), "Clients allowed to apply updates (executor, storage, sentry, public)")
+	Flags.StringSlice(CfgWorkerPolicyDiff, grantsToStrings(defaultPolicy.Diff), "Clients allowed to fetch diffs (executor, storage, sentry, public)")
+	Flags.StringSlice(CfgWorkerPolicyCheckpoint, grantsToStrings(defaultPolicy.Checkpoint), "Clients allowed to fetch checkpoints (executor, storage, sentry, public)")
+	Flags.StringSlice(CfgWorkerPolicyRead, grantsToStrings(defaultPolicy.Read), "Clients allowed to perform read queries (executor, storage, sentry, public)")
+	Flags.Bool(CfgWorkerReadOnlyReplica, false, "Run as a read-only replica that does not register as a storage node")
 
 	Flags.Bool(CfgWorkerDebugIgnoreApply, false, "Ignore Apply operations (for debugging purposes)")
 	_ = Flags.MarkHidden(CfgWorkerDebugIgnoreApply)
@@ -104,6 +289,16 @@ func init() {
 	Flags.Bool(cfgCrashEnabled, false, "Enable the crashing storage wrapper")
 	Flags.Int(CfgLRUSlots, 1000, "How many LRU slots to use for Apply call locks in the MKVS tree root cache")
 	Flags.String(CfgMaxCacheSize, "64mb", "Maximum in-memory cache size")
+	Flags.String(CfgTreeCacheSize, "64mb", "Memory budget shared by the in-memory caches of all trees backed by the storage database (0 uses per-tree capacities)")
+	Flags.Uint64(CfgFsyncBatchCommits, 0, "Sync the storage database after this many commits (0 means no limit)")
+	Flags.Duration(CfgFsyncBatchInterval, 0, "Sync the storage database at most this long after a commit (0 means no limit)")
+	Flags.Uint64(CfgKeyFilterCapacity, 0, "Expected number of keys in the key existence filter (0 disables the filter)")
+	Flags.Float64(CfgKeyFilterFalsePositiveRate, 0.01, "Key existence filter false positive rate at capacity")
+	Flags.Duration(CfgGCInterval, 5*time.Minute, "Interval between periodic storage database garbage collection runs (negative disables)")
+	Flags.Float64(CfgGCDiscardRatio, 0.5, "Fraction of a database log file that must be discardable for it to be garbage collected")
+	Flags.Uint64(CfgGCMaxRewrites, 0, "Maximum number of database log files rewritten per garbage collection run (0 means no limit)")
+	Flags.String(CfgSharedNodeDB, "", "Path to a node database shared by all runtimes to deduplicate identical nodes (empty disables)")
+	Flags.Int(CfgWriteLogBufferSize, 100, "Number of write log entries buffered while streaming write logs from the storage database")
 
 	Flags.Bool(cfgInsecureSkipChecks, false, "INSECURE: Skip known root checks")
 
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+var errReadOnly = errors.New("storage: read-only replica, rejecting update operations")
+
+// readOnlyWrapper is a storage backend wrapper that rejects all update
+// operations. It is used to expose the local storage of a read-only replica.
+type readOnlyWrapper struct {
+	api.Backend
+}
+
+func (w *readOnlyWrapper) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
+	return nil, errReadOnly
+}
+
+func (w *readOnlyWrapper) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
+	return nil, errReadOnly
+}
+
+func newReadOnlyWrapper(base api.Backend) api.Backend {
+	return &readOnlyWrapper{
+		Backend: base,
+	}
+}
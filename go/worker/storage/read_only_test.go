@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+)
+
+func TestReadOnlyBackend(t *testing.T) {
+	require := require.New(t)
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("read-only backend test ns"), 0)
+
+	var (
+		cfg = api.Config{
+			Backend:      database.BackendNameBadgerDB,
+			Namespace:    testNs,
+			MaxCacheSize: 16 * 1024 * 1024,
+		}
+		err error
+	)
+
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+
+	cfg.DB, err = ioutil.TempDir("", "readonly.test.badgerdb")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(cfg.DB)
+
+	realBackend, err := database.New(&cfg)
+	require.NoError(err, "database.New")
+	defer realBackend.Cleanup()
+	backend := newReadOnlyWrapper(realBackend)
+
+	ctx := context.Background()
+	var root api.Root
+	root.Empty()
+	root.Namespace = testNs
+
+	_, err = backend.Apply(ctx, &api.ApplyRequest{
+		Namespace: testNs,
+		SrcRound:  0,
+		SrcRoot:   root.Hash,
+		DstRound:  0,
+		DstRoot:   root.Hash,
+		WriteLog:  api.WriteLog{{Key: []byte("key"), Value: []byte("value")}},
+	})
+	require.Equal(errReadOnly, err, "Apply should be rejected")
+
+	_, err = backend.ApplyBatch(ctx, &api.ApplyBatchRequest{
+		Namespace: testNs,
+		DstRound:  0,
+	})
+	require.Equal(errReadOnly, err, "ApplyBatch should be rejected")
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
@@ -30,3 +31,34 @@ func (w *Worker) ForceFinalize(ctx context.Context, request *api.ForceFinalizeRe
 
 	return node.ForceFinalize(ctx, request.Round)
 }
+
+func (w *Worker) CreateCheckpoint(ctx context.Context, request *api.CheckpointRequest) ([]*checkpoint.Metadata, error) {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return nil, api.ErrRuntimeNotFound
+	}
+
+	return node.ForceCheckpoint(ctx, request.Round)
+}
+
+func (w *Worker) DeleteCheckpoint(ctx context.Context, request *api.CheckpointRequest) error {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return api.ErrRuntimeNotFound
+	}
+
+	return node.DeleteCheckpoints(ctx, request.Round)
+}
+
+func (w *Worker) TriggerGC(ctx context.Context, request *api.TriggerGCRequest) (*api.TriggerGCResponse, error) {
+	node := w.runtimes[request.RuntimeID]
+	if node == nil {
+		return nil, api.ErrRuntimeNotFound
+	}
+
+	rewrites, err := node.TriggerGC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TriggerGCResponse{Rewrites: rewrites}, nil
+}
@@ -31,9 +31,15 @@ func Enabled() bool {
 	return viper.GetBool(CfgWorkerEnabled)
 }
 
+// ReadOnlyReplica returns true iff the storage worker is configured to run as a read-only replica.
+func ReadOnlyReplica() bool {
+	return Enabled() && viper.GetBool(CfgWorkerReadOnlyReplica)
+}
+
 // Worker is a worker handling storage operations.
 type Worker struct {
-	enabled bool
+	enabled  bool
+	readOnly bool
 
 	commonWorker *workerCommon.Worker
 	registration *registration.Worker
@@ -47,6 +53,7 @@ type Worker struct {
 	fetchPool  *workerpool.Pool
 
 	grpcPolicy *policy.DynamicRuntimePolicyChecker
+	policyCfg  *committee.PolicyConfig
 }
 
 // New constructs a new storage worker.
@@ -59,6 +66,7 @@ func New(
 ) (*Worker, error) {
 	s := &Worker{
 		enabled:      viper.GetBool(CfgWorkerEnabled),
+		readOnly:     ReadOnlyReplica(),
 		commonWorker: commonWorker,
 		registration: registration,
 		logger:       logging.GetLogger("worker/storage"),
@@ -78,19 +86,29 @@ func New(
 			return nil, err
 		}
 
-		// Attach storage interface to gRPC server.
-		s.grpcPolicy = policy.NewDynamicRuntimePolicyChecker(api.ServiceName, s.commonWorker.GrpcPolicyWatcher)
-		api.RegisterService(s.commonWorker.Grpc.Server(), &storageService{
-			w:                  s,
-			storage:            s.commonWorker.RuntimeRegistry.StorageRouter(),
-			debugRejectUpdates: viper.GetBool(CfgWorkerDebugIgnoreApply) && flags.DebugDontBlameOasis(),
-		})
+		if s.policyCfg, err = policyConfig(); err != nil {
+			return nil, fmt.Errorf("worker/storage: invalid access policy configuration: %w", err)
+		}
+
+		// Attach storage interface to gRPC server. Read-only replicas are not part of any storage
+		// committee so they only expose their local storage via the internal gRPC interface.
+		if !s.readOnly {
+			s.grpcPolicy = policy.NewDynamicRuntimePolicyChecker(api.ServiceName, s.commonWorker.GrpcPolicyWatcher)
+			api.RegisterService(s.commonWorker.Grpc.Server(), &storageService{
+				w:                  s,
+				storage:            s.commonWorker.RuntimeRegistry.StorageRouter(),
+				debugRejectUpdates: viper.GetBool(CfgWorkerDebugIgnoreApply) && flags.DebugDontBlameOasis(),
+			})
+		}
 
 		var checkpointerCfg *checkpoint.CheckpointerConfig
 		if !viper.GetBool(CfgWorkerCheckpointerDisabled) {
 			checkpointerCfg = &checkpoint.CheckpointerConfig{
 				CheckInterval: viper.GetDuration(CfgWorkerCheckpointCheckInterval),
 			}
+			if checkpointerCfg.Store, err = checkpointStore(); err != nil {
+				return nil, fmt.Errorf("worker/storage: invalid checkpoint store configuration: %w", err)
+			}
 		}
 
 		// Start storage node for every runtime.
@@ -113,9 +131,13 @@ func (s *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 		"runtime_id", id,
 	)
 
-	rp, err := s.registration.NewRuntimeRoleProvider(node.RoleStorageWorker, id)
-	if err != nil {
-		return fmt.Errorf("failed to create role provider: %w", err)
+	// Read-only replicas never register as storage nodes.
+	var rp registration.RoleProvider
+	if !s.readOnly {
+		var err error
+		if rp, err = s.registration.NewRuntimeRoleProvider(node.RoleStorageWorker, id); err != nil {
+			return fmt.Errorf("failed to create role provider: %w", err)
+		}
 	}
 
 	path, err := registry.EnsureRuntimeStateDir(dataDir, id)
@@ -127,7 +149,12 @@ func (s *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 	if err != nil {
 		return fmt.Errorf("can't create local storage backend: %w", err)
 	}
-	commonNode.Runtime.RegisterStorage(localStorage)
+	switch s.readOnly {
+	case true:
+		commonNode.Runtime.RegisterStorage(newReadOnlyWrapper(localStorage))
+	case false:
+		commonNode.Runtime.RegisterStorage(localStorage)
+	}
 
 	node, err := committee.NewNode(
 		commonNode,
@@ -138,7 +165,21 @@ func (s *Worker) registerRuntime(dataDir string, commonNode *committeeCommon.Nod
 		s.commonWorker.GetConfig(),
 		localStorage,
 		checkpointerCfg,
-		viper.GetBool(CfgWorkerCheckpointSyncDisabled),
+		&committee.CheckpointSyncConfig{
+			Disabled:               viper.GetBool(CfgWorkerCheckpointSyncDisabled),
+			ChunkFetchersPerNode:   viper.GetUint(CfgWorkerCheckpointSyncChunkFetchersPerNode),
+			RestoreWorkers:         viper.GetUint(CfgWorkerCheckpointSyncRestoreWorkers),
+			MemoryBudget:           uint64(viper.GetSizeInBytes(CfgWorkerCheckpointSyncMemoryBudget)),
+			ExternalStoresDisabled: viper.GetBool(CfgWorkerCheckpointSyncExternalStoresDisabled),
+		},
+		&committee.DiffSyncConfig{
+			VerifyDiffs:   viper.GetBool(CfgWorkerDiffSyncVerify),
+			ValidateApply: viper.GetBool(CfgWorkerDiffSyncValidateApply),
+		},
+		&committee.RecoveryConfig{
+			AutoRecover: viper.GetBool(CfgWorkerAutoRecover),
+		},
+		s.policyCfg,
 	)
 	if err != nil {
 		return err
@@ -163,6 +204,11 @@ func (s *Worker) Enabled() bool {
 	return s.enabled
 }
 
+// ReadOnly returns true iff the worker is running as a read-only replica.
+func (s *Worker) ReadOnly() bool {
+	return s.readOnly
+}
+
 // Initialized returns a channel that will be closed when the storage worker
 // is initialized and ready to service requests.
 func (s *Worker) Initialized() <-chan struct{} {
@@ -205,7 +251,9 @@ func (s *Worker) Start() error {
 			<-r.Initialized()
 		}
 
-		<-s.registration.InitialRegistrationCh()
+		if !s.readOnly {
+			<-s.registration.InitialRegistrationCh()
+		}
 
 		s.logger.Info("storage worker started")
 
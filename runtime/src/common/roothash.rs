@@ -52,8 +52,20 @@ impl Default for HeaderType {
 }
 
 /// Roothash message.
+///
+/// # Note
+///
+/// This should be kept in sync with go/roothash/api/block/message.go.
 #[derive(Clone, Debug, PartialEq, Eq, Hash, Serialize, Deserialize)]
-pub enum Message {}
+pub enum Message {
+    /// Subscribes the runtime to (or unsubscribes it from) staking events
+    /// affecting its runtime account.
+    #[serde(rename = "staking_subscribe")]
+    StakingSubscribe {
+        /// Whether the runtime should be subscribed or unsubscribed.
+        subscribe: bool,
+    },
+}
 
 /// Block header.
 #[derive(Clone, Debug, Default, PartialEq, Eq, Hash, Serialize, Deserialize)]
@@ -121,7 +133,32 @@ impl ComputeResultsHeader {
 
 #[cfg(test)]
 mod tests {
+    use std::{fs::File, io::BufReader};
+
     use super::*;
+    use crate::common::crypto::signature::{PrivateKey, PublicKey, Signature, Signer};
+
+    /// Location of the structure test vectors (from Go).
+    const TEST_VECTORS: &'static str = "../go/roothash/tests/vectors/testdata/structures.json";
+
+    /// Roothash structure test vector.
+    ///
+    /// NOTE: This MUST be kept in sync with go/roothash/tests/vectors/structures.go.
+    #[derive(Deserialize)]
+    struct StructureTestVector {
+        kind: String,
+        encoded: String,
+        hash: String,
+        signature_context: Option<String>,
+        signature: Option<StructureTestSignature>,
+        signer_private_key: Option<String>,
+    }
+
+    #[derive(Deserialize)]
+    struct StructureTestSignature {
+        public_key: String,
+        signature: String,
+    }
 
     #[test]
     fn test_consistent_hash_header() {
@@ -170,4 +207,100 @@ mod tests {
             Hash::from("374021bcba44f1014d0d9919e876a1ecd7fe5ec1a92ecf9c8b313cd4976fbc01")
         );
     }
+
+    #[test]
+    fn test_consistent_message_encoding() {
+        use rustc_hex::FromHex;
+
+        // NOTE: These encodings MUST be synced with go/roothash/api/block/message_test.go.
+        for (msg, expected) in vec![
+            (
+                Message::StakingSubscribe { subscribe: true },
+                "a1717374616b696e675f737562736372696265a169737562736372696265f5",
+            ),
+            (
+                Message::StakingSubscribe { subscribe: false },
+                "a1717374616b696e675f737562736372696265a169737562736372696265f4",
+            ),
+        ] {
+            let expected: Vec<u8> = expected.from_hex().unwrap();
+            assert_eq!(
+                cbor::to_vec(&msg),
+                expected,
+                "message should encode the same"
+            );
+
+            let decoded: Message = cbor::from_slice(&expected).expect("message should decode");
+            assert_eq!(decoded, msg, "message should decode the same");
+        }
+    }
+
+    #[test]
+    fn test_structure_vectors() {
+        let file = File::open(TEST_VECTORS).expect("failed to open test vectors");
+        let vectors: Vec<StructureTestVector> =
+            serde_json::from_reader(BufReader::new(file)).expect("failed to parse test vectors");
+        assert!(!vectors.is_empty(), "test vectors should not be empty");
+
+        for v in vectors {
+            let encoded = base64::decode(&v.encoded).expect("encoded should be valid base64");
+            let expected_hash =
+                Hash::from(base64::decode(&v.hash).expect("hash should be valid base64"));
+
+            let hash = match v.kind.as_str() {
+                "BlockHeader" => {
+                    let header: Header = cbor::from_slice(&encoded).expect("header should decode");
+                    assert_eq!(
+                        cbor::to_vec(&header),
+                        encoded,
+                        "header should encode the same"
+                    );
+                    header.encoded_hash()
+                }
+                "ComputeResultsHeader" => {
+                    let header: ComputeResultsHeader =
+                        cbor::from_slice(&encoded).expect("compute results header should decode");
+                    assert_eq!(
+                        cbor::to_vec(&header),
+                        encoded,
+                        "compute results header should encode the same"
+                    );
+                    assert_eq!(
+                        v.signature_context.as_ref().map(|ctx| ctx.as_bytes()),
+                        Some(COMPUTE_RESULTS_HEADER_CONTEXT),
+                        "compute results header signature context should match"
+                    );
+                    header.encoded_hash()
+                }
+                // Structures not used by the runtime are only checked for consistent signatures.
+                _ => Hash::digest_bytes(&encoded),
+            };
+            assert_eq!(hash, expected_hash, "{} hash should match", v.kind);
+
+            if let Some(sig) = v.signature {
+                let context = v
+                    .signature_context
+                    .expect("signed vectors should have a signature context");
+                let pk = PublicKey::from(base64::decode(&sig.public_key).unwrap());
+                let signature = Signature::from(base64::decode(&sig.signature).unwrap());
+                signature
+                    .verify(&pk, context.as_bytes(), &encoded)
+                    .expect("signature should be valid");
+
+                // Signatures are deterministic so signing must produce the same signature.
+                // The Go private key also includes the public key, only the seed is needed.
+                let mut sk = base64::decode(&v.signer_private_key.expect("signer private key"))
+                    .expect("signer private key should be valid base64");
+                sk.truncate(32);
+                let sk = PrivateKey::from_bytes(sk);
+                assert_eq!(sk.public_key(), pk, "{} signer should match", v.kind);
+                assert_eq!(
+                    sk.sign(context.as_bytes(), &encoded).unwrap(),
+                    signature,
+                    "{} signature should match",
+                    v.kind
+                );
+            }
+        }
+    }
 }
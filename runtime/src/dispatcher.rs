@@ -230,6 +230,7 @@ impl Dispatcher {
                         io_root,
                         inputs,
                         block,
+                        staking_events,
                     },
                 )) => {
                     // Transaction execution.
@@ -242,6 +243,7 @@ impl Dispatcher {
                         io_root,
                         inputs,
                         block,
+                        staking_events,
                         false,
                     );
                 }
@@ -256,6 +258,7 @@ impl Dispatcher {
                         Hash::default(),
                         inputs,
                         block,
+                        Vec::new(),
                         true,
                     );
                 }
@@ -300,6 +303,7 @@ impl Dispatcher {
         io_root: Hash,
         mut inputs: TxnBatch,
         block: Block,
+        staking_events: Vec<cbor::Value>,
         check_only: bool,
     ) {
         debug!(self.logger, "Received transaction batch request";
@@ -320,7 +324,8 @@ impl Dispatcher {
             Context::create_child(&ctx),
             protocol.clone(),
         ));
-        let txn_ctx = TxnContext::new(ctx.clone(), &block.header, check_only);
+        let mut txn_ctx = TxnContext::new(ctx.clone(), &block.header, check_only);
+        txn_ctx.staking_events = staking_events;
         match StorageContext::enter(&mut cache.mkvs, untrusted_local.clone(), || {
             txn_dispatcher.dispatch_batch(&inputs, txn_ctx)
         }) {
@@ -4,7 +4,10 @@ use std::{any::Any, sync::Arc};
 use io_context::Context as IoContext;
 
 use super::tags::{Tag, Tags};
-use crate::common::roothash::{Header, Message};
+use crate::common::{
+    cbor::Value,
+    roothash::{Header, Message},
+};
 
 struct NoRuntimeContext;
 
@@ -20,6 +23,12 @@ pub struct Context<'a> {
     /// Flag indicating whether to only perform transaction check rather than
     /// running the transaction.
     pub check_only: bool,
+    /// Staking events affecting the runtime account that occurred since the
+    /// last processed round. Only set in case the runtime is subscribed to
+    /// staking events (see `Message::StakingSubscribe`).
+    ///
+    /// Each event is an encoded go/staking/api Event structure.
+    pub staking_events: Vec<Value>,
 
     /// List of emitted tags for each transaction.
     tags: Vec<Tags>,
@@ -36,6 +45,7 @@ impl<'a> Context<'a> {
             header,
             runtime: Box::new(NoRuntimeContext),
             check_only,
+            staking_events: Vec::new(),
             tags: Vec::new(),
             messages: Vec::new(),
         }
@@ -4,6 +4,7 @@ use serde_bytes;
 
 use crate::{
     common::{
+        cbor::Value,
         crypto::{
             hash::Hash,
             signature::{PublicKey, Signature},
@@ -115,6 +116,8 @@ pub enum Body {
         io_root: Hash,
         inputs: TxnBatch,
         block: Block,
+        #[serde(default)]
+        staking_events: Vec<Value>,
     },
     RuntimeExecuteTxBatchResponse {
         batch: ComputedBatch,